@@ -0,0 +1,654 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/input"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ageLabel is the label of the secrets holding age identities.
+	ageLabel = "age"
+
+	// ageNamePrefix prefixes the identity name in the secret name of an age identity.
+	ageNamePrefix = "age/"
+
+	// ageAttrRecipient is the attribute holding the recipient of an identity.
+	ageAttrRecipient = "recipient"
+)
+
+type AgeError struct {
+	Err error
+}
+
+func (e *AgeError) Error() string { return "age: " + e.Err.Error() }
+
+func (e *AgeError) Unwrap() error { return e.Err }
+
+// ageIdentity is an age identity stored in the vault.
+//
+// The identity is a secret labeled [ageLabel] holding the AGE-SECRET-KEY-1
+// encoded X25519 identity, its recipient is stored as an attribute.
+type ageIdentity struct {
+	id        int
+	name      string
+	recipient string
+}
+
+// ageIdentities returns the age identities stored in the vault, ordered by name.
+func (o *VaultOptions) ageIdentities(ctx context.Context) ([]ageIdentity, error) {
+	secrets, err := o.vault.FilterSecrets(ctx, "", "", []string{ageLabel})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+
+	ids := slices.Collect(maps.Keys(secrets))
+
+	attrs, err := o.vault.SecretsAttributes(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]ageIdentity, 0, len(ids))
+
+	for _, id := range ids {
+		name, ok := strings.CutPrefix(secrets[id].Name, ageNamePrefix)
+
+		recipient := attrs[id][ageAttrRecipient]
+		if !ok || len(recipient) == 0 {
+			continue // labeled by the user, not added by 'vlt age'.
+		}
+
+		identities = append(identities, ageIdentity{id: id, name: name, recipient: recipient})
+	}
+
+	slices.SortFunc(identities, func(a, b ageIdentity) int { return strings.Compare(a.name, b.name) })
+
+	return identities, nil
+}
+
+// ageIdentity returns the stored age identity with the given name.
+func (o *VaultOptions) ageIdentity(ctx context.Context, name string) (ageIdentity, error) {
+	identities, err := o.ageIdentities(ctx)
+	if err != nil {
+		return ageIdentity{}, err
+	}
+
+	i := slices.IndexFunc(identities, func(id ageIdentity) bool { return id.name == name })
+	if i < 0 {
+		return ageIdentity{}, fmt.Errorf("%w: age identity %q", vaulterrors.ErrSearchNoMatch, name)
+	}
+
+	return identities[i], nil
+}
+
+// unwrapAgeIdentities decrypts the given stored identities.
+func (o *VaultOptions) unwrapAgeIdentities(ctx context.Context, stdio *genericclioptions.StdioOptions, stored []ageIdentity) ([]age.Identity, error) {
+	identities := make([]age.Identity, 0, len(stored))
+
+	for _, s := range stored {
+		var identity *age.X25519Identity
+
+		err := o.vault.WithSecret(ctx, s.id, func(raw []byte) (err error) {
+			identity, err = age.ParseX25519Identity(string(raw))
+			if err != nil {
+				return fmt.Errorf("age identity %q: %w", s.name, err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		o.recordAccess(ctx, stdio, s.id)
+
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
+
+// storeAgeIdentity saves identity under name, failing if the name is taken.
+func (o *VaultOptions) storeAgeIdentity(ctx context.Context, name string, identity *age.X25519Identity) error {
+	if _, err := o.ageIdentity(ctx, name); err == nil {
+		return fmt.Errorf("age identity %q already exists", name)
+	} else if !errors.Is(err, vaulterrors.ErrSearchNoMatch) {
+		return err
+	}
+
+	id, err := o.vault.InsertNewSecret(ctx, ageNamePrefix+name, []byte(identity.String()), []string{ageLabel})
+	if err != nil {
+		return err
+	}
+
+	attrs := map[string]string{ageAttrRecipient: identity.Recipient().String()}
+
+	if err := o.vault.UpdateSecretAttributes(ctx, id, attrs, nil); err != nil {
+		return err
+	}
+
+	o.persistRequired = true
+
+	return nil
+}
+
+// openAgeInput opens the file at path, or stdin if path is empty.
+func openAgeInput(stdio *genericclioptions.StdioOptions, path string) (io.ReadCloser, error) {
+	if len(path) == 0 {
+		if !stdio.StdinIsPiped {
+			return nil, errors.New("no input source provided (stdin or file)")
+		}
+
+		return io.NopCloser(stdio.In), nil
+	}
+
+	return os.Open(filepath.Clean(path))
+}
+
+// createAgeOutput creates the file at path with mode 0600, or returns stdout if path is empty.
+func createAgeOutput(stdio *genericclioptions.StdioOptions, path string) (io.WriteCloser, error) {
+	if len(path) == 0 {
+		return nopWriteCloser{stdio.Out}, nil
+	}
+
+	return os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// AgeKeygenOptions holds data required to run the command.
+type AgeKeygenOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	name string
+}
+
+var _ genericclioptions.CmdOptions = &AgeKeygenOptions{}
+
+// NewAgeKeygenOptions initializes the options struct.
+func NewAgeKeygenOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *AgeKeygenOptions {
+	return &AgeKeygenOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*AgeKeygenOptions) Complete() error { return nil }
+
+func (*AgeKeygenOptions) Validate() error { return nil }
+
+func (o *AgeKeygenOptions) Run(ctx context.Context, _ ...string) error {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return &AgeError{err}
+	}
+
+	if err := o.storeAgeIdentity(ctx, o.name, identity); err != nil {
+		return &AgeError{err}
+	}
+
+	_, err = fmt.Fprintln(o.Out, identity.Recipient())
+
+	return err
+}
+
+// AgeAddOptions holds data required to run the command.
+type AgeAddOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	name string
+}
+
+var _ genericclioptions.CmdOptions = &AgeAddOptions{}
+
+// NewAgeAddOptions initializes the options struct.
+func NewAgeAddOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *AgeAddOptions {
+	return &AgeAddOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*AgeAddOptions) Complete() error { return nil }
+
+func (*AgeAddOptions) Validate() error { return nil }
+
+func (o *AgeAddOptions) Run(ctx context.Context, _ ...string) error {
+	raw, err := o.readIdentity(ctx)
+	if err != nil {
+		return &AgeError{err}
+	}
+	defer securebytes.Wipe(raw)
+
+	identity, err := age.ParseX25519Identity(string(raw))
+	if err != nil {
+		return &AgeError{err}
+	}
+
+	if err := o.storeAgeIdentity(ctx, o.name, identity); err != nil {
+		return &AgeError{err}
+	}
+
+	_, err = fmt.Fprintln(o.Out, identity.Recipient())
+
+	return err
+}
+
+// readIdentity reads the identity from piped input, skipping the comments
+// written by age-keygen, or prompts for it.
+func (o *AgeAddOptions) readIdentity(ctx context.Context) ([]byte, error) {
+	if !o.StdinIsPiped {
+		return input.PromptReadSecure(ctx, o.Prompter(), "Age identity (AGE-SECRET-KEY-1...): ")
+	}
+
+	s := bufio.NewScanner(o.In)
+	for s.Scan() {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) > 0 && line[0] != '#' {
+			return bytes.Clone(line), nil
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, errors.New("no identity found in input")
+}
+
+// AgeRecipientOptions holds data required to run the command.
+type AgeRecipientOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	name string
+}
+
+var _ genericclioptions.CmdOptions = &AgeRecipientOptions{}
+
+// NewAgeRecipientOptions initializes the options struct.
+func NewAgeRecipientOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *AgeRecipientOptions {
+	return &AgeRecipientOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*AgeRecipientOptions) Complete() error { return nil }
+
+func (*AgeRecipientOptions) Validate() error { return nil }
+
+func (o *AgeRecipientOptions) Run(ctx context.Context, _ ...string) error {
+	identity, err := o.ageIdentity(ctx, o.name)
+	if err != nil {
+		return &AgeError{err}
+	}
+
+	_, err = fmt.Fprintln(o.Out, identity.recipient)
+
+	return err
+}
+
+// AgeEncryptOptions holds data required to run the command.
+type AgeEncryptOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	identities []string // identities are the names of stored identities to encrypt to.
+	recipients []string // recipients are additional age recipients to encrypt to.
+	armor      bool
+	output     string
+}
+
+var _ genericclioptions.CmdOptions = &AgeEncryptOptions{}
+
+// NewAgeEncryptOptions initializes the options struct.
+func NewAgeEncryptOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *AgeEncryptOptions {
+	return &AgeEncryptOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*AgeEncryptOptions) Complete() error { return nil }
+
+func (o *AgeEncryptOptions) Validate() error {
+	if len(o.identities) == 0 && len(o.recipients) == 0 {
+		return &AgeError{errors.New("no recipients, set --identity or --recipient")}
+	}
+
+	return nil
+}
+
+func (o *AgeEncryptOptions) Run(ctx context.Context, args ...string) error {
+	if err := o.encrypt(ctx, args...); err != nil {
+		return &AgeError{err}
+	}
+
+	return nil
+}
+
+func (o *AgeEncryptOptions) encrypt(ctx context.Context, args ...string) (retErr error) {
+	recipients := make([]age.Recipient, 0, len(o.identities)+len(o.recipients))
+
+	for _, name := range o.identities {
+		identity, err := o.ageIdentity(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		o.recipients = append(o.recipients, identity.recipient)
+	}
+
+	for _, s := range o.recipients {
+		r, err := age.ParseX25519Recipient(s)
+		if err != nil {
+			return err
+		}
+
+		recipients = append(recipients, r)
+	}
+
+	in, err := openAgeInput(o.StdioOptions, firstArg(args))
+	if err != nil {
+		return err
+	}
+	defer func() { //nolint:wsl_v5
+		_ = in.Close()
+	}()
+
+	out, err := createAgeOutput(o.StdioOptions, o.output)
+	if err != nil {
+		return err
+	}
+	defer func() { //nolint:wsl_v5
+		retErr = errors.Join(retErr, out.Close())
+	}()
+
+	dst := io.WriteCloser(nopWriteCloser{out})
+	if o.armor {
+		dst = armor.NewWriter(out)
+	}
+
+	w, err := age.Encrypt(dst, recipients...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, in); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return dst.Close()
+}
+
+// AgeDecryptOptions holds data required to run the command.
+type AgeDecryptOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	identities []string // identities are the names of stored identities to try, all if empty.
+	output     string
+}
+
+var _ genericclioptions.CmdOptions = &AgeDecryptOptions{}
+
+// NewAgeDecryptOptions initializes the options struct.
+func NewAgeDecryptOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *AgeDecryptOptions {
+	return &AgeDecryptOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*AgeDecryptOptions) Complete() error { return nil }
+
+func (*AgeDecryptOptions) Validate() error { return nil }
+
+func (o *AgeDecryptOptions) Run(ctx context.Context, args ...string) error {
+	if err := o.decrypt(ctx, args...); err != nil {
+		return &AgeError{err}
+	}
+
+	return nil
+}
+
+func (o *AgeDecryptOptions) decrypt(ctx context.Context, args ...string) (retErr error) {
+	stored, err := o.ageIdentities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(o.identities) > 0 {
+		selected := make([]ageIdentity, 0, len(o.identities))
+
+		for _, name := range o.identities {
+			i := slices.IndexFunc(stored, func(id ageIdentity) bool { return id.name == name })
+			if i < 0 {
+				return fmt.Errorf("%w: age identity %q", vaulterrors.ErrSearchNoMatch, name)
+			}
+
+			selected = append(selected, stored[i])
+		}
+
+		stored = selected
+	}
+
+	if len(stored) == 0 {
+		return errors.New("no age identities, add one using 'vlt age keygen' or 'vlt age add'")
+	}
+
+	identities, err := o.unwrapAgeIdentities(ctx, o.StdioOptions, stored)
+	if err != nil {
+		return err
+	}
+
+	in, err := openAgeInput(o.StdioOptions, firstArg(args))
+	if err != nil {
+		return err
+	}
+	defer func() { //nolint:wsl_v5
+		_ = in.Close()
+	}()
+
+	br := bufio.NewReader(in)
+
+	src := io.Reader(br)
+	if head, _ := br.Peek(len(armor.Header)); string(head) == armor.Header {
+		src = armor.NewReader(br)
+	}
+
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return err
+	}
+
+	out, err := createAgeOutput(o.StdioOptions, o.output)
+	if err != nil {
+		return err
+	}
+	defer func() { //nolint:wsl_v5
+		retErr = errors.Join(retErr, out.Close())
+	}()
+
+	_, err = io.Copy(out, r)
+
+	return err
+}
+
+// firstArg returns the first of args, or empty if there are none.
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	return args[0]
+}
+
+// NewCmdAge creates the age cobra command.
+func NewCmdAge(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "age",
+		Short: i18n.T("Store age identities and encrypt or decrypt files with them (subcommands available)"),
+		Long: `Store age identities and encrypt or decrypt files with them.
+
+An identity is saved as a secret named 'age/<name>' with the 'age' label,
+holding the AGE-SECRET-KEY-1 encoded X25519 identity, its recipient is stored
+as an encrypted attribute. Only X25519 identities are supported, GPG keys are not.
+
+Files are decrypted in memory using the stored identities,
+which never have to be written to an identity file on disk.
+Decrypted output can be piped to other tools, e.g., tar.`,
+		Example: `  # Generate an identity and print its recipient
+  vlt age keygen work
+
+  # Move an existing identity file into the vault
+  vlt age add work < ~/.config/age/key.txt
+
+  # Encrypt a file to a stored identity
+  vlt age encrypt --identity work --armor notes.txt --output notes.txt.age
+
+  # Decrypt a file using the stored identities
+  vlt age decrypt notes.txt.age
+
+  # Extract an encrypted backup without writing the archive to disk
+  vlt age decrypt backup.tar.age | tar x`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(NewCmdAgeKeygen(defaults))
+	cmd.AddCommand(NewCmdAgeAdd(defaults))
+	cmd.AddCommand(NewCmdAgeRecipient(defaults))
+	cmd.AddCommand(NewCmdAgeEncrypt(defaults))
+	cmd.AddCommand(NewCmdAgeDecrypt(defaults))
+
+	return cmd
+}
+
+// NewCmdAgeKeygen creates the age keygen cobra command.
+func NewCmdAgeKeygen(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewAgeKeygenOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	return &cobra.Command{
+		Use:   "keygen name",
+		Short: i18n.T("Generate an age identity into the vault"),
+		Long: `Generate an X25519 age identity into the vault, and print its recipient.
+
+The recipient, e.g., age1..., is the public key files are encrypted to.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.name = args[0]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+}
+
+// NewCmdAgeAdd creates the age add cobra command.
+func NewCmdAgeAdd(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewAgeAddOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	return &cobra.Command{
+		Use:   "add name",
+		Short: i18n.T("Save an existing age identity"),
+		Long: `Save an existing X25519 age identity, and print its recipient.
+
+The identity is prompted for, or read from piped or redirected input,
+e.g., an identity file created by age-keygen, ignoring its comments.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.name = args[0]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+}
+
+// NewCmdAgeRecipient creates the age recipient cobra command.
+func NewCmdAgeRecipient(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewAgeRecipientOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	return &cobra.Command{
+		Use:   "recipient name",
+		Short: i18n.T("Print the recipient of a stored age identity"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.name = args[0]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+}
+
+// NewCmdAgeEncrypt creates the age encrypt cobra command.
+func NewCmdAgeEncrypt(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewAgeEncryptOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "encrypt [file]",
+		Short: i18n.T("Encrypt a file to stored age identities"),
+		Long: `Encrypt a file, or piped input, to the recipients of stored identities
+and to additional recipients.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&o.identities, "identity", "i", nil, "name of a stored identity to encrypt to, may be repeated")
+	cmd.Flags().StringSliceVarP(&o.recipients, "recipient", "r", nil, "age recipient to encrypt to, may be repeated")
+	cmd.Flags().BoolVarP(&o.armor, "armor", "a", false, "write PEM encoded output")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "write the output to the specified file path")
+
+	return cmd
+}
+
+// NewCmdAgeDecrypt creates the age decrypt cobra command.
+func NewCmdAgeDecrypt(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewAgeDecryptOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "decrypt [file]",
+		Short: i18n.T("Decrypt a file using stored age identities"),
+		Long: `Decrypt an age encrypted file, or piped input, armored or binary,
+using the stored identities, all of them unless --identity is set.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&o.identities, "identity", "i", nil, "name of a stored identity to decrypt with, may be repeated")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "write the output to the specified file path")
+
+	return cmd
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// aliasNameRE matches valid alias names, e.g., "pw" or "work-find".
+var aliasNameRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// validateAlias verifies that an alias has a valid name and expands to at least one argument.
+func validateAlias(name, definition string) error {
+	if !aliasNameRE.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: expected letters, digits, '_', '.' or '-'", name)
+	}
+
+	if len(strings.Fields(definition)) == 0 {
+		return errors.New("defined but contains no values")
+	}
+
+	return nil
+}
+
+// expandAlias replaces the command name in args by the definition of the alias
+// of the same name, from the [aliases] section of the config file. The remaining
+// args follow the definition, e.g., with pw = 'show -c --name', 'vlt pw foo'
+// runs 'vlt show -c --name foo'.
+//
+// Built-in commands take precedence over aliases, and aliases are not expanded
+// recursively. Definitions are split on white space, quoting is not supported.
+//
+// If the config file cannot be loaded, args are returned unchanged,
+// the error is reported once the command loads the config file again.
+func expandAlias(root *cobra.Command, args []string) []string {
+	i, configPath := scanRootArgs(root.PersistentFlags(), args)
+	if i < 0 || isBuiltinCommand(root, args[i]) {
+		return args
+	}
+
+	config, err := LoadFileConfig(configPath)
+	if err != nil {
+		return args
+	}
+
+	definition, ok := config.Aliases[args[i]]
+	if !ok {
+		return args
+	}
+
+	fields := strings.Fields(definition)
+
+	expanded := make([]string, 0, len(args)+len(fields)-1)
+	expanded = append(expanded, args[:i]...)
+	expanded = append(expanded, fields...)
+
+	return append(expanded, args[i+1:]...)
+}
+
+// isBuiltinCommand reports whether name is a sub-command of root, or one of its aliases.
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	// the help command is only added by cobra once the command is executed.
+	if name == "help" {
+		return true
+	}
+
+	return slices.ContainsFunc(root.Commands(), func(c *cobra.Command) bool {
+		return c.Name() == name || c.HasAlias(name)
+	})
+}
+
+// scanRootArgs returns the index of the command name in args, the first
+// argument that is neither a global flag nor its value, or -1 if there is none.
+// It also returns the value of the --config flag, if given anywhere in args.
+func scanRootArgs(flags *pflag.FlagSet, args []string) (index int, configPath string) {
+	index = -1
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			return index, configPath
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			if name == "config" {
+				if !hasValue && i+1 < len(args) {
+					i++
+					value = args[i]
+				}
+
+				configPath = value
+
+				continue
+			}
+
+			// flags following the command name belong to the command,
+			// only global flags are skipped along with their values.
+			if f := flags.Lookup(name); index < 0 && !hasValue && f != nil && takesValue(f) {
+				i++
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			if index < 0 && shorthandsTakeValue(flags, arg[1:]) {
+				i++
+			}
+		case index < 0:
+			index = i
+		}
+	}
+
+	return index, configPath
+}
+
+// shorthandsTakeValue reports whether the last flag of a group
+// of shorthand flags, e.g., "-vf", takes its value from the next argument.
+func shorthandsTakeValue(flags *pflag.FlagSet, shorthands string) bool {
+	for i := range len(shorthands) {
+		f := flags.ShorthandLookup(shorthands[i : i+1])
+		if f == nil {
+			return false
+		}
+
+		// the rest of the group is the value of the flag, e.g., "-fvault.db".
+		if takesValue(f) {
+			return i == len(shorthands)-1
+		}
+	}
+
+	return false
+}
+
+// takesValue reports whether f requires a value, i.e., it is not a boolean flag.
+func takesValue(f *pflag.Flag) bool {
+	return len(f.NoOptDefVal) == 0
+}
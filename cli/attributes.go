@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidAttribute = errors.New("invalid attribute")
+
+// attributeKeyRE matches valid attribute keys, e.g., env, owner or aws.region.
+var attributeKeyRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// validateAttributeKey verifies that key is a valid attribute key.
+func validateAttributeKey(key string) error {
+	if !attributeKeyRE.MatchString(key) {
+		return fmt.Errorf("%w: key %q: expected letters, digits, '_', '.' or '-'", ErrInvalidAttribute, key)
+	}
+
+	return nil
+}
+
+// parseAttributes parses key=value pairs into a map.
+//
+// If valueRequired is not set, a bare key maps to an empty value.
+func parseAttributes(pairs []string, valueRequired bool) (map[string]string, error) {
+	attrs := make(map[string]string, len(pairs))
+
+	for _, p := range pairs {
+		key, value, ok := strings.Cut(p, "=")
+		if err := validateAttributeKey(key); err != nil {
+			return nil, err
+		}
+
+		if valueRequired && (!ok || len(value) == 0) {
+			return nil, fmt.Errorf("%w: %q: expected key=value", ErrInvalidAttribute, p)
+		}
+
+		if _, dup := attrs[key]; dup {
+			return nil, fmt.Errorf("%w: key %q given more than once", ErrInvalidAttribute, key)
+		}
+
+		attrs[key] = value
+	}
+
+	return attrs, nil
+}
+
+// matchAttributes reports whether attrs holds every key of filters,
+// with a value matching its glob pattern. An empty pattern matches any value.
+//
+// Patterns support the '*' and '?' wildcards.
+func matchAttributes(attrs map[string]string, filters map[string]string) bool {
+	for key, pattern := range filters {
+		value, ok := attrs[key]
+		if !ok {
+			return false
+		}
+
+		if len(pattern) > 0 && !globToRegexp(pattern).MatchString(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// globToRegexp compiles a glob pattern with the '*' and '?' wildcards
+// into an anchored regular expression, all other characters match literally.
+func globToRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+
+	b.WriteString("(?s)^")
+
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
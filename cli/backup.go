@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// backupTimeLayout is the UTC timestamp in backup file names, it sorts chronologically.
+	backupTimeLayout = "20060102T150405.000Z"
+
+	// backupExt is the extension of backup files.
+	backupExt = ".bak"
+
+	// backupLogName is the append-only log of backups, kept in the backup directory.
+	backupLogName = "backups.log"
+
+	backupDirPerm = 0o700
+)
+
+type BackupError struct {
+	Err error
+}
+
+func (e *BackupError) Error() string { return "backup: " + e.Err.Error() }
+
+func (e *BackupError) Unwrap() error { return e.Err }
+
+// BackupOptions holds data required to run the command.
+type BackupOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	config  *ResolvedConfig
+	dir     string // dir is the backup directory, overrides the configured one.
+	keep    int    // keep is the number of backups to keep, 0 keeps all.
+	keepSet bool   // keepSet reports whether --keep was given, overriding the configured retention.
+}
+
+var _ genericclioptions.CmdOptions = &BackupOptions{}
+
+// NewBackupOptions initializes the options struct.
+func NewBackupOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions, config *ResolvedConfig) *BackupOptions {
+	return &BackupOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+		config:       config,
+	}
+}
+
+func (o *BackupOptions) Complete() error {
+	o.dir = cmp.Or(o.dir, o.config.BackupDir)
+
+	if !o.keepSet {
+		o.keep = o.config.BackupKeep
+	}
+
+	return nil
+}
+
+func (o *BackupOptions) Validate() error {
+	if len(o.dir) == 0 {
+		return &BackupError{errors.New("no backup directory, use --to or set 'dir' in the [backup] section of the config file")}
+	}
+
+	if o.keep < 0 {
+		return &BackupError{errors.New("--keep must be zero or a positive integer")}
+	}
+
+	exists, err := o.vaultExists()
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("%w: %s", vaulterrors.ErrVaultFileNotFound, o.path)
+	}
+
+	return nil
+}
+
+func (o *BackupOptions) Run(ctx context.Context, _ ...string) error {
+	b := &vaultBackup{path: o.path, dir: o.dir, keep: o.keep}
+
+	dest, pruned, err := b.run(ctx)
+	if err != nil {
+		return &BackupError{err}
+	}
+
+	o.Infof("backup written to %s\n", dest)
+
+	for _, p := range pruned {
+		o.Debugf("removed old backup: %s\n", p)
+	}
+
+	return nil
+}
+
+// vaultBackup backs up the vault at path to dir,
+// keeping the keep most recent backups, 0 keeps all.
+type vaultBackup struct {
+	path string
+	dir  string
+	keep int
+}
+
+// run writes a new verified backup, prunes the backups beyond the retention limit
+// and records the backup in the backup log. It returns the path of the new backup
+// and the names of the removed backups.
+func (b *vaultBackup) run(ctx context.Context) (dest string, pruned []string, _ error) {
+	if err := os.MkdirAll(b.dir, backupDirPerm); err != nil {
+		return "", nil, err
+	}
+
+	name := b.prefix() + time.Now().UTC().Format(backupTimeLayout) + backupExt
+	dest = filepath.Join(b.dir, name)
+
+	if _, err := os.Stat(dest); !errors.Is(err, fs.ErrNotExist) {
+		return "", nil, fmt.Errorf("backup file already exists: %s", dest)
+	}
+
+	// the copy is written and verified under a temporary name,
+	// so an interrupted backup never looks like a complete one.
+	tmp := dest + ".tmp"
+	defer func() { _ = os.Remove(tmp) }()
+
+	if err := vault.Backup(ctx, b.path, tmp); err != nil {
+		return "", nil, err
+	}
+
+	if err := restrictVaultFile(tmp); err != nil {
+		return "", nil, err
+	}
+
+	if err := vault.VerifyBackup(ctx, tmp); err != nil {
+		return "", nil, err
+	}
+
+	sum, size, err := fileSHA256(tmp)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := os.Rename(tmp, dest); err != nil {
+		return "", nil, err
+	}
+
+	if err := syncDir(b.dir); err != nil {
+		return "", nil, err
+	}
+
+	pruned, err = b.prune()
+	if err != nil {
+		return "", nil, fmt.Errorf("retention: %w", err)
+	}
+
+	if err := b.appendLog(name, size, sum, pruned); err != nil {
+		return "", nil, err
+	}
+
+	return dest, pruned, nil
+}
+
+// prefix returns the file name prefix of backups of the vault,
+// derived from the vault file name, e.g., "vlt-" for "~/.vlt".
+func (b *vaultBackup) prefix() string {
+	return strings.TrimPrefix(filepath.Base(b.path), ".") + "-"
+}
+
+// backups returns the names of the existing backups of the vault, oldest first.
+func (b *vaultBackup) backups() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := b.prefix()
+
+	var names []string
+
+	for _, e := range entries {
+		ts, ok := strings.CutPrefix(e.Name(), prefix)
+		if !ok || !e.Type().IsRegular() {
+			continue
+		}
+
+		ts, ok = strings.CutSuffix(ts, backupExt)
+		if !ok {
+			continue
+		}
+
+		if _, err := time.Parse(backupTimeLayout, ts); err != nil {
+			continue
+		}
+
+		names = append(names, e.Name())
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// prune removes the oldest backups beyond the retention limit,
+// and returns the names of the removed backups.
+func (b *vaultBackup) prune() ([]string, error) {
+	if b.keep == 0 {
+		return nil, nil
+	}
+
+	names, err := b.backups()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) <= b.keep {
+		return nil, nil
+	}
+
+	pruned := names[:len(names)-b.keep]
+	for _, name := range pruned {
+		if err := os.Remove(filepath.Join(b.dir, name)); err != nil {
+			return nil, err
+		}
+	}
+
+	return pruned, nil
+}
+
+// appendLog records the backup in the append-only backup log of the directory.
+func (b *vaultBackup) appendLog(name string, size int64, sum string, pruned []string) error {
+	//nolint:gosec // the backup directory is user provided
+	f, err := os.OpenFile(filepath.Join(b.dir, backupLogName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, vaultPerm)
+	if err != nil {
+		return fmt.Errorf("backup log: %w", err)
+	}
+
+	line := fmt.Sprintf("%s backup %s size=%d sha256=%s vault=%s",
+		time.Now().UTC().Format(time.RFC3339), name, size, sum, b.path)
+	if len(pruned) > 0 {
+		line += " pruned=" + strings.Join(pruned, ",")
+	}
+
+	_, err = fmt.Fprintln(f, line)
+
+	return errors.Join(err, f.Close())
+}
+
+// fileSHA256 returns the hex encoded SHA-256 digest and the size of the file at path.
+func fileSHA256(path string) (string, int64, error) {
+	f, err := os.Open(path) //nolint:gosec // path is the backup file
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = f.Close() }() //nolint:wsl_v5
+
+	h := sha256.New()
+
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// NewCmdBackup creates the backup cobra command.
+func NewCmdBackup(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewBackupOptions(
+		defaults.StdioOptions,
+		defaults.vaultOptions,
+		defaults.configOptions.resolved,
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: i18n.T("Copy the encrypted vault to a backup directory"),
+		Long: `Copy the encrypted vault to a backup directory, e.g., a mounted USB drive.
+
+Each backup is written to a new file named after the vault and the UTC time,
+existing backups are never overwritten. The copy is verified by test-opening it
+(database integrity, key derivation headers and vault checksum) without unlocking it.
+
+Once the backup is written, the oldest backups beyond --keep are removed.
+Every backup is recorded in the append-only 'backups.log' file of the directory.
+
+The directory and retention can be set in the [backup] section of the config file.
+No password is required, the vault is copied encrypted, as stored.`,
+		Example: `  # Back up the vault to a USB drive, keeping the 5 most recent backups
+  vlt backup --to /mnt/usb/vlt --keep 5
+
+  # Back up to the directory set in the config file
+  vlt backup`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			o.keepSet = cmd.Flags().Changed("keep")
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.dir, "to", "", "", "backup directory (default: 'dir' of the [backup] config section)")
+	cmd.Flags().IntVarP(&o.keep, "keep", "", 0, "number of backups to keep, 0 keeps all (default: 'keep' of the [backup] config section, or 10)")
+
+	return cmd
+}
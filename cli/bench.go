@@ -0,0 +1,329 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vaultcrypto"
+
+	"github.com/spf13/cobra"
+)
+
+type BenchError struct {
+	Err error
+}
+
+func (e *BenchError) Error() string { return "bench: " + e.Err.Error() }
+
+func (e *BenchError) Unwrap() error { return e.Err }
+
+const benchPassword = "vlt-bench-password"
+
+// BenchOptions holds data required to run the command.
+type BenchOptions struct {
+	*genericclioptions.StdioOptions
+
+	iterations int
+	secrets    int
+	valueSize  int
+	kdfParams  vaultcrypto.Argon2Params
+}
+
+var _ genericclioptions.CmdOptions = &BenchOptions{}
+
+// NewBenchOptions initializes the options struct.
+func NewBenchOptions(stdio *genericclioptions.StdioOptions) *BenchOptions {
+	return &BenchOptions{
+		StdioOptions: stdio,
+		kdfParams:    vaultcrypto.NewArgon2idKDF().PHC().Argon2Params,
+	}
+}
+
+func (*BenchOptions) Complete() error { return nil }
+
+func (o *BenchOptions) Validate() error {
+	if o.iterations < 1 {
+		return &BenchError{errors.New("iterations must be at least 1")}
+	}
+
+	if o.secrets < 1 {
+		return &BenchError{errors.New("secrets must be at least 1")}
+	}
+
+	if o.valueSize < 1 {
+		return &BenchError{errors.New("value size must be at least 1 byte")}
+	}
+
+	if o.kdfParams.Memory == 0 || o.kdfParams.Time == 0 || o.kdfParams.Parallelism == 0 {
+		return &BenchError{errors.New("argon2id parameters must be positive")}
+	}
+
+	return nil
+}
+
+// benchResult is a single measured operation.
+type benchResult struct {
+	name    string
+	ops     int
+	elapsed time.Duration
+	bytes   int // bytes is the total number of bytes processed, if relevant.
+}
+
+func (r benchResult) String() string {
+	avg := r.elapsed / time.Duration(r.ops)
+	if avg > time.Millisecond {
+		avg = avg.Round(time.Microsecond)
+	}
+
+	s := fmt.Sprintf("%s\t%d\t%s\t%.1f ops/s", r.name, r.ops, avg, float64(r.ops)/r.elapsed.Seconds())
+	if r.bytes > 0 {
+		s += fmt.Sprintf("\t%.1f MiB/s", float64(r.bytes)/(1<<20)/r.elapsed.Seconds())
+	}
+
+	return s
+}
+
+func (o *BenchOptions) Run(ctx context.Context, _ ...string) (retErr error) {
+	defer func() {
+		if retErr != nil {
+			retErr = &BenchError{retErr}
+			return
+		}
+	}()
+
+	dir, err := os.MkdirTemp("", "vlt_bench_")
+	if err != nil {
+		return err
+	}
+
+	o.Debugf("created temporary benchmark directory: %s\n", dir)
+
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			o.Errorf("failure removing dir: %v", err)
+		}
+	}()
+
+	o.Printf("argon2id: memory=%dKiB time=%d parallelism=%d\n", o.kdfParams.Memory, o.kdfParams.Time, o.kdfParams.Parallelism)
+	o.Printf("secrets: %d x %d bytes, iterations: %d\n\n", o.secrets, o.valueSize, o.iterations)
+
+	kdf, err := o.benchKDF()
+	if err != nil {
+		return err
+	}
+
+	encrypt, decrypt, err := o.benchAESGCM()
+	if err != nil {
+		return err
+	}
+
+	vaultResults, err := o.benchVault(ctx, filepath.Join(dir, ".vlt"))
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(tw, "OPERATION\tOPS\tAVG\tRATE\tTHROUGHPUT")
+
+	for _, r := range append([]benchResult{kdf, encrypt, decrypt}, vaultResults...) {
+		fmt.Fprintln(tw, r.String())
+	}
+
+	return tw.Flush()
+}
+
+func (o *BenchOptions) benchKDF() (benchResult, error) {
+	salt, err := vaultcrypto.RandBytes(vaultcrypto.SaltSize)
+	if err != nil {
+		return benchResult{}, err
+	}
+
+	kdf := vaultcrypto.NewArgon2idKDF(vaultcrypto.WithSalt(salt), vaultcrypto.WithParams(o.kdfParams))
+
+	start := time.Now()
+
+	for range o.iterations {
+		securebytes.Wipe(kdf.Derive([]byte(benchPassword)))
+	}
+
+	return benchResult{name: "kdf", ops: o.iterations, elapsed: time.Since(start)}, nil
+}
+
+func (o *BenchOptions) benchAESGCM() (encrypt benchResult, decrypt benchResult, _ error) {
+	key, err := vaultcrypto.RandBytes(32)
+	if err != nil {
+		return encrypt, decrypt, err
+	}
+
+	aes, err := vaultcrypto.NewAESGCM(key)
+	if err != nil {
+		return encrypt, decrypt, err
+	}
+
+	value, err := vaultcrypto.RandBytes(o.valueSize)
+	if err != nil {
+		return encrypt, decrypt, err
+	}
+
+	nonce, err := vaultcrypto.RandBytes(vaultcrypto.NonceSizeGCM)
+	if err != nil {
+		return encrypt, decrypt, err
+	}
+
+	var ciphertext []byte
+
+	start := time.Now()
+
+	for range o.secrets {
+		if ciphertext, err = aes.Seal(nonce, value); err != nil {
+			return encrypt, decrypt, err
+		}
+	}
+
+	encrypt = benchResult{name: "secret encrypt", ops: o.secrets, elapsed: time.Since(start), bytes: o.secrets * o.valueSize}
+
+	start = time.Now()
+
+	for range o.secrets {
+		if _, err := aes.Open(nonce, ciphertext); err != nil {
+			return encrypt, decrypt, err
+		}
+	}
+
+	decrypt = benchResult{name: "secret decrypt", ops: o.secrets, elapsed: time.Since(start), bytes: o.secrets * o.valueSize}
+
+	return encrypt, decrypt, nil
+}
+
+// benchVault measures the latency of vault operations
+// on a vault populated with the configured number of secrets.
+func (o *BenchOptions) benchVault(ctx context.Context, path string) ([]benchResult, error) {
+	password := []byte(benchPassword)
+
+	start := time.Now()
+
+	vlt, err := vault.New(ctx, path, password)
+	if err != nil {
+		return nil, err
+	}
+
+	create := benchResult{name: "vault create", ops: 1, elapsed: time.Since(start)}
+
+	value, err := vaultcrypto.RandBytes(o.valueSize)
+	if err != nil {
+		return nil, errors.Join(err, vlt.Close())
+	}
+
+	start = time.Now()
+
+	for i := range o.secrets {
+		if _, err := vlt.InsertNewSecret(ctx, fmt.Sprintf("bench-%d", i), value, []string{"bench"}); err != nil {
+			return nil, errors.Join(err, vlt.Close())
+		}
+	}
+
+	insert := benchResult{name: "secret insert", ops: o.secrets, elapsed: time.Since(start), bytes: o.secrets * o.valueSize}
+
+	start = time.Now()
+
+	for range o.iterations {
+		if _, err := vlt.Seal(ctx); err != nil {
+			return nil, errors.Join(err, vlt.Close())
+		}
+	}
+
+	seal := benchResult{name: "vault seal", ops: o.iterations, elapsed: time.Since(start)}
+
+	if err := vlt.Close(); err != nil {
+		return nil, err
+	}
+
+	key, nonce, err := vault.Login(ctx, path, password)
+	if err != nil {
+		return nil, err
+	}
+	defer securebytes.Wipe(key)
+
+	start = time.Now()
+
+	for range o.iterations {
+		// the vault wipes its nonce on close, hence the copy.
+		vlt, err := vault.Open(ctx, path, vault.WithSessionKey(key, bytes.Clone(nonce)))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := vlt.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	open := benchResult{name: "vault open (session)", ops: o.iterations, elapsed: time.Since(start)}
+
+	start = time.Now()
+
+	for range o.iterations {
+		vlt, err := vault.Open(ctx, path, vault.WithPassword(password))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := vlt.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	openPassword := benchResult{name: "vault open (password)", ops: o.iterations, elapsed: time.Since(start)}
+
+	return []benchResult{create, insert, seal, open, openPassword}, nil
+}
+
+// NewCmdBench creates the bench cobra command.
+func NewCmdBench(defaults *DefaultVltOptions) *cobra.Command {
+	hiddenFlags := []string{"config", "file", "insecure-path-ok", "no-hooks", "no-login-prompt"}
+	o := NewBenchOptions(defaults.StdioOptions)
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: i18n.T("Benchmark vault operations on this machine"),
+		Long: `Benchmark vault operations on this machine.
+
+Measures the Argon2id key derivation time, per-secret AES-GCM encrypt and decrypt
+throughput, and the latency of creating, sealing and opening a vault.
+
+All measurements run against a temporary vault; the user vault is not accessed.
+The Argon2id parameters of the kdf measurement can be adjusted using the --kdf-* flags
+to evaluate alternative settings.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmp.Or(
+				clierror.Check(genericclioptions.RejectDisallowedFlags(cmd, hiddenFlags...)),
+				clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o)),
+			)
+		},
+	}
+
+	genericclioptions.MarkFlagsHidden(cmd, hiddenFlags...)
+
+	cmd.Flags().IntVarP(&o.iterations, "iterations", "n", 3, "number of iterations for the kdf, seal and open measurements")
+	cmd.Flags().IntVarP(&o.secrets, "secrets", "", 1000, "number of secrets to encrypt, decrypt and insert")
+	cmd.Flags().IntVarP(&o.valueSize, "value-size", "", 64, "size of each secret value in bytes")
+	cmd.Flags().Uint32VarP(&o.kdfParams.Memory, "kdf-memory", "", o.kdfParams.Memory, "argon2id memory cost in KiB")
+	cmd.Flags().Uint32VarP(&o.kdfParams.Time, "kdf-time", "", o.kdfParams.Time, "argon2id time cost (iterations)")
+	cmd.Flags().Uint8VarP(&o.kdfParams.Parallelism, "kdf-parallelism", "", o.kdfParams.Parallelism, "argon2id parallelism (threads)")
+
+	return cmd
+}
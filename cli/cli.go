@@ -7,12 +7,16 @@ import (
 	"io/fs"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/clipboard"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
-	"github.com/ladzaretti/vlt-cli/input"
+	"github.com/ladzaretti/vlt-cli/hookscript"
+	"github.com/ladzaretti/vlt-cli/redact"
+	"github.com/ladzaretti/vlt-cli/sandbox"
+	"github.com/ladzaretti/vlt-cli/securebytes"
 	"github.com/ladzaretti/vlt-cli/vault"
 	"github.com/ladzaretti/vlt-cli/vaultdaemon"
 	"github.com/ladzaretti/vlt-cli/vaulterrors"
@@ -22,18 +26,56 @@ import (
 
 var Version = "0.0.0"
 
+// UpdatePublicKey is the minisign public key release archives are verified against
+// by 'vlt self-update'. It is set at build time; builds without it cannot self-update.
+var UpdatePublicKey = ""
+
 const (
-	// defaultDatabaseFilename is the default vault file name.
-	defaultDatabaseFilename = ".vlt"
+	// appDirName is the vlt directory in the XDG base directories.
+	appDirName = "vlt"
+
+	// defaultDatabaseFilename is the default vault file name, in the vlt XDG data directory.
+	defaultDatabaseFilename = "vault.db"
+
+	// defaultConfigName is the default configuration file name, in the vlt XDG config directory.
+	defaultConfigName = "config.toml"
 
-	// defaultConfigName is the default configuration file name.
-	defaultConfigName = ".vlt.toml"
+	// legacyDatabaseFilename is the vault file name in the home directory,
+	// used by default before vlt followed the XDG base directories.
+	legacyDatabaseFilename = ".vlt"
+
+	// legacyConfigName is the configuration file name in the home directory,
+	// used by default before vlt followed the XDG base directories.
+	legacyConfigName = ".vlt.toml"
+
+	// defaultVaultPathHelp and defaultConfigPathHelp describe the default paths in help texts.
+	defaultVaultPathHelp  = "$XDG_DATA_HOME/vlt/vault.db"
+	defaultConfigPathHelp = "$XDG_CONFIG_HOME/vlt/config.toml"
 
 	// defaultSessionDuration is the fallback for session duration.
 	defaultSessionDuration = "1m"
 
 	// defaultMaxHistorySnapshots is the default number of vault snapshots to keep.
 	defaultMaxHistorySnapshots = 3
+
+	// defaultAutoVacuumThreshold is the default reclaimable space in KiB
+	// above which the vault container is vacuumed.
+	defaultAutoVacuumThreshold = 1024
+
+	// defaultMinPasswordLength is the default minimum length of a new master password.
+	defaultMinPasswordLength = 8
+
+	// defaultMinPasswordClasses is the default minimum number of character classes of a new master password.
+	defaultMinPasswordClasses = 1
+
+	// maxPasswordClasses is the number of character classes: lower case, upper case, digits and symbols.
+	maxPasswordClasses = 4
+
+	// defaultMinPasswordBits is the default minimum estimated strength of a new master password.
+	defaultMinPasswordBits = 40
+
+	// defaultBackupKeep is the default number of backups 'vlt backup' keeps.
+	defaultBackupKeep = 10
 )
 
 var (
@@ -41,12 +83,12 @@ var (
 
 	// preRunSkipCommands are commands that skips the pre-run execution.
 	preRunSkipCommands = append(
-		[]string{"config", "validate", "version"},
+		[]string{"bench", "config", "docs", "gen-docs", "migrate", "self-update", "validate", "version"},
 		cobraCompletionCommands...,
 	)
 
 	// preRunPartialCommands are commands that require partial pre-run execution without vault opening.
-	preRunPartialCommands = []string{"create", "generate", "login", "logout", "rotate"}
+	preRunPartialCommands = []string{"backup", "create", "generate", "lock", "log", "login", "logout", "move", "rotate", "status", "title", "verify-backup"}
 
 	// postRunSkipCommands are commands that skips the post-run execution.
 	postRunSkipCommands = append(
@@ -54,6 +96,19 @@ var (
 		preRunSkipCommands...,
 	)
 
+	// metadataOnlyCommands are commands that only read secret names and labels.
+	// When a session exists, they are served from the vault metadata index
+	// without decrypting the vault.
+	metadataOnlyCommands = []string{"find"}
+
+	// fullVaultFlags are flags of metadata-only commands that need data
+	// the metadata index does not hold, e.g., usage statistics or attributes.
+	fullVaultFlags = []string{"unused", "unmodified", "attr", "sort"}
+
+	// planMigrationsFlag is the flag of commands listing the pending migrations
+	// of the vault, which is then unlocked without being migrated, see [vault.PlanMigrations].
+	planMigrationsFlag = "migrations"
+
 	// persistRequiredCommands lists commands that modify the in-memory vault state,
 	// requiring subsequent persistence to the on-disk vault container.
 	persistRequiredCommands = []string{
@@ -62,22 +117,52 @@ var (
 		"save",
 		"update",
 		"secret", // vlt update secret
+		"label",  // vlt update label
 	}
 )
 
 type vaultHooks struct {
 	postLogin []string
 	postWrite []string
+	postLock  []string
+
+	// script is the hook script, run after the hook commands, if set.
+	script *hookscript.Script
+
+	// command is the vlt command triggering the hooks, passed to the script.
+	command string
 }
 
 type VaultOptions struct {
 	path                string
 	vault               *vault.Vault
+	index               *vault.Index // index is set instead of vault when a metadata-only command is served from the index.
+	metadataOnly        bool
+	fullVaultRequired   bool // fullVaultRequired disables serving metadata-only commands from the index, see [fullVaultFlags].
+	planMigrations      bool // planMigrations sets migrationPlan instead of opening the vault, see [planMigrationsFlag].
+	migrationPlan       *vault.MigrationPlan
 	hooks               vaultHooks
 	disableHooks        bool
 	nonInteractive      bool
+	identityFile        string          // identityFile is the age identity file used to unlock the vault as a member, see [VaultOptions.loginWithIdentity].
+	passwordCommand     []string        // passwordCommand prints the master password instead of prompting for it, see [VaultOptions.readPassword].
+	identity            *vault.Identity // identity is the cached identity of the vault, see [VaultOptions.vaultIdentity].
+	insecurePathOK      bool
+	persistRequired     bool // persistRequired marks the in-memory vault as modified by the current command.
+	trackUsage          bool // trackUsage enables recording secret retrievals, see [VaultOptions.recordAccess].
+	usageRecorded       bool // usageRecorded marks the in-memory vault as modified by usage tracking only.
+	containerWritten    bool // containerWritten marks the vault container as written without sealing the vault, e.g., vacuumed.
 	sessionDuration     time.Duration
+	discreet            bool // discreet masks secret names and labels in tables, see [VaultOptions.printSecrets].
+	confirmEachUse      bool // confirmEachUse requires confirming each use of the session, see [vaultdaemon.WithConfirmEachUse].
 	maxHistorySnapshots int
+	autoVacuumThreshold int                       // autoVacuumThreshold is the reclaimable container space in KiB, see [vault.WithAutoVacuumThreshold].
+	uniqueNames         bool                      // uniqueNames rejects secret names already in use, see [vault.WithUniqueNames].
+	lintRules           map[string]LintRuleConfig // lintRules check secret values on save and update, see [VaultOptions.lintSecret].
+	skipLint            bool
+	minPasswordLength   int
+	minPasswordClasses  int
+	minPasswordBits     int // minPasswordBits is the minimum estimated strength of a new master password.
 }
 
 var _ genericclioptions.BaseOptions = &VaultOptions{}
@@ -100,6 +185,11 @@ func (*VaultOptions) Complete() error { return nil }
 
 func (*VaultOptions) Validate() error { return nil }
 
+// redactSecrets registers the secret values passing through a vault
+// to be redacted from logs, see [vault.WithSecretObserver].
+// Only keyed digests of the values are kept, see [redact.Add].
+var redactSecrets = vault.WithSecretObserver(func(value []byte) { redact.Add(value) })
+
 // Run initializes the Vault object from the specified existing file.
 func (o *VaultOptions) Open(ctx context.Context, io *genericclioptions.StdioOptions, sessionClient *vaultdaemon.SessionClient) error {
 	exists, err := o.vaultExists()
@@ -111,16 +201,38 @@ func (o *VaultOptions) Open(ctx context.Context, io *genericclioptions.StdioOpti
 		return fmt.Errorf("%w: %s", vaulterrors.ErrVaultFileNotFound, o.path)
 	}
 
-	opts := []vault.Option{vault.WithMaxHistorySnapshots(o.maxHistorySnapshots)}
+	if err := o.verifyPath(io); err != nil {
+		return err
+	}
+
+	opts := []vault.Option{
+		vault.WithMaxHistorySnapshots(o.maxHistorySnapshots),
+		vault.WithAutoVacuumThreshold(int64(o.autoVacuumThreshold) << 10),
+		vault.WithUniqueNames(o.uniqueNames),
+		redactSecrets,
+	}
 
 	// nil-safe: sessionClient methods handle nil receivers safely.
-	key, nonce, err := sessionClient.GetSessionKey(ctx, o.path)
+	key, nonce, err := sessionClient.GetSessionKey(ctx, o.path, o.sessionOptions(ctx, io)...)
 	if err != nil {
+		if errors.Is(err, vaultdaemon.ErrSessionDenied) {
+			return err
+		}
+
 		io.Debugf("vlt: no session found, falling back to password: %v\n", err)
 	}
 
+	if (key == nil || nonce == nil) && len(o.identityFile) > 0 {
+		key, nonce, err = o.loginWithIdentity(ctx, io, sessionClient)
+		if err != nil {
+			return err
+		}
+		defer securebytes.Wipe(key)
+	}
+
 	if key == nil || nonce == nil {
-		if o.nonInteractive {
+		// the password command does not prompt, it is run with --no-login-prompt too.
+		if o.nonInteractive && len(o.passwordCommand) == 0 {
 			return vaulterrors.ErrInteractiveLoginDisabled
 		}
 
@@ -128,13 +240,40 @@ func (o *VaultOptions) Open(ctx context.Context, io *genericclioptions.StdioOpti
 		if err != nil {
 			return err
 		}
-		defer clear(password)
+		defer securebytes.Wipe(password)
+
+		redact.Add(password)
 
 		opts = append(opts, vault.WithPassword(password))
 	} else {
+		redact.Add(key, nonce)
+
+		if o.metadataOnly {
+			idx, err := vault.OpenIndex(ctx, o.path, vault.WithSessionKey(key, nonce))
+			if err == nil {
+				io.Debugf("vlt: serving metadata from the vault index\n")
+				o.index = idx
+
+				return nil
+			}
+
+			io.Debugf("vlt: metadata index unavailable, opening vault: %v\n", err)
+		}
+
 		opts = append(opts, vault.WithSessionKey(key, nonce))
 	}
 
+	if o.planMigrations {
+		plan, err := vault.PlanMigrations(ctx, o.path, opts...)
+		if err != nil {
+			return err
+		}
+
+		o.migrationPlan = plan
+
+		return nil
+	}
+
 	v, err := vault.Open(ctx, o.path, opts...)
 	if err != nil {
 		return err
@@ -142,25 +281,54 @@ func (o *VaultOptions) Open(ctx context.Context, io *genericclioptions.StdioOpti
 
 	o.vault = v
 
+	o.warnDuplicateNames(ctx, io)
+
 	return nil
 }
 
-func (o *VaultOptions) login(ctx context.Context, io *genericclioptions.StdioOptions, sessionClient *vaultdaemon.SessionClient) ([]byte, error) {
-	password, err := input.PromptReadSecure(io.Out, int(io.In.Fd()), "[vlt] Password for %q:", o.path)
+// warnDuplicateNames reports the secret names in use more than once,
+// which prevent unique names from being enforced, see [vault.Vault.DuplicateNames].
+func (o *VaultOptions) warnDuplicateNames(ctx context.Context, io *genericclioptions.StdioOptions) {
+	if !o.uniqueNames {
+		return
+	}
+
+	duplicates, err := o.vault.DuplicateNames(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("prompt password: %v", err)
+		io.Debugf("vlt: %v\n", err)
+		return
 	}
 
-	if len(password) == 0 {
-		return nil, vaulterrors.ErrEmptyPassword
+	if len(duplicates) > 0 {
+		io.Infof("unique_names is enabled, but these names are in use more than once: %s; rename them using 'vlt update'\n",
+			strings.Join(duplicates, ", "))
 	}
+}
 
-	key, nonce, err := vault.Login(ctx, o.path, password, vault.WithMaxHistorySnapshots(o.maxHistorySnapshots))
+// secretSearcher returns the source of secret metadata for search queries,
+// the metadata index if loaded, the vault otherwise.
+func (o *VaultOptions) secretSearcher() secretSearcher {
+	if o.index != nil {
+		return o.index
+	}
+
+	return o.vault
+}
+
+func (o *VaultOptions) login(ctx context.Context, io *genericclioptions.StdioOptions, sessionClient *vaultdaemon.SessionClient) ([]byte, error) {
+	password, err := o.readPassword(ctx, io)
+	if err != nil {
+		return nil, err
+	}
+
+	key, nonce, err := o.passwordLogin(ctx, io, password)
 	if err != nil {
+		securebytes.Wipe(password)
 		return nil, err
 	}
+	defer securebytes.Wipe(key)
 
-	_ = sessionClient.Login(ctx, o.path, key, nonce, o.sessionDuration)
+	_ = sessionClient.Login(ctx, o.path, key, nonce, o.sessionDuration, o.sessionOptions(ctx, io, vaultdaemon.WithConfirmEachUse(o.confirmEachUse))...)
 
 	if err := o.postLoginHook(ctx, io); err != nil {
 		return nil, fmt.Errorf("post-login hook: %w", err)
@@ -169,6 +337,65 @@ func (o *VaultOptions) login(ctx context.Context, io *genericclioptions.StdioOpt
 	return password, nil
 }
 
+// passwordLogin verifies the password and derives the vault key, see [vault.Login],
+// warning about failed unlock attempts since the last unlock.
+func (o *VaultOptions) passwordLogin(ctx context.Context, io *genericclioptions.StdioOptions, password []byte) (key []byte, nonce []byte, _ error) {
+	attempts, err := vault.FailedUnlocks(ctx, o.path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, nonce, err = vault.Login(ctx, o.path, password, vault.WithMaxHistorySnapshots(o.maxHistorySnapshots))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attempts.Failed > 0 {
+		io.Errorf("%d failed unlock attempt(s) since the last unlock, the last one at %s\n",
+			attempts.Failed, attempts.LastFailedAt.Format(time.DateTime))
+	}
+
+	return key, nonce, nil
+}
+
+// loginWithIdentity unlocks the vault as a member using the age identity file,
+// see [vault.LoginWithIdentity].
+func (o *VaultOptions) loginWithIdentity(ctx context.Context, io *genericclioptions.StdioOptions, sessionClient *vaultdaemon.SessionClient) (key []byte, nonce []byte, _ error) {
+	key, nonce, err := o.unwrapKey(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_ = sessionClient.Login(ctx, o.path, key, nonce, o.sessionDuration, o.sessionOptions(ctx, io, vaultdaemon.WithConfirmEachUse(o.confirmEachUse))...)
+
+	if err := o.postLoginHook(ctx, io); err != nil {
+		securebytes.Wipe(key)
+		return nil, nil, fmt.Errorf("post-login hook: %w", err)
+	}
+
+	return key, nonce, nil
+}
+
+// updateSessionStamp updates the session of the vault, if any, to the vault file
+// written by vlt without resealing the vault, e.g., vacuumed. Otherwise, the daemon
+// would drop the session, as if the file was replaced, see [vaultdaemon.SessionClient.UpdateSession].
+func (o *VaultOptions) updateSessionStamp(ctx context.Context, io *genericclioptions.StdioOptions, sessionClient *vaultdaemon.SessionClient) {
+	if err := sessionClient.UpdateSession(ctx, o.path, nil, o.sessionOptions(ctx, io)...); err != nil {
+		io.Errorf("session stamp update failed: %v\n", err)
+	}
+}
+
+// unwrapKey unwraps the vault key from the member key slot
+// matching the configured age identity file.
+func (o *VaultOptions) unwrapKey(ctx context.Context) (key []byte, nonce []byte, _ error) {
+	identities, err := readAgeIdentities(o.identityFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("identity file: %w", err)
+	}
+
+	return vault.LoginWithIdentity(ctx, o.path, identities, vault.WithMaxHistorySnapshots(o.maxHistorySnapshots))
+}
+
 func (o *VaultOptions) vaultExists() (bool, error) {
 	_, err := os.Stat(o.path)
 	if err == nil {
@@ -182,22 +409,73 @@ func (o *VaultOptions) vaultExists() (bool, error) {
 	return false, fmt.Errorf("stat vault file: %w", err)
 }
 
-func (o *VaultOptions) postLoginHook(ctx context.Context, io *genericclioptions.StdioOptions) error {
-	if o.disableHooks {
-		io.Debugf("post-login hook skipped\n")
-		return nil
+// verifyPath checks the vault file and its directory for insecure permissions.
+// See [verifyVaultPath].
+func (o *VaultOptions) verifyPath(io *genericclioptions.StdioOptions) error {
+	return verifyVaultPath(o.path, o.insecurePathOK, io.Errorf)
+}
+
+// recordAccess records a retrieval of the secret value, if usage tracking is enabled.
+//
+// Usage statistics are best effort, a failure to record them does not fail the command.
+func (o *VaultOptions) recordAccess(ctx context.Context, io *genericclioptions.StdioOptions, id int) {
+	if !o.trackUsage {
+		return
+	}
+
+	if err := o.vault.RecordAccess(ctx, id, time.Now()); err != nil {
+		io.Debugf("vlt: %v\n", err)
+		return
 	}
 
-	return genericclioptions.RunHook(ctx, io, "post-login", o.hooks.postLogin)
+	o.usageRecorded = true
+}
+
+func (o *VaultOptions) postLoginHook(ctx context.Context, io *genericclioptions.StdioOptions) error {
+	return o.runHook(ctx, io, "post-login", o.hooks.postLogin)
 }
 
 func (o *VaultOptions) postWriteHook(ctx context.Context, io *genericclioptions.StdioOptions) error {
+	return o.runHook(ctx, io, "post-write", o.hooks.postWrite)
+}
+
+func (o *VaultOptions) postLockHook(ctx context.Context, io *genericclioptions.StdioOptions) error {
+	return o.runHook(ctx, io, "post-lock", o.hooks.postLock)
+}
+
+// runHook runs the hook command, followed by the function of the hook
+// defined by the hook script, if any.
+func (o *VaultOptions) runHook(ctx context.Context, io *genericclioptions.StdioOptions, name string, hook []string) error {
 	if o.disableHooks {
-		io.Debugf("post-write hook skipped\n")
+		io.Debugf("%s hook skipped\n", name)
+		return nil
+	}
+
+	if err := genericclioptions.RunHook(ctx, io, name, hook); err != nil {
+		return err
+	}
+
+	if o.hooks.script == nil {
 		return nil
 	}
 
-	return genericclioptions.RunHook(ctx, io, "post-write", o.hooks.postWrite)
+	io.Debugf("running %s hook script %s\n", name, o.hooks.script.Path())
+
+	ran, err := o.hooks.script.Run(ctx, io.Out, io.ErrOut, hookscript.Event{
+		Name:      name,
+		VaultPath: o.path,
+		Command:   o.hooks.command,
+	})
+	if err != nil {
+		io.Errorf("%s hook script failed.\n\n", name)
+		return fmt.Errorf("hook script: %w", err)
+	}
+
+	if ran {
+		io.Infof("%s hook script completed successfully.\n\n", name)
+	}
+
+	return nil
 }
 
 type DefaultVltOptions struct {
@@ -209,6 +487,16 @@ type DefaultVltOptions struct {
 	// sessionClient is used for daemon communication,
 	// it is lazily initialized in [DefaultVltOptions.Run].
 	sessionClient *vaultdaemon.SessionClient
+
+	// cancelTimeout releases the command timeout context, if one was set.
+	cancelTimeout context.CancelFunc
+
+	// noSandbox disables the process sandbox, see [sandbox.Apply].
+	noSandbox bool
+
+	// cmd is the executed command, its flag defaults are set
+	// from the config file once it is loaded.
+	cmd *cobra.Command
 }
 
 var _ genericclioptions.CmdOptions = &DefaultVltOptions{}
@@ -241,6 +529,18 @@ func (o *DefaultVltOptions) Complete() error {
 
 //nolint:revive // allow internal complete() alongside public Complete()
 func (o *DefaultVltOptions) complete() error {
+	// core dumps are disabled before the vault is opened,
+	// a crash would otherwise write the decrypted vault to disk.
+	if !o.configOptions.resolved.AllowCoreDumps {
+		if err := sandbox.DisableCoreDumps(); err != nil {
+			return err
+		}
+	}
+
+	if socket := o.configOptions.resolved.DaemonSocket; len(socket) > 0 {
+		vaultdaemon.SetSocketPath(socket)
+	}
+
 	copyCmd, pasteCmd := o.configOptions.resolved.CopyCmd, o.configOptions.resolved.PasteCmd
 
 	var opts []clipboard.Opt
@@ -256,13 +556,49 @@ func (o *DefaultVltOptions) complete() error {
 		clipboard.SetDefault(clipboard.New(opts...))
 	}
 
+	if o.cmd != nil {
+		if err := applyFlagDefaults(o.cmd, o.configOptions.resolved.Defaults); err != nil {
+			return err
+		}
+
+		o.vaultOptions.fullVaultRequired = slices.ContainsFunc(fullVaultFlags, func(name string) bool {
+			return o.cmd.Flags().Changed(name)
+		})
+
+		o.vaultOptions.planMigrations = o.cmd.Flags().Changed(planMigrationsFlag)
+	}
+
 	o.vaultOptions.maxHistorySnapshots = o.configOptions.resolved.MaxHistorySnapshots
+	o.vaultOptions.autoVacuumThreshold = o.configOptions.resolved.AutoVacuumThreshold
+	o.vaultOptions.uniqueNames = o.configOptions.resolved.UniqueNames
+	o.vaultOptions.lintRules = o.configOptions.resolved.Lint
+	o.vaultOptions.minPasswordLength = o.configOptions.resolved.MinPasswordLength
+	o.vaultOptions.minPasswordClasses = o.configOptions.resolved.MinPasswordClasses
+	o.vaultOptions.minPasswordBits = o.configOptions.resolved.MinPasswordBits
 	o.vaultOptions.sessionDuration = time.Duration(o.configOptions.resolved.SessionDuration)
+	o.vaultOptions.confirmEachUse = o.configOptions.resolved.ConfirmEachUse
+	o.vaultOptions.trackUsage = o.configOptions.resolved.TrackUsage
 	o.vaultOptions.path = o.configOptions.resolved.VaultPath
+	o.vaultOptions.identityFile = o.configOptions.resolved.IdentityFile
+	o.vaultOptions.passwordCommand = o.configOptions.resolved.PasswordCommand
+
+	o.Theme = o.configOptions.resolved.Theme
+	o.NoColor = o.NoColor || o.configOptions.resolved.NoColor
+	o.vaultOptions.discreet = o.vaultOptions.discreet || o.configOptions.resolved.Discreet
+	clierror.SetStyler(o.Styler(o.ErrOut))
 
 	o.vaultOptions.hooks = vaultHooks{
 		postLogin: o.configOptions.resolved.PostLoginCmd,
 		postWrite: o.configOptions.resolved.PostWriteCmd,
+		postLock:  o.configOptions.resolved.PostLockCmd,
+	}
+
+	if script := o.configOptions.resolved.HookScript; len(script) > 0 {
+		o.vaultOptions.hooks.script = hookscript.New(script, o.configOptions.resolved.HookScriptAllowExec)
+	}
+
+	if o.cmd != nil {
+		o.vaultOptions.hooks.command = strings.TrimPrefix(o.cmd.CommandPath(), o.cmd.Root().Name()+" ")
 	}
 
 	return nil
@@ -290,24 +626,55 @@ func (o *DefaultVltOptions) Run(ctx context.Context, args ...string) error {
 		cmd = args[0]
 	}
 
-	if slices.Contains(preRunPartialCommands, cmd) {
+	// plugins open the vault, if at all, by running vlt themselves.
+	if slices.Contains(preRunPartialCommands, cmd) || isPluginCommand(o.cmd) {
 		return nil
 	}
 
 	if o.configOptions.resolved.enableSession {
-		c, err := vaultdaemon.NewSessionClient()
-		if err != nil {
+		c, err := connectDaemon(ctx, o.StdioOptions, o.configOptions.resolved.AutostartDaemon)
+		switch {
+		case errors.Is(err, vaultdaemon.ErrIncompatibleDaemon):
+			o.Errorf("%v: continuing without session support\nRestart vltd after upgrading vlt (e.g., 'systemctl --user restart vltd').\n\n", err)
+		case err != nil:
+			o.Debugf("%v\n", err)
 			o.Infof("vlt: daemon unavailable, continuing without session support\nTo enable session support, make sure the 'vltd' daemon is running.\n\n")
 		}
 
 		o.sessionClient = c
 	}
 
+	o.vaultOptions.metadataOnly = slices.Contains(metadataOnlyCommands, cmd) && !o.vaultOptions.fullVaultRequired
+
 	return o.vaultOptions.Open(ctx, o.StdioOptions, o.sessionClient)
 }
 
+// setCommandTimeout bounds the remaining execution of cmd by the configured
+// command timeout. The timeout starts once the vault is unlocked,
+// so the initial password prompt does not count against it.
+func (o *DefaultVltOptions) setCommandTimeout(cmd *cobra.Command) {
+	timeout := time.Duration(o.configOptions.resolved.CommandTimeout)
+	if timeout <= 0 {
+		return
+	}
+
+	o.Debugf("command timeout: %s\n", timeout)
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	o.cancelTimeout = cancel
+
+	cmd.SetContext(ctx)
+}
+
 func (o *DefaultVltOptions) postRun(ctx context.Context, cmd string) (retErr error) {
-	if slices.Contains(postRunSkipCommands, cmd) {
+	if o.cancelTimeout != nil {
+		defer o.cancelTimeout()
+	}
+
+	// the values registered by the command are no longer logged.
+	defer redact.Reset()
+
+	if slices.Contains(postRunSkipCommands, cmd) || isPluginCommand(o.cmd) {
 		return nil
 	}
 
@@ -316,12 +683,21 @@ func (o *DefaultVltOptions) postRun(ctx context.Context, cmd string) (retErr err
 			retErr = errors.Join(retErr, fmt.Errorf("post-run: %w", err))
 		}
 
+		if err := o.vaultOptions.index.Close(); err != nil {
+			retErr = errors.Join(retErr, fmt.Errorf("post-run: %w", err))
+		}
+
 		if err := o.sessionClient.Close(); err != nil {
 			o.Errorf("post-run: session client close failed: %v", err)
 		}
 	}()
 
-	if !slices.Contains(persistRequiredCommands, cmd) {
+	modified := slices.Contains(persistRequiredCommands, cmd) || o.vaultOptions.persistRequired
+	if !modified && !o.vaultOptions.usageRecorded {
+		if o.vaultOptions.containerWritten {
+			o.vaultOptions.updateSessionStamp(ctx, o.StdioOptions, o.sessionClient)
+		}
+
 		return nil
 	}
 
@@ -330,10 +706,15 @@ func (o *DefaultVltOptions) postRun(ctx context.Context, cmd string) (retErr err
 		return fmt.Errorf("post-run: %w", err)
 	}
 
-	if err := o.sessionClient.UpdateSession(ctx, o.vaultOptions.path, nonce); err != nil {
+	if err := o.sessionClient.UpdateSession(ctx, o.vaultOptions.path, nonce, o.vaultOptions.sessionOptions(ctx, o.StdioOptions)...); err != nil {
 		o.Errorf("post-run: session nonce update failed: %v", err)
 	}
 
+	// usage statistics are bookkeeping, not a change of the stored secrets.
+	if !modified {
+		return nil
+	}
+
 	if err := o.vaultOptions.postWriteHook(ctx, o.StdioOptions); err != nil {
 		o.Errorf("post-write hook failed: %v", err)
 	}
@@ -361,25 +742,73 @@ Description:
 
   The vault file is never written to disk in plaintext. 
 
+Aliases:
+  Common commands can be shortened by aliases defined in the [aliases] section of the config file,
+  e.g., with pw = 'show -c --name', 'vlt pw foo' runs 'vlt show -c --name foo'.
+
+Plugins:
+  Unknown commands run the 'vlt-<name>' executable found on PATH, e.g., 'vlt foo bar' runs 'vlt-foo bar'.
+  Global flags must precede the plugin name. Plugins receive VLT_BIN, VLT_VAULT_PATH, VLT_CONFIG_PATH and,
+  if sessions are enabled, VLT_SESSION_SOCKET and VLT_SESSION_DURATION in their environment.
+
 Environment Variables:
-  VLT_CONFIG_PATH - overrides the default config path: "~/.vlt.toml".`,
+  VLT_CONFIG_PATH - overrides the default config path: "$XDG_CONFIG_HOME/vlt/config.toml".
+  VLT_PASSWORD_COMMAND - command printing the master password, run instead of prompting when
+    no session exists, e.g., "secret-tool lookup vlt master"; overrides 'vault.password_command'.
+  VLT_OLD_PASSWORD_FILE, VLT_NEW_PASSWORD_FILE - files the current and new master passwords
+    are read from by 'vlt rotate' instead of prompting, e.g., for scheduled rotation.
+  XDG_CONFIG_HOME, XDG_DATA_HOME - base directories of the default config and vault paths
+    (default: "~/.config" and "~/.local/share"). The legacy "~/.vlt.toml" and "~/.vlt" paths
+    are still used if only they exist, see 'vlt config migrate'.
+  LC_ALL, LC_MESSAGES, LANG - select the language of prompts and messages, e.g., "de_DE.UTF-8".
+
+Exit Codes:
+  0 success, 1 other failure, 2 invalid usage, 3 no secret found, 4 ambiguous match,
+  5 authentication failed, 6 vault locked (--no-login-prompt), 7 unlock throttled,
+  8 vault file not found, 124 timed out, 130 interrupted.`,
 		SilenceUsage: true,
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := clierror.Check(o.ConfigureLogger()); err != nil {
+				return err
+			}
+
+			// the configured theme is applied once the config file is loaded.
+			clierror.SetStyler(o.Styler(o.ErrOut))
+
+			if !o.noSandbox {
+				if err := sandbox.Apply(); err != nil {
+					o.Debugf("%v\n", err)
+				}
+			}
+
 			if slices.Contains(preRunSkipCommands, cmd.Name()) {
 				return nil
 			}
 
-			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, cmd.Name()))
+			o.cmd = cmd
+
+			if err := clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, cmd.Name())); err != nil {
+				return err
+			}
+
+			o.setCommandTimeout(cmd)
+
+			return nil
 		},
 		PersistentPostRunE: func(cmd *cobra.Command, _ []string) error {
-			return clierror.Check(o.postRun(cmd.Context(), cmd.Name()))
+			ctx := cmd.Context()
+			return clierror.Check(genericclioptions.WithContextError(ctx, o.postRun(ctx, cmd.Name())))
 		},
 	}
 
-	cmd.SetArgs(args)
-
-	cmd.PersistentFlags().BoolVarP(&o.Verbose, "verbose", "v", false, "enable verbose output")
+	cmd.PersistentFlags().BoolVarP(&o.Verbose, "verbose", "v", false, "enable verbose output, same as --log-level=debug")
+	cmd.PersistentFlags().BoolVarP(&o.Quiet, "quiet", "q", false, "suppress informational messages and warnings, same as --log-level=error")
+	cmd.PersistentFlags().StringVarP(&o.LogLevel, "log-level", "", "info", "minimal level of log messages (debug, info, warn, error)")
+	cmd.PersistentFlags().StringVarP(&o.LogFormat, "log-format", "", genericclioptions.LogFormatText, "format of log messages (text, json)")
 	cmd.PersistentFlags().BoolVarP(&o.vaultOptions.disableHooks, "no-hooks", "H", false, "disable hook execution")
+	cmd.PersistentFlags().BoolVarP(&o.noSandbox, "no-sandbox", "", false, "disable the process sandbox (seccomp filter, no_new_privs), for debugging")
+	cmd.PersistentFlags().BoolVarP(&o.NoColor, "no-color", "", false, "disable colored output")
+	cmd.PersistentFlags().BoolVarP(&o.vaultOptions.discreet, "discreet", "", false, "mask secret names and labels in tables, e.g., while screen sharing")
 	cmd.PersistentFlags().BoolVarP(
 		&o.vaultOptions.nonInteractive,
 		"no-login-prompt",
@@ -387,22 +816,36 @@ Environment Variables:
 		false,
 		"do not prompt for login; use existing session or fail",
 	)
+	cmd.PersistentFlags().BoolVarP(
+		&o.vaultOptions.insecurePathOK,
+		"insecure-path-ok",
+		"",
+		false,
+		"allow a vault file or directory with permissions accessible by other users",
+	)
 	cmd.PersistentFlags().StringVarP(&o.configOptions.cliFlags.vaultPath, "file", "f", "",
-		fmt.Sprintf("database file path (default: ~/%s)", defaultDatabaseFilename))
+		"database file path (default: "+defaultVaultPathHelp+")")
+	cmd.PersistentFlags().StringVarP(&o.configOptions.cliFlags.identityFile, "identity-file", "", "",
+		"age identity file used to unlock the vault as a member instead of the password")
 	cmd.PersistentFlags().StringVarP(
 		&o.configOptions.cliFlags.configPath,
 		"config",
 		"",
 		"",
-		fmt.Sprintf("configuration file path (default: ~/%s)", defaultConfigName),
+		"configuration file path (default: "+defaultConfigPathHelp+")",
 	)
 
 	genericclioptions.MarkAllFlagsHidden(cmd, "help")
 
 	cmd.AddCommand(newVersionCommand(o))
+	cmd.AddCommand(NewCmdDocs(o))
+	cmd.AddCommand(NewCmdGenDocs(o))
 	cmd.AddCommand(NewCmdGenerate(o))
 	cmd.AddCommand(NewCmdConfig(o))
 	cmd.AddCommand(NewCmdLogout(o))
+	cmd.AddCommand(NewCmdLock(o))
+	cmd.AddCommand(NewCmdTitle(o))
+	cmd.AddCommand(NewCmdMove(o))
 	cmd.AddCommand(NewCmdCreate(o))
 	cmd.AddCommand(NewCmdRotate(o))
 	cmd.AddCommand(NewCmdRemove(o))
@@ -410,10 +853,32 @@ Environment Variables:
 	cmd.AddCommand(NewCmdImport(o))
 	cmd.AddCommand(NewCmdExport(o))
 	cmd.AddCommand(NewCmdVacuum(o))
+	cmd.AddCommand(NewCmdFsck(o))
+	cmd.AddCommand(NewCmdBench(o))
 	cmd.AddCommand(NewCmdLogin(o))
+	cmd.AddCommand(NewCmdSelfUpdate(o))
 	cmd.AddCommand(NewCmdSave(o))
 	cmd.AddCommand(NewCmdFind(o))
 	cmd.AddCommand(NewCmdShow(o))
+	cmd.AddCommand(NewCmdStats(o))
+	cmd.AddCommand(NewCmdShare(o))
+	cmd.AddCommand(NewCmdMember(o))
+	cmd.AddCommand(NewCmdBackup(o))
+	cmd.AddCommand(NewCmdVerifyBackup(o))
+	cmd.AddCommand(NewCmdSession(o))
+	cmd.AddCommand(NewCmdWifi(o))
+	cmd.AddCommand(NewCmdPasskey(o))
+	cmd.AddCommand(NewCmdSSH(o))
+	cmd.AddCommand(NewCmdAge(o))
+	cmd.AddCommand(NewCmdSops(o))
+	cmd.AddCommand(NewCmdCSIProvider(o))
+	cmd.AddCommand(NewCmdPush(o))
+	cmd.AddCommand(NewCmdPull(o))
+	cmd.AddCommand(NewCmdTemplateHelper(o))
+
+	// aliases and plugins are resolved once all built-in commands are known,
+	// they take precedence. An alias may expand to a plugin.
+	cmd.SetArgs(addPluginCommand(o, cmd, expandAlias(cmd, expandTemplateHelper(cmd, args))))
 
 	return cmd
 }
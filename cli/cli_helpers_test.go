@@ -1,12 +1,19 @@
 package cli_test
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"path"
+	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
 	"time"
 	"unicode"
@@ -16,8 +23,11 @@ import (
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
 	"github.com/ladzaretti/vlt-cli/input"
 	"github.com/ladzaretti/vlt-cli/randstring"
+	"github.com/ladzaretti/vlt-cli/redact"
 	"github.com/ladzaretti/vlt-cli/vault"
 	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+	"github.com/ladzaretti/vlt-cli/vlttest"
 
 	gocmp "github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -28,21 +38,37 @@ const (
 	mockedPromptPassword = "mocked_prompt_password_input"
 )
 
+// testEnv is a [vlttest.Env] with the configured settings of a test.
 type testEnv struct {
-	tempDir              string
-	configPath           string
-	vaultPath            string
-	clipboardContentPath string
-	hookOutputPath       string
+	*vlttest.Env
+
+	hookOutputPath string
 }
 
 type testEnvConfig struct {
-	writeHook bool
-	loginHook bool
+	writeHook   bool
+	loginHook   bool
+	trackUsage  bool
+	uniqueNames bool
+
+	minPasswordClasses int
+	sessionDuration    time.Duration
 }
 
 type testEnvConfigOpt = func(*testEnvConfig)
 
+func withMinPasswordClasses(n int) testEnvConfigOpt {
+	return func(c *testEnvConfig) {
+		c.minPasswordClasses = n
+	}
+}
+
+func withSessionDuration(d time.Duration) testEnvConfigOpt {
+	return func(c *testEnvConfig) {
+		c.sessionDuration = d
+	}
+}
+
 func withLoginHook(enabled bool) testEnvConfigOpt {
 	return func(c *testEnvConfig) {
 		c.loginHook = enabled
@@ -55,6 +81,20 @@ func withWriteHook(enabled bool) testEnvConfigOpt {
 	}
 }
 
+func withTrackUsage(enabled bool) testEnvConfigOpt {
+	return func(c *testEnvConfig) {
+		c.trackUsage = enabled
+	}
+}
+
+func withUniqueNames(enabled bool) testEnvConfigOpt {
+	return func(c *testEnvConfig) {
+		c.uniqueNames = enabled
+	}
+}
+
+// setupTestEnv creates a [vlttest.Env], the vault of which is protected by mockedPromptPassword,
+// and pasting from its clipboard returns mockedPastedPassword.
 func setupTestEnv(t *testing.T, opts ...testEnvConfigOpt) testEnv {
 	t.Helper()
 
@@ -64,58 +104,37 @@ func setupTestEnv(t *testing.T, opts ...testEnvConfigOpt) testEnv {
 		opt(config)
 	}
 
-	tempDir := t.TempDir()
-
-	ff, err := os.CreateTemp(tempDir, ".clipboard.*")
-	if err != nil {
-		t.Fatalf("failed to create clipboard content file: %v", err)
-	}
-	defer func() { //nolint:wsl_v5
-		_ = ff.Close()
-	}()
-
-	f, err := os.CreateTemp(tempDir, ".vlt.*.toml")
-	if err != nil {
-		t.Fatalf("failed to create temp config file: %v", err)
+	// values registered by previous tests would hide missing redactions.
+	t.Cleanup(redact.Reset)
+
+	envOpts := []vlttest.EnvOption{
+		vlttest.WithPassword(mockedPromptPassword),
+		vlttest.WithSessionDuration(config.sessionDuration),
+		vlttest.WithVaultConfig(fmt.Sprintf(`
+			track_usage = %t
+			unique_names = %t
+			min_password_classes = %d
+		`, config.trackUsage, config.uniqueNames, max(config.minPasswordClasses, 1))),
 	}
-	defer func() { //nolint:wsl_v5
-		_ = f.Close()
-	}()
 
 	hookOutputPath := ""
-	clipboardContentPath := ff.Name()
-	configPath := f.Name()
-	vaultPath := path.Join(tempDir, ".vlt")
-
-	content := fmt.Sprintf(`
-		[vault]
-		path = '%s'
-		session_duration = '%s'
-		[clipboard]
-		copy_cmd=['tee', '%s']
-		paste_cmd=['printf', '%s']
-	`, vaultPath, "0m", clipboardContentPath, mockedPastedPassword)
 
 	if config.loginHook || config.writeHook {
-		f, hooksConfig := setupHookTest(t, tempDir, *config)
+		f, hooksConfig := setupHookTest(t, t.TempDir(), *config)
 		hookOutputPath = f.Name()
-		content += hooksConfig
+		envOpts = append(envOpts, vlttest.WithConfig(hooksConfig))
 	}
 
-	if _, err := f.WriteString(content); err != nil {
-		t.Fatalf("failed to write config content: %v", err)
-	}
+	env := vlttest.NewEnv(t, envOpts...)
+	env.SetClipboard(t, mockedPastedPassword)
 
-	if err := f.Sync(); err != nil {
-		t.Fatalf("failed to flush config file: %v", err)
-	}
+	// keep the clipboard ownership marker out of the real runtime directory,
+	// as commands are run without [vlttest.Env.Run].
+	t.Setenv("XDG_RUNTIME_DIR", env.Dir)
 
 	return testEnv{
-		tempDir:              tempDir,
-		configPath:           configPath,
-		vaultPath:            vaultPath,
-		clipboardContentPath: clipboardContentPath,
-		hookOutputPath:       hookOutputPath,
+		Env:            env,
+		hookOutputPath: hookOutputPath,
 	}
 }
 
@@ -150,53 +169,145 @@ func setupHookTest(t *testing.T, tempDir string, config testEnvConfig) (f *os.Fi
 	return f, hooksTOML
 }
 
-// setupIOStreams creates IOStreams with a mocked stdin.
-func setupIOStreams(t *testing.T, stdinData []byte, stdinFileInfoFn func(string, int) os.FileInfo) (ioStreams *genericclioptions.IOStreams, out *bytes.Buffer, errOut *bytes.Buffer) {
+// stdinTTY and stdinPiped select the stdin of [setupIOStreams].
+const (
+	stdinTTY   = true
+	stdinPiped = false
+)
+
+// setupIOStreams creates IOStreams with a mocked stdin, see [vlttest.NewIOStreams],
+// and reports command errors to errOut until the test ends.
+// Passwords are prompted for by the terminal prompter, mocked by [input.SetDefaultReadPassword].
+func setupIOStreams(t *testing.T, stdinData []byte, tty bool) (ioStreams *genericclioptions.IOStreams, out *bytes.Buffer, errOut *bytes.Buffer) {
 	t.Helper()
 
-	var (
-		buf       = bytes.NewBuffer(stdinData)
-		stdinInfo = stdinFileInfoFn("stdin", len(stdinData))
-	)
+	s := vlttest.NewIOStreams(string(stdinData), tty)
+	s.SetPrompter(nil)
 
-	stdinReader := genericclioptions.NewTestFdReader(buf, 0, stdinInfo)
+	debugLog := &bytes.Buffer{}
+	s.DebugLog = debugLog
 
-	ioStreams, _, out, errOut = genericclioptions.NewTestIOStreams(stdinReader)
+	t.Cleanup(func() { assertNoSecretsLogged(t, debugLog.String()) })
 
 	clierror.SetErrorHandler(clierror.PrintErrHandler)
-	clierror.SetErrWriter(ioStreams.ErrOut)
+	clierror.SetErrWriter(s.ErrOut)
 
 	t.Cleanup(func() {
 		clierror.ResetErrorHandler()
 		clierror.ResetErrWriter()
 	})
 
-	return ioStreams, out, errOut
+	return s.IOStreams, s.Out, s.ErrOut
 }
 
-func newTTYFileInfo(name string, size int) os.FileInfo {
-	return genericclioptions.NewMockFileInfo(name, int64(size), os.ModeCharDevice, false, time.Now())
+// seededValues holds the secret values seeded by each test, keyed by test name,
+// see [assertNoSecretsLogged].
+var seededValues = make(map[string][]string)
+
+// recordSeededValues records the secret values of the seeded csv input,
+// hex encoded in the vlt format, or in plain text in other formats.
+func recordSeededValues(t *testing.T, input string) {
+	t.Helper()
+
+	records, err := csv.NewReader(strings.NewReader(input)).ReadAll()
+	if err != nil || len(records) < 2 {
+		return
+	}
+
+	header := records[0]
+
+	for _, r := range records[1:] {
+		for i, col := range header {
+			if i >= len(r) {
+				break
+			}
+
+			switch col {
+			case "secret":
+				if v, err := hex.DecodeString(r[i]); err == nil {
+					seededValues[t.Name()] = append(seededValues[t.Name()], string(v))
+				}
+			case "password":
+				seededValues[t.Name()] = append(seededValues[t.Name()], r[i])
+			}
+		}
+	}
 }
 
-func newNonTTYFileInfo(name string, size int) os.FileInfo {
-	return genericclioptions.NewMockFileInfo(name, int64(size), 0, false, time.Now())
+// assertNoSecretsLogged fails the test if the log messages written by its commands,
+// including debug messages, contain any of its seeded secret values, or the vault password.
+func assertNoSecretsLogged(t *testing.T, log string) {
+	t.Helper()
+
+	values := []string{mockedPromptPassword, mockedPastedPassword}
+
+	// values seeded by a parent test are used by its subtests.
+	for name := t.Name(); ; {
+		values = append(values, seededValues[name]...)
+
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			break
+		}
+
+		name = name[:i]
+	}
+
+	for _, v := range values {
+		if len(v) < redact.MinLen {
+			continue
+		}
+
+		if strings.Contains(log, v) || strings.Contains(log, hex.EncodeToString([]byte(v))) {
+			t.Errorf("secret value %q logged:\n%s", v, log)
+		}
+	}
 }
 
-func mustInitializeVault(t *testing.T, configPath string, vaultPassword string) { //nolint:unparam // vaultPassword always receives mockedPassword ("mocked_password_input")
+// mustInitializeVault creates the vault of env, see [vlttest.Env.Init],
+// and answers the password prompts of the following commands with its password.
+func mustInitializeVault(t *testing.T, env testEnv) {
 	t.Helper()
 
-	ioStreams, _, errOut := setupIOStreams(t, nil, newTTYFileInfo)
+	env.Init(t)
 
 	input.SetDefaultReadPassword(func(_ int) ([]byte, error) {
-		return []byte(vaultPassword), nil
+		return []byte(env.Password), nil
 	})
+}
+
+// startTestDaemon runs vltd on a socket in dir until the test ends.
+func startTestDaemon(t *testing.T, dir string) {
+	t.Helper()
+
+	socket := filepath.Join(dir, "vlt.sock")
+	t.Setenv("VLT_SESSION_SOCKET", socket)
+
+	orig := vaultdaemon.SocketPath()
+	vaultdaemon.SetSocketPath(socket)
+	t.Cleanup(func() { vaultdaemon.SetSocketPath(orig) })
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error)
+
+	go func() {
+		done <- vaultdaemon.Run(ctx, vaultdaemon.WithLogger(slog.New(slog.DiscardHandler)), vaultdaemon.WithAuditLog(""))
+	}()
 
-	cmd := cli.NewDefaultVltCommand(ioStreams, []string{
-		"create", "--config", configPath,
+	t.Cleanup(func() {
+		cancel()
+		<-done
 	})
 
-	if err := cmd.Execute(); err != nil {
-		t.Fatalf("create command failed: %v\nstderr: %q", err, errOut.String())
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if c, err := vaultdaemon.NewSessionClient(); err == nil {
+			_ = c.Close()
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("daemon socket not created")
+		}
 	}
 }
 
@@ -269,6 +380,62 @@ func export(t *testing.T, vaultPath string, vaultPassword []byte) map[int]vaultd
 	return export
 }
 
+// writeXLSX writes a minimal xlsx file at path, holding rows as inline strings in its only sheet.
+func writeXLSX(t *testing.T, path string, rows [][]string) {
+	t.Helper()
+
+	var sheet strings.Builder
+
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for _, row := range rows {
+		sheet.WriteString("<row>")
+
+		for _, c := range row {
+			var text strings.Builder
+			if err := xml.EscapeText(&text, []byte(c)); err != nil {
+				t.Fatal(err)
+			}
+
+			fmt.Fprintf(&sheet, `<c t="inlineStr"><is><t>%s</t></is></c>`, text.String())
+		}
+
+		sheet.WriteString("</row>")
+	}
+
+	sheet.WriteString("</sheetData></worksheet>")
+
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   sheet.String(),
+	}
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func seedSecrets(t *testing.T, vaultEnv testEnv, input string) {
 	t.Helper()
 
@@ -276,7 +443,7 @@ func seedSecrets(t *testing.T, vaultEnv testEnv, input string) {
 		return
 	}
 
-	f, err := os.CreateTemp(vaultEnv.tempDir, "import.csv")
+	f, err := os.CreateTemp(vaultEnv.Dir, "import.csv")
 	if err != nil {
 		t.Fatalf("failed to create import file: %v", err)
 	}
@@ -288,10 +455,12 @@ func seedSecrets(t *testing.T, vaultEnv testEnv, input string) {
 		t.Fatalf("failed to write import file content: %v", err)
 	}
 
-	ioStreams, _, errOut := setupIOStreams(t, nil, newTTYFileInfo)
+	recordSeededValues(t, input)
+
+	ioStreams, _, errOut := setupIOStreams(t, nil, stdinTTY)
 
 	cmd := cli.NewDefaultVltCommand(ioStreams,
-		[]string{"import", "-H", "--config", vaultEnv.configPath, f.Name()})
+		[]string{"import", "-H", "--config", vaultEnv.ConfigPath, f.Name()})
 
 	if err := cmd.Execute(); err != nil {
 		t.Fatalf("unexpected error from import command: %v", err)
@@ -306,7 +475,7 @@ type commandTestCase struct {
 	name                 string
 	seed                 string
 	stdinData            []byte
-	stdinInfoFn          func(string, int) os.FileInfo
+	tty                  bool
 	args                 []string
 	wantErrorAs          any
 	wantSecrets          []vaultdb.SecretWithLabels
@@ -317,12 +486,12 @@ type commandTestCase struct {
 
 func (tt *commandTestCase) run(t *testing.T) {
 	vaultEnv := setupTestEnv(t)
-	mustInitializeVault(t, vaultEnv.configPath, mockedPromptPassword)
+	mustInitializeVault(t, vaultEnv)
 	seedSecrets(t, vaultEnv, tt.seed)
 
-	ioStreams, out, errOut := setupIOStreams(t, tt.stdinData, tt.stdinInfoFn)
+	ioStreams, out, errOut := setupIOStreams(t, tt.stdinData, tt.tty)
 
-	args := []string{"--config", vaultEnv.configPath} //nolint:prealloc
+	args := []string{"--config", vaultEnv.ConfigPath} //nolint:prealloc
 	args = append(args, tt.args...)
 
 	cmd := cli.NewDefaultVltCommand(ioStreams, args)
@@ -341,21 +510,16 @@ func (tt *commandTestCase) run(t *testing.T) {
 		t.Errorf("want stderr output: %q, got %q", tt.wantStderr, gotStderr)
 	}
 
-	got, want := out.String(), fmt.Sprintf(`[vlt] Password for "%s":`, vaultEnv.vaultPath)+tt.wantOutput
+	got, want := out.String(), fmt.Sprintf(`[vlt] Password for "%s":`, vaultEnv.VaultPath)+tt.wantOutput
 	if diff := gocmp.Diff(want, got); diff != "" {
 		t.Errorf("unexpected stdout output (-want +got):\n%s", diff)
 	}
 
-	gotClipboardContent, err := os.ReadFile(vaultEnv.clipboardContentPath)
-	if err != nil {
-		t.Errorf("unexpected error while reading clipboard content containing file: %v", err)
-	}
-
-	if diff := gocmp.Diff(tt.wantClipboardContent, string(gotClipboardContent)); diff != "" {
+	if diff := gocmp.Diff(tt.wantClipboardContent, vaultEnv.Clipboard(t)); diff != "" {
 		t.Errorf("clipboard content mismatch (-want +got):\n%s", diff)
 	}
 
-	exported := export(t, vaultEnv.vaultPath, []byte(mockedPromptPassword))
+	exported := export(t, vaultEnv.VaultPath, []byte(mockedPromptPassword))
 
 	gotSecrets := make([]vaultdb.SecretWithLabels, 0, len(exported))
 
@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/cloudsecrets"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/spf13/cobra"
+)
+
+// cloudAttrPrefix prefixes the provider name in the attribute mapping
+// a secret to its cloud copy, e.g., cloud.aws=prod/db-password.
+const cloudAttrPrefix = "cloud."
+
+type CloudError struct {
+	Err error
+}
+
+func (e *CloudError) Error() string { return "cloud: " + e.Err.Error() }
+
+func (e *CloudError) Unwrap() error { return e.Err }
+
+// CloudSyncOptions holds data required to run the command.
+type CloudSyncOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	search   *SearchableOptions
+	pull     bool // pull copies the cloud values into the vault, instead of pushing.
+	dryRun   bool
+	provider cloudsecrets.Provider
+}
+
+var _ genericclioptions.CmdOptions = &CloudSyncOptions{}
+
+// NewCloudSyncOptions initializes the options struct.
+func NewCloudSyncOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions, pull bool) *CloudSyncOptions {
+	return &CloudSyncOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+		search:       NewSearchableOptions(),
+		pull:         pull,
+	}
+}
+
+func (o *CloudSyncOptions) Complete() error { return o.search.Complete() }
+
+func (o *CloudSyncOptions) Validate() error {
+	if err := o.search.Validate(); err != nil {
+		return &CloudError{err}
+	}
+
+	return nil
+}
+
+func (o *CloudSyncOptions) Run(ctx context.Context, args ...string) error {
+	p, err := cloudsecrets.New(args[0])
+	if err != nil {
+		return &CloudError{err}
+	}
+
+	o.provider = p
+	o.search.WildcardFrom(args[1:])
+
+	if err := o.sync(ctx); err != nil {
+		return &CloudError{err}
+	}
+
+	return nil
+}
+
+// sync pushes or pulls the matching secrets mapped to the provider,
+// secrets whose values already match are left as is.
+func (o *CloudSyncOptions) sync(ctx context.Context) error {
+	matchingSecrets, err := o.search.search(ctx, o.vault)
+	if err != nil {
+		return err
+	}
+
+	if len(matchingSecrets) == 0 {
+		return vaulterrors.ErrSearchNoMatch
+	}
+
+	ids := make([]int, len(matchingSecrets))
+	for i, s := range matchingSecrets {
+		ids[i] = s.id
+	}
+
+	attrs, err := o.vault.SecretsAttributes(ctx, ids...)
+	if err != nil {
+		return err
+	}
+
+	key := cloudAttrPrefix + o.provider.Name()
+
+	var synced, unchanged, unmapped int
+
+	for _, s := range matchingSecrets {
+		ref := attrs[s.id][key]
+		if len(ref) == 0 {
+			o.Debugf("skipping %q: no %s attribute\n", s.name, key)
+
+			unmapped++
+
+			continue
+		}
+
+		changed, err := o.syncSecret(ctx, s, ref)
+		if err != nil {
+			return fmt.Errorf("%s %q: %w", key, ref, err)
+		}
+
+		if changed {
+			synced++
+		} else {
+			unchanged++
+		}
+	}
+
+	verb := "pushed"
+	if o.pull {
+		verb = "pulled"
+	}
+
+	if o.dryRun {
+		verb = "would have " + verb
+	}
+
+	o.Infof("%s %d, unchanged %d, skipped %d without a %s attribute\n", verb, synced, unchanged, unmapped, key)
+
+	return nil
+}
+
+// syncSecret pushes or pulls a single secret, and reports whether its values differed.
+func (o *CloudSyncOptions) syncSecret(ctx context.Context, s secretWithLabels, ref string) (changed bool, _ error) {
+	err := o.vault.WithSecret(ctx, s.id, func(local []byte) (err error) {
+		changed, err = o.syncValue(ctx, s, ref, local)
+		return err
+	})
+
+	return changed, err
+}
+
+// syncValue pushes or pulls a single secret given its local value.
+func (o *CloudSyncOptions) syncValue(ctx context.Context, s secretWithLabels, ref string, local []byte) (bool, error) {
+	remote, err := o.provider.Get(ctx, ref)
+	if err != nil && (o.pull || !errors.Is(err, cloudsecrets.ErrNotFound)) {
+		return false, err
+	}
+	defer securebytes.Wipe(remote)
+
+	if err == nil && bytes.Equal(local, remote) {
+		return false, nil
+	}
+
+	if o.dryRun {
+		return true, nil
+	}
+
+	if !o.pull {
+		if err := o.provider.Put(ctx, ref, local); err != nil {
+			return false, err
+		}
+
+		o.Debugf("pushed %q to %s\n", s.name, ref)
+
+		return true, nil
+	}
+
+	if len(remote) == 0 {
+		return false, vaulterrors.ErrEmptySecret
+	}
+
+	if _, err := o.vault.UpdateSecret(ctx, s.id, remote); err != nil {
+		return false, err
+	}
+
+	o.persistRequired = true
+
+	o.Debugf("pulled %q from %s\n", s.name, ref)
+
+	return true, nil
+}
+
+// NewCmdPush creates the push cobra command.
+func NewCmdPush(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "push",
+		Short: i18n.T("Push secrets to external secret stores (subcommands available)"),
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newCmdCloudSync(defaults, false))
+
+	return cmd
+}
+
+// NewCmdPull creates the pull cobra command.
+func NewCmdPull(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pull",
+		Short: i18n.T("Pull secrets from external secret stores (subcommands available)"),
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newCmdCloudSync(defaults, true))
+
+	return cmd
+}
+
+func newCmdCloudSync(defaults *DefaultVltOptions, pull bool) *cobra.Command {
+	o := NewCloudSyncOptions(defaults.StdioOptions, defaults.vaultOptions, pull)
+
+	short := i18n.T("Push secrets to a cloud secret manager")
+	long := `Push the matching secrets to a cloud secret manager, creating missing
+cloud secrets, and adding a new version to those whose value differs.`
+	example := `  # Map a secret to an AWS secret, and push the secrets labeled prod
+  vlt update --name db-password --set-attr cloud.aws=prod/db-password
+  vlt push cloud aws --label prod`
+
+	if pull {
+		short = i18n.T("Pull secrets from a cloud secret manager")
+		long = `Pull the latest values of the matching secrets from a cloud secret manager,
+updating the secrets whose value differs.`
+		example = `  # Update the secrets labeled prod from GCP
+  vlt pull cloud gcp --label prod`
+	}
+
+	cmd := &cobra.Command{
+		Use:   "cloud provider [glob]",
+		Short: short,
+		Long: long + `
+
+Secrets are mapped to their cloud copy by a 'cloud.<provider>' attribute,
+set using 'vlt update --set-attr', matching secrets without one are skipped.
+
+Providers, and the format of their mappings:
+  aws     AWS Secrets Manager, the secret name or ARN
+  gcp     GCP Secret Manager, the secret name, or projects/<project>/secrets/<name>
+  azure   Azure Key Vault, <vault>/<name>, text values only
+
+The aws, gcloud and az commands are used, with their configured credentials,
+values are passed to them over stdin.
+
+Supports UNIX glob patterns, e.g., "*", "?", "[a-z]".`,
+		Example: example,
+		Args:    cobra.RangeArgs(1, 2),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return cloudsecrets.Providers(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+
+	cmd.Flags().IntSliceVarP(&o.search.IDs, "id", "", nil, FilterByID.Help())
+	cmd.Flags().StringVarP(&o.search.Name, "name", "", "", FilterByName.Help())
+	cmd.Flags().StringSliceVarP(&o.search.Labels, "label", "", nil, FilterByLabels.Help())
+	cmd.Flags().BoolVarP(&o.dryRun, "dry-run", "", false, "report the secrets that differ without changing them")
+
+	return cmd
+}
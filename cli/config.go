@@ -5,13 +5,17 @@ import (
 	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"maps"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/style"
 
 	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/cobra"
@@ -29,8 +33,9 @@ type ConfigOptions struct {
 
 // Flags holds cli overrides for configuration.
 type Flags struct {
-	configPath string
-	vaultPath  string
+	configPath   string
+	vaultPath    string
+	identityFile string
 }
 
 // ResolvedConfig contains the final merged configuration.
@@ -41,10 +46,37 @@ type ResolvedConfig struct {
 	SessionDuration     Duration `json:"session_duration,omitempty"`
 	VaultPath           string   `json:"vault_path,omitempty"`
 	MaxHistorySnapshots int      `json:"max_history_snapshots"`
+	AutoVacuumThreshold int      `json:"auto_vacuum_threshold"`
+	MinPasswordLength   int      `json:"min_password_length"`
+	MinPasswordClasses  int      `json:"min_password_classes"`
+	MinPasswordBits     int      `json:"min_password_bits"`
+	CommandTimeout      Duration `json:"command_timeout,omitempty"`
+	AutostartDaemon     bool     `json:"autostart_daemon,omitempty"`
+	TrackUsage          bool     `json:"track_usage,omitempty"`
+	UniqueNames         bool     `json:"unique_names,omitempty"`
+	IdentityFile        string   `json:"identity_file,omitempty"`
+	PasswordCommand     []string `json:"password_command,omitempty"`
+	AllowCoreDumps      bool     `json:"allow_core_dumps,omitempty"`
+	VaultPolicy         string   `json:"vault_policy,omitempty"`
+	ConfirmEachUse      bool     `json:"confirm_each_use,omitempty"`
 	CopyCmd             []string `json:"copy_cmd,omitempty"`
 	PasteCmd            []string `json:"paste_cmd,omitempty"`
 	PostLoginCmd        []string `json:"post_login_cmd,omitempty"`
 	PostWriteCmd        []string `json:"post_write_cmd,omitempty"`
+	PostLockCmd         []string `json:"post_lock_cmd,omitempty"`
+	HookScript          string   `json:"hook_script,omitempty"`
+	HookScriptAllowExec bool     `json:"hook_script_allow_exec,omitempty"`
+	Theme               string   `json:"theme,omitempty"`
+	NoColor             bool     `json:"no_color,omitempty"`
+	Discreet            bool     `json:"discreet,omitempty"`
+	BackupDir           string   `json:"backup_dir,omitempty"`
+	BackupKeep          int      `json:"backup_keep"`
+	DaemonSocket        string   `json:"daemon_socket,omitempty"`
+
+	Templates map[string]TemplateConfig `json:"templates,omitempty"`
+	Lint      map[string]LintRuleConfig `json:"lint,omitempty"`
+	Aliases   map[string]string         `json:"aliases,omitempty"`
+	Defaults  map[string]any            `json:"defaults,omitempty"`
 
 	enableSession bool
 }
@@ -85,13 +117,62 @@ func (o *ConfigOptions) resolve() error {
 	o.resolved.PasteCmd = o.fileConfig.Clipboard.PasteCmd
 	o.resolved.PostLoginCmd = o.fileConfig.Hooks.PostLoginCmd
 	o.resolved.PostWriteCmd = o.fileConfig.Hooks.PostWriteCmd
+	o.resolved.PostLockCmd = o.fileConfig.Hooks.PostLockCmd
+	o.resolved.HookScript = o.fileConfig.Hooks.Script
+	o.resolved.HookScriptAllowExec = o.fileConfig.Hooks.ScriptAllowExec
 	o.resolved.VaultPath = cmp.Or(o.cliFlags.vaultPath, o.fileConfig.Vault.Path)
+	o.resolved.AutostartDaemon = o.fileConfig.Vault.AutostartDaemon
+	o.resolved.TrackUsage = o.fileConfig.Vault.TrackUsage
+	o.resolved.UniqueNames = o.fileConfig.Vault.UniqueNames
+	o.resolved.AllowCoreDumps = o.fileConfig.Vault.AllowCoreDumps
+	o.resolved.IdentityFile = cmp.Or(o.cliFlags.identityFile, o.fileConfig.Vault.IdentityFile)
+	o.resolved.PasswordCommand = PasswordCommand(o.fileConfig)
+	o.resolved.Theme = cmp.Or(o.fileConfig.UI.Theme, style.DefaultTheme)
+	o.resolved.NoColor = o.fileConfig.UI.NoColor
+	o.resolved.Discreet = o.fileConfig.UI.Discreet
+	o.resolved.Templates = o.fileConfig.Templates
+	o.resolved.Lint = o.fileConfig.Lint
+	o.resolved.Aliases = o.fileConfig.Aliases
+	o.resolved.Defaults = o.fileConfig.Defaults
+	o.resolved.BackupDir = o.fileConfig.Backup.Dir
+
+	socket, err := DaemonSocket(o.fileConfig)
+	if err != nil {
+		return err
+	}
+
+	o.resolved.DaemonSocket = socket
+
+	o.resolved.BackupKeep = defaultBackupKeep
+	if o.fileConfig.Backup.Keep != nil {
+		o.resolved.BackupKeep = *o.fileConfig.Backup.Keep
+	}
 
 	o.resolved.MaxHistorySnapshots = defaultMaxHistorySnapshots
 	if o.fileConfig.Vault.MaxHistorySnapshots != nil {
 		o.resolved.MaxHistorySnapshots = *o.fileConfig.Vault.MaxHistorySnapshots
 	}
 
+	o.resolved.AutoVacuumThreshold = defaultAutoVacuumThreshold
+	if o.fileConfig.Vault.AutoVacuumThreshold != nil {
+		o.resolved.AutoVacuumThreshold = *o.fileConfig.Vault.AutoVacuumThreshold
+	}
+
+	o.resolved.MinPasswordLength = defaultMinPasswordLength
+	if o.fileConfig.Vault.MinPasswordLength != nil {
+		o.resolved.MinPasswordLength = *o.fileConfig.Vault.MinPasswordLength
+	}
+
+	o.resolved.MinPasswordClasses = defaultMinPasswordClasses
+	if o.fileConfig.Vault.MinPasswordClasses != nil {
+		o.resolved.MinPasswordClasses = *o.fileConfig.Vault.MinPasswordClasses
+	}
+
+	o.resolved.MinPasswordBits = defaultMinPasswordBits
+	if o.fileConfig.Vault.MinPasswordBits != nil {
+		o.resolved.MinPasswordBits = *o.fileConfig.Vault.MinPasswordBits
+	}
+
 	if len(o.resolved.VaultPath) == 0 {
 		vaultPath, err := defaultVaultPath()
 		if err != nil {
@@ -101,7 +182,14 @@ func (o *ConfigOptions) resolve() error {
 		o.resolved.VaultPath = vaultPath
 	}
 
-	sessionDuration := cmp.Or(o.fileConfig.Vault.SessionDuration, defaultSessionDuration)
+	policy, err := o.vaultPolicy()
+	if err != nil {
+		return err
+	}
+
+	o.resolved.ConfirmEachUse = policy.ConfirmEachUse
+
+	sessionDuration := cmp.Or(policy.SessionDuration, o.fileConfig.Vault.SessionDuration, defaultSessionDuration)
 
 	t, err := time.ParseDuration(sessionDuration)
 	if err != nil {
@@ -110,6 +198,19 @@ func (o *ConfigOptions) resolve() error {
 
 	o.resolved.SessionDuration = Duration(t)
 
+	if len(o.fileConfig.Vault.CommandTimeout) > 0 {
+		t, err := time.ParseDuration(o.fileConfig.Vault.CommandTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid command timeout: %w", err)
+		}
+
+		if t < 0 {
+			return &ConfigError{Opt: "vault.command_timeout", Err: errors.New("must not be negative")}
+		}
+
+		o.resolved.CommandTimeout = Duration(t)
+	}
+
 	if o.resolved.SessionDuration > 0 {
 		o.resolved.enableSession = true
 	}
@@ -117,13 +218,49 @@ func (o *ConfigOptions) resolve() error {
 	return nil
 }
 
+// vaultPolicy returns the per-vault policy matching the resolved vault path,
+// or a zero policy if none matches.
+func (o *ConfigOptions) vaultPolicy() (VaultPolicyConfig, error) {
+	vaultPath, err := filepath.Abs(o.resolved.VaultPath)
+	if err != nil {
+		return VaultPolicyConfig{}, err
+	}
+
+	names := slices.Sorted(maps.Keys(o.fileConfig.Vaults))
+	seen := make(map[string]string, len(names))
+
+	var match VaultPolicyConfig
+
+	for _, name := range names {
+		p := o.fileConfig.Vaults[name]
+
+		abs, err := filepath.Abs(p.Path)
+		if err != nil {
+			return VaultPolicyConfig{}, err
+		}
+
+		if other, ok := seen[abs]; ok {
+			return VaultPolicyConfig{}, &ConfigError{Opt: "vaults." + name, Err: fmt.Errorf("path already used by 'vaults.%s'", other)}
+		}
+
+		seen[abs] = name
+
+		if abs == vaultPath {
+			o.resolved.VaultPolicy = name
+			match = p
+		}
+	}
+
+	return match, nil
+}
+
 func defaultVaultPath() (string, error) {
-	home, err := os.UserHomeDir()
+	p, err := vaultPaths()
 	if err != nil {
 		return "", err
 	}
 
-	return filepath.Join(home, defaultDatabaseFilename), nil
+	return p.resolve(), nil
 }
 func (*ConfigOptions) Validate() error { return nil }
 
@@ -136,10 +273,10 @@ func NewCmdConfig(defaults *DefaultVltOptions) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "config",
-		Short: "Resolve and inspect the active vlt configuration (subcommands available)",
+		Short: i18n.T("Resolve and inspect the active vlt configuration (subcommands available)"),
 		Long: fmt.Sprintf(`Resolve and display the active vlt configuration.
 
-If --file is not provided, the default config path (~/%s) is used.`, defaultConfigName),
+If --file is not provided, the default config path (%s) is used.`, defaultConfigPathHelp),
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			if err := clierror.Check(genericclioptions.RejectDisallowedFlags(cmd, hiddenFlags...)); err != nil {
 				return err
@@ -149,6 +286,8 @@ If --file is not provided, the default config path (~/%s) is used.`, defaultConf
 				return err
 			}
 
+			o.reportLegacyPaths()
+
 			if len(o.fileConfig.path) == 0 {
 				o.Infof("no config file found; using default values.\n")
 				return nil
@@ -171,16 +310,29 @@ If --file is not provided, the default config path (~/%s) is used.`, defaultConf
 	}
 
 	cmd.PersistentFlags().StringVarP(&o.cliFlags.configPath, "file", "f", "",
-		fmt.Sprintf("path to the configuration file (default: ~/%s)", defaultConfigName))
+		"path to the configuration file (default: "+defaultConfigPathHelp+")")
 
 	cmd.AddCommand(newGenerateConfigCmd(defaults))
 	cmd.AddCommand(newValidateConfigCmd(defaults))
+	cmd.AddCommand(newMigrateConfigCmd(defaults))
 
 	genericclioptions.MarkFlagsHidden(cmd, hiddenFlags...)
 
 	return cmd
 }
 
+// reportLegacyPaths notes the default config and vault files
+// still used from their legacy location in the home directory.
+func (o *ConfigOptions) reportLegacyPaths() {
+	if p, err := configPaths(); err == nil && len(o.fileConfig.path) > 0 && o.fileConfig.path == p.legacy {
+		o.Infof("using the legacy config path %s, run 'vlt config migrate' to move it to %s\n", p.legacy, p.xdg)
+	}
+
+	if p, err := vaultPaths(); err == nil && o.resolved.VaultPath == p.legacy {
+		o.Infof("using the legacy vault path %s, run 'vlt config migrate' to move it to %s\n", p.legacy, p.xdg)
+	}
+}
+
 // stringifyPretty returns the pretty-printed JSON representation of v.
 // If marshalling fails, it returns the error message instead.
 func stringifyPretty(v any) string {
@@ -218,6 +370,11 @@ func (*generateConfigOptions) Validate() error { return nil }
 func (o *generateConfigOptions) Run(context.Context, ...string) error {
 	c := newFileConfig()
 	c.Vault.MaxHistorySnapshots = ptr(defaultMaxHistorySnapshots)
+	c.Vault.AutoVacuumThreshold = ptr(defaultAutoVacuumThreshold)
+	c.Vault.MinPasswordLength = ptr(defaultMinPasswordLength)
+	c.Vault.MinPasswordClasses = ptr(defaultMinPasswordClasses)
+	c.Vault.MinPasswordBits = ptr(defaultMinPasswordBits)
+	c.Backup.Keep = ptr(defaultBackupKeep)
 
 	out, err := toml.Marshal(c)
 	if err := clierror.Check(err); err != nil {
@@ -236,7 +393,7 @@ func newGenerateConfigCmd(defaults *DefaultVltOptions) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "generate",
-		Short: "Print a default config file",
+		Short: i18n.T("Print a default config file"),
 		Long:  `Outputs the default configuration in TOML format to stdout.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return cmp.Or(
@@ -293,10 +450,10 @@ func newValidateConfigCmd(defaults *DefaultVltOptions) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "validate",
-		Short: "Check config validity",
+		Short: i18n.T("Check config validity"),
 		Long: fmt.Sprintf(`Loads the configuration file and checks for common errors.
 
-If --file is not provided, the default config path (~/%s) is used.`, defaultConfigName),
+If --file is not provided, the default config path (%s) is used.`, defaultConfigPathHelp),
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			o.configPath, _ = cmd.InheritedFlags().GetString("file")
 
@@ -311,3 +468,99 @@ If --file is not provided, the default config path (~/%s) is used.`, defaultConf
 
 	return cmd
 }
+
+type migrateConfigOptions struct {
+	*genericclioptions.StdioOptions
+}
+
+var _ genericclioptions.CmdOptions = &migrateConfigOptions{}
+
+// newMigrateConfigOptions initializes the options struct.
+func newMigrateConfigOptions(stdio *genericclioptions.StdioOptions) *migrateConfigOptions {
+	return &migrateConfigOptions{
+		StdioOptions: stdio,
+	}
+}
+
+func (*migrateConfigOptions) Complete() error { return nil }
+
+func (*migrateConfigOptions) Validate() error { return nil }
+
+func (o *migrateConfigOptions) Run(context.Context, ...string) error {
+	cp, err := configPaths()
+	if err != nil {
+		return err
+	}
+
+	vp, err := vaultPaths()
+	if err != nil {
+		return err
+	}
+
+	moved, err := cp.migrate()
+	if err != nil {
+		return fmt.Errorf("migrate config: %w", err)
+	}
+
+	if moved {
+		o.Infof("moved config %s to %s\n", cp.legacy, cp.xdg)
+	}
+
+	// a vault set explicitly by the config file is not a default path, and stays in place.
+	c, err := LoadFileConfig("")
+	if err != nil {
+		return err
+	}
+
+	if len(c.Vault.Path) > 0 {
+		if abs, err := filepath.Abs(c.Vault.Path); err == nil && abs == vp.legacy {
+			o.Infof("vault %s is set by 'path' in the config file, not moved\n", vp.legacy)
+			return nil
+		}
+	}
+
+	vaultMoved, err := vp.migrate()
+	if err != nil {
+		return fmt.Errorf("migrate vault: %w", err)
+	}
+
+	if vaultMoved {
+		o.Infof("moved vault %s to %s\nActive sessions of the old path are no longer used, log in again.\n", vp.legacy, vp.xdg)
+	}
+
+	if !moved && !vaultMoved {
+		o.Infof("nothing to migrate\n")
+	}
+
+	return nil
+}
+
+// newMigrateConfigCmd creates the 'migrate' subcommand for moving legacy files to the XDG base directories.
+func newMigrateConfigCmd(defaults *DefaultVltOptions) *cobra.Command {
+	hiddenFlags := []string{"config", "file", "no-hooks", "no-login-prompt"}
+	o := newMigrateConfigOptions(defaults.StdioOptions)
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: i18n.T("Move the legacy config and vault files to the XDG base directories"),
+		Long: fmt.Sprintf(`Move the legacy default files in the home directory to the XDG base directories:
+
+  ~/%s -> %s
+  ~/%s -> %s
+
+Until migrated, the legacy files are used if the new ones do not exist.
+Files that exist in both locations are left untouched, and a vault set
+explicitly by 'path' in the config file is not moved.`, legacyConfigName, defaultConfigPathHelp, legacyDatabaseFilename, defaultVaultPathHelp),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmp.Or(
+				clierror.Check(genericclioptions.RejectDisallowedFlags(cmd, hiddenFlags...)),
+				clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o)),
+			)
+		},
+	}
+
+	genericclioptions.MarkFlagsHidden(cmd, hiddenFlags...)
+
+	return cmd
+}
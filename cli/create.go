@@ -6,40 +6,56 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
 	"github.com/ladzaretti/vlt-cli/input"
+	"github.com/ladzaretti/vlt-cli/redact"
+	"github.com/ladzaretti/vlt-cli/securebytes"
 	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
 	"github.com/ladzaretti/vlt-cli/vaulterrors"
 
 	"github.com/spf13/cobra"
 )
 
-const (
-	masterPasswordMinLen = 8
-	vaultPerm            = 0o600
-)
+const vaultPerm = 0o600
 
 // CreateOptions have the data required to perform the create operation.
 type CreateOptions struct {
 	*genericclioptions.StdioOptions
 
 	vaultOptions *VaultOptions
+	config       *ResolvedConfig
+	weakOK       bool   // weakOK accepts a master password below the strength threshold with a warning.
+	title        string // title is the human readable title of the new vault, see [vault.Identity].
 }
 
 var _ genericclioptions.CmdOptions = &CreateOptions{}
 
 // NewCreateOptions initializes the options struct.
-func NewCreateOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *CreateOptions {
+func NewCreateOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions, config *ResolvedConfig) *CreateOptions {
 	return &CreateOptions{
 		StdioOptions: stdio,
 		vaultOptions: vaultOptions,
+		config:       config,
 	}
 }
 
 func (o *CreateOptions) Complete() error {
-	return o.vaultOptions.Complete()
+	if err := o.vaultOptions.Complete(); err != nil {
+		return err
+	}
+
+	// the vlt XDG data directory is created along with the default vault.
+	if p, err := vaultPaths(); err == nil && o.vaultOptions.path == p.xdg {
+		return os.MkdirAll(filepath.Dir(p.xdg), appDirPerm)
+	}
+
+	return nil
 }
 
 func (o *CreateOptions) Validate() error {
@@ -51,53 +67,131 @@ func (o *CreateOptions) Validate() error {
 		return vaulterrors.ErrNonInteractiveUnsupported
 	}
 
-	return nil
+	if err := vault.ValidateTitle(o.title); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	return o.vaultOptions.verifyPath(o.StdioOptions)
 }
 
 func (o *CreateOptions) Run(ctx context.Context, _ ...string) error {
-	password, err := input.PromptNewPassword(o.Out, int(o.In.Fd()), masterPasswordMinLen)
+	password, err := input.PromptNewPassword(ctx, o.Prompter(), o.vaultOptions.newPasswordPolicy(o.weakOK))
 	if err != nil {
 		return fmt.Errorf("create: %w", err)
 	}
-	defer clear(password)
+	defer securebytes.Wipe(password)
+
+	redact.Add(password)
 
 	vlt, err := vault.New(ctx, o.vaultOptions.path, password,
 		vault.WithMaxHistorySnapshots(o.vaultOptions.maxHistorySnapshots),
+		vault.WithUniqueNames(o.vaultOptions.uniqueNames),
+		vault.WithIdentity(vault.Identity{Title: o.title}),
+		redactSecrets,
 	)
 	if err != nil {
 		return fmt.Errorf("create: %w", err)
 	}
 
-	if _, err := vlt.Seal(ctx); err != nil {
+	nonce, err := vlt.Seal(ctx)
+	if err != nil {
 		return fmt.Errorf("create: %w", err)
 	}
 
+	key := vlt.Key()
+	defer securebytes.Wipe(key)
+
 	if err := vlt.Close(); err != nil {
 		return fmt.Errorf("create: %w", err)
 	}
 
-	if err := os.Chmod(o.vaultOptions.path, vaultPerm); err != nil {
+	if err := restrictVaultFile(o.vaultOptions.path); err != nil {
 		return fmt.Errorf("create: %w", err)
 	}
 
 	o.Infof("new vault successfully created at %q\n", o.vaultOptions.path)
 
+	if err := o.login(ctx, key, nonce); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	return nil
+}
+
+// login starts a session of the new vault using its key, if sessions are enabled,
+// so that it is used without entering the password again, see [LoginOptions.Run].
+//
+// The vault is created regardless, a daemon that is unavailable or fails
+// to start the session is reported only.
+func (o *CreateOptions) login(ctx context.Context, key []byte, nonce []byte) error {
+	if !o.config.enableSession {
+		return nil
+	}
+
+	sessionClient, err := connectDaemon(ctx, o.StdioOptions, o.config.AutostartDaemon)
+	if err != nil {
+		o.Debugf("%v\n", err)
+		return nil
+	}
+	defer func() { _ = sessionClient.Close() }() //nolint:wsl_v5
+
+	opts := o.vaultOptions.sessionOptions(ctx, o.StdioOptions, vaultdaemon.WithConfirmEachUse(o.config.ConfirmEachUse))
+	if err := sessionClient.Login(ctx, o.vaultOptions.path, key, nonce, time.Duration(o.config.SessionDuration), opts...); err != nil {
+		o.Errorf("session login failed, run 'vlt login' to start one: %v\n", err)
+		return nil
+	}
+
+	o.Infof("login successful\n")
+
+	if err := o.vaultOptions.postLoginHook(ctx, o.StdioOptions); err != nil {
+		return fmt.Errorf("post-login hook: %w", err)
+	}
+
 	return nil
 }
 
 // NewCmdCreate creates the create cobra command.
 func NewCmdCreate(defaults *DefaultVltOptions) *cobra.Command {
-	o := NewCreateOptions(defaults.StdioOptions, defaults.vaultOptions)
+	o := NewCreateOptions(defaults.StdioOptions, defaults.vaultOptions, defaults.configOptions.resolved)
 
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "create",
 		Aliases: []string{"new"},
-		Short:   "Initialize a new vault",
+		Short:   i18n.T("Initialize a new vault"),
 		Long: fmt.Sprintf(`Create a new vault at the specified path. 
 
-If no --file path is provided, uses the default path (~/%s).`, defaultDatabaseFilename),
+If no --file path is provided, uses the default path (%s).
+
+The estimated strength of the master password is shown while it is typed.
+Passwords shorter than 'min_password_length' or using fewer character classes
+than 'min_password_classes' of the [vault] config section are refused.
+Passwords weaker than 'min_password_bits' are refused too, unless --weak-ok is given.
+
+A retyped password differing by a single character is taken for a typo,
+and the password is prompted for again.
+
+The vault is given a random id, and the title set by --title if given,
+naming the vault in password prompts, see 'vlt title'.
+
+If sessions are enabled and vltd is running, the new vault is logged in right away
+using the key derived from the new password, as with 'vlt login'.`, defaultVaultPathHelp),
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
 		},
 	}
+
+	cmd.Flags().BoolVarP(&o.weakOK, "weak-ok", "", false, "accept a weak master password with a warning instead of refusing it")
+	cmd.Flags().StringVarP(&o.title, "title", "", "", "human readable title of the vault, shown in password prompts")
+
+	return cmd
+}
+
+// newPasswordPolicy returns the policy of a new master password.
+func (o *VaultOptions) newPasswordPolicy(weakOK bool) input.NewPasswordPolicy {
+	return input.NewPasswordPolicy{
+		MinLength:  o.minPasswordLength,
+		MinClasses: o.minPasswordClasses,
+		MinBits:    o.minPasswordBits,
+		WeakOK:     weakOK,
+	}
 }
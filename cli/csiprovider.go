@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/csiprovider"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultCSILabel is the label of the secrets served to the cluster by default.
+const defaultCSILabel = "k8s"
+
+type CSIProviderError struct {
+	Err error
+}
+
+func (e *CSIProviderError) Error() string { return "csi provider: " + e.Err.Error() }
+
+func (e *CSIProviderError) Unwrap() error { return e.Err }
+
+// CSIProviderOptions holds data required to run the command.
+type CSIProviderOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	socket string
+	labels []string // labels of the served secrets.
+
+	mu sync.Mutex // mu serializes vault access of concurrent mounts.
+}
+
+var _ genericclioptions.CmdOptions = &CSIProviderOptions{}
+
+// NewCSIProviderOptions initializes the options struct.
+func NewCSIProviderOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *CSIProviderOptions {
+	return &CSIProviderOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (o *CSIProviderOptions) Complete() error {
+	if len(o.socket) == 0 {
+		o.socket = csiprovider.SocketPath()
+	}
+
+	return nil
+}
+
+func (o *CSIProviderOptions) Validate() error {
+	if len(o.labels) == 0 {
+		return &CSIProviderError{fmt.Errorf("no labels, secrets are served by label, e.g., --label %s", defaultCSILabel)}
+	}
+
+	return nil
+}
+
+func (o *CSIProviderOptions) Run(ctx context.Context, _ ...string) error {
+	if err := o.serve(ctx); err != nil {
+		return &CSIProviderError{err}
+	}
+
+	return nil
+}
+
+func (o *CSIProviderOptions) serve(ctx context.Context) error {
+	logger := o.Logger()
+
+	s, err := csiprovider.NewServer(o.secret, Version, logger)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(o.socket), 0o700); err != nil {
+		return err
+	}
+
+	// the driver runs as root on the node.
+	lis, err := vaultdaemon.ListenUIDs(ctx, o.socket, logger, os.Getuid(), 0)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	o.Infof("serving secrets labeled %v on %s\n", o.labels, o.socket)
+
+	return csiprovider.Serve(ctx, lis, s)
+}
+
+// secret returns the value of the secret with the given name,
+// if it is labeled by any of the served labels.
+func (o *CSIProviderOptions) secret(ctx context.Context, name string) ([]byte, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	secrets, err := o.vault.FilterSecrets(ctx, "", name, o.labels)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, 1)
+
+	for id, s := range secrets {
+		if s.Name == name { // the name filter is a glob pattern.
+			ids = append(ids, id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return nil, csiprovider.ErrNotFound
+	case 1:
+	default:
+		return nil, fmt.Errorf("%d secrets named %q", len(ids), name)
+	}
+
+	// the copy written to the driver is wiped by the provider once sent.
+	var value []byte
+
+	err = o.vault.WithSecret(ctx, ids[0], func(v []byte) error {
+		value = bytes.Clone(v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o.recordAccess(ctx, o.StdioOptions, ids[0])
+
+	return value, nil
+}
+
+// NewCmdCSIProvider creates the csi-provider cobra command.
+func NewCmdCSIProvider(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewCSIProviderOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "csi-provider",
+		Short: i18n.T("Serve secrets to a local Kubernetes cluster as a Secrets Store CSI driver provider"),
+		Long: `Serve secrets to a local Kubernetes development cluster, e.g., kind or minikube,
+as a provider of the Secrets Store CSI driver, until interrupted.
+
+Pods mount secrets as files using a SecretProviderClass with the 'vlt' provider,
+listing the secrets by name in its 'objects' parameter, a JSON array of
+{"name": "<secret name>", "path": "<file path>"} objects, the path defaults to the name.
+
+Only secrets labeled by --label are served, by default 'k8s'.
+Secrets are read on mount, from the vault unlocked on startup,
+changes made by other vlt commands are served after a restart.
+
+The driver connects to <providers dir>/vlt.sock on the node, by default
+/etc/kubernetes/secrets-store-csi-providers/vlt.sock. Mount the directory of
+--socket there, e.g., using an extraMounts entry of the kind node.
+The socket accepts connections of the current user and of root.
+
+The socket defaults to /run/user/<uid>/vlt-csi/vlt.sock.`,
+		Example: `  # Serve the secrets labeled k8s
+  vlt csi-provider
+
+  # Mount the socket directory into a kind node
+  #   nodes:
+  #   - role: control-plane
+  #     extraMounts:
+  #     - hostPath: /run/user/1000/vlt-csi
+  #       containerPath: /etc/kubernetes/secrets-store-csi-providers
+
+  # Mount secrets into a pod, using a SecretProviderClass
+  #   apiVersion: secrets-store.csi.x-k8s.io/v1
+  #   kind: SecretProviderClass
+  #   metadata:
+  #     name: app
+  #   spec:
+  #     provider: vlt
+  #     parameters:
+  #       objects: '[{"name": "db/password", "path": "db-password"}]'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVar(&o.socket, "socket", "", "path of the unix domain socket to listen on")
+	cmd.Flags().StringSliceVarP(&o.labels, "label", "l", []string{defaultCSILabel}, "label of the secrets to serve, may be repeated")
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+)
+
+// connectDaemon connects to the vltd daemon.
+//
+// If the daemon is not running and autostart is enabled,
+// it is spawned in the background before connecting again.
+func connectDaemon(ctx context.Context, io *genericclioptions.StdioOptions, autostart bool) (*vaultdaemon.SessionClient, error) {
+	c, err := vaultdaemon.NewSessionClient()
+	if err == nil || !autostart || !errors.Is(err, vaultdaemon.ErrSocketUnavailable) {
+		return c, err
+	}
+
+	io.Debugf("daemon unavailable: %v: starting vltd\n", err)
+
+	if err := vaultdaemon.Spawn(ctx); err != nil {
+		return nil, errors.Join(vaultdaemon.ErrSocketUnavailable, err)
+	}
+
+	return vaultdaemon.NewSessionClient()
+}
+
+// DaemonSocket returns the vltd socket path set by $VLT_SESSION_SOCKET, or by the
+// 'socket' setting of the [daemon] config section, empty for the default path.
+//
+// vlt and vltd resolve the path alike, so that both use the same socket.
+func DaemonSocket(c *FileConfig) (string, error) {
+	p := os.Getenv(envSessionSocketKey)
+	if len(p) == 0 {
+		return c.Daemon.Socket, nil
+	}
+
+	if err := validateSocketPath(p); err != nil {
+		return "", &ConfigError{Opt: envSessionSocketKey, Err: err}
+	}
+
+	return p, nil
+}
+
+// validateSocketPath verifies p is an absolute path, or names an abstract socket.
+func validateSocketPath(p string) error {
+	if !filepath.IsAbs(p) && !strings.HasPrefix(p, "@") {
+		return fmt.Errorf("%q: must be an absolute path, or start with '@' for an abstract socket", p)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// exclusiveDefaultsAnnotation groups flags of which at most one may be set,
+// e.g., the output flags of 'vlt show'. The config file default of a flag
+// is not applied if another flag of its group is given on the command line.
+const exclusiveDefaultsAnnotation = "vlt_exclusive_defaults"
+
+// defaultsKeyRE matches valid keys of the [defaults] section,
+// command names and flag names, with '_' or '-' separated words.
+var defaultsKeyRE = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// markExclusiveDefaults marks the named flags of cmd as a group of exclusive flags,
+// see [exclusiveDefaultsAnnotation].
+func markExclusiveDefaults(cmd *cobra.Command, names ...string) {
+	group := strings.Join(names, " ")
+
+	for _, name := range names {
+		f := cmd.Flags().Lookup(name)
+		if f == nil {
+			panic(fmt.Sprintf("markExclusiveDefaults: flag %q not defined", name))
+		}
+
+		if f.Annotations == nil {
+			f.Annotations = map[string][]string{}
+		}
+
+		f.Annotations[exclusiveDefaultsAnnotation] = append(f.Annotations[exclusiveDefaultsAnnotation], group)
+	}
+}
+
+// validateDefaults verifies that the keys of a [defaults] table are valid names,
+// and its values are flag values or the tables of sub-commands.
+func validateDefaults(defaults map[string]any, opt string) error {
+	for key, value := range defaults {
+		keyOpt := opt + "." + key
+
+		if !defaultsKeyRE.MatchString(key) {
+			return &ConfigError{Opt: keyOpt, Err: errors.New("invalid name: expected lower case letters, digits, '_' or '-'")}
+		}
+
+		if table, ok := value.(map[string]any); ok {
+			if err := validateDefaults(table, keyOpt); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if _, err := flagValues(value); err != nil {
+			return &ConfigError{Opt: keyOpt, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// applyFlagDefaults sets the flags of cmd not given on the command line to their
+// defaults from the [defaults] table of the command, e.g., [defaults.show]
+// or [defaults.wifi.show] for sub-commands. Flag names are written with '_'
+// or '-', e.g., copy_clipboard = true sets --copy-clipboard.
+//
+// Only the flags of the command itself can be set, global flags are
+// configured by their own config options.
+func applyFlagDefaults(cmd *cobra.Command, defaults map[string]any) error {
+	path := strings.Fields(cmd.CommandPath())[1:]
+
+	table, ok := commandDefaults(defaults, path)
+	if !ok {
+		return nil
+	}
+
+	local := cmd.LocalNonPersistentFlags()
+
+	// flags set by earlier defaults do not count as given.
+	var given []*pflag.Flag
+
+	local.VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			given = append(given, f)
+		}
+	})
+
+	for _, key := range slices.Sorted(maps.Keys(table)) {
+		value := table[key]
+		if _, ok := value.(map[string]any); ok {
+			continue // the defaults of a sub-command.
+		}
+
+		opt := strings.Join(slices.Concat([]string{"defaults"}, path, []string{key}), ".")
+
+		f := local.Lookup(strings.ReplaceAll(key, "_", "-"))
+		if f == nil {
+			return &ConfigError{Opt: opt, Err: fmt.Errorf("unknown flag of 'vlt %s'", strings.Join(path, " "))}
+		}
+
+		if f.Changed || exclusiveFlagGiven(f, given) {
+			continue
+		}
+
+		values, err := flagValues(value)
+		if err != nil {
+			return &ConfigError{Opt: opt, Err: err}
+		}
+
+		for _, v := range values {
+			if err := cmd.Flags().Set(f.Name, v); err != nil {
+				return &ConfigError{Opt: opt, Err: err}
+			}
+		}
+	}
+
+	return nil
+}
+
+// commandDefaults returns the table of the command at path in defaults.
+func commandDefaults(defaults map[string]any, path []string) (map[string]any, bool) {
+	table := defaults
+
+	for _, name := range path {
+		next, ok := table[name].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		table = next
+	}
+
+	return table, len(path) > 0
+}
+
+// exclusiveFlagGiven reports whether a flag sharing an exclusive group with f
+// is one of the given flags.
+func exclusiveFlagGiven(f *pflag.Flag, given []*pflag.Flag) bool {
+	for _, group := range f.Annotations[exclusiveDefaultsAnnotation] {
+		names := strings.Fields(group)
+
+		if slices.ContainsFunc(given, func(g *pflag.Flag) bool {
+			return g != f && slices.Contains(names, g.Name)
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flagValues converts a config value to flag values,
+// an array sets a repeatable flag once per element.
+func flagValues(value any) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case bool:
+		return []string{strconv.FormatBool(v)}, nil
+	case int64:
+		return []string{strconv.FormatInt(v, 10)}, nil
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case []any:
+		values := make([]string, 0, len(v))
+
+		for _, e := range v {
+			if _, ok := e.([]any); ok {
+				return nil, errors.New("nested arrays are not supported")
+			}
+
+			ev, err := flagValues(e)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, ev...)
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"unicode/utf8"
+
+	"github.com/ladzaretti/vlt-cli/style"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+)
+
+// maskedName replaces secret names in tables in discreet mode.
+const maskedName = "******"
+
+// printSecrets writes the secrets as a table, see [printTable].
+//
+// In discreet mode, names are masked and labels are reduced to hints,
+// leaving the ids to select secrets by, e.g., 'vlt show --id 3 -c'.
+func (o *VaultOptions) printSecrets(ctx context.Context, w io.Writer, st *style.Styler, secrets []secretWithLabels) {
+	if !o.discreet {
+		printTable(w, st, secrets, o.labelsMeta(ctx))
+		return
+	}
+
+	masked := make([]secretWithLabels, len(secrets))
+	for i, s := range secrets {
+		labels := make([]string, len(s.labels))
+		for j, l := range s.labels {
+			labels[j] = labelHint(l)
+		}
+
+		masked[i] = secretWithLabels{id: s.id, name: maskedName, labels: labels}
+	}
+
+	printTable(w, st, masked, nil)
+}
+
+// discreetUsage returns the usage of secrets with names masked in discreet mode.
+func (o *VaultOptions) discreetUsage(usage []vaultdb.SecretUsage) []vaultdb.SecretUsage {
+	if !o.discreet {
+		return usage
+	}
+
+	masked := make([]vaultdb.SecretUsage, len(usage))
+	for i, u := range usage {
+		u.Name = maskedName
+		masked[i] = u
+	}
+
+	return masked
+}
+
+// labelHint returns the first character of the label followed by an ellipsis.
+func labelHint(label string) string {
+	r, _ := utf8.DecodeRuneInString(label)
+	return string(r) + "…"
+}
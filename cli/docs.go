@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"golang.org/x/term"
+)
+
+// defaultPager is used to display help topics when PAGER is not set.
+const defaultPager = "less"
+
+//go:embed docs/*.txt
+var docsFS embed.FS
+
+var ErrUnknownTopic = errors.New("unknown help topic")
+
+type DocsError struct {
+	Err error
+}
+
+func (e *DocsError) Error() string { return "docs: " + e.Err.Error() }
+
+func (e *DocsError) Unwrap() error { return e.Err }
+
+// docTopics returns the names of the embedded help topics, sorted.
+func docTopics() []string {
+	entries, _ := docsFS.ReadDir("docs")
+
+	topics := make([]string, 0, len(entries))
+	for _, e := range entries {
+		topics = append(topics, strings.TrimSuffix(e.Name(), ".txt"))
+	}
+
+	return topics
+}
+
+// DocsOptions holds data required to run the command.
+type DocsOptions struct {
+	*genericclioptions.StdioOptions
+}
+
+var _ genericclioptions.CmdOptions = &DocsOptions{}
+
+// NewDocsOptions initializes the options struct.
+func NewDocsOptions(stdio *genericclioptions.StdioOptions) *DocsOptions {
+	return &DocsOptions{
+		StdioOptions: stdio,
+	}
+}
+
+func (*DocsOptions) Complete() error { return nil }
+
+func (*DocsOptions) Validate() error { return nil }
+
+func (o *DocsOptions) Run(ctx context.Context, args ...string) error {
+	if len(args) == 0 {
+		o.Printf("Available topics:\n")
+
+		for _, t := range docTopics() {
+			o.Printf("  %s\n", t)
+		}
+
+		return nil
+	}
+
+	topic := args[0]
+	if !slices.Contains(docTopics(), topic) {
+		return &DocsError{fmt.Errorf("%w: %q (available: %s)", ErrUnknownTopic, topic, strings.Join(docTopics(), ", "))}
+	}
+
+	content, err := docsFS.ReadFile(path.Join("docs", topic+".txt"))
+	if err != nil {
+		return &DocsError{err}
+	}
+
+	if o.page(ctx, content) {
+		return nil
+	}
+
+	o.Printf("%s", content)
+
+	return nil
+}
+
+// page displays content using the PAGER when the output is a terminal.
+// It reports whether the content was displayed.
+func (o *DocsOptions) page(ctx context.Context, content []byte) bool {
+	f, ok := o.Out.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) { //nolint:gosec // fd fits in int.
+		return false
+	}
+
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{defaultPager}
+	}
+
+	cmd := exec.CommandContext(ctx, pager[0], pager[1:]...) //nolint:gosec // the pager is chosen by the user.
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = f
+	cmd.Stderr = o.ErrOut
+
+	if err := cmd.Run(); err != nil {
+		o.Debugf("pager %q: %v\n", pager[0], err)
+
+		// the pager may have failed after writing some output,
+		// printing the content again is still preferable to showing nothing.
+		return false
+	}
+
+	return true
+}
+
+// NewCmdDocs creates the docs cobra command.
+func NewCmdDocs(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewDocsOptions(defaults.StdioOptions)
+
+	cmd := &cobra.Command{
+		Use:   "docs [topic]",
+		Short: i18n.T("Show offline documentation topics"),
+		Long: fmt.Sprintf(`Show offline documentation topics.
+
+Without arguments, the available topics are listed.
+Topics are displayed using $PAGER (default: %s) when writing to a terminal.`, defaultPager),
+		Example: `  # List the available topics
+  vlt docs
+
+  # Read about the vault encryption
+  vlt docs crypto`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: docTopics(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+
+	return cmd
+}
+
+const (
+	docsFormatMan      = "man"
+	docsFormatMarkdown = "markdown"
+)
+
+// GenDocsOptions holds data required to run the command.
+type GenDocsOptions struct {
+	*genericclioptions.StdioOptions
+
+	root   *cobra.Command
+	dir    string
+	format string
+}
+
+var _ genericclioptions.CmdOptions = &GenDocsOptions{}
+
+// NewGenDocsOptions initializes the options struct.
+func NewGenDocsOptions(stdio *genericclioptions.StdioOptions) *GenDocsOptions {
+	return &GenDocsOptions{
+		StdioOptions: stdio,
+	}
+}
+
+func (*GenDocsOptions) Complete() error { return nil }
+
+func (o *GenDocsOptions) Validate() error {
+	if o.format != docsFormatMan && o.format != docsFormatMarkdown {
+		return &DocsError{fmt.Errorf("unsupported format %q (expected %s or %s)", o.format, docsFormatMan, docsFormatMarkdown)}
+	}
+
+	if o.dir == "" {
+		return &DocsError{errors.New("output directory must not be empty")}
+	}
+
+	return nil
+}
+
+func (o *GenDocsOptions) Run(context.Context, ...string) error {
+	if err := os.MkdirAll(o.dir, 0o750); err != nil {
+		return &DocsError{err}
+	}
+
+	// keep the generated files reproducible for packaging.
+	o.root.DisableAutoGenTag = true
+
+	var err error
+
+	switch o.format {
+	case docsFormatMan:
+		err = doc.GenManTree(o.root, &doc.GenManHeader{
+			Title:   "VLT",
+			Section: "1",
+			Source:  "vlt " + Version,
+			Manual:  "vlt manual",
+		}, o.dir)
+	case docsFormatMarkdown:
+		err = doc.GenMarkdownTree(o.root, o.dir)
+	}
+
+	if err != nil {
+		return &DocsError{err}
+	}
+
+	o.Infof("%s documentation written to %s\n", o.format, o.dir)
+
+	return nil
+}
+
+// NewCmdGenDocs creates the hidden gen-docs cobra command,
+// used to generate documentation for packaging.
+func NewCmdGenDocs(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewGenDocsOptions(defaults.StdioOptions)
+
+	cmd := &cobra.Command{
+		Use:    "gen-docs",
+		Short:  i18n.T("Generate man pages or markdown documentation"),
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			o.root = cmd.Root()
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.dir, "dir", "", "docs", "output directory")
+	cmd.Flags().StringVarP(&o.format, "format", "", docsFormatMan, fmt.Sprintf("output format (%s or %s)", docsFormatMan, docsFormatMarkdown))
+
+	return cmd
+}
@@ -4,19 +4,78 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
 
 	"github.com/spf13/cobra"
 )
 
-// vltExportHeader is the CSV header for exported vlt data.
-const vltExportHeader = "name,secret,labels"
+const (
+	// vltExportHeader is the CSV header for exported vlt data.
+	vltExportHeader = "name,secret,labels"
+
+	// vltExportMetaHeader is the CSV header for exported vlt data
+	// including label display metadata, used if any label has a color or icon.
+	vltExportMetaHeader = vltExportHeader + ",label_meta"
+)
+
+// exportedLabelMeta is the serialized form of label display metadata in the label_meta column.
+type exportedLabelMeta struct {
+	Color string `json:"color,omitempty"`
+	Icon  string `json:"icon,omitempty"`
+}
+
+// encodeLabelMeta returns the label_meta column of a secret with the given labels,
+// a JSON object of the display metadata of its labels, or empty if they have none.
+func encodeLabelMeta(labels []string, meta map[string]vaultdb.LabelMeta) (string, error) {
+	m := make(map[string]exportedLabelMeta)
+
+	for _, l := range labels {
+		if lm, ok := meta[l]; ok {
+			m[l] = exportedLabelMeta{Color: lm.Color, Icon: lm.Icon}
+		}
+	}
+
+	if len(m) == 0 {
+		return "", nil
+	}
+
+	bs, err := json.Marshal(m)
+
+	return string(bs), err
+}
+
+// encodeLabels returns the labels column of a secret, its labels as a csv record,
+// i.e., comma separated and quoted if holding commas or quotes, see [decodeLabels].
+func encodeLabels(labels []string) (string, error) {
+	if len(labels) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+
+	w := csv.NewWriter(&sb)
+	if err := w.Write(labels); err != nil {
+		return "", err
+	}
+
+	w.Flush()
+
+	return strings.TrimSuffix(sb.String(), "\n"), w.Error()
+}
 
 type ExportError struct {
 	Err error
@@ -26,12 +85,29 @@ func (e *ExportError) Error() string { return "export: " + e.Err.Error() }
 
 func (e *ExportError) Unwrap() error { return e.Err }
 
+const (
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+)
+
+// exportedSecret is the serialized form of a secret in JSON exports.
+type exportedSecret struct {
+	Name      string                       `json:"name"`
+	Secret    string                       `json:"secret"`
+	Labels    []string                     `json:"labels"`
+	LabelMeta map[string]exportedLabelMeta `json:"label_meta,omitempty"`
+}
+
 type ExportOptions struct {
 	*genericclioptions.StdioOptions
 	*VaultOptions
 
-	output string
-	stdout bool
+	output        string
+	stdout        bool
+	forceStdout   bool // forceStdout prints to a terminal stdout without confirmation, see [confirmTerminalStdout].
+	format        string
+	splitPerLabel bool // splitPerLabel exports into the --output directory, one file per label, see [ExportOptions.exportSplit].
+	onePerSecret  bool // onePerSecret exports into the --output directory, one file per secret.
 }
 
 var _ genericclioptions.CmdOptions = &ExportOptions{}
@@ -47,6 +123,14 @@ func NewExportOptions(stdio *genericclioptions.StdioOptions, vaultOptions *Vault
 func (*ExportOptions) Complete() error { return nil }
 
 func (o *ExportOptions) Validate() error {
+	if o.format != exportFormatCSV && o.format != exportFormatJSON {
+		return &ExportError{fmt.Errorf("unsupported format %q (expected %s or %s)", o.format, exportFormatCSV, exportFormatJSON)}
+	}
+
+	if (o.splitPerLabel || o.onePerSecret) && o.stdout {
+		return &ExportError{errors.New("--split-per-label and --one-per-secret export into an --output directory")}
+	}
+
 	if len(o.output) == 0 && !o.stdout {
 		return &ExportError{errors.New("either specify an --output path or use --stdout")}
 	}
@@ -62,6 +146,34 @@ func (o *ExportOptions) Run(ctx context.Context, _ ...string) (retErr error) {
 		}
 	}()
 
+	if o.stdout {
+		if err := confirmTerminalStdout(ctx, o.StdioOptions, o.forceStdout); err != nil {
+			return err
+		}
+	}
+
+	exported, err := o.vault.ExportSecrets(ctx)
+	if err != nil {
+		return err
+	}
+	defer clear(exported)
+
+	secrets := sortedByID(exported)
+	defer func() { //nolint:wsl_v5
+		for _, s := range secrets {
+			securebytes.Wipe(s.Value)
+		}
+	}()
+
+	meta, err := o.vault.LabelsMeta(ctx)
+	if err != nil {
+		return err
+	}
+
+	if o.splitPerLabel || o.onePerSecret {
+		return o.exportSplit(secrets, meta)
+	}
+
 	var out io.Writer
 
 	if len(o.output) > 0 {
@@ -80,29 +192,118 @@ func (o *ExportOptions) Run(ctx context.Context, _ ...string) (retErr error) {
 		out = o.Out
 	}
 
+	return o.writeSecrets(out, secrets, meta, false)
+}
+
+// sortedByID returns the secrets of m ordered by their ids, the order secrets are exported in.
+func sortedByID(m map[int]vaultdb.SecretWithLabels) []vaultdb.SecretWithLabels {
+	secrets := make([]vaultdb.SecretWithLabels, 0, len(m))
+	for _, id := range slices.Sorted(maps.Keys(m)) {
+		secrets = append(secrets, m[id])
+	}
+
+	return secrets
+}
+
+// writeSecrets writes secrets to w in the export format. A single secret
+// is written as a JSON object rather than an array, if single is set.
+func (o *ExportOptions) writeSecrets(w io.Writer, secrets []vaultdb.SecretWithLabels, meta map[string]vaultdb.LabelMeta, single bool) error {
+	if o.format == exportFormatJSON {
+		return writeSecretsJSON(w, secrets, meta, single)
+	}
+
+	return writeSecretsCSV(w, secrets, meta)
+}
+
+// writeSecretsCSV writes secrets as CSV, hex encoding their values.
+// The label_meta column is added if any label has display metadata.
+func writeSecretsCSV(out io.Writer, secrets []vaultdb.SecretWithLabels, meta map[string]vaultdb.LabelMeta) error {
 	w := csv.NewWriter(out)
-	defer w.Flush()
 
-	secrets, err := o.vault.ExportSecrets(ctx)
-	if err != nil {
-		return err
+	header := vltExportHeader
+	if len(meta) > 0 {
+		header = vltExportMetaHeader
 	}
-	defer clear(secrets)
 
-	if err := w.Write(strings.Split(vltExportHeader, ",")); err != nil {
+	if err := w.Write(strings.Split(header, ",")); err != nil {
 		return err
 	}
 
 	for _, secret := range secrets {
-		labels := strings.Join(secret.Labels, ",")
-		if err := w.Write([]string{secret.Name, hex.EncodeToString(secret.Value), labels}); err != nil {
+		labels, err := encodeLabels(secret.Labels)
+		if err != nil {
 			return err
 		}
 
-		clear(secret.Value)
+		record := []string{secret.Name, hex.EncodeToString(secret.Value), labels}
+
+		if len(meta) > 0 {
+			labelMeta, err := encodeLabelMeta(secret.Labels, meta)
+			if err != nil {
+				return err
+			}
+
+			record = append(record, labelMeta)
+		}
+
+		if err := w.Write(record); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	w.Flush()
+
+	return w.Error()
+}
+
+// writeSecretsJSON writes secrets as JSON, their values as text.
+// Values that are not valid UTF-8 can only be exported as CSV.
+func writeSecretsJSON(w io.Writer, secrets []vaultdb.SecretWithLabels, meta map[string]vaultdb.LabelMeta, single bool) error {
+	records := make([]exportedSecret, 0, len(secrets))
+
+	for _, secret := range secrets {
+		if !utf8.Valid(secret.Value) {
+			return fmt.Errorf("secret %q is not valid UTF-8 text, export it as %s", secret.Name, exportFormatCSV)
+		}
+
+		record := exportedSecret{
+			Name:   secret.Name,
+			Secret: string(secret.Value),
+			Labels: secret.Labels,
+		}
+
+		if record.Labels == nil {
+			record.Labels = []string{}
+		}
+
+		for _, l := range secret.Labels {
+			if lm, ok := meta[l]; ok {
+				if record.LabelMeta == nil {
+					record.LabelMeta = make(map[string]exportedLabelMeta)
+				}
+
+				record.LabelMeta[l] = exportedLabelMeta{Color: lm.Color, Icon: lm.Icon}
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	var v any = records
+	if single && len(records) == 1 {
+		v = records[0]
+	}
+
+	bs, err := json.MarshalIndent(v, "", "  ")
+	defer securebytes.Wipe(bs)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(bs, '\n'))
+
+	return err
 }
 
 // NewCmdExport creates the export cobra command.
@@ -114,16 +315,44 @@ func NewCmdExport(defaults *DefaultVltOptions) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "export",
-		Short: "Export secrets to a file or stdout",
-		Long: `Export secrets in CSV format.
+		Short: i18n.T("Export secrets to a file or stdout"),
+		Long: `Export secrets in CSV format, or as JSON using --format json.
 	
-Use --output to specify a file path or --stdout to print to standard output (unsafe).`,
+Use --output to specify a file path or --stdout to print to standard output (unsafe).
+Printing to a terminal is only done once confirmed, or with --force-stdout.
+
+Secrets are exported in the order of their ids, and their labels in the order they were
+added, so that exports of an unchanged vault are identical, e.g., to diff exports kept in
+encrypted backups. Importing an export assigns new ids in the same order.
+
+The labels of a secret are comma separated, labels holding commas or quotes are quoted.
+If any label has a display color or icon, it is exported in an additional label_meta column.
+CSV exports hex encode the secret values and can be imported back, JSON exports hold them
+as text, e.g., to feed other tools.
+
+Use --split-per-label to export into the --output directory, one file per label named
+after it, holding the secrets of the label, e.g., of a service. Secrets without labels
+are exported to the 'unlabeled' file. Use --one-per-secret for one file per secret,
+named after it. Characters not allowed in file names are replaced by '_', and names
+used already are suffixed by a number. Existing files of the same names are replaced.`,
+		Example: `  # Export all secrets to a CSV file
+  vlt export -o secrets.csv
+
+  # Export one JSON file per label, e.g., secrets/github.json
+  vlt export --split-per-label --format json -o secrets/`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
 		},
 	}
 	cmd.Flags().StringVarP(&o.output, "output", "o", "", "export secrets to the specified file path")
 	cmd.Flags().BoolVarP(&o.stdout, "stdout", "", false, "print exported secrets to standard output (unsafe)")
+	cmd.Flags().BoolVarP(&o.forceStdout, "force-stdout", "", false, "print to stdout without confirmation if it is a terminal")
+	cmd.Flags().StringVarP(&o.format, "format", "", exportFormatCSV, fmt.Sprintf("export format (%s or %s)", exportFormatCSV, exportFormatJSON))
+	cmd.Flags().BoolVarP(&o.splitPerLabel, "split-per-label", "", false, "export into the --output directory, one file per label")
+	cmd.Flags().BoolVarP(&o.onePerSecret, "one-per-secret", "", false, "export into the --output directory, one file per secret")
+
+	markExclusiveDefaults(cmd, "output", "stdout")
+	cmd.MarkFlagsMutuallyExclusive("split-per-label", "one-per-secret")
 
 	return cmd
 }
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+)
+
+const (
+	// unlabeledExportName is the file name of the secrets without labels exported by --split-per-label.
+	unlabeledExportName = "unlabeled"
+
+	// maxExportNameLen is the maximal length in bytes of the file names of split exports, without extension.
+	maxExportNameLen = 128
+
+	exportDirPerm  = 0o700
+	exportFilePerm = 0o600
+)
+
+// exportSplit exports secrets, ordered by id, into the --output directory, one file
+// per label, or per secret if --one-per-secret is set. Files are named in the order
+// of the secrets, and of the label names.
+func (o *ExportOptions) exportSplit(secrets []vaultdb.SecretWithLabels, meta map[string]vaultdb.LabelMeta) error {
+	if err := os.MkdirAll(o.output, exportDirPerm); err != nil {
+		return err
+	}
+
+	var (
+		names = exportFileNames{}
+		files = 0
+	)
+
+	write := func(name string, secrets []vaultdb.SecretWithLabels, single bool) error {
+		path := filepath.Join(o.output, names.unique(name)+"."+o.format)
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, exportFilePerm) //nolint:gosec // user export path
+		if err != nil {
+			return err
+		}
+
+		if err := o.writeSecrets(f, secrets, meta, single); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		files++
+
+		return f.Close()
+	}
+
+	if o.onePerSecret {
+		for _, s := range secrets {
+			if err := write(s.Name, []vaultdb.SecretWithLabels{s}, true); err != nil {
+				return err
+			}
+		}
+
+		o.Infof("exported %d secrets to %d files in %s\n", len(secrets), files, o.output)
+
+		return nil
+	}
+
+	var (
+		byLabel   = map[string][]vaultdb.SecretWithLabels{}
+		unlabeled []vaultdb.SecretWithLabels
+	)
+
+	for _, s := range secrets {
+		if len(s.Labels) == 0 {
+			unlabeled = append(unlabeled, s)
+		}
+
+		for _, l := range s.Labels {
+			byLabel[l] = append(byLabel[l], s)
+		}
+	}
+
+	for _, l := range slices.Sorted(maps.Keys(byLabel)) {
+		if err := write(l, byLabel[l], false); err != nil {
+			return err
+		}
+	}
+
+	if len(unlabeled) > 0 {
+		if err := write(unlabeledExportName, unlabeled, false); err != nil {
+			return err
+		}
+	}
+
+	o.Infof("exported %d secrets to %d files in %s\n", len(secrets), files, o.output)
+
+	return nil
+}
+
+// exportFileNames assigns unique file names to the labels or secrets of a split export.
+// Names are compared case-insensitively, as are file names of some filesystems.
+type exportFileNames map[string]bool
+
+// unique returns the file name of s, see [exportFileName],
+// suffixed by a number if used already.
+func (used exportFileNames) unique(s string) string {
+	base := exportFileName(s)
+	name := base
+
+	for i := 2; used[strings.ToLower(name)]; i++ {
+		name = base + "-" + strconv.Itoa(i)
+	}
+
+	used[strings.ToLower(name)] = true
+
+	return name
+}
+
+// exportFileName returns s as a file name: letters, digits and ._-@+ are kept,
+// other characters and a leading dot are replaced by '_'.
+func exportFileName(s string) string {
+	var sb strings.Builder
+
+	for i, r := range s {
+		if sb.Len()+len(string(r)) > maxExportNameLen {
+			break
+		}
+
+		switch {
+		case r == '.' && i == 0:
+			sb.WriteRune('_')
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("._-@+", r):
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "_"
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+var errFIFOUnsupported = errors.New("named pipes are not supported on this platform")
+
+func makeFIFO(string) error { return errFIFOUnsupported }
+
+func openFIFOWriter(context.Context, string) (*os.File, error) {
+	return nil, errFIFOUnsupported
+}
@@ -0,0 +1,47 @@
+//go:build unix
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// fifoPollInterval is the interval at which a FIFO is checked for a reader.
+const fifoPollInterval = 50 * time.Millisecond
+
+// makeFIFO creates a named pipe at path, readable and writable by the owner only.
+func makeFIFO(path string) error {
+	return unix.Mkfifo(path, vaultPerm)
+}
+
+// openFIFOWriter opens the FIFO at path for writing once a reader opened it,
+// or until ctx is done.
+//
+// A blocking open cannot be canceled, so the FIFO is polled without blocking,
+// which fails with ENXIO as long as there is no reader.
+func openFIFOWriter(ctx context.Context, path string) (*os.File, error) {
+	t := time.NewTicker(fifoPollInterval)
+	defer t.Stop()
+
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|unix.O_NONBLOCK, 0) //nolint:gosec // the path is created by vlt
+		if err == nil {
+			return f, nil
+		}
+
+		if !errors.Is(err, unix.ENXIO) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
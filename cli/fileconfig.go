@@ -8,6 +8,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/style"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -16,6 +19,10 @@ const (
 	// envConfigPathKey is the environment variable key for overriding
 	// the config file path.
 	envConfigPathKey = "VLT_CONFIG_PATH"
+
+	// envSessionSocketKey is the environment variable key for overriding
+	// the vltd socket path, see [DaemonSocket].
+	envSessionSocketKey = "VLT_SESSION_SOCKET"
 )
 
 type ConfigError struct {
@@ -36,6 +43,29 @@ type FileConfig struct {
 	Vault     VaultConfig      `toml:"vault" json:"vault"`
 	Clipboard *ClipboardConfig `toml:"clipboard" comment:"Clipboard configuration: Both copy and paste commands must be either both set or both unset." json:"clipboard"`
 	Hooks     *HooksConfig     `toml:"hooks" comment:"Optional lifecycle hooks for vault events" json:"hooks"`
+	UI        *UIConfig        `toml:"ui" comment:"Terminal output settings" json:"ui"`
+	Backup    *BackupConfig    `toml:"backup" comment:"Offline backups created by 'vlt backup'" json:"backup"`
+	Daemon    *DaemonConfig    `toml:"daemon" comment:"Settings read by the vltd daemon on startup" json:"daemon"`
+
+	// Vaults holds per-vault policies keyed by a user chosen name,
+	// e.g., [vaults.work]. It is omitted from the generated config.
+	Vaults map[string]VaultPolicyConfig `toml:"vaults,omitempty" json:"vaults,omitempty"`
+
+	// Templates holds secret entry templates keyed by name,
+	// e.g., [templates.aws-iam]. It is omitted from the generated config.
+	Templates map[string]TemplateConfig `toml:"templates,omitempty" json:"templates,omitempty"`
+
+	// Lint holds secret value lint rules keyed by name, e.g., [lint.aws-keys],
+	// checked on save and update. It is omitted from the generated config.
+	Lint map[string]LintRuleConfig `toml:"lint,omitempty" json:"lint,omitempty"`
+
+	// Aliases holds command aliases keyed by name, e.g., pw = 'show -c --name'.
+	// It is omitted from the generated config.
+	Aliases map[string]string `toml:"aliases,omitempty" json:"aliases,omitempty"`
+
+	// Defaults holds default flag values keyed by command, e.g., [defaults.show]
+	// or [defaults.wifi.show] for sub-commands. It is omitted from the generated config.
+	Defaults map[string]any `toml:"defaults,omitempty" json:"defaults,omitempty"`
 
 	path string // path to the loaded config file. Empty if no config file was used.
 }
@@ -44,6 +74,9 @@ func newFileConfig() *FileConfig {
 	return &FileConfig{
 		Clipboard: &ClipboardConfig{},
 		Hooks:     &HooksConfig{},
+		UI:        &UIConfig{},
+		Backup:    &BackupConfig{},
+		Daemon:    &DaemonConfig{Schedule: &ScheduleConfig{}},
 	}
 }
 
@@ -51,9 +84,73 @@ func newFileConfig() *FileConfig {
 //
 //nolint:tagalign,tagliatelle
 type VaultConfig struct {
-	Path                string `toml:"path,commented" comment:"Vlt database path (default: '~/.vlt' if not set)" json:"path,omitempty"`
-	SessionDuration     string `toml:"session_duration,commented" comment:"How long a session lasts before requiring login again (default: '1m')" json:"session_duration,omitempty"`
-	MaxHistorySnapshots *int   `toml:"max_history_snapshots,commented" comment:"Maximum number of historical vault snapshots to keep (default: 3, 0 disables history)" json:"max_history_snapshots,omitempty"`
+	Path                string   `toml:"path,commented" comment:"Vlt database path (default: '$XDG_DATA_HOME/vlt/vault.db' if not set)" json:"path,omitempty"`
+	SessionDuration     string   `toml:"session_duration,commented" comment:"How long a session lasts before requiring login again (default: '1m')" json:"session_duration,omitempty"`
+	MaxHistorySnapshots *int     `toml:"max_history_snapshots,commented" comment:"Maximum number of historical vault snapshots to keep (default: 3, 0 disables history)" json:"max_history_snapshots,omitempty"`
+	AutoVacuumThreshold *int     `toml:"auto_vacuum_threshold,commented" comment:"Vacuum the vault container once pruned history snapshots leave more than this many KiB reclaimable (default: 1024, 0 disables auto-vacuum)" json:"auto_vacuum_threshold,omitempty"`
+	CommandTimeout      string   `toml:"command_timeout,commented" comment:"Maximum duration of a command once the vault is unlocked, e.g., '30s' (default: '0', no timeout)" json:"command_timeout,omitempty"`
+	AutostartDaemon     bool     `toml:"autostart_daemon,commented" comment:"Start the vltd daemon in the background if it is not running and sessions are enabled (default: false)" json:"autostart_daemon,omitempty"`
+	TrackUsage          bool     `toml:"track_usage,commented" comment:"Record how often and when each secret is retrieved, inside the encrypted vault, see 'vlt stats' (default: false)" json:"track_usage,omitempty"`
+	UniqueNames         bool     `toml:"unique_names,commented" comment:"Reject saving, importing or renaming a secret to a name already in use, compared case-insensitively (default: false)" json:"unique_names,omitempty"`
+	MinPasswordLength   *int     `toml:"min_password_length,commented" comment:"Minimum length of a new master password (default: 8)" json:"min_password_length,omitempty"`
+	MinPasswordClasses  *int     `toml:"min_password_classes,commented" comment:"Minimum number of character classes (lower case, upper case, digits, symbols) of a new master password (default: 1)" json:"min_password_classes,omitempty"`
+	MinPasswordBits     *int     `toml:"min_password_bits,commented" comment:"Minimum estimated strength in bits of a new master password, see 'vlt create --weak-ok' (default: 40)" json:"min_password_bits,omitempty"`
+	PasswordCommand     []string `toml:"password_command,commented" comment:"Command printing the master password to stdout, run instead of prompting when no session exists, e.g., ['secret-tool', 'lookup', 'vlt', 'master']; overridden by $VLT_PASSWORD_COMMAND (default: none)" json:"password_command,omitempty"`
+	IdentityFile        string   `toml:"identity_file,commented" comment:"Age identity file used to unlock the vault as a member instead of the password, see 'vlt member' (default: none)" json:"identity_file,omitempty"`
+	AllowCoreDumps      bool     `toml:"allow_core_dumps,commented" comment:"Allow core dumps of vlt and vltd, e.g., to debug a crash; core files may contain decrypted secrets (default: false)" json:"allow_core_dumps,omitempty"`
+}
+
+// VaultPolicyConfig holds the session policy of the vault at Path,
+// overriding the global vault settings.
+//
+//nolint:tagalign,tagliatelle
+type VaultPolicyConfig struct {
+	Path            string `toml:"path" json:"path"`
+	SessionDuration string `toml:"session_duration,omitempty" json:"session_duration,omitempty"`
+	ConfirmEachUse  bool   `toml:"confirm_each_use,omitempty" json:"confirm_each_use,omitempty"`
+}
+
+// TemplateConfig defines a secret entry template used by 'vlt save --template'.
+//
+// Name and attribute values may reference the template fields
+// as {field} placeholders, e.g., name = 'aws/{account}/{user}'.
+//
+//nolint:tagalign,tagliatelle
+type TemplateConfig struct {
+	Name     string                `toml:"name" json:"name"`
+	Fields   []string              `toml:"fields,omitempty" json:"fields,omitempty"`
+	Labels   []string              `toml:"labels,omitempty" json:"labels,omitempty"`
+	Attrs    map[string]string     `toml:"attrs,omitempty" json:"attrs,omitempty"`
+	Generate bool                  `toml:"generate,omitempty" json:"generate,omitempty"`
+	Policy   *PasswordPolicyConfig `toml:"policy,omitempty" json:"policy,omitempty"`
+}
+
+// PasswordPolicyConfig defines the policy of generated secrets,
+// with the same meaning as the 'vlt generate' flags.
+//
+//nolint:tagalign,tagliatelle
+type PasswordPolicyConfig struct {
+	UpperCase int `toml:"upper_case,omitempty" json:"upper_case,omitempty"`
+	LowerCase int `toml:"lower_case,omitempty" json:"lower_case,omitempty"`
+	Numeric   int `toml:"numeric,omitempty" json:"numeric,omitempty"`
+	Special   int `toml:"special,omitempty" json:"special,omitempty"`
+	MinLength int `toml:"min_length,omitempty" json:"min_length,omitempty"`
+}
+
+// LintRuleConfig defines a lint rule checking the values of secrets
+// on 'vlt save' and 'vlt update secret', see 'vlt save --help'.
+//
+// The value must be of the builtin Type, or match the Pattern regular expression.
+// The rule applies to secrets matching any of the Names glob patterns, or having
+// any of the Labels, or to all secrets if neither is set.
+//
+//nolint:tagalign,tagliatelle
+type LintRuleConfig struct {
+	Type    string   `toml:"type,omitempty" json:"type,omitempty"`
+	Pattern string   `toml:"pattern,omitempty" json:"pattern,omitempty"`
+	Names   []string `toml:"names,omitempty" json:"names,omitempty"`
+	Labels  []string `toml:"labels,omitempty" json:"labels,omitempty"`
+	Action  string   `toml:"action,omitempty" json:"action,omitempty"` // Action is reject, the default, or warn.
 }
 
 // ClipboardConfig defines commands for clipboard ops.
@@ -68,8 +165,43 @@ type ClipboardConfig struct {
 //
 //nolint:tagalign,tagliatelle
 type HooksConfig struct {
-	PostLoginCmd []string `toml:"post_login_cmd,commented" comment:"Command to run after a successful login" json:"post_login_cmd"`
-	PostWriteCmd []string `toml:"post_write_cmd,commented" comment:"Command to run after any vault write (e.g., create, update, delete)" json:"post_write_cmd"`
+	PostLoginCmd    []string `toml:"post_login_cmd,commented" comment:"Command to run after a successful login" json:"post_login_cmd"`
+	PostWriteCmd    []string `toml:"post_write_cmd,commented" comment:"Command to run after any vault write (e.g., create, update, delete)" json:"post_write_cmd"`
+	PostLockCmd     []string `toml:"post_lock_cmd,commented" comment:"Command to run after 'vlt lock' drops all sessions" json:"post_lock_cmd"`
+	Script          string   `toml:"script,commented" comment:"Starlark script defining post_login, post_write and post_lock functions, run after the hook commands (default: none)" json:"script,omitempty"`
+	ScriptAllowExec bool     `toml:"script_allow_exec,commented" comment:"Allow the hook script to run commands using exec() (default: false)" json:"script_allow_exec,omitempty"`
+}
+
+// UIConfig defines how output is styled on a terminal.
+//
+//nolint:tagalign,tagliatelle
+type UIConfig struct {
+	Theme    string `toml:"theme,commented" comment:"Output color theme: default, high-contrast or monochrome (default: 'default')" json:"theme,omitempty"`
+	NoColor  bool   `toml:"no_color,commented" comment:"Disable colored output, same as --no-color (default: false)" json:"no_color,omitempty"`
+	Discreet bool   `toml:"discreet,commented" comment:"Mask secret names and labels in tables, e.g., while screen sharing, same as --discreet (default: false)" json:"discreet,omitempty"`
+}
+
+// BackupConfig defines where 'vlt backup' writes backups and how many are kept.
+//
+//nolint:tagalign,tagliatelle
+type BackupConfig struct {
+	Dir  string `toml:"dir,commented" comment:"Directory backups are written to, e.g., '/mnt/usb/vlt' (default: none, see 'vlt backup --to')" json:"dir,omitempty"`
+	Keep *int   `toml:"keep,commented" comment:"Number of backups to keep in the directory, older ones are removed (default: 10, 0 keeps all backups)" json:"keep,omitempty"`
+}
+
+// DaemonConfig defines the settings of the vltd daemon.
+//
+//nolint:tagalign,tagliatelle
+type DaemonConfig struct {
+	Socket   string          `toml:"socket,commented" comment:"Path of the vltd socket used by vlt and vltd, or '@name' for an abstract socket, e.g., in containers; overridden by $VLT_SESSION_SOCKET (default: '$XDG_RUNTIME_DIR/vlt.sock')" json:"socket,omitempty"`
+	Schedule *ScheduleConfig `toml:"schedule" comment:"Periodic jobs run by vltd while it is up, results are logged and shown by 'vlt session status'" json:"schedule"`
+}
+
+// ScheduleConfig defines the intervals of the jobs run by vltd.
+//
+//nolint:tagalign,tagliatelle
+type ScheduleConfig struct {
+	Backup string `toml:"backup,commented" comment:"Back up the vault to the [backup] directory at this interval, e.g., '24h' (default: none, disabled)" json:"backup,omitempty"`
 }
 
 // LoadFileConfig loads the config from the given or default path.
@@ -97,17 +229,16 @@ func LoadFileConfig(path string) (*FileConfig, error) {
 }
 
 func defaultConfigPath() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("config: user home dir: %w", err)
+	if p, ok := os.LookupEnv(envConfigPathKey); ok {
+		return p, nil
 	}
 
-	path := filepath.Join(home, defaultConfigName)
-	if p, ok := os.LookupEnv(envConfigPathKey); ok {
-		path = p
+	p, err := configPaths()
+	if err != nil {
+		return "", fmt.Errorf("config: %w", err)
 	}
 
-	return path, nil
+	return p.resolve(), nil
 }
 
 func parseFileConfig(path string) (*FileConfig, error) {
@@ -133,10 +264,42 @@ func (c *FileConfig) validate() error {
 		return &ConfigError{Err: errors.New("cannot validate a nil config")}
 	}
 
+	for name, v := range c.Vaults {
+		if len(v.Path) == 0 {
+			return &ConfigError{Opt: "vaults." + name, Err: errors.New("'path' must be set")}
+		}
+	}
+
+	for name, t := range c.Templates {
+		if err := t.validate(); err != nil {
+			return &ConfigError{Opt: "templates." + name, Err: err}
+		}
+	}
+
+	for name, r := range c.Lint {
+		if err := r.validate(); err != nil {
+			return &ConfigError{Opt: "lint." + name, Err: err}
+		}
+	}
+
+	for name, definition := range c.Aliases {
+		if err := validateAlias(name, definition); err != nil {
+			return &ConfigError{Opt: "aliases." + name, Err: err}
+		}
+	}
+
+	if err := validateDefaults(c.Defaults, "defaults"); err != nil {
+		return err
+	}
+
 	if c.hasPartialClipboard() {
 		return &ConfigError{Opt: "clipboard", Err: errors.New("both 'copy_cmd' and 'paste_cmd' must be set or unset together")}
 	}
 
+	if c.Vault.PasswordCommand != nil && len(c.Vault.PasswordCommand) == 0 {
+		return &ConfigError{Opt: "vault.password_command", Err: errors.New("defined but contains no values")}
+	}
+
 	if c.Hooks.PostLoginCmd != nil && len(c.Hooks.PostLoginCmd) == 0 {
 		return &ConfigError{Opt: "hooks.post_login_cmd", Err: errors.New("defined but contains no values")}
 	}
@@ -145,10 +308,59 @@ func (c *FileConfig) validate() error {
 		return &ConfigError{Opt: "hooks.post_write_cmd", Err: errors.New("defined but contains no values")}
 	}
 
+	if c.Hooks.PostLockCmd != nil && len(c.Hooks.PostLockCmd) == 0 {
+		return &ConfigError{Opt: "hooks.post_lock_cmd", Err: errors.New("defined but contains no values")}
+	}
+
 	if c.Vault.MaxHistorySnapshots != nil && *c.Vault.MaxHistorySnapshots < 0 {
 		return &ConfigError{Opt: "vault.max_history_snapshots", Err: errors.New("must be zero or a positive integer")}
 	}
 
+	if c.Vault.AutoVacuumThreshold != nil && *c.Vault.AutoVacuumThreshold < 0 {
+		return &ConfigError{Opt: "vault.auto_vacuum_threshold", Err: errors.New("must be zero or a positive integer")}
+	}
+
+	if c.Vault.MinPasswordLength != nil && *c.Vault.MinPasswordLength < 1 {
+		return &ConfigError{Opt: "vault.min_password_length", Err: errors.New("must be a positive integer")}
+	}
+
+	if c.Vault.MinPasswordClasses != nil && (*c.Vault.MinPasswordClasses < 0 || *c.Vault.MinPasswordClasses > maxPasswordClasses) {
+		return &ConfigError{Opt: "vault.min_password_classes", Err: fmt.Errorf("must be between 0 and %d", maxPasswordClasses)}
+	}
+
+	if c.Vault.MinPasswordBits != nil && *c.Vault.MinPasswordBits < 0 {
+		return &ConfigError{Opt: "vault.min_password_bits", Err: errors.New("must be zero or a positive integer")}
+	}
+
+	if c.Backup.Keep != nil && *c.Backup.Keep < 0 {
+		return &ConfigError{Opt: "backup.keep", Err: errors.New("must be zero or a positive integer")}
+	}
+
+	if len(c.Daemon.Socket) > 0 {
+		if err := validateSocketPath(c.Daemon.Socket); err != nil {
+			return &ConfigError{Opt: "daemon.socket", Err: err}
+		}
+	}
+
+	if len(c.Daemon.Schedule.Backup) > 0 {
+		d, err := time.ParseDuration(c.Daemon.Schedule.Backup)
+		if err != nil {
+			return &ConfigError{Opt: "daemon.schedule.backup", Err: err}
+		}
+
+		if d <= 0 {
+			return &ConfigError{Opt: "daemon.schedule.backup", Err: errors.New("must be positive")}
+		}
+
+		if len(c.Backup.Dir) == 0 {
+			return &ConfigError{Opt: "daemon.schedule.backup", Err: errors.New("requires 'dir' in the [backup] section")}
+		}
+	}
+
+	if _, err := style.LookupTheme(c.UI.Theme); err != nil {
+		return &ConfigError{Opt: "ui.theme", Err: err}
+	}
+
 	return nil
 }
 
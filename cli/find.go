@@ -3,9 +3,17 @@ package cli
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
 
 	"github.com/spf13/cobra"
 )
@@ -23,10 +31,26 @@ type FindOptions struct {
 	*genericclioptions.StdioOptions
 	*VaultOptions
 
-	config *ResolvedConfig
-	search *SearchableOptions
+	config     *ResolvedConfig
+	search     *SearchableOptions
+	unused     bool              // unused lists only secrets not retrieved within since, or never.
+	unmodified bool              // unmodified lists only secrets not modified within since.
+	since      string            // since is the raw --since age, see [parseAge].
+	sinceAge   time.Duration     // sinceAge is the parsed since value.
+	attrs      []string          // attrs holds the raw --attr key[=glob] filters.
+	attrFilter map[string]string // attrFilter holds the parsed attrs.
+	tree       bool              // tree groups the matching secrets by label, see [printTree].
+	sort       string            // sort orders the matching secrets, one of [findSortOrders].
 }
 
+const (
+	sortByName   = "name"   // sortByName orders secrets by name.
+	sortByRecent = "recent" // sortByRecent orders secrets by their last retrieval, most recent first.
+)
+
+// findSortOrders lists the supported --sort values.
+var findSortOrders = []string{sortByName, sortByRecent}
+
 var _ genericclioptions.CmdOptions = &FindOptions{}
 
 // NewFindOptions initializes the options struct.
@@ -41,7 +65,41 @@ func NewFindOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOp
 
 func (o *FindOptions) Complete() error { return o.search.Complete() }
 
-func (o *FindOptions) Validate() error { return o.search.Validate() }
+func (o *FindOptions) Validate() error {
+	if err := o.search.Validate(); err != nil {
+		return err
+	}
+
+	attrFilter, err := parseAttributes(o.attrs, false)
+	if err != nil {
+		return &FindError{err}
+	}
+
+	o.attrFilter = attrFilter
+
+	if len(o.sort) > 0 && !slices.Contains(findSortOrders, o.sort) {
+		return &FindError{fmt.Errorf("invalid --sort value %q: must be one of %s", o.sort, strings.Join(findSortOrders, ", "))}
+	}
+
+	if len(o.since) > 0 && !o.unused && !o.unmodified {
+		return &FindError{errors.New("--since requires --unused or --unmodified")}
+	}
+
+	if o.unmodified && len(o.since) == 0 {
+		return &FindError{errors.New("--unmodified requires --since")}
+	}
+
+	if len(o.since) > 0 {
+		age, err := parseAge(o.since)
+		if err != nil {
+			return &FindError{fmt.Errorf("invalid --since value: %w", err)}
+		}
+
+		o.sinceAge = age
+	}
+
+	return nil
+}
 
 func (o *FindOptions) Run(ctx context.Context, args ...string) (retErr error) {
 	defer func() {
@@ -53,20 +111,177 @@ func (o *FindOptions) Run(ctx context.Context, args ...string) (retErr error) {
 
 	o.search.WildcardFrom(args)
 
-	matchingSecrets, err := o.search.search(ctx, o.vault)
+	matchingSecrets, err := o.search.search(ctx, o.secretSearcher())
 	if err != nil {
 		return err
 	}
 
+	if o.unused || o.unmodified {
+		matchingSecrets, err = o.filterStale(ctx, matchingSecrets)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(o.attrFilter) > 0 {
+		matchingSecrets, err = o.filterAttributes(ctx, matchingSecrets)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(o.sort) > 0 {
+		matchingSecrets, err = o.sortSecrets(ctx, matchingSecrets)
+		if err != nil {
+			return err
+		}
+	}
+
 	var buf bytes.Buffer
 
-	printTable(&buf, matchingSecrets)
+	if o.tree {
+		o.printSecretsTree(ctx, &buf, o.Styler(o.Out), matchingSecrets)
+	} else {
+		o.printSecrets(ctx, &buf, o.Styler(o.Out), matchingSecrets)
+	}
 
 	_, err = buf.WriteTo(o.Out)
 
 	return err
 }
 
+// filterStale returns the secrets not retrieved or not modified
+// within the --since age, as requested by the --unused and --unmodified flags.
+func (o *FindOptions) filterStale(ctx context.Context, secrets []secretWithLabels) ([]secretWithLabels, error) {
+	if o.unused && !o.trackUsage {
+		o.Infof("usage tracking is disabled, retrievals are not recorded; see 'vlt stats --help'\n")
+	}
+
+	usage, err := o.vault.SecretsUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]vaultdb.SecretUsage, len(usage))
+	for _, u := range usage {
+		byID[u.ID] = u
+	}
+
+	cutoff := time.Now().Add(-o.sinceAge)
+
+	return slices.DeleteFunc(secrets, func(s secretWithLabels) bool {
+		u := byID[s.id]
+
+		// without --since, unused means never retrieved.
+		if o.unused && !u.LastAccessed.IsZero() && (o.sinceAge == 0 || u.LastAccessed.After(cutoff)) {
+			return true
+		}
+
+		return o.unmodified && u.Modified.After(cutoff)
+	}), nil
+}
+
+// sortSecrets returns the secrets ordered as requested by --sort.
+// Secrets never retrieved are listed last by the recent order, in their
+// original order.
+func (o *FindOptions) sortSecrets(ctx context.Context, secrets []secretWithLabels) ([]secretWithLabels, error) {
+	if o.sort == sortByName {
+		slices.SortStableFunc(secrets, func(a, b secretWithLabels) int {
+			return strings.Compare(a.name, b.name)
+		})
+
+		return secrets, nil
+	}
+
+	if !o.trackUsage {
+		o.Infof("usage tracking is disabled, retrievals are not recorded; see 'vlt stats --help'\n")
+	}
+
+	usage, err := o.vault.SecretsUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lastAccessed := make(map[int]time.Time, len(usage))
+	for _, u := range usage {
+		lastAccessed[u.ID] = u.LastAccessed
+	}
+
+	slices.SortStableFunc(secrets, func(a, b secretWithLabels) int {
+		return lastAccessed[b.id].Compare(lastAccessed[a.id])
+	})
+
+	return secrets, nil
+}
+
+// filterAttributes returns the secrets with attributes matching all --attr filters.
+func (o *FindOptions) filterAttributes(ctx context.Context, secrets []secretWithLabels) ([]secretWithLabels, error) {
+	if len(secrets) == 0 {
+		return secrets, nil
+	}
+
+	attrs, err := o.vault.SecretsAttributes(ctx, extractIDs(secrets)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(secrets, func(s secretWithLabels) bool {
+		return !matchAttributes(attrs[s.id], o.attrFilter)
+	}), nil
+}
+
+// parseAge parses an age such as "90d", "1y" or "1y12h".
+//
+// In addition to the [time.ParseDuration] units, leading "y" (365 days),
+// "w" (7 days) and "d" (24 hours) components are accepted.
+func parseAge(s string) (time.Duration, error) {
+	days := map[byte]time.Duration{'y': 365, 'w': 7, 'd': 1}
+
+	var (
+		age  time.Duration
+		rest = s
+	)
+
+	for {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+
+		if i == 0 || i == len(rest) {
+			break
+		}
+
+		d, ok := days[rest[i]]
+		if !ok {
+			break
+		}
+
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, err
+		}
+
+		age += time.Duration(n) * d * 24 * time.Hour
+		rest = rest[i+1:]
+	}
+
+	if len(rest) > 0 {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("age %q: expected e.g. 90d, 2w, 1y or 36h", s)
+		}
+
+		age += d
+	}
+
+	if age <= 0 {
+		return 0, fmt.Errorf("age %q: must be positive", s)
+	}
+
+	return age, nil
+}
+
 // NewCmdFind creates the find cobra command.
 func NewCmdFind(defaults *DefaultVltOptions) *cobra.Command {
 	o := NewFindOptions(
@@ -79,7 +294,7 @@ func NewCmdFind(defaults *DefaultVltOptions) *cobra.Command {
 		Use:     "find [glob]",
 		Args:    cobra.ArbitraryArgs,
 		Aliases: []string{"list", "ls"},
-		Short:   "Search for secrets",
+		Short:   i18n.T("Search for secrets"),
 		Long: `Search for secrets stored in the vault using various filters.
 
 You may optionally provide a glob pattern to match against secret names or labels.
@@ -87,15 +302,46 @@ You may optionally provide a glob pattern to match against secret names or label
 Filters can be applied using --id, --name, or --label.
 Multiple --label flags can be applied and are logically ORed.
 
-Search values support UNIX glob patterns (e.g., "foo*", "*bar*").`,
+Search values support UNIX glob patterns (e.g., "foo*", "*bar*").
+Use --fuzzy to match the glob argument loosely instead, e.g., "ght" matches
+"github-token", listing the best matches first.
+
+Use --unused to list secrets never retrieved, or with --since, not retrieved
+within the given age, e.g., 90d, 2w or 1y. Retrievals are only recorded while
+usage tracking is enabled, see 'vlt stats'.
+Use --unmodified --since to list secrets not created or updated within the given age.
+
+Use --attr key to list secrets with the given attribute, or --attr key=glob
+to also match its value, e.g., --attr "env=prod*". Multiple --attr filters are ANDed.
+
+Use --sort name to list secrets by name, or --sort recent to list the most
+recently retrieved secrets first, requires usage tracking, see 'vlt stats'.
+
+Use --tree to group the matching secrets under their labels, with a count per label.
+Secrets with several labels are listed under each of them, marked with *.`,
 		Example: `  # Find secrets with names or labels containing "foo"
   vlt find "*foo*"
 
+  # Find secrets with names or labels loosely matching "ght", e.g., "github-token"
+  vlt find --fuzzy ght
+
   # Find secrets matching multiple labels (AND logic)
   vlt find --label foo --label bar
 
   # List all secrets in the vault
-  vlt find`,
+  vlt find
+
+  # List secrets not retrieved in the last year
+  vlt find --unused --since 1y
+
+  # List secrets by attribute value
+  vlt find --attr env=prod --attr owner
+
+  # List the most recently retrieved secrets first
+  vlt find --sort recent
+
+  # Browse all secrets grouped by label
+  vlt find --tree`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
 		},
@@ -104,6 +350,13 @@ Search values support UNIX glob patterns (e.g., "foo*", "*bar*").`,
 	cmd.Flags().IntSliceVarP(&o.search.IDs, "id", "", nil, FilterByID.Help())
 	cmd.Flags().StringVarP(&o.search.Name, "name", "", "", FilterByName.Help())
 	cmd.Flags().StringSliceVarP(&o.search.Labels, "label", "", nil, FilterByLabels.Help())
+	cmd.Flags().BoolVarP(&o.unused, "unused", "", false, "list only secrets not retrieved within --since, or never retrieved")
+	cmd.Flags().BoolVarP(&o.unmodified, "unmodified", "", false, "list only secrets not modified within --since")
+	cmd.Flags().StringVarP(&o.since, "since", "", "", "age used by --unused and --unmodified, e.g., 90d, 2w, 1y")
+	cmd.Flags().StringArrayVarP(&o.attrs, "attr", "", nil, "filter by attribute, as key or key=glob")
+	cmd.Flags().BoolVarP(&o.search.Fuzzy, "fuzzy", "", false, "match the glob argument loosely, ranking the best matches first")
+	cmd.Flags().StringVarP(&o.sort, "sort", "", "", "order secrets by "+strings.Join(findSortOrders, " or "))
+	cmd.Flags().BoolVarP(&o.tree, "tree", "", false, "group secrets under their labels")
 
 	return cmd
 }
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/ladzaretti/migrate"
+	"github.com/spf13/cobra"
+)
+
+type FsckError struct {
+	Err error
+}
+
+func (e *FsckError) Error() string { return "fsck: " + e.Err.Error() }
+
+func (e *FsckError) Unwrap() error { return e.Err }
+
+// FsckOptions holds data required to run the command.
+type FsckOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	repair         bool
+	rollbackSchema int
+	migrations     bool
+}
+
+var _ genericclioptions.CmdOptions = &FsckOptions{}
+
+// NewFsckOptions initializes the options struct.
+func NewFsckOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *FsckOptions {
+	return &FsckOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*FsckOptions) Complete() error { return nil }
+
+func (o *FsckOptions) Validate() error {
+	if o.rollbackSchema < 0 {
+		return errors.New("--rollback-schema must not be negative")
+	}
+
+	if o.rollbackSchema > 0 && o.repair {
+		return errors.New("--rollback-schema and --repair cannot be used together")
+	}
+
+	if o.migrations && (o.rollbackSchema > 0 || o.repair) {
+		return errors.New("--migrations cannot be used with --rollback-schema or --repair")
+	}
+
+	return nil
+}
+
+func (o *FsckOptions) Run(ctx context.Context, _ ...string) (retErr error) {
+	defer func() {
+		if retErr != nil {
+			retErr = &FsckError{retErr}
+			return
+		}
+	}()
+
+	if o.migrations {
+		o.printMigrationPlan(o.migrationPlan)
+		return nil
+	}
+
+	if o.rollbackSchema > 0 {
+		version, err := o.vault.RollbackSchema(ctx, o.rollbackSchema)
+		if err != nil {
+			return err
+		}
+
+		o.persistRequired = true
+
+		o.Infof("vault schema rolled back to version %d\n", version)
+
+		return nil
+	}
+
+	report, err := o.vault.Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	o.Printf("container schema: version %d (latest %d)\n", report.ContainerSchema.Version, report.ContainerSchema.Latest)
+	o.Printf("vault schema: version %d (latest %d)\n", report.VaultSchema.Version, report.VaultSchema.Latest)
+	o.Printf("secrets checked: %d\n", report.Secrets)
+	o.Printf("snapshots checked: %d\n", report.Snapshots)
+
+	st := o.Styler(o.Out)
+	unresolved := 0
+
+	for _, issue := range report.Issues {
+		note, kind := "", st.Error(string(issue.Kind))
+		if issue.Repairable() {
+			kind = st.Warning(string(issue.Kind))
+		}
+
+		if issue.Repairable() && !o.repair {
+			note = " (repairable)"
+		}
+
+		o.Printf("%s: %s%s\n", kind, issue.Detail, note)
+
+		if !issue.Repairable() || !o.repair {
+			unresolved++
+		}
+	}
+
+	if o.repair && len(report.Issues) > 0 {
+		repaired, sealRequired, err := o.vault.Repair(ctx, report)
+		if err != nil {
+			return err
+		}
+
+		o.persistRequired = sealRequired
+		o.containerWritten = repaired > 0
+
+		o.Infof("repaired %d issue(s)\n", repaired)
+	}
+
+	if unresolved > 0 {
+		return fmt.Errorf("%w: %d issue(s) found", vaulterrors.ErrVaultInconsistent, unresolved)
+	}
+
+	return nil
+}
+
+// printMigrationPlan writes the pending migrations of the vault as a table.
+func (o *FsckOptions) printMigrationPlan(plan *vault.MigrationPlan) {
+	if len(plan.Container)+len(plan.Vault) == 0 {
+		o.Printf("no pending migrations\n")
+		return
+	}
+
+	tw := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(tw, "DATABASE\tVERSION\tCHECKSUM\tFIRST LINE")
+
+	for _, p := range []struct {
+		database   string
+		migrations []migrate.PlannedMigration
+	}{
+		{"container", plan.Container},
+		{"vault", plan.Vault},
+	} {
+		for _, m := range p.migrations {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", p.database, m.Index, m.Checksum, m.FirstLine)
+		}
+	}
+
+	_ = tw.Flush()
+}
+
+// NewCmdFsck creates the fsck cobra command.
+func NewCmdFsck(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewFsckOptions(
+		defaults.StdioOptions,
+		defaults.vaultOptions,
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fsck",
+		Short: i18n.T("Verify the integrity of the vault"),
+		Long: `Verify the integrity of the vault.
+
+Checks the schema versions of the vault and its container,
+runs the SQLite integrity check on both databases, verifies that every secret
+decrypts successfully, that every label references an existing secret,
+and that the checksums of the encrypted vault and its history snapshots match.
+
+Use --repair to fix repairable issues: dangling labels are removed,
+the vault checksum is recomputed and corrupted history snapshots are deleted.
+
+Use --rollback-schema to revert the last migrations of the vault schema
+before downgrading to an older vlt release, e.g., after a faulty release.
+Data stored by the reverted migrations is lost, and the migrations
+are applied again the next time this release opens the vault.
+
+Use --migrations to list the schema migrations pending for the vault,
+e.g., after upgrading vlt, without applying them.`,
+		Example: `  # List the schema migrations the next command would apply
+  vlt fsck --migrations
+
+  # Revert the latest vault schema migration, then downgrade vlt
+  vlt fsck --rollback-schema 1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.repair, "repair", "", false, "repair inconsistencies where possible")
+	cmd.Flags().IntVarP(&o.rollbackSchema, "rollback-schema", "", 0, "revert the last `n` vault schema migrations")
+	cmd.Flags().BoolVarP(&o.migrations, planMigrationsFlag, "", false, "list pending schema migrations without applying them")
+
+	return cmd
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+)
+
+// FuzzReadSecrets feeds arbitrary csv files to the importers, which must
+// return an error rather than panic on malformed input.
+func FuzzReadSecrets(f *testing.F) {
+	f.Add([]byte(vltExportHeader+"\nname,736563726574,\"a,b\"\n"), "")
+	f.Add([]byte(vltExportHeader+"\nname,not-hex,\n"), "")
+	f.Add([]byte(vltExportMetaHeader+"\nname,736563726574,a,\"{\"\"a\"\":{\"\"color\"\":\"\"red\"\"}}\"\n"), "")
+	f.Add([]byte(firefoxHeader+"\nhttps://example.com,alice,secret,,,{guid},1,2,3\n"), "")
+	f.Add([]byte(chromiumHeader+"\nexample,https://example.com,alice,secret,note\n"), "")
+	f.Add([]byte("user,pass,url\nalice,secret,https://example.com\n"), `{"name":0,"secret":1,"labels":[2]}`)
+	f.Add([]byte("user,pass\nalice,secret\n"), `{"name":-1,"secret":1}`)
+
+	f.Fuzz(func(t *testing.T, data []byte, indexes string) {
+		o := NewImportOptions(&genericclioptions.StdioOptions{IOStreams: genericclioptions.NewTestIOStreamsDiscard(nil)}, nil)
+
+		o.indexes = indexes
+		o.nonInteractive = true
+
+		if err := o.Complete(); err != nil {
+			return
+		}
+
+		importerFor := func(header []string, preview [][]string) (Importer, error) {
+			return o.importerForHeader(t.Context(), header, preview)
+		}
+
+		_ = readSecrets(o.dialect.reader(bytes.NewReader(data)), importerFor, func(secret) error { return nil })
+	})
+}
+
+// FuzzLoadFileConfig feeds arbitrary config files to the config loader,
+// which must return an error rather than panic on malformed input.
+func FuzzLoadFileConfig(f *testing.F) {
+	defaultConfig, err := os.ReadFile(filepath.Join("..", "assets", "default-config.toml"))
+	if err != nil {
+		f.Fatalf("read default config: %v", err)
+	}
+
+	f.Add(defaultConfig)
+	f.Add([]byte("[vaults.work]\npath = '/vault.db'\n"))
+	f.Add([]byte("[daemon]\nsocket = '@vlt'\n"))
+	f.Add([]byte("[lint.jwt]\ntype = 'jwt'\nnames = ['[']\n"))
+
+	// each fuzzing worker runs the fuzz target in a process of its own.
+	path := filepath.Join(f.TempDir(), "config.toml")
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+
+		_, _ = LoadFileConfig(path)
+	})
+}
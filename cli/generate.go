@@ -7,6 +7,7 @@ import (
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/clipboard"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
 	"github.com/ladzaretti/vlt-cli/randstring"
 
 	"github.com/spf13/cobra"
@@ -62,7 +63,7 @@ func NewCmdGenerate(defaults *DefaultVltOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "generate",
 		Aliases: []string{"gen", "rand"},
-		Short:   "Generate a random password",
+		Short:   i18n.T("Generate a random password"),
 		Long: fmt.Sprintf(`Generate a random password based on the provided character requirements and minimum length.
 
 If no flags are provided, the default policy is:
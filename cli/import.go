@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"bufio"
+	"cmp"
 	"context"
 	"encoding/csv"
 	"encoding/hex"
@@ -8,13 +10,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/otpauth"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+	"github.com/ladzaretti/vlt-cli/xlsx"
 
 	"github.com/spf13/cobra"
 )
@@ -52,14 +62,25 @@ var (
 
 	// vltImporter is a password importer for exported vlt password data.
 	vltImporter = VltImporter{}
+
+	// vltMetaImporter is a password importer for exported vlt password data with label metadata.
+	vltMetaImporter = VltImporter{labelMeta: true}
 )
 
-type VltImporter struct{}
+// VltImporter imports data exported by vlt, optionally including
+// the label_meta column, see [vltExportMetaHeader].
+type VltImporter struct {
+	labelMeta bool
+}
 
 var _ Importer = VltImporter{}
 
-func (VltImporter) validate(record []string) error {
-	if len(record) != 3 {
+func (i VltImporter) validate(record []string) error {
+	if i.labelMeta && len(record) != 4 {
+		return &ImportError{errors.New("expected 4 fields per record for vlt csv record with label metadata")}
+	}
+
+	if !i.labelMeta && len(record) != 3 {
 		return &ImportError{errors.New("expected 3 fields per record for vlt csv record")}
 	}
 
@@ -69,28 +90,73 @@ func (VltImporter) validate(record []string) error {
 // convert converts a CSV record into a secret.
 //
 // It assumes that the input record has already been validated, so it may panic on
-// out-of-bounds access or invalid hex data.
-func (VltImporter) convert(record []string) secret {
+// out-of-bounds access.
+func (i VltImporter) convert(record []string) (secret, error) {
 	s, err := hex.DecodeString(record[1])
 	if err != nil {
-		panic(err)
+		return secret{}, fmt.Errorf("secret %q: %w", record[0], err)
 	}
 
-	return secret{
+	converted := secret{
 		name:   record[0],
 		secret: s,
-		labels: strings.Split(record[2], ","),
+		labels: decodeLabels(record[2]),
+	}
+
+	if i.labelMeta && len(record[3]) > 0 {
+		converted.labelMeta, err = decodeLabelMeta(record[3])
+		if err != nil {
+			securebytes.Wipe(s)
+			return secret{}, fmt.Errorf("secret %q: label_meta: %w", record[0], err)
+		}
+	}
+
+	return converted, nil
+}
+
+// decodeLabels parses a labels column, see [encodeLabels]. Columns of older exports,
+// not quoting labels, are split at commas. Empty labels are skipped.
+func decodeLabels(column string) []string {
+	r := csv.NewReader(strings.NewReader(column))
+	r.LazyQuotes = true
+
+	labels, err := r.Read()
+	if err != nil {
+		labels = strings.Split(column, ",")
 	}
+
+	return slices.DeleteFunc(labels, func(l string) bool { return len(l) == 0 })
+}
+
+// decodeLabelMeta parses a label_meta column, see [encodeLabelMeta].
+func decodeLabelMeta(column string) ([]vaultdb.LabelMeta, error) {
+	var m map[string]exportedLabelMeta
+	if err := json.Unmarshal([]byte(column), &m); err != nil {
+		return nil, err
+	}
+
+	meta := make([]vaultdb.LabelMeta, 0, len(m))
+
+	for _, name := range slices.Sorted(maps.Keys(m)) {
+		if err := validateLabelColor(m[name].Color); err != nil {
+			return nil, err
+		}
+
+		meta = append(meta, vaultdb.LabelMeta{Name: name, Color: m[name].Color, Icon: m[name].Icon})
+	}
+
+	return meta, nil
 }
 
 type secret struct {
-	name   string
-	secret []byte
-	labels []string
+	name      string
+	secret    []byte
+	labels    []string
+	labelMeta []vaultdb.LabelMeta
 }
 
 type Importer interface {
-	convert(record []string) secret
+	convert(record []string) (secret, error)
 	validate(record []string) error
 }
 
@@ -112,16 +178,16 @@ func (ic CustomImporter) validate(record []string) error {
 		return errors.New("secret index is not set")
 	}
 
-	if *ic.NameIndex >= len(record) {
+	if *ic.NameIndex < 0 || *ic.NameIndex >= len(record) {
 		return fmt.Errorf("name index %d is out of range (record has %d columns)", *ic.NameIndex, len(record))
 	}
 
-	if *ic.SecretIndex >= len(record) {
+	if *ic.SecretIndex < 0 || *ic.SecretIndex >= len(record) {
 		return fmt.Errorf("secret index %d is out of range (record has %d columns)", *ic.SecretIndex, len(record))
 	}
 
 	for _, index := range ic.LabelIndexes {
-		if index >= len(record) {
+		if index < 0 || index >= len(record) {
 			return fmt.Errorf("label index %d is out of range (record has %d columns)", index, len(record))
 		}
 	}
@@ -129,7 +195,7 @@ func (ic CustomImporter) validate(record []string) error {
 	return nil
 }
 
-func (ic CustomImporter) convert(record []string) secret {
+func (ic CustomImporter) convert(record []string) (secret, error) {
 	// safe to dereference since validate is expected to run first.
 	s := secret{
 		name:   record[*ic.NameIndex],
@@ -144,7 +210,7 @@ func (ic CustomImporter) convert(record []string) secret {
 		}
 	}
 
-	return s
+	return s, nil
 }
 
 func (ic CustomImporter) String() string {
@@ -165,9 +231,15 @@ type ImportOptions struct {
 	*genericclioptions.StdioOptions
 	*VaultOptions
 
-	indexes string
+	indexes        string
+	mapping        string
+	delimiter      string
+	quote          string
+	nonInteractive bool // nonInteractive disables the import wizard, see [ImportOptions.importWizard].
 
-	importConfig CustomImporter
+	importConfig  CustomImporter
+	columnMapping *ColumnMapping // columnMapping is set by --map, resolved once the header is read.
+	dialect       csvDialect
 }
 
 var _ genericclioptions.CmdOptions = &ImportOptions{}
@@ -187,10 +259,30 @@ func (o *ImportOptions) Complete() error {
 		}
 	}
 
+	if len(o.mapping) > 0 {
+		o.columnMapping = &ColumnMapping{}
+		if err := json.Unmarshal([]byte(o.mapping), o.columnMapping); err != nil {
+			return &ImportError{fmt.Errorf("--map: %w", err)}
+		}
+	}
+
+	dialect, err := parseCSVDialect(cmp.Or(o.delimiter, ","), cmp.Or(o.quote, `"`))
+	if err != nil {
+		return &ImportError{err}
+	}
+
+	o.dialect = dialect
+
 	return nil
 }
 
-func (*ImportOptions) Validate() error { return nil }
+func (o *ImportOptions) Validate() error {
+	if len(o.indexes) > 0 && len(o.mapping) > 0 {
+		return &ImportError{errors.New("--indexes and --map are mutually exclusive")}
+	}
+
+	return nil
+}
 
 func (o *ImportOptions) Run(ctx context.Context, files ...string) (retErr error) {
 	defer func() {
@@ -220,44 +312,147 @@ func (o *ImportOptions) Run(ctx context.Context, files ...string) (retErr error)
 }
 
 func (o *ImportOptions) importSecrets(ctx context.Context, in io.Reader) error {
-	r := csv.NewReader(in)
+	br := bufio.NewReader(in)
+
+	// otp keys are not csv files, they are detected by their first bytes.
+	head, _ := br.Peek(otpDetectLen)
+	if format := otpauth.Detect(head); len(format) > 0 {
+		return o.importOTPKeys(ctx, br, format)
+	}
+
+	// xlsx files are zip archives, read through the same importers as csv files.
+	records := o.dialect.reader(br)
+
+	if xlsx.Detect(head) {
+		o.Infof("xlsx file detected, reading its first sheet\n")
+
+		r, err := xlsxReader(br)
+		if err != nil {
+			return err
+		}
+
+		records = r
+	}
+
+	var (
+		secrets   []vault.NewSecret
+		labelMeta []vaultdb.LabelMeta
+	)
+
+	defer func() {
+		for _, s := range secrets {
+			securebytes.Wipe(s.Value)
+		}
+	}()
+
+	importerFor := func(header []string, preview [][]string) (Importer, error) {
+		return o.importerForHeader(ctx, header, preview)
+	}
+
+	// the records are inserted at once, in a single transaction.
+	err := readSecrets(records, importerFor, func(s secret) error {
+		secrets = append(secrets, vault.NewSecret{Name: s.name, Value: s.secret, Labels: s.labels})
+		labelMeta = append(labelMeta, s.labelMeta...)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := o.vault.InsertNewSecrets(ctx, secrets); err != nil {
+		return err
+	}
+
+	for _, m := range labelMeta {
+		if err := o.vault.SetLabelMeta(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	o.Infof("successfully imported %d records\n", len(secrets))
+
+	return nil
+}
 
+// readSecrets reads the csv records of r, converting them to secrets using the
+// importer returned by importerFor for the header, and calls yield for each.
+// importerFor is also given up to [previewRows] records following the header.
+func readSecrets(r recordReader, importerFor func(header []string, preview [][]string) (Importer, error), yield func(secret) error) error {
 	header, err := r.Read()
 	if err != nil {
 		return err
 	}
 
-	importer := o.importerForHeader(strings.Join(header, ","))
+	preview := make([][]string, 0, previewRows)
+	defer func() { //nolint:wsl_v5
+		for _, record := range preview {
+			clear(record)
+		}
+	}()
+
+	var readErr error
+
+	for len(preview) < previewRows {
+		record, err := r.Read()
+		if err != nil {
+			readErr = err
+			break
+		}
+
+		preview = append(preview, record)
+	}
+
+	importer, err := importerFor(header, preview)
+	if err != nil {
+		return err
+	}
+
 	if err := importer.validate(header); err != nil {
 		return err
 	}
 
-	i := 0
-	for {
-		record, err := r.Read()
+	next := func() ([]string, error) {
+		if len(preview) > 0 {
+			record := preview[0]
+			preview = preview[1:]
+
+			return record, nil
+		}
+
+		if readErr != nil {
+			return nil, readErr
+		}
+
+		return r.Read()
+	}
+
+	for i := 1; ; i++ {
+		record, err := next()
 		if err == io.EOF {
-			break
+			return nil
 		}
 
 		if err != nil {
 			return err
 		}
 
-		s := importer.convert(record)
-
-		if _, err := o.vault.InsertNewSecret(ctx, s.name, s.secret, s.labels); err != nil {
-			return err
+		// records are validated too, convert may not be safe otherwise.
+		if err := importer.validate(record); err != nil {
+			return fmt.Errorf("record %d: %w", i, err)
 		}
 
+		s, err := importer.convert(record)
 		clear(record)
-		clear(s.secret)
-
-		i++
-	}
 
-	o.Infof("successfully imported %d records\n", i)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		if err := yield(s); err != nil {
+			return err
+		}
+	}
 }
 
 func (o *ImportOptions) importFromFile(ctx context.Context, name string) error {
@@ -271,28 +466,69 @@ func (o *ImportOptions) importFromFile(ctx context.Context, name string) error {
 
 	o.Infof("importing secrets from: %q\n", name)
 
+	// tab separated files are detected by their extension, unless --delimiter is given.
+	if len(o.delimiter) == 0 && strings.EqualFold(filepath.Ext(name), ".tsv") {
+		dialect, err := parseCSVDialect(`\t`, cmp.Or(o.quote, `"`))
+		if err != nil {
+			return err
+		}
+
+		dialect.lazyQuotes = true
+		o.dialect = dialect
+	}
+
 	return o.importSecrets(ctx, f)
 }
 
 //nolint:ireturn
-func (o *ImportOptions) importerForHeader(header string) Importer {
-	switch header {
+func (o *ImportOptions) importerForHeader(ctx context.Context, header []string, preview [][]string) (Importer, error) {
+	switch strings.Join(header, ",") {
 	case firefoxHeader:
 		o.Infof("firefox export file detected\n")
-		return firefoxImporter
+		return firefoxImporter, nil
 
 	case chromiumHeader:
 		o.Infof("chromium export file detected\n")
-		return chromiumImporter
+		return chromiumImporter, nil
 
 	case vltExportHeader:
 		o.Infof("vlt export file detected\n")
-		return vltImporter
+		return vltImporter, nil
 
-	default:
-		o.Debugf("using custom import config: %s\n", o.importConfig)
-		return o.importConfig
+	case vltExportMetaHeader:
+		o.Infof("vlt export file with label metadata detected\n")
+		return vltMetaImporter, nil
+	}
+
+	if o.columnMapping != nil {
+		importer, err := o.columnMapping.importer(header)
+		if err != nil {
+			return nil, fmt.Errorf("--map: %w", err)
+		}
+
+		o.Debugf("using custom import config: %s\n", importer)
+
+		return importer, nil
+	}
+
+	if len(o.indexes) == 0 {
+		if o.StdinIsPiped || o.nonInteractive {
+			return nil, fmt.Errorf("unrecognized CSV header %q, set its columns using --map or --indexes", header)
+		}
+
+		importer, err := o.importWizard(ctx, header, preview)
+		if err != nil {
+			return nil, err
+		}
+
+		o.Debugf("using custom import config: %s\n", importer)
+
+		return importer, nil
 	}
+
+	o.Debugf("using custom import config: %s\n", o.importConfig)
+
+	return o.importConfig, nil
 }
 
 // NewCmdImport creates the import cobra command.
@@ -304,7 +540,7 @@ func NewCmdImport(defaults *DefaultVltOptions) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "import [file]",
-		Short: "Import secrets from file (supports Firefox, Chromium, and custom formats)",
+		Short: i18n.T("Import secrets from file (supports Firefox, Chromium, and custom formats)"),
 		Args:  cobra.ArbitraryArgs,
 		Long: `Import secrets into the vault from a CSV file.
 
@@ -313,8 +549,28 @@ Additional columns can be used for optional labels.
 
 Use the --indexes flag to specify how to extract each field. 
 Indexes are zero-based and refer to column positions in the header row.
+Alternatively, use the --map flag to refer to the columns by their names in the header row.
+
+Fields are separated by commas and quoted using double quotes, unless
+set otherwise by --delimiter, e.g., ';', or '\t' for tabs, and by --quote.
+Files with the .tsv extension are tab separated.
+
+Excel (.xlsx) files are detected by their content, the rows of their first sheet
+are imported the same way, with the first row as the header. Cells are read as
+stored, e.g., dates as serial numbers.
 
 Firefox and Chromium-based CSV files are auto-detected for import and do not require manual index specification.
+
+Files of other formats, imported from a terminal without --indexes or --map, enter an
+interactive mode showing their first rows, to assign the columns to the name, secret and
+labels before importing. The equivalent --map flag is printed for later imports.
+Use --no-interactive to fail instead.
+
+One-time password keys are auto-detected as well, from otpauth:// URIs, one per line,
+and from unencrypted Aegis and andOTP backups. TOTP keys are imported as secrets named
+totp/<issuer>:<account> and labeled totp, holding the base32 encoded key, with their
+issuer, account, algorithm, digits and period stored as attributes.
+Keys of other types, e.g., HOTP, are skipped.
 `,
 		Example: `  # Import secrets from a file (format is auto-detected if compatible)
   vlt import passwords.csv
@@ -322,13 +578,34 @@ Firefox and Chromium-based CSV files are auto-detected for import and do not req
   # Import from custom CSV data using a column mapping
   echo -e "password,username,label_1,label_2\npass,some_username,meta1,meta2" | \
     vlt import \
-        --indexes '{"name":1,"secret":0,"labels":[2,3]}'`,
+        --indexes '{"name":1,"secret":0,"labels":[2,3]}'
+
+  # Import from custom CSV data using the column names of its header
+  vlt import passwords.csv \
+    --map '{"name":"username","secret":"password","labels":["url","folder"]}'
+
+  # Import the first sheet of a spreadsheet, or a tab separated file
+  vlt import passwords.xlsx --map '{"name":"Login","secret":"Password"}'
+  vlt import passwords.tsv --indexes '{"name":0,"secret":1}'
+
+  # Import from semicolon separated data quoting using single quotes
+  vlt import passwords.csv --delimiter ';' --quote "'" --map '{"name":"user","secret":"pass"}'
+
+  # Import the TOTP keys of an Aegis backup, or of otpauth:// URIs
+  vlt import aegis-backup.json
+  echo "otpauth://totp/ACME:alice?secret=JBSWY3DPEHPK3PXP" | vlt import`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
 		},
 	}
 
 	cmd.Flags().StringVarP(&o.indexes, "indexes", "i", "", "json with column indexes (e.g., '{\"name\":0,\"secret\":1,\"labels\":[2]}')")
+	cmd.Flags().StringVarP(&o.mapping, "map", "", "", "json with column names of the header row (e.g., '{\"name\":\"username\",\"secret\":\"password\",\"labels\":[\"url\"]}')")
+	cmd.Flags().StringVarP(&o.delimiter, "delimiter", "", "", "field delimiter, a single character, or '\\t' for tabs (default ',', or tabs for .tsv files)")
+	cmd.Flags().StringVarP(&o.quote, "quote", "", `"`, "quote character, a single ASCII character")
+	cmd.Flags().BoolVarP(&o.nonInteractive, "no-interactive", "N", false, "fail on an unrecognized header instead of prompting for its columns")
+
+	cmd.MarkFlagsMutuallyExclusive("indexes", "map")
 
 	return cmd
 }
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ladzaretti/vlt-cli/xlsx"
+)
+
+// maxXLSXSize is the maximal size of imported xlsx files.
+const maxXLSXSize = 32 << 20
+
+// ColumnMapping defines the columns used to extract fields from a CSV row by their header names,
+// resolved to a [CustomImporter] once the header is read.
+type ColumnMapping struct {
+	Name   string   `json:"name"`             // Name is the header of the name column.
+	Secret string   `json:"secret"`           // Secret is the header of the secret column.
+	Labels []string `json:"labels,omitempty"` // Labels are the headers of the label columns.
+}
+
+// importer resolves the mapped header names to the indexes of their columns.
+//
+// Header names are compared after trimming surrounding white space,
+// and a byte order mark of the first one, e.g., of CSV files saved by spreadsheets.
+func (m ColumnMapping) importer(header []string) (CustomImporter, error) {
+	if len(m.Name) == 0 {
+		return CustomImporter{}, errors.New("name column is not set")
+	}
+
+	if len(m.Secret) == 0 {
+		return CustomImporter{}, errors.New("secret column is not set")
+	}
+
+	names := headerNames(header)
+
+	index := func(column string) (int, error) {
+		i := slices.Index(names, column)
+		if i < 0 {
+			return 0, fmt.Errorf("column %q not found in the header %q", column, names)
+		}
+
+		if slices.Index(names[i+1:], column) >= 0 {
+			return 0, fmt.Errorf("column %q appears more than once in the header", column)
+		}
+
+		return i, nil
+	}
+
+	nameIndex, err := index(m.Name)
+	if err != nil {
+		return CustomImporter{}, err
+	}
+
+	secretIndex, err := index(m.Secret)
+	if err != nil {
+		return CustomImporter{}, err
+	}
+
+	importer := CustomImporter{
+		NameIndex:    &nameIndex,
+		SecretIndex:  &secretIndex,
+		LabelIndexes: make([]int, 0, len(m.Labels)),
+	}
+
+	for _, label := range m.Labels {
+		i, err := index(label)
+		if err != nil {
+			return CustomImporter{}, err
+		}
+
+		importer.LabelIndexes = append(importer.LabelIndexes, i)
+	}
+
+	return importer, nil
+}
+
+// headerNames returns the column names of header, trimmed as described in [ColumnMapping.importer].
+func headerNames(header []string) []string {
+	names := make([]string, len(header))
+	for i, h := range header {
+		names[i] = strings.TrimSpace(h)
+	}
+
+	if len(names) > 0 {
+		names[0] = strings.TrimPrefix(names[0], "\ufeff")
+	}
+
+	return names
+}
+
+// recordReader reads the records of a CSV file, see [csv.Reader.Read].
+type recordReader interface {
+	Read() ([]string, error)
+}
+
+// csvDialect describes the field delimiter and quote character of a CSV file.
+type csvDialect struct {
+	delimiter  rune
+	quote      byte
+	lazyQuotes bool // lazyQuotes allows quotes in unquoted fields, e.g., of TSV files, see [csv.Reader.LazyQuotes].
+}
+
+// parseCSVDialect parses the --delimiter and --quote flags. The delimiter
+// is a single character, or \t for tabs, the quote a single ASCII character.
+func parseCSVDialect(delimiter string, quote string) (csvDialect, error) {
+	if delimiter == `\t` {
+		delimiter = "\t"
+	}
+
+	d, n := utf8.DecodeRuneInString(delimiter)
+	if n == 0 || n != len(delimiter) || d == utf8.RuneError || d == '\r' || d == '\n' {
+		return csvDialect{}, fmt.Errorf("invalid delimiter %q: a single character is expected", delimiter)
+	}
+
+	if len(quote) != 1 || quote[0] >= utf8.RuneSelf || quote[0] == '\r' || quote[0] == '\n' {
+		return csvDialect{}, fmt.Errorf("invalid quote %q: a single ASCII character is expected", quote)
+	}
+
+	if d == rune(quote[0]) {
+		return csvDialect{}, fmt.Errorf("delimiter and quote must differ, both are %q", delimiter)
+	}
+
+	return csvDialect{delimiter: d, quote: quote[0]}, nil
+}
+
+// reader returns a reader of the CSV records of in.
+//
+// encoding/csv only quotes using '"'. Another quote character is swapped
+// with '"' in the input, and back in the fields read.
+//
+//nolint:ireturn
+func (d csvDialect) reader(in io.Reader) recordReader {
+	if d.quote == '"' {
+		r := csv.NewReader(in)
+		r.Comma = d.delimiter
+		r.LazyQuotes = d.lazyQuotes
+
+		return r
+	}
+
+	r := csv.NewReader(&swapReader{r: in, a: d.quote, b: '"'})
+	r.Comma = d.delimiter
+	r.LazyQuotes = d.lazyQuotes
+
+	// swapped with the quote character as well.
+	if d.delimiter == '"' {
+		r.Comma = rune(d.quote)
+	}
+
+	return &swappedRecordReader{r: r, a: d.quote, b: '"'}
+}
+
+// xlsxReader returns a reader of the rows of the first sheet of the xlsx file read from in,
+// at most [maxXLSXSize] bytes.
+//
+//nolint:ireturn
+func xlsxReader(in io.Reader) (recordReader, error) {
+	data, err := io.ReadAll(io.LimitReader(in, maxXLSXSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxXLSXSize {
+		return nil, fmt.Errorf("xlsx file exceeds %d MiB", maxXLSXSize>>20)
+	}
+
+	return xlsx.NewReader(bytes.NewReader(data), int64(len(data)))
+}
+
+// swapReader swaps the bytes a and b in the data read from r.
+type swapReader struct {
+	r    io.Reader
+	a, b byte
+}
+
+func (s *swapReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	swapBytes(p[:n], s.a, s.b)
+
+	return n, err
+}
+
+// swappedRecordReader swaps the bytes a and b back in the fields read from r.
+type swappedRecordReader struct {
+	r    *csv.Reader
+	a, b byte
+}
+
+func (s *swappedRecordReader) Read() ([]string, error) {
+	record, err := s.r.Read()
+	for i, field := range record {
+		b := []byte(field)
+		swapBytes(b, s.a, s.b)
+		record[i] = string(b)
+	}
+
+	return record, err
+}
+
+func swapBytes(p []byte, a byte, b byte) {
+	for i, c := range p {
+		switch c {
+		case a:
+			p[i] = b
+		case b:
+			p[i] = a
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ladzaretti/vlt-cli/otpauth"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+)
+
+const (
+	// totpLabel is the label of the secrets holding TOTP keys.
+	totpLabel = "totp"
+
+	// totpNamePrefix prefixes the issuer:account label in the secret name of a TOTP key.
+	totpNamePrefix = "totp/"
+
+	// otpDetectLen is the length of the input prefix otp key formats are detected by.
+	otpDetectLen = 512
+)
+
+// TOTP key attribute names.
+const (
+	totpAttrIssuer    = "issuer"
+	totpAttrAccount   = "account"
+	totpAttrAlgorithm = "algorithm"
+	totpAttrDigits    = "digits"
+	totpAttrPeriod    = "period"
+)
+
+// importOTPKeys imports the TOTP keys of otpauth:// URIs, or of an Aegis or andOTP backup.
+//
+// Each key is a secret labeled [totpLabel] holding the base32 encoded key,
+// its issuer, account and parameters are stored as attributes.
+// Keys of other types, e.g., hotp, are skipped.
+func (o *ImportOptions) importOTPKeys(ctx context.Context, in io.Reader, format otpauth.Format) error {
+	o.Infof("%s otp keys detected\n", format)
+
+	data, err := io.ReadAll(in)
+	defer securebytes.Wipe(data)
+
+	if err != nil {
+		return err
+	}
+
+	keys, err := otpauth.Parse(format, data)
+	if err != nil {
+		return err
+	}
+
+	imported, skipped := 0, 0
+
+	for _, k := range keys {
+		if k.Type != otpauth.TypeTOTP {
+			o.Debugf("skipping %q: %s keys are not supported\n", k.Label(), k.Type)
+
+			skipped++
+
+			continue
+		}
+
+		if err := o.importTOTPKey(ctx, k); err != nil {
+			return fmt.Errorf("key %q: %w", k.Label(), err)
+		}
+
+		imported++
+	}
+
+	o.Infof("successfully imported %d records\n", imported)
+
+	if skipped > 0 {
+		o.Infof("skipped %d keys that are not totp keys\n", skipped)
+	}
+
+	return nil
+}
+
+func (o *ImportOptions) importTOTPKey(ctx context.Context, k otpauth.Key) error {
+	id, err := o.vault.InsertNewSecret(ctx, totpNamePrefix+k.Label(), []byte(k.Secret), []string{totpLabel})
+	if err != nil {
+		return err
+	}
+
+	attrs := map[string]string{
+		totpAttrAlgorithm: k.Algorithm,
+		totpAttrDigits:    strconv.Itoa(k.Digits),
+		totpAttrPeriod:    strconv.Itoa(k.Period),
+	}
+
+	if len(k.Issuer) > 0 {
+		attrs[totpAttrIssuer] = k.Issuer
+	}
+
+	if len(k.Account) > 0 {
+		attrs[totpAttrAccount] = k.Account
+	}
+
+	return o.vault.UpdateSecretAttributes(ctx, id, attrs, nil)
+}
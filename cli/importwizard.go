@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ladzaretti/vlt-cli/input"
+)
+
+const (
+	// previewRows is the number of records shown by the import wizard.
+	previewRows = 3
+
+	// previewValueLen is the length values are truncated to in the import preview.
+	previewValueLen = 24
+
+	// wizardAttempts is the number of attempts to enter a valid column.
+	wizardAttempts = 3
+)
+
+// errImportAborted is returned if the user declined the mapping of the import wizard.
+var errImportAborted = errors.New("import aborted")
+
+// importWizard lets the user assign the columns of a CSV file of an unknown format
+// to the name, secret and labels, showing its header and first records.
+// The mapping is printed as the equivalent --map flag for later imports.
+func (o *ImportOptions) importWizard(ctx context.Context, header []string, preview [][]string) (CustomImporter, error) {
+	p := o.Prompter()
+
+	p.Notify(ctx, "unrecognized CSV header, assign its columns to the secret fields:\n"+formatImportPreview(header, preview))
+
+	name, err := promptColumn(ctx, p, header, "Name column: ")
+	if err != nil {
+		return CustomImporter{}, err
+	}
+
+	secret, err := promptColumn(ctx, p, header, "Secret column: ")
+	if err != nil {
+		return CustomImporter{}, err
+	}
+
+	labels, err := promptLabelColumns(ctx, p, header)
+	if err != nil {
+		return CustomImporter{}, err
+	}
+
+	m := ColumnMapping{Name: name, Secret: secret, Labels: labels}
+
+	// duplicate header names are rejected alike by --map.
+	importer, err := m.importer(header)
+	if err != nil {
+		return CustomImporter{}, err
+	}
+
+	flag, err := json.Marshal(m)
+	if err != nil {
+		return CustomImporter{}, err
+	}
+
+	p.Notify(ctx, fmt.Sprintf("to import files of this format without prompting, use --map '%s'", flag))
+
+	yes, err := confirm(ctx, p, "Import using this mapping? (y/N): ")
+	if err != nil {
+		return CustomImporter{}, err
+	}
+
+	if !yes {
+		return CustomImporter{}, errImportAborted
+	}
+
+	return importer, nil
+}
+
+// promptColumn prompts for a column, by its index or header name,
+// and returns its header name.
+func promptColumn(ctx context.Context, p input.Prompter, header []string, prompt string) (string, error) {
+	for range wizardAttempts {
+		response, err := input.PromptRead(ctx, p, prompt)
+		if err != nil {
+			return "", err
+		}
+
+		column, err := resolveColumn(header, response)
+		if err == nil {
+			return column, nil
+		}
+
+		p.Notify(ctx, err.Error())
+	}
+
+	return "", errors.New("no valid column entered")
+}
+
+// promptLabelColumns prompts for the comma separated label columns, none if empty.
+func promptLabelColumns(ctx context.Context, p input.Prompter, header []string) ([]string, error) {
+outer:
+	for range wizardAttempts {
+		response, err := input.PromptRead(ctx, p, "Label columns (comma separated, empty for none): ")
+		if err != nil {
+			return nil, err
+		}
+
+		var labels []string
+
+		for c := range strings.SplitSeq(response, ",") {
+			if len(strings.TrimSpace(c)) == 0 {
+				continue
+			}
+
+			column, err := resolveColumn(header, c)
+			if err != nil {
+				p.Notify(ctx, err.Error())
+				continue outer
+			}
+
+			labels = append(labels, column)
+		}
+
+		return labels, nil
+	}
+
+	return nil, errors.New("no valid columns entered")
+}
+
+// resolveColumn returns the header name of the column s refers to,
+// by its zero-based index, as shown by the preview, or by its header name.
+func resolveColumn(header []string, s string) (string, error) {
+	s = strings.TrimSpace(s)
+	names := headerNames(header)
+
+	if i, err := strconv.Atoi(s); err == nil {
+		if i < 0 || i >= len(names) {
+			return "", fmt.Errorf("column %d is out of range, the file has %d columns", i, len(names))
+		}
+
+		return names[i], nil
+	}
+
+	if slices.Contains(names, s) {
+		return s, nil
+	}
+
+	return "", fmt.Errorf("no column %q, enter its number or header name", s)
+}
+
+// formatImportPreview formats the columns of the header and their values
+// of the preview records, truncated to [previewValueLen] characters.
+func formatImportPreview(header []string, preview [][]string) string {
+	var sb strings.Builder
+
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "#\tCOLUMN\tFIRST VALUES\n")
+
+	for i, h := range headerNames(header) {
+		values := make([]string, 0, len(preview))
+
+		for _, record := range preview {
+			if i < len(record) {
+				values = append(values, truncateRunes(record[i], previewValueLen))
+			}
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", i, h, strings.Join(values, " | "))
+	}
+
+	_ = tw.Flush()
+
+	return sb.String()
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/style"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+
+	"github.com/spf13/cobra"
+)
+
+// maxLabelIconLen bounds the length of a label icon, in runes.
+const maxLabelIconLen = 4
+
+var ErrInvalidLabelColor = errors.New("invalid label color")
+
+// validateLabelColor verifies that c is empty or one of the supported label colors.
+func validateLabelColor(c string) error {
+	if c == "" {
+		return nil
+	}
+
+	if err := style.ValidateColor(c); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidLabelColor, err)
+	}
+
+	return nil
+}
+
+// formatLabels joins labels for display, prefixing each with its icon
+// and wrapping it in its color, if styled output is enabled.
+func formatLabels(labels []string, meta map[string]vaultdb.LabelMeta, st *style.Styler) string {
+	formatted := make([]string, len(labels))
+
+	for i, l := range labels {
+		m, ok := meta[l]
+		if !ok {
+			formatted[i] = l
+			continue
+		}
+
+		if m.Icon != "" {
+			l = m.Icon + " " + l
+		}
+
+		formatted[i] = st.Color(m.Color, l)
+	}
+
+	return strings.Join(formatted, ",")
+}
+
+// labelsMeta returns the label display metadata used to render secret tables.
+//
+// The metadata is cosmetic, if it cannot be loaded, labels are rendered as is.
+func (o *VaultOptions) labelsMeta(ctx context.Context) map[string]vaultdb.LabelMeta {
+	meta, err := o.secretSearcher().LabelsMeta(ctx)
+	if err != nil {
+		return nil
+	}
+
+	return meta
+}
+
+type LabelError struct {
+	Err error
+}
+
+func (e *LabelError) Error() string { return "label: " + e.Err.Error() }
+
+func (e *LabelError) Unwrap() error { return e.Err }
+
+// UpdateLabelOptions holds data required to run the command.
+type UpdateLabelOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	color string
+	icon  string
+	clear bool
+}
+
+var _ genericclioptions.CmdOptions = &UpdateLabelOptions{}
+
+// NewUpdateLabelOptions initializes the options struct.
+func NewUpdateLabelOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *UpdateLabelOptions {
+	return &UpdateLabelOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*UpdateLabelOptions) Complete() error { return nil }
+
+func (o *UpdateLabelOptions) Validate() error {
+	if o.clear && (o.color != "" || o.icon != "") {
+		return &LabelError{errors.New("--clear cannot be combined with --color or --icon")}
+	}
+
+	if !o.clear && o.color == "" && o.icon == "" {
+		return &LabelError{errors.New("specify at least one of --color, --icon, or --clear")}
+	}
+
+	if err := validateLabelColor(o.color); err != nil {
+		return &LabelError{err}
+	}
+
+	if n := len([]rune(o.icon)); n > maxLabelIconLen {
+		return &LabelError{fmt.Errorf("icon is too long (%d characters, maximum %d)", n, maxLabelIconLen)}
+	}
+
+	return nil
+}
+
+func (o *UpdateLabelOptions) Run(ctx context.Context, args ...string) error {
+	name := args[0]
+
+	meta := vaultdb.LabelMeta{Name: name}
+
+	if !o.clear {
+		current := o.labelsMeta(ctx)[name]
+		meta.Color, meta.Icon = cmp.Or(o.color, current.Color), cmp.Or(o.icon, current.Icon)
+	}
+
+	if err := o.vault.SetLabelMeta(ctx, meta); err != nil {
+		return &LabelError{err}
+	}
+
+	return nil
+}
+
+// NewCmdUpdateLabel creates the update label cobra command.
+func NewCmdUpdateLabel(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewUpdateLabelOptions(
+		defaults.StdioOptions,
+		defaults.vaultOptions,
+	)
+
+	cmd := &cobra.Command{
+		Use:   "label <name>",
+		Short: i18n.T("Set the display color and icon of a label"),
+		Long: fmt.Sprintf(`Set the display color and icon of a label.
+
+The color and icon are shown wherever the label is listed, e.g., by 'vlt find'.
+Colors are only used when writing to a terminal, NO_COLOR is not set
+and --no-color is not used.
+
+Supported colors: %s.`, strings.Join(style.ColorNames(), ", ")),
+		Example: `  # Show the "work" label in red
+  vlt update label work --color red
+
+  # Prefix the "bank" label with an icon
+  vlt update label bank --icon 💰
+
+  # Remove the display metadata of a label
+  vlt update label work --clear`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.color, "color", "", "", "label color")
+	cmd.Flags().StringVarP(&o.icon, "icon", "", "", "short symbol shown before the label")
+	cmd.Flags().BoolVarP(&o.clear, "clear", "", false, "remove the label color and icon")
+
+	return cmd
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+)
+
+// ErrLintRejected is returned when a secret value fails a lint rule, see [LintRuleConfig].
+var ErrLintRejected = errors.New("secret value rejected by lint rule")
+
+// Lint rule actions.
+const (
+	lintActionReject = "reject"
+	lintActionWarn   = "warn"
+)
+
+// lintTypes holds the builtin value types of lint rules, keyed by name.
+var lintTypes = map[string]func(value []byte) error{
+	"aws-access-key": lintAWSAccessKey,
+	"jwt":            lintJWT,
+	"pem":            lintPEM,
+}
+
+// awsAccessKeyRE matches AWS access key ids, long-term (AKIA) or temporary (ASIA).
+var awsAccessKeyRE = regexp.MustCompile(`^(AKIA|ASIA)[A-Z2-7]{16}$`)
+
+// validate verifies that the rule sets either a known type or a valid pattern,
+// and that its name patterns and action are valid.
+func (r LintRuleConfig) validate() error {
+	switch {
+	case len(r.Type) > 0 && len(r.Pattern) > 0:
+		return errors.New("only one of 'type' or 'pattern' can be set")
+	case len(r.Type) > 0:
+		if _, ok := lintTypes[r.Type]; !ok {
+			return fmt.Errorf("unknown type %q: expected one of: %s", r.Type, strings.Join(slices.Sorted(maps.Keys(lintTypes)), ", "))
+		}
+	case len(r.Pattern) > 0:
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("pattern: %w", err)
+		}
+	default:
+		return errors.New("either 'type' or 'pattern' must be set")
+	}
+
+	for _, p := range r.Names {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("names: %q: %w", p, err)
+		}
+	}
+
+	switch r.Action {
+	case "", lintActionReject, lintActionWarn:
+	default:
+		return fmt.Errorf("unknown action %q: expected %s or %s", r.Action, lintActionReject, lintActionWarn)
+	}
+
+	return nil
+}
+
+// appliesTo reports whether the rule applies to a secret, by its name or labels.
+// Rules without names or labels apply to all secrets.
+func (r LintRuleConfig) appliesTo(name string, labels []string) bool {
+	if len(r.Names) == 0 && len(r.Labels) == 0 {
+		return true
+	}
+
+	for _, p := range r.Names {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+
+	return slices.ContainsFunc(labels, func(l string) bool { return slices.Contains(r.Labels, l) })
+}
+
+// check verifies the value against the type or pattern of the rule.
+func (r LintRuleConfig) check(value []byte) error {
+	if len(r.Type) > 0 {
+		return lintTypes[r.Type](value)
+	}
+
+	// validated on load, see [LintRuleConfig.validate].
+	if !regexp.MustCompile(r.Pattern).Match(value) {
+		return fmt.Errorf("does not match %q", r.Pattern)
+	}
+
+	return nil
+}
+
+// lintSecret checks a secret value against the lint rules applying to it.
+//
+// Failed warn rules are reported on stderr, failed reject rules
+// return an error wrapping [ErrLintRejected].
+func (o *VaultOptions) lintSecret(io *genericclioptions.StdioOptions, name string, labels []string, value []byte) error {
+	if o.skipLint {
+		return nil
+	}
+
+	for _, ruleName := range slices.Sorted(maps.Keys(o.lintRules)) {
+		r := o.lintRules[ruleName]
+		if !r.appliesTo(name, labels) {
+			continue
+		}
+
+		err := r.check(value)
+		if err == nil {
+			continue
+		}
+
+		if r.Action == lintActionWarn {
+			io.Errorf("secret %q: lint rule %q: %v\n", name, ruleName, err)
+			continue
+		}
+
+		return fmt.Errorf("%w %q: secret %q: %v, use --skip-lint to save it anyway", ErrLintRejected, ruleName, name, err)
+	}
+
+	return nil
+}
+
+func lintAWSAccessKey(value []byte) error {
+	if !awsAccessKeyRE.Match(value) {
+		return errors.New("not an AWS access key id, expected AKIA or ASIA followed by 16 upper case letters or digits")
+	}
+
+	return nil
+}
+
+func lintJWT(value []byte) error {
+	parts := strings.Split(string(value), ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("not a JWT, expected 3 dot separated parts, got %d", len(parts))
+	}
+
+	for i, name := range []string{"header", "payload"} {
+		b, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			return fmt.Errorf("not a JWT, invalid %s encoding: %w", name, err)
+		}
+
+		if !json.Valid(b) || !bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+			return fmt.Errorf("not a JWT, the %s is not a JSON object", name)
+		}
+	}
+
+	if _, err := base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return fmt.Errorf("not a JWT, invalid signature encoding: %w", err)
+	}
+
+	return nil
+}
+
+// lintPEM verifies the value holds one or more complete PEM blocks,
+// catching truncated keys and certificates.
+func lintPEM(value []byte) error {
+	block, rest := pem.Decode(value)
+	if block == nil {
+		return errors.New("not a PEM block, or truncated")
+	}
+
+	for len(bytes.TrimSpace(rest)) > 0 {
+		if block, rest = pem.Decode(rest); block == nil {
+			return errors.New("unexpected data after the PEM block, or a truncated block")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/clipboard"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+
+	"github.com/spf13/cobra"
+)
+
+type LockError struct {
+	Err error
+}
+
+func (e *LockError) Error() string { return "lock: " + e.Err.Error() }
+
+func (e *LockError) Unwrap() error { return e.Err }
+
+// LockOptions holds data required to run the command.
+type LockOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	sessionClient *vaultdaemon.SessionClient
+}
+
+var _ genericclioptions.CmdOptions = &LockOptions{}
+
+// NewLockOptions initializes the options struct.
+func NewLockOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *LockOptions {
+	return &LockOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (o *LockOptions) Complete() error {
+	s, err := vaultdaemon.NewSessionClient()
+	if err != nil {
+		// without a running daemon there are no sessions to drop,
+		// the clipboard is still cleared.
+		if errors.Is(err, vaultdaemon.ErrSocketUnavailable) {
+			o.Debugf("%v\n", err)
+			return nil
+		}
+
+		return err
+	}
+
+	o.sessionClient = s
+
+	return nil
+}
+
+func (*LockOptions) Validate() error { return nil }
+
+func (o *LockOptions) Run(ctx context.Context, _ ...string) (retErr error) {
+	defer func() { _ = o.sessionClient.Close() }()
+
+	defer func() {
+		if retErr != nil {
+			retErr = &LockError{retErr}
+			return
+		}
+	}()
+
+	n, err := o.sessionClient.LogoutAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	o.Infof("logged out of %d session(s)\n", n)
+
+	cleared, err := clipboard.ClearIfOwned()
+	if err != nil {
+		o.Errorf("clipboard not cleared: %v\n", err)
+	}
+
+	if cleared {
+		o.Infof("clipboard cleared\n")
+	}
+
+	return o.postLockHook(ctx, o.StdioOptions)
+}
+
+// NewCmdLock creates the lock cobra command.
+func NewCmdLock(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewLockOptions(
+		defaults.StdioOptions,
+		defaults.vaultOptions,
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: i18n.T("Log out of all sessions and clear the clipboard"),
+		Long: `Log out of all vault sessions at once.
+
+The daemon drops and wipes the session keys of all vaults.
+If the clipboard still holds a value copied by vlt, it is cleared as well.
+The 'post_lock_cmd' hook runs afterwards, if configured.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	return cmd
+}
@@ -7,8 +7,8 @@ import (
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
-	"github.com/ladzaretti/vlt-cli/input"
-	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/securebytes"
 	"github.com/ladzaretti/vlt-cli/vaultdaemon"
 	"github.com/ladzaretti/vlt-cli/vaulterrors"
 
@@ -36,7 +36,7 @@ func NewLoginOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultO
 }
 
 func (o *LoginOptions) Complete() error {
-	s, err := vaultdaemon.NewSessionClient()
+	s, err := connectDaemon(context.Background(), o.StdioOptions, o.config.AutostartDaemon)
 	if err != nil {
 		return err
 	}
@@ -56,11 +56,11 @@ func (o *LoginOptions) Validate() error {
 		return fmt.Errorf("%w: %s", vaulterrors.ErrVaultFileNotFound, o.path)
 	}
 
-	if o.StdinIsPiped {
+	if o.StdinIsPiped && len(o.identityFile) == 0 && len(o.passwordCommand) == 0 {
 		return vaulterrors.ErrNonInteractiveUnsupported
 	}
 
-	return nil
+	return o.verifyPath(o.StdioOptions)
 }
 
 func (o *LoginOptions) Close() error {
@@ -72,24 +72,14 @@ func (o *LoginOptions) Run(ctx context.Context, _ ...string) error {
 
 	path := o.path
 
-	password, err := input.PromptReadSecure(o.Out, int(o.In.Fd()), "[vlt] Password for %q:", path)
-	if err != nil {
-		return fmt.Errorf("prompt password: %v", err)
-	}
-
-	defer clear(password)
-
-	if len(password) == 0 {
-		return vaulterrors.ErrEmptyPassword
-	}
-
-	key, nonce, err := vault.Login(ctx, path, password, vault.WithMaxHistorySnapshots(o.maxHistorySnapshots))
+	key, nonce, err := o.unlock(ctx)
 	if err != nil {
 		return err
 	}
+	defer securebytes.Wipe(key)
 
 	sessionDuration := time.Duration(o.config.SessionDuration)
-	if err := o.sessionClient.Login(ctx, path, key, nonce, sessionDuration); err != nil {
+	if err := o.sessionClient.Login(ctx, path, key, nonce, sessionDuration, o.sessionOptions(ctx, o.StdioOptions, vaultdaemon.WithConfirmEachUse(o.config.ConfirmEachUse))...); err != nil {
 		return err
 	}
 
@@ -102,6 +92,23 @@ func (o *LoginOptions) Run(ctx context.Context, _ ...string) error {
 	return nil
 }
 
+// unlock returns the vault key, unwrapped using the configured age identity
+// file, or derived from the password, see [VaultOptions.readPassword].
+func (o *LoginOptions) unlock(ctx context.Context) (key []byte, nonce []byte, _ error) {
+	if len(o.identityFile) > 0 {
+		return o.unwrapKey(ctx)
+	}
+
+	password, err := o.readPassword(ctx, o.StdioOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer securebytes.Wipe(password)
+
+	return o.passwordLogin(ctx, o.StdioOptions, password)
+}
+
 // NewCmdLogin creates the login cobra command.
 func NewCmdLogin(defaults *DefaultVltOptions) *cobra.Command {
 	o := NewLoginOptions(
@@ -112,8 +119,14 @@ func NewCmdLogin(defaults *DefaultVltOptions) *cobra.Command {
 
 	return &cobra.Command{
 		Use:   "login",
-		Short: "Authenticate the user",
-		Long:  "Authenticate the user and grant access to the vault for subsequent operations.",
+		Short: i18n.T("Authenticate the user"),
+		Long: `Authenticate the user and grant access to the vault for subsequent operations.
+
+If an age identity file is configured (--identity-file or 'vault.identity_file'),
+the vault is unlocked as a member using the identity instead of the password, see 'vlt member'.
+
+If a password command is configured ($VLT_PASSWORD_COMMAND or 'vault.password_command'),
+the password is read from its output instead of being prompted for, e.g., from the keychain of the OS.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
 		},
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
 	"github.com/ladzaretti/vlt-cli/vaultdaemon"
 
 	"github.com/spf13/cobra"
@@ -15,6 +16,8 @@ type LogoutOptions struct {
 	*VaultOptions
 
 	sessionClient *vaultdaemon.SessionClient
+
+	all bool
 }
 
 var _ genericclioptions.CmdOptions = &LogoutOptions{}
@@ -43,9 +46,20 @@ func (*LogoutOptions) Validate() error { return nil }
 func (o *LogoutOptions) Run(ctx context.Context, _ ...string) error {
 	defer func() { _ = o.Close() }()
 
+	if o.all {
+		n, err := o.sessionClient.LogoutAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		o.Infof("logged out of %d session(s)\n", n)
+
+		return nil
+	}
+
 	o.Infof("logging out of %q\n", o.path)
 
-	if err := o.sessionClient.Logout(ctx, o.path); err != nil {
+	if err := o.sessionClient.Logout(ctx, o.path, o.sessionOptions(ctx, o.StdioOptions)...); err != nil {
 		return err
 	}
 
@@ -66,14 +80,26 @@ func NewCmdLogout(defaults *DefaultVltOptions) *cobra.Command {
 	)
 
 	cmd := &cobra.Command{
-		Use:     "logout",
-		Aliases: []string{"lock"},
-		Short:   "Log out of the current session",
-		Long:    "Log out of the current session.",
+		Use:   "logout",
+		Short: i18n.T("Log out of the current session"),
+		Long: `Log out of the current session.
+
+Use --all to log out of the sessions of all vaults, without clearing
+the clipboard or running the 'post_lock_cmd' hook as 'vlt lock' does.
+
+Sessions also end once the vault file is modified or replaced outside vlt,
+e.g., by a file sync, so that a stale session key is never used.
+
+Sessions are identified by the id of the vault, they are kept when the vault
+file is moved or renamed, see 'vlt title'.`,
+		Example: `  # Log out of the sessions of all vaults
+  vlt logout --all`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
 		},
 	}
 
+	cmd.Flags().BoolVarP(&o.all, "all", "a", false, "log out of the sessions of all vaults")
+
 	return cmd
 }
@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+
+	"filippo.io/age"
+	"github.com/spf13/cobra"
+)
+
+type MemberError struct {
+	Err error
+}
+
+func (e *MemberError) Error() string { return "member: " + e.Err.Error() }
+
+func (e *MemberError) Unwrap() error { return e.Err }
+
+// MemberAddOptions holds data required to run the command.
+type MemberAddOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	name      string
+	recipient string
+}
+
+var _ genericclioptions.CmdOptions = &MemberAddOptions{}
+
+// NewMemberAddOptions initializes the options struct.
+func NewMemberAddOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *MemberAddOptions {
+	return &MemberAddOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*MemberAddOptions) Complete() error { return nil }
+
+func (o *MemberAddOptions) Validate() error {
+	if !attributeKeyRE.MatchString(o.name) {
+		return &MemberError{fmt.Errorf("invalid member name %q: expected letters, digits, '_', '.' or '-'", o.name)}
+	}
+
+	if _, err := age.ParseX25519Recipient(o.recipient); err != nil {
+		return &MemberError{fmt.Errorf("invalid recipient: %w", err)}
+	}
+
+	return nil
+}
+
+func (o *MemberAddOptions) Run(ctx context.Context, _ ...string) error {
+	if err := o.vault.AddMember(ctx, o.name, o.recipient); err != nil {
+		return &MemberError{err}
+	}
+
+	o.persistRequired = true
+
+	o.Infof("member %q added\n", o.name)
+
+	return nil
+}
+
+// MemberRemoveOptions holds data required to run the command.
+type MemberRemoveOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+}
+
+var _ genericclioptions.CmdOptions = &MemberRemoveOptions{}
+
+// NewMemberRemoveOptions initializes the options struct.
+func NewMemberRemoveOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *MemberRemoveOptions {
+	return &MemberRemoveOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*MemberRemoveOptions) Complete() error { return nil }
+
+func (*MemberRemoveOptions) Validate() error { return nil }
+
+func (o *MemberRemoveOptions) Run(ctx context.Context, args ...string) error {
+	name := args[0]
+
+	if err := o.vault.RemoveMember(ctx, name); err != nil {
+		return &MemberError{err}
+	}
+
+	o.persistRequired = true
+
+	o.Infof("member %q removed\nThe member may still hold a copy of the vault key, run 'vlt rotate' to revoke their access.\n", name)
+
+	return nil
+}
+
+// MemberListOptions holds data required to run the command.
+type MemberListOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+}
+
+var _ genericclioptions.CmdOptions = &MemberListOptions{}
+
+// NewMemberListOptions initializes the options struct.
+func NewMemberListOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *MemberListOptions {
+	return &MemberListOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*MemberListOptions) Complete() error { return nil }
+
+func (*MemberListOptions) Validate() error { return nil }
+
+func (o *MemberListOptions) Run(ctx context.Context, _ ...string) error {
+	members, err := o.vault.Members(ctx)
+	if err != nil {
+		return &MemberError{err}
+	}
+
+	if len(members) == 0 {
+		o.Infof("no members, the vault is unlocked with the password only\n")
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	tw := tabwriter.NewWriter(&buf, 0, 0, 5, ' ', 0)
+
+	fmt.Fprintln(tw, "NAME\tRECIPIENT\tADDED")
+
+	for _, m := range members {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", m.Name, m.Recipient, m.CreatedAt)
+	}
+
+	_ = tw.Flush()
+
+	// the header is styled once aligned,
+	// escape sequences would otherwise count towards the column widths.
+	header, rows, _ := strings.Cut(buf.String(), "\n")
+
+	_, err = fmt.Fprintf(o.Out, "%s\n%s", o.Styler(o.Out).Header(header), rows)
+
+	return err
+}
+
+// NewCmdMember creates the member cobra command.
+func NewCmdMember(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "member",
+		Short: i18n.T("Manage the members of a shared vault (subcommands available)"),
+		Long: `Manage the members of a shared vault.
+
+A member unlocks the vault with their own age identity instead of the password.
+Each member holds a copy of the vault key, wrapped (age encrypted) to the member's
+X25519 public key and stored in the vault file, so a vault shared by a small team,
+e.g., in a git repository, can be unlocked by every member with their own key.
+
+Members unlock the vault with --identity-file, or the 'identity_file' option
+in the [vault] section of the config file.
+
+Member names and public keys are stored unencrypted in the vault file.
+
+Removing a member deletes their key slot only. A removed member who kept
+a copy of the vault file or key can still decrypt it; run 'vlt rotate'
+to replace the vault key, the remaining members are rewrapped to the new key.`,
+		Example: `  # Add a member using their age public key
+  vlt member add alice age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+
+  # Unlock the vault as a member
+  vlt --identity-file ~/.config/age/key.txt show foo
+
+  # List the members of the vault
+  vlt member list
+
+  # Remove a member and revoke their access
+  vlt member remove alice
+  vlt rotate`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(NewCmdMemberAdd(defaults))
+	cmd.AddCommand(NewCmdMemberRemove(defaults))
+	cmd.AddCommand(NewCmdMemberList(defaults))
+
+	return cmd
+}
+
+// NewCmdMemberAdd creates the member add cobra command.
+func NewCmdMemberAdd(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewMemberAddOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	return &cobra.Command{
+		Use:   "add name recipient",
+		Short: i18n.T("Add a vault member by their age public key"),
+		Long: `Add a vault member by their age X25519 public key (age1...).
+
+The vault key is wrapped to the public key and stored in the vault file,
+the member can then unlock the vault with the matching identity.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.name, o.recipient = args[0], args[1]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+}
+
+// NewCmdMemberRemove creates the member remove cobra command.
+func NewCmdMemberRemove(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewMemberRemoveOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	return &cobra.Command{
+		Use:   "remove name",
+		Short: i18n.T("Remove a vault member"),
+		Long: `Remove a vault member by name.
+
+The member's key slot is deleted. Run 'vlt rotate' afterwards to replace
+the vault key, a removed member may still hold a copy of it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+}
+
+// NewCmdMemberList creates the member list cobra command.
+func NewCmdMemberList(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewMemberListOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	return &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("List the vault members"),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+}
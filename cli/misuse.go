@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+)
+
+const (
+	// minSecretLikeLen is the minimum length of a name segment considered
+	// a secret by its character distribution, see [looksRandom].
+	minSecretLikeLen = 16
+
+	// nameSeparators separate the segments of names, e.g., aws/prod/ci-user.
+	nameSeparators = "/.:-_"
+
+	// maxNameLikeLen is the maximum length of a value considered a name, see [looksLikeName].
+	maxNameLikeLen = 128
+)
+
+// secretPrefixes are well-known prefixes of API tokens and keys.
+var secretPrefixes = []string{
+	"ghp_", "gho_", "ghu_", "ghs_", "github_pat_", "glpat-", "xoxb-", "xoxp-",
+	"sk-", "sk_live_", "AKIA", "ASIA", "AIza", "-----BEGIN ",
+}
+
+var (
+	// emailRE matches email addresses, a common kind of secret name.
+	emailRE = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[A-Za-z]{2,}$`)
+
+	// pathNameRE matches lower case path-like names, e.g., aws/prod/ci.
+	pathNameRE = regexp.MustCompile(`^[a-z0-9._-]+(/[a-z0-9._-]+)+$`)
+
+	// wordRE matches words of names, see [wordRatio].
+	wordRE = regexp.MustCompile(`[A-Z]?[a-z]{3,}`)
+)
+
+// warnSecretLikeName warns if the name of a secret looks like a secret value,
+// e.g., when the name and the value were swapped.
+//
+// Names are not kept out of sight like values, they are listed by 'vlt find',
+// and stay in the shell history when set using flags.
+func warnSecretLikeName(io *genericclioptions.StdioOptions, name string) {
+	if !looksLikeSecret(name) {
+		return
+	}
+
+	io.Errorf("the name looks like a secret, names are listed by 'vlt find' and flags are kept in the shell history; " +
+		"if the name and value were swapped, rename the secret using 'vlt update --set-name'\n")
+}
+
+// warnNameLikeValue warns if a piped value looks like the name of a secret
+// rather than a secret, e.g., an email address or the name of an existing secret.
+func (o *VaultOptions) warnNameLikeValue(ctx context.Context, io *genericclioptions.StdioOptions, value []byte) {
+	if !looksLikeName(value) && !o.secretNameExists(ctx, string(bytes.TrimSpace(value))) {
+		return
+	}
+
+	io.Errorf("the piped value looks like a name rather than a secret, check the name and value were not swapped\n")
+}
+
+// secretNameExists reports whether a secret is named exactly name.
+func (o *VaultOptions) secretNameExists(ctx context.Context, name string) bool {
+	if len(name) == 0 || len(name) > maxNameLikeLen {
+		return false
+	}
+
+	secrets, err := o.vault.FilterSecrets(ctx, "", name, nil)
+	if err != nil {
+		return false
+	}
+
+	for _, s := range secrets {
+		if s.Name == name { // the name filter is a glob pattern.
+			return true
+		}
+	}
+
+	return false
+}
+
+// looksLikeSecret reports whether s looks like a secret value, i.e., it starts
+// with a well-known token prefix, or holds a long word of random looking
+// letters and digits, between the separators common in names, e.g., '/'.
+func looksLikeSecret(s string) bool {
+	for _, p := range secretPrefixes {
+		if strings.HasPrefix(s, p) && len(s) >= len(p)+8 {
+			return true
+		}
+	}
+
+	if strings.ContainsFunc(s, unicode.IsSpace) {
+		return false
+	}
+
+	segments := strings.FieldsFunc(s, func(r rune) bool { return strings.ContainsRune(nameSeparators, r) })
+
+	return slices.ContainsFunc(segments, looksRandom)
+}
+
+// looksRandom reports whether s is a long string of random looking letters and digits.
+func looksRandom(s string) bool {
+	if len(s) < minSecretLikeLen {
+		return false
+	}
+
+	var letters, digits bool
+
+	for _, r := range s {
+		letters = letters || unicode.IsLetter(r)
+		digits = digits || unicode.IsDigit(r)
+	}
+
+	// names are mostly made of words, e.g., MyWorkLaptop2024Backup01,
+	// random strings rarely hold runs of lower case letters.
+	return letters && digits && wordRatio(s) < 0.5 && shannonEntropy(s) >= 3.5
+}
+
+// looksLikeName reports whether a value looks like the name of a secret,
+// i.e., a short single line email address, url or lower case path.
+func looksLikeName(value []byte) bool {
+	v := string(bytes.TrimSpace(value))
+	if len(v) == 0 || len(v) > maxNameLikeLen || !utf8.ValidString(v) || strings.ContainsAny(v, "\r\n") {
+		return false
+	}
+
+	return emailRE.MatchString(v) ||
+		strings.HasPrefix(v, "https://") || strings.HasPrefix(v, "http://") ||
+		pathNameRE.MatchString(v)
+}
+
+// wordRatio returns the ratio of the characters of s belonging to words,
+// i.e., runs of three or more lower case letters, optionally capitalized.
+func wordRatio(s string) float64 {
+	n := 0
+
+	for _, loc := range wordRE.FindAllStringIndex(s, -1) {
+		n += loc[1] - loc[0]
+	}
+
+	return float64(n) / float64(len(s))
+}
+
+// shannonEntropy returns the entropy of the character distribution of s, in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+
+	h := 0.0
+
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+
+	return h
+}
@@ -0,0 +1,352 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+)
+
+type MoveError struct {
+	Err error
+}
+
+func (e *MoveError) Error() string { return "move: " + e.Err.Error() }
+
+func (e *MoveError) Unwrap() error { return e.Err }
+
+// MoveOptions holds data required to run the command.
+type MoveOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	config       *ConfigOptions
+	updateConfig bool // updateConfig rewrites the vault path in the config file, see [rewriteConfigVaultPath].
+}
+
+var _ genericclioptions.CmdOptions = &MoveOptions{}
+
+// NewMoveOptions initializes the options struct.
+func NewMoveOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions, config *ConfigOptions) *MoveOptions {
+	return &MoveOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+		config:       config,
+	}
+}
+
+func (*MoveOptions) Complete() error { return nil }
+
+func (o *MoveOptions) Validate() error {
+	exists, err := o.vaultExists()
+	if err != nil {
+		return &MoveError{err}
+	}
+
+	if !exists {
+		return &MoveError{fmt.Errorf("%w: %s", vaulterrors.ErrVaultFileNotFound, o.path)}
+	}
+
+	// a rollback journal is left by an interrupted write, and is rolled back
+	// on the next open. The vault file alone is inconsistent until then.
+	if _, err := os.Stat(o.path + "-journal"); err == nil {
+		return &MoveError{errors.New("the vault has an unfinished write, run any vlt command on it to recover it first")}
+	}
+
+	return nil
+}
+
+func (o *MoveOptions) Run(ctx context.Context, args ...string) (retErr error) {
+	defer func() {
+		if retErr != nil {
+			retErr = &MoveError{retErr}
+			return
+		}
+	}()
+
+	src := o.path
+
+	dst, err := moveDestination(src, args[0])
+	if err != nil {
+		return err
+	}
+
+	// read before moving, the session is identified by the vault id if set.
+	sessionOpts := o.sessionOptions(ctx, o.StdioOptions)
+
+	if err := moveFile(src, dst); err != nil {
+		return err
+	}
+
+	o.Infof("moved vault %s to %s\n", src, dst)
+
+	o.moveSession(ctx, src, dst, sessionOpts)
+
+	return o.movePathInConfig(src, dst)
+}
+
+// moveDestination returns the absolute path the vault at src is moved to,
+// into dst if it is a directory.
+func moveDestination(src string, dst string) (string, error) {
+	if fi, err := os.Stat(dst); err == nil && fi.IsDir() {
+		dst = filepath.Join(dst, filepath.Base(src))
+	}
+
+	dst, err := filepath.Abs(dst)
+	if err != nil {
+		return "", err
+	}
+
+	if abs, err := filepath.Abs(src); err == nil && abs == dst {
+		return "", fmt.Errorf("the vault is already at %s", dst)
+	}
+
+	if exists(dst) {
+		return "", fmt.Errorf("%w: %s", os.ErrExist, dst)
+	}
+
+	return dst, nil
+}
+
+// moveSession moves the daemon session of the vault, if any, to its new path.
+// Without a running daemon there is no session to move.
+func (o *MoveOptions) moveSession(ctx context.Context, src string, dst string, opts []vaultdaemon.RequestOption) {
+	sessionClient, err := vaultdaemon.NewSessionClient()
+	if err != nil {
+		o.Debugf("vlt: session not moved: %v\n", err)
+		return
+	}
+	defer func() { _ = sessionClient.Close() }() //nolint:wsl_v5
+
+	if err := sessionClient.MoveSession(ctx, src, dst, opts...); err != nil {
+		if errors.Is(err, vaultdaemon.ErrIncompatibleDaemon) {
+			o.Errorf("session not moved: %v; log in again\n", err)
+			return
+		}
+
+		o.Debugf("vlt: session not moved: %v\n", err)
+	}
+}
+
+// movePathInConfig updates the settings of the config file referring to the vault at src
+// if --update-config is given, or reports them otherwise.
+func (o *MoveOptions) movePathInConfig(src string, dst string) error {
+	configPath := o.config.fileConfig.path
+
+	// the vault was found at the default path, it is only found at dst once 'path' is set.
+	defaultVault := len(o.config.fileConfig.Vault.Path) == 0 && len(o.config.cliFlags.vaultPath) == 0
+
+	if len(configPath) == 0 {
+		if defaultVault {
+			o.Errorf("the vault is no longer at the default path, set 'path' in the [vault] config section to %q\n", dst)
+		}
+
+		return nil
+	}
+
+	raw, err := os.ReadFile(configPath) //nolint:gosec // user config path
+	if err != nil {
+		return err
+	}
+
+	updated, n, err := rewriteConfigVaultPath(raw, src, dst, defaultVault)
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", configPath, err)
+	}
+
+	if n == 0 {
+		return nil
+	}
+
+	if !o.updateConfig {
+		o.Errorf("%d setting(s) of %s still refer to %s, set them to %q, or move the vault using --update-config\n", n, configPath, src, dst)
+		return nil
+	}
+
+	if err := writeFileSynced(configPath, updated); err != nil {
+		return fmt.Errorf("update config file: %w", err)
+	}
+
+	o.Infof("updated %d setting(s) of %s\n", n, configPath)
+
+	return nil
+}
+
+var (
+	tomlTableRegex = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(#.*)?$`)
+	tomlPathRegex  = regexp.MustCompile(`^(\s*)path\s*=`)
+)
+
+// rewriteConfigVaultPath sets the 'path' settings of the [vault] section and
+// of the [vaults.<name>] policies referring to the vault at src to dst, and
+// returns the updated config and the number of settings updated.
+// If insert is set, 'path' is added to the [vault] section.
+//
+// The config is rewritten line by line, keeping its comments and layout.
+// A trailing comment of an updated line is dropped.
+func rewriteConfigVaultPath(config []byte, src string, dst string, insert bool) ([]byte, int, error) {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	setting, err := toml.Marshal(struct {
+		Path string `toml:"path"`
+	}{dst})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	setting = bytes.TrimSpace(setting)
+
+	var (
+		lines       = strings.SplitAfter(string(config), "\n")
+		table       string
+		vaultHeader = -1
+		n           int
+	)
+
+	for i, line := range lines {
+		content := strings.TrimRight(line, "\r\n")
+
+		if m := tomlTableRegex.FindStringSubmatch(content); m != nil {
+			table = m[1]
+
+			if table == "vault" {
+				vaultHeader = i
+			}
+
+			continue
+		}
+
+		m := tomlPathRegex.FindStringSubmatch(content)
+		if m == nil || (table != "vault" && !strings.HasPrefix(table, "vaults.")) {
+			continue
+		}
+
+		var value struct {
+			Path string `toml:"path"`
+		}
+
+		if err := toml.Unmarshal([]byte(strings.TrimSpace(content)), &value); err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if abs, err := filepath.Abs(value.Path); err != nil || abs != src {
+			continue
+		}
+
+		lines[i] = m[1] + string(setting) + line[len(content):]
+		n++
+	}
+
+	if insert {
+		inserted := string(setting) + "\n"
+
+		if vaultHeader < 0 {
+			lines = append(lines, "\n[vault]\n"+inserted)
+		} else {
+			lines = append(lines[:vaultHeader+1], append([]string{inserted}, lines[vaultHeader+1:]...)...)
+		}
+
+		n++
+	}
+
+	return []byte(strings.Join(lines, "")), n, nil
+}
+
+// writeFileSynced replaces the file at path with data, keeping its permissions.
+// data is written to a temporary file renamed to path, so that path is never left
+// partially written.
+func writeFileSynced(path string, data []byte) (retErr error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, ".vlt-config-*")
+	if err != nil {
+		return err
+	}
+	defer func() { //nolint:wsl_v5
+		if retErr != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := tmp.Chmod(fi.Mode().Perm()); err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// NewCmdMove creates the move cobra command.
+func NewCmdMove(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewMoveOptions(
+		defaults.StdioOptions,
+		defaults.vaultOptions,
+		defaults.configOptions,
+	)
+
+	cmd := &cobra.Command{
+		Use:     "move NEW_PATH",
+		Aliases: []string{"mv"},
+		Short:   i18n.T("Move the vault file to a new path"),
+		Long: `Move the vault file to a new path, or into a directory.
+
+The vault is renamed, or across filesystems copied, synced and renamed
+into place before the original is removed, so that a complete vault file
+exists at all times. The vault is not unlocked.
+
+The session of the vault in the daemon, if any, is moved along.
+Settings of the config file referring to the vault, 'path' of the [vault]
+section and of the [vaults.<name>] policies, are reported, and rewritten
+if --update-config is given.`,
+		Example: `  # Move the vault, and update the config file referring to it
+  vlt mv ~/sync/vault.db --update-config
+
+  # Move the vault set by --file into a directory
+  vlt mv ~/vaults/ --file ./work.vlt`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.updateConfig, "update-config", "", false, "rewrite the settings of the config file referring to the vault")
+
+	return cmd
+}
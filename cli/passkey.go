@@ -0,0 +1,518 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/cxf"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// passkeyLabel is the label of the secrets holding passkeys.
+	passkeyLabel = "passkey"
+
+	// passkeyNamePrefix prefixes the relying party and user in the secret name of a passkey.
+	passkeyNamePrefix = "passkey/"
+
+	// cxfExporter identifies vlt as the exporting provider of CXF documents.
+	cxfExporter = "vlt"
+)
+
+// Attributes of the secrets holding passkeys, binary values are base64url encoded.
+const (
+	passkeyAttrRpID            = "rp_id"
+	passkeyAttrCredentialID    = "credential_id"
+	passkeyAttrUserHandle      = "user_handle"
+	passkeyAttrUsername        = "username"
+	passkeyAttrUserDisplayName = "user_display_name"
+)
+
+type PasskeyError struct {
+	Err error
+}
+
+func (e *PasskeyError) Error() string { return "passkey: " + e.Err.Error() }
+
+func (e *PasskeyError) Unwrap() error { return e.Err }
+
+// storedPasskey is a passkey stored in the vault.
+//
+// The passkey is a secret labeled [passkeyLabel] holding the PKCS#8 DER encoded
+// private key, its relying party and user are stored as attributes.
+type storedPasskey struct {
+	id              int
+	rpID            string
+	credentialID    string
+	userHandle      string
+	username        string
+	userDisplayName string
+}
+
+// passkeyName returns the secret name of a passkey, e.g., "passkey/github.com/alice".
+func passkeyName(rpID, username, userHandle string) string {
+	return passkeyNamePrefix + rpID + "/" + cmp.Or(username, userHandle)
+}
+
+// storedPasskeys returns the passkeys stored in the vault, ordered by relying
+// party and username. A non-empty rpID only returns the passkeys of that party.
+func (o *VaultOptions) storedPasskeys(ctx context.Context, rpID string) ([]storedPasskey, error) {
+	secrets, err := o.vault.FilterSecrets(ctx, "", "", []string{passkeyLabel})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+
+	ids := slices.Sorted(maps.Keys(secrets))
+
+	attrs, err := o.vault.SecretsAttributes(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys := make([]storedPasskey, 0, len(ids))
+
+	for _, id := range ids {
+		a := attrs[id]
+
+		if len(a[passkeyAttrRpID]) == 0 || len(a[passkeyAttrCredentialID]) == 0 {
+			continue // labeled by the user, not imported by 'vlt passkey import'.
+		}
+
+		if len(rpID) > 0 && a[passkeyAttrRpID] != rpID {
+			continue
+		}
+
+		passkeys = append(passkeys, storedPasskey{
+			id:              id,
+			rpID:            a[passkeyAttrRpID],
+			credentialID:    a[passkeyAttrCredentialID],
+			userHandle:      a[passkeyAttrUserHandle],
+			username:        a[passkeyAttrUsername],
+			userDisplayName: a[passkeyAttrUserDisplayName],
+		})
+	}
+
+	slices.SortFunc(passkeys, func(a, b storedPasskey) int {
+		return cmp.Or(cmp.Compare(a.rpID, b.rpID), cmp.Compare(a.username, b.username), cmp.Compare(a.id, b.id))
+	})
+
+	return passkeys, nil
+}
+
+// PasskeyImportOptions holds data required to run the command.
+type PasskeyImportOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+}
+
+var _ genericclioptions.CmdOptions = &PasskeyImportOptions{}
+
+// NewPasskeyImportOptions initializes the options struct.
+func NewPasskeyImportOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *PasskeyImportOptions {
+	return &PasskeyImportOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*PasskeyImportOptions) Complete() error { return nil }
+
+func (*PasskeyImportOptions) Validate() error { return nil }
+
+func (o *PasskeyImportOptions) Run(ctx context.Context, files ...string) error {
+	var in io.Reader
+
+	switch {
+	case o.StdinIsPiped && len(files) > 0:
+		return &PasskeyError{errors.New("cannot import from both stdin and file")}
+
+	case o.StdinIsPiped:
+		in = o.In
+
+	case len(files) == 1:
+		f, err := os.Open(filepath.Clean(files[0]))
+		if err != nil {
+			return &PasskeyError{err}
+		}
+		defer func() { //nolint:wsl_v5
+			_ = f.Close()
+		}()
+
+		in = f
+
+	default:
+		return &PasskeyError{errors.New("no input source provided (stdin or file)")}
+	}
+
+	if err := o.importPasskeys(ctx, in); err != nil {
+		return &PasskeyError{err}
+	}
+
+	return nil
+}
+
+// importPasskeys stores the passkeys of the CXF document read from r,
+// skipping the ones already stored, matched by their credential id.
+func (o *PasskeyImportOptions) importPasskeys(ctx context.Context, r io.Reader) error {
+	h, err := cxf.Parse(r)
+	if err != nil {
+		return err
+	}
+
+	passkeys, err := h.Passkeys()
+	if err != nil {
+		return err
+	}
+
+	stored, err := o.storedPasskeys(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(stored))
+	for _, p := range stored {
+		known[p.credentialID] = true
+	}
+
+	var imported, skipped int
+
+	for _, p := range passkeys {
+		if known[p.CredentialID] {
+			skipped++
+			continue
+		}
+
+		if err := o.insertPasskey(ctx, p); err != nil {
+			return fmt.Errorf("passkey for %q at %q: %w", cmp.Or(p.Username, p.UserHandle), p.RpID, err)
+		}
+
+		known[p.CredentialID] = true
+		imported++
+	}
+
+	if imported > 0 {
+		o.persistRequired = true
+	}
+
+	o.Infof("imported %s, skipped %d already stored\n", pluralize(imported, "passkey"), skipped)
+
+	return nil
+}
+
+func (o *PasskeyImportOptions) insertPasskey(ctx context.Context, p cxf.Passkey) error {
+	key, err := cxf.DecodeBytes(p.Key)
+	if err != nil {
+		return err
+	}
+	defer securebytes.Wipe(key)
+
+	if _, err := x509.ParsePKCS8PrivateKey(key); err != nil {
+		return errors.New("unsupported private key: expected a PKCS#8 DER encoded key")
+	}
+
+	id, err := o.vault.InsertNewSecret(ctx, passkeyName(p.RpID, p.Username, p.UserHandle), key, []string{passkeyLabel})
+	if err != nil {
+		return err
+	}
+
+	attrs := map[string]string{
+		passkeyAttrRpID:         p.RpID,
+		passkeyAttrCredentialID: p.CredentialID,
+		passkeyAttrUserHandle:   p.UserHandle,
+	}
+
+	if len(p.Username) > 0 {
+		attrs[passkeyAttrUsername] = p.Username
+	}
+
+	if len(p.UserDisplayName) > 0 {
+		attrs[passkeyAttrUserDisplayName] = p.UserDisplayName
+	}
+
+	return o.vault.UpdateSecretAttributes(ctx, id, attrs, nil)
+}
+
+// PasskeyExportOptions holds data required to run the command.
+type PasskeyExportOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	output      string
+	stdout      bool
+	forceStdout bool   // forceStdout prints to a terminal stdout without confirmation, see [confirmTerminalStdout].
+	rpID        string // rpID limits the export to the passkeys of a relying party.
+}
+
+var _ genericclioptions.CmdOptions = &PasskeyExportOptions{}
+
+// NewPasskeyExportOptions initializes the options struct.
+func NewPasskeyExportOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *PasskeyExportOptions {
+	return &PasskeyExportOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*PasskeyExportOptions) Complete() error { return nil }
+
+func (o *PasskeyExportOptions) Validate() error {
+	if len(o.output) == 0 && !o.stdout {
+		return &PasskeyError{errors.New("either specify an --output path or use --stdout")}
+	}
+
+	return nil
+}
+
+func (o *PasskeyExportOptions) Run(ctx context.Context, _ ...string) error {
+	if o.stdout {
+		if err := confirmTerminalStdout(ctx, o.StdioOptions, o.forceStdout); err != nil {
+			return &PasskeyError{err}
+		}
+	}
+
+	h, err := o.exportPasskeys(ctx)
+	if err != nil {
+		return &PasskeyError{err}
+	}
+
+	out := o.Out
+
+	if len(o.output) > 0 {
+		f, err := os.OpenFile(o.output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return &PasskeyError{err}
+		}
+		defer func() { //nolint:wsl_v5
+			_ = f.Close()
+		}()
+
+		out = f
+	}
+
+	if err := cxf.Encode(out, h); err != nil {
+		return &PasskeyError{err}
+	}
+
+	return nil
+}
+
+// exportPasskeys returns the stored passkeys as a CXF document
+// of a single account, with an item per passkey.
+func (o *PasskeyExportOptions) exportPasskeys(ctx context.Context) (*cxf.Header, error) {
+	passkeys, err := o.storedPasskeys(ctx, o.rpID)
+	if err != nil {
+		return nil, err
+	}
+
+	accountID := make([]byte, 16)
+	_, _ = rand.Read(accountID)
+
+	account := cxf.Account{
+		ID:    cxf.EncodeBytes(accountID),
+		Items: make([]cxf.Item, 0, len(passkeys)),
+	}
+
+	for _, p := range passkeys {
+		var item cxf.Item
+
+		err := o.vault.WithSecret(ctx, p.id, func(key []byte) (err error) {
+			item, err = cxf.NewPasskeyItem(cxf.Passkey{
+				CredentialID:    p.credentialID,
+				RpID:            p.rpID,
+				Username:        p.username,
+				UserDisplayName: p.userDisplayName,
+				UserHandle:      p.userHandle,
+				Key:             cxf.EncodeBytes(key),
+			})
+
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		account.Items = append(account.Items, item)
+	}
+
+	return &cxf.Header{
+		Version:             cxf.CurrentVersion,
+		ExporterRpID:        cxfExporter,
+		ExporterDisplayName: cxfExporter,
+		Timestamp:           time.Now().Unix(),
+		Accounts:            []cxf.Account{account},
+	}, nil
+}
+
+// PasskeyListOptions holds data required to run the command.
+type PasskeyListOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	rpID string // rpID limits the listing to the passkeys of a relying party.
+}
+
+var _ genericclioptions.CmdOptions = &PasskeyListOptions{}
+
+// NewPasskeyListOptions initializes the options struct.
+func NewPasskeyListOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *PasskeyListOptions {
+	return &PasskeyListOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*PasskeyListOptions) Complete() error { return nil }
+
+func (*PasskeyListOptions) Validate() error { return nil }
+
+func (o *PasskeyListOptions) Run(ctx context.Context, _ ...string) error {
+	passkeys, err := o.storedPasskeys(ctx, o.rpID)
+	if err != nil {
+		return &PasskeyError{err}
+	}
+
+	if len(passkeys) == 0 {
+		o.Infof("no passkeys, import some using 'vlt passkey import'\n")
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	tw := tabwriter.NewWriter(&buf, 0, 0, 5, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tRP ID\tUSERNAME\tCREDENTIAL ID")
+
+	for _, p := range passkeys {
+		rpID, username := p.rpID, cmp.Or(p.username, p.userHandle)
+		if o.discreet {
+			rpID, username = maskedName, maskedName
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", p.id, rpID, username, p.credentialID)
+	}
+
+	_ = tw.Flush()
+
+	// the header is styled once aligned,
+	// escape sequences would otherwise count towards the column widths.
+	header, rows, _ := strings.Cut(buf.String(), "\n")
+
+	_, err = fmt.Fprintf(o.Out, "%s\n%s", o.Styler(o.Out).Header(header), rows)
+
+	return err
+}
+
+// NewCmdPasskey creates the passkey cobra command.
+func NewCmdPasskey(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "passkey",
+		Short: i18n.T("Store passkeys and exchange them with other providers (subcommands available)"),
+		Long: `Store passkeys (WebAuthn credentials) and exchange them with other providers.
+
+A passkey is saved as a secret named 'passkey/<rp id>/<username>' with the 'passkey' label,
+holding its PKCS#8 DER encoded private key, its relying party id, credential id,
+user handle and names are stored as encrypted attributes.
+
+Passkeys are imported and exported in the FIDO Alliance Credential Exchange Format (CXF),
+a JSON document supported by a growing number of password managers.
+Credentials other than passkeys found in imported documents are skipped.`,
+		Example: `  # Import the passkeys exported by another provider
+  vlt passkey import passkeys.json
+
+  # List the stored passkeys of a relying party
+  vlt passkey list --rp-id github.com
+
+  # Export all passkeys
+  vlt passkey export --output passkeys.json`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(NewCmdPasskeyImport(defaults))
+	cmd.AddCommand(NewCmdPasskeyExport(defaults))
+	cmd.AddCommand(NewCmdPasskeyList(defaults))
+
+	return cmd
+}
+
+// NewCmdPasskeyImport creates the passkey import cobra command.
+func NewCmdPasskeyImport(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewPasskeyImportOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	return &cobra.Command{
+		Use:   "import [file]",
+		Short: i18n.T("Import passkeys from a CXF document"),
+		Long: `Import passkeys from a Credential Exchange Format (CXF) document,
+read from the given file, or from piped or redirected input.
+
+Passkeys already stored, matched by their credential id, are skipped.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+}
+
+// NewCmdPasskeyExport creates the passkey export cobra command.
+func NewCmdPasskeyExport(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewPasskeyExportOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: i18n.T("Export passkeys as a CXF document"),
+		Long: `Export the stored passkeys as a Credential Exchange Format (CXF) document.
+
+The document holds the private keys unencrypted, it is written with mode 0600.
+Printing it to a terminal using --stdout is only done once confirmed, or with --force-stdout.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "export passkeys to the specified file path")
+	cmd.Flags().BoolVarP(&o.stdout, "stdout", "", false, "print exported passkeys to standard output (unsafe)")
+	cmd.Flags().BoolVarP(&o.forceStdout, "force-stdout", "", false, "print to stdout without confirmation if it is a terminal")
+	cmd.Flags().StringVarP(&o.rpID, "rp-id", "", "", "only export the passkeys of this relying party, e.g., github.com")
+
+	return cmd
+}
+
+// NewCmdPasskeyList creates the passkey list cobra command.
+func NewCmdPasskeyList(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewPasskeyListOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("List the stored passkeys"),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.rpID, "rp-id", "", "", "only list the passkeys of this relying party, e.g., github.com")
+
+	return cmd
+}
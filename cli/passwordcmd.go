@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/input"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+)
+
+// envPasswordCommandKey is the environment variable key for overriding
+// the password command, see [PasswordCommand].
+const envPasswordCommandKey = "VLT_PASSWORD_COMMAND"
+
+// PasswordCommand returns the command printing the master password set by
+// $VLT_PASSWORD_COMMAND, or by the 'password_command' setting of the [vault]
+// config section, nil if neither is set.
+//
+// The environment variable is split on white space, quoting is not supported.
+func PasswordCommand(c *FileConfig) []string {
+	if fields := strings.Fields(os.Getenv(envPasswordCommandKey)); len(fields) > 0 {
+		return fields
+	}
+
+	return c.Vault.PasswordCommand
+}
+
+// readPassword returns the master password printed by the password command
+// if configured, or prompts for it otherwise.
+func (o *VaultOptions) readPassword(ctx context.Context, io *genericclioptions.StdioOptions) ([]byte, error) {
+	if len(o.passwordCommand) > 0 {
+		return runPasswordCommand(ctx, io, o.passwordCommand)
+	}
+
+	prompt, name := "[vlt] Password for %q:", o.path
+	if i := o.vaultIdentity(ctx, io); i != nil && len(i.Title) > 0 {
+		prompt, name = "[vlt] Password for %s vault:", i.Title
+	}
+
+	password, err := input.PromptReadSecure(ctx, io.Prompter(), prompt, name)
+	if err != nil {
+		return nil, fmt.Errorf("prompt password: %v", err)
+	}
+
+	if len(password) == 0 {
+		return nil, vaulterrors.ErrEmptyPassword
+	}
+
+	return password, nil
+}
+
+// runPasswordCommand runs the password command, e.g., querying the keychain
+// of the OS, and returns the first line it prints to stdout, of at most
+// [maxPasswordInputSize] bytes.
+// Its stderr is passed through, e.g., for a pinentry program to report errors.
+func runPasswordCommand(ctx context.Context, io *genericclioptions.StdioOptions, command []string) ([]byte, error) {
+	io.Debugf("vlt: running password command %q\n", command[0])
+
+	//nolint:gosec // G204: safe, user config on local CLI tool
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stderr = io.ErrOut
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("password command: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("password command: %w", err)
+	}
+
+	out, err := readAllLimited(stdout, maxPasswordInputSize)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+
+		return nil, fmt.Errorf("password command: stdout: %w", err)
+	}
+	defer securebytes.Wipe(out)
+
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("password command: %w", err)
+	}
+
+	line := passwordLine(out)
+	if len(line) == 0 {
+		return nil, fmt.Errorf("password command: %w: nothing printed to stdout", vaulterrors.ErrEmptyPassword)
+	}
+
+	return bytes.Clone(line), nil
+}
+
+// maxPasswordInputSize is the maximal size read from a password file, descriptor or command.
+const maxPasswordInputSize = 4 << 10
+
+// readPasswordFile returns the password on the first line of the file at path.
+func readPasswordFile(path string) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // path is set by the user
+	if err != nil {
+		return nil, fmt.Errorf("password file: %w", err)
+	}
+
+	return readPasswordInput(f, "password file "+path)
+}
+
+// readPasswordFD returns the password on the first line read from the open
+// file descriptor fd, e.g., 3 for '3<file' in the shell. The descriptor is closed.
+func readPasswordFD(fd int) ([]byte, error) {
+	f := os.NewFile(uintptr(fd), "fd "+strconv.Itoa(fd)) //nolint:gosec // fd is not negative
+	if f == nil {
+		return nil, fmt.Errorf("invalid password file descriptor %d", fd)
+	}
+
+	return readPasswordInput(f, "password "+f.Name())
+}
+
+// readPasswordInput returns the password on the first line read from f, and closes it.
+func readPasswordInput(f *os.File, name string) ([]byte, error) {
+	defer func() { _ = f.Close() }()
+
+	b, err := readAllLimited(f, maxPasswordInputSize)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	defer securebytes.Wipe(b)
+
+	line := passwordLine(b)
+	if len(line) == 0 {
+		return nil, fmt.Errorf("%s: %w", name, vaulterrors.ErrEmptyPassword)
+	}
+
+	return bytes.Clone(line), nil
+}
+
+// passwordLine returns the first line of b, without its line ending.
+func passwordLine(b []byte) []byte {
+	line, _, _ := bytes.Cut(b, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r"))
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+)
+
+// vaultPathReport holds the results of a vault path security inspection.
+type vaultPathReport struct {
+	// problems are issues that refuse access to the vault
+	// unless explicitly allowed by the user.
+	problems []string
+
+	// warnings are informational only.
+	warnings []string
+}
+
+// inspectVaultPath checks the vault file and its parent directory
+// for permissions that may expose the vault to other users,
+// similar to the checks ssh performs on private key files.
+//
+// A missing vault file is not an error, only the directory is inspected.
+func inspectVaultPath(path string) (*vaultPathReport, error) {
+	r := &vaultPathReport{}
+
+	fi, err := os.Stat(path)
+	switch {
+	case err == nil:
+		problem, err := fileAccessProblem(path, fi)
+		if err != nil {
+			return nil, fmt.Errorf("inspect vault file: %w", err)
+		}
+
+		if len(problem) > 0 {
+			r.problems = append(r.problems, problem)
+		}
+
+		if uid, ok := fileOwner(fi); ok && uid != os.Getuid() {
+			r.problems = append(r.problems, fmt.Sprintf("vault file %q is owned by uid %d, expected %d", path, uid, os.Getuid()))
+		}
+	case errors.Is(err, fs.ErrNotExist):
+	default:
+		return nil, fmt.Errorf("stat vault file: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+
+	di, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("stat vault directory: %w", err)
+	}
+
+	if problem := dirAccessProblem(dir, di); len(problem) > 0 {
+		r.problems = append(r.problems, problem)
+	}
+
+	if name, ok := networkFilesystem(dir); ok {
+		r.warnings = append(r.warnings, fmt.Sprintf("vault directory %q is on a network filesystem (%s)", dir, name))
+	}
+
+	return r, nil
+}
+
+// verifyVaultPath inspects the vault path and reports any findings.
+//
+// Problems are returned as [vaulterrors.ErrInsecureVaultPath],
+// unless insecureOK is set, in which case they are downgraded to warnings.
+func verifyVaultPath(path string, insecureOK bool, warnf func(string, ...any)) error {
+	r, err := inspectVaultPath(path)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range r.warnings {
+		warnf("%s\n", w)
+	}
+
+	if len(r.problems) == 0 {
+		return nil
+	}
+
+	if insecureOK {
+		for _, p := range r.problems {
+			warnf("%s\n", p)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", vaulterrors.ErrInsecureVaultPath, r.problems[0])
+}
@@ -0,0 +1,46 @@
+//go:build linux
+
+package cli
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// networkFilesystems maps statfs magic numbers of network
+// and user space filesystems to their display names.
+var networkFilesystems = map[int64]string{
+	unix.NFS_SUPER_MAGIC:  "nfs",
+	unix.SMB_SUPER_MAGIC:  "smb",
+	unix.SMB2_SUPER_MAGIC: "smb2",
+	unix.CIFS_SUPER_MAGIC: "cifs",
+	unix.AFS_SUPER_MAGIC:  "afs",
+	unix.CEPH_SUPER_MAGIC: "ceph",
+	unix.CODA_SUPER_MAGIC: "coda",
+	unix.V9FS_MAGIC:       "9p",
+	unix.FUSE_SUPER_MAGIC: "fuse",
+}
+
+// networkFilesystem reports whether path resides on a network filesystem.
+func networkFilesystem(path string) (name string, ok bool) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return "", false
+	}
+
+	name, ok = networkFilesystems[int64(st.Type)] //nolint:unconvert // type differs between architectures
+
+	return name, ok
+}
+
+// fileOwner returns the uid of the file owner.
+func fileOwner(fi os.FileInfo) (int, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+
+	return int(st.Uid), true
+}
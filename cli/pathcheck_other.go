@@ -0,0 +1,9 @@
+//go:build !linux
+
+package cli
+
+import "os"
+
+func networkFilesystem(string) (string, bool) { return "", false }
+
+func fileOwner(os.FileInfo) (int, bool) { return 0, false }
@@ -0,0 +1,31 @@
+//go:build unix
+
+package cli
+
+import (
+	"fmt"
+	"os"
+)
+
+// fileAccessProblem reports a vault file readable or writable by other users.
+func fileAccessProblem(path string, fi os.FileInfo) (string, error) {
+	if perm := fi.Mode().Perm(); perm&0o077 != 0 {
+		return fmt.Sprintf("vault file %q is accessible by other users (mode %04o, expected %04o)", path, perm, vaultPerm), nil
+	}
+
+	return "", nil
+}
+
+// dirAccessProblem reports a world-writable vault directory without the sticky bit.
+func dirAccessProblem(dir string, di os.FileInfo) string {
+	if mode := di.Mode(); mode.Perm()&0o002 != 0 && mode&os.ModeSticky == 0 {
+		return fmt.Sprintf("vault directory %q is world-writable (mode %04o)", dir, mode.Perm())
+	}
+
+	return ""
+}
+
+// restrictVaultFile limits access to the vault file at path to its owner.
+func restrictVaultFile(path string) error {
+	return os.Chmod(path, vaultPerm)
+}
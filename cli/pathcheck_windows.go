@@ -0,0 +1,137 @@
+//go:build windows
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// fileAccessProblem reports a vault file whose access control list
+// grants access to anyone but the current user, SYSTEM and the administrators.
+//
+// File mode bits are not meaningful on Windows, only the ACL is inspected.
+func fileAccessProblem(path string, _ os.FileInfo) (string, error) {
+	sd, err := windows.GetNamedSecurityInfo(path, windows.SE_FILE_OBJECT, windows.DACL_SECURITY_INFORMATION)
+	if err != nil {
+		return "", err
+	}
+
+	dacl, _, err := sd.DACL()
+	if err != nil {
+		return "", err
+	}
+
+	if dacl == nil {
+		return fmt.Sprintf("vault file %q has no access control list, it is accessible by everyone", path), nil
+	}
+
+	trusted, err := trustedSIDs()
+	if err != nil {
+		return "", err
+	}
+
+	for i := range uint32(dacl.AceCount) {
+		var ace *windows.ACCESS_ALLOWED_ACE
+		if err := windows.GetAce(dacl, i, &ace); err != nil {
+			return "", err
+		}
+
+		if ace.Header.AceType != windows.ACCESS_ALLOWED_ACE_TYPE {
+			continue
+		}
+
+		sid := (*windows.SID)(unsafe.Pointer(&ace.SidStart))
+		if isTrusted(sid, trusted) {
+			continue
+		}
+
+		return fmt.Sprintf("vault file %q is accessible by other users (%s)", path, accountName(sid)), nil
+	}
+
+	return "", nil
+}
+
+// dirAccessProblem is a no-op on Windows, directory ACLs are not inspected.
+func dirAccessProblem(string, os.FileInfo) string { return "" }
+
+// restrictVaultFile replaces the access control list of the vault file at path
+// with a protected one, not inheriting from the directory,
+// that grants full access to the current user only.
+func restrictVaultFile(path string) error {
+	user, err := currentUserSID()
+	if err != nil {
+		return err
+	}
+
+	acl, err := windows.ACLFromEntries([]windows.EXPLICIT_ACCESS{{
+		AccessPermissions: windows.GENERIC_ALL,
+		AccessMode:        windows.GRANT_ACCESS,
+		Inheritance:       windows.NO_INHERITANCE,
+		Trustee: windows.TRUSTEE{
+			TrusteeForm:  windows.TRUSTEE_IS_SID,
+			TrusteeType:  windows.TRUSTEE_IS_USER,
+			TrusteeValue: windows.TrusteeValueFromSID(user),
+		},
+	}}, nil)
+	if err != nil {
+		return err
+	}
+
+	return windows.SetNamedSecurityInfo(path, windows.SE_FILE_OBJECT,
+		windows.DACL_SECURITY_INFORMATION|windows.PROTECTED_DACL_SECURITY_INFORMATION, nil, nil, acl, nil)
+}
+
+// trustedSIDs returns the accounts allowed to access the vault file:
+// the current user, SYSTEM and the administrators.
+func trustedSIDs() ([]*windows.SID, error) {
+	user, err := currentUserSID()
+	if err != nil {
+		return nil, err
+	}
+
+	sids := []*windows.SID{user}
+
+	for _, t := range []windows.WELL_KNOWN_SID_TYPE{windows.WinLocalSystemSid, windows.WinBuiltinAdministratorsSid} {
+		sid, err := windows.CreateWellKnownSid(t)
+		if err != nil {
+			return nil, err
+		}
+
+		sids = append(sids, sid)
+	}
+
+	return sids, nil
+}
+
+func isTrusted(sid *windows.SID, trusted []*windows.SID) bool {
+	for _, t := range trusted {
+		if sid.Equals(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func currentUserSID() (*windows.SID, error) {
+	u, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return nil, fmt.Errorf("current user: %w", err)
+	}
+
+	return u.User.Sid, nil
+}
+
+// accountName returns the account name of sid, or its string form if unknown.
+func accountName(sid *windows.SID) string {
+	account, domain, _, err := sid.LookupAccount("")
+	if err != nil {
+		return sid.String()
+	}
+
+	return domain + `\` + account
+}
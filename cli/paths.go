@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// appDirPerm is the file permission mode of the vlt XDG directories.
+	appDirPerm = 0o700
+
+	xdgConfigHomeEnv = "XDG_CONFIG_HOME"
+	xdgDataHomeEnv   = "XDG_DATA_HOME"
+)
+
+// defaultPaths holds the default location of a vlt file,
+// following the XDG base directories, and its legacy location in the home directory.
+type defaultPaths struct {
+	xdg    string
+	legacy string
+}
+
+// resolve returns the XDG path, unless only the legacy path exists.
+//
+// Vaults and configs created before vlt followed the XDG base directories
+// keep working until they are moved by 'vlt config migrate'.
+func (p defaultPaths) resolve() string {
+	if exists(p.xdg) || !exists(p.legacy) {
+		return p.xdg
+	}
+
+	return p.legacy
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// configPaths returns the default config file paths.
+func configPaths() (defaultPaths, error) {
+	return appPaths(xdgConfigHomeEnv, defaultConfigName, legacyConfigName)
+}
+
+// vaultPaths returns the default vault file paths.
+func vaultPaths() (defaultPaths, error) {
+	return appPaths(xdgDataHomeEnv, defaultDatabaseFilename, legacyDatabaseFilename)
+}
+
+// appPaths returns the path of name in the vlt directory of the XDG base directory
+// set by env, or of its platform default if env is unset or not absolute,
+// as required by the XDG base directory specification.
+// The legacy path is legacyName in the home directory.
+func appPaths(env string, name string, legacyName string) (defaultPaths, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return defaultPaths{}, fmt.Errorf("user home dir: %w", err)
+	}
+
+	base := os.Getenv(env)
+	if !filepath.IsAbs(base) {
+		base = defaultBaseDir(env, home)
+	}
+
+	return defaultPaths{
+		xdg:    filepath.Join(base, appDirName, name),
+		legacy: filepath.Join(home, legacyName),
+	}, nil
+}
+
+// migrate moves the file at the legacy path to the XDG path.
+// It reports whether the file was moved, nothing is done if there is no legacy file.
+func (p defaultPaths) migrate() (bool, error) {
+	if !exists(p.legacy) {
+		return false, nil
+	}
+
+	if exists(p.xdg) {
+		return false, fmt.Errorf("both %s and %s exist, move or remove one of them manually", p.legacy, p.xdg)
+	}
+
+	dir := filepath.Dir(p.xdg)
+
+	if err := os.MkdirAll(dir, appDirPerm); err != nil {
+		return false, err
+	}
+
+	if err := os.Rename(p.legacy, p.xdg); err != nil {
+		return false, err
+	}
+
+	return true, syncDir(dir)
+}
+
+// moveFile moves the file at src to dst, which must not exist.
+//
+// Across filesystems, where the file cannot be renamed, it is copied to
+// a temporary file next to dst, synced and renamed to dst, before src is removed,
+// so that dst is never left partially written.
+func moveFile(src string, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil {
+		return errors.Join(syncDir(filepath.Dir(dst)), syncDir(filepath.Dir(src)))
+	}
+
+	if !crossDevice(err) {
+		return err
+	}
+
+	if err := copyFileSynced(src, dst); err != nil {
+		return err
+	}
+
+	if err := os.Remove(src); err != nil {
+		return fmt.Errorf("copied to %s, but: %w", dst, err)
+	}
+
+	return syncDir(filepath.Dir(src))
+}
+
+// copyFileSynced copies the file at src to dst, with the permissions of a vault file.
+func copyFileSynced(src string, dst string) (retErr error) {
+	in, err := os.Open(src) //nolint:gosec // src is the vault file
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }() //nolint:wsl_v5
+
+	dir := filepath.Dir(dst)
+
+	tmp, err := os.CreateTemp(dir, ".vlt-move-*")
+	if err != nil {
+		return err
+	}
+	defer func() { //nolint:wsl_v5
+		if retErr != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := restrictVaultFile(tmp.Name()); err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package cli
+
+import (
+	"errors"
+	"path/filepath"
+	"syscall"
+)
+
+// defaultBaseDir returns the default of the XDG base directory env,
+// relative to the home directory.
+func defaultBaseDir(env string, home string) string {
+	if env == xdgDataHomeEnv {
+		return filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(home, ".config")
+}
+
+// crossDevice reports whether a rename failed as the paths are on different filesystems.
+func crossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
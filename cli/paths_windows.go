@@ -0,0 +1,32 @@
+//go:build windows
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// defaultBaseDir returns the Windows counterpart of the XDG base directory env:
+// the local application data directory for data files,
+// the roaming one for config files.
+func defaultBaseDir(env string, home string) string {
+	appData, dir := "APPDATA", filepath.Join("AppData", "Roaming")
+	if env == xdgDataHomeEnv {
+		appData, dir = "LOCALAPPDATA", filepath.Join("AppData", "Local")
+	}
+
+	if d := os.Getenv(appData); filepath.IsAbs(d) {
+		return d
+	}
+
+	return filepath.Join(home, dir)
+}
+
+// crossDevice reports whether a rename failed as the paths are on different volumes.
+func crossDevice(err error) bool {
+	return errors.Is(err, windows.ERROR_NOT_SAME_DEVICE)
+}
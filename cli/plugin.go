@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// pluginPrefix is the executable name prefix of plugins,
+	// e.g., 'vlt foo' runs the 'vlt-foo' executable found on PATH.
+	pluginPrefix = "vlt-"
+
+	// pluginAnnotation marks plugin commands, its value is the plugin executable path.
+	pluginAnnotation = "vlt_plugin"
+)
+
+// Environment variables passed to plugins.
+const (
+	envPluginBin             = "VLT_BIN"
+	envPluginVaultPath       = "VLT_VAULT_PATH"
+	envPluginSessionSocket   = envSessionSocketKey
+	envPluginSessionDuration = "VLT_SESSION_DURATION"
+)
+
+type PluginError struct {
+	Name string
+	Err  error
+}
+
+func (e *PluginError) Error() string { return "plugin " + e.Name + ": " + e.Err.Error() }
+
+func (e *PluginError) Unwrap() error { return e.Err }
+
+// PluginOptions holds data required to run the command.
+type PluginOptions struct {
+	*genericclioptions.StdioOptions
+
+	config *ConfigOptions
+	name   string
+	path   string
+}
+
+var _ genericclioptions.CmdOptions = &PluginOptions{}
+
+// NewPluginOptions initializes the options struct.
+func NewPluginOptions(stdio *genericclioptions.StdioOptions, config *ConfigOptions, name, path string) *PluginOptions {
+	return &PluginOptions{
+		StdioOptions: stdio,
+		config:       config,
+		name:         name,
+		path:         path,
+	}
+}
+
+func (*PluginOptions) Complete() error { return nil }
+
+func (*PluginOptions) Validate() error { return nil }
+
+func (o *PluginOptions) Run(ctx context.Context, args ...string) error {
+	env, err := o.env()
+	if err != nil {
+		return &PluginError{o.name, err}
+	}
+
+	cmd := exec.CommandContext(ctx, o.path, args...) //nolint:gosec // the plugin is chosen by the user
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = o.In, o.Out, o.ErrOut
+	cmd.Env = append(os.Environ(), env...)
+
+	o.Debugf("running plugin %s\n", o.path)
+
+	err = cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	// the plugin reports its own failures, vlt exits with its status.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return &clierror.ExitStatusError{Code: exitErr.ExitCode()}
+	}
+
+	if err != nil {
+		return &PluginError{o.name, err}
+	}
+
+	return nil
+}
+
+// env returns the environment variables describing the vault and session to the plugin.
+// VLT_CONFIG_PATH is set to the loaded config file, so vlt commands run by the plugin
+// use the same configuration.
+func (o *PluginOptions) env() ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := o.config.resolved
+
+	env := []string{
+		envPluginBin + "=" + exe,
+		envPluginVaultPath + "=" + resolved.VaultPath,
+	}
+
+	if len(o.config.fileConfig.path) > 0 {
+		env = append(env, envConfigPathKey+"="+o.config.fileConfig.path)
+	}
+
+	if resolved.enableSession {
+		env = append(env,
+			envPluginSessionSocket+"="+vaultdaemon.SocketPath(),
+			envPluginSessionDuration+"="+resolved.SessionDuration.String(),
+		)
+	}
+
+	return env, nil
+}
+
+// addPluginCommand adds the command running the plugin named by args to root,
+// if the command name in args is not a built-in command and a 'vlt-<name>'
+// executable is found on PATH. It returns args with the plugin arguments
+// following a "--" terminator, so they are passed to the plugin unparsed.
+func addPluginCommand(o *DefaultVltOptions, root *cobra.Command, args []string) []string {
+	i, _ := scanRootArgs(root.PersistentFlags(), args)
+	if i < 0 || isBuiltinCommand(root, args[i]) || !aliasNameRE.MatchString(args[i]) {
+		return args
+	}
+
+	name := args[i]
+
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return args
+	}
+
+	root.AddCommand(NewCmdPlugin(o, name, path))
+
+	return slices.Concat(args[:i+1], []string{"--"}, args[i+1:])
+}
+
+// isPluginCommand reports whether cmd runs a plugin.
+func isPluginCommand(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+
+	_, ok := cmd.Annotations[pluginAnnotation]
+
+	return ok
+}
+
+// NewCmdPlugin creates the cobra command running the plugin executable at path.
+func NewCmdPlugin(defaults *DefaultVltOptions, name, path string) *cobra.Command {
+	o := NewPluginOptions(defaults.StdioOptions, defaults.configOptions, name, path)
+
+	return &cobra.Command{
+		Use:         name + " [args...]",
+		Short:       "Run the " + path + " plugin",
+		Args:        cobra.ArbitraryArgs,
+		Hidden:      true,
+		Annotations: map[string]string{pluginAnnotation: path},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+}
@@ -3,12 +3,12 @@ package cli
 import (
 	"context"
 	"fmt"
-	"io"
 	"slices"
 	"strings"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
 	"github.com/ladzaretti/vlt-cli/input"
 	"github.com/ladzaretti/vlt-cli/vaulterrors"
 
@@ -70,7 +70,7 @@ func (o *RemoveOptions) Run(ctx context.Context, args ...string) (retErr error)
 	count := len(matchingSecrets)
 
 	if count > 0 && !o.assumeYes {
-		printTable(o.Out, matchingSecrets)
+		o.printSecrets(ctx, o.Out, o.Styler(o.Out), matchingSecrets)
 	}
 
 	switch count {
@@ -88,7 +88,7 @@ func (o *RemoveOptions) Run(ctx context.Context, args ...string) (retErr error)
 	}
 
 	if !o.assumeYes {
-		yes, err := confirm(o.Out, o.In, "Delete %d secrets? (y/N): ", count)
+		yes, err := confirm(ctx, o.Prompter(), "Delete %d secrets? (y/N): ", count)
 		if err != nil {
 			return err
 		}
@@ -112,8 +112,8 @@ func (o *RemoveOptions) Run(ctx context.Context, args ...string) (retErr error)
 	return nil
 }
 
-func confirm(out io.Writer, in io.Reader, prompt string, a ...any) (bool, error) {
-	response, err := input.PromptRead(out, in, prompt, a...)
+func confirm(ctx context.Context, p input.Prompter, prompt string, a ...any) (bool, error) {
+	response, err := input.PromptRead(ctx, p, prompt, a...)
 	if err != nil {
 		return false, err
 	}
@@ -133,7 +133,7 @@ func NewCmdRemove(defaults *DefaultVltOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "remove [glob]",
 		Aliases: []string{"rm", "delete"},
-		Short:   "Remove secrets",
+		Short:   i18n.T("Remove secrets"),
 		Long: `Remove one or more secrets from the vault.
 
 You may optionally provide a glob pattern to match against secret names or labels.
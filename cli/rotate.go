@@ -10,13 +10,26 @@ import (
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
 	"github.com/ladzaretti/vlt-cli/input"
+	"github.com/ladzaretti/vlt-cli/redact"
+	"github.com/ladzaretti/vlt-cli/securebytes"
 	"github.com/ladzaretti/vlt-cli/vault"
 	"github.com/ladzaretti/vlt-cli/vaulterrors"
 
 	"github.com/spf13/cobra"
 )
 
+const (
+	// envOldPasswordFileKey is the environment variable key of the file
+	// the current password is read from by rotate.
+	envOldPasswordFileKey = "VLT_OLD_PASSWORD_FILE"
+
+	// envNewPasswordFileKey is the environment variable key of the file
+	// the new password is read from by rotate.
+	envNewPasswordFileKey = "VLT_NEW_PASSWORD_FILE"
+)
+
 type RotateError struct {
 	Err error
 }
@@ -29,7 +42,12 @@ func (e *RotateError) Unwrap() error { return e.Err }
 type RotateOptions struct {
 	*genericclioptions.StdioOptions
 
-	vaultOptions *VaultOptions
+	vaultOptions    *VaultOptions
+	weakOK          bool   // weakOK accepts a new master password below the strength threshold with a warning.
+	oldPasswordFD   int    // oldPasswordFD is the file descriptor the current password is read from, if not negative.
+	newPasswordFD   int    // newPasswordFD is the file descriptor the new password is read from, if not negative.
+	oldPasswordFile string // oldPasswordFile is the file the current password is read from, set by $VLT_OLD_PASSWORD_FILE.
+	newPasswordFile string // newPasswordFile is the file the new password is read from, set by $VLT_NEW_PASSWORD_FILE.
 }
 
 var _ genericclioptions.CmdOptions = &RotateOptions{}
@@ -37,20 +55,62 @@ var _ genericclioptions.CmdOptions = &RotateOptions{}
 // NewRotateOptions initializes the options struct.
 func NewRotateOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *RotateOptions {
 	return &RotateOptions{
-		StdioOptions: stdio,
-		vaultOptions: vaultOptions,
+		StdioOptions:  stdio,
+		vaultOptions:  vaultOptions,
+		oldPasswordFD: -1,
+		newPasswordFD: -1,
 	}
 }
 
 func (o *RotateOptions) Complete() error {
+	o.oldPasswordFile = os.Getenv(envOldPasswordFileKey)
+	o.newPasswordFile = os.Getenv(envNewPasswordFileKey)
+
 	return o.vaultOptions.Complete()
 }
 
 func (o *RotateOptions) Validate() error {
-	if o.StdinIsPiped {
+	if err := o.validatePasswordInputs(); err != nil {
+		return &RotateError{err}
+	}
+
+	if o.StdinIsPiped && !o.nonInteractive() {
 		return vaulterrors.ErrNonInteractiveUnsupported
 	}
 
+	return o.vaultOptions.verifyPath(o.StdioOptions)
+}
+
+// nonInteractive reports whether the passwords are read from file descriptors
+// or files instead of being prompted for.
+func (o *RotateOptions) nonInteractive() bool {
+	return o.oldPasswordFD >= 0 || len(o.oldPasswordFile) > 0
+}
+
+// validatePasswordInputs verifies that the old and new passwords are either both
+// prompted for, or both read from distinct file descriptors or files.
+func (o *RotateOptions) validatePasswordInputs() error {
+	oldSet := o.oldPasswordFD >= 0 || len(o.oldPasswordFile) > 0
+	newSet := o.newPasswordFD >= 0 || len(o.newPasswordFile) > 0
+
+	switch {
+	case o.oldPasswordFD >= 0 && len(o.oldPasswordFile) > 0:
+		return fmt.Errorf("--old-password-fd and $%s are mutually exclusive", envOldPasswordFileKey)
+
+	case o.newPasswordFD >= 0 && len(o.newPasswordFile) > 0:
+		return fmt.Errorf("--new-password-fd and $%s are mutually exclusive", envNewPasswordFileKey)
+
+	case oldSet != newSet:
+		return fmt.Errorf("the old and new passwords are read non-interactively together, set --old-password-fd or $%s, and --new-password-fd or $%s",
+			envOldPasswordFileKey, envNewPasswordFileKey)
+
+	case o.oldPasswordFD >= 0 && o.oldPasswordFD == o.newPasswordFD:
+		return fmt.Errorf("the old and new passwords are read from distinct file descriptors, both are %d", o.oldPasswordFD)
+
+	case len(o.oldPasswordFile) > 0 && filepath.Clean(o.oldPasswordFile) == filepath.Clean(o.newPasswordFile):
+		return fmt.Errorf("the old and new passwords are read from distinct files, both are %q", o.oldPasswordFile)
+	}
+
 	return nil
 }
 
@@ -71,6 +131,23 @@ func (o *RotateOptions) Run(ctx context.Context, _ ...string) (retErr error) { /
 	if err != nil {
 		return err
 	}
+	defer func() { //nolint:wsl_v5
+		for _, s := range secrets {
+			securebytes.Wipe(s.Value)
+		}
+	}()
+
+	// members are re-added, so their key slots wrap the new vault key.
+	members, err := srcVault.Members(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the rotated vault keeps its identity, e.g., its daemon sessions and title.
+	identity, err := srcVault.Identity(ctx)
+	if err != nil {
+		return err
+	}
 
 	err = srcVault.Close()
 	if err != nil {
@@ -92,7 +169,7 @@ func (o *RotateOptions) Run(ctx context.Context, _ ...string) (retErr error) { /
 		}
 	}()
 
-	destVault, err := o.openDestVault(ctx, filepath.Join(dir, ".vlt.tmp"))
+	destVault, err := o.openDestVault(ctx, filepath.Join(dir, ".vlt.tmp"), identity)
 	if err != nil {
 		return err
 	}
@@ -100,17 +177,24 @@ func (o *RotateOptions) Run(ctx context.Context, _ ...string) (retErr error) { /
 		retErr = errors.Join(retErr, destVault.Close())
 	}()
 
-	i := 0
+	newSecrets := make([]vault.NewSecret, 0, len(secrets))
 	for id, s := range secrets {
-		_, err := destVault.InsertNewSecret(ctx, s.Name, s.Value, s.Labels, vault.InsertWithID(id))
-		if err != nil {
+		newSecrets = append(newSecrets, vault.NewSecret{ID: id, Name: s.Name, Value: s.Value, Labels: s.Labels})
+	}
+
+	if _, err := destVault.InsertNewSecrets(ctx, newSecrets); err != nil {
+		return err
+	}
+
+	o.Debugf("number of secrets rotated: %d", len(newSecrets))
+
+	for _, m := range members {
+		if err := destVault.AddMember(ctx, m.Name, m.Recipient); err != nil {
 			return err
 		}
-
-		i++
 	}
 
-	o.Debugf("number of secrets rotated: %d", i)
+	o.Debugf("number of members rotated: %d", len(members))
 
 	if _, err := destVault.Seal(ctx); err != nil {
 		return fmt.Errorf("create: %w", err)
@@ -120,12 +204,20 @@ func (o *RotateOptions) Run(ctx context.Context, _ ...string) (retErr error) { /
 		return err
 	}
 
+	if err := restrictVaultFile(destVault.Path); err != nil {
+		return err
+	}
+
 	o.Debugf("rotating vault: from %q to %q", srcVault.Path, destVault.Path)
 
 	if err := os.Rename(destVault.Path, srcVault.Path); err != nil {
 		return err
 	}
 
+	if err := syncDir(filepath.Dir(srcVault.Path)); err != nil {
+		return err
+	}
+
 	o.Infof("vault rotated successfully\n")
 
 	if err := o.vaultOptions.postWriteHook(ctx, o.StdioOptions); err != nil {
@@ -138,36 +230,100 @@ func (o *RotateOptions) Run(ctx context.Context, _ ...string) (retErr error) { /
 func (o *RotateOptions) openSrcVault(ctx context.Context) (*vault.Vault, error) {
 	path := o.vaultOptions.path
 
-	password, err := input.PromptReadSecure(o.Out, int(o.In.Fd()), "[vlt] Password for %q:", path)
+	password, err := o.readOldPassword(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer securebytes.Wipe(password)
+
+	key, nonce, err := o.vaultOptions.passwordLogin(ctx, o.StdioOptions, password)
+	if err != nil {
+		return nil, err
+	}
+	defer securebytes.Wipe(key)
+
+	redact.Add(password, key, nonce)
+
+	if err := o.vaultOptions.postLoginHook(ctx, o.StdioOptions); err != nil {
+		return nil, err
+	}
+
+	return vault.Open(ctx, path, vault.WithSessionKey(key, nonce), redactSecrets)
+}
+
+// readOldPassword reads the current password from --old-password-fd
+// or $VLT_OLD_PASSWORD_FILE if set, or prompts for it otherwise.
+func (o *RotateOptions) readOldPassword(ctx context.Context) ([]byte, error) {
+	switch {
+	case o.oldPasswordFD >= 0:
+		return readPasswordFD(o.oldPasswordFD)
+	case len(o.oldPasswordFile) > 0:
+		return readPasswordFile(o.oldPasswordFile)
+	}
+
+	password, err := input.PromptReadSecure(ctx, o.Prompter(), "[vlt] Password for %q:", o.vaultOptions.path)
 	if err != nil {
 		return nil, fmt.Errorf("prompt password: %v", err)
 	}
-	defer clear(password)
 
 	if len(password) == 0 {
 		return nil, vaulterrors.ErrEmptyPassword
 	}
 
-	key, nonce, err := vault.Login(ctx, path, password)
+	return password, nil
+}
+
+// readNewPassword reads the new password from --new-password-fd
+// or $VLT_NEW_PASSWORD_FILE if set, refused unless it satisfies
+// the password policy, or prompts for it otherwise.
+func (o *RotateOptions) readNewPassword(ctx context.Context) ([]byte, error) {
+	policy := o.vaultOptions.newPasswordPolicy(o.weakOK)
+
+	var (
+		password []byte
+		err      error
+	)
+
+	switch {
+	case o.newPasswordFD >= 0:
+		password, err = readPasswordFD(o.newPasswordFD)
+	case len(o.newPasswordFile) > 0:
+		password, err = readPasswordFile(o.newPasswordFile)
+	default:
+		return input.PromptNewPassword(ctx, o.Prompter(), policy)
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	if err := o.vaultOptions.postLoginHook(ctx, o.StdioOptions); err != nil {
-		return nil, err
+	if err := policy.Check(password); err != nil {
+		securebytes.Wipe(password)
+		return nil, fmt.Errorf("new password: %w", err)
 	}
 
-	return vault.Open(ctx, path, vault.WithSessionKey(key, nonce))
+	if strength := input.EstimateStrength(password); strength.Bits < policy.MinBits {
+		o.Errorf("weak password accepted, %s, at least %d bits recommended\n", strength, policy.MinBits)
+	}
+
+	return password, nil
 }
 
-func (o *RotateOptions) openDestVault(ctx context.Context, path string) (*vault.Vault, error) {
-	password, err := input.PromptNewPassword(o.Out, int(o.In.Fd()), masterPasswordMinLen)
+func (o *RotateOptions) openDestVault(ctx context.Context, path string, identity *vault.Identity) (*vault.Vault, error) {
+	password, err := o.readNewPassword(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("create: %w", err)
 	}
-	defer clear(password)
+	defer securebytes.Wipe(password)
+
+	redact.Add(password)
 
-	return vault.New(ctx, path, password, vault.WithMaxHistorySnapshots(o.vaultOptions.maxHistorySnapshots))
+	return vault.New(ctx, path, password,
+		vault.WithMaxHistorySnapshots(o.vaultOptions.maxHistorySnapshots),
+		vault.WithAutoVacuumThreshold(int64(o.vaultOptions.autoVacuumThreshold)<<10),
+		vault.WithIdentity(*identity),
+		redactSecrets,
+	)
 }
 
 // NewCmdRotate creates the create cobra command.
@@ -177,12 +333,27 @@ func NewCmdRotate(defaults *DefaultVltOptions) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "rotate",
-		Short: "Rotate the master password",
+		Short: i18n.T("Rotate the master password"),
 		Long: fmt.Sprintf(`Securely change the master password of a vault.
 
 The vault will be re-encrypted using the new password.
 
-If no --file path is provided, uses the default path (~/%s).`, defaultDatabaseFilename),
+If no --file path is provided, uses the default path (%s).
+
+The new password is subject to the same policy as in 'vlt create'.
+
+For scheduled rotation, the passwords are read without prompting from the first line
+of distinct file descriptors, set by --old-password-fd and --new-password-fd, or files,
+set by $%s and $%s. Passwords are never read from the command line.
+A new password not satisfying the policy is refused.`, defaultVaultPathHelp, envOldPasswordFileKey, envNewPasswordFileKey),
+		Example: `  # Rotate the master password, prompting for the current and the new one
+  vlt rotate
+
+  # Rotate the master password non-interactively, reading the passwords from file descriptors
+  vlt rotate --old-password-fd 3 --new-password-fd 4 3<old.txt 4<new.txt
+
+  # Rotate the master password non-interactively, reading the passwords from files
+  VLT_OLD_PASSWORD_FILE=/run/secrets/old VLT_NEW_PASSWORD_FILE=/run/secrets/new vlt rotate`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return cmp.Or(
 				clierror.Check(genericclioptions.RejectDisallowedFlags(cmd, hiddenFlags...)),
@@ -191,7 +362,22 @@ If no --file path is provided, uses the default path (~/%s).`, defaultDatabaseFi
 		},
 	}
 
+	cmd.Flags().BoolVarP(&o.weakOK, "weak-ok", "", false, "accept a weak master password with a warning instead of refusing it")
+	cmd.Flags().IntVarP(&o.oldPasswordFD, "old-password-fd", "", -1, "read the current password from the given file descriptor, e.g., 3 for '3<old.txt'")
+	cmd.Flags().IntVarP(&o.newPasswordFD, "new-password-fd", "", -1, "read the new password from the given file descriptor, e.g., 4 for '4<new.txt'")
+
 	genericclioptions.MarkFlagsHidden(cmd, hiddenFlags...)
 
 	return cmd
 }
+
+// syncDir flushes the directory entry changes of dir to disk,
+// making a preceding rename durable.
+func syncDir(dir string) error {
+	f, err := os.Open(dir) //nolint:gosec // dir is the vault directory
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(f.Sync(), f.Close())
+}
@@ -2,16 +2,23 @@ package cli
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"slices"
 	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/clipboard"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
 	"github.com/ladzaretti/vlt-cli/input"
 	"github.com/ladzaretti/vlt-cli/randstring"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault"
 	"github.com/ladzaretti/vlt-cli/vaulterrors"
 
 	"github.com/spf13/cobra"
@@ -34,36 +41,95 @@ type SaveOptions struct {
 
 	name           string   // name is the name of the secret to save in the vault.
 	labels         []string // labels to associate with the a given secret.
+	url            string   // url is the site the secret belongs to, see [vault.NormalizeURL].
 	generate       bool     // generate indicates whether to auto-generate a random secret.
 	output         bool     // output controls whether to print the saved secret to stdout.
 	copy           bool     // copy controls whether to copy the saved secret to the clipboard.
 	paste          bool     // paste controls whether to read the secret to save from the clipboard.
 	nonInteractive bool     // nonInteractive disables all interactive prompts.
+	batch          bool     // batch saves a JSON array of secrets read from stdin, see [batchSecret].
+	stripNewline   bool     // stripNewline removes a single trailing newline of piped or pasted input.
+
+	config       *ResolvedConfig
+	templateName string            // templateName selects a template from the config, see [TemplateConfig].
+	fieldPairs   []string          // fieldPairs holds the raw --field key=value template field values.
+	template     *TemplateConfig   // template is the selected template, nil if none.
+	fields       map[string]string // fields holds the template field values.
+	attrs        map[string]string // attrs holds the expanded template attributes.
 }
 
 var _ genericclioptions.CmdOptions = &SaveOptions{}
 
 // NewSaveOptions initializes the options struct.
-func NewSaveOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *SaveOptions {
+func NewSaveOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions, config *ResolvedConfig) *SaveOptions {
 	return &SaveOptions{
 		StdioOptions: stdio,
 		VaultOptions: vaultOptions,
+		config:       config,
 	}
 }
 
 func (*SaveOptions) Complete() error { return nil }
 
 func (o *SaveOptions) Validate() error {
+	if o.batch {
+		return o.validateBatch()
+	}
+
 	if strings.HasPrefix(o.name, "-") {
 		return fmt.Errorf("invalid --name value %q (must not start with '-')", o.name)
 	}
 
+	if len(o.url) > 0 {
+		if _, err := vault.NormalizeURL(o.url); err != nil {
+			return &SaveError{err}
+		}
+	}
+
+	if err := o.validateTemplate(); err != nil {
+		return &SaveError{err}
+	}
+
 	return o.validateInputSource()
 }
 
+func (o *SaveOptions) validateTemplate() error {
+	if len(o.templateName) == 0 {
+		if len(o.fieldPairs) > 0 {
+			return errors.New("--field requires --template")
+		}
+
+		return nil
+	}
+
+	if len(o.name) > 0 {
+		return errors.New("--name cannot be used with --template, the template defines the name")
+	}
+
+	t, err := lookupTemplate(o.config.Templates, o.templateName)
+	if err != nil {
+		return err
+	}
+
+	fields, err := parseAttributes(o.fieldPairs, true)
+	if err != nil {
+		return err
+	}
+
+	for f := range fields {
+		if !slices.Contains(t.Fields, f) {
+			return fmt.Errorf("template %q has no field %q", o.templateName, f)
+		}
+	}
+
+	o.template, o.fields = &t, fields
+
+	return nil
+}
+
 func (o *SaveOptions) Run(ctx context.Context, _ ...string) (retErr error) {
 	var secret []byte
-	defer clear(secret)
+	defer func() { securebytes.Wipe(secret) }() // deferred closure, secret is reassigned below.
 
 	// ensure error is wrapped and output is printed if everything succeeded
 	defer func() {
@@ -80,6 +146,10 @@ func (o *SaveOptions) Run(ctx context.Context, _ ...string) (retErr error) {
 		}
 	}()
 
+	if o.batch {
+		return o.saveBatch(ctx)
+	}
+
 	s, err := o.readSecretNonInteractive()
 	if err != nil {
 		return fmt.Errorf("read secret non-interactive: %w", err)
@@ -87,7 +157,13 @@ func (o *SaveOptions) Run(ctx context.Context, _ ...string) (retErr error) {
 
 	secret = s
 
-	err = o.readInteractive(&secret)
+	if o.template != nil {
+		if err := o.applyTemplate(ctx); err != nil {
+			return err
+		}
+	}
+
+	err = o.readInteractive(ctx, &secret)
 	if err != nil {
 		return err
 	}
@@ -100,34 +176,94 @@ func (o *SaveOptions) Run(ctx context.Context, _ ...string) (retErr error) {
 		o.Errorf("no name or labels provided; use `vlt update` to add metadata later\n")
 	}
 
+	warnSecretLikeName(o.StdioOptions, o.name)
+
+	if o.StdinIsPiped {
+		o.warnNameLikeValue(ctx, o.StdioOptions, secret)
+	}
+
+	if err := o.lintSecret(o.StdioOptions, o.name, o.labels, secret); err != nil {
+		return err
+	}
+
 	return o.insertNewSecret(ctx, secret)
 }
 
 func (o *SaveOptions) readSecretNonInteractive() ([]byte, error) {
 	if o.generate {
-		return randstring.NewWithPolicy(randstring.DefaultPasswordPolicy)
+		return randstring.NewWithPolicy(o.passwordPolicy())
 	}
 
 	if o.paste {
 		o.Debugf("reading secret from clipboard")
-		return clipboard.Paste()
+		return pasteSecret(o.stripNewline)
 	}
 
 	if o.StdinIsPiped {
 		o.Debugf("reading non-interactive secret")
-		return io.ReadAll(o.In)
+		return readSecretInput(o.In, o.stripNewline)
+	}
+
+	if o.template != nil && o.template.Generate {
+		o.Debugf("generating secret as set by template %q\n", o.templateName)
+		return randstring.NewWithPolicy(o.passwordPolicy())
 	}
 
 	return nil, nil
 }
 
-func (o *SaveOptions) readInteractive(secret *[]byte) error {
+// passwordPolicy returns the policy of generated secrets,
+// set by the template, if any.
+func (o *SaveOptions) passwordPolicy() randstring.PasswordPolicy {
+	if o.template != nil {
+		return o.template.passwordPolicy()
+	}
+
+	return randstring.DefaultPasswordPolicy
+}
+
+// applyTemplate reads the template fields not set by --field,
+// and derives the secret name, labels and attributes from the template.
+func (o *SaveOptions) applyTemplate(ctx context.Context) error {
+	for _, f := range o.template.Fields {
+		if _, ok := o.fields[f]; ok {
+			continue
+		}
+
+		if o.StdinIsPiped || o.nonInteractive {
+			return fmt.Errorf("template %q: missing field %q, set it using --field %s=value", o.templateName, f, f)
+		}
+
+		v, err := o.promptRead(ctx, "Enter %s: ", f)
+		if err != nil {
+			return fmt.Errorf("template field read interactive: %w", err)
+		}
+
+		if len(v) == 0 {
+			return fmt.Errorf("template %q: field %q must not be empty", o.templateName, f)
+		}
+
+		o.fields[f] = v
+	}
+
+	o.name = o.template.expand(o.template.Name, o.fields)
+	o.labels = append(slices.Clone(o.template.Labels), o.labels...)
+
+	o.attrs = make(map[string]string, len(o.template.Attrs))
+	for k, v := range o.template.Attrs {
+		o.attrs[k] = o.template.expand(v, o.fields)
+	}
+
+	return nil
+}
+
+func (o *SaveOptions) readInteractive(ctx context.Context, secret *[]byte) error {
 	if o.StdinIsPiped || o.nonInteractive {
 		return nil
 	}
 
 	if len(o.name) == 0 {
-		k, err := o.promptRead("Enter name: ")
+		k, err := o.promptRead(ctx, "Enter name: ")
 		if err != nil {
 			return fmt.Errorf("name read interactive: %w", err)
 		}
@@ -136,7 +272,7 @@ func (o *SaveOptions) readInteractive(secret *[]byte) error {
 	}
 
 	if len(*secret) == 0 {
-		s, err := o.promptReadSecure("Enter secret for name %q: ", o.name)
+		s, err := o.promptReadSecure(ctx, "Enter secret for name %q: ", o.name)
 		if err != nil {
 			return err
 		}
@@ -144,8 +280,8 @@ func (o *SaveOptions) readInteractive(secret *[]byte) error {
 		*secret = s
 	}
 
-	if len(o.labels) == 0 {
-		labels, err := o.promptRead("Enter labels (comma-separated), or press Enter to skip: ")
+	if len(o.labels) == 0 && o.template == nil {
+		labels, err := o.promptRead(ctx, "Enter labels (comma-separated), or press Enter to skip: ")
 		if err != nil {
 			return fmt.Errorf("label read interactive: %w", err)
 		}
@@ -156,12 +292,12 @@ func (o *SaveOptions) readInteractive(secret *[]byte) error {
 	return nil
 }
 
-func (o *SaveOptions) promptRead(prompt string, a ...any) (string, error) {
-	return input.PromptRead(o.Out, o.In, prompt, a...)
+func (o *SaveOptions) promptRead(ctx context.Context, prompt string, a ...any) (string, error) {
+	return input.PromptRead(ctx, o.Prompter(), prompt, a...)
 }
 
-func (o *SaveOptions) promptReadSecure(prompt string, a ...any) ([]byte, error) {
-	return input.PromptReadSecure(o.Out, int(o.In.Fd()), prompt, a...)
+func (o *SaveOptions) promptReadSecure(ctx context.Context, prompt string, a ...any) ([]byte, error) {
+	return input.PromptReadSecure(ctx, o.Prompter(), prompt, a...)
 }
 
 func (o *SaveOptions) insertNewSecret(ctx context.Context, s []byte) error {
@@ -174,13 +310,22 @@ func (o *SaveOptions) insertNewSecret(ctx context.Context, s []byte) error {
 		return ErrNoSecretInserted
 	}
 
+	if len(o.url) > 0 {
+		if err := o.vault.SetSecretURL(ctx, n, o.url); err != nil {
+			return err
+		}
+	}
+
+	if len(o.attrs) > 0 {
+		return o.vault.UpdateSecretAttributes(ctx, n, o.attrs, nil)
+	}
+
 	return nil
 }
 
 func (o *SaveOptions) outputSecret(s []byte) error {
 	if o.output {
-		o.Infof("%s", s)
-		return nil
+		return writeSecret(o.Out, s)
 	}
 
 	if o.copy {
@@ -212,20 +357,208 @@ func (o *SaveOptions) validateInputSource() error {
 	return nil
 }
 
+// maxBatchInputSize is the maximal size of the JSON array read by 'vlt save --batch'.
+const maxBatchInputSize = 32 << 20
+
+// batchSecret is a secret of the JSON array read by 'vlt save --batch'.
+type batchSecret struct {
+	Name   string
+	Value  []byte
+	Labels []string
+}
+
+// UnmarshalJSON decodes the "name", "value" and "labels" fields of a secret,
+// rejecting unknown fields. The value is decoded without an intermediate
+// string, which could not be wiped, see [unquoteJSON].
+func (s *batchSecret) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	defer func() {
+		for _, raw := range fields {
+			securebytes.Wipe(raw)
+		}
+	}()
+
+	for k, raw := range fields {
+		var err error
+
+		switch k {
+		case "name":
+			err = json.Unmarshal(raw, &s.Name)
+		case "value":
+			s.Value, err = unquoteJSON(raw)
+		case "labels":
+			err = json.Unmarshal(raw, &s.Labels)
+		default:
+			err = fmt.Errorf("unknown field %q", k)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unquoteJSON decodes the JSON string raw, validated by [json.Unmarshal],
+// into a new slice. The decoded string is never longer than raw,
+// so the slice is never grown, leaving no unwiped copies behind.
+func unquoteJSON(raw []byte) ([]byte, error) {
+	if string(raw) == "null" {
+		return nil, nil
+	}
+
+	if len(raw) < 2 || raw[0] != '"' {
+		return nil, errors.New("value must be a string")
+	}
+
+	raw = raw[1 : len(raw)-1]
+	b := make([]byte, 0, len(raw))
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			b = append(b, raw[i])
+			continue
+		}
+
+		i++
+
+		switch raw[i] {
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'u':
+			r := hexRune(raw[i+1 : i+5])
+			i += 4
+
+			if utf16.IsSurrogate(r) && i+6 < len(raw) && raw[i+1] == '\\' && raw[i+2] == 'u' {
+				if d := utf16.DecodeRune(r, hexRune(raw[i+3:i+7])); d != utf8.RuneError {
+					r = d
+					i += 6
+				}
+			}
+
+			b = utf8.AppendRune(b, r) // unpaired surrogates are appended as [utf8.RuneError].
+		default: // '"', '\\' and '/'.
+			b = append(b, raw[i])
+		}
+	}
+
+	return b, nil
+}
+
+// hexRune decodes the 4 hex digits of a JSON \u escape.
+func hexRune(digits []byte) rune {
+	var d [2]byte
+	_, _ = hex.Decode(d[:], digits) // validated by [json.Unmarshal].
+
+	return rune(d[0])<<8 | rune(d[1])
+}
+
+func (o *SaveOptions) validateBatch() error {
+	if !o.StdinIsPiped {
+		return &SaveError{errors.New("--batch reads a JSON array of secrets from piped or redirected input")}
+	}
+
+	if len(o.name) > 0 || len(o.url) > 0 || len(o.templateName) > 0 || o.generate || o.paste || o.output || o.copy {
+		return &SaveError{errors.New("--batch cannot be used with --name, --url, --template, --generate, --paste-clipboard, --output or --copy-clipboard")}
+	}
+
+	return nil
+}
+
+// saveBatch reads a JSON array of secrets from stdin and inserts them
+// in a single transaction, --label values are added to every secret.
+func (o *SaveOptions) saveBatch(ctx context.Context) error {
+	input, err := readAllLimited(o.In, maxBatchInputSize)
+	if err != nil {
+		return fmt.Errorf("batch: %w", err)
+	}
+	defer securebytes.Wipe(input)
+
+	var batch []batchSecret
+	defer func() {
+		for _, b := range batch {
+			securebytes.Wipe(b.Value)
+		}
+	}()
+
+	if err := json.Unmarshal(input, &batch); err != nil {
+		return fmt.Errorf("batch: decode: %w", err)
+	}
+
+	if len(batch) == 0 {
+		return ErrNoSecretInserted
+	}
+
+	secrets := make([]vault.NewSecret, 0, len(batch))
+
+	for i, b := range batch {
+		if strings.HasPrefix(b.Name, "-") {
+			return fmt.Errorf("batch: secret %d: invalid name %q (must not start with '-')", i, b.Name)
+		}
+
+		if len(b.Value) == 0 {
+			return fmt.Errorf("batch: secret %d (%q): %w", i, b.Name, vaulterrors.ErrEmptySecret)
+		}
+
+		warnSecretLikeName(o.StdioOptions, b.Name)
+
+		secrets = append(secrets, vault.NewSecret{
+			Name:   b.Name,
+			Value:  b.Value, // wiped along with the batch.
+			Labels: append(slices.Clone(b.Labels), o.labels...),
+		})
+
+		if err := o.lintSecret(o.StdioOptions, b.Name, secrets[i].Labels, secrets[i].Value); err != nil {
+			return fmt.Errorf("batch: secret %d: %w", i, err)
+		}
+	}
+
+	ids, err := o.vault.InsertNewSecrets(ctx, secrets)
+	if err != nil {
+		return err
+	}
+
+	saved := make([]secretWithLabels, len(ids))
+	for i, id := range ids {
+		saved[i] = secretWithLabels{id: id, name: secrets[i].Name, labels: secrets[i].Labels}
+	}
+
+	o.printSecrets(ctx, o.Out, o.Styler(o.Out), saved)
+	o.Infof("saved %d secrets\n", len(saved))
+
+	return nil
+}
+
 // NewCmdSave creates the save cobra command.
 func NewCmdSave(defaults *DefaultVltOptions) *cobra.Command {
 	o := NewSaveOptions(
 		defaults.StdioOptions,
 		defaults.vaultOptions,
+		defaults.configOptions.resolved,
 	)
 
 	cmd := &cobra.Command{
 		Use:     "save",
 		Aliases: []string{"put"},
-		Short:   "Save a new secret",
+		Short:   i18n.T("Save a new secret"),
 		Long: `Save a new key-value pair to the vault.
 
 The secret value can be provided via prompt, clipboard, random generation, or piped input.
+Piped input is saved as is, including binary content and trailing newlines,
+use --strip-newline to remove a single trailing newline, e.g., the one added by 'echo'.
 Metadata (e.g., name and labels) can be provided via command-line arguments or prompted.
 
 Note 1:
@@ -233,15 +566,59 @@ Note 1:
 
 Note 2:
 	If data is piped or redirected into the command (i.e., stdin is not a TTY),
-	metadata must be provided as command-line arguments. Interactive prompts will be skipped in this case.`,
+	metadata must be provided as command-line arguments. Interactive prompts will be skipped in this case.
+
+Note 3:
+	A warning is printed if the name looks like a secret, or if piped input looks like
+	a name, e.g., an email address or the name of another secret, as if the two were swapped.
+
+Templates:
+	Use --template to save a secret from a template defined in the config file.
+	Only the template fields are prompted for, or set using --field.
+	The name, labels and attributes are derived from the template, and the value
+	is generated by the template policy if the template sets 'generate = true'.
+
+	[templates.aws-iam]
+	name = 'aws/{account}/{user}'
+	fields = ['account', 'user']
+	labels = ['aws', 'iam']
+	attrs = { account = '{account}' }
+	generate = true
+	policy = { min_length = 24, special = 0 }
+
+Batch:
+	Use --batch to save many secrets at once from a JSON array read from stdin,
+	e.g., [{"name": "foo", "value": "bar", "labels": ["baz"]}].
+	The secrets are saved in a single transaction, either all or none are saved,
+	and --label values are added to every secret. The array is limited to 32 MiB.
+
+Lint:
+	Lint rules defined in the config file check values before they are saved,
+	by 'vlt save' and 'vlt update secret', rejecting or warning about malformed
+	values, e.g., truncated keys. A rule checks a builtin type, aws-access-key,
+	jwt or pem, or matches a regular expression pattern, and applies to secrets
+	matching its names glob patterns or having one of its labels, or to all
+	secrets if neither is set. Use --skip-lint to save a value failing a rule.
+
+	[lint.aws-keys]
+	names = ['aws/*/access-key-id']
+	type = 'aws-access-key'
+
+	[lint.api-tokens]
+	labels = ['api']
+	pattern = '^tok_[a-z0-9]{32}$'
+	action = 'warn'`,
 		Example: `  # Save a secret interactively (prompts for name and value)
   vlt save
 
   # Save a named secret, prompting for the value
   vlt save --name foo
 
-  # Pipe a secret value from stdin (requires --name)
-  echo "bar" | vlt save --name foo
+  # Save a secret with the url of its site, see 'vlt show --url'
+  vlt save --name foo --url https://github.com/login
+
+  # Pipe a secret value from stdin (requires --name), without the trailing newline
+  echo "bar" | vlt save --name foo --strip-newline
 
   # Generate a random secret and copy to clipboard
   vlt save --name foo --generate --copy-clipboard
@@ -253,7 +630,16 @@ Note 2:
   vlt save --name foo < secret.file
 
   # Save a named secret with a piped value (non-interactive)
-  vlt generate -u3 -l3 -d3 -s3 | vlt save --name foo -N`,
+  vlt generate -u3 -l3 -d3 -s3 | vlt save --name foo -N
+
+  # Save a secret from a template, prompting for its fields
+  vlt save --template aws-iam
+
+  # Save a secret from a template, setting its fields (non-interactive)
+  vlt save --template aws-iam --field account=prod --field user=ci -N
+
+  # Save many secrets at once from a JSON array
+  vlt save --batch --label imported < secrets.json`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
 		},
@@ -264,9 +650,15 @@ Note 2:
 	cmd.Flags().BoolVarP(&o.copy, "copy-clipboard", "c", false, "copy the saved secret to the clipboard")
 	cmd.Flags().BoolVarP(&o.paste, "paste-clipboard", "p", false, "read the secret from the clipboard")
 	cmd.Flags().BoolVarP(&o.nonInteractive, "no-interactive", "N", false, "disable interactive prompts")
+	cmd.Flags().BoolVarP(&o.stripNewline, "strip-newline", "", false, "remove a single trailing newline from piped or pasted input")
+	cmd.Flags().BoolVarP(&o.batch, "batch", "", false, "save a JSON array of {name, value, labels} secrets read from stdin")
+	cmd.Flags().BoolVarP(&o.skipLint, "skip-lint", "", false, "save the value even if it fails the lint rules of the config file")
 
 	cmd.Flags().StringVarP(&o.name, "name", "", "", "the secret name (e.g., username)")
 	cmd.Flags().StringSliceVarP(&o.labels, "label", "", nil, "optional label to associate with the secret (comma-separated or repeated)")
+	cmd.Flags().StringVarP(&o.url, "url", "", "", "optional url of the site the secret belongs to")
+	cmd.Flags().StringVarP(&o.templateName, "template", "t", "", "save the secret using the given template from the config file")
+	cmd.Flags().StringArrayVarP(&o.fieldPairs, "field", "", nil, "template field value, as key=value")
 
 	return cmd
 }
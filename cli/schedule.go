@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+)
+
+// ScheduledJobs returns the jobs defined in the [daemon.schedule] section
+// of the given config, to be run by the vltd daemon.
+//
+// The config is expected to be validated, see [LoadFileConfig].
+func ScheduledJobs(c *FileConfig) ([]vaultdaemon.Job, error) {
+	var jobs []vaultdaemon.Job
+
+	if len(c.Daemon.Schedule.Backup) > 0 {
+		job, err := scheduledBackup(c)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// scheduledBackup returns a job backing up the configured vault
+// to the [backup] directory, with the configured retention.
+func scheduledBackup(c *FileConfig) (vaultdaemon.Job, error) {
+	interval, err := time.ParseDuration(c.Daemon.Schedule.Backup)
+	if err != nil {
+		return vaultdaemon.Job{}, &ConfigError{Opt: "daemon.schedule.backup", Err: err}
+	}
+
+	defaultPath, err := defaultVaultPath()
+	if err != nil {
+		return vaultdaemon.Job{}, err
+	}
+
+	b := &vaultBackup{
+		path: cmp.Or(c.Vault.Path, defaultPath),
+		dir:  c.Backup.Dir,
+		keep: defaultBackupKeep,
+	}
+
+	if c.Backup.Keep != nil {
+		b.keep = *c.Backup.Keep
+	}
+
+	job := vaultdaemon.Job{
+		Name:     "backup",
+		Interval: interval,
+		Run: func(ctx context.Context) (string, error) {
+			dest, pruned, err := b.run(ctx)
+			if err != nil {
+				return "", &BackupError{err}
+			}
+
+			return fmt.Sprintf("wrote %s, removed %d old backup(s)", dest, len(pruned)), nil
+		},
+	}
+
+	return job, nil
+}
@@ -1,14 +1,18 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"slices"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/ladzaretti/vlt-cli/fuzzy"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/style"
 	"github.com/ladzaretti/vlt-cli/vault"
 	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
 )
@@ -21,6 +25,7 @@ type SearchableOptions struct {
 	Name     string
 	Labels   []string
 	Wildcard string
+	Fuzzy    bool // Fuzzy matches Wildcard loosely, see [fuzzy.Score].
 }
 
 type Filter int
@@ -70,12 +75,26 @@ func (o *SearchableOptions) WildcardFrom(args []string) {
 	}
 }
 
+// secretSearcher queries secret metadata.
+//
+// It is implemented by both [vault.Vault] and [vault.Index].
+type secretSearcher interface {
+	SecretsByIDs(ctx context.Context, ids ...int) (map[int]vaultdb.SecretWithLabels, error)
+	FilterSecrets(ctx context.Context, wildcard string, name string, labels []string) (map[int]vaultdb.SecretWithLabels, error)
+	LabelsMeta(ctx context.Context) (map[string]vaultdb.LabelMeta, error)
+}
+
+var (
+	_ secretSearcher = &vault.Vault{}
+	_ secretSearcher = &vault.Index{}
+)
+
 // search queries the vault for secrets based on the fields
 // set in [genericclioptions.SearchOptions].
 //
 // For any matched secret, it returns all labels associated with it,
 // regardless of the filter options used.
-func (o *SearchableOptions) search(ctx context.Context, vault *vault.Vault) ([]secretWithLabels, error) {
+func (o *SearchableOptions) search(ctx context.Context, vault secretSearcher) ([]secretWithLabels, error) {
 	if o.ID > 0 {
 		return retrieveSortedByID(func() (map[int]vaultdb.SecretWithLabels, error) {
 			return vault.SecretsByIDs(ctx, o.ID)
@@ -88,6 +107,12 @@ func (o *SearchableOptions) search(ctx context.Context, vault *vault.Vault) ([]s
 		})
 	}
 
+	if o.Fuzzy && len(o.Wildcard) > 0 {
+		return retrieveSortedByScore(ctx, vault, o.Wildcard, func() (map[int]vaultdb.SecretWithLabels, error) {
+			return vault.FilterSecrets(ctx, "", o.Name, o.Labels)
+		})
+	}
+
 	retrieveSecretsFunc := func() (map[int]vaultdb.SecretWithLabels, error) {
 		return vault.FilterSecrets(ctx, o.Wildcard, o.Name, o.Labels)
 	}
@@ -122,12 +147,62 @@ func retrieveSortedByID(retrieveSecretsFunc retrieveSecretsFunc) ([]secretWithLa
 	return sortedByID, nil
 }
 
+// retrieveSortedByScore returns the secrets retrieved by retrieveSecretsFunc
+// with a name or any label fuzzy matching pattern, with all their labels,
+// ordered in descending order by their best match score.
+func retrieveSortedByScore(ctx context.Context, vault secretSearcher, pattern string, retrieveSecretsFunc retrieveSecretsFunc) ([]secretWithLabels, error) {
+	filtered, err := retrieveSecretsFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filtered) == 0 {
+		return nil, nil
+	}
+
+	// label filters narrow the returned labels to the matching ones.
+	secrets, err := vault.SecretsByIDs(ctx, slices.Collect(maps.Keys(filtered))...)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int]int, len(secrets))
+	matching := make([]secretWithLabels, 0, len(secrets))
+
+	for _, s := range secretsMapToSlice(secrets) {
+		best, matched := fuzzy.Score(pattern, s.name)
+
+		for _, l := range s.labels {
+			if score, ok := fuzzy.Score(pattern, l); ok && (!matched || score > best) {
+				best, matched = score, true
+			}
+		}
+
+		if matched {
+			scores[s.id] = best
+			matching = append(matching, s)
+		}
+	}
+
+	slices.SortFunc(matching, func(a, b secretWithLabels) int {
+		// desc by score
+		if scoreA, scoreB := scores[a.id], scores[b.id]; scoreA != scoreB {
+			return scoreB - scoreA
+		}
+
+		// tie break: desc by id
+		return b.id - a.id
+	})
+
+	return matching, nil
+}
+
 // retrieveSortedByMatch returns secrets with all their labels, ordered in
 // descending order by the number of labels initially matched by retrieveMatchingFunc.
 //
 // retrieveMatchingFunc typically returns secrets containing only the labels
 // that match the applied filter.
-func retrieveSortedByMatch(ctx context.Context, vault *vault.Vault, retrieveSecretsFunc retrieveSecretsFunc) ([]secretWithLabels, error) {
+func retrieveSortedByMatch(ctx context.Context, vault secretSearcher, retrieveSecretsFunc retrieveSecretsFunc) ([]secretWithLabels, error) {
 	matchingSecrets, err := retrieveSecretsFunc()
 	if err != nil {
 		return nil, err
@@ -194,15 +269,26 @@ func extractIDs(secrets []secretWithLabels) []int {
 	return ids
 }
 
-func printTable(w io.Writer, markedLabeledSecrets []secretWithLabels) {
-	tw := tabwriter.NewWriter(w, 0, 0, 5, ' ', 0)
-	defer func() { _ = tw.Flush() }()
+// printTable writes the secrets as a table, rendering labels
+// with their display metadata, see [formatLabels].
+func printTable(w io.Writer, st *style.Styler, markedLabeledSecrets []secretWithLabels, meta map[string]vaultdb.LabelMeta) {
+	var buf bytes.Buffer
+
+	tw := tabwriter.NewWriter(&buf, 0, 0, 5, ' ', 0)
 
 	fmt.Fprintln(tw, "ID\tNAME\tLABELS")
 
 	for _, marked := range markedLabeledSecrets {
-		fmt.Fprintf(tw, "%d\t%s\t%s\n", marked.id, marked.name, strings.Join(marked.labels, ","))
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", marked.id, marked.name, formatLabels(marked.labels, meta, st))
 	}
 
 	fmt.Fprintln(tw) // add padding
+
+	_ = tw.Flush()
+
+	// the header is styled once aligned,
+	// escape sequences would otherwise count towards the column widths.
+	header, rows, _ := strings.Cut(buf.String(), "\n")
+
+	fmt.Fprintf(w, "%s\n%s", st.Header(header), rows)
 }
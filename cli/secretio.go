@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ladzaretti/vlt-cli/clipboard"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+)
+
+// Secret values are raw bytes end-to-end: piped input is stored as read,
+// including trailing newlines and binary content, and values are written
+// to stdout or files as stored, without formatting or a trailing newline.
+
+// readSecretInput reads a secret value from r as is.
+// If stripNewline is set, a single trailing newline is removed, see [trimNewline].
+func readSecretInput(r io.Reader, stripNewline bool) ([]byte, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if stripNewline {
+		return trimNewline(b), nil
+	}
+
+	return b, nil
+}
+
+// readAllLimited reads r into a single buffer of limit+1 bytes, which, unlike
+// a growing one, leaves no unwiped copies behind, and returns the bytes read,
+// to be wiped by the caller. Input larger than limit bytes is rejected.
+func readAllLimited(r io.Reader, limit int) ([]byte, error) {
+	buf := make([]byte, limit+1)
+
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		securebytes.Wipe(buf)
+		return nil, err
+	}
+
+	if n > limit {
+		securebytes.Wipe(buf)
+		return nil, fmt.Errorf("larger than %d bytes", limit)
+	}
+
+	return buf[:n], nil
+}
+
+// trimNewline removes a single trailing "\n" or "\r\n" from b,
+// e.g., the one added by 'echo' or by clipboard tools.
+func trimNewline(b []byte) []byte {
+	if t, ok := bytes.CutSuffix(b, []byte("\r\n")); ok {
+		return t
+	}
+
+	t, _ := bytes.CutSuffix(b, []byte("\n"))
+
+	return t
+}
+
+// writeSecret writes the secret value s to w as is.
+func writeSecret(w io.Writer, s []byte) error {
+	_, err := w.Write(s)
+	return err
+}
+
+// pasteSecret reads a secret value from the clipboard.
+// If stripNewline is set, a single trailing newline is removed, see [trimNewline].
+func pasteSecret(stripNewline bool) ([]byte, error) {
+	b, err := clipboard.Paste()
+	if err != nil {
+		return nil, err
+	}
+
+	if stripNewline {
+		return trimNewline(b), nil
+	}
+
+	return b, nil
+}
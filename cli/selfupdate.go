@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/selfupdate"
+
+	"github.com/spf13/cobra"
+)
+
+type SelfUpdateError struct {
+	Err error
+}
+
+func (e *SelfUpdateError) Error() string { return "self-update: " + e.Err.Error() }
+
+func (e *SelfUpdateError) Unwrap() error { return e.Err }
+
+// SelfUpdateOptions holds data required to run the command.
+type SelfUpdateOptions struct {
+	*genericclioptions.StdioOptions
+
+	updater *selfupdate.Updater
+	check   bool
+}
+
+var _ genericclioptions.CmdOptions = &SelfUpdateOptions{}
+
+// NewSelfUpdateOptions initializes the options struct.
+func NewSelfUpdateOptions(stdio *genericclioptions.StdioOptions) *SelfUpdateOptions {
+	return &SelfUpdateOptions{
+		StdioOptions: stdio,
+	}
+}
+
+func (o *SelfUpdateOptions) Complete() error {
+	o.updater = selfupdate.New(selfupdate.WithPublicKey(UpdatePublicKey))
+	return nil
+}
+
+func (o *SelfUpdateOptions) Validate() error {
+	if o.check {
+		return nil
+	}
+
+	if UpdatePublicKey == "" {
+		return &SelfUpdateError{selfupdate.ErrNoPublicKey}
+	}
+
+	if _, err := selfupdate.ParsePublicKey(UpdatePublicKey); err != nil {
+		return &SelfUpdateError{err}
+	}
+
+	return nil
+}
+
+func (o *SelfUpdateOptions) Run(ctx context.Context, _ ...string) (retErr error) {
+	defer func() {
+		if retErr != nil {
+			retErr = &SelfUpdateError{retErr}
+		}
+	}()
+
+	rel, err := o.updater.Latest(ctx)
+	if err != nil {
+		return err
+	}
+
+	newer, err := selfupdate.Compare(rel.Version, Version)
+	if err != nil {
+		return err
+	}
+
+	if newer <= 0 {
+		o.Printf("vlt %s is up to date\n", Version)
+		return nil
+	}
+
+	if o.check {
+		o.Printf("vlt %s is available (current %s)\n", rel.Version, Version)
+		return nil
+	}
+
+	executables, err := installedExecutables()
+	if err != nil {
+		return err
+	}
+
+	o.Debugf("replacing %v\n", executables)
+
+	if err := o.updater.Apply(ctx, rel, executables...); err != nil {
+		return err
+	}
+
+	o.Printf("updated vlt %s -> %s\n", Version, rel.Version)
+
+	if len(executables) > 1 {
+		o.Infof("restart vltd to run the updated daemon\n")
+	}
+
+	return nil
+}
+
+// installedExecutables returns the path of the running vlt executable,
+// followed by the vltd executable installed next to it, if any.
+func installedExecutables() ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locate executable: %w", err)
+	}
+
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return nil, fmt.Errorf("locate executable: %w", err)
+	}
+
+	executables := []string{exe}
+
+	vltd := filepath.Join(filepath.Dir(exe), "vltd")
+	if fi, err := os.Lstat(vltd); err == nil && fi.Mode().IsRegular() {
+		executables = append(executables, vltd)
+	}
+
+	return executables, nil
+}
+
+// NewCmdSelfUpdate creates the self-update cobra command.
+func NewCmdSelfUpdate(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewSelfUpdateOptions(defaults.StdioOptions)
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: i18n.T("Update vlt to the latest release"),
+		Long: `Update vlt to the latest GitHub release.
+
+The release archive is verified against the minisign public key embedded
+in this binary before the running executable is replaced.
+If vltd is installed next to vlt, it is updated as well;
+a running daemon keeps the old version until restarted.
+
+Use --check to only report whether a newer version is available.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.check, "check", "", false, "only report the latest available version")
+
+	return cmd
+}
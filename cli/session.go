@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/style"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+)
+
+type SessionError struct {
+	Err error
+}
+
+func (e *SessionError) Error() string { return "session: " + e.Err.Error() }
+
+func (e *SessionError) Unwrap() error { return e.Err }
+
+// SessionStatusOptions holds data required to run the command.
+type SessionStatusOptions struct {
+	*genericclioptions.StdioOptions
+
+	sessionClient *vaultdaemon.SessionClient
+}
+
+var _ genericclioptions.CmdOptions = &SessionStatusOptions{}
+
+// NewSessionStatusOptions initializes the options struct.
+func NewSessionStatusOptions(stdio *genericclioptions.StdioOptions) *SessionStatusOptions {
+	return &SessionStatusOptions{
+		StdioOptions: stdio,
+	}
+}
+
+func (o *SessionStatusOptions) Complete() error {
+	s, err := vaultdaemon.NewSessionClient()
+	if err != nil {
+		return &SessionError{err}
+	}
+
+	o.sessionClient = s
+
+	return nil
+}
+
+func (*SessionStatusOptions) Validate() error { return nil }
+
+func (o *SessionStatusOptions) Run(ctx context.Context, _ ...string) (retErr error) {
+	defer func() { _ = o.sessionClient.Close() }()
+
+	defer func() {
+		if retErr != nil {
+			retErr = &SessionError{retErr}
+		}
+	}()
+
+	info, err := o.sessionClient.Info(ctx)
+	if err != nil {
+		return err
+	}
+
+	health, err := o.sessionClient.Health(ctx)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := o.sessionClient.Schedule(ctx)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "daemon: vltd %s (protocol %d)\n", cmp.Or(info.GetVersion(), "(unknown version)"), info.GetProtocolVersion())
+	fmt.Fprintf(&buf, "uptime: %s\n", time.Duration(health.GetUptimeSeconds())*time.Second)
+	fmt.Fprintf(&buf, "active sessions: %d\n\n", health.GetActiveSessions())
+
+	printJobsTable(&buf, o.Styler(o.Out), jobs)
+
+	_, err = buf.WriteTo(o.Out)
+
+	return err
+}
+
+// SessionLogOptions holds data required to run the command.
+type SessionLogOptions struct {
+	*genericclioptions.StdioOptions
+
+	sessionClient *vaultdaemon.SessionClient
+
+	limit int
+}
+
+var _ genericclioptions.CmdOptions = &SessionLogOptions{}
+
+// NewSessionLogOptions initializes the options struct.
+func NewSessionLogOptions(stdio *genericclioptions.StdioOptions) *SessionLogOptions {
+	return &SessionLogOptions{
+		StdioOptions: stdio,
+	}
+}
+
+func (o *SessionLogOptions) Complete() error {
+	s, err := vaultdaemon.NewSessionClient()
+	if err != nil {
+		return &SessionError{err}
+	}
+
+	o.sessionClient = s
+
+	return nil
+}
+
+func (o *SessionLogOptions) Validate() error {
+	if o.limit < 0 {
+		return &SessionError{fmt.Errorf("invalid --limit %d: must not be negative", o.limit)}
+	}
+
+	return nil
+}
+
+func (o *SessionLogOptions) Run(ctx context.Context, _ ...string) error {
+	defer func() { _ = o.sessionClient.Close() }()
+
+	entries, path, err := o.sessionClient.AuditLog(ctx, o.limit)
+	if err != nil {
+		return &SessionError{err}
+	}
+
+	o.Debugf("audit log: %s\n", path)
+
+	if len(entries) == 0 {
+		o.Infof("the audit log %s is empty\n", path)
+		return nil
+	}
+
+	printAuditTable(o.Out, o.Styler(o.Out), entries)
+
+	return nil
+}
+
+// printAuditTable writes the audit log entries as a table, highlighting failed requests.
+func printAuditTable(w io.Writer, st *style.Styler, entries []*pb.AuditEntry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(tw, st.Header("TIME\tMETHOD\tUID\tPID\tVAULT\tRESULT"))
+
+	for _, e := range entries {
+		result := e.GetResult()
+		if result != codes.OK.String() {
+			result = st.Error(result)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%s\n",
+			time.Unix(e.GetTimeUnix(), 0).Local().Format(time.DateTime), e.GetMethod(), e.GetUid(), e.GetPid(), cmp.Or(e.GetVaultPath(), "-"), result)
+	}
+
+	_ = tw.Flush()
+}
+
+// printJobsTable writes the status of the scheduled jobs as a titled table.
+func printJobsTable(w io.Writer, st *style.Styler, jobs []*pb.JobStatus) {
+	fmt.Fprintf(w, "%s:\n", st.Header("Scheduled jobs"))
+
+	if len(jobs) == 0 {
+		fmt.Fprintf(w, "  none, see the [daemon.schedule] section of the config file\n")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(tw, "  NAME\tEVERY\tLAST RUN\tNEXT RUN\tRUNS\tFAILURES\tLAST RESULT")
+
+	for _, j := range jobs {
+		last, result := "never", "-"
+		if j.GetLastRunUnix() > 0 {
+			last = time.Unix(j.GetLastRunUnix(), 0).Local().Format(time.DateTime)
+			result = j.GetLastResult()
+		}
+
+		if len(j.GetLastError()) > 0 {
+			result = st.Error("error: " + j.GetLastError())
+		}
+
+		next := "-"
+		if j.GetNextRunUnix() > 0 {
+			next = time.Unix(j.GetNextRunUnix(), 0).Local().Format(time.DateTime)
+		}
+
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			j.GetName(), time.Duration(j.GetIntervalSeconds())*time.Second, last, next, j.GetRuns(), j.GetFailures(), result)
+	}
+
+	_ = tw.Flush()
+}
+
+// NewCmdSession creates the session cobra command.
+func NewCmdSession(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: i18n.T("Inspect the vltd session daemon (subcommands available)"),
+		Long: `Inspect the vltd session daemon.
+
+The daemon holds the session keys of logged in vaults, and runs the periodic
+jobs of the [daemon.schedule] section of the config file while it is up.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(NewCmdSessionStatus(defaults))
+	cmd.AddCommand(NewCmdSessionLog(defaults))
+
+	return cmd
+}
+
+// NewCmdSessionStatus creates the session status cobra command.
+func NewCmdSessionStatus(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewSessionStatusOptions(defaults.StdioOptions)
+
+	return &cobra.Command{
+		Use:   "status",
+		Short: i18n.T("Show the daemon status and its scheduled jobs"),
+		Long: `Show the status of the running vltd daemon: its version, uptime,
+the number of active sessions, and the scheduled jobs with their last results.
+
+Scheduled jobs are read by vltd from the [daemon.schedule] section of the config
+file on startup; restart vltd after changing them. Job results are also written
+to the daemon log.
+
+Only vault backups can be scheduled. vlt has no breach audit or secret expiry
+yet, so there are no audit or expiry notification jobs.`,
+		Example: `  # Back up the vault daily while vltd is running (config file)
+  [backup]
+  dir = '/mnt/usb/vlt'
+
+  [daemon.schedule]
+  backup = '24h'
+
+  # Show the daemon status and the last backup result
+  vlt session status`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+}
+
+// NewCmdSessionLog creates the session log cobra command.
+func NewCmdSessionLog(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewSessionLogOptions(defaults.StdioOptions)
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: i18n.T("Show the session requests recorded by the daemon"),
+		Long: `Show the audit log of the running vltd daemon, recording which client
+process (uid and pid) requested which vault's session key, and when.
+
+Requests logging in, using or dropping sessions are recorded, as well as all
+requests rejected by the per-uid rate limit (RESULT ResourceExhausted), which
+point to local processes hammering the daemon. A burst of GetSessionKey requests
+from an unknown pid is worth a look, e.g., using 'ps -p <pid>'.
+
+The audit log is a file only ever appended to, by default
+$XDG_STATE_HOME/vlt/vltd-audit.log (see vltd --audit-log). The rate limit is
+set by vltd --rate-limit.`,
+		Example: `  # Show the last 20 session requests
+  vlt session log --limit 20`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().IntVarP(&o.limit, "limit", "n", 50, "number of most recent entries to show, 0 for all")
+
+	return cmd
+}
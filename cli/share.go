@@ -0,0 +1,447 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/input"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/spf13/cobra"
+)
+
+// shareBundleVersion is the version of the [shareBundle] format.
+const shareBundleVersion = 1
+
+// shareBundle is the plaintext of a share bundle, a single secret
+// with its metadata. Bundles are age encrypted, see 'vlt share'.
+//
+//nolint:tagliatelle
+type shareBundle struct {
+	Version    int               `json:"version"`
+	Name       string            `json:"name"`
+	Labels     []string          `json:"labels,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Value      []byte            `json:"value"`
+	SharedAt   time.Time         `json:"shared_at"`
+}
+
+type ShareError struct {
+	Err error
+}
+
+func (e *ShareError) Error() string { return "share: " + e.Err.Error() }
+
+func (e *ShareError) Unwrap() error { return e.Err }
+
+// ShareOptions holds data required to run the command.
+type ShareOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	search     *SearchableOptions
+	recipients []string // recipients holds the age recipients, e.g., age1...
+	passphrase bool     // passphrase encrypts the bundle with a prompted passphrase instead of recipients.
+	output     string   // output is the bundle file path, stdout if empty.
+}
+
+var _ genericclioptions.CmdOptions = &ShareOptions{}
+
+// NewShareOptions initializes the options struct.
+func NewShareOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *ShareOptions {
+	return &ShareOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+		search:       NewSearchableOptions(),
+	}
+}
+
+func (o *ShareOptions) Complete() error { return o.search.Complete() }
+
+func (o *ShareOptions) Validate() error {
+	if err := o.search.Validate(); err != nil {
+		return &ShareError{err}
+	}
+
+	if (len(o.recipients) > 0) == o.passphrase {
+		return &ShareError{errors.New("exactly one of --to or --passphrase must be set")}
+	}
+
+	for _, r := range o.recipients {
+		if _, err := age.ParseX25519Recipient(r); err != nil {
+			return &ShareError{fmt.Errorf("invalid --to recipient %q: %w", r, err)}
+		}
+	}
+
+	return nil
+}
+
+func (o *ShareOptions) Run(ctx context.Context, args ...string) (retErr error) {
+	defer func() {
+		if retErr != nil {
+			retErr = &ShareError{retErr}
+			return
+		}
+	}()
+
+	o.search.WildcardFrom(args)
+
+	matchingSecrets, err := o.search.search(ctx, o.vault)
+	if err != nil {
+		return err
+	}
+
+	switch count := len(matchingSecrets); count {
+	case 1:
+		o.Debugf("found one match.\n")
+	case 0:
+		o.Errorf("no match found.\n")
+		return vaulterrors.ErrSearchNoMatch
+	default:
+		o.Errorf("expecting exactly one match, but found %d.\n\n", count)
+		o.printSecrets(ctx, o.ErrOut, o.Styler(o.ErrOut), matchingSecrets)
+
+		return vaulterrors.ErrAmbiguousSecretMatch
+	}
+
+	recipients, err := o.ageRecipients(ctx)
+	if err != nil {
+		return err
+	}
+
+	secret := matchingSecrets[0]
+
+	attrs, err := o.vault.SecretsAttributes(ctx, secret.id)
+	if err != nil {
+		return err
+	}
+
+	var plaintext []byte
+
+	err = o.vault.WithSecret(ctx, secret.id, func(value []byte) (err error) {
+		plaintext, err = json.Marshal(shareBundle{
+			Version:    shareBundleVersion,
+			Name:       secret.name,
+			Labels:     secret.labels,
+			Attributes: attrs[secret.id],
+			Value:      value,
+			SharedAt:   time.Now().UTC(),
+		})
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer securebytes.Wipe(plaintext)
+
+	var bundle bytes.Buffer
+	if err := encryptBundle(&bundle, plaintext, recipients...); err != nil {
+		return err
+	}
+
+	if len(o.output) > 0 {
+		return os.WriteFile(o.output, bundle.Bytes(), 0o600)
+	}
+
+	_, err = bundle.WriteTo(o.Out)
+
+	return err
+}
+
+func (o *ShareOptions) ageRecipients(ctx context.Context) ([]age.Recipient, error) {
+	if o.passphrase {
+		pass, err := promptNewBundlePassphrase(ctx, o.StdioOptions)
+		if err != nil {
+			return nil, err
+		}
+		defer securebytes.Wipe(pass)
+
+		r, err := age.NewScryptRecipient(string(pass))
+		if err != nil {
+			return nil, err
+		}
+
+		return []age.Recipient{r}, nil
+	}
+
+	recipients := make([]age.Recipient, len(o.recipients))
+
+	for i, s := range o.recipients {
+		r, err := age.ParseX25519Recipient(s)
+		if err != nil {
+			return nil, err
+		}
+
+		recipients[i] = r
+	}
+
+	return recipients, nil
+}
+
+// encryptBundle writes the ASCII armored age encryption of plaintext to w.
+func encryptBundle(w io.Writer, plaintext []byte, recipients ...age.Recipient) error {
+	aw := armor.NewWriter(w)
+
+	ew, err := age.Encrypt(aw, recipients...)
+	if err != nil {
+		return fmt.Errorf("encrypt bundle: %w", err)
+	}
+
+	if _, err := ew.Write(plaintext); err != nil {
+		return fmt.Errorf("encrypt bundle: %w", err)
+	}
+
+	if err := ew.Close(); err != nil {
+		return fmt.Errorf("encrypt bundle: %w", err)
+	}
+
+	return aw.Close()
+}
+
+// decryptBundle decrypts an age encrypted bundle, armored or binary.
+func decryptBundle(r io.Reader, identities ...age.Identity) (*shareBundle, error) {
+	br := bufio.NewReader(r)
+
+	src := io.Reader(br)
+	if head, _ := br.Peek(len(armor.Header)); string(head) == armor.Header {
+		src = armor.NewReader(br)
+	}
+
+	dr, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt bundle: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(dr)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt bundle: %w", err)
+	}
+	defer securebytes.Wipe(plaintext)
+
+	var b shareBundle
+	if err := json.Unmarshal(plaintext, &b); err != nil {
+		return nil, fmt.Errorf("parse bundle: %w", err)
+	}
+
+	if b.Version != shareBundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d (expected %d)", b.Version, shareBundleVersion)
+	}
+
+	if len(b.Value) == 0 {
+		return nil, vaulterrors.ErrEmptySecret
+	}
+
+	return &b, nil
+}
+
+// promptNewBundlePassphrase prompts for a bundle passphrase twice.
+func promptNewBundlePassphrase(ctx context.Context, io *genericclioptions.StdioOptions) ([]byte, error) {
+	pass, err := input.PromptReadSecure(ctx, io.Prompter(), "Enter bundle passphrase: ")
+	if err != nil {
+		return nil, fmt.Errorf("prompt passphrase: %w", err)
+	}
+
+	if len(pass) == 0 {
+		return nil, errors.New("prompt passphrase: empty passphrase")
+	}
+
+	pass2, err := input.PromptReadSecure(ctx, io.Prompter(), "Retype bundle passphrase: ")
+	if err != nil {
+		return nil, fmt.Errorf("prompt passphrase: %w", err)
+	}
+	defer securebytes.Wipe(pass2)
+
+	if !slices.Equal(pass, pass2) {
+		return nil, errors.New("prompt passphrase: passphrases do not match")
+	}
+
+	return pass, nil
+}
+
+// NewCmdShare creates the share cobra command.
+func NewCmdShare(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewShareOptions(
+		defaults.StdioOptions,
+		defaults.vaultOptions,
+	)
+
+	cmd := &cobra.Command{
+		Use:   "share [glob]",
+		Short: i18n.T("Share a single secret as an encrypted bundle"),
+		Long: `Share a single secret as an encrypted bundle.
+
+The bundle holds a copy of the secret value, name, labels and attributes,
+encrypted using age (https://age-encryption.org), either to the given
+recipient public keys, or with a prompted passphrase.
+The bundle is ASCII armored, and written to stdout unless --output is set.
+
+The secret is shared only if exactly one secret matches the given search criteria.
+
+Use 'vlt share import' on the receiving side to save the secret into a vault.`,
+		Example: `  # Share a secret by ID with an age recipient
+  vlt share --id 42 --to age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+
+  # Share a secret encrypted with a passphrase, written to a file
+  vlt share foo --passphrase --output foo.age`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+
+	cmd.Flags().IntVarP(&o.search.ID, "id", "", 0, FilterByID.Help())
+	cmd.Flags().StringVarP(&o.search.Name, "name", "", "", FilterByName.Help())
+	cmd.Flags().StringSliceVarP(&o.search.Labels, "label", "", nil, FilterByLabels.Help())
+	cmd.Flags().StringArrayVarP(&o.recipients, "to", "", nil, "age recipient public key to encrypt the bundle to (repeatable)")
+	cmd.Flags().BoolVarP(&o.passphrase, "passphrase", "", false, "encrypt the bundle with a prompted passphrase")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "write the bundle to the specified file path")
+
+	markExclusiveDefaults(cmd, "to", "passphrase")
+
+	cmd.AddCommand(NewCmdShareImport(defaults))
+
+	return cmd
+}
+
+// ShareImportOptions holds data required to run the command.
+type ShareImportOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	identityPath string // identityPath is the age identity file, the passphrase is prompted if empty.
+}
+
+var _ genericclioptions.CmdOptions = &ShareImportOptions{}
+
+// NewShareImportOptions initializes the options struct.
+func NewShareImportOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *ShareImportOptions {
+	return &ShareImportOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*ShareImportOptions) Complete() error { return nil }
+
+func (*ShareImportOptions) Validate() error { return nil }
+
+func (o *ShareImportOptions) Run(ctx context.Context, args ...string) (retErr error) {
+	defer func() {
+		if retErr != nil {
+			retErr = &ShareError{retErr}
+			return
+		}
+	}()
+
+	identities, err := o.ageIdentities(ctx)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }() //nolint:wsl_v5
+
+	b, err := decryptBundle(f, identities...)
+	if err != nil {
+		return err
+	}
+	defer securebytes.Wipe(b.Value)
+
+	id, err := o.vault.InsertNewSecret(ctx, b.Name, b.Value, b.Labels)
+	if err != nil {
+		return err
+	}
+
+	if len(b.Attributes) > 0 {
+		if err := o.vault.UpdateSecretAttributes(ctx, id, b.Attributes, nil); err != nil {
+			return err
+		}
+	}
+
+	o.Infof("imported secret %q shared at %s (id %d)\n", b.Name, b.SharedAt.Local().Format(time.DateTime), id)
+
+	return nil
+}
+
+func (o *ShareImportOptions) ageIdentities(ctx context.Context) ([]age.Identity, error) {
+	if len(o.identityPath) > 0 {
+		return readAgeIdentities(o.identityPath)
+	}
+
+	pass, err := input.PromptReadSecure(ctx, o.Prompter(), "Enter bundle passphrase: ")
+	if err != nil {
+		return nil, fmt.Errorf("prompt passphrase: %w", err)
+	}
+	defer securebytes.Wipe(pass)
+
+	identity, err := age.NewScryptIdentity(string(pass))
+	if err != nil {
+		return nil, err
+	}
+
+	return []age.Identity{identity}, nil
+}
+
+// readAgeIdentities reads the age identities from the identity file at path.
+func readAgeIdentities(path string) ([]age.Identity, error) {
+	raw, err := os.ReadFile(path) //nolint:gosec // path is a user provided identity file
+	if err != nil {
+		return nil, err
+	}
+	defer securebytes.Wipe(raw)
+
+	identities, err := age.ParseIdentities(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parse identity file: %w", err)
+	}
+
+	return identities, nil
+}
+
+// NewCmdShareImport creates the share import cobra command.
+func NewCmdShareImport(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewShareImportOptions(
+		defaults.StdioOptions,
+		defaults.vaultOptions,
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import bundle",
+		Short: i18n.T("Save a secret from a shared bundle"),
+		Long: `Save a secret from a bundle created by 'vlt share'.
+
+The bundle is decrypted using the age identities in --identity,
+or with a prompted passphrase if no identity file is given.
+The secret is saved as a new secret, with its labels and attributes.`,
+		Example: `  # Import a bundle shared with your age key
+  vlt share import foo.age --identity ~/.config/age/key.txt
+
+  # Import a passphrase encrypted bundle
+  vlt share import foo.age`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.identityPath, "identity", "i", "", "age identity file used to decrypt the bundle")
+
+	return cmd
+}
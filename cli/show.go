@@ -3,11 +3,21 @@ package cli
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/clipboard"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
 	"github.com/ladzaretti/vlt-cli/vaulterrors"
 
 	"github.com/spf13/cobra"
@@ -26,20 +36,34 @@ type ShowOptions struct {
 	*genericclioptions.StdioOptions
 	*VaultOptions
 
-	search *SearchableOptions
-	stdout bool   // stdout controls whether to print the secret to stdout.
-	copy   bool   // copy controls whether to copy the secret to the clipboard.
-	output string // output controls whether to write secret to a given file.
+	search      *SearchableOptions
+	stdout      bool          // stdout controls whether to print the secret to stdout.
+	forceStdout bool          // forceStdout prints to a terminal stdout without confirmation, see [confirmTerminalStdout].
+	clearAfter  time.Duration // clearAfter clears the secret printed to a terminal after the duration, see [clearTerminalLines].
+	printed     int           // printed is the number of terminal lines of the printed secret, cleared if clearAfter is set.
+	copy        bool          // copy controls whether to copy the secret to the clipboard.
+	output      string        // output controls whether to write secret to a given file.
+	force       bool          // force overwrites an existing output file without confirmation.
+	shred       bool          // shred overwrites the previous content of an existing output file with zeros.
+	ttl         time.Duration // ttl schedules the shredding of the output file by the daemon.
+	fifo        bool          // fifo controls whether to write the secret to the first reader of a one-shot named pipe.
+	attr        string        // attr selects an attribute to output instead of the secret value.
+	url         string        // url selects the secret by the registrable domain of its url, see [vault.Vault.SecretsByURL].
+
+	config        *ResolvedConfig
+	sessionClient *vaultdaemon.SessionClient // sessionClient schedules the shredding of --ttl output files.
+	fifoWriter    *os.File                   // fifoWriter is the opened --fifo named pipe.
 }
 
 var _ genericclioptions.CmdOptions = &ShowOptions{}
 
 // NewShowOptions initializes the options struct.
-func NewShowOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *ShowOptions {
+func NewShowOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions, config *ResolvedConfig) *ShowOptions {
 	return &ShowOptions{
 		StdioOptions: stdio,
 		VaultOptions: vaultOptions,
 		search:       NewSearchableOptions(),
+		config:       config,
 	}
 }
 
@@ -56,7 +80,19 @@ func (o *ShowOptions) Validate() error {
 		return err
 	}
 
-	return o.search.Validate()
+	if len(o.attr) > 0 {
+		if err := validateAttributeKey(o.attr); err != nil {
+			return &ShowError{err}
+		}
+	}
+
+	if len(o.url) > 0 {
+		if _, err := vault.NormalizeURL(o.url); err != nil {
+			return &ShowError{err}
+		}
+	}
+
+	return nil
 }
 
 func (o *ShowOptions) validateConfigOptions() error {
@@ -74,8 +110,24 @@ func (o *ShowOptions) validateConfigOptions() error {
 		c++
 	}
 
+	if o.fifo {
+		c++
+	}
+
 	if c != 1 {
-		return &ShowError{errors.New("exactly one of --stdout, --output, or --copy-clipboard must be set")}
+		return &ShowError{errors.New("exactly one of --stdout, --output, --fifo, or --copy-clipboard must be set")}
+	}
+
+	if o.clearAfter < 0 || (o.clearAfter > 0 && !o.stdout) {
+		return &ShowError{errors.New("--clear-after requires --stdout and a positive duration")}
+	}
+
+	if len(o.output) == 0 && (o.force || o.shred || o.ttl != 0) {
+		return &ShowError{errors.New("--force, --shred and --ttl require --output")}
+	}
+
+	if o.ttl != 0 && o.ttl < time.Second {
+		return &ShowError{fmt.Errorf("invalid --ttl %s: must be at least 1s", o.ttl)}
 	}
 
 	return nil
@@ -85,7 +137,31 @@ func (o *ShowOptions) validateConfigOptions() error {
 func (o *ShowOptions) Run(ctx context.Context, args ...string) error {
 	o.search.WildcardFrom(args)
 
-	matchingSecrets, err := o.search.search(ctx, o.vault)
+	if len(o.output) > 0 {
+		if err := o.confirmOverwrite(ctx); err != nil {
+			return &ShowError{err}
+		}
+	}
+
+	// with --clear-after, the secret is printed without confirmation as it is cleared from the terminal.
+	if o.stdout {
+		if err := confirmTerminalStdout(ctx, o.StdioOptions, o.forceStdout || o.clearAfter > 0); err != nil {
+			return &ShowError{err}
+		}
+	}
+
+	// the daemon is connected first, so that no file is left behind without it.
+	if o.ttl > 0 {
+		c, err := connectDaemon(ctx, o.StdioOptions, o.config.AutostartDaemon)
+		if err != nil {
+			return &ShowError{fmt.Errorf("--ttl requires the vltd daemon: %w", err)}
+		}
+		defer func() { _ = c.Close() }()
+
+		o.sessionClient = c
+	}
+
+	matchingSecrets, err := o.matchSecrets(ctx)
 	if err != nil {
 		return err
 	}
@@ -96,29 +172,97 @@ func (o *ShowOptions) Run(ctx context.Context, args ...string) error {
 	case 1:
 		o.Debugf("found one match.\n")
 
-		s, err := o.vault.ShowSecret(ctx, matchingSecrets[0].id)
-		if err != nil {
+		if o.fifo {
+			cleanup, err := o.openFIFO(ctx)
+			if err != nil {
+				return &ShowError{err}
+			}
+			defer cleanup()
+		}
+
+		if len(o.attr) > 0 {
+			if err := o.showAttribute(ctx, matchingSecrets[0].id); err != nil {
+				return err
+			}
+
+			if err := o.clearPrinted(ctx); err != nil {
+				return &ShowError{err}
+			}
+
+			return o.scheduleShred(ctx)
+		}
+
+		if err := o.vault.WithSecret(ctx, matchingSecrets[0].id, o.outputSecret); err != nil {
 			return err
 		}
 
-		return o.outputSecret(s)
+		o.recordAccess(ctx, o.StdioOptions, matchingSecrets[0].id)
+
+		if err := o.clearPrinted(ctx); err != nil {
+			return &ShowError{err}
+		}
+
+		return o.scheduleShred(ctx)
 	case 0:
 		o.Errorf("no match found.\n")
 		return &ShowError{vaulterrors.ErrSearchNoMatch}
 	default:
 		o.Errorf("expecting exactly one match, but found %d.\n\n", count)
-		printTable(o.ErrOut, matchingSecrets)
+		o.printSecrets(ctx, o.ErrOut, o.Styler(o.ErrOut), matchingSecrets)
 
 		return &ShowError{vaulterrors.ErrAmbiguousSecretMatch}
 	}
 }
 
+// matchSecrets returns the secrets matching the search flags,
+// or the secrets of the --url site if set.
+func (o *ShowOptions) matchSecrets(ctx context.Context) ([]secretWithLabels, error) {
+	if len(o.url) == 0 {
+		return o.search.search(ctx, o.vault)
+	}
+
+	if o.search.ID > 0 || len(o.search.Name) > 0 || len(o.search.Labels) > 0 || len(o.search.Wildcard) > 0 {
+		return nil, &ShowError{errors.New("--url cannot be combined with other search filters")}
+	}
+
+	return retrieveSortedByID(func() (map[int]vaultdb.SecretWithLabels, error) {
+		return o.vault.SecretsByURL(ctx, o.url)
+	})
+}
+
+// showAttribute outputs the value of the selected attribute of the secret.
+func (o *ShowOptions) showAttribute(ctx context.Context, id int) error {
+	attrs, err := o.vault.SecretsAttributes(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	value, ok := attrs[id][o.attr]
+	if !ok {
+		return &ShowError{fmt.Errorf("attribute %q is not set", o.attr)}
+	}
+
+	return o.outputSecret([]byte(value))
+}
+
 func (o *ShowOptions) outputSecret(s []byte) error {
-	defer clear(s)
+	defer securebytes.Wipe(s)
+
+	if o.stdout && o.clearAfter > 0 && o.OutIsTerminal() {
+		o.printed = terminalLines(s, terminalWidth(o.Out))
+
+		if err := writeSecret(o.Out, s); err != nil {
+			return err
+		}
+
+		// terminated by a newline, so that the lines cleared are the secret's only.
+		_, err := io.WriteString(o.Out, "\n")
+
+		return err
+	}
 
 	if o.stdout {
-		o.Printf("%s", s)
-		return nil
+		return writeSecret(o.Out, s)
 	}
 
 	if o.copy {
@@ -127,51 +271,225 @@ func (o *ShowOptions) outputSecret(s []byte) error {
 	}
 
 	if len(o.output) > 0 {
-		f, err := os.Create(o.output)
-		if err != nil {
-			return err
-		}
-		defer func() { //nolint:wsl_v5
-			_ = f.Close()
-		}()
+		return o.writeOutput(s)
+	}
 
-		if _, err := f.Write(s); err != nil {
-			return err
-		}
+	if o.fifoWriter != nil {
+		_, err := o.fifoWriter.Write(s)
+		return err
 	}
 
 	return nil
 }
 
+// clearPrinted clears the secret printed to the terminal once --clear-after passes,
+// or on Ctrl-C. Secrets printed to piped or redirected stdout are not cleared.
+func (o *ShowOptions) clearPrinted(ctx context.Context) error {
+	if o.printed == 0 {
+		return nil
+	}
+
+	return clearTerminalLines(ctx, o.Out, o.printed, o.clearAfter)
+}
+
+// openFIFO creates a named pipe in a private temporary directory, prints its path
+// and waits for the first reader to open it. The pipe is removed once opened,
+// so that it has a single reader. The returned func closes the pipe,
+// signaling the end of the secret to the reader.
+func (o *ShowOptions) openFIFO(ctx context.Context) (cleanup func(), retErr error) {
+	dir, err := os.MkdirTemp("", "vlt-fifo-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { retErr = errors.Join(retErr, os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "secret")
+	if err := makeFIFO(path); err != nil {
+		return nil, fmt.Errorf("fifo: %w", err)
+	}
+
+	if _, err := fmt.Fprintln(o.Out, path); err != nil {
+		return nil, err
+	}
+
+	o.Debugf("waiting for a reader of %q\n", path)
+
+	f, err := openFIFOWriter(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("fifo: %w", err)
+	}
+
+	o.fifoWriter = f
+
+	return func() { _ = f.Close() }, nil
+}
+
+// scheduleShred schedules the shredding of the written output file by the daemon
+// once --ttl passes. If the daemon fails to schedule it, the file is shredded right away.
+func (o *ShowOptions) scheduleShred(ctx context.Context) error {
+	if o.ttl == 0 {
+		return nil
+	}
+
+	if err := o.sessionClient.ShredFile(ctx, o.output, o.ttl); err != nil {
+		return &ShowError{errors.Join(fmt.Errorf("schedule shred: %w", err), shredOutput(o.output))}
+	}
+
+	o.Infof("%q will be shredded in %s\n", o.output, o.ttl)
+
+	return nil
+}
+
+// shredOutput overwrites the file at path with zeros and removes it.
+func shredOutput(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0) //nolint:gosec // the output path is user provided
+	if err != nil {
+		return err
+	}
+
+	if err := securebytes.WipeFile(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return errors.Join(f.Close(), os.Remove(path))
+}
+
+// confirmOverwrite prompts before an existing output file is overwritten,
+// unless --force is set. Non-interactive runs require --force.
+func (o *ShowOptions) confirmOverwrite(ctx context.Context) error {
+	fi, err := os.Stat(o.output)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("output %q is not a regular file", o.output)
+	}
+
+	if o.force {
+		return nil
+	}
+
+	if o.StdinIsPiped {
+		return fmt.Errorf("output file %q already exists, use --force to overwrite it", o.output)
+	}
+
+	yes, err := confirm(ctx, o.Prompter(), "File %q already exists, overwrite? (y/N): ", o.output)
+	if err != nil {
+		return err
+	}
+
+	if !yes {
+		return fmt.Errorf("output file %q not overwritten", o.output)
+	}
+
+	return nil
+}
+
+// writeOutput writes s to the output file, created with owner-only permissions.
+// An existing file is restricted to its owner, and shredded first if --shred is set.
+func (o *ShowOptions) writeOutput(s []byte) (retErr error) {
+	//nolint:gosec // the output path is user provided
+	f, err := os.OpenFile(o.output, os.O_WRONLY|os.O_CREATE, vaultPerm)
+	if err != nil {
+		return err
+	}
+	defer func() { retErr = errors.Join(retErr, f.Close()) }()
+
+	if err := restrictVaultFile(o.output); err != nil {
+		return err
+	}
+
+	if o.shred {
+		if err := securebytes.WipeFile(f); err != nil {
+			return fmt.Errorf("shred %q: %w", o.output, err)
+		}
+	}
+
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+
+	if _, err := f.Write(s); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
 // NewCmdShow creates the Show cobra command.
 func NewCmdShow(defaults *DefaultVltOptions) *cobra.Command {
 	o := NewShowOptions(
 		defaults.StdioOptions,
 		defaults.vaultOptions,
+		defaults.configOptions.resolved,
 	)
 
 	cmd := &cobra.Command{
 		Use:     "show [glob]",
 		Aliases: []string{"get"},
-		Short:   "Retrieve a secret value",
+		Short:   i18n.T("Retrieve a secret value"),
 		Long: `Retrieve and display a secret value from the vault.
 
 The secret value will be displayed only if there is exactly one match for the given search criteria.
 
 Search values support UNIX glob patterns (e.g., "foo*", "*bar*").
 
-Use --stdout to print to stdout (unsafe), or --copy-clipboard to copy the value to the clipboard.`,
+Use --stdout to print to stdout (unsafe), or --copy-clipboard to copy the value to the clipboard.
+Printing to a terminal, where the value remains in its scrollback, is only done once confirmed,
+or with --force-stdout. Piped or redirected stdout is not affected.
+Use --clear-after to print the value to the terminal without confirmation, and clear it from
+the screen once the duration passes, or on Ctrl-C, e.g., for quick reads.
+
+Use --output to write the value to a file, created readable by the owner only.
+An existing file is only overwritten once confirmed, or with --force,
+and --shred overwrites its previous content with zeros first.
+Use --ttl to have the vltd daemon shred and remove the file once the duration passes,
+for tools reading credentials from a path only.
+
+Use --fifo to write the value to a one-shot named pipe instead of a file,
+for programs taking a file path. Its path is printed, and vlt waits for
+the first program opening it, writes the value to it and removes it (not on Windows).
+
+Use --attr to retrieve the value of an attribute instead, see 'vlt update --set-attr'.
+
+Use --url to select the secret by the url of its site, see 'vlt save --url'.
+Secrets match if their url shares the registrable domain of the given url,
+e.g., https://app.github.com/login matches a secret saved with https://github.com.`,
 		Example: `  # Show a secret by matching its name or label, output to stdout (unsafe)
   vlt show foo --stdout
 
+  # Show a secret in the terminal, cleared from the screen after 10 seconds
+  vlt show foo --stdout --clear-after 10s
+
   # Show a secret by matching its ID, copy the value to the clipboard
   vlt show --id 42 --copy-clipboard
 
   # Show a secret by ID and write its value to a file
   vlt show --id 42 --output secret.file
 
+  # Replace an existing file without confirmation, zeroing its previous content
+  vlt show --id 42 --output secret.file --force --shred
+
+  # Write a secret to a file shredded after a minute
+  vlt show --id 42 --output /tmp/token --ttl 60s
+
+  # Pass a secret to a program reading it from a path, using a one-shot named pipe
+  vlt show foo --fifo | { read -r fifo; curl --netrc-file "$fifo" https://example.com; }
+
   # Use glob pattern and label filter
-  vlt show "*foo*" --label "*bar*" --stdout`,
+  vlt show "*foo*" --label "*bar*" --stdout
+
+  # Show the owner attribute of a secret
+  vlt show foo --attr owner --stdout
+
+  # Show the secret of a site by its url
+  vlt show --url https://app.github.com/login --copy-clipboard`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
 		},
@@ -181,8 +499,18 @@ Use --stdout to print to stdout (unsafe), or --copy-clipboard to copy the value
 	cmd.Flags().StringVarP(&o.search.Name, "name", "", "", FilterByName.Help())
 	cmd.Flags().StringSliceVarP(&o.search.Labels, "label", "", nil, FilterByLabels.Help())
 	cmd.Flags().BoolVarP(&o.stdout, "stdout", "", false, "output the secret to stdout (unsafe)")
+	cmd.Flags().BoolVarP(&o.forceStdout, "force-stdout", "", false, "print to stdout without confirmation if it is a terminal")
+	cmd.Flags().DurationVarP(&o.clearAfter, "clear-after", "", 0, "clear the secret printed to the terminal after the given duration")
 	cmd.Flags().BoolVarP(&o.copy, "copy-clipboard", "c", false, "copy the secret to the clipboard")
-	cmd.Flags().StringVarP(&o.output, "output", "o", "", "export secrets to the specified file path")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "write the secret to the specified file path")
+	cmd.Flags().BoolVarP(&o.force, "force", "", false, "overwrite an existing --output file without confirmation")
+	cmd.Flags().BoolVarP(&o.shred, "shred", "", false, "overwrite the previous content of an existing --output file with zeros")
+	cmd.Flags().DurationVarP(&o.ttl, "ttl", "", 0, "shred and remove the --output file after the given duration, using the vltd daemon")
+	cmd.Flags().BoolVarP(&o.fifo, "fifo", "", false, "write the secret to the first reader of a one-shot named pipe, whose path is printed")
+	cmd.Flags().StringVarP(&o.attr, "attr", "", "", "output the value of the given attribute instead of the secret")
+	cmd.Flags().StringVarP(&o.url, "url", "", "", "select the secret by the domain of its url")
+
+	markExclusiveDefaults(cmd, "stdout", "copy-clipboard", "output", "fifo")
 
 	return cmd
 }
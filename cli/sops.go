@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/sopskeyservice"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+
+	"filippo.io/age"
+	"github.com/spf13/cobra"
+)
+
+type SopsError struct {
+	Err error
+}
+
+func (e *SopsError) Error() string { return "sops: " + e.Err.Error() }
+
+func (e *SopsError) Unwrap() error { return e.Err }
+
+// SopsKeyserviceOptions holds data required to run the command.
+type SopsKeyserviceOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	socket string
+}
+
+var _ genericclioptions.CmdOptions = &SopsKeyserviceOptions{}
+
+// NewSopsKeyserviceOptions initializes the options struct.
+func NewSopsKeyserviceOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *SopsKeyserviceOptions {
+	return &SopsKeyserviceOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (o *SopsKeyserviceOptions) Complete() error {
+	if len(o.socket) == 0 {
+		o.socket = sopskeyservice.SocketPath()
+	}
+
+	return nil
+}
+
+func (*SopsKeyserviceOptions) Validate() error { return nil }
+
+func (o *SopsKeyserviceOptions) Run(ctx context.Context, _ ...string) error {
+	if err := o.serve(ctx); err != nil {
+		return &SopsError{err}
+	}
+
+	return nil
+}
+
+// serve unwraps the stored age identities once, and serves them
+// until ctx is done, so the vault is not accessed per request.
+func (o *SopsKeyserviceOptions) serve(ctx context.Context) error {
+	stored, err := o.ageIdentities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(stored) == 0 {
+		return errors.New("no age identities, add one using 'vlt age keygen' or 'vlt age add'")
+	}
+
+	unwrapped, err := o.unwrapAgeIdentities(ctx, o.StdioOptions, stored)
+	if err != nil {
+		return err
+	}
+
+	identities := make(map[string]age.Identity, len(stored))
+	for i, s := range stored {
+		identities[s.recipient] = unwrapped[i]
+	}
+
+	identityFunc := func(_ context.Context, recipient string) (age.Identity, error) {
+		identity, ok := identities[recipient]
+		if !ok {
+			return nil, sopskeyservice.ErrNoIdentity
+		}
+
+		return identity, nil
+	}
+
+	logger := o.Logger()
+
+	lis, err := vaultdaemon.Listen(ctx, o.socket, logger)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	o.Infof("serving %d age identities on %s\n", len(identities), o.socket)
+	o.Infof("use: sops --keyservice unix://%s --enable-local-keyservice=false ...\n", o.socket)
+
+	return sopskeyservice.Serve(ctx, lis, sopskeyservice.NewServer(identityFunc, logger))
+}
+
+// NewCmdSops creates the sops cobra command.
+func NewCmdSops(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sops",
+		Short: i18n.T("Use vlt as a key service for sops (subcommands available)"),
+		Long: `Use vlt as a key service for sops encrypted files.
+
+The key service encrypts and decrypts sops data keys
+using the age identities stored by 'vlt age'.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(NewCmdSopsKeyservice(defaults))
+
+	return cmd
+}
+
+// NewCmdSopsKeyservice creates the sops keyservice cobra command.
+func NewCmdSopsKeyservice(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewSopsKeyserviceOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "keyservice",
+		Short: i18n.T("Serve the sops key service backed by stored age identities"),
+		Long: `Serve the sops key service over a unix domain socket until interrupted.
+
+Data keys of sops files with age master keys are encrypted to the recipient
+of the key, and decrypted using the stored identity of the recipient.
+Other master keys, e.g., AWS KMS or PGP, are rejected, and left to sops.
+
+The stored identities are decrypted once on startup, identities added
+afterwards are served after a restart. The socket is created with
+mode 0600 and only accepts connections of the current user.
+
+The socket defaults to /run/user/<uid>/vlt-sops.sock.`,
+		Example: `  # Serve the stored age identities
+  vlt sops keyservice
+
+  # Decrypt a sops file in another shell
+  sops --keyservice unix:///run/user/$(id -u)/vlt-sops.sock \
+    --enable-local-keyservice=false decrypt secrets.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVar(&o.socket, "socket", "", "path of the unix domain socket to listen on")
+
+	return cmd
+}
@@ -0,0 +1,466 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// sshLabel is the label of the secrets holding SSH keys.
+	sshLabel = "ssh"
+
+	// sshNamePrefix prefixes the key name in the secret name of an SSH key.
+	sshNamePrefix = "ssh/"
+
+	// minRSABits is the smallest RSA key size generated, as recommended by NIST.
+	minRSABits = 3072
+)
+
+// SSH key types.
+const (
+	sshKeyTypeEd25519 = "ed25519"
+	sshKeyTypeRSA     = "rsa"
+)
+
+// Attributes of the secrets holding SSH keys.
+const (
+	sshAttrPublicKey = "public_key" // sshAttrPublicKey is the key in the authorized_keys format.
+	sshAttrType      = "type"
+)
+
+type SSHError struct {
+	Err error
+}
+
+func (e *SSHError) Error() string { return "ssh: " + e.Err.Error() }
+
+func (e *SSHError) Unwrap() error { return e.Err }
+
+// sshKey is an SSH key stored in the vault.
+//
+// The key is a secret labeled [sshLabel] holding the private key in the
+// OpenSSH PEM format, its public key and type are stored as attributes.
+type sshKey struct {
+	id        int
+	name      string
+	keyType   string
+	publicKey string
+}
+
+// sshKey returns the stored SSH key with the given name.
+func (o *VaultOptions) sshKey(ctx context.Context, name string) (sshKey, error) {
+	secrets, err := o.vault.FilterSecrets(ctx, "", "", []string{sshLabel})
+	if err != nil {
+		return sshKey{}, err
+	}
+
+	notFound := fmt.Errorf("%w: ssh key %q", vaulterrors.ErrSearchNoMatch, name)
+
+	if len(secrets) == 0 {
+		return sshKey{}, notFound
+	}
+
+	ids := slices.Sorted(maps.Keys(secrets))
+
+	attrs, err := o.vault.SecretsAttributes(ctx, ids...)
+	if err != nil {
+		return sshKey{}, err
+	}
+
+	for _, id := range ids {
+		a := attrs[id]
+
+		if secrets[id].Name != sshNamePrefix+name || len(a[sshAttrPublicKey]) == 0 {
+			continue
+		}
+
+		return sshKey{id: id, name: name, keyType: a[sshAttrType], publicKey: a[sshAttrPublicKey]}, nil
+	}
+
+	return sshKey{}, notFound
+}
+
+// sshSigner returns the private key of k, the caller should not keep it
+// longer than needed.
+func (o *VaultOptions) sshSigner(ctx context.Context, stdio *genericclioptions.StdioOptions, k sshKey) (crypto.Signer, error) {
+	var priv any
+
+	err := o.vault.WithSecret(ctx, k.id, func(pemBytes []byte) (err error) {
+		priv, err = ssh.ParseRawPrivateKey(pemBytes)
+		if err != nil {
+			return fmt.Errorf("parse ssh key %q: %w", k.name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("ssh key %q: unsupported key type %T", k.name, priv)
+	}
+
+	o.recordAccess(ctx, stdio, k.id)
+
+	return signer, nil
+}
+
+// generateSSHKey returns a new private key of the given type,
+// and its OpenSSH PEM encoding. bits only applies to RSA keys.
+func generateSSHKey(keyType string, bits int, comment string) (crypto.Signer, []byte, error) {
+	var priv crypto.Signer
+
+	switch keyType {
+	case sshKeyTypeEd25519:
+		_, k, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		priv = k
+	case sshKeyTypeRSA:
+		k, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		priv = k
+	default:
+		return nil, nil, fmt.Errorf("unsupported key type %q: expected %s or %s", keyType, sshKeyTypeEd25519, sshKeyTypeRSA)
+	}
+
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return priv, pem.EncodeToMemory(block), nil
+}
+
+// authorizedKey returns the public key of priv as an authorized_keys line,
+// followed by the comment if set.
+func authorizedKey(priv crypto.Signer, comment string) (string, error) {
+	pub, err := ssh.NewPublicKey(priv.Public())
+	if err != nil {
+		return "", err
+	}
+
+	line := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pub)), "\n")
+	if len(comment) > 0 {
+		line += " " + comment
+	}
+
+	return line, nil
+}
+
+// SSHKeygenOptions holds data required to run the command.
+type SSHKeygenOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	name    string
+	keyType string // keyType is the key algorithm, ed25519 or rsa.
+	bits    int    // bits is the size of RSA keys.
+	comment string // comment is appended to the public key, e.g., user@host.
+}
+
+var _ genericclioptions.CmdOptions = &SSHKeygenOptions{}
+
+// NewSSHKeygenOptions initializes the options struct.
+func NewSSHKeygenOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *SSHKeygenOptions {
+	return &SSHKeygenOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (o *SSHKeygenOptions) Complete() error {
+	o.keyType = strings.ToLower(o.keyType)
+	return nil
+}
+
+func (o *SSHKeygenOptions) Validate() error {
+	if len(o.name) == 0 {
+		return &SSHError{errors.New("empty key name")}
+	}
+
+	switch o.keyType {
+	case sshKeyTypeEd25519:
+	case sshKeyTypeRSA:
+		if o.bits < minRSABits {
+			return &SSHError{fmt.Errorf("RSA keys must be at least %d bits", minRSABits)}
+		}
+	default:
+		return &SSHError{fmt.Errorf("unsupported key type %q: expected %s or %s", o.keyType, sshKeyTypeEd25519, sshKeyTypeRSA)}
+	}
+
+	return nil
+}
+
+func (o *SSHKeygenOptions) Run(ctx context.Context, _ ...string) error {
+	if _, err := o.sshKey(ctx, o.name); err == nil {
+		return &SSHError{fmt.Errorf("ssh key %q already exists", o.name)}
+	} else if !errors.Is(err, vaulterrors.ErrSearchNoMatch) {
+		return &SSHError{err}
+	}
+
+	priv, pemBytes, err := generateSSHKey(o.keyType, o.bits, o.comment)
+	if err != nil {
+		return &SSHError{err}
+	}
+	defer securebytes.Wipe(pemBytes)
+
+	pub, err := authorizedKey(priv, o.comment)
+	if err != nil {
+		return &SSHError{err}
+	}
+
+	id, err := o.vault.InsertNewSecret(ctx, sshNamePrefix+o.name, pemBytes, []string{sshLabel})
+	if err != nil {
+		return &SSHError{err}
+	}
+
+	attrs := map[string]string{
+		sshAttrPublicKey: pub,
+		sshAttrType:      o.keyType,
+	}
+
+	if err := o.vault.UpdateSecretAttributes(ctx, id, attrs, nil); err != nil {
+		return &SSHError{err}
+	}
+
+	o.persistRequired = true
+
+	_, err = fmt.Fprintln(o.Out, pub)
+
+	return err
+}
+
+// SSHPubkeyOptions holds data required to run the command.
+type SSHPubkeyOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	name   string
+	output string // output is the file the public key is written to, stdout if empty.
+}
+
+var _ genericclioptions.CmdOptions = &SSHPubkeyOptions{}
+
+// NewSSHPubkeyOptions initializes the options struct.
+func NewSSHPubkeyOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *SSHPubkeyOptions {
+	return &SSHPubkeyOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*SSHPubkeyOptions) Complete() error { return nil }
+
+func (*SSHPubkeyOptions) Validate() error { return nil }
+
+func (o *SSHPubkeyOptions) Run(ctx context.Context, _ ...string) error {
+	k, err := o.sshKey(ctx, o.name)
+	if err != nil {
+		return &SSHError{err}
+	}
+
+	if len(o.output) > 0 {
+		if err := os.WriteFile(o.output, []byte(k.publicKey+"\n"), 0o644); err != nil { //nolint:gosec // public keys are not sensitive
+			return &SSHError{err}
+		}
+
+		return nil
+	}
+
+	_, err = fmt.Fprintln(o.Out, k.publicKey)
+
+	return err
+}
+
+// SSHCSROptions holds data required to run the command.
+type SSHCSROptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	name       string
+	commonName string   // commonName is the subject common name of the request.
+	dnsNames   []string // dnsNames are the subject alternative names of the request.
+	output     string   // output is the file the request is written to, stdout if empty.
+}
+
+var _ genericclioptions.CmdOptions = &SSHCSROptions{}
+
+// NewSSHCSROptions initializes the options struct.
+func NewSSHCSROptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *SSHCSROptions {
+	return &SSHCSROptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*SSHCSROptions) Complete() error { return nil }
+
+func (o *SSHCSROptions) Validate() error {
+	if len(o.commonName) == 0 && len(o.dnsNames) == 0 {
+		return &SSHError{errors.New("set a subject using --common-name or --dns-name")}
+	}
+
+	return nil
+}
+
+func (o *SSHCSROptions) Run(ctx context.Context, _ ...string) error {
+	k, err := o.sshKey(ctx, o.name)
+	if err != nil {
+		return &SSHError{err}
+	}
+
+	signer, err := o.sshSigner(ctx, o.StdioOptions, k)
+	if err != nil {
+		return &SSHError{err}
+	}
+
+	template := &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: cmp.Or(o.commonName, o.dnsNames[0])},
+		DNSNames: o.dnsNames,
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, signer)
+	if err != nil {
+		return &SSHError{fmt.Errorf("create certificate request: %w", err)}
+	}
+
+	csr := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+
+	if len(o.output) > 0 {
+		if err := os.WriteFile(o.output, csr, 0o644); err != nil { //nolint:gosec // requests are not sensitive
+			return &SSHError{err}
+		}
+
+		return nil
+	}
+
+	_, err = o.Out.Write(csr)
+
+	return err
+}
+
+// NewCmdSSH creates the ssh cobra command.
+func NewCmdSSH(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssh",
+		Short: i18n.T("Generate SSH keys inside the vault (subcommands available)"),
+		Long: `Generate SSH keys inside the vault.
+
+A key is saved as a secret named 'ssh/<name>' with the 'ssh' label,
+holding the private key in the OpenSSH format, its public key and type
+are stored as encrypted attributes.
+
+The private key is generated in memory and written to the vault only,
+it never exists on disk unencrypted. Use 'vlt show' to retrieve it if needed.`,
+		Example: `  # Generate an ed25519 key and print its public key
+  vlt ssh keygen github --comment alice@laptop
+
+  # Append the public key to the authorized keys of a server
+  vlt ssh pubkey github | ssh server 'cat >> ~/.ssh/authorized_keys'
+
+  # Request a TLS certificate for the key
+  vlt ssh csr github --common-name alice.example.com --output alice.csr`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(NewCmdSSHKeygen(defaults))
+	cmd.AddCommand(NewCmdSSHPubkey(defaults))
+	cmd.AddCommand(NewCmdSSHCSR(defaults))
+
+	return cmd
+}
+
+// NewCmdSSHKeygen creates the ssh keygen cobra command.
+func NewCmdSSHKeygen(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewSSHKeygenOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "keygen name",
+		Short: i18n.T("Generate an SSH key pair into the vault"),
+		Long: `Generate an SSH key pair into the vault, and print its public key
+in the authorized_keys format.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.name = args[0]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.keyType, "type", "t", sshKeyTypeEd25519, "key type: ed25519 or rsa")
+	cmd.Flags().IntVarP(&o.bits, "bits", "b", minRSABits, "size of RSA keys in bits")
+	cmd.Flags().StringVarP(&o.comment, "comment", "C", "", "comment appended to the public key, e.g., user@host")
+
+	return cmd
+}
+
+// NewCmdSSHPubkey creates the ssh pubkey cobra command.
+func NewCmdSSHPubkey(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewSSHPubkeyOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "pubkey name",
+		Short: i18n.T("Print the public key of an SSH key"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.name = args[0]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "write the public key to the specified file path")
+
+	return cmd
+}
+
+// NewCmdSSHCSR creates the ssh csr cobra command.
+func NewCmdSSHCSR(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewSSHCSROptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "csr name",
+		Short: i18n.T("Create a certificate signing request for an SSH key"),
+		Long: `Create a PEM encoded X.509 certificate signing request signed by an SSH key,
+e.g., to be issued a TLS client certificate for the same key pair.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.name = args[0]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.commonName, "common-name", "", "", "subject common name, defaults to the first --dns-name")
+	cmd.Flags().StringSliceVarP(&o.dnsNames, "dns-name", "", nil, "subject alternative DNS name, may be repeated")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "write the request to the specified file path")
+
+	return cmd
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/style"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// staleAccessAge is the time since the last retrieval
+	// after which a secret is listed as a cleanup candidate.
+	staleAccessAge = 90 * 24 * time.Hour
+
+	// defaultStatsLimit is the default number of most and least used secrets shown.
+	defaultStatsLimit = 5
+)
+
+type StatsError struct {
+	Err error
+}
+
+func (e *StatsError) Error() string { return "stats: " + e.Err.Error() }
+
+func (e *StatsError) Unwrap() error { return e.Err }
+
+// StatsOptions holds data required to run the command.
+type StatsOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	limit int
+}
+
+var _ genericclioptions.CmdOptions = &StatsOptions{}
+
+// NewStatsOptions initializes the options struct.
+func NewStatsOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *StatsOptions {
+	return &StatsOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*StatsOptions) Complete() error { return nil }
+
+func (o *StatsOptions) Validate() error {
+	if o.limit < 1 {
+		return &StatsError{errors.New("--limit must be a positive integer")}
+	}
+
+	return nil
+}
+
+func (o *StatsOptions) Run(ctx context.Context, _ ...string) error {
+	if !o.trackUsage {
+		o.Infof("usage tracking is disabled, set 'track_usage = true' in the [vault] section of the config file to enable it\n")
+	}
+
+	usage, err := o.vault.SecretsUsage(ctx)
+	if err != nil {
+		return &StatsError{err}
+	}
+
+	usage = o.discreetUsage(usage)
+
+	accessed := slices.DeleteFunc(slices.Clone(usage), func(u vaultdb.SecretUsage) bool { return u.AccessCount == 0 })
+
+	// most used first, the most recently used first among equals.
+	slices.SortStableFunc(accessed, func(a, b vaultdb.SecretUsage) int {
+		return cmp.Or(cmp.Compare(b.AccessCount, a.AccessCount), b.LastAccessed.Compare(a.LastAccessed))
+	})
+
+	mostUsed := accessed[:min(o.limit, len(accessed))]
+
+	leastUsed := slices.Clone(accessed[max(0, len(accessed)-o.limit):])
+	slices.Reverse(leastUsed)
+
+	staleBefore := time.Now().Add(-staleAccessAge)
+	candidates := slices.DeleteFunc(slices.Clone(usage), func(u vaultdb.SecretUsage) bool {
+		return u.LastAccessed.After(staleBefore)
+	})
+
+	var buf bytes.Buffer
+
+	st := o.Styler(o.Out)
+
+	printUsageTable(&buf, st, "Most used", mostUsed)
+	printUsageTable(&buf, st, "Least used", leastUsed)
+	printUsageTable(&buf, st, fmt.Sprintf("Cleanup candidates, not retrieved in the last %d days", staleAccessAge/(24*time.Hour)), candidates)
+
+	_, err = buf.WriteTo(o.Out)
+
+	return err
+}
+
+// printUsageTable writes the usage of secrets as a titled table.
+func printUsageTable(w io.Writer, st *style.Styler, title string, usage []vaultdb.SecretUsage) {
+	fmt.Fprintf(w, "%s:\n", st.Header(title))
+
+	if len(usage) == 0 {
+		fmt.Fprintf(w, "  none\n\n")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(tw, "  ID\tNAME\tRETRIEVALS\tLAST RETRIEVED")
+
+	for _, u := range usage {
+		last := "never"
+		if !u.LastAccessed.IsZero() {
+			last = u.LastAccessed.Local().Format(time.DateTime)
+		}
+
+		fmt.Fprintf(tw, "  %d\t%s\t%d\t%s\n", u.ID, u.Name, u.AccessCount, last)
+	}
+
+	_ = tw.Flush()
+
+	fmt.Fprintln(w)
+}
+
+// NewCmdStats creates the stats cobra command.
+func NewCmdStats(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewStatsOptions(
+		defaults.StdioOptions,
+		defaults.vaultOptions,
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: i18n.T("Show secret usage statistics"),
+		Long: fmt.Sprintf(`Show secret usage statistics.
+
+Lists the most and least retrieved secrets, and the cleanup candidates:
+secrets never retrieved or not retrieved in the last %d days.
+
+Retrievals by 'vlt show' are only recorded while usage tracking is enabled
+by 'track_usage = true' in the [vault] section of the config file.
+The statistics are stored in the encrypted vault and never leave it.`, staleAccessAge/(24*time.Hour)),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().IntVarP(&o.limit, "limit", "", defaultStatsLimit, "number of most and least used secrets to show")
+
+	return cmd
+}
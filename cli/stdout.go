@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+
+	"golang.org/x/term"
+)
+
+// errNotPrinted is returned if printing to the terminal was declined, see [confirmTerminalStdout].
+var errNotPrinted = errors.New("not printed to the terminal")
+
+// confirmTerminalStdout prompts before secrets are printed to stdout if it is a terminal,
+// where they remain in its scrollback, unless force is set, i.e., --force-stdout.
+// Piped or redirected stdout, e.g., of scripts, is not affected.
+// Non-interactive runs printing to a terminal require force.
+func confirmTerminalStdout(ctx context.Context, stdio *genericclioptions.StdioOptions, force bool) error {
+	if force || !stdio.OutIsTerminal() {
+		return nil
+	}
+
+	if stdio.StdinIsPiped {
+		return errors.New("stdout is a terminal, use --force-stdout to print secrets to it")
+	}
+
+	yes, err := confirm(ctx, stdio.Prompter(), "Print to the terminal? The output remains in its scrollback (y/N): ")
+	if err != nil {
+		return err
+	}
+
+	if !yes {
+		return errNotPrinted
+	}
+
+	return nil
+}
+
+// terminalLines returns the number of lines text takes on a terminal of the given width,
+// wrapping longer lines, or not if width is unknown, i.e., zero. Characters are assumed
+// to be one column wide.
+func terminalLines(text []byte, width int) int {
+	n := 0
+
+	for l := range bytes.SplitSeq(bytes.TrimSuffix(text, []byte("\n")), []byte("\n")) {
+		columns := utf8.RuneCount(l)
+		if width <= 0 || columns <= width {
+			n++
+			continue
+		}
+
+		n += (columns + width - 1) / width
+	}
+
+	return n
+}
+
+// terminalWidth returns the width of the terminal w, or zero if unknown.
+func terminalWidth(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+
+	width, _, err := term.GetSize(int(f.Fd())) //nolint:gosec // fd fits in int.
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// clearTerminalLines waits for d, or until ctx is done, e.g., on Ctrl-C, and then
+// clears the last n lines written to the terminal w using ANSI escape sequences,
+// leaving the cursor at the start of the first. Lines scrolled out of the screen
+// in the meantime are not cleared.
+func clearTerminalLines(ctx context.Context, w io.Writer, n int, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+
+	// cursor up n lines, to the first column, and erase to the end of the screen.
+	_, err := fmt.Fprintf(w, "\x1b[%dA\r\x1b[J", n)
+
+	return err
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/randstring"
+)
+
+var ErrUnknownTemplate = errors.New("unknown template")
+
+// placeholderRE matches {field} placeholders in template names and attribute values.
+var placeholderRE = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// validate verifies that the template has a name, that its fields
+// and attribute keys are valid, and that every placeholder refers to a field.
+func (t TemplateConfig) validate() error {
+	if len(t.Name) == 0 {
+		return errors.New("'name' must be set")
+	}
+
+	for i, f := range t.Fields {
+		if !attributeKeyRE.MatchString(f) {
+			return fmt.Errorf("invalid field %q: expected letters, digits, '_', '.' or '-'", f)
+		}
+
+		if slices.Contains(t.Fields[:i], f) {
+			return fmt.Errorf("field %q declared more than once", f)
+		}
+	}
+
+	if err := t.validatePlaceholders("name", t.Name); err != nil {
+		return err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(t.Attrs)) {
+		if err := validateAttributeKey(key); err != nil {
+			return err
+		}
+
+		if len(t.Attrs[key]) == 0 {
+			return fmt.Errorf("attrs.%s: must not be empty", key)
+		}
+
+		if err := t.validatePlaceholders("attrs."+key, t.Attrs[key]); err != nil {
+			return err
+		}
+	}
+
+	if p := t.Policy; p != nil && min(p.UpperCase, p.LowerCase, p.Numeric, p.Special, p.MinLength) < 0 {
+		return errors.New("policy: values must be zero or positive integers")
+	}
+
+	return nil
+}
+
+func (t TemplateConfig) validatePlaceholders(opt string, s string) error {
+	for _, m := range placeholderRE.FindAllStringSubmatch(s, -1) {
+		if !slices.Contains(t.Fields, m[1]) {
+			return fmt.Errorf("%s: placeholder {%s} does not refer to a field in 'fields'", opt, m[1])
+		}
+	}
+
+	return nil
+}
+
+// expand replaces the {field} placeholders in s with the field values.
+func (TemplateConfig) expand(s string, fields map[string]string) string {
+	return placeholderRE.ReplaceAllStringFunc(s, func(p string) string {
+		return fields[p[1:len(p)-1]]
+	})
+}
+
+// passwordPolicy returns the policy of generated secrets,
+// or [randstring.DefaultPasswordPolicy] if none is set.
+func (t TemplateConfig) passwordPolicy() randstring.PasswordPolicy {
+	p := t.Policy
+	if p == nil || *p == (PasswordPolicyConfig{}) {
+		return randstring.DefaultPasswordPolicy
+	}
+
+	return randstring.PasswordPolicy{
+		MinUppercase: p.UpperCase,
+		MinLowercase: p.LowerCase,
+		MinNumeric:   p.Numeric,
+		MinSpecial:   p.Special,
+		MinLength:    p.MinLength,
+	}
+}
+
+// lookupTemplate returns the template with the given name.
+func lookupTemplate(templates map[string]TemplateConfig, name string) (TemplateConfig, error) {
+	t, ok := templates[name]
+	if !ok {
+		available := "none configured"
+		if len(templates) > 0 {
+			available = "expected one of: " + strings.Join(slices.Sorted(maps.Keys(templates)), ", ")
+		}
+
+		return TemplateConfig{}, fmt.Errorf("%w %q (%s)", ErrUnknownTemplate, name, available)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// templateHelperCommand is the name of the template helper command.
+	templateHelperCommand = "template-helper"
+
+	// templateHelperFlag selects the template helper, e.g., 'vlt --template-helper get foo'.
+	templateHelperFlag = "--template-helper"
+)
+
+// templateHelperArgs are the global flags the template helper runs with,
+// it never prompts, and writes nothing but the requested output to stdout.
+var templateHelperArgs = []string{"--no-login-prompt", "--quiet"}
+
+type TemplateHelperError struct {
+	Err error
+}
+
+func (e *TemplateHelperError) Error() string { return "template helper: " + e.Err.Error() }
+
+func (e *TemplateHelperError) Unwrap() error { return e.Err }
+
+// expandTemplateHelper replaces the --template-helper global flag by
+// the template helper command, and adds the global flags it runs with.
+func expandTemplateHelper(root *cobra.Command, args []string) []string {
+	i, _ := scanRootArgs(root.PersistentFlags(), args)
+
+	j := slices.Index(args, templateHelperFlag)
+	if j >= 0 && (i < 0 || j < i) {
+		return slices.Concat(args[:j], templateHelperArgs, []string{templateHelperCommand}, args[j+1:])
+	}
+
+	if i >= 0 && args[i] == templateHelperCommand {
+		return slices.Concat(args[:i], templateHelperArgs, args[i:])
+	}
+
+	return args
+}
+
+// templateHelperSecret is the output of the json request of the template helper,
+// see templatehelper.Secret.
+type templateHelperSecret struct {
+	Name       string            `json:"name"`
+	Labels     []string          `json:"labels"`
+	Attributes map[string]string `json:"attributes"`
+	Value      string            `json:"value"`
+}
+
+// TemplateHelperOptions holds data required to run the command.
+type TemplateHelperOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	json bool // json writes the secret as a JSON object instead of its value.
+}
+
+var _ genericclioptions.CmdOptions = &TemplateHelperOptions{}
+
+// NewTemplateHelperOptions initializes the options struct.
+func NewTemplateHelperOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions, asJSON bool) *TemplateHelperOptions {
+	return &TemplateHelperOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+		json:         asJSON,
+	}
+}
+
+func (*TemplateHelperOptions) Complete() error { return nil }
+
+func (*TemplateHelperOptions) Validate() error { return nil }
+
+func (o *TemplateHelperOptions) Run(ctx context.Context, args ...string) error {
+	if err := o.run(ctx, args[0]); err != nil {
+		return &TemplateHelperError{err}
+	}
+
+	return nil
+}
+
+func (o *TemplateHelperOptions) run(ctx context.Context, name string) error {
+	secrets, err := o.vault.FilterSecrets(ctx, "", name, nil)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, 1)
+
+	for id, s := range secrets {
+		if s.Name == name { // the name filter is a glob pattern.
+			ids = append(ids, id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return fmt.Errorf("%w: %q", vaulterrors.ErrSearchNoMatch, name)
+	case 1:
+	default:
+		return fmt.Errorf("%w: %d secrets named %q", vaulterrors.ErrAmbiguousSecretMatch, len(ids), name)
+	}
+
+	id := ids[0]
+
+	err = o.vault.WithSecret(ctx, id, func(value []byte) error {
+		return o.output(ctx, id, name, secrets[id].Labels, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	o.recordAccess(ctx, o.StdioOptions, id)
+
+	return nil
+}
+
+// output writes the value of the secret, or the secret as json if --json is set.
+func (o *TemplateHelperOptions) output(ctx context.Context, id int, name string, labels []string, value []byte) error {
+	if !o.json {
+		return writeSecret(o.Out, value)
+	}
+
+	if !utf8.Valid(value) {
+		return errors.New("the value is not valid UTF-8, use the get request")
+	}
+
+	attrs, err := o.vault.SecretsAttributes(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	attributes := attrs[id]
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+
+	return json.NewEncoder(o.Out).Encode(templateHelperSecret{
+		Name:       name,
+		Labels:     nonNil(labels),
+		Attributes: attributes,
+		Value:      string(value),
+	})
+}
+
+// nonNil returns s, or an empty slice if s is nil, encoded as [] instead of null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
+
+// NewCmdTemplateHelper creates the template-helper cobra command.
+func NewCmdTemplateHelper(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   templateHelperCommand,
+		Short: i18n.T("Query secrets for dotfile managers, e.g., chezmoi (subcommands available)"),
+		Long: `Query secrets for dotfile managers and other programs rendering secrets
+into files, e.g., the secret template functions of chezmoi.
+
+Also run as 'vlt --template-helper', the template helper never prompts
+for the password, it uses an existing session, see 'vlt login', or fails.
+Informational messages are not written, stdout only holds the output below.
+
+Output:
+  get <name>    the value of the secret, as is, without a trailing newline
+  json <name>   {"name": ..., "labels": [...], "attributes": {...}, "value": ...}
+
+Secrets are matched by their exact name.
+
+Exit codes:
+  0 success, 3 no secret found, 4 more than one secret found,
+  5 session use denied, 6 vault locked, 8 vault file not found, 1 other failure.`,
+		Example: `  # chezmoi configuration, ~/.config/chezmoi/chezmoi.toml
+  [secret]
+  command = "vlt"
+  args = ["--template-helper"]
+
+  # chezmoi templates
+  {{ secret "get" "github/token" }}
+  {{ (secretJSON "json" "smtp").attributes.user }}`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newCmdTemplateHelperRequest(defaults, false))
+	cmd.AddCommand(newCmdTemplateHelperRequest(defaults, true))
+
+	return cmd
+}
+
+func newCmdTemplateHelperRequest(defaults *DefaultVltOptions, asJSON bool) *cobra.Command {
+	o := NewTemplateHelperOptions(defaults.StdioOptions, defaults.vaultOptions, asJSON)
+
+	use, short := "get name", i18n.T("Write the value of a secret")
+	if asJSON {
+		use, short = "json name", i18n.T("Write a secret and its metadata as JSON")
+	}
+
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+}
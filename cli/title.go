@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vaultdaemon"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/spf13/cobra"
+)
+
+type TitleError struct {
+	Err error
+}
+
+func (e *TitleError) Error() string { return "title: " + e.Err.Error() }
+
+func (e *TitleError) Unwrap() error { return e.Err }
+
+// TitleOptions holds data required to run the command.
+type TitleOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+}
+
+var _ genericclioptions.CmdOptions = &TitleOptions{}
+
+// NewTitleOptions initializes the options struct.
+func NewTitleOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *TitleOptions {
+	return &TitleOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*TitleOptions) Complete() error { return nil }
+
+func (o *TitleOptions) Validate() error {
+	exists, err := o.vaultExists()
+	if err != nil {
+		return &TitleError{err}
+	}
+
+	if !exists {
+		return &TitleError{fmt.Errorf("%w: %s", vaulterrors.ErrVaultFileNotFound, o.path)}
+	}
+
+	return nil
+}
+
+func (o *TitleOptions) Run(ctx context.Context, args ...string) error {
+	if len(args) == 0 {
+		i, err := vault.ReadIdentity(ctx, o.path)
+		if err != nil {
+			return &TitleError{err}
+		}
+
+		o.Printf("id:    %s\ntitle: %s\n", i.ID, i.Title)
+
+		return nil
+	}
+
+	if err := vault.SetTitle(ctx, o.path, args[0]); err != nil {
+		return &TitleError{err}
+	}
+
+	o.updateSession(ctx)
+
+	if len(args[0]) == 0 {
+		o.Infof("title cleared\n")
+		return nil
+	}
+
+	o.Infof("title set to %q\n", args[0])
+
+	return nil
+}
+
+// updateSession updates the daemon session of the vault, if any, to the vault file
+// rewritten by setting the title. Without a running daemon there is no session to update.
+func (o *TitleOptions) updateSession(ctx context.Context) {
+	sessionClient, err := vaultdaemon.NewSessionClient()
+	if err != nil {
+		o.Debugf("vlt: session not updated: %v\n", err)
+		return
+	}
+	defer func() { _ = sessionClient.Close() }() //nolint:wsl_v5
+
+	o.updateSessionStamp(ctx, o.StdioOptions, sessionClient)
+}
+
+// NewCmdTitle creates the title cobra command.
+func NewCmdTitle(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewTitleOptions(
+		defaults.StdioOptions,
+		defaults.vaultOptions,
+	)
+
+	cmd := &cobra.Command{
+		Use:   "title [TITLE]",
+		Short: i18n.T("Show or set the title of the vault"),
+		Long: fmt.Sprintf(`Show the id and title of the vault, or set its title.
+
+Each vault has a random id and an optional human readable title,
+stored unencrypted in the vault file. Both are read without unlocking the vault.
+The title names the vault in password prompts, e.g., "Password for work vault:".
+The id identifies the sessions of the vault in the daemon, which are kept
+when the vault file is moved or renamed.
+
+A title is a single line of at most %d characters, an empty title clears it.`, vault.MaxTitleLen),
+		Example: `  # Show the id and title of the vault
+  vlt title
+
+  # Set the title of the vault
+  vlt title work
+
+  # Clear the title of the vault
+  vlt title ""`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+
+	return cmd
+}
+
+// vaultIdentity returns the identity of the vault, read once, nil if it
+// cannot be read, e.g., of a read-only vault file predating vault ids.
+func (o *VaultOptions) vaultIdentity(ctx context.Context, io *genericclioptions.StdioOptions) *vault.Identity {
+	if o.identity != nil {
+		return o.identity
+	}
+
+	i, err := vault.ReadIdentity(ctx, o.path)
+	if err != nil {
+		io.Debugf("vlt: vault identity unavailable, using the vault path: %v\n", err)
+		return nil
+	}
+
+	o.identity = i
+
+	return i
+}
+
+// sessionOptions identifies the daemon session of the vault by its id if available,
+// see [vaultdaemon.WithVaultID].
+func (o *VaultOptions) sessionOptions(ctx context.Context, io *genericclioptions.StdioOptions, opts ...vaultdaemon.RequestOption) []vaultdaemon.RequestOption {
+	if i := o.vaultIdentity(ctx, io); i != nil {
+		opts = append(opts, vaultdaemon.WithVaultID(i.ID))
+	}
+
+	return opts
+}
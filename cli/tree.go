@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ladzaretti/vlt-cli/style"
+)
+
+const (
+	// unlabeledGroup groups the secrets without labels in trees.
+	unlabeledGroup = "(unlabeled)"
+
+	// multiLabelMark marks secrets listed under several labels in trees.
+	multiLabelMark = "*"
+)
+
+// printSecretsTree writes the secrets grouped by label, see [printTree].
+//
+// In discreet mode, names are masked and labels are reduced to hints,
+// as done by [VaultOptions.printSecrets].
+func (o *VaultOptions) printSecretsTree(ctx context.Context, w io.Writer, st *style.Styler, secrets []secretWithLabels) {
+	if o.discreet {
+		masked := make([]secretWithLabels, len(secrets))
+		for i, s := range secrets {
+			masked[i] = secretWithLabels{id: s.id, name: maskedName, labels: s.labels}
+		}
+
+		printTree(w, masked, labelHint)
+
+		return
+	}
+
+	meta := o.labelsMeta(ctx)
+
+	printTree(w, secrets, func(l string) string {
+		return formatLabels([]string{l}, meta, st)
+	})
+}
+
+// printTree writes the secrets grouped under their labels, sorted by label
+// and followed by the unlabeled secrets, keeping the order of the secrets
+// within each group. formatLabel renders the label of each group.
+//
+// Secrets with several labels are listed under each of them, marked with
+// [multiLabelMark], and counted once in the closing total.
+func printTree(w io.Writer, secrets []secretWithLabels, formatLabel func(string) string) {
+	var (
+		groups    = make(map[string][]secretWithLabels)
+		unlabeled []secretWithLabels
+		multi     int
+	)
+
+	for _, s := range secrets {
+		// empty labels, e.g., imported from an empty column, are not grouped by.
+		s.labels = slices.DeleteFunc(slices.Clone(s.labels), func(l string) bool { return len(l) == 0 })
+
+		if len(s.labels) == 0 {
+			unlabeled = append(unlabeled, s)
+			continue
+		}
+
+		if len(s.labels) > 1 {
+			multi++
+		}
+
+		for _, l := range s.labels {
+			groups[l] = append(groups[l], s)
+		}
+	}
+
+	labels := make([]string, 0, len(groups))
+	for l := range groups {
+		labels = append(labels, l)
+	}
+
+	slices.Sort(labels)
+
+	// group headings hold no tabs, each group is aligned on its own,
+	// and styled labels do not count towards the column widths.
+	tw := tabwriter.NewWriter(w, 0, 0, 5, ' ', 0)
+
+	printGroup := func(heading string, group []secretWithLabels) {
+		fmt.Fprintf(tw, "%s (%d)\n", heading, len(group))
+
+		for i, s := range group {
+			branch := "├── "
+			if i == len(group)-1 {
+				branch = "└── "
+			}
+
+			name := s.name
+			if len(s.labels) > 1 {
+				name += " " + multiLabelMark
+			}
+
+			fmt.Fprintf(tw, "%s%d\t%s\n", branch, s.id, name)
+		}
+	}
+
+	for _, l := range labels {
+		printGroup(formatLabel(l), groups[l])
+	}
+
+	if len(unlabeled) > 0 {
+		printGroup(unlabeledGroup, unlabeled)
+	}
+
+	total := pluralize(len(secrets), "secret")
+	if multi > 0 {
+		total += fmt.Sprintf(", %d listed under several labels (%s)", multi, multiLabelMark)
+	}
+
+	fmt.Fprintf(tw, "\n%s\n", total)
+
+	_ = tw.Flush()
+}
+
+// pluralize returns n followed by noun, in plural unless n is one.
+func pluralize(n int, noun string) string {
+	if n != 1 {
+		noun += "s"
+	}
+
+	return strconv.Itoa(n) + " " + noun
+}
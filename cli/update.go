@@ -4,20 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/clipboard"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
 	"github.com/ladzaretti/vlt-cli/input"
 	"github.com/ladzaretti/vlt-cli/randstring"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault"
 	"github.com/ladzaretti/vlt-cli/vaulterrors"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	ErrNoUpdateArgs    = errors.New("no update arguments provided; specify at least one of --set-name, --add-label, or --remove-label")
+	ErrNoUpdateArgs    = errors.New("no update arguments provided; specify at least one of --set-name, --add-label, --remove-label, --set-attr, --unset-attr, --set-url, or --unset-url")
 	ErrNoSecretUpdated = errors.New("no secret was updated")
 )
 
@@ -37,6 +39,11 @@ type UpdateOptions struct {
 	newName      string
 	addLabels    []string
 	removeLabels []string
+	setAttrs     []string          // setAttrs holds the raw --set-attr key=value pairs.
+	unsetAttrs   []string          // unsetAttrs holds the keys of attributes to remove.
+	attrs        map[string]string // attrs holds the parsed setAttrs.
+	setURL       string            // setURL is the new url of the secret, see [vault.NormalizeURL].
+	unsetURL     bool              // unsetURL removes the url of the secret.
 }
 
 var _ genericclioptions.CmdOptions = &UpdateOptions{}
@@ -57,9 +64,44 @@ func (o *UpdateOptions) Validate() error {
 		return &UpdateError{err}
 	}
 
+	if err := o.validateAttributes(); err != nil {
+		return &UpdateError{err}
+	}
+
+	if len(o.setURL) > 0 {
+		if o.unsetURL {
+			return &UpdateError{errors.New("--set-url cannot be combined with --unset-url")}
+		}
+
+		if _, err := vault.NormalizeURL(o.setURL); err != nil {
+			return &UpdateError{err}
+		}
+	}
+
 	return o.validateUpdateArgs()
 }
 
+func (o *UpdateOptions) validateAttributes() error {
+	attrs, err := parseAttributes(o.setAttrs, true)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range o.unsetAttrs {
+		if err := validateAttributeKey(key); err != nil {
+			return err
+		}
+
+		if _, ok := attrs[key]; ok {
+			return fmt.Errorf("%w: key %q is both set and unset", ErrInvalidAttribute, key)
+		}
+	}
+
+	o.attrs = attrs
+
+	return nil
+}
+
 func (o *UpdateOptions) validateUpdateArgs() error {
 	args := 0
 
@@ -75,6 +117,14 @@ func (o *UpdateOptions) validateUpdateArgs() error {
 		args++
 	}
 
+	if len(o.attrs) > 0 || len(o.unsetAttrs) > 0 {
+		args++
+	}
+
+	if len(o.setURL) > 0 || o.unsetURL {
+		args++
+	}
+
 	if args == 0 {
 		return &UpdateError{ErrNoUpdateArgs}
 	}
@@ -107,12 +157,34 @@ func (o *UpdateOptions) Run(ctx context.Context, args ...string) (retErr error)
 		return vaulterrors.ErrSearchNoMatch
 	default:
 		o.Errorf("expecting exactly one match, but found %d.\n\n", count)
-		printTable(o.ErrOut, matchingSecrets)
+		o.printSecrets(ctx, o.ErrOut, o.Styler(o.ErrOut), matchingSecrets)
 
 		return vaulterrors.ErrAmbiguousSecretMatch
 	}
 
-	return o.vault.UpdateSecretMetadata(ctx, matchingSecrets[0].id, o.newName, o.removeLabels, o.addLabels)
+	id := matchingSecrets[0].id
+
+	if len(o.newName) > 0 {
+		warnSecretLikeName(o.StdioOptions, o.newName)
+	}
+
+	if len(o.newName) > 0 || len(o.addLabels) > 0 || len(o.removeLabels) > 0 {
+		if err := o.vault.UpdateSecretMetadata(ctx, id, o.newName, o.removeLabels, o.addLabels); err != nil {
+			return err
+		}
+	}
+
+	if len(o.setURL) > 0 || o.unsetURL {
+		if err := o.vault.SetSecretURL(ctx, id, o.setURL); err != nil {
+			return err
+		}
+	}
+
+	if len(o.attrs) > 0 || len(o.unsetAttrs) > 0 {
+		return o.vault.UpdateSecretAttributes(ctx, id, o.attrs, o.unsetAttrs)
+	}
+
+	return nil
 }
 
 // NewCmdUpdate creates the update cobra command.
@@ -121,13 +193,19 @@ func NewCmdUpdate(defaults *DefaultVltOptions) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "update [glob]",
-		Short: "Update secret data or metadata (subcommands available)",
+		Short: i18n.T("Update secret data or metadata (subcommands available)"),
 		Long: `Update metadata for an existing secret.
 
-This command updates metadata such as the name or labels of a secret.
+This command updates metadata such as the name, labels, attributes or url of a secret.
 The update will proceed only if exactly one secret matches the given search criteria.
 
-To update the secret value, use the 'vlt update secret' subcommand.`,
+Attributes are custom key-value pairs stored encrypted with the secret,
+see 'vlt show --attr' and 'vlt find --attr'.
+
+The url is the site the secret belongs to, see 'vlt show --url'.
+
+To update the secret value, use the 'vlt update secret' subcommand.
+To set the display color or icon of a label, use the 'vlt update label' subcommand.`,
 		Example: `  # Rename a secret by ID
   vlt update --id 42 --set-name foo
 
@@ -138,7 +216,16 @@ To update the secret value, use the 'vlt update secret' subcommand.`,
   vlt update --name foo --add-label bar
 
   # Remove a label from a secret
-  vlt update --id 42 --remove-label bar`,
+  vlt update --id 42 --remove-label bar
+
+  # Set attributes of a secret
+  vlt update --name foo --set-attr env=prod --set-attr owner=alice
+
+  # Remove an attribute from a secret
+  vlt update --name foo --unset-attr owner
+
+  # Set the url of a secret
+  vlt update --name foo --set-url https://github.com/login`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
 		},
@@ -151,8 +238,13 @@ To update the secret value, use the 'vlt update secret' subcommand.`,
 	cmd.Flags().StringVarP(&o.newName, "set-name", "", "", "new name for the secret")
 	cmd.Flags().StringSliceVarP(&o.addLabels, "add-label", "", nil, "label to add to the secret")
 	cmd.Flags().StringSliceVarP(&o.removeLabels, "remove-label", "", nil, "label to remove from the secret")
+	cmd.Flags().StringArrayVarP(&o.setAttrs, "set-attr", "", nil, "attribute to set on the secret, as key=value")
+	cmd.Flags().StringSliceVarP(&o.unsetAttrs, "unset-attr", "", nil, "key of the attribute to remove from the secret")
+	cmd.Flags().StringVarP(&o.setURL, "set-url", "", "", "url of the site the secret belongs to")
+	cmd.Flags().BoolVarP(&o.unsetURL, "unset-url", "", false, "remove the url of the secret")
 
 	cmd.AddCommand(NewCmdUpdateSecretValue(defaults))
+	cmd.AddCommand(NewCmdUpdateLabel(defaults))
 
 	return cmd
 }
@@ -168,6 +260,7 @@ type UpdateSecretValueOptions struct {
 	copy           bool // copy controls whether to copy the saved secret to the clipboard.
 	paste          bool // paste controls whether to read the secret to save from the clipboard.
 	nonInteractive bool // nonInteractive disables all interactive prompts.
+	stripNewline   bool // stripNewline removes a single trailing newline of piped or pasted input.
 }
 
 var _ genericclioptions.CmdOptions = &UpdateSecretValueOptions{}
@@ -232,7 +325,7 @@ func (o *UpdateSecretValueOptions) Run(ctx context.Context, args ...string) (ret
 		return &UpdateError{vaulterrors.ErrSearchNoMatch}
 	default:
 		o.Errorf("expecting exactly one match, but found %d.\n\n", count)
-		printTable(o.ErrOut, matchingSecrets)
+		o.printSecrets(ctx, o.ErrOut, o.Styler(o.ErrOut), matchingSecrets)
 
 		return &UpdateError{vaulterrors.ErrAmbiguousSecretMatch}
 	}
@@ -241,7 +334,7 @@ func (o *UpdateSecretValueOptions) Run(ctx context.Context, args ...string) (ret
 		id     = matchingSecrets[0].id
 		secret []byte
 	)
-	defer clear(secret)
+	defer func() { securebytes.Wipe(secret) }() // deferred closure, secret is reassigned below.
 
 	// ensure error is wrapped and output is printed if everything succeeded
 	defer func() {
@@ -266,7 +359,7 @@ func (o *UpdateSecretValueOptions) Run(ctx context.Context, args ...string) (ret
 	secret = s
 
 	if !o.nonInteractive && len(secret) == 0 {
-		s, err := o.promptReadSecure("Enter new secret value: ")
+		s, err := o.promptReadSecure(ctx, "Enter new secret value: ")
 		if err != nil {
 			return err
 		}
@@ -278,6 +371,14 @@ func (o *UpdateSecretValueOptions) Run(ctx context.Context, args ...string) (ret
 		return vaulterrors.ErrEmptySecret
 	}
 
+	if o.StdinIsPiped {
+		o.warnNameLikeValue(ctx, o.StdioOptions, secret)
+	}
+
+	if err := o.lintSecret(o.StdioOptions, matchingSecrets[0].name, matchingSecrets[0].labels, secret); err != nil {
+		return err
+	}
+
 	return o.UpdateSecretValue(ctx, id, secret)
 }
 
@@ -288,12 +389,12 @@ func (o *UpdateSecretValueOptions) readSecretNonInteractive() ([]byte, error) {
 
 	if o.paste {
 		o.Debugf("reading secret from clipboard")
-		return clipboard.Paste()
+		return pasteSecret(o.stripNewline)
 	}
 
 	if o.StdinIsPiped {
 		o.Debugf("reading non-interactive secret")
-		return io.ReadAll(o.In)
+		return readSecretInput(o.In, o.stripNewline)
 	}
 
 	return nil, nil
@@ -301,8 +402,7 @@ func (o *UpdateSecretValueOptions) readSecretNonInteractive() ([]byte, error) {
 
 func (o *UpdateSecretValueOptions) outputSecret(bs []byte) error {
 	if o.output {
-		o.Printf("%s", bs)
-		return nil
+		return writeSecret(o.Out, bs)
 	}
 
 	if o.copy {
@@ -313,8 +413,8 @@ func (o *UpdateSecretValueOptions) outputSecret(bs []byte) error {
 	return nil
 }
 
-func (o *UpdateSecretValueOptions) promptReadSecure(prompt string, a ...any) ([]byte, error) {
-	return input.PromptReadSecure(o.Out, int(o.In.Fd()), prompt, a...)
+func (o *UpdateSecretValueOptions) promptReadSecure(ctx context.Context, prompt string, a ...any) ([]byte, error) {
+	return input.PromptReadSecure(ctx, o.Prompter(), prompt, a...)
 }
 
 func (o *UpdateSecretValueOptions) UpdateSecretValue(ctx context.Context, id int, secret []byte) error {
@@ -341,12 +441,13 @@ func NewCmdUpdateSecretValue(defaults *DefaultVltOptions) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "secret [glob]",
-		Short: "Update the value of an existing secret",
+		Short: i18n.T("Update the value of an existing secret"),
 		Long: `Update the value of an existing secret.
 
 The update is performed only if exactly one secret matches the provided criteria.
 
-Accepts new value via prompt, clipboard, random generation, or piped input.`,
+Accepts new value via prompt, clipboard, random generation, or piped input.
+Piped input is saved as is, use --strip-newline to remove a single trailing newline.`,
 		Example: `  # Update value using prompt (interactive)
   vlt update secret --id 42
 
@@ -375,6 +476,8 @@ Accepts new value via prompt, clipboard, random generation, or piped input.`,
 	cmd.Flags().BoolVarP(&o.copy, "copy-clipboard", "c", false, "copy the saved secret to the clipboard")
 	cmd.Flags().BoolVarP(&o.paste, "paste-clipboard", "p", false, "read the secret from the clipboard")
 	cmd.Flags().BoolVarP(&o.nonInteractive, "no-interactive", "N", false, "disable interactive prompts")
+	cmd.Flags().BoolVarP(&o.stripNewline, "strip-newline", "", false, "remove a single trailing newline from piped or pasted input")
+	cmd.Flags().BoolVarP(&o.skipLint, "skip-lint", "", false, "save the value even if it fails the lint rules of the config file")
 
 	return cmd
 }
@@ -2,9 +2,12 @@ package cli
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultcontainer"
 
 	"github.com/spf13/cobra"
 )
@@ -12,6 +15,8 @@ import (
 type VacuumOptions struct {
 	*genericclioptions.StdioOptions
 	*VaultOptions
+
+	dryRun bool
 }
 
 var _ genericclioptions.CmdOptions = &VacuumOptions{}
@@ -29,22 +34,84 @@ func (*VacuumOptions) Complete() error { return nil }
 func (*VacuumOptions) Validate() error { return nil }
 
 func (o *VacuumOptions) Run(ctx context.Context, _ ...string) error {
+	before, err := o.vault.ContainerPageStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := o.vault.HistorySnapshots(ctx)
+	if err != nil {
+		return err
+	}
+
+	if o.dryRun {
+		o.reportDryRun(before, snapshots)
+		return nil
+	}
+
 	o.Debugf("vacuuming vault\n")
 
 	if err := o.vault.Vacuum(ctx); err != nil {
 		return err
 	}
 
-	f := func() error {
-		o.Debugf("vacuuming vault container\n")
-		return o.vault.VacuumContainer(ctx)
+	pruned, err := o.vault.PruneHistory(ctx, o.maxHistorySnapshots)
+	if err != nil {
+		return err
+	}
+
+	o.Debugf("vacuuming vault container\n")
+
+	if err := o.vault.VacuumContainer(ctx); err != nil {
+		return err
+	}
+
+	o.containerWritten = true
+
+	after, err := o.vault.ContainerPageStats(ctx)
+	if err != nil {
+		return err
 	}
 
-	o.vault.RegisterCleanup(f)
+	o.Printf("history snapshots: %d -> %d (%d pruned)\n", len(snapshots), len(snapshots)-int(pruned), pruned)
+	o.Printf("free pages: %d of %d -> %d of %d (%d bytes per page)\n", before.FreePages, before.PageCount, after.FreePages, after.PageCount, after.PageSize)
+	o.Printf("container size: %s -> %s (%s reclaimed)\n", formatSize(before.Size()), formatSize(after.Size()), formatSize(before.Size()-after.Size()))
 
 	return nil
 }
 
+// reportDryRun prints the space that vacuuming would reclaim: the free pages of
+// the vault container, and the history snapshots beyond the configured limit.
+func (o *VacuumOptions) reportDryRun(stats vaultcontainer.PageStats, snapshots []vaultcontainer.SnapshotInfo) {
+	excess := snapshots[:max(len(snapshots)-o.maxHistorySnapshots, 0)]
+
+	var excessSize int64
+	for _, s := range excess {
+		excessSize += s.Size
+	}
+
+	o.Printf("history snapshots: %d (%d to prune, %s)\n", len(snapshots), len(excess), formatSize(excessSize))
+	o.Printf("free pages: %d of %d (%d bytes per page)\n", stats.FreePages, stats.PageCount, stats.PageSize)
+	o.Printf("container size: %s (%s reclaimable)\n", formatSize(stats.Size()), formatSize(stats.Reclaimable()+excessSize))
+}
+
+// formatSize formats n bytes using binary units, e.g., 1.5 KiB.
+func formatSize(n int64) string {
+	const unit = 1 << 10
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 // NewCmdVacuum creates the vacuum cobra command.
 func NewCmdVacuum(defaults *DefaultVltOptions) *cobra.Command {
 	o := NewVacuumOptions(
@@ -54,15 +121,21 @@ func NewCmdVacuum(defaults *DefaultVltOptions) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "vacuum",
-		Short: "Reclaim unused space in the database",
+		Short: i18n.T("Reclaim unused space in the database"),
 		Long: `Reclaim unused space in the database.
 
 This is typically unnecessary, as SQLite reuses space internally.  
-However, after deleting large blobs, vacuuming can help shrink the database file.`,
+However, after deleting large blobs, vacuuming can help shrink the database file.
+
+History snapshots beyond 'max_history_snapshots' are pruned before vacuuming.
+The container size, free pages and pruned snapshots are reported before and after,
+use --dry-run to report what would be reclaimed without modifying the vault.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
 		},
 	}
 
+	cmd.Flags().BoolVarP(&o.dryRun, "dry-run", "", false, "report the reclaimable space without vacuuming")
+
 	return cmd
 }
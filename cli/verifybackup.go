@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/input"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/spf13/cobra"
+)
+
+type VerifyBackupError struct {
+	Err error
+}
+
+func (e *VerifyBackupError) Error() string { return "verify-backup: " + e.Err.Error() }
+
+func (e *VerifyBackupError) Unwrap() error { return e.Err }
+
+// VerifyBackupOptions holds data required to run the command.
+type VerifyBackupOptions struct {
+	*genericclioptions.StdioOptions
+}
+
+var _ genericclioptions.CmdOptions = &VerifyBackupOptions{}
+
+// NewVerifyBackupOptions initializes the options struct.
+func NewVerifyBackupOptions(stdio *genericclioptions.StdioOptions) *VerifyBackupOptions {
+	return &VerifyBackupOptions{
+		StdioOptions: stdio,
+	}
+}
+
+func (*VerifyBackupOptions) Complete() error { return nil }
+
+func (o *VerifyBackupOptions) Validate() error {
+	if o.StdinIsPiped {
+		return vaulterrors.ErrNonInteractiveUnsupported
+	}
+
+	return nil
+}
+
+func (o *VerifyBackupOptions) Run(ctx context.Context, args ...string) (retErr error) {
+	defer func() {
+		if retErr != nil {
+			retErr = &VerifyBackupError{retErr}
+			return
+		}
+	}()
+
+	path := args[0]
+
+	sum, size, err := fileSHA256(path)
+	if err != nil {
+		return err
+	}
+
+	password, err := input.PromptReadSecure(ctx, o.Prompter(), "[vlt] Password for backup %q:", path)
+	if err != nil {
+		return fmt.Errorf("prompt password: %v", err)
+	}
+	defer securebytes.Wipe(password)
+
+	if len(password) == 0 {
+		return vaulterrors.ErrEmptyPassword
+	}
+
+	v, err := vault.OpenBackup(ctx, path, password)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = v.Close() }() //nolint:wsl_v5
+
+	report, err := v.Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	o.Printf("backup: %s\n", path)
+	o.Printf("size: %d bytes\n", size)
+	o.Printf("sha256: %s\n", sum)
+	o.Printf("secrets checked: %d\n", report.Secrets)
+	o.Printf("snapshots checked: %d\n", report.Snapshots)
+
+	st := o.Styler(o.Out)
+
+	for _, issue := range report.Issues {
+		o.Printf("%s: %s\n", st.Error(string(issue.Kind)), issue.Detail)
+	}
+
+	if len(report.Issues) > 0 {
+		return fmt.Errorf("%w: %d issue(s) found", vault.ErrBackupVerification, len(report.Issues))
+	}
+
+	o.Infof("backup verified, all %d secret(s) restore successfully\n", report.Secrets)
+
+	return nil
+}
+
+// NewCmdVerifyBackup creates the verify-backup cobra command.
+func NewCmdVerifyBackup(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewVerifyBackupOptions(defaults.StdioOptions)
+
+	return &cobra.Command{
+		Use:   "verify-backup <file>",
+		Short: i18n.T("Verify that a vault backup restores"),
+		Long: `Verify that a vault backup restores, e.g., periodically for backups written by 'vlt backup'.
+
+The backup is unlocked with its password and fully checked, as 'vlt fsck' does:
+the database integrity, the key derivation headers, the checksums of the vault
+and its history snapshots, and that every secret decrypts successfully,
+i.e., its AEAD tag is valid.
+
+The backup file is never modified, a temporary copy is opened instead.
+A verification report is printed, the command fails if any issue is found.`,
+		Example: `  # Verify the most recent backup on a USB drive
+  vlt verify-backup "$(ls /mnt/usb/vlt/*.bak | tail -n 1)"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o, args...))
+		},
+	}
+}
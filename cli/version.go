@@ -2,6 +2,7 @@ package cli
 
 import (
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
 
 	"github.com/spf13/cobra"
 )
@@ -9,7 +10,7 @@ import (
 func newVersionCommand(defaults *DefaultVltOptions) *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "version",
-		Short: "Show version",
+		Short: i18n.T("Show version"),
 		Args:  cobra.NoArgs,
 		Run: func(_ *cobra.Command, _ []string) {
 			defaults.Printf("%s\n", Version)
@@ -0,0 +1,756 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/input"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// wifiLabel is the label of the secrets holding WiFi networks.
+	wifiLabel = "wifi"
+
+	// wifiNamePrefix prefixes the SSID in the secret name of a network.
+	wifiNamePrefix = "wifi/"
+
+	// maxSSIDLen is the maximum length of an SSID in bytes.
+	maxSSIDLen = 32
+)
+
+// WiFi network security types, named as in the WIFI: QR code format.
+const (
+	wifiSecurityWPA  = "WPA"
+	wifiSecurityWEP  = "WEP"
+	wifiSecurityNone = "nopass"
+)
+
+// WiFi configuration formats.
+const (
+	wifiFormatWPASupplicant = "wpa_supplicant"
+	wifiFormatNMCLI         = "nmcli"
+)
+
+type WifiError struct {
+	Err error
+}
+
+func (e *WifiError) Error() string { return "wifi: " + e.Err.Error() }
+
+func (e *WifiError) Unwrap() error { return e.Err }
+
+// wifiNetwork is a WiFi network stored in the vault.
+//
+// The network is a secret labeled [wifiLabel] holding the passphrase,
+// its SSID, security type and visibility are stored as attributes.
+type wifiNetwork struct {
+	id       int
+	ssid     string
+	security string
+	hidden   bool
+}
+
+// wifiNetworks returns the WiFi networks stored in the vault, ordered by SSID.
+func (o *VaultOptions) wifiNetworks(ctx context.Context) ([]wifiNetwork, error) {
+	secrets, err := o.vault.FilterSecrets(ctx, "", "", []string{wifiLabel})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+
+	ids := slices.Collect(maps.Keys(secrets))
+
+	attrs, err := o.vault.SecretsAttributes(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make([]wifiNetwork, 0, len(ids))
+
+	for _, id := range ids {
+		a := attrs[id]
+
+		ssid, ok := a["ssid"]
+		if !ok {
+			continue // labeled by the user, not added by 'vlt wifi add'.
+		}
+
+		networks = append(networks, wifiNetwork{
+			id:       id,
+			ssid:     ssid,
+			security: cmp.Or(a["security"], wifiSecurityWPA),
+			hidden:   a["hidden"] == "true",
+		})
+	}
+
+	slices.SortFunc(networks, func(a, b wifiNetwork) int {
+		return cmp.Or(cmp.Compare(a.ssid, b.ssid), cmp.Compare(a.id, b.id))
+	})
+
+	return networks, nil
+}
+
+// wifiNetwork returns the stored WiFi network with the given SSID.
+func (o *VaultOptions) wifiNetwork(ctx context.Context, ssid string) (wifiNetwork, error) {
+	networks, err := o.wifiNetworks(ctx)
+	if err != nil {
+		return wifiNetwork{}, err
+	}
+
+	i := slices.IndexFunc(networks, func(n wifiNetwork) bool { return n.ssid == ssid })
+	if i < 0 {
+		return wifiNetwork{}, fmt.Errorf("%w: network %q", vaulterrors.ErrSearchNoMatch, ssid)
+	}
+
+	return networks[i], nil
+}
+
+// withWifiPassphrase calls f with the passphrase of the network, empty for open networks.
+// The passphrase is wiped once f returns, see [vault.Vault.WithSecret].
+func (o *VaultOptions) withWifiPassphrase(ctx context.Context, stdio *genericclioptions.StdioOptions, n wifiNetwork, f func(psk []byte) error) error {
+	if n.security == wifiSecurityNone {
+		return f(nil)
+	}
+
+	if err := o.vault.WithSecret(ctx, n.id, f); err != nil {
+		return err
+	}
+
+	o.recordAccess(ctx, stdio, n.id)
+
+	return nil
+}
+
+// parseWifiSecurity returns the security type named by s, case-insensitive.
+func parseWifiSecurity(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "wpa", "wpa2", "wpa3":
+		return wifiSecurityWPA, nil
+	case "wep":
+		return wifiSecurityWEP, nil
+	case "none", "nopass", "open":
+		return wifiSecurityNone, nil
+	default:
+		return "", fmt.Errorf("unsupported security type %q: expected wpa, wep or none", s)
+	}
+}
+
+func validateSSID(ssid string) error {
+	if len(ssid) == 0 {
+		return errors.New("empty SSID")
+	}
+
+	if len(ssid) > maxSSIDLen {
+		return fmt.Errorf("SSID %q is longer than %d bytes", ssid, maxSSIDLen)
+	}
+
+	return nil
+}
+
+// validatePassphrase verifies the passphrase length for the security type.
+func validatePassphrase(security string, psk []byte) error {
+	switch security {
+	case wifiSecurityWPA:
+		if n := len(psk); n < 8 || n > 64 {
+			return errors.New("WPA passphrase must be 8 to 63 characters, or 64 hex digits")
+		}
+	case wifiSecurityWEP:
+		if !slices.Contains([]int{5, 10, 13, 26}, len(psk)) {
+			return errors.New("WEP key must be 5 or 13 characters, or 10 or 26 hex digits")
+		}
+	}
+
+	return nil
+}
+
+// WifiAddOptions holds data required to run the command.
+type WifiAddOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	ssid     string
+	security string // security is the network security type, wpa, wep or none.
+	hidden   bool   // hidden marks the network as not broadcasting its SSID.
+}
+
+var _ genericclioptions.CmdOptions = &WifiAddOptions{}
+
+// NewWifiAddOptions initializes the options struct.
+func NewWifiAddOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *WifiAddOptions {
+	return &WifiAddOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (o *WifiAddOptions) Complete() error {
+	s, err := parseWifiSecurity(o.security)
+	if err != nil {
+		return &WifiError{err}
+	}
+
+	o.security = s
+
+	return nil
+}
+
+func (o *WifiAddOptions) Validate() error {
+	if err := validateSSID(o.ssid); err != nil {
+		return &WifiError{err}
+	}
+
+	return nil
+}
+
+func (o *WifiAddOptions) Run(ctx context.Context, _ ...string) error {
+	if _, err := o.wifiNetwork(ctx, o.ssid); err == nil {
+		return &WifiError{fmt.Errorf("network %q already exists", o.ssid)}
+	} else if !errors.Is(err, vaulterrors.ErrSearchNoMatch) {
+		return &WifiError{err}
+	}
+
+	psk, err := o.readPassphrase(ctx)
+	if err != nil {
+		return &WifiError{err}
+	}
+	defer securebytes.Wipe(psk)
+
+	if err := validatePassphrase(o.security, psk); err != nil {
+		return &WifiError{err}
+	}
+
+	// open networks have no secret value, a placeholder keeps the secret non-empty.
+	value := psk
+	if o.security == wifiSecurityNone {
+		value = []byte(wifiSecurityNone)
+	}
+
+	id, err := o.vault.InsertNewSecret(ctx, wifiNamePrefix+o.ssid, value, []string{wifiLabel})
+	if err != nil {
+		return &WifiError{err}
+	}
+
+	attrs := map[string]string{
+		"ssid":     o.ssid,
+		"security": o.security,
+	}
+
+	if o.hidden {
+		attrs["hidden"] = "true"
+	}
+
+	if err := o.vault.UpdateSecretAttributes(ctx, id, attrs, nil); err != nil {
+		return &WifiError{err}
+	}
+
+	o.persistRequired = true
+
+	o.Infof("network %q saved\n", o.ssid)
+
+	return nil
+}
+
+// readPassphrase reads the network passphrase from piped input,
+// or prompts for it. The trailing newline of piped input is dropped.
+func (o *WifiAddOptions) readPassphrase(ctx context.Context) ([]byte, error) {
+	if o.security == wifiSecurityNone {
+		return nil, nil
+	}
+
+	if o.StdinIsPiped {
+		b, err := io.ReadAll(o.In)
+		if err != nil {
+			return nil, err
+		}
+
+		return bytes.TrimRight(b, "\r\n"), nil
+	}
+
+	return input.PromptReadSecure(ctx, o.Prompter(), "Passphrase for network %q: ", o.ssid)
+}
+
+// WifiListOptions holds data required to run the command.
+type WifiListOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+}
+
+var _ genericclioptions.CmdOptions = &WifiListOptions{}
+
+// NewWifiListOptions initializes the options struct.
+func NewWifiListOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *WifiListOptions {
+	return &WifiListOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*WifiListOptions) Complete() error { return nil }
+
+func (*WifiListOptions) Validate() error { return nil }
+
+func (o *WifiListOptions) Run(ctx context.Context, _ ...string) error {
+	networks, err := o.wifiNetworks(ctx)
+	if err != nil {
+		return &WifiError{err}
+	}
+
+	if len(networks) == 0 {
+		o.Infof("no networks, add one using 'vlt wifi add'\n")
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	tw := tabwriter.NewWriter(&buf, 0, 0, 5, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tSSID\tSECURITY\tHIDDEN")
+
+	for _, n := range networks {
+		ssid := n.ssid
+		if o.discreet {
+			ssid = maskedName
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%t\n", n.id, ssid, n.security, n.hidden)
+	}
+
+	_ = tw.Flush()
+
+	// the header is styled once aligned,
+	// escape sequences would otherwise count towards the column widths.
+	header, rows, _ := strings.Cut(buf.String(), "\n")
+
+	_, err = fmt.Fprintf(o.Out, "%s\n%s", o.Styler(o.Out).Header(header), rows)
+
+	return err
+}
+
+// WifiConfigOptions holds data required to run the command.
+type WifiConfigOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	ssid   string
+	format string // format is the configuration format, wpa_supplicant or nmcli.
+	qr     bool   // qr prints the network as a WIFI: QR code instead.
+}
+
+var _ genericclioptions.CmdOptions = &WifiConfigOptions{}
+
+// NewWifiConfigOptions initializes the options struct.
+func NewWifiConfigOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *WifiConfigOptions {
+	return &WifiConfigOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*WifiConfigOptions) Complete() error { return nil }
+
+func (o *WifiConfigOptions) Validate() error {
+	if o.format != wifiFormatWPASupplicant && o.format != wifiFormatNMCLI {
+		return &WifiError{fmt.Errorf("unsupported format %q: expected %s or %s", o.format, wifiFormatWPASupplicant, wifiFormatNMCLI)}
+	}
+
+	return nil
+}
+
+func (o *WifiConfigOptions) Run(ctx context.Context, _ ...string) error {
+	n, err := o.wifiNetwork(ctx, o.ssid)
+	if err != nil {
+		return &WifiError{err}
+	}
+
+	err = o.withWifiPassphrase(ctx, o.StdioOptions, n, func(psk []byte) error {
+		return o.output(n, psk)
+	})
+	if err != nil {
+		return &WifiError{err}
+	}
+
+	return nil
+}
+
+// output writes the network configuration, or its QR code if --qr is set.
+func (o *WifiConfigOptions) output(n wifiNetwork, psk []byte) error {
+	if o.qr {
+		code, err := qrcode.New(wifiQRPayload(n, psk), qrcode.Medium)
+		if err != nil {
+			return fmt.Errorf("qr code: %w", err)
+		}
+
+		_, err = fmt.Fprint(o.Out, code.ToSmallString(false))
+
+		return err
+	}
+
+	var conf string
+
+	switch o.format {
+	case wifiFormatNMCLI:
+		conf = nmKeyfile(n, psk)
+	default:
+		conf = wpaSupplicantConf(n, psk)
+	}
+
+	_, err := fmt.Fprint(o.Out, conf)
+
+	return err
+}
+
+// wifiQRPayload returns the network in the WIFI: QR code format,
+// as read by the camera apps of Android and iOS.
+func wifiQRPayload(n wifiNetwork, psk []byte) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "WIFI:T:%s;S:%s;", n.security, escapeQRField(n.ssid))
+
+	if n.security != wifiSecurityNone {
+		fmt.Fprintf(&sb, "P:%s;", escapeQRField(string(psk)))
+	}
+
+	if n.hidden {
+		sb.WriteString("H:true;")
+	}
+
+	sb.WriteString(";")
+
+	return sb.String()
+}
+
+var qrFieldEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `"`, `\"`, `:`, `\:`)
+
+// escapeQRField escapes the special characters of a WIFI: QR code field.
+func escapeQRField(s string) string {
+	return qrFieldEscaper.Replace(s)
+}
+
+// wpaSupplicantConf returns the wpa_supplicant.conf network block of the network.
+func wpaSupplicantConf(n wifiNetwork, psk []byte) string {
+	var sb strings.Builder
+
+	sb.WriteString("network={\n")
+	fmt.Fprintf(&sb, "\tssid=%s\n", wpaString(n.ssid))
+
+	if n.hidden {
+		sb.WriteString("\tscan_ssid=1\n")
+	}
+
+	switch n.security {
+	case wifiSecurityWPA:
+		if isHexKey(psk, 64) {
+			fmt.Fprintf(&sb, "\tpsk=%s\n", psk) // a raw 256-bit key is written unquoted.
+		} else {
+			fmt.Fprintf(&sb, "\tpsk=%s\n", wpaString(string(psk)))
+		}
+	case wifiSecurityWEP:
+		sb.WriteString("\tkey_mgmt=NONE\n")
+
+		if isHexKey(psk, 10) || isHexKey(psk, 26) {
+			fmt.Fprintf(&sb, "\twep_key0=%s\n", psk)
+		} else {
+			fmt.Fprintf(&sb, "\twep_key0=%s\n", wpaString(string(psk)))
+		}
+
+		sb.WriteString("\twep_tx_keyidx=0\n")
+	default:
+		sb.WriteString("\tkey_mgmt=NONE\n")
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// wpaString returns s as a wpa_supplicant string, quoted if printable
+// and hex encoded otherwise, quotes cannot be escaped within quoted strings.
+func wpaString(s string) string {
+	printable := !strings.ContainsFunc(s, func(r rune) bool {
+		return r == '"' || r > unicode.MaxASCII || !unicode.IsPrint(r)
+	})
+
+	if printable {
+		return `"` + s + `"`
+	}
+
+	return hex.EncodeToString([]byte(s))
+}
+
+// nmKeyfile returns the NetworkManager keyfile of the network,
+// to be saved in /etc/NetworkManager/system-connections with mode 0600.
+func nmKeyfile(n wifiNetwork, psk []byte) string {
+	var sb strings.Builder
+
+	sb.WriteString("[connection]\n")
+	fmt.Fprintf(&sb, "id=%s\n", n.ssid)
+	sb.WriteString("type=wifi\n\n")
+
+	sb.WriteString("[wifi]\n")
+	sb.WriteString("mode=infrastructure\n")
+	fmt.Fprintf(&sb, "ssid=%s\n", nmSSID(n.ssid))
+
+	if n.hidden {
+		sb.WriteString("hidden=true\n")
+	}
+
+	switch n.security {
+	case wifiSecurityWPA:
+		sb.WriteString("\n[wifi-security]\n")
+		sb.WriteString("key-mgmt=wpa-psk\n")
+		fmt.Fprintf(&sb, "psk=%s\n", psk)
+	case wifiSecurityWEP:
+		sb.WriteString("\n[wifi-security]\n")
+		sb.WriteString("key-mgmt=none\n")
+		sb.WriteString("wep-key-type=1\n")
+		fmt.Fprintf(&sb, "wep-key0=%s\n", psk)
+	}
+
+	sb.WriteString("\n[ipv4]\nmethod=auto\n\n[ipv6]\nmethod=auto\n")
+
+	return sb.String()
+}
+
+// nmSSID returns the SSID as a keyfile value, SSIDs that are not plain
+// printable text are written as a semicolon separated byte list.
+func nmSSID(ssid string) string {
+	plain := !strings.ContainsFunc(ssid, func(r rune) bool {
+		return r == ';' || r == '\\' || r > unicode.MaxASCII || !unicode.IsPrint(r)
+	})
+
+	if plain {
+		return ssid
+	}
+
+	var sb strings.Builder
+	for _, b := range []byte(ssid) {
+		fmt.Fprintf(&sb, "%d;", b)
+	}
+
+	return sb.String()
+}
+
+// isHexKey reports whether k consists of exactly n hex digits.
+func isHexKey(k []byte, n int) bool {
+	if len(k) != n {
+		return false
+	}
+
+	_, err := hex.DecodeString(string(k))
+
+	return err == nil
+}
+
+// WifiJoinOptions holds data required to run the command.
+type WifiJoinOptions struct {
+	*genericclioptions.StdioOptions
+	*VaultOptions
+
+	ssid string
+}
+
+var _ genericclioptions.CmdOptions = &WifiJoinOptions{}
+
+// NewWifiJoinOptions initializes the options struct.
+func NewWifiJoinOptions(stdio *genericclioptions.StdioOptions, vaultOptions *VaultOptions) *WifiJoinOptions {
+	return &WifiJoinOptions{
+		StdioOptions: stdio,
+		VaultOptions: vaultOptions,
+	}
+}
+
+func (*WifiJoinOptions) Complete() error { return nil }
+
+func (*WifiJoinOptions) Validate() error { return nil }
+
+func (o *WifiJoinOptions) Run(ctx context.Context, _ ...string) error {
+	nmcli, err := exec.LookPath("nmcli")
+	if err != nil {
+		return &WifiError{fmt.Errorf("nmcli not found, use 'vlt wifi show' to configure the network manually: %w", err)}
+	}
+
+	n, err := o.wifiNetwork(ctx, o.ssid)
+	if err != nil {
+		return &WifiError{err}
+	}
+
+	err = o.withWifiPassphrase(ctx, o.StdioOptions, n, func(psk []byte) error {
+		return o.connect(ctx, nmcli, n, psk)
+	})
+	if err != nil {
+		return &WifiError{err}
+	}
+
+	return nil
+}
+
+// connect connects to the network using nmcli.
+func (o *WifiJoinOptions) connect(ctx context.Context, nmcli string, n wifiNetwork, psk []byte) error {
+	// the passphrase is answered to the --ask prompt over stdin,
+	// it would otherwise be visible to other users in the process list.
+	args := []string{"--ask", "device", "wifi", "connect", n.ssid}
+	if n.hidden {
+		args = append(args, "hidden", "yes")
+	}
+
+	stdin := make([]byte, 0, len(psk)+1)
+	stdin = append(append(stdin, psk...), '\n')
+
+	defer securebytes.Wipe(stdin)
+
+	cmd := exec.CommandContext(ctx, nmcli, args...) //nolint:gosec // the arguments are not shell interpreted
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = o.Out
+	cmd.Stderr = o.ErrOut
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("nmcli: %w", err)
+	}
+
+	return nil
+}
+
+// NewCmdWifi creates the wifi cobra command.
+func NewCmdWifi(defaults *DefaultVltOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wifi",
+		Short: i18n.T("Store WiFi networks and join them (subcommands available)"),
+		Long: `Store WiFi networks and join them.
+
+A network is saved as a secret named 'wifi/<ssid>' with the 'wifi' label,
+holding the passphrase, its SSID, security type and visibility are stored
+as encrypted attributes.
+
+The stored networks can be exported as a wpa_supplicant network block,
+a NetworkManager keyfile, or a WIFI: QR code to be scanned by a phone,
+or joined directly using nmcli.`,
+		Example: `  # Save a WPA network, prompting for the passphrase
+  vlt wifi add home
+
+  # Save a hidden network, reading the passphrase from stdin
+  vlt wifi add office --hidden < passphrase.txt
+
+  # Print the wpa_supplicant configuration of a network
+  vlt wifi show home >> /etc/wpa_supplicant/wpa_supplicant.conf
+
+  # Show a QR code for a guest to scan
+  vlt wifi show home --qr
+
+  # Join a network using nmcli
+  vlt wifi join home`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(NewCmdWifiAdd(defaults))
+	cmd.AddCommand(NewCmdWifiList(defaults))
+	cmd.AddCommand(NewCmdWifiConfig(defaults))
+	cmd.AddCommand(NewCmdWifiJoin(defaults))
+
+	return cmd
+}
+
+// NewCmdWifiAdd creates the wifi add cobra command.
+func NewCmdWifiAdd(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewWifiAddOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "add ssid",
+		Short: i18n.T("Save a WiFi network"),
+		Long: `Save a WiFi network.
+
+The passphrase is prompted for, or read from piped or redirected input.
+Open networks (--security none) have no passphrase.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.ssid = args[0]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.security, "security", "", "wpa", "network security type: wpa, wep or none")
+	cmd.Flags().BoolVarP(&o.hidden, "hidden", "", false, "the network does not broadcast its SSID")
+
+	return cmd
+}
+
+// NewCmdWifiList creates the wifi list cobra command.
+func NewCmdWifiList(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewWifiListOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	return &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("List the saved WiFi networks"),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+}
+
+// NewCmdWifiConfig creates the wifi show cobra command.
+func NewCmdWifiConfig(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewWifiConfigOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	cmd := &cobra.Command{
+		Use:   "show ssid",
+		Short: i18n.T("Print the configuration of a saved WiFi network"),
+		Long: `Print the configuration of a saved WiFi network, including its passphrase.
+
+Formats:
+	wpa_supplicant  a network block for wpa_supplicant.conf
+	nmcli           a NetworkManager keyfile, for /etc/NetworkManager/system-connections
+
+With --qr, the network is printed as a WIFI: QR code instead,
+which the camera apps of Android and iOS offer to join.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.ssid = args[0]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.format, "format", "", wifiFormatWPASupplicant, "configuration format: wpa_supplicant or nmcli")
+	cmd.Flags().BoolVarP(&o.qr, "qr", "", false, "print the network as a WIFI: QR code")
+
+	return cmd
+}
+
+// NewCmdWifiJoin creates the wifi join cobra command.
+func NewCmdWifiJoin(defaults *DefaultVltOptions) *cobra.Command {
+	o := NewWifiJoinOptions(defaults.StdioOptions, defaults.vaultOptions)
+
+	return &cobra.Command{
+		Use:   "join ssid",
+		Short: i18n.T("Join a saved WiFi network using nmcli"),
+		Long: `Join a saved WiFi network using NetworkManager's nmcli.
+
+The passphrase is passed to nmcli over stdin, not on its command line.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.ssid = args[0]
+			return clierror.Check(genericclioptions.ExecuteCommand(cmd.Context(), o))
+		},
+	}
+}
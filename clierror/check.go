@@ -1,18 +1,53 @@
 package clierror
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/redact"
+	"github.com/ladzaretti/vlt-cli/style"
+	"github.com/ladzaretti/vlt-cli/vault"
 	"github.com/ladzaretti/vlt-cli/vaultdaemon"
 	"github.com/ladzaretti/vlt-cli/vaulterrors"
 )
 
+// Exit codes of the failure modes scripts may branch on,
+// see [ExitCode]. Other failures exit with [DefaultErrorExitCode].
 const (
 	DefaultErrorExitCode = 1
+
+	// UsageExitCode reports invalid command line usage, e.g., an unknown flag.
+	UsageExitCode = 2
+
+	// NotFoundExitCode reports that no secret matches the search.
+	NotFoundExitCode = 3
+
+	// AmbiguousExitCode reports that multiple secrets match a search expecting one.
+	AmbiguousExitCode = 4
+
+	// AuthFailedExitCode reports a wrong password or identity, or a denied session use.
+	AuthFailedExitCode = 5
+
+	// LockedExitCode reports that the vault is locked and interactive login is disabled.
+	LockedExitCode = 6
+
+	// ThrottledExitCode reports that unlocking is delayed after repeated failures.
+	ThrottledExitCode = 7
+
+	// VaultNotFoundExitCode reports that the vault file does not exist.
+	VaultNotFoundExitCode = 8
+
+	// TimeoutExitCode reports that the command timed out, as timeout(1) does.
+	TimeoutExitCode = 124
+
+	// InterruptedExitCode is the conventional exit code of a process
+	// terminated by SIGINT (128 + 2).
+	InterruptedExitCode = 130
 )
 
 var (
@@ -27,6 +62,9 @@ var (
 
 	// debugMode enables always printing raw error values.
 	debugMode bool
+
+	// styler styles the summary line of error messages.
+	styler *style.Styler
 )
 
 // SetErrorHandler overrides the default [FatalErrHandler] error handler.
@@ -54,6 +92,12 @@ func SetDefaultFprintf(f func(w io.Writer, format string, a ...any) (n int, err
 	fprintf = f
 }
 
+// SetStyler sets the styler used for the summary line of error messages.
+// A nil styler prints messages unstyled.
+func SetStyler(s *style.Styler) {
+	styler = s
+}
+
 // DebugMode sets whether debug logging is enabled.
 //
 // When enabled, raw error values are printed to stderr.
@@ -65,6 +109,9 @@ func DebugMode(enabled bool) {
 func FatalErrHandler(msg string, code int) {
 	printError(msg)
 
+	// deferred calls, e.g., of the post-run, are skipped by os.Exit.
+	redact.Reset()
+
 	//nolint:revive // Intentional exit after fatal error.
 	os.Exit(code)
 }
@@ -83,6 +130,10 @@ func printError(msg string) {
 		msg += "\n"
 	}
 
+	// style the summary only, hints in the following lines are kept plain.
+	summary, details, _ := strings.Cut(msg, "\n")
+	msg = styler.Error(summary) + "\n" + details
+
 	_, _ = fprintf(errWriter, msg)
 }
 
@@ -94,6 +145,15 @@ func debugPrint(err error) {
 	_, _ = fprintf(errWriter, "DEBUG %+v\n", err)
 }
 
+// ExitStatusError reports the exit status of an external command, e.g., a plugin,
+// that reported its failure itself. It is checked by exiting with the same
+// status, without printing a message.
+type ExitStatusError struct {
+	Code int
+}
+
+func (e *ExitStatusError) Error() string { return fmt.Sprintf("exit status %d", e.Code) }
+
 // ErrExit may be passed to CheckError to instruct it to output nothing but exit with
 // status code 1.
 var ErrExit = errors.New("exit")
@@ -114,21 +174,44 @@ func check(err error, handleErr func(string, int)) {
 
 	debugPrint(err)
 
+	code := ExitCode(err)
+
+	var (
+		statusErr *ExitStatusError
+		formatErr *vault.FormatError
+	)
+
 	switch {
-	case errors.Is(err, ErrExit):
-		handleErr("", DefaultErrorExitCode)
+	case errors.Is(err, ErrExit), errors.As(err, &statusErr):
+		handleErr("", code)
 	case errors.Is(err, vaulterrors.ErrVaultFileExists):
-		handleErr("vlt: vault file already exists\nConsider deleting the file first before running 'create' to create a new vault at the specified path.", DefaultErrorExitCode)
+		handleErr(i18n.T("vlt: vault file already exists\nConsider deleting the file first before running 'create' to create a new vault at the specified path."), code)
 	case errors.Is(err, vaulterrors.ErrVaultFileNotFound):
-		handleErr("vlt: "+err.Error()+"\nUse the `create` command to create a new vault file.", DefaultErrorExitCode)
+		handleErr("vlt: "+err.Error()+"\n"+i18n.T("Use the `create` command to create a new vault file."), code)
 	case errors.Is(err, vaulterrors.ErrWrongPassword):
-		handleErr("vlt: incorrect password\nPlease check your password and try again.", DefaultErrorExitCode)
+		handleErr(i18n.T("vlt: incorrect password\nPlease check your password and try again."), code)
+	case errors.Is(err, vaulterrors.ErrUnlockThrottled):
+		handleErr("vlt: "+err.Error()+"\n"+i18n.T("The delay doubles with each further failed attempt and is cleared by a successful unlock."), code)
 	case errors.Is(err, vaulterrors.ErrNonInteractiveUnsupported):
-		handleErr("vlt: this command supports interactive input only.", DefaultErrorExitCode)
+		handleErr(i18n.T("vlt: this command supports interactive input only."), code)
 	case errors.Is(err, vaulterrors.ErrInteractiveLoginDisabled):
-		handleErr("vlt: no login session available and interactive login is disabled\nuse 'vlt login' or remove --no-login-prompt to continue", DefaultErrorExitCode)
+		handleErr(i18n.T("vlt: no login session available and interactive login is disabled\nuse 'vlt login' or remove --no-login-prompt to continue"), code)
+	case errors.Is(err, vaulterrors.ErrInsecureVaultPath):
+		handleErr("vlt: "+err.Error()+"\n"+i18n.T("Restrict the permissions (e.g., 'chmod 600' the vault file) or use --insecure-path-ok to proceed anyway."), code)
+	case errors.As(err, &formatErr):
+		handleErr("vlt: "+formatErr.Error()+"\n"+i18n.T("Update vlt to open this vault, e.g., using 'vlt self-update'."), code)
+	case errors.Is(err, vaulterrors.ErrVaultInconsistent):
+		handleErr("vlt: "+err.Error()+"\n"+i18n.T("Run 'vlt fsck --repair' to fix repairable issues."), code)
+	case errors.Is(err, context.DeadlineExceeded):
+		handleErr(i18n.T("vlt: command timed out\nIncrease 'command_timeout' in the configuration file to allow longer operations."), code)
+	case errors.Is(err, context.Canceled):
+		handleErr(i18n.T("vlt: operation canceled"), code)
+	case errors.Is(err, vaultdaemon.ErrSessionDenied):
+		handleErr("vlt: "+err.Error()+"\n"+i18n.T("The session use was not confirmed; confirm the prompt or use 'vlt logout' to unlock with the password."), code)
+	case errors.Is(err, vaultdaemon.ErrIncompatibleDaemon):
+		handleErr("vlt: "+err.Error()+"\n"+i18n.T("Restart vltd after upgrading vlt (e.g., 'systemctl --user restart vltd')."), code)
 	case errors.Is(err, vaultdaemon.ErrSocketUnavailable):
-		handleErr("vlt: vault daemon is not running\nStart `vltd` to enable session support", DefaultErrorExitCode)
+		handleErr(i18n.T("vlt: vault daemon is not running\nStart `vltd` to enable session support"), code)
 	default:
 		msg, ok := StandardErrorMessage(err)
 		if !ok {
@@ -138,7 +221,41 @@ func check(err error, handleErr func(string, int)) {
 			}
 		}
 
-		handleErr(msg, DefaultErrorExitCode)
+		handleErr(msg, code)
+	}
+}
+
+// ExitCode returns the exit code of the failure mode of err,
+// [DefaultErrorExitCode] if it has no dedicated code.
+func ExitCode(err error) int {
+	var statusErr *ExitStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, vaulterrors.ErrSearchNoMatch):
+		return NotFoundExitCode
+	case errors.Is(err, vaulterrors.ErrAmbiguousSecretMatch):
+		return AmbiguousExitCode
+	case errors.Is(err, vaulterrors.ErrWrongPassword),
+		errors.Is(err, vault.ErrAuthenticationFailed),
+		errors.Is(err, vaultdaemon.ErrSessionDenied):
+		return AuthFailedExitCode
+	case errors.Is(err, vaulterrors.ErrInteractiveLoginDisabled):
+		return LockedExitCode
+	case errors.Is(err, vaulterrors.ErrUnlockThrottled):
+		return ThrottledExitCode
+	case errors.Is(err, vaulterrors.ErrVaultFileNotFound):
+		return VaultNotFoundExitCode
+	case errors.Is(err, context.DeadlineExceeded):
+		return TimeoutExitCode
+	case errors.Is(err, context.Canceled):
+		return InterruptedExitCode
+	default:
+		return DefaultErrorExitCode
 	}
 }
 
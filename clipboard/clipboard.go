@@ -9,11 +9,6 @@ import (
 	"os/exec"
 )
 
-var (
-	defaultCopy  = []string{"wl-copy"}
-	defaultPaste = []string{"wl-paste", "--no-newline"}
-)
-
 // ConfigurationError indicates that a clipboard command is not available
 // or misconfigured on the host system.
 type ConfigurationError struct {
@@ -43,8 +38,17 @@ func SetDefault(c *Clipboard) {
 
 // Copy writes the given string to the system clipboard
 // using the default command.
+//
+// The copied content is marked as owned by vlt, see [ClearIfOwned].
+// Marking is best-effort and does not fail the copy.
 func Copy(bs []byte) error {
-	return clipboard.Copy(bs)
+	if err := clipboard.Copy(bs); err != nil {
+		return err
+	}
+
+	_ = markOwned(bs)
+
+	return nil
 }
 
 // Paste reads and returns the current contents of the system clipboard
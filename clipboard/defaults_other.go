@@ -0,0 +1,22 @@
+//go:build !windows
+
+package clipboard
+
+import (
+	"fmt"
+	"os"
+)
+
+var (
+	defaultCopy  = []string{"wl-copy"}
+	defaultPaste = []string{"wl-paste", "--no-newline"}
+)
+
+// runtimeDir returns the user runtime directory.
+func runtimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); len(dir) > 0 {
+		return dir
+	}
+
+	return fmt.Sprintf("/run/user/%d", os.Getuid())
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package clipboard
+
+import "os"
+
+// clip.exe copies its input, PowerShell reads the clipboard back;
+// both ship with Windows.
+var (
+	defaultCopy  = []string{"clip.exe"}
+	defaultPaste = []string{
+		"powershell.exe", "-NoProfile", "-NonInteractive", "-Command",
+		"[Console]::OutputEncoding = [Text.Encoding]::UTF8; Get-Clipboard -Raw",
+	}
+)
+
+// runtimeDir returns the user runtime directory,
+// the per-user temporary directory unless XDG_RUNTIME_DIR is set.
+func runtimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); len(dir) > 0 {
+		return dir
+	}
+
+	return os.TempDir()
+}
@@ -0,0 +1,101 @@
+package clipboard
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// markerName is the file name of the ownership marker.
+	markerName = "vlt-clipboard"
+
+	// markerPerm is the file permission mode of the ownership marker.
+	markerPerm = 0o600
+
+	markerSaltSize = 16
+)
+
+// markerPath returns the path of the ownership marker,
+// located in the user runtime directory.
+func markerPath() string {
+	return filepath.Join(runtimeDir(), markerName)
+}
+
+// markOwned records that the clipboard holds content copied by vlt.
+//
+// Only a salted digest of the content is stored, so the marker can later
+// tell whether the clipboard still holds it without keeping the content itself.
+func markOwned(bs []byte) error {
+	salt := make([]byte, markerSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	return os.WriteFile(markerPath(), append(salt, digest(salt, bs)...), markerPerm)
+}
+
+func digest(salt []byte, bs []byte) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write(bs)
+
+	return h.Sum(nil)
+}
+
+// ClearIfOwned clears the clipboard using the default commands
+// if it still holds the content last copied by vlt.
+//
+// It reports whether the clipboard was cleared.
+func ClearIfOwned() (cleared bool, _ error) {
+	return clipboard.ClearIfOwned()
+}
+
+// ClearIfOwned clears the clipboard if it still holds the content last copied by vlt,
+// see [Copy].
+//
+// It reports whether the clipboard was cleared.
+func (c *Clipboard) ClearIfOwned() (cleared bool, _ error) {
+	path := markerPath()
+
+	marker, err := os.ReadFile(path) //nolint:gosec // path is derived from the runtime dir.
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	// the marker is consumed either way; a replaced clipboard is no longer vlt's.
+	defer func() { _ = os.Remove(path) }()
+
+	if len(marker) != markerSaltSize+sha256.Size {
+		return false, nil
+	}
+
+	current, err := c.Paste()
+	if err != nil {
+		return false, err
+	}
+
+	salt, want := marker[:markerSaltSize], marker[markerSaltSize:]
+
+	// some paste commands append a trailing newline, PowerShell a CRLF.
+	owned := bytes.Equal(digest(salt, current), want) ||
+		bytes.Equal(digest(salt, bytes.TrimSuffix(current, []byte("\n"))), want) ||
+		bytes.Equal(digest(salt, bytes.TrimSuffix(current, []byte("\r\n"))), want)
+	if !owned {
+		return false, nil
+	}
+
+	if err := c.Copy(nil); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
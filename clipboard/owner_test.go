@@ -0,0 +1,90 @@
+package clipboard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClearIfOwned(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	content := filepath.Join(t.TempDir(), "clipboard")
+	if err := os.WriteFile(content, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := clipboard
+	t.Cleanup(func() { clipboard = orig })
+
+	SetDefault(New(
+		WithCopyCmd([]string{"sh", "-c", "cat > " + content}),
+		WithPasteCmd([]string{"cat", content}),
+	))
+
+	readClipboard := func() string {
+		bs, err := os.ReadFile(content) //nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(bs)
+	}
+
+	t.Run("Owned", func(t *testing.T) {
+		if err := Copy([]byte("secret")); err != nil {
+			t.Fatalf("copy: %v", err)
+		}
+
+		cleared, err := ClearIfOwned()
+		if err != nil || !cleared {
+			t.Fatalf("want cleared, got %v, err %v", cleared, err)
+		}
+
+		if got := readClipboard(); got != "" {
+			t.Errorf("want empty clipboard, got %q", got)
+		}
+	})
+
+	t.Run("OwnedTrailingCRLF", func(t *testing.T) {
+		if err := Copy([]byte("secret")); err != nil {
+			t.Fatalf("copy: %v", err)
+		}
+
+		// e.g., PowerShell Get-Clipboard on Windows.
+		if err := os.WriteFile(content, []byte("secret\r\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cleared, err := ClearIfOwned()
+		if err != nil || !cleared {
+			t.Fatalf("want cleared, got %v, err %v", cleared, err)
+		}
+	})
+
+	t.Run("Replaced", func(t *testing.T) {
+		if err := Copy([]byte("secret")); err != nil {
+			t.Fatalf("copy: %v", err)
+		}
+
+		if err := os.WriteFile(content, []byte("copied elsewhere"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cleared, err := ClearIfOwned()
+		if err != nil || cleared {
+			t.Fatalf("want not cleared, got %v, err %v", cleared, err)
+		}
+
+		if got := readClipboard(); got != "copied elsewhere" {
+			t.Errorf("want untouched clipboard, got %q", got)
+		}
+	})
+
+	t.Run("NoMarker", func(t *testing.T) {
+		cleared, err := ClearIfOwned()
+		if err != nil || cleared {
+			t.Fatalf("want not cleared, got %v, err %v", cleared, err)
+		}
+	})
+}
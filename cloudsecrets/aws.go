@@ -0,0 +1,70 @@
+package cloudsecrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+const awsName = "aws"
+
+// awsNotFound is the error code of a missing AWS secret.
+const awsNotFound = "ResourceNotFoundException"
+
+// AWS is AWS Secrets Manager, using the aws command.
+//
+// Secrets are referenced by their name or ARN. Text values are stored
+// as the secret string, other values as the secret binary.
+type AWS struct{}
+
+var _ Provider = &AWS{}
+
+func (*AWS) Name() string { return awsName }
+
+func (*AWS) Get(ctx context.Context, ref string) ([]byte, error) {
+	out, err := run(ctx, nil, "aws", "secretsmanager", "get-secret-value", "--secret-id", ref, "--output", "json")
+	if notFound(err, awsNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var v struct {
+		SecretString *string
+		SecretBinary []byte // base64 encoded in JSON
+	}
+
+	if err := json.Unmarshal(out, &v); err != nil {
+		return nil, fmt.Errorf("aws: parse secret value: %w", err)
+	}
+
+	if v.SecretString != nil {
+		return []byte(*v.SecretString), nil
+	}
+
+	if v.SecretBinary == nil {
+		return nil, errors.New("aws: secret value has neither a string nor a binary")
+	}
+
+	return v.SecretBinary, nil
+}
+
+func (*AWS) Put(ctx context.Context, ref string, value []byte) error {
+	valueArgs := []string{"--secret-string", "file:///dev/stdin"}
+	if !utf8.Valid(value) {
+		valueArgs = []string{"--secret-binary", "fileb:///dev/stdin"}
+	}
+
+	_, err := run(ctx, value, "aws", append([]string{"secretsmanager", "put-secret-value", "--secret-id", ref, "--output", "json"}, valueArgs...)...)
+	if !notFound(err, awsNotFound) {
+		return err
+	}
+
+	_, err = run(ctx, value, "aws", append([]string{"secretsmanager", "create-secret", "--name", ref, "--output", "json"}, valueArgs...)...)
+
+	return err
+}
@@ -0,0 +1,76 @@
+package cloudsecrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const azureName = "azure"
+
+// azureNotFound is the error code of a missing Azure Key Vault secret.
+const azureNotFound = "SecretNotFound"
+
+// Azure is Azure Key Vault, using the az command.
+//
+// Secrets are referenced by the name of the key vault and the name
+// of the secret, i.e., <vault>/<name>. Only text values are supported.
+type Azure struct{}
+
+var _ Provider = &Azure{}
+
+func (*Azure) Name() string { return azureName }
+
+func (*Azure) Get(ctx context.Context, ref string) ([]byte, error) {
+	vault, name, err := azureSecretRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := run(ctx, nil, "az", "keyvault", "secret", "show", "--vault-name", vault, "--name", name, "--output", "json")
+	if notFound(err, azureNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var v struct {
+		Value string `json:"value"`
+	}
+
+	if err := json.Unmarshal(out, &v); err != nil {
+		return nil, fmt.Errorf("azure: parse secret: %w", err)
+	}
+
+	return []byte(v.Value), nil
+}
+
+// Put sets the secret, az creates a secret that does not exist.
+func (*Azure) Put(ctx context.Context, ref string, value []byte) error {
+	vault, name, err := azureSecretRef(ref)
+	if err != nil {
+		return err
+	}
+
+	if !utf8.Valid(value) {
+		return fmt.Errorf("azure: secret %q: only text values are supported", ref)
+	}
+
+	_, err = run(ctx, value, "az", "keyvault", "secret", "set", "--vault-name", vault, "--name", name,
+		"--file", "/dev/stdin", "--encoding", "utf-8", "--output", "none")
+
+	return err
+}
+
+func azureSecretRef(ref string) (vault string, name string, _ error) {
+	vault, name, ok := strings.Cut(ref, "/")
+	if !ok || len(vault) == 0 || len(name) == 0 || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("azure: invalid secret reference %q: expected <vault>/<name>", ref)
+	}
+
+	return vault, name, nil
+}
@@ -0,0 +1,103 @@
+// Package cloudsecrets reads and writes secrets of cloud secret managers,
+// i.e., AWS Secrets Manager, GCP Secret Manager and Azure Key Vault.
+//
+// The command line tools of the providers are used, aws, gcloud and az,
+// so their configured credentials and defaults, e.g., region or project, apply.
+// Values are passed to the tools over stdin, never as arguments.
+package cloudsecrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// ErrNotFound is returned for a secret that does not exist in the secret manager.
+var ErrNotFound = errors.New("secret not found")
+
+// ErrUnknownProvider is returned by [New] for an unsupported provider name.
+var ErrUnknownProvider = errors.New("unknown provider")
+
+// CommandError indicates that the command line tool of a provider failed.
+type CommandError struct {
+	Cmd    string
+	Stderr string
+	Err    error
+}
+
+func (e *CommandError) Error() string {
+	if len(e.Stderr) == 0 {
+		return e.Cmd + ": " + e.Err.Error()
+	}
+
+	return e.Cmd + ": " + e.Err.Error() + ": " + e.Stderr
+}
+
+func (e *CommandError) Unwrap() error { return e.Err }
+
+// Provider is a cloud secret manager.
+//
+// Secrets are referenced by a provider specific reference,
+// see the documentation of each provider.
+type Provider interface {
+	// Name returns the name of the provider, e.g., aws.
+	Name() string
+
+	// Get returns the latest value of the secret, or [ErrNotFound].
+	Get(ctx context.Context, ref string) ([]byte, error)
+
+	// Put stores value as the latest value of the secret,
+	// creating the secret if it does not exist.
+	Put(ctx context.Context, ref string, value []byte) error
+}
+
+// Providers returns the names of the supported providers.
+func Providers() []string { return []string{awsName, gcpName, azureName} }
+
+// New returns the provider with the given name.
+func New(name string) (Provider, error) {
+	switch name {
+	case awsName:
+		return &AWS{}, nil
+	case gcpName:
+		return &GCP{}, nil
+	case azureName:
+		return &Azure{}, nil
+	default:
+		return nil, fmt.Errorf("%w %q: expected one of %s", ErrUnknownProvider, name, strings.Join(Providers(), ", "))
+	}
+}
+
+// run runs the command with stdin as its input, and returns its output.
+func run(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, &CommandError{
+			Cmd:    strings.Join(slices.Concat([]string{name}, args[:min(len(args), 2)]), " "),
+			Stderr: strings.TrimSpace(stderr.String()),
+			Err:    err,
+		}
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// notFound reports whether err is a [CommandError] whose stderr contains any of codes.
+func notFound(err error, codes ...string) bool {
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		return false
+	}
+
+	return slices.ContainsFunc(codes, func(c string) bool { return strings.Contains(cmdErr.Stderr, c) })
+}
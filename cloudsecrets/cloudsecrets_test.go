@@ -0,0 +1,224 @@
+package cloudsecrets_test
+
+import (
+	"cmp"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ladzaretti/vlt-cli/cloudsecrets"
+)
+
+const (
+	fakeToolEnv  = "CLOUDSECRETS_FAKE_TOOL"
+	fakeStoreEnv = "CLOUDSECRETS_FAKE_STORE"
+)
+
+// TestHelper is run by the fake aws, gcloud and az commands installed by
+// [installFakeTools], storing secrets as files of a directory.
+func TestHelper(*testing.T) {
+	tool := os.Getenv(fakeToolEnv)
+	if len(tool) == 0 {
+		return
+	}
+
+	args := os.Args[slices.Index(os.Args, "--")+1:]
+
+	out, code := fakeTool(tool, args, os.Getenv(fakeStoreEnv))
+	fmt.Print(out) //nolint:forbidigo // the output of the fake command
+	os.Exit(code)
+}
+
+func fakeTool(tool string, args []string, store string) (string, int) {
+	flags := map[string]string{}
+
+	for i := 0; i < len(args); i++ {
+		k, v, ok := strings.Cut(args[i], "=")
+		if !strings.HasPrefix(k, "--") {
+			continue
+		}
+
+		if !ok && i+1 < len(args) {
+			v = args[i+1]
+			i++
+		}
+
+		flags[k] = v
+	}
+
+	// the positional arguments, e.g., secretsmanager get-secret-value, or secrets versions add <name>.
+	var positional []string
+
+	for i := 0; i < len(args) && !strings.HasPrefix(args[i], "--"); i++ {
+		positional = append(positional, args[i])
+	}
+
+	var (
+		ref      string
+		op       string
+		notFound string
+	)
+
+	switch tool {
+	case "aws":
+		op, ref, notFound = positional[1], cmp.Or(flags["--secret-id"], flags["--name"]), "ResourceNotFoundException"
+	case "gcloud":
+		op, ref, notFound = strings.Join(positional[:3], " "), cmp.Or(flags["--secret"], positional[len(positional)-1]), "NOT_FOUND"
+		if positional[1] == "create" {
+			op = "secrets create"
+		}
+
+		ref = flags["--project"] + "/" + ref
+	case "az":
+		op, ref, notFound = positional[2], flags["--vault-name"]+"/"+flags["--name"], "SecretNotFound"
+	}
+
+	path := filepath.Join(store, hex.EncodeToString([]byte(tool+"|"+ref)))
+
+	read := func() ([]byte, bool) {
+		b, err := os.ReadFile(path)
+		return b, err == nil
+	}
+
+	write := func() (string, int) {
+		b, _ := io.ReadAll(os.Stdin)
+		if err := os.WriteFile(path, b, 0o600); err != nil {
+			return err.Error(), 1
+		}
+
+		return "{}", 0
+	}
+
+	fail := func() (string, int) {
+		fmt.Fprintf(os.Stderr, "ERROR: (%s) %s\n", notFound, ref)
+		return "", 1
+	}
+
+	value, exists := read()
+
+	switch op {
+	case "get-secret-value", "show":
+		if !exists {
+			return fail()
+		}
+
+		v := map[string]any{"SecretBinary": value, "value": string(value)}
+		if utf8.Valid(value) {
+			v["SecretString"] = string(value)
+		}
+
+		b, _ := json.Marshal(v)
+
+		return string(b), 0
+	case "secrets versions access":
+		if !exists {
+			return fail()
+		}
+
+		return string(value), 0
+	case "put-secret-value", "secrets versions add":
+		if !exists {
+			return fail()
+		}
+
+		return write()
+	case "create-secret", "secrets create", "set":
+		return write()
+	default:
+		return "unknown command " + op, 2
+	}
+}
+
+// installFakeTools puts fake aws, gcloud and az commands first on PATH.
+func installFakeTools(t *testing.T) {
+	t.Helper()
+
+	bin := t.TempDir()
+
+	for _, tool := range []string{"aws", "gcloud", "az"} {
+		script := fmt.Sprintf("#!/bin/sh\n%s=%s %s=%s exec %q -test.run=^TestHelper$ -- \"$@\"\n",
+			fakeToolEnv, tool, fakeStoreEnv, t.TempDir(), os.Args[0])
+
+		if err := os.WriteFile(filepath.Join(bin, tool), []byte(script), 0o700); err != nil { //nolint:gosec
+			t.Fatalf("write fake %s: %v", tool, err)
+		}
+	}
+
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestProviders(t *testing.T) {
+	installFakeTools(t)
+
+	tests := []struct {
+		provider string
+		ref      string
+		value    []byte
+	}{
+		{provider: "aws", ref: "prod/db-password", value: []byte("hunter2")},
+		{provider: "aws", ref: "prod/tls-key", value: []byte{0xff, 0x00, 0xfe}},
+		{provider: "gcp", ref: "db-password", value: []byte("hunter2")},
+		{provider: "gcp", ref: "projects/dev/secrets/db-password", value: []byte("hunter3\n")},
+		{provider: "azure", ref: "dev-vault/db-password", value: []byte("hunter2")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+"/"+tt.ref, func(t *testing.T) {
+			p, err := cloudsecrets.New(tt.provider)
+			if err != nil {
+				t.Fatalf("new provider: %v", err)
+			}
+
+			if _, err := p.Get(t.Context(), tt.ref); !errors.Is(err, cloudsecrets.ErrNotFound) {
+				t.Fatalf("want %v, got %v", cloudsecrets.ErrNotFound, err)
+			}
+
+			// the first put creates the secret, the second adds a version.
+			for _, value := range [][]byte{[]byte("old"), tt.value} {
+				if err := p.Put(t.Context(), tt.ref, value); err != nil {
+					t.Fatalf("put: %v", err)
+				}
+
+				got, err := p.Get(t.Context(), tt.ref)
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+
+				if string(got) != string(value) {
+					t.Errorf("want %q, got %q", value, got)
+				}
+			}
+		})
+	}
+}
+
+func TestProviderErrors(t *testing.T) {
+	installFakeTools(t)
+
+	if _, err := cloudsecrets.New("vault"); !errors.Is(err, cloudsecrets.ErrUnknownProvider) {
+		t.Errorf("want %v, got %v", cloudsecrets.ErrUnknownProvider, err)
+	}
+
+	azure, _ := cloudsecrets.New("azure")
+	gcp, _ := cloudsecrets.New("gcp")
+
+	if err := azure.Put(t.Context(), "dev-vault/key", []byte{0xff}); err == nil || !strings.Contains(err.Error(), "only text values") {
+		t.Errorf("azure binary value: want a text only error, got %v", err)
+	}
+
+	if _, err := azure.Get(t.Context(), "db-password"); err == nil || !strings.Contains(err.Error(), "invalid secret reference") {
+		t.Errorf("azure reference without vault: want an invalid reference error, got %v", err)
+	}
+
+	if _, err := gcp.Get(t.Context(), "projects/dev/db-password"); err == nil || !strings.Contains(err.Error(), "invalid secret reference") {
+		t.Errorf("gcp malformed resource name: want an invalid reference error, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package cloudsecrets
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const gcpName = "gcp"
+
+// gcpNotFound is the error code of a missing GCP secret.
+const gcpNotFound = "NOT_FOUND"
+
+// GCP is GCP Secret Manager, using the gcloud command.
+//
+// Secrets are referenced by their name, in the default project of gcloud,
+// or by their resource name, i.e., projects/<project>/secrets/<name>.
+type GCP struct{}
+
+var _ Provider = &GCP{}
+
+func (*GCP) Name() string { return gcpName }
+
+func (*GCP) Get(ctx context.Context, ref string) ([]byte, error) {
+	args, err := gcpSecretArgs(ref, "secrets", "versions", "access", "latest", "--secret")
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := run(ctx, nil, "gcloud", args...)
+	if notFound(err, gcpNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+
+	return out, err
+}
+
+func (*GCP) Put(ctx context.Context, ref string, value []byte) error {
+	args, err := gcpSecretArgs(ref, "secrets", "versions", "add")
+	if err != nil {
+		return err
+	}
+
+	_, err = run(ctx, value, "gcloud", append(args, "--data-file=-")...)
+	if !notFound(err, gcpNotFound) {
+		return err
+	}
+
+	args, err = gcpSecretArgs(ref, "secrets", "create")
+	if err != nil {
+		return err
+	}
+
+	_, err = run(ctx, value, "gcloud", append(args, "--data-file=-")...)
+
+	return err
+}
+
+// gcpSecretArgs returns cmd followed by the secret name and project of ref.
+func gcpSecretArgs(ref string, cmd ...string) ([]string, error) {
+	name, project := ref, ""
+
+	if rest, ok := strings.CutPrefix(ref, "projects/"); ok {
+		p, n, ok := strings.Cut(rest, "/secrets/")
+		if !ok || len(p) == 0 || len(n) == 0 || strings.Contains(n, "/") {
+			return nil, fmt.Errorf("gcp: invalid secret reference %q: expected <name> or projects/<project>/secrets/<name>", ref)
+		}
+
+		name, project = n, p
+	}
+
+	args := slices.Concat(cmd, []string{name})
+	if len(project) > 0 {
+		args = append(args, "--project", project)
+	}
+
+	return args, nil
+}
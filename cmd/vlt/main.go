@@ -27,16 +27,74 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/ladzaretti/vlt-cli/cli"
 	"github.com/ladzaretti/vlt-cli/clierror"
 	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/redact"
+
+	"golang.org/x/term"
 )
 
+// interruptGracePeriod is how long an interrupted command is given
+// to abort its in-flight operations and roll back before vlt exits forcefully.
+const interruptGracePeriod = 3 * time.Second
+
 func main() {
+	// messages stay untranslated for locales without a catalog.
+	_ = i18n.SetLocale(i18n.DetectLocale())
+
 	iostream := genericclioptions.NewDefaultIOStreams()
 	clierror.SetErrWriter(iostream.ErrOut)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go forceExitAfterInterrupt(ctx, stop)
+
 	vlt := cli.NewDefaultVltCommand(iostream, os.Args[1:])
-	_ = vlt.Execute()
+	err := vlt.ExecuteContext(ctx)
+
+	// the post-run is skipped on errors, e.g., of the flags.
+	redact.Reset()
+
+	if err != nil {
+		// command failures exit with their own code in clierror.Check,
+		// errors returned here are usage errors reported by cobra, e.g., an unknown flag.
+		stop()
+		os.Exit(clierror.UsageExitCode) //nolint:gocritic // the deferred stop is called above.
+	}
+}
+
+// forceExitAfterInterrupt exits the process if it does not terminate on its own
+// shortly after being interrupted, e.g., while blocked on a password prompt,
+// which cannot be canceled.
+//
+// A second interrupt exits immediately, as the default signal behavior
+// is restored after the first.
+func forceExitAfterInterrupt(ctx context.Context, stop context.CancelFunc) {
+	fd := int(os.Stdin.Fd()) //nolint:gosec // fd fits in int
+
+	state, err := term.GetState(fd)
+	if err != nil {
+		state = nil // stdin is not a terminal
+	}
+
+	<-ctx.Done()
+	stop()
+
+	time.Sleep(interruptGracePeriod)
+
+	if state != nil {
+		_ = term.Restore(fd, state)
+	}
+
+	redact.Reset()
+	os.Exit(clierror.InterruptedExitCode)
 }
@@ -28,21 +28,38 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/ladzaretti/vlt-cli/cli"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/sandbox"
 	"github.com/ladzaretti/vlt-cli/vaultdaemon"
 )
 
+// logFilePerm is the file permission mode for the log file.
+const logFilePerm = 0o600
+
 var Version = "0.0.0"
 
 func main() {
 	help := flag.Bool("help", false, "Show usage information")
 	version := flag.Bool("version", false, "Show version")
+	logLevel := flag.String("log-level", "info", "Minimal level of log messages (debug, info, warn, error)")
+	logFormat := flag.String("log-format", genericclioptions.LogFormatText, "Format of log messages (text, json)")
+	logFile := flag.String("log-file", "", "Append log messages to the given file instead of stderr")
+	confirmProgram := flag.String("confirm-program", "pinentry", "Pinentry program used to confirm session use of vaults with 'confirm_each_use' set")
+	configPath := flag.String("config", "", "Path to the vlt config file, jobs are read from its [daemon.schedule] section and the core dump setting from its [vault] section (default: $VLT_CONFIG_PATH or $XDG_CONFIG_HOME/vlt/config.toml)")
+	metricsAddr := flag.String("metrics-addr", "", "Serve Prometheus metrics at /metrics on the given loopback address, e.g., 127.0.0.1:9464")
+	auditLog := flag.String("audit-log", vaultdaemon.DefaultAuditLogPath(), "Append a record of each session request to the given file, shown by 'vlt session log'; empty to disable")
+	rateLimit := flag.Float64("rate-limit", vaultdaemon.DefaultRateLimit, "Maximal requests per second of each client uid, bursting to twice as many; zero to disable")
+	socket := flag.String("socket", "", "Path of the unix socket, or '@name' for an abstract socket (default: $VLT_SESSION_SOCKET, the [daemon] socket config setting, or $XDG_RUNTIME_DIR/vlt.sock)")
+	noSandbox := flag.Bool("no-sandbox", false, "Disable the process sandbox (seccomp filter, no_new_privs), for debugging")
 
 	flag.Usage = func() {
 		_, _ = fmt.Fprint(flag.CommandLine.Output(), `vltd - background daemon for the 'vlt' cli.
@@ -50,7 +67,12 @@ func main() {
 Usage: vltd [options]
 
 Manages user sessions for the 'vlt' cli.
-Runs over a UNIX socket at /run/user/$UID/vlt.sock and takes no arguments.
+Runs over a UNIX socket, by default at $XDG_RUNTIME_DIR/vlt.sock, and takes no arguments.
+Without $XDG_RUNTIME_DIR or /run/user/$UID, e.g., in ssh sessions without systemd,
+the socket is created in a private $TMPDIR/vlt-$UID directory.
+Runs the periodic jobs of the [daemon.schedule] section of the vlt config file,
+only vault backups for now.
+Records the session requests of clients in an audit log, see 'vlt session log'.
 
 Options:
 `)
@@ -70,8 +92,92 @@ Options:
 		return
 	}
 
+	level, err := genericclioptions.ParseLogLevel(*logLevel)
+	if err != nil {
+		fatalf("vltd: %v\n", err)
+	}
+
+	var w io.Writer = os.Stderr
+
+	if len(*logFile) > 0 {
+		f, err := os.OpenFile(*logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, logFilePerm)
+		if err != nil {
+			fatalf("vltd: open log file: %v\n", err)
+		}
+		defer func() { //nolint:wsl_v5
+			_ = f.Close()
+		}()
+
+		w = f
+	}
+
+	logger, err := genericclioptions.NewLogger(w, level, *logFormat)
+	if err != nil {
+		fatalf("vltd: %v\n", err)
+	}
+
+	logger = logger.With("component", "vltd")
+
+	if !*noSandbox {
+		if err := sandbox.Apply(); err != nil {
+			logger.Warn("sandbox not fully applied", "err", err)
+		}
+	}
+
+	config, err := cli.LoadFileConfig(*configPath)
+	if err != nil {
+		fatalf("vltd: %v\n", err)
+	}
+
+	// sessions hold vault keys, a crash must not write them to disk.
+	if !config.Vault.AllowCoreDumps {
+		if err := sandbox.DisableCoreDumps(); err != nil {
+			fatalf("vltd: %v\n", err)
+		}
+	}
+
+	jobs, err := cli.ScheduledJobs(config)
+	if err != nil {
+		fatalf("vltd: %v\n", err)
+	}
+
+	// resolved like vlt does, unless set by the flag, e.g., when spawned by vlt.
+	if len(*socket) == 0 {
+		if *socket, err = cli.DaemonSocket(config); err != nil {
+			fatalf("vltd: %v\n", err)
+		}
+	}
+
+	if len(*socket) > 0 {
+		vaultdaemon.SetSocketPath(*socket)
+	}
+
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
 	defer cancel()
 
-	log.Println(vaultdaemon.Run(ctx))
+	opts := []vaultdaemon.Option{
+		vaultdaemon.WithLogger(logger),
+		vaultdaemon.WithVersion(Version),
+		vaultdaemon.WithConfirmProgram(*confirmProgram),
+		vaultdaemon.WithJobs(jobs...),
+		vaultdaemon.WithAuditLog(*auditLog),
+		vaultdaemon.WithRateLimit(*rateLimit, int(2*(*rateLimit))),
+	}
+
+	if len(*metricsAddr) > 0 {
+		opts = append(opts, vaultdaemon.WithMetricsAddr(*metricsAddr))
+	}
+
+	err = vaultdaemon.Run(ctx, opts...)
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	logger.Error("daemon exited", "err", err)
+	os.Exit(1) //nolint:revive // report failure to the service manager.
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format, args...)
+	os.Exit(2) //nolint:revive // invalid command line usage.
 }
@@ -0,0 +1,272 @@
+// Package csiprovider implements a provider of the Secrets Store CSI driver,
+// mounting secrets held by vlt as files into the pods of a local Kubernetes
+// development cluster, e.g., kind or minikube.
+//
+// The driver connects to the provider of a SecretProviderClass at
+// <providers dir>/<provider>.sock, by default
+// /etc/kubernetes/secrets-store-csi-providers/vlt.sock on the node.
+package csiprovider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+
+	pb "github.com/ladzaretti/vlt-cli/csiprovider/proto/csiproviderpb"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vaultcrypto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ProviderName is the provider name set by SecretProviderClass objects served by vlt.
+	ProviderName = "vlt"
+
+	// apiVersion is the version of the provider API implemented by [Server].
+	apiVersion = "v1alpha1"
+
+	// objectsParameter is the SecretProviderClass parameter listing the mounted secrets.
+	objectsParameter = "objects"
+)
+
+// socketPath is the default path of the unix domain socket of the provider.
+var socketPath = fmt.Sprintf("/run/user/%d/vlt-csi/%s.sock", os.Getuid(), ProviderName)
+
+// SocketPath returns the default path of the unix domain socket of the provider.
+func SocketPath() string { return socketPath }
+
+// ErrNotFound is returned by a [SecretFunc] for a secret that is not served.
+var ErrNotFound = errors.New("secret not found")
+
+// SecretFunc returns the value of the secret with the given name, or [ErrNotFound].
+// The value is owned by the [Server], which wipes it once sent to the driver.
+type SecretFunc func(ctx context.Context, name string) ([]byte, error)
+
+// Object is a secret listed by the objects parameter of a SecretProviderClass,
+// given as a JSON array, e.g., [{"name": "db/password", "path": "password"}].
+type Object struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+
+	// Path is the file path of the secret relative to the mount point,
+	// defaults to the name of the secret.
+	Path string `json:"path,omitempty"`
+}
+
+// Server implements the CSIDriverProvider service of the Secrets Store CSI driver.
+type Server struct {
+	pb.UnimplementedCSIDriverProviderServer
+
+	secret  SecretFunc
+	version string
+	logger  *slog.Logger
+
+	// versionKey keys the object versions reported to the driver,
+	// which are stored in the cluster, so they do not reveal the secrets.
+	versionKey []byte
+}
+
+var _ pb.CSIDriverProviderServer = &Server{}
+
+// NewServer returns a provider mounting the secrets returned by secret,
+// version is the runtime version reported to the driver.
+func NewServer(secret SecretFunc, version string, logger *slog.Logger) (*Server, error) {
+	key, err := vaultcrypto.RandBytes(sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		secret:     secret,
+		version:    version,
+		logger:     logger,
+		versionKey: key,
+	}, nil
+}
+
+// Version returns the provider API version and the runtime name and version.
+func (s *Server) Version(context.Context, *pb.VersionRequest) (*pb.VersionResponse, error) {
+	return &pb.VersionResponse{
+		Version:        apiVersion,
+		RuntimeName:    ProviderName,
+		RuntimeVersion: s.version,
+	}, nil
+}
+
+// Mount returns the files of the secrets listed by the objects parameter,
+// the driver writes them to the mount point of the volume.
+//
+// When served by [Serve], the contents of the files are wiped once the
+// response is sent, see [wipeHandler].
+func (s *Server) Mount(ctx context.Context, req *pb.MountRequest) (_ *pb.MountResponse, retErr error) {
+	var attrs map[string]string
+	if err := json.Unmarshal([]byte(req.GetAttributes()), &attrs); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "parse attributes: %v", err)
+	}
+
+	objects, err := ParseObjects(attrs[objectsParameter])
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var perm os.FileMode
+	if err := json.Unmarshal([]byte(req.GetPermission()), &perm); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "parse permission: %v", err)
+	}
+
+	resp := &pb.MountResponse{
+		Files:         make([]*pb.File, 0, len(objects)),
+		ObjectVersion: make([]*pb.ObjectVersion, 0, len(objects)),
+	}
+
+	defer func() {
+		if retErr != nil {
+			wipeFiles(resp.Files)
+			return
+		}
+
+		if sent, ok := ctx.Value(sentFilesKey{}).(*sentFiles); ok {
+			sent.files = resp.Files
+		}
+	}()
+
+	for _, o := range objects {
+		contents, err := s.secret(ctx, o.Name)
+		if errors.Is(err, ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "secret %q not found", o.Name)
+		}
+
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "secret %q: %v", o.Name, err)
+		}
+
+		resp.Files = append(resp.Files, &pb.File{Path: o.Path, Mode: int32(perm), Contents: contents}) //nolint:gosec // file modes fit int32.
+		resp.ObjectVersion = append(resp.ObjectVersion, &pb.ObjectVersion{Id: o.Name, Version: s.objectVersion(contents)})
+	}
+
+	s.logger.Info("secrets mounted",
+		"pod", attrs["csi.storage.k8s.io/pod.name"],
+		"namespace", attrs["csi.storage.k8s.io/pod.namespace"],
+		"objects", len(objects),
+	)
+
+	return resp, nil
+}
+
+// sentFiles holds the files of a mount response, see [wipeHandler].
+type sentFiles struct {
+	files []*pb.File
+}
+
+type sentFilesKey struct{}
+
+// wipeHandler wipes the contents of the files returned by [Server.Mount] once
+// the RPC ends, after the response is marshalled and sent, or failed to be.
+// The buffers of the encoded response are held by gRPC, and are not wiped.
+type wipeHandler struct{}
+
+var _ stats.Handler = wipeHandler{}
+
+func (wipeHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, sentFilesKey{}, &sentFiles{})
+}
+
+func (wipeHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	if _, ok := rs.(*stats.End); !ok {
+		return
+	}
+
+	if sent, ok := ctx.Value(sentFilesKey{}).(*sentFiles); ok {
+		wipeFiles(sent.files)
+	}
+}
+
+func (wipeHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+
+func (wipeHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+func wipeFiles(files []*pb.File) {
+	for _, f := range files {
+		securebytes.Wipe(f.GetContents())
+	}
+}
+
+// objectVersion returns the version of an object with the given contents.
+func (s *Server) objectVersion(contents []byte) string {
+	mac := hmac.New(sha256.New, s.versionKey)
+	mac.Write(contents)
+
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// ParseObjects parses the objects parameter of a SecretProviderClass,
+// defaulting the path of each object to its name.
+func ParseObjects(raw string) ([]Object, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("missing %q parameter", objectsParameter)
+	}
+
+	var objects []Object
+	if err := json.Unmarshal([]byte(raw), &objects); err != nil {
+		return nil, fmt.Errorf("parse %q parameter: %w", objectsParameter, err)
+	}
+
+	paths := make(map[string]struct{}, len(objects))
+
+	for i := range objects {
+		o := &objects[i]
+
+		if len(o.Name) == 0 {
+			return nil, fmt.Errorf("object %d: missing name", i)
+		}
+
+		if len(o.Path) == 0 {
+			o.Path = o.Name
+		}
+
+		if !filepath.IsLocal(o.Path) {
+			return nil, fmt.Errorf("object %q: path %q is not local to the mount point", o.Name, o.Path)
+		}
+
+		if _, ok := paths[o.Path]; ok {
+			return nil, fmt.Errorf("object %q: duplicate path %q", o.Name, o.Path)
+		}
+
+		paths[o.Path] = struct{}{}
+	}
+
+	return objects, nil
+}
+
+// Serve serves the provider on lis until ctx is done.
+func Serve(ctx context.Context, lis net.Listener, s *Server) error {
+	srv := grpc.NewServer(grpc.StatsHandler(wipeHandler{}))
+	pb.RegisterCSIDriverProviderServer(srv, s)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		srv.GracefulStop()
+		<-errc
+
+		return nil
+	case err := <-errc:
+		return err
+	}
+}
@@ -0,0 +1,184 @@
+package csiprovider_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/csiprovider"
+	pb "github.com/ladzaretti/vlt-cli/csiprovider/proto/csiproviderpb"
+
+	gocmp "github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer(t *testing.T) {
+	secrets := map[string]string{
+		"db/password": "hunter2",
+		"api-token":   "t0ken",
+	}
+
+	var (
+		mu       sync.Mutex
+		returned [][]byte
+	)
+
+	secretFunc := func(_ context.Context, name string) ([]byte, error) {
+		v, ok := secrets[name]
+		if !ok {
+			return nil, csiprovider.ErrNotFound
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		returned = append(returned, []byte(v))
+
+		return returned[len(returned)-1], nil
+	}
+
+	s, err := csiprovider.NewServer(secretFunc, "v1.0.0", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "vlt.sock")
+
+	var lc net.ListenConfig
+
+	lis, err := lc.Listen(t.Context(), "unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	done := make(chan error)
+	go func() {
+		done <- csiprovider.Serve(ctx, lis, s)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := <-done; err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	})
+
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	client := pb.NewCSIDriverProviderClient(conn)
+
+	version, err := client.Version(t.Context(), &pb.VersionRequest{Version: "v1alpha1"})
+	if err != nil {
+		t.Fatalf("version: %v", err)
+	}
+
+	if version.GetVersion() != "v1alpha1" || version.GetRuntimeName() != csiprovider.ProviderName || version.GetRuntimeVersion() != "v1.0.0" {
+		t.Errorf("unexpected version response: %v", version)
+	}
+
+	mount := func(objects string) (*pb.MountResponse, error) {
+		attrs := `{"objects": ` + objects + `, "csi.storage.k8s.io/pod.name": "app"}`
+		return client.Mount(t.Context(), &pb.MountRequest{Attributes: attrs, Permission: "420", TargetPath: "/var/lib/kubelet/pods/x"})
+	}
+
+	resp, err := mount(`"[{\"name\": \"db/password\", \"path\": \"password\"}, {\"name\": \"api-token\"}]"`)
+	if err != nil {
+		t.Fatalf("mount: %v", err)
+	}
+
+	type file struct {
+		Path     string
+		Mode     int32
+		Contents string
+	}
+
+	got := make([]file, 0, len(resp.GetFiles()))
+	for _, f := range resp.GetFiles() {
+		got = append(got, file{f.GetPath(), f.GetMode(), string(f.GetContents())})
+	}
+
+	want := []file{
+		{"password", 0o644, "hunter2"},
+		{"api-token", 0o644, "t0ken"},
+	}
+
+	if diff := gocmp.Diff(want, got); diff != "" {
+		t.Errorf("files mismatch (-want +got):\n%s", diff)
+	}
+
+	// the contents are wiped once the response is sent, after the client receives it.
+	wiped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, v := range returned {
+			if !bytes.Equal(v, make([]byte, len(v))) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); !wiped(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("want the mounted contents wiped once sent")
+		}
+	}
+
+	if n := len(resp.GetObjectVersion()); n != 2 {
+		t.Fatalf("want 2 object versions, got %d", n)
+	}
+
+	for _, v := range resp.GetObjectVersion() {
+		if len(v.GetVersion()) == 0 || v.GetVersion() == secrets[v.GetId()] {
+			t.Errorf("unexpected version %q of object %q", v.GetVersion(), v.GetId())
+		}
+	}
+
+	// versions only change with the contents of the secret.
+	again, err := mount(`"[{\"name\": \"db/password\"}]"`)
+	if err != nil {
+		t.Fatalf("mount: %v", err)
+	}
+
+	if a, b := again.GetObjectVersion()[0].GetVersion(), resp.GetObjectVersion()[0].GetVersion(); a != b {
+		t.Errorf("want a stable version, got %q and %q", a, b)
+	}
+
+	tests := []struct {
+		name     string
+		objects  string
+		wantCode codes.Code
+	}{
+		{name: "unknown secret", objects: `"[{\"name\": \"missing\"}]"`, wantCode: codes.NotFound},
+		{name: "path outside mount", objects: `"[{\"name\": \"api-token\", \"path\": \"../token\"}]"`, wantCode: codes.InvalidArgument},
+		{name: "duplicate path", objects: `"[{\"name\": \"api-token\"}, {\"name\": \"db/password\", \"path\": \"api-token\"}]"`, wantCode: codes.InvalidArgument},
+		{name: "missing objects", objects: `""`, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := mount(tt.objects); status.Code(err) != tt.wantCode {
+				t.Errorf("want code %v, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
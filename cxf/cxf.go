@@ -0,0 +1,195 @@
+// Package cxf reads and writes passkeys in the FIDO Alliance Credential
+// Exchange Format (CXF), the JSON format password managers exchange
+// credentials in.
+//
+// Only passkey credentials are modeled, other credential kinds, e.g.,
+// basic-auth or totp, are skipped when reading. The format is still a draft,
+// documents of an unknown major version are rejected.
+package cxf
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CredentialTypePasskey is the type of passkey credentials.
+const CredentialTypePasskey = "passkey"
+
+// ErrUnsupportedVersion is returned for documents of an unknown major version.
+var ErrUnsupportedVersion = errors.New("unsupported cxf version")
+
+// CurrentVersion is the format version written by [Encode].
+var CurrentVersion = Version{Major: 1, Minor: 0}
+
+// Version is the version of the format a document is written in,
+// readers support all minor versions of their major version.
+type Version struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+}
+
+// Header is the root of a CXF document.
+type Header struct {
+	Version             Version   `json:"version"`
+	ExporterRpID        string    `json:"exporterRpId"`
+	ExporterDisplayName string    `json:"exporterDisplayName"`
+	Timestamp           int64     `json:"timestamp"` // Timestamp is the export time in unix seconds.
+	Accounts            []Account `json:"accounts"`
+}
+
+// Account holds the items of a single user of the exporting provider.
+type Account struct {
+	ID       string `json:"id"` // ID is the base64url encoded account id.
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Items    []Item `json:"items"`
+}
+
+// Item is an entry of an account, holding the credentials of a single site.
+type Item struct {
+	ID          string            `json:"id"` // ID is the base64url encoded item id.
+	Title       string            `json:"title"`
+	Scope       *Scope            `json:"scope,omitempty"`
+	Credentials []json.RawMessage `json:"credentials"`
+}
+
+// Scope lists where the credentials of an item are used.
+type Scope struct {
+	URLs        []string `json:"urls"`
+	AndroidApps []any    `json:"androidApps"`
+}
+
+// Passkey is a WebAuthn credential.
+//
+// Binary fields are base64url encoded without padding, see [EncodeBytes].
+type Passkey struct {
+	Type            string `json:"type"`
+	CredentialID    string `json:"credentialId"`
+	RpID            string `json:"rpId"`
+	Username        string `json:"username"`
+	UserDisplayName string `json:"userDisplayName"`
+	UserHandle      string `json:"userHandle"`
+	Key             string `json:"key"` // Key is the PKCS#8 DER encoded private key.
+}
+
+// NewPasskeyItem returns an item holding p, titled and scoped by its relying party.
+func NewPasskeyItem(p Passkey) (Item, error) {
+	p.Type = CredentialTypePasskey
+
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return Item{}, err
+	}
+
+	return Item{
+		ID:          p.CredentialID,
+		Title:       p.RpID,
+		Scope:       &Scope{URLs: []string{"https://" + p.RpID}, AndroidApps: []any{}},
+		Credentials: []json.RawMessage{raw},
+	}, nil
+}
+
+// Passkeys returns the passkey credentials of all items of h, in order,
+// with the binary fields re-encoded without padding.
+func (h *Header) Passkeys() ([]Passkey, error) {
+	var passkeys []Passkey
+
+	for _, a := range h.Accounts {
+		for _, it := range a.Items {
+			for _, raw := range it.Credentials {
+				var kind struct {
+					Type string `json:"type"`
+				}
+
+				if err := json.Unmarshal(raw, &kind); err != nil {
+					return nil, fmt.Errorf("item %q: %w", it.ID, err)
+				}
+
+				if kind.Type != CredentialTypePasskey {
+					continue
+				}
+
+				var p Passkey
+				if err := json.Unmarshal(raw, &p); err != nil {
+					return nil, fmt.Errorf("item %q: %w", it.ID, err)
+				}
+
+				if err := p.normalize(); err != nil {
+					return nil, fmt.Errorf("item %q: %w", it.ID, err)
+				}
+
+				passkeys = append(passkeys, p)
+			}
+		}
+	}
+
+	return passkeys, nil
+}
+
+// normalize verifies the required fields of p are set,
+// and re-encodes its binary fields without padding.
+func (p *Passkey) normalize() error {
+	if len(p.RpID) == 0 {
+		return errors.New("passkey has no rpId")
+	}
+
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"credentialId", &p.CredentialID},
+		{"userHandle", &p.UserHandle},
+		{"key", &p.Key},
+	}
+
+	for _, f := range fields {
+		b, err := DecodeBytes(*f.value)
+		if err != nil {
+			return fmt.Errorf("passkey %s: %w", f.name, err)
+		}
+
+		if len(b) == 0 {
+			return fmt.Errorf("passkey has no %s", f.name)
+		}
+
+		*f.value = EncodeBytes(b)
+	}
+
+	return nil
+}
+
+// Parse reads a CXF document from r.
+func Parse(r io.Reader) (*Header, error) {
+	var h Header
+	if err := json.NewDecoder(r).Decode(&h); err != nil {
+		return nil, fmt.Errorf("parse cxf: %w", err)
+	}
+
+	if h.Version.Major != CurrentVersion.Major {
+		return nil, fmt.Errorf("%w: %d.%d", ErrUnsupportedVersion, h.Version.Major, h.Version.Minor)
+	}
+
+	return &h, nil
+}
+
+// Encode writes h to w as an indented CXF document.
+func Encode(w io.Writer, h *Header) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(h)
+}
+
+// EncodeBytes returns b base64url encoded without padding, as binary fields are stored.
+func EncodeBytes(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBytes decodes a base64url encoded field, with or without padding.
+func DecodeBytes(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
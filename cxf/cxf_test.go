@@ -0,0 +1,117 @@
+package cxf_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/cxf"
+
+	gocmp "github.com/google/go-cmp/cmp"
+)
+
+func TestRoundTrip(t *testing.T) {
+	want := cxf.Passkey{
+		Type:            cxf.CredentialTypePasskey,
+		CredentialID:    cxf.EncodeBytes([]byte("credential")),
+		RpID:            "github.com",
+		Username:        "alice",
+		UserDisplayName: "Alice",
+		UserHandle:      cxf.EncodeBytes([]byte("handle")),
+		Key:             cxf.EncodeBytes([]byte("key")),
+	}
+
+	item, err := cxf.NewPasskeyItem(want)
+	if err != nil {
+		t.Fatalf("new passkey item: %v", err)
+	}
+
+	var buf bytes.Buffer
+
+	h := &cxf.Header{Version: cxf.CurrentVersion, Accounts: []cxf.Account{{Items: []cxf.Item{item}}}}
+	if err := cxf.Encode(&buf, h); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	parsed, err := cxf.Parse(&buf)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	got, err := parsed.Passkeys()
+	if err != nil {
+		t.Fatalf("passkeys: %v", err)
+	}
+
+	if diff := gocmp.Diff([]cxf.Passkey{want}, got); diff != "" {
+		t.Errorf("passkeys mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse(t *testing.T) {
+	doc := `{
+  "version": {"major": 1, "minor": 3},
+  "exporterRpId": "example.com",
+  "exporterDisplayName": "Example",
+  "timestamp": 1700000000,
+  "accounts": [{
+    "id": "YWNjb3VudA",
+    "username": "", "email": "",
+    "items": [{
+      "id": "aXRlbQ",
+      "title": "github.com",
+      "credentials": [
+        {"type": "basic-auth", "username": {"value": "alice"}},
+        {"type": "passkey", "credentialId": "Y3JlZA==", "rpId": "github.com", "username": "alice",
+         "userDisplayName": "", "userHandle": "aGFuZGxl", "key": "a2V5"}
+      ]
+    }]
+  }]
+}`
+
+	h, err := cxf.Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	got, err := h.Passkeys()
+	if err != nil {
+		t.Fatalf("passkeys: %v", err)
+	}
+
+	// other credential kinds are skipped, padding is dropped.
+	want := []cxf.Passkey{{
+		Type:         cxf.CredentialTypePasskey,
+		CredentialID: "Y3JlZA",
+		RpID:         "github.com",
+		Username:     "alice",
+		UserHandle:   "aGFuZGxl",
+		Key:          "a2V5",
+	}}
+
+	if diff := gocmp.Diff(want, got); diff != "" {
+		t.Errorf("passkeys mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := cxf.Parse(strings.NewReader(`{"version": {"major": 2, "minor": 0}}`)); !errors.Is(err, cxf.ErrUnsupportedVersion) {
+		t.Errorf("want %v, got %v", cxf.ErrUnsupportedVersion, err)
+	}
+
+	missingKey, _ := json.Marshal(cxf.Header{
+		Version: cxf.CurrentVersion,
+		Accounts: []cxf.Account{{Items: []cxf.Item{{
+			Credentials: []json.RawMessage{[]byte(`{"type": "passkey", "credentialId": "Y3JlZA", "rpId": "github.com", "userHandle": "aGFuZGxl"}`)},
+		}}}},
+	})
+
+	h, err = cxf.Parse(bytes.NewReader(missingKey))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if _, err := h.Passkeys(); err == nil || !strings.Contains(err.Error(), "passkey has no key") {
+		t.Errorf("want a missing key error, got %v", err)
+	}
+}
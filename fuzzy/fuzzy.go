@@ -0,0 +1,134 @@
+// Package fuzzy scores strings against loosely typed search patterns,
+// e.g., "ght" matching "github-token".
+//
+// A pattern matches a string if its characters appear in the string in order,
+// not necessarily adjacent. Such matches are ranked by how closely the
+// characters are packed, and whether they start words. Patterns with typos,
+// e.g., "gihtub", still match a substring within a small edit distance,
+// ranked below any in order match.
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	scoreMatch       = 1  // scoreMatch is awarded per matched character.
+	bonusConsecutive = 5  // bonusConsecutive is awarded per character matched right after the previous one.
+	bonusBoundary    = 8  // bonusBoundary is awarded per character matched at the start of a word.
+	penaltyGap       = 1  // penaltyGap is deducted per character skipped between matches.
+	maxGapPenalty    = 10 // maxGapPenalty bounds the penalty of a single gap.
+
+	// typoRatio is the number of pattern characters allowed per typo,
+	// e.g., a transposition of adjacent characters.
+	typoRatio = 4
+)
+
+// Score reports whether pattern matches s, ignoring case, and the score
+// of the match; higher scores are better matches.
+//
+// In order matches score at least 1, matches with typos score below 0,
+// and the empty pattern matches anything with a score of 0.
+func Score(pattern, s string) (int, bool) {
+	p, r := []rune(strings.ToLower(pattern)), []rune(s)
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	if score, ok := subsequence(p, r); ok {
+		return max(score, 1), true
+	}
+
+	maxTypos := len(p) / typoRatio
+	if maxTypos == 0 {
+		return 0, false
+	}
+
+	if d := substringDistance(p, []rune(strings.ToLower(s))); d <= maxTypos {
+		return -d, true
+	}
+
+	return 0, false
+}
+
+// subsequence matches the lowercase pattern p against the characters of s in
+// order, preferring the earliest match of each character.
+func subsequence(p, s []rune) (int, bool) {
+	var (
+		score int
+		i     int
+		last  = -1
+	)
+
+	for j := 0; j < len(s) && i < len(p); j++ {
+		if unicode.ToLower(s[j]) != p[i] {
+			continue
+		}
+
+		score += scoreMatch
+
+		switch {
+		case last >= 0 && j == last+1:
+			score += bonusConsecutive
+		case last >= 0:
+			score -= min((j-last-1)*penaltyGap, maxGapPenalty)
+		}
+
+		if isBoundary(s, j) {
+			score += bonusBoundary
+		}
+
+		last = j
+		i++
+	}
+
+	return score, i == len(p)
+}
+
+// isBoundary reports whether s[j] starts a word, i.e., it is the first
+// character, follows a non alphanumeric one, or is an inner upper case letter.
+func isBoundary(s []rune, j int) bool {
+	if j == 0 {
+		return true
+	}
+
+	prev, cur := s[j-1], s[j]
+
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// substringDistance returns the smallest edit distance between p and any
+// substring of s, counting insertions, deletions, substitutions and
+// transpositions of adjacent characters as single edits.
+func substringDistance(p, s []rune) int {
+	// rows i-2, i-1 and i hold the distances between the prefixes of p and
+	// the substrings of s ending at each position, starting anywhere at no cost.
+	prev2, prev, cur := make([]int, len(s)+1), make([]int, len(s)+1), make([]int, len(s)+1)
+
+	for i := 1; i <= len(p); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(s); j++ {
+			cost := 1
+			if p[i-1] == s[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j-1]+cost, prev[j]+1, cur[j-1]+1)
+
+			if i > 1 && j > 1 && p[i-1] == s[j-2] && p[i-2] == s[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return slices.Min(prev)
+}
@@ -0,0 +1,56 @@
+package fuzzy_test
+
+import (
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/fuzzy"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		wantOK  bool
+	}{
+		{pattern: "", s: "github-token", wantOK: true},
+		{pattern: "ght", s: "github-token", wantOK: true},
+		{pattern: "GHT", s: "github-token", wantOK: true},
+		{pattern: "gh", s: "GitHub", wantOK: true},
+		{pattern: "gihtub", s: "github-token", wantOK: true},
+		{pattern: "thg", s: "github-token", wantOK: false},
+		{pattern: "aws", s: "github-token", wantOK: false},
+		{pattern: "xyz", s: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := fuzzy.Score(tt.pattern, tt.s); ok != tt.wantOK {
+			t.Errorf("Score(%q, %q): got ok %t, want %t", tt.pattern, tt.s, ok, tt.wantOK)
+		}
+	}
+}
+
+func TestScore_Ranking(t *testing.T) {
+	// each pair is ordered from the better to the worse match of the pattern.
+	tests := []struct {
+		pattern       string
+		better, worse string
+	}{
+		{pattern: "git", better: "github", worse: "gadget-item"},
+		{pattern: "gt", better: "github-token", worse: "vagrant"},
+		{pattern: "tok", better: "token", worse: "the-other-key"},
+		{pattern: "gihtub", better: "github", worse: "gitlab-hub"},
+	}
+
+	for _, tt := range tests {
+		better, ok := fuzzy.Score(tt.pattern, tt.better)
+		if !ok {
+			t.Errorf("Score(%q, %q): want a match", tt.pattern, tt.better)
+			continue
+		}
+
+		worse, ok := fuzzy.Score(tt.pattern, tt.worse)
+		if ok && worse >= better {
+			t.Errorf("pattern %q: want %q (%d) ranked above %q (%d)", tt.pattern, tt.better, better, tt.worse, worse)
+		}
+	}
+}
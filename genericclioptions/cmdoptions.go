@@ -1,6 +1,10 @@
 package genericclioptions
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+)
 
 // BaseOptions defines the interface for shared setup and validation logic.
 type BaseOptions interface {
@@ -26,5 +30,22 @@ func ExecuteCommand(ctx context.Context, cmd CmdOptions, args ...string) error {
 		return err
 	}
 
-	return cmd.Run(ctx, args...)
+	return WithContextError(ctx, cmd.Run(ctx, args...))
+}
+
+// WithContextError wraps err with the context error, if the context was canceled
+// or its deadline exceeded.
+//
+// Errors of interrupted operations do not necessarily wrap the context error,
+// e.g., an interrupted SQLite statement.
+func WithContextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+
+	return err
 }
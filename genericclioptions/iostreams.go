@@ -2,9 +2,18 @@ package genericclioptions
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/input"
+	"github.com/ladzaretti/vlt-cli/style"
+
+	"golang.org/x/term"
 )
 
 type IOStreams struct {
@@ -13,6 +22,38 @@ type IOStreams struct {
 	ErrOut io.Writer
 
 	Verbose bool
+
+	// Quiet suppresses informational messages and warnings, same as the error log level,
+	// leaving the command output and errors only.
+	Quiet bool
+
+	// LogLevel is the minimal level of logged messages, see [ParseLogLevel].
+	// Verbose implies the debug level.
+	LogLevel string
+
+	// LogFormat is the format of logged messages, [LogFormatText] or [LogFormatJSON].
+	LogFormat string
+
+	// NoColor disables styled output, see [IOStreams.Styler].
+	NoColor bool
+
+	// Theme is the name of the theme used to style output, see [style.LookupTheme].
+	Theme string
+
+	// TerminalOut, if set, reports Out as a terminal regardless of its type,
+	// see [IOStreams.OutIsTerminal], e.g., in tests.
+	TerminalOut bool
+
+	// DebugLog, if set, receives all log messages in the text format,
+	// including debug messages regardless of the log level, e.g., to audit
+	// the verbose output of commands in tests.
+	DebugLog io.Writer
+
+	// logger is initialized by [IOStreams.ConfigureLogger].
+	logger *slog.Logger
+
+	// prompter is set by [IOStreams.SetPrompter].
+	prompter input.Prompter
 }
 
 // NewDefaultIOStreams returns the default IOStreams (using os.Stdin, os.Stdout, os.Stderr).
@@ -51,25 +92,149 @@ func NewTestIOStreamsDiscard(in *TestFdReader) *IOStreams {
 	}
 }
 
+// ConfigureLogger initializes the logger used by [IOStreams.Debugf], [IOStreams.Infof]
+// and [IOStreams.Errorf] according to the LogLevel, LogFormat, Verbose and Quiet settings.
+//
+// Using the text format, info messages are written to the standard output stream
+// and all other messages to the error stream. Using the json format, all messages
+// are written to the error stream, keeping the standard output for command output.
+//
+// Sensitive values registered using [redact.Add] are redacted from all messages,
+// command output written using [IOStreams.Printf] or to Out directly is kept as is.
+func (s *IOStreams) ConfigureLogger() error {
+	level, err := ParseLogLevel(s.LogLevel)
+	if err != nil {
+		return err
+	}
+
+	if s.Verbose && s.Quiet {
+		return errors.New("--quiet cannot be used with --verbose")
+	}
+
+	if s.Verbose {
+		level = min(level, slog.LevelDebug)
+	}
+
+	if s.Quiet {
+		level = max(level, slog.LevelError)
+	}
+
+	s.Verbose = level <= slog.LevelDebug
+
+	if s.LogFormat == LogFormatJSON {
+		h, err := newHandler(s.ErrOut, level, s.LogFormat)
+		if err != nil {
+			return err
+		}
+
+		s.logger = s.newLogger(h)
+
+		return nil
+	}
+
+	if len(s.LogFormat) > 0 && s.LogFormat != LogFormatText {
+		return fmt.Errorf("invalid log format %q: must be one of %s, %s", s.LogFormat, LogFormatText, LogFormatJSON)
+	}
+
+	s.logger = s.newLogger(newStreamHandler(s.Out, s.ErrOut, level))
+
+	return nil
+}
+
+// Logger returns the configured logger, see [IOStreams.ConfigureLogger].
+func (s IOStreams) Logger() *slog.Logger {
+	if s.logger != nil {
+		return s.logger
+	}
+
+	level := slog.LevelInfo
+	if s.Verbose {
+		level = slog.LevelDebug
+	}
+
+	return s.newLogger(newStreamHandler(s.Out, s.ErrOut, level))
+}
+
+// newLogger returns a logger redacting sensitive values, see [redact.Add],
+// passing records to h, and to DebugLog if set.
+func (s IOStreams) newLogger(h slog.Handler) *slog.Logger {
+	if s.DebugLog != nil {
+		h = teeHandler{h, newStreamHandler(s.DebugLog, s.DebugLog, slog.LevelDebug)}
+	}
+
+	return slog.New(newRedactHandler(h))
+}
+
+// SetPrompter sets the [input.Prompter] used to read prompted input,
+// e.g., by integrations prompting using their own dialogs.
+func (s *IOStreams) SetPrompter(p input.Prompter) {
+	s.prompter = p
+}
+
+// Prompter returns the [input.Prompter] set by [IOStreams.SetPrompter],
+// or an [input.TerminalPrompter] prompting via Out and reading from In.
+func (s IOStreams) Prompter() input.Prompter {
+	if s.prompter != nil {
+		return s.prompter
+	}
+
+	return &input.TerminalPrompter{
+		Out: s.Out,
+		In:  s.In,
+		Fd:  int(s.In.Fd()),
+	}
+}
+
+// Styler returns a [style.Styler] for output written to w.
+//
+// Output is styled only if NoColor is unset and w is a terminal, see [style.Enabled].
+// An unknown Theme falls back to the default theme.
+func (s IOStreams) Styler(w io.Writer) *style.Styler {
+	theme, err := style.LookupTheme(s.Theme)
+	if err != nil {
+		theme = style.Themes[style.DefaultTheme]
+	}
+
+	return style.New(theme, !s.NoColor && style.Enabled(w))
+}
+
+// OutIsTerminal reports whether Out is an interactive terminal, where output
+// remains in its scrollback, rather than piped or redirected.
+func (s IOStreams) OutIsTerminal() bool {
+	if s.TerminalOut {
+		return true
+	}
+
+	f, ok := s.Out.(*os.File)
+
+	return ok && term.IsTerminal(int(f.Fd())) //nolint:gosec // fd fits in int.
+}
+
 // Printf writes a general, unprefixed formatted message to the standard output stream.
 func (s IOStreams) Printf(format string, args ...any) {
 	fmt.Fprintf(s.Out, format, args...)
 }
 
-// Debugf writes formatted debug output to the error stream.
-// if Verbose is enabled.
+// Debugf logs a formatted message at the debug level.
 func (s IOStreams) Debugf(format string, args ...any) {
-	if s.Verbose {
-		fmt.Fprintf(s.ErrOut, "DEBUG "+format, args...)
-	}
+	s.logf(slog.LevelDebug, format, args...)
 }
 
-// Infof writes a formatted message to the standard output stream.
+// Infof logs a formatted message at the info level.
 func (s IOStreams) Infof(format string, args ...any) {
-	fmt.Fprintf(s.Out, "INFO "+format, args...)
+	s.logf(slog.LevelInfo, format, args...)
 }
 
-// Errorf writes a formatted message to the error stream.
+// Errorf logs a formatted message at the warn level.
 func (s IOStreams) Errorf(format string, args ...any) {
-	fmt.Fprintf(s.ErrOut, "WARN "+format, args...)
+	s.logf(slog.LevelWarn, format, args...)
+}
+
+func (s IOStreams) logf(level slog.Level, format string, args ...any) {
+	logger, ctx := s.Logger(), context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	logger.Log(ctx, level, strings.TrimSuffix(fmt.Sprintf(format, args...), "\n"))
 }
@@ -0,0 +1,237 @@
+package genericclioptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/ladzaretti/vlt-cli/redact"
+)
+
+// Supported log formats.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// ParseLogLevel parses a log level name, one of debug, info, warn or error.
+//
+// An empty name is parsed as [slog.LevelInfo].
+func ParseLogLevel(name string) (slog.Level, error) {
+	var level slog.Level
+
+	if len(name) == 0 {
+		return slog.LevelInfo, nil
+	}
+
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: must be one of debug, info, warn, error", name)
+	}
+
+	return level, nil
+}
+
+// NewLogger returns a logger writing records at or above the given level to w
+// using the given format, [LogFormatText] or [LogFormatJSON].
+//
+// The text format is the one produced by [slog.TextHandler].
+// Sensitive values registered using [redact.Add] are redacted.
+func NewLogger(w io.Writer, level slog.Leveler, format string) (*slog.Logger, error) {
+	h, err := newHandler(w, level, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return slog.New(newRedactHandler(h)), nil
+}
+
+func newHandler(w io.Writer, level slog.Leveler, format string) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch format {
+	case LogFormatText, "":
+		return slog.NewTextHandler(w, opts), nil
+	case LogFormatJSON:
+		return slog.NewJSONHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be one of %s, %s", format, LogFormatText, LogFormatJSON)
+	}
+}
+
+// streamHandler is the [slog.Handler] used for the human-readable cli output.
+//
+// Records are written as "LEVEL message", followed by their attributes, if any.
+// Info records are written to out, as they are part of the regular command output,
+// all other records are diagnostics and are written to errOut.
+type streamHandler struct {
+	out, errOut io.Writer
+	level       slog.Leveler
+	attrs       []slog.Attr
+
+	mu *sync.Mutex
+}
+
+var _ slog.Handler = &streamHandler{}
+
+func newStreamHandler(out, errOut io.Writer, level slog.Leveler) *streamHandler {
+	return &streamHandler{
+		out:    out,
+		errOut: errOut,
+		level:  level,
+		mu:     &sync.Mutex{},
+	}
+}
+
+func (h *streamHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *streamHandler) Handle(_ context.Context, r slog.Record) error {
+	var sb strings.Builder
+
+	sb.WriteString(r.Level.String())
+	sb.WriteString(" ")
+	sb.WriteString(r.Message)
+
+	appendAttr := func(a slog.Attr) bool {
+		fmt.Fprintf(&sb, " %s=%v", a.Key, a.Value)
+		return true
+	}
+
+	for _, a := range h.attrs {
+		appendAttr(a)
+	}
+
+	r.Attrs(appendAttr)
+
+	sb.WriteString("\n")
+
+	w := h.errOut
+	if r.Level == slog.LevelInfo {
+		w = h.out
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+func (h *streamHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)
+
+	return &h2
+}
+
+// WithGroup is not supported; groups are flattened into the handler attributes.
+func (h *streamHandler) WithGroup(string) slog.Handler { return h }
+
+// redactHandler is a [slog.Handler] replacing the sensitive values registered
+// using [redact.Add] in the messages and attributes of records,
+// before passing them to the wrapped handler.
+type redactHandler struct {
+	slog.Handler
+}
+
+var _ slog.Handler = redactHandler{}
+
+func newRedactHandler(h slog.Handler) slog.Handler {
+	return redactHandler{Handler: h}
+}
+
+func (h redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, redact.String(r.Message), r.PC)
+
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(a))
+		return true
+	})
+
+	return h.Handler.Handle(ctx, redacted)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(a)
+	}
+
+	return redactHandler{Handler: h.Handler.WithAttrs(redacted)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// redactAttr redacts the value of a, formatting values of kind any as strings.
+func redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+
+	switch v.Kind() {
+	case slog.KindString, slog.KindAny:
+		return slog.String(a.Key, redact.String(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+
+		redacted := make([]any, len(group))
+		for i, g := range group {
+			redacted[i] = redactAttr(g)
+		}
+
+		return slog.Group(a.Key, redacted...)
+	default:
+		return a
+	}
+}
+
+// teeHandler is a [slog.Handler] passing records to each of its handlers enabled for them.
+type teeHandler []slog.Handler
+
+var _ slog.Handler = teeHandler{}
+
+func (h teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, hh := range h {
+		if hh.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+
+	for _, hh := range h {
+		if hh.Enabled(ctx, r.Level) {
+			errs = append(errs, hh.Handle(ctx, r.Clone()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := make(teeHandler, len(h))
+	for i, hh := range h {
+		h2[i] = hh.WithAttrs(attrs)
+	}
+
+	return h2
+}
+
+func (h teeHandler) WithGroup(name string) slog.Handler {
+	h2 := make(teeHandler, len(h))
+	for i, hh := range h {
+		h2[i] = hh.WithGroup(name)
+	}
+
+	return h2
+}
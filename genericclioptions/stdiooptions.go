@@ -20,6 +20,10 @@ var _ BaseOptions = &StdioOptions{}
 
 // Complete sets default values, e.g., enabling Stdin if piped input is detected.
 func (o *StdioOptions) Complete() error {
+	if err := o.ConfigureLogger(); err != nil {
+		return err
+	}
+
 	if !o.StdinIsPiped {
 		fi, err := o.In.Stat()
 		if err != nil {
@@ -0,0 +1,201 @@
+// Package hookscript runs vault lifecycle hooks written in Starlark,
+// a small Python dialect, as an alternative to hook commands. Scripts
+// behave the same on every platform, they depend on neither a shell
+// nor its quoting rules.
+//
+// A script defines a function per hook, named after the hook with
+// underscores, e.g., post_write(event). Hooks the script does not define
+// are skipped. The event argument holds the hook name (event.name), the
+// vault path (event.vault) and the vlt command that triggered the hook
+// (event.command).
+//
+// Scripts cannot access files, the network or other processes, and
+// load statements are not supported. In addition to the Starlark
+// built-ins, e.g., print, scripts can use:
+//
+//	env(name, default=None)
+//	    returns the value of an environment variable, or default if unset.
+//
+//	exec(args, dir="", capture=False)
+//	    runs the command args, e.g., ["git", "push"], failing the hook
+//	    if it fails. The command output is returned if capture is set,
+//	    and written to the vlt output otherwise. Only available if
+//	    running commands is allowed, see [New].
+package hookscript
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// ErrExecNotAllowed is returned by the exec built-in
+// of scripts that are not allowed to run commands.
+var ErrExecNotAllowed = errors.New("exec is not allowed")
+
+// fileOptions enables the Starlark features a hook script may reasonably use.
+var fileOptions = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+}
+
+// Event describes the vault event that triggered a hook.
+type Event struct {
+	Name      string // Name is the hook name, e.g., "post-write".
+	VaultPath string
+	Command   string // Command is the vlt command that triggered the hook, e.g., "update secret".
+}
+
+// Script is a hook script file.
+type Script struct {
+	path      string
+	allowExec bool
+}
+
+// New returns the hook script at path.
+// If allowExec is set, the script can run commands using exec.
+func New(path string, allowExec bool) *Script {
+	return &Script{path: path, allowExec: allowExec}
+}
+
+// Path returns the path of the script file.
+func (s *Script) Path() string { return s.path }
+
+// Run calls the function of the event hook, with the command output written
+// to stdout and stderr. It reports whether the script defines the hook,
+// scripts are executed anew on each run.
+func (s *Script) Run(ctx context.Context, stdout, stderr io.Writer, e Event) (bool, error) {
+	src, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, err
+	}
+
+	thread := &starlark.Thread{
+		Name:  e.Name,
+		Print: func(_ *starlark.Thread, msg string) { _, _ = fmt.Fprintln(stdout, msg) },
+	}
+
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	defer stop()
+
+	predeclared := starlark.StringDict{
+		"env":  starlark.NewBuiltin("env", envBuiltin),
+		"exec": starlark.NewBuiltin("exec", s.execBuiltin(ctx, stdout, stderr)),
+	}
+
+	globals, err := starlark.ExecFileOptions(fileOptions, thread, s.path, src, predeclared)
+	if err != nil {
+		return false, scriptError(err)
+	}
+
+	fn, ok := globals[strings.ReplaceAll(e.Name, "-", "_")]
+	if !ok {
+		return false, nil
+	}
+
+	if _, ok := fn.(starlark.Callable); !ok {
+		return false, fmt.Errorf("%s: %s is not a function", s.path, fn.String())
+	}
+
+	event := starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"name":    starlark.String(e.Name),
+		"vault":   starlark.String(e.VaultPath),
+		"command": starlark.String(e.Command),
+	})
+
+	if _, err := starlark.Call(thread, fn, starlark.Tuple{event}, nil); err != nil {
+		return true, scriptError(err)
+	}
+
+	return true, nil
+}
+
+// scriptError includes the Starlark backtrace of evaluation errors.
+func scriptError(err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return errors.New(evalErr.Backtrace())
+	}
+
+	return err
+}
+
+func envBuiltin(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		name string
+		def  starlark.Value = starlark.None
+	)
+
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "default?", &def); err != nil {
+		return nil, err
+	}
+
+	if v, ok := os.LookupEnv(name); ok {
+		return starlark.String(v), nil
+	}
+
+	return def, nil
+}
+
+func (s *Script) execBuiltin(ctx context.Context, stdout, stderr io.Writer) func(*starlark.Thread, *starlark.Builtin, starlark.Tuple, []starlark.Tuple) (starlark.Value, error) {
+	return func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var (
+			argv    *starlark.List
+			dir     string
+			capture bool
+		)
+
+		if err := starlark.UnpackArgs(b.Name(), args, kwargs, "args", &argv, "dir?", &dir, "capture?", &capture); err != nil {
+			return nil, err
+		}
+
+		if !s.allowExec {
+			return nil, fmt.Errorf("%s: %w, set 'script_allow_exec' in the [hooks] section of the config file", b.Name(), ErrExecNotAllowed)
+		}
+
+		if argv.Len() == 0 {
+			return nil, fmt.Errorf("%s: empty args", b.Name())
+		}
+
+		cmdArgs := make([]string, argv.Len())
+
+		for i := range argv.Len() {
+			v, ok := starlark.AsString(argv.Index(i))
+			if !ok {
+				return nil, fmt.Errorf("%s: args[%d]: got %s, want string", b.Name(), i, argv.Index(i).Type())
+			}
+
+			cmdArgs[i] = v
+		}
+
+		var out bytes.Buffer
+
+		cmd := exec.CommandContext(ctx, cmdArgs[0], cmdArgs[1:]...) //nolint:gosec // commands are run by the user's own script
+		cmd.Dir = dir
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+
+		if capture {
+			cmd.Stdout = &out
+		}
+
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("%s: %q: %w", b.Name(), cmdArgs, err)
+		}
+
+		if capture {
+			return starlark.String(out.String()), nil
+		}
+
+		return starlark.None, nil
+	}
+}
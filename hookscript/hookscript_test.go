@@ -0,0 +1,125 @@
+package hookscript_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/hookscript"
+)
+
+func TestRun(t *testing.T) {
+	t.Setenv("VLT_TEST_HOOK_ENV", "from-env")
+
+	event := hookscript.Event{Name: "post-write", VaultPath: "/tmp/vault.db", Command: "update secret"}
+
+	tests := []struct {
+		name      string
+		script    string
+		allowExec bool
+		wantRan   bool
+		wantOut   string
+		wantErr   string
+	}{
+		{
+			name:    "event",
+			script:  "def post_write(event):\n    print(event.name, event.vault, event.command)\n",
+			wantRan: true,
+			wantOut: "post-write /tmp/vault.db update secret\n",
+		},
+		{
+			name:   "undefined hook",
+			script: "def post_login(event):\n    fail('unexpected')\n",
+		},
+		{
+			name:    "env",
+			script:  "def post_write(event):\n    print(env('VLT_TEST_HOOK_ENV'), env('VLT_TEST_HOOK_UNSET', 'default'))\n",
+			wantRan: true,
+			wantOut: "from-env default\n",
+		},
+		{
+			name:    "exec not allowed",
+			script:  "def post_write(event):\n    exec(['true'])\n",
+			wantRan: true,
+			wantErr: "exec is not allowed",
+		},
+		{
+			name:      "exec capture",
+			script:    "def post_write(event):\n    print(exec(['echo', 'hi'], capture=True).strip())\n",
+			allowExec: true,
+			wantRan:   true,
+			wantOut:   "hi\n",
+		},
+		{
+			name:      "exec failure",
+			script:    "def post_write(event):\n    exec(['false'])\n",
+			allowExec: true,
+			wantRan:   true,
+			wantErr:   "exit status 1",
+		},
+		{
+			name:    "fail",
+			script:  "def post_write(event):\n    fail('boom')\n",
+			wantRan: true,
+			wantErr: "boom",
+		},
+		{
+			name:    "not a function",
+			script:  "post_write = 1\n",
+			wantErr: "is not a function",
+		},
+		{
+			name:    "load",
+			script:  "load('other.star', 'x')\n",
+			wantErr: "load not implemented",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "hooks.star")
+			if err := os.WriteFile(path, []byte(tt.script), 0o600); err != nil {
+				t.Fatalf("write script: %v", err)
+			}
+
+			var stdout, stderr bytes.Buffer
+
+			ran, err := hookscript.New(path, tt.allowExec).Run(t.Context(), &stdout, &stderr, event)
+
+			switch {
+			case len(tt.wantErr) > 0 && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("want error containing %q, got %v", tt.wantErr, err)
+			case len(tt.wantErr) == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ran != tt.wantRan {
+				t.Errorf("want ran %t, got %t", tt.wantRan, ran)
+			}
+
+			if got := stdout.String(); got != tt.wantOut {
+				t.Errorf("want output %q, got %q", tt.wantOut, got)
+			}
+		})
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hooks.star")
+	if err := os.WriteFile(path, []byte("def post_write(event):\n    while True:\n        pass\n"), 0o600); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	var out bytes.Buffer
+
+	if _, err := hookscript.New(path, false).Run(ctx, &out, &out, hookscript.Event{Name: "post-write"}); err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("want the script canceled, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+// Package i18n translates user-facing messages.
+//
+// Messages are identified by their English text, which is also used
+// when no translation is available. Translations are read from the
+// embedded message catalogs in the locales directory, one JSON object
+// per language mapping English messages to their translation.
+//
+// The locale is selected once at startup, see [SetLocale] and [DetectLocale].
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// DefaultLocale is the locale of the untranslated messages.
+const DefaultLocale = "en"
+
+// ErrUnsupportedLocale is returned when no catalog exists for a locale.
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+var (
+	// locale is the active locale, see [SetLocale].
+	locale = DefaultLocale
+
+	// catalog maps English messages to their translation in the active locale.
+	catalog map[string]string
+)
+
+// T returns the translation of msg in the active locale,
+// or msg itself if it has no translation.
+//
+// Format verbs in msg are kept by its translations,
+// so T can be used to translate format strings.
+func T(msg string) string {
+	if s, ok := catalog[msg]; ok {
+		return s
+	}
+
+	return msg
+}
+
+// Locale returns the active locale.
+func Locale() string { return locale }
+
+// Locales returns the locales with a message catalog, sorted,
+// including the [DefaultLocale].
+func Locales() []string {
+	entries, _ := localesFS.ReadDir("locales")
+
+	locales := []string{DefaultLocale}
+	for _, e := range entries {
+		locales = append(locales, strings.TrimSuffix(e.Name(), ".json"))
+	}
+
+	slices.Sort(locales)
+
+	return locales
+}
+
+// SetLocale activates the message catalog of the given locale,
+// e.g., "de_DE.UTF-8", "de_DE" or "de".
+//
+// The most specific available catalog is used: "de_DE" before "de".
+// The C and POSIX locales select the [DefaultLocale].
+func SetLocale(name string) error {
+	for _, l := range candidates(name) {
+		if l == DefaultLocale {
+			locale, catalog = DefaultLocale, nil
+			return nil
+		}
+
+		c, err := loadCatalog(l)
+		if errors.Is(err, ErrUnsupportedLocale) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		locale, catalog = l, c
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnsupportedLocale, name)
+}
+
+// DetectLocale returns the locale of messages configured in the environment,
+// following the POSIX precedence of LC_ALL, LC_MESSAGES and LANG.
+func DetectLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+
+	return DefaultLocale
+}
+
+// candidates returns the catalog names to try for a locale, most specific first.
+func candidates(name string) []string {
+	// strip the codeset and modifier, e.g., de_DE.UTF-8@euro -> de_DE.
+	name, _, _ = strings.Cut(name, "@")
+	name, _, _ = strings.Cut(name, ".")
+	name = strings.ReplaceAll(name, "-", "_")
+
+	if name == "" || name == "C" || name == "POSIX" {
+		return []string{DefaultLocale}
+	}
+
+	lang, _, _ := strings.Cut(name, "_")
+	lang = strings.ToLower(lang)
+
+	if lang == name {
+		return []string{lang}
+	}
+
+	return []string{name, lang}
+}
+
+func loadCatalog(name string) (map[string]string, error) {
+	raw, err := localesFS.ReadFile(path.Join("locales", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLocale, name)
+	}
+
+	var c map[string]string
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("i18n: parse %s catalog: %w", name, err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestSetLocale(t *testing.T) {
+	t.Cleanup(func() { _ = SetLocale(DefaultLocale) })
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+	}{
+		{name: "de_DE.UTF-8", want: "de"},
+		{name: "de_AT@euro", want: "de"},
+		{name: "de-CH", want: "de"},
+		{name: "de", want: "de"},
+		{name: "C", want: DefaultLocale},
+		{name: "POSIX", want: DefaultLocale},
+		{name: "en_US.UTF-8", want: DefaultLocale},
+		{name: "xx_YY.UTF-8", wantErr: ErrUnsupportedLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = SetLocale(DefaultLocale)
+
+			err := SetLocale(tt.name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				if Locale() != DefaultLocale {
+					t.Errorf("want locale unchanged after error, got %q", Locale())
+				}
+
+				return
+			}
+
+			if Locale() != tt.want {
+				t.Errorf("want locale %q, got %q", tt.want, Locale())
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	t.Cleanup(func() { _ = SetLocale(DefaultLocale) })
+
+	const msg = "Enter password: "
+
+	if got := T(msg); got != msg {
+		t.Errorf("default locale: want %q, got %q", msg, got)
+	}
+
+	if err := SetLocale("de"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := T(msg); got == msg {
+		t.Errorf("de: want a translation of %q", msg)
+	}
+
+	if got, untranslated := T("no such message"), "no such message"; got != untranslated {
+		t.Errorf("want untranslated fallback %q, got %q", untranslated, got)
+	}
+}
+
+func TestDetectLocale(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+
+	if got := DetectLocale(); got != DefaultLocale {
+		t.Errorf("empty environment: want %q, got %q", DefaultLocale, got)
+	}
+
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	t.Setenv("LC_MESSAGES", "de_DE.UTF-8")
+
+	if got := DetectLocale(); got != "de_DE.UTF-8" {
+		t.Errorf("want LC_MESSAGES to take precedence over LANG, got %q", got)
+	}
+
+	t.Setenv("LC_ALL", "C")
+
+	if got := DetectLocale(); got != "C" {
+		t.Errorf("want LC_ALL to take precedence, got %q", got)
+	}
+}
+
+// verbRE matches fmt verbs, with optional flags, width and precision.
+var verbRE = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+// TestCatalogs verifies that every translation keeps the format verbs of its message.
+func TestCatalogs(t *testing.T) {
+	for _, l := range Locales() {
+		if l == DefaultLocale {
+			continue
+		}
+
+		c, err := loadCatalog(l)
+		if err != nil {
+			t.Fatalf("%s: %v", l, err)
+		}
+
+		for msg, translation := range c {
+			want, got := verbRE.FindAllString(msg, -1), verbRE.FindAllString(translation, -1)
+			if !slices.Equal(want, got) {
+				t.Errorf("%s: %q: want verbs %v, got %v", l, msg, want, got)
+			}
+		}
+	}
+}
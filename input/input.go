@@ -1,6 +1,8 @@
 package input
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -8,6 +10,9 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/ladzaretti/vlt-cli/i18n"
+	"github.com/ladzaretti/vlt-cli/securebytes"
+
 	"golang.org/x/term"
 )
 
@@ -15,24 +20,26 @@ import (
 var readPasswordFunc = term.ReadPassword
 
 // SetDefaultReadPassword overrides readPasswordFunc for testing.
+//
+// It disables the live strength meter of new password prompts,
+// which reads from the terminal directly.
 func SetDefaultReadPassword(f func(fd int) ([]byte, error)) {
 	readPasswordFunc = f
+	liveMeter = false
 }
 
 func IsPipedOrRedirected(fi os.FileInfo) bool {
 	return (fi.Mode() & os.ModeCharDevice) == 0
 }
 
-// PromptRead prompts via w for input and reads it from r until a newline is entered.
-func PromptRead(w io.Writer, r io.Reader, prompt string, a ...any) (string, error) {
-	fmt.Fprintf(w, prompt, a...)
-
-	line, err := readUntil(r, '\n')
+// PromptRead prompts via p for a line of input, formatting the translated prompt with a.
+func PromptRead(ctx context.Context, p Prompter, prompt string, a ...any) (string, error) {
+	line, err := p.ReadLine(ctx, fmt.Sprintf(i18n.T(prompt), a...))
 	if err != nil {
 		return "", fmt.Errorf("prompt read: %w", err)
 	}
 
-	return strings.TrimSpace(string(line)), nil
+	return strings.TrimSpace(line), nil
 }
 
 // readUntil reads from r until the given delimiter is found.
@@ -67,55 +74,159 @@ func readUntil(r io.Reader, delim byte) ([]byte, error) {
 	return buf, nil
 }
 
-// PromptReadSecure prompts the user via w for input and securely reads it
-// from the given file descriptor.
-func PromptReadSecure(w io.Writer, fd int, prompt string, a ...any) ([]byte, error) {
-	fmt.Fprintf(w, prompt, a...)
+// PromptReadSecure prompts via p for a secret read without echo,
+// formatting the translated prompt with a.
+func PromptReadSecure(ctx context.Context, p Prompter, prompt string, a ...any) ([]byte, error) {
+	return p.ReadSecret(ctx, fmt.Sprintf(i18n.T(prompt), a...))
+}
 
-	defer fmt.Println()
+// PromptPassword prompts via p for the current password.
+func PromptPassword(ctx context.Context, p Prompter) ([]byte, error) {
+	return PromptReadSecure(ctx, p, "Enter password: ")
+}
 
-	bs, err := readPasswordFunc(fd)
-	if err != nil {
-		return nil, fmt.Errorf("term read password: %w", err)
+// NewPasswordPolicy defines the requirements of a new password.
+type NewPasswordPolicy struct {
+	MinLength  int
+	MinClasses int  // MinClasses is the minimum number of character classes used: lower case, upper case, digits and symbols.
+	MinBits    int  // MinBits is the minimum estimated strength in bits, see [EstimateStrength].
+	WeakOK     bool // WeakOK accepts a password below MinBits with a warning instead of refusing it.
+}
+
+// Check returns an error if pw does not satisfy the policy, e.g., a new password
+// read non-interactively. Weak passwords accepted by WeakOK are not reported.
+func (policy NewPasswordPolicy) Check(pw []byte) error {
+	if len(pw) < policy.MinLength {
+		return fmt.Errorf("password must be at least %d characters", policy.MinLength)
 	}
 
-	return bs, nil
-}
+	if classesOf(pw).count() < policy.MinClasses {
+		return fmt.Errorf("password must use at least %d of lower case, upper case, digits and symbols", policy.MinClasses)
+	}
 
-// PromptPassword prompts the user to enter the current password securely.
-// The prompt is displayed via the writer w, and input is read from the
-// given file descriptor fd.
-func PromptPassword(w io.Writer, fd int) ([]byte, error) {
-	return PromptReadSecure(w, fd, "Enter password: ")
+	if strength := EstimateStrength(pw); strength.Bits < policy.MinBits && !policy.WeakOK {
+		return fmt.Errorf("password too weak, %s, at least %d bits required", strength, policy.MinBits)
+	}
+
+	return nil
 }
 
-// PromptNewPassword prompts the user to enter a new password of the specified length.
-// The prompt is displayed via the writer w, and input is read from the given file descriptor fd.
-func PromptNewPassword(w io.Writer, fd int, length int) ([]byte, error) {
-	var pass []byte
+// PromptNewPassword prompts via p for a new password satisfying the given policy,
+// re-prompting until it does, and to retype it.
+//
+// On a terminal, the estimated strength of the password is shown while it is typed.
+//
+// If the retyped password differs from the first by a single character,
+// the mismatch is taken for a typo and both are prompted for again.
+func PromptNewPassword(ctx context.Context, p Prompter, policy NewPasswordPolicy) ([]byte, error) {
+	for {
+		pass, err := promptPolicyPassword(ctx, p, policy)
+		if err != nil {
+			return nil, fmt.Errorf("prompt new password: %w", err)
+		}
 
-	for len(pass) < length {
-		p, err := PromptReadSecure(w, fd, "Enter new password: ")
+		pass2, err := PromptReadSecure(ctx, p, "Retype password: ")
 		if err != nil {
+			securebytes.Wipe(pass)
 			return nil, fmt.Errorf("prompt new password: %w", err)
 		}
 
-		pass = p
+		if slices.Compare(pass2, pass) == 0 {
+			securebytes.Wipe(pass2)
+			return pass, nil
+		}
+
+		nearMiss := withinOneEdit(pass, pass2)
+
+		securebytes.Wipe(pass)
+		securebytes.Wipe(pass2)
 
-		if len(pass) < length {
-			fmt.Fprintf(w, "Password must be at least %d characters. Please try again.\n", length)
+		if nearMiss {
+			p.Notify(ctx, i18n.T("Passwords differ by a single character, likely a typo. Please try again."))
+			continue
 		}
+
+		p.Notify(ctx, i18n.T("Passwords do not match. Please try again."))
+
+		return nil, errors.New("prompt new password: passwords do not match")
 	}
+}
 
-	pass2, err := PromptReadSecure(w, fd, "Retype password: ")
-	if err != nil {
-		return nil, fmt.Errorf("prompt new password: %w", err)
+// promptPolicyPassword prompts for a new password until one satisfies the policy.
+func promptPolicyPassword(ctx context.Context, p Prompter, policy NewPasswordPolicy) ([]byte, error) {
+	for {
+		pass, err := readNewPassword(ctx, p, "Enter new password: ")
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pass) < policy.MinLength {
+			securebytes.Wipe(pass)
+			p.Notify(ctx, fmt.Sprintf(i18n.T("Password must be at least %d characters. Please try again.\n"), policy.MinLength))
+
+			continue
+		}
+
+		if classesOf(pass).count() < policy.MinClasses {
+			securebytes.Wipe(pass)
+			p.Notify(ctx, fmt.Sprintf(i18n.T("Password must use at least %d of lower case, upper case, digits and symbols. Please try again.\n"), policy.MinClasses))
+
+			continue
+		}
+
+		strength := EstimateStrength(pass)
+		if strength.Bits >= policy.MinBits {
+			return pass, nil
+		}
+
+		if policy.WeakOK {
+			p.Notify(ctx, fmt.Sprintf(i18n.T("Warning: weak password accepted, %s, at least %d bits recommended.\n"), strength, policy.MinBits))
+			return pass, nil
+		}
+
+		securebytes.Wipe(pass)
+		p.Notify(ctx, fmt.Sprintf(i18n.T("Password too weak, %s, at least %d bits required. Please try again.\n"), strength, policy.MinBits))
 	}
+}
 
-	if slices.Compare(pass2, pass) != 0 {
-		fmt.Fprintln(w, "Passwords do not match. Please try again.")
-		return nil, errors.New("prompt new password: passwords do not match")
+// withinOneEdit reports whether a and b are at most one character
+// insertion, deletion or substitution apart.
+func withinOneEdit(a, b []byte) bool {
+	ra, rb := bytes.Runes(a), bytes.Runes(b)
+	defer func() { //nolint:wsl_v5
+		clear(ra)
+		clear(rb)
+	}()
+
+	if len(ra) > len(rb) {
+		ra, rb = rb, ra
+	}
+
+	if len(rb)-len(ra) > 1 {
+		return false
+	}
+
+	i := 0
+	for i < len(ra) && ra[i] == rb[i] {
+		i++
+	}
+
+	if i == len(ra) {
+		return true
+	}
+
+	if len(ra) == len(rb) {
+		return slices.Equal(ra[i+1:], rb[i+1:])
+	}
+
+	return slices.Equal(ra[i:], rb[i+1:])
+}
+
+// readNewPassword reads a new password, with the live strength meter on a terminal.
+func readNewPassword(ctx context.Context, p Prompter, prompt string) ([]byte, error) {
+	if t, ok := p.(*TerminalPrompter); ok && liveMeter && term.IsTerminal(t.Fd) {
+		return readPasswordWithMeter(t.Out, t.Fd, i18n.T(prompt))
 	}
 
-	return pass, nil
+	return PromptReadSecure(ctx, p, prompt)
 }
@@ -0,0 +1,107 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/ladzaretti/vlt-cli/securebytes"
+
+	"golang.org/x/term"
+)
+
+// ErrInterrupted indicates that the user interrupted a prompt with Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+// liveMeter enables the strength meter shown while a new password is typed.
+// It is disabled when reading passwords is overridden, see [SetDefaultReadPassword].
+var liveMeter = true
+
+const (
+	keyInterrupt = 0x03 // Ctrl-C
+	keyEOF       = 0x04 // Ctrl-D
+	keyBackspace = 0x08 // Ctrl-H
+	keyKill      = 0x15 // Ctrl-U
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+
+	// maxPasswordLen bounds the input read by the meter prompt.
+	maxPasswordLen = 4096
+)
+
+// readPasswordWithMeter reads a password from the terminal at fd without echo,
+// redrawing the prompt followed by the estimated strength of the input
+// after each keystroke.
+func readPasswordWithMeter(w io.Writer, fd int, prompt string) (_ []byte, retErr error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { //nolint:wsl_v5
+		retErr = errors.Join(retErr, term.Restore(fd, state))
+	}()
+
+	// the terminal is not closed, it is owned by the caller.
+	f := os.NewFile(uintptr(fd), "terminal")
+
+	buf := make([]byte, 0, maxPasswordLen)
+	key := make([]byte, 1)
+	escape := false
+
+	render := func() {
+		fmt.Fprintf(w, "\r\x1b[K%s", prompt)
+
+		if len(buf) > 0 {
+			fmt.Fprintf(w, "[%s] ", EstimateStrength(buf))
+		}
+	}
+
+	render()
+
+	for {
+		if _, err := f.Read(key); err != nil {
+			securebytes.Wipe(buf)
+			return nil, err
+		}
+
+		c := key[0]
+
+		switch {
+		case escape:
+			// skip escape sequences, e.g., arrow keys, up to their final byte.
+			escape = c == '[' || c == 'O' || (c >= 0x20 && c < 0x40)
+			continue
+		case c == '\r' || c == '\n':
+			fmt.Fprintf(w, "\r\x1b[K%s[%s]\r\n", prompt, EstimateStrength(buf))
+			return buf, nil
+		case c == keyInterrupt:
+			securebytes.Wipe(buf)
+			fmt.Fprint(w, "\r\n")
+
+			return nil, ErrInterrupted
+		case c == keyEOF && len(buf) == 0:
+			fmt.Fprint(w, "\r\n")
+			return nil, io.EOF
+		case c == keyBackspace || c == keyDelete:
+			if len(buf) > 0 {
+				_, size := utf8.DecodeLastRune(buf)
+				securebytes.Wipe(buf[len(buf)-size:])
+				buf = buf[:len(buf)-size]
+			}
+		case c == keyKill:
+			securebytes.Wipe(buf)
+			buf = buf[:0]
+		case c == keyEscape:
+			escape = true
+			continue
+		case c < 0x20:
+			continue
+		case len(buf) < maxPasswordLen:
+			buf = append(buf, c)
+		}
+
+		render()
+	}
+}
@@ -0,0 +1,172 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/pinentry"
+)
+
+// Prompter reads input in response to prompts, e.g., from a terminal
+// or a desktop dialog.
+//
+// Integrations supply their own Prompter instead of the default
+// [TerminalPrompter], e.g., using genericclioptions.IOStreams.SetPrompter.
+type Prompter interface {
+	// ReadLine prompts for a line of input.
+	ReadLine(ctx context.Context, prompt string) (string, error)
+
+	// ReadSecret prompts for a secret, e.g., a password, read without echo.
+	ReadSecret(ctx context.Context, prompt string) ([]byte, error)
+
+	// Notify shows msg to the user, e.g., why the previous input was rejected.
+	Notify(ctx context.Context, msg string)
+}
+
+// TerminalPrompter prompts via Out, reading lines from In,
+// and secrets from the terminal file descriptor Fd.
+type TerminalPrompter struct {
+	Out io.Writer
+	In  io.Reader
+	Fd  int
+}
+
+var _ Prompter = &TerminalPrompter{}
+
+// ReadLine writes prompt to Out and reads from In until a newline is entered.
+func (p *TerminalPrompter) ReadLine(ctx context.Context, prompt string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	fmt.Fprint(p.Out, prompt)
+
+	line, err := readUntil(p.In, '\n')
+	if err != nil {
+		return "", err
+	}
+
+	return string(line), nil
+}
+
+// ReadSecret writes prompt to Out and reads from the terminal Fd without echo.
+func (p *TerminalPrompter) ReadSecret(ctx context.Context, prompt string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprint(p.Out, prompt)
+
+	defer fmt.Println()
+
+	bs, err := readPasswordFunc(p.Fd)
+	if err != nil {
+		return nil, fmt.Errorf("term read password: %w", err)
+	}
+
+	return bs, nil
+}
+
+// Notify writes msg to Out, on its own line.
+func (p *TerminalPrompter) Notify(_ context.Context, msg string) {
+	fmt.Fprintln(p.Out, strings.TrimSuffix(msg, "\n"))
+}
+
+// PinentryPrompter prompts using desktop or console dialogs of
+// a pinentry program, see [pinentry.GetPIN].
+//
+// pinentry has no plain text input, lines are read masked as well.
+type PinentryPrompter struct {
+	Program string // Program is the pinentry program, [pinentry.DefaultProgram] if empty.
+	Title   string // Title is the window title of the dialogs.
+
+	// notice is shown along the next dialog, see [PinentryPrompter.Notify].
+	notice string
+}
+
+var _ Prompter = &PinentryPrompter{}
+
+// ReadLine shows prompt in a pinentry dialog and returns the entered line.
+func (p *PinentryPrompter) ReadLine(ctx context.Context, prompt string) (string, error) {
+	line, err := p.ReadSecret(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	return string(line), nil
+}
+
+// ReadSecret shows prompt in a pinentry dialog and returns the entered secret.
+func (p *PinentryPrompter) ReadSecret(ctx context.Context, prompt string) ([]byte, error) {
+	program := p.Program
+	if len(program) == 0 {
+		program = pinentry.DefaultProgram
+	}
+
+	notice := p.notice
+	p.notice = ""
+
+	return pinentry.GetPIN(ctx, program, pinentry.Dialog{
+		Title: p.Title,
+		Desc:  strings.TrimSpace(prompt),
+		Error: notice,
+	})
+}
+
+// Notify shows msg along the next dialog, as pinentry has no message dialog
+// of its own.
+func (p *PinentryPrompter) Notify(_ context.Context, msg string) {
+	p.notice = strings.TrimSpace(p.notice + "\n" + msg)
+}
+
+// ErrNoTestInput is returned by a [TestPrompter] out of scripted input.
+var ErrNoTestInput = errors.New("no scripted input left")
+
+// TestPrompter answers prompts with scripted input, recording the prompts
+// and notifications, for use in tests.
+type TestPrompter struct {
+	Lines   []string // Lines are returned by ReadLine, in order.
+	Secrets [][]byte // Secrets are returned by ReadSecret, in order.
+
+	Prompts  []string // Prompts records the prompts read so far.
+	Messages []string // Messages records the notified messages.
+}
+
+var _ Prompter = &TestPrompter{}
+
+// ReadLine returns the next scripted line.
+func (p *TestPrompter) ReadLine(_ context.Context, prompt string) (string, error) {
+	p.Prompts = append(p.Prompts, prompt)
+
+	if len(p.Lines) == 0 {
+		return "", ErrNoTestInput
+	}
+
+	line := p.Lines[0]
+	p.Lines = p.Lines[1:]
+
+	return line, nil
+}
+
+// ReadSecret returns a copy of the next scripted secret.
+func (p *TestPrompter) ReadSecret(_ context.Context, prompt string) ([]byte, error) {
+	p.Prompts = append(p.Prompts, prompt)
+
+	if len(p.Secrets) == 0 {
+		return nil, ErrNoTestInput
+	}
+
+	secret := slices.Clone(p.Secrets[0])
+	p.Secrets = p.Secrets[1:]
+
+	return secret, nil
+}
+
+// Notify records msg.
+func (p *TestPrompter) Notify(_ context.Context, msg string) {
+	p.Messages = append(p.Messages, msg)
+}
@@ -0,0 +1,59 @@
+package input_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/input"
+
+	gocmp "github.com/google/go-cmp/cmp"
+)
+
+func TestPromptNewPassword(t *testing.T) {
+	policy := input.NewPasswordPolicy{MinLength: 8, MinClasses: 2}
+
+	p := &input.TestPrompter{Secrets: [][]byte{
+		[]byte("short"),
+		[]byte("lowercaseonly"),
+		[]byte("Correct horse"),
+		[]byte("Correct hors"), // a typo re-prompts for both
+		[]byte("Correct horse"),
+		[]byte("Correct horse"),
+	}}
+
+	got, err := input.PromptNewPassword(t.Context(), p, policy)
+	if err != nil {
+		t.Fatalf("prompt new password: %v", err)
+	}
+
+	if string(got) != "Correct horse" {
+		t.Errorf("got %q, want %q", got, "Correct horse")
+	}
+
+	wantPrompts := []string{
+		"Enter new password: ",
+		"Enter new password: ",
+		"Enter new password: ",
+		"Retype password: ",
+		"Enter new password: ",
+		"Retype password: ",
+	}
+
+	if diff := gocmp.Diff(wantPrompts, p.Prompts); diff != "" {
+		t.Errorf("prompts mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(p.Messages) != 3 {
+		t.Errorf("want 3 notifications of rejected input, got %q", p.Messages)
+	}
+
+	p = &input.TestPrompter{Secrets: [][]byte{[]byte("Correct horse"), []byte("different")}}
+
+	if _, err := input.PromptNewPassword(t.Context(), p, policy); err == nil {
+		t.Error("want an error for mismatching passwords")
+	}
+
+	if _, err := input.PromptPassword(t.Context(), &input.TestPrompter{}); !errors.Is(err, input.ErrNoTestInput) {
+		t.Errorf("want %v, got %v", input.ErrNoTestInput, err)
+	}
+}
@@ -0,0 +1,158 @@
+package input
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Strength levels of a password, by estimated entropy in bits.
+const (
+	weakBits       = 28
+	fairBits       = 36
+	strongBits     = 60
+	veryStrongBits = 128
+)
+
+// commonPasswords are frequently used passwords, guessed first by any attacker.
+// A password matching one of them, ignoring case and trailing digits or symbols,
+// is estimated at a few bits only.
+var commonPasswords = []string{
+	"password", "passw0rd", "p@ssw0rd", "qwerty", "qwertyuiop", "asdfgh", "zxcvbn",
+	"letmein", "welcome", "admin", "administrator", "root", "login", "master",
+	"iloveyou", "monkey", "dragon", "football", "baseball", "sunshine", "princess",
+	"shadow", "superman", "batman", "trustno1", "starwars", "secret", "changeme",
+	"abc", "abcd", "abcdef", "qwe", "test", "default", "hello", "freedom", "whatever",
+}
+
+// commonPasswordBits is the estimated strength of a common password.
+const commonPasswordBits = 10
+
+// Strength is an estimate of the entropy of a password.
+type Strength struct {
+	Bits int
+}
+
+// EstimateStrength estimates the entropy of pw in bits.
+//
+// The estimate assumes each character is drawn from the pool of the character
+// classes used (lower case, upper case, digits, symbols, other), discounting
+// repeated characters and sequences such as "aaaa" or "1234",
+// and common passwords. It is an upper bound: a password built from
+// dictionary words is weaker than estimated.
+func EstimateStrength(pw []byte) Strength {
+	if len(pw) == 0 {
+		return Strength{}
+	}
+
+	if isCommonPassword(string(pw)) {
+		return Strength{Bits: commonPasswordBits}
+	}
+
+	c := classesOf(pw)
+
+	pool := 0
+
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{c.lower, 26}, {c.upper, 26}, {c.digit, 10}, {c.symbol, 33}, {c.other, 100}} {
+		if class.used {
+			pool += class.size
+		}
+	}
+
+	perChar := math.Log2(float64(pool))
+
+	// the first character of a run of repeated or consecutive characters,
+	// e.g., "aaaa" or "1234", counts in full, the following ones one bit each.
+	bits, prev := 0.0, rune(-1)
+
+	for _, r := range string(pw) {
+		if d := r - prev; prev >= 0 && d >= -1 && d <= 1 {
+			bits++
+		} else {
+			bits += perChar
+		}
+
+		prev = r
+	}
+
+	return Strength{Bits: int(bits)}
+}
+
+// charClasses holds the character classes used by a password.
+type charClasses struct {
+	lower, upper, digit, symbol, other bool
+}
+
+func classesOf(pw []byte) charClasses {
+	var c charClasses
+
+	for _, r := range string(pw) {
+		switch {
+		case r >= 'a' && r <= 'z':
+			c.lower = true
+		case r >= 'A' && r <= 'Z':
+			c.upper = true
+		case r >= '0' && r <= '9':
+			c.digit = true
+		case r < utf8.RuneSelf && unicode.IsPrint(r):
+			c.symbol = true
+		default:
+			c.other = true
+		}
+	}
+
+	return c
+}
+
+// count returns the number of classes used,
+// symbols and non-ASCII characters count as a single class.
+func (c charClasses) count() int {
+	n := 0
+
+	for _, used := range []bool{c.lower, c.upper, c.digit, c.symbol || c.other} {
+		if used {
+			n++
+		}
+	}
+
+	return n
+}
+
+func isCommonPassword(pw string) bool {
+	base := strings.TrimRightFunc(strings.ToLower(pw), func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+
+	if len(base) == 0 {
+		return false
+	}
+
+	return slices.Contains(commonPasswords, base)
+}
+
+// Level returns a short description of the strength.
+func (s Strength) Level() string {
+	switch {
+	case s.Bits < weakBits:
+		return "very weak"
+	case s.Bits < fairBits:
+		return "weak"
+	case s.Bits < strongBits:
+		return "fair"
+	case s.Bits < veryStrongBits:
+		return "strong"
+	default:
+		return "very strong"
+	}
+}
+
+// String returns the level and estimated bits, e.g., "fair (~42 bits)".
+func (s Strength) String() string {
+	return fmt.Sprintf("%s (~%d bits)", s.Level(), s.Bits)
+}
@@ -0,0 +1,67 @@
+package input_test
+
+import (
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/input"
+)
+
+func TestEstimateStrength(t *testing.T) {
+	tests := []struct {
+		name      string
+		password  string
+		wantLevel string
+		minBits   int
+		maxBits   int
+	}{
+		{name: "Empty", password: "", wantLevel: "very weak", minBits: 0, maxBits: 0},
+		{name: "Common", password: "password123", wantLevel: "very weak", minBits: 10, maxBits: 10},
+		{name: "CommonMixedCase", password: "Qwerty!", wantLevel: "very weak", minBits: 10, maxBits: 10},
+		{name: "Repeated", password: "aaaaaaaaaaaa", wantLevel: "very weak", minBits: 1, maxBits: 20},
+		{name: "Sequence", password: "abcdefghijkl", wantLevel: "very weak", minBits: 1, maxBits: 20},
+		{name: "Digits", password: "839274619305", wantLevel: "fair", minBits: 36, maxBits: 59},
+		{name: "Mixed", password: "new-password", wantLevel: "strong", minBits: 60, maxBits: 127},
+		{name: "Long", password: "correct horse battery staple, really", wantLevel: "very strong", minBits: 128, maxBits: 1 << 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := input.EstimateStrength([]byte(tt.password))
+
+			if got.Bits < tt.minBits || got.Bits > tt.maxBits {
+				t.Errorf("want %d to %d bits, got %d", tt.minBits, tt.maxBits, got.Bits)
+			}
+
+			if got.Level() != tt.wantLevel {
+				t.Errorf("want level %q, got %q", tt.wantLevel, got.Level())
+			}
+		})
+	}
+}
+
+func TestNewPasswordPolicy_Check(t *testing.T) {
+	policy := input.NewPasswordPolicy{MinLength: 8, MinClasses: 2, MinBits: 40}
+
+	tests := []struct {
+		name     string
+		password string
+		weakOK   bool
+		wantErr  bool
+	}{
+		{name: "Short", password: "aB3$", wantErr: true},
+		{name: "SingleClass", password: "abcdefghijkl", wantErr: true},
+		{name: "Weak", password: "Password1", wantErr: true},
+		{name: "WeakOK", password: "Password1", weakOK: true},
+		{name: "Strong", password: "correct-Horse-battery-staple", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy.WeakOK = tt.weakOK
+
+			if err := policy.Check([]byte(tt.password)); (err != nil) != tt.wantErr {
+				t.Errorf("want error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+// Package otpauth reads one-time password keys from otpauth:// URIs,
+// and from the backups of the Aegis and andOTP authenticator apps.
+//
+// Keys of all types are read, e.g., totp, hotp or steam, their parameters
+// are validated and filled with the defaults of the key uri format, i.e.,
+// SHA1, 6 digits and a 30 second period.
+package otpauth
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Key types.
+const (
+	TypeTOTP = "totp"
+	TypeHOTP = "hotp"
+)
+
+// Defaults of the key parameters.
+const (
+	DefaultAlgorithm = "SHA1"
+	DefaultDigits    = 6
+	DefaultPeriod    = 30
+)
+
+// Format is a format keys are read from.
+type Format string
+
+// Supported formats.
+const (
+	FormatURI    Format = "otpauth"
+	FormatAegis  Format = "aegis"
+	FormatAndOTP Format = "andotp"
+)
+
+// ErrEncrypted is returned for encrypted backups, which must be exported as plain text.
+var ErrEncrypted = errors.New("encrypted backups are not supported, export the backup unencrypted")
+
+// Key is a one-time password key.
+type Key struct {
+	Type      string // Type is the lowercase key type, e.g., totp.
+	Issuer    string
+	Account   string
+	Secret    string // Secret is the base32 encoded secret, uppercase and without padding.
+	Algorithm string // Algorithm is the HMAC hash function, e.g., SHA1, SHA256 or SHA512.
+	Digits    int
+	Period    int // Period is the time step in seconds, zero for HOTP keys.
+}
+
+// Label returns the label of k as in otpauth:// URIs, i.e., issuer:account.
+func (k *Key) Label() string {
+	switch {
+	case len(k.Issuer) == 0:
+		return k.Account
+	case len(k.Account) == 0:
+		return k.Issuer
+	default:
+		return k.Issuer + ":" + k.Account
+	}
+}
+
+// normalize validates the fields of k, and sets the defaults of unset parameters.
+func (k *Key) normalize() error {
+	k.Type = strings.ToLower(k.Type)
+	k.Issuer = strings.TrimSpace(k.Issuer)
+	k.Account = strings.TrimSpace(k.Account)
+
+	if len(k.Type) == 0 {
+		return errors.New("key has no type")
+	}
+
+	if len(k.Issuer) == 0 && len(k.Account) == 0 {
+		return errors.New("key has neither an issuer nor an account")
+	}
+
+	secret, err := normalizeSecret(k.Secret)
+	if err != nil {
+		return fmt.Errorf("key %q: %w", k.Label(), err)
+	}
+
+	k.Secret = secret
+
+	if len(k.Algorithm) == 0 {
+		k.Algorithm = DefaultAlgorithm
+	}
+
+	k.Algorithm = strings.ToUpper(k.Algorithm)
+
+	// other types, e.g., motp, use algorithms of their own.
+	switch {
+	case k.Type != TypeTOTP && k.Type != TypeHOTP:
+	case k.Algorithm == "SHA1", k.Algorithm == "SHA256", k.Algorithm == "SHA512":
+	default:
+		return fmt.Errorf("key %q: unsupported algorithm %q", k.Label(), k.Algorithm)
+	}
+
+	if k.Digits == 0 {
+		k.Digits = DefaultDigits
+	}
+
+	if k.Digits < 5 || k.Digits > 10 {
+		return fmt.Errorf("key %q: unsupported number of digits %d", k.Label(), k.Digits)
+	}
+
+	if k.Period == 0 && k.Type != TypeHOTP {
+		k.Period = DefaultPeriod
+	}
+
+	if k.Period < 0 {
+		return fmt.Errorf("key %q: invalid period %d", k.Label(), k.Period)
+	}
+
+	return nil
+}
+
+// normalizeSecret validates a base32 encoded secret, and returns it
+// uppercase, without padding and spaces.
+func normalizeSecret(s string) (string, error) {
+	s = strings.ToUpper(strings.TrimRight(strings.ReplaceAll(s, " ", ""), "="))
+	if len(s) == 0 {
+		return "", errors.New("key has no secret")
+	}
+
+	if _, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s); err != nil {
+		return "", fmt.Errorf("invalid base32 secret: %w", err)
+	}
+
+	return s, nil
+}
+
+// Detect returns the format of data, or an empty format if it is not supported.
+func Detect(data []byte) Format {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+
+	// uri lists may start with comments.
+	for bytes.HasPrefix(data, []byte("#")) {
+		_, data, _ = bytes.Cut(data, []byte("\n"))
+		data = bytes.TrimSpace(data)
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("otpauth://")):
+		return FormatURI
+	case bytes.HasPrefix(data, []byte("[")):
+		return FormatAndOTP
+	case bytes.HasPrefix(data, []byte("{")):
+		return FormatAegis
+	default:
+		return ""
+	}
+}
+
+// Parse reads the keys of data in the given format.
+func Parse(format Format, data []byte) ([]Key, error) {
+	switch format {
+	case FormatURI:
+		return parseURIs(data)
+	case FormatAegis:
+		return parseAegis(data)
+	case FormatAndOTP:
+		return parseAndOTP(data)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// ParseURI parses an otpauth:// URI, e.g.,
+// otpauth://totp/ACME:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME.
+func ParseURI(uri string) (*Key, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "otpauth" {
+		return nil, fmt.Errorf("unsupported uri scheme %q", u.Scheme)
+	}
+
+	q := u.Query()
+
+	k := &Key{
+		Type:      u.Host,
+		Issuer:    q.Get("issuer"),
+		Secret:    q.Get("secret"),
+		Algorithm: q.Get("algorithm"),
+	}
+
+	// the label is issuer:account, or the account only.
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		k.Account = account
+
+		if len(k.Issuer) == 0 {
+			k.Issuer = issuer
+		}
+	} else {
+		k.Account = label
+	}
+
+	for _, p := range []struct {
+		name  string
+		value *int
+	}{
+		{"digits", &k.Digits},
+		{"period", &k.Period},
+	} {
+		v := q.Get(p.name)
+		if len(v) == 0 {
+			continue
+		}
+
+		if *p.value, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid %s %q", p.name, v)
+		}
+	}
+
+	if err := k.normalize(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// parseURIs parses a list of otpauth:// URIs, one per line.
+func parseURIs(data []byte) ([]Key, error) {
+	var keys []Key
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for i := 1; s.Scan(); i++ {
+		line := strings.TrimSpace(s.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		k, err := ParseURI(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i, err)
+		}
+
+		keys = append(keys, *k)
+	}
+
+	return keys, s.Err()
+}
+
+// aegisBackup is a plain text Aegis backup, whose db is a string if encrypted.
+type aegisBackup struct {
+	Version int             `json:"version"`
+	DB      json.RawMessage `json:"db"`
+}
+
+type aegisDB struct {
+	Entries []struct {
+		Type   string `json:"type"`
+		Name   string `json:"name"`
+		Issuer string `json:"issuer"`
+		Info   struct {
+			Secret string `json:"secret"`
+			Algo   string `json:"algo"`
+			Digits int    `json:"digits"`
+			Period int    `json:"period"`
+		} `json:"info"`
+	} `json:"entries"`
+}
+
+func parseAegis(data []byte) ([]Key, error) {
+	var backup aegisBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("parse aegis backup: %w", err)
+	}
+
+	if len(backup.DB) == 0 {
+		return nil, errors.New("parse aegis backup: no db")
+	}
+
+	if backup.DB[0] == '"' {
+		return nil, fmt.Errorf("aegis: %w", ErrEncrypted)
+	}
+
+	var db aegisDB
+	if err := json.Unmarshal(backup.DB, &db); err != nil {
+		return nil, fmt.Errorf("parse aegis backup: %w", err)
+	}
+
+	keys := make([]Key, 0, len(db.Entries))
+
+	for i, e := range db.Entries {
+		k := Key{
+			Type:      e.Type,
+			Issuer:    e.Issuer,
+			Account:   e.Name,
+			Secret:    e.Info.Secret,
+			Algorithm: e.Info.Algo,
+			Digits:    e.Info.Digits,
+			Period:    e.Info.Period,
+		}
+
+		if err := k.normalize(); err != nil {
+			return nil, fmt.Errorf("aegis entry %d: %w", i+1, err)
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// andOTPEntry is an entry of a plain text andOTP backup,
+// whose label is the account, optionally prefixed by the issuer.
+type andOTPEntry struct {
+	Type      string `json:"type"`
+	Issuer    string `json:"issuer"`
+	Label     string `json:"label"`
+	Secret    string `json:"secret"`
+	Algorithm string `json:"algorithm"`
+	Digits    int    `json:"digits"`
+	Period    int    `json:"period"`
+}
+
+func parseAndOTP(data []byte) ([]Key, error) {
+	var entries []andOTPEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse andotp backup: %w", err)
+	}
+
+	keys := make([]Key, 0, len(entries))
+
+	for i, e := range entries {
+		k := Key{
+			Type:      e.Type,
+			Issuer:    e.Issuer,
+			Account:   e.Label,
+			Secret:    e.Secret,
+			Algorithm: e.Algorithm,
+			Digits:    e.Digits,
+			Period:    e.Period,
+		}
+
+		if issuer, account, ok := strings.Cut(e.Label, ":"); ok && (len(e.Issuer) == 0 || strings.TrimSpace(issuer) == e.Issuer) {
+			k.Issuer, k.Account = issuer, account
+		}
+
+		if err := k.normalize(); err != nil {
+			return nil, fmt.Errorf("andotp entry %d: %w", i+1, err)
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
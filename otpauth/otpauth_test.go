@@ -0,0 +1,146 @@
+package otpauth_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/otpauth"
+
+	gocmp "github.com/google/go-cmp/cmp"
+)
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    *otpauth.Key
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			uri:  "otpauth://totp/ACME:alice@example.com?secret=jbswy3dpehpk3pxp&issuer=ACME",
+			want: &otpauth.Key{Type: "totp", Issuer: "ACME", Account: "alice@example.com", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA1", Digits: 6, Period: 30},
+		},
+		{
+			name: "parameters",
+			uri:  "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co&algorithm=sha256&digits=8&period=60",
+			want: &otpauth.Key{Type: "totp", Issuer: "ACME Co", Account: "alice", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA256", Digits: 8, Period: 60},
+		},
+		{
+			name: "issuer from label",
+			uri:  "otpauth://totp/Example%3Abob?secret=JBSW%20Y3DP%3D%3D",
+			want: &otpauth.Key{Type: "totp", Issuer: "Example", Account: "bob", Secret: "JBSWY3DP", Algorithm: "SHA1", Digits: 6, Period: 30},
+		},
+		{
+			name: "hotp",
+			uri:  "otpauth://hotp/ACME:alice?secret=JBSWY3DPEHPK3PXP&counter=1",
+			want: &otpauth.Key{Type: "hotp", Issuer: "ACME", Account: "alice", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA1", Digits: 6},
+		},
+		{name: "no secret", uri: "otpauth://totp/ACME:alice", wantErr: true},
+		{name: "invalid secret", uri: "otpauth://totp/ACME:alice?secret=not-base32", wantErr: true},
+		{name: "invalid digits", uri: "otpauth://totp/ACME:alice?secret=JBSWY3DP&digits=six", wantErr: true},
+		{name: "unsupported algorithm", uri: "otpauth://totp/ACME:alice?secret=JBSWY3DP&algorithm=MD5", wantErr: true},
+		{name: "other scheme", uri: "https://example.com/?secret=JBSWY3DP", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := otpauth.ParseURI(tt.uri)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("want an error, got %+v", got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			if diff := gocmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("key mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	totp := otpauth.Key{Type: "totp", Issuer: "ACME", Account: "alice", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA512", Digits: 8, Period: 60}
+
+	tests := []struct {
+		name   string
+		data   string
+		format otpauth.Format
+		want   []otpauth.Key
+	}{
+		{
+			name: "uris",
+			data: "\n# exported keys\n" +
+				"otpauth://totp/ACME:alice?secret=JBSWY3DPEHPK3PXP&algorithm=SHA512&digits=8&period=60\n\n" +
+				"otpauth://hotp/ACME:bob?secret=JBSWY3DP&counter=3\n",
+			format: otpauth.FormatURI,
+			want:   []otpauth.Key{totp, {Type: "hotp", Issuer: "ACME", Account: "bob", Secret: "JBSWY3DP", Algorithm: "SHA1", Digits: 6}},
+		},
+		{
+			name: "aegis",
+			data: `{"version": 1, "header": {"slots": null, "params": null}, "db": {"version": 3, "entries": [
+				{"type": "totp", "name": "alice", "issuer": "ACME", "info": {"secret": "JBSWY3DPEHPK3PXP", "algo": "SHA512", "digits": 8, "period": 60}},
+				{"type": "steam", "name": "bob", "issuer": "Steam", "info": {"secret": "JBSWY3DP", "algo": "SHA1", "digits": 5, "period": 30}}
+			]}}`,
+			format: otpauth.FormatAegis,
+			want:   []otpauth.Key{totp, {Type: "steam", Issuer: "Steam", Account: "bob", Secret: "JBSWY3DP", Algorithm: "SHA1", Digits: 5, Period: 30}},
+		},
+		{
+			name: "andotp",
+			data: `[
+				{"secret": "JBSWY3DPEHPK3PXP", "issuer": "ACME", "label": "ACME:alice", "digits": 8, "type": "TOTP", "algorithm": "SHA512", "period": 60},
+				{"secret": "JBSWY3DP", "issuer": "", "label": "Example - carol", "digits": 6, "type": "TOTP", "algorithm": "SHA1"}
+			]`,
+			format: otpauth.FormatAndOTP,
+			want:   []otpauth.Key{totp, {Type: "totp", Account: "Example - carol", Secret: "JBSWY3DP", Algorithm: "SHA1", Digits: 6, Period: 30}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := otpauth.Detect([]byte(tt.data)); got != tt.format {
+				t.Fatalf("detect: want %q, got %q", tt.format, got)
+			}
+
+			got, err := otpauth.Parse(tt.format, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			if diff := gocmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("keys mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseEncryptedAegis(t *testing.T) {
+	data := []byte(`{"version": 1, "header": {"slots": [], "params": {}}, "db": "c2VhbGVk"}`)
+
+	if _, err := otpauth.Parse(otpauth.FormatAegis, data); !errors.Is(err, otpauth.ErrEncrypted) {
+		t.Errorf("want %v, got %v", otpauth.ErrEncrypted, err)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		key  otpauth.Key
+		want string
+	}{
+		{key: otpauth.Key{Issuer: "ACME", Account: "alice"}, want: "ACME:alice"},
+		{key: otpauth.Key{Account: "alice"}, want: "alice"},
+		{key: otpauth.Key{Issuer: "ACME"}, want: "ACME"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.key.Label(); got != tt.want {
+			t.Errorf("want %q, got %q", tt.want, got)
+		}
+	}
+}
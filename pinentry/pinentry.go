@@ -0,0 +1,186 @@
+// Package pinentry shows dialogs using pinentry programs, e.g., pinentry-gnome3
+// or pinentry-curses, speaking the Assuan protocol over their stdio.
+//
+// Desktop pinentry variants show a desktop dialog; they require the graphical
+// session environment, e.g., DISPLAY, to be set.
+package pinentry
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DefaultProgram is the pinentry program found on PATH by default.
+const DefaultProgram = "pinentry"
+
+var (
+	// ErrCanceled indicates that the user canceled or declined the dialog.
+	ErrCanceled = errors.New("pinentry: canceled")
+
+	// errAssuan is returned for ERR responses of the Assuan server.
+	errAssuan = errors.New("assuan error")
+)
+
+// Dialog describes the texts of a pinentry dialog. Empty fields are left
+// to the defaults of the pinentry program.
+type Dialog struct {
+	Title  string // Title is the window title.
+	Desc   string // Desc is the descriptive text shown above the input.
+	Prompt string // Prompt is the label of the input, e.g., "Password:".
+	Error  string // Error is shown along the dialog, e.g., why a previous input was rejected.
+	OK     string // OK is the label of the OK button.
+	Cancel string // Cancel is the label of the cancel button.
+}
+
+// commands returns the Assuan commands setting up d.
+func (d Dialog) commands() []string {
+	var cmds []string
+
+	for _, c := range []struct{ cmd, arg string }{
+		{"SETTITLE", d.Title},
+		{"SETDESC", d.Desc},
+		{"SETPROMPT", d.Prompt},
+		{"SETERROR", d.Error},
+		{"SETOK", d.OK},
+		{"SETCANCEL", d.Cancel},
+	} {
+		if len(c.arg) > 0 {
+			cmds = append(cmds, c.cmd+" "+escape(c.arg))
+		}
+	}
+
+	return cmds
+}
+
+// Confirm shows d as a confirmation dialog using program.
+//
+// It returns nil if confirmed, and an error wrapping [ErrCanceled] if declined.
+func Confirm(ctx context.Context, program string, d Dialog) error {
+	_, err := run(ctx, program, d, "CONFIRM")
+	return err
+}
+
+// GetPIN shows d as a dialog reading a secret, e.g., a password, using program,
+// and returns the entered secret.
+//
+// It returns an error wrapping [ErrCanceled] if the dialog was canceled.
+func GetPIN(ctx context.Context, program string, d Dialog) ([]byte, error) {
+	return run(ctx, program, d, "GETPIN")
+}
+
+// run sets up d and runs the Assuan command cmd, returning its data.
+func run(ctx context.Context, program string, d Dialog, cmd string) (_ []byte, retErr error) {
+	c := exec.CommandContext(ctx, program) //nolint:gosec // program is set by the user.
+
+	stdin, err := c.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	defer func() { //nolint:wsl_v5
+		_ = stdin.Close()
+		_ = c.Wait()
+	}()
+
+	a := &assuan{w: stdin, r: bufio.NewReader(stdout)}
+
+	if _, err := a.response(); err != nil {
+		return nil, fmt.Errorf("greeting: %w", err)
+	}
+
+	for _, c := range d.commands() {
+		if _, err := a.command(c); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := a.command(cmd)
+	if err != nil {
+		if errors.Is(err, errAssuan) {
+			return nil, fmt.Errorf("%w: %v", ErrCanceled, err)
+		}
+
+		return nil, err
+	}
+
+	_, _ = a.command("BYE")
+
+	return data, nil
+}
+
+// assuan is a minimal client of the Assuan protocol.
+type assuan struct {
+	w io.Writer
+	r *bufio.Reader
+}
+
+func (a *assuan) command(c string) ([]byte, error) {
+	if _, err := io.WriteString(a.w, c+"\n"); err != nil {
+		return nil, err
+	}
+
+	return a.response()
+}
+
+// response reads lines until the final OK or ERR response, returning the
+// data of D lines, and skipping status and comment lines.
+func (a *assuan) response() ([]byte, error) {
+	var data []byte
+
+	for {
+		line, err := a.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "OK" || strings.HasPrefix(line, "OK "):
+			return data, nil
+		case strings.HasPrefix(line, "ERR "):
+			return nil, fmt.Errorf("%w: %s", errAssuan, strings.TrimPrefix(line, "ERR "))
+		case strings.HasPrefix(line, "D "):
+			data = append(data, unescape(line[2:])...)
+		}
+	}
+}
+
+// escape percent-escapes the characters not allowed in Assuan command arguments.
+func escape(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// unescape decodes the percent-escaped data of a D line.
+func unescape(s string) []byte {
+	b := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b = append(b, byte(c))
+				i += 2
+
+				continue
+			}
+		}
+
+		b = append(b, s[i])
+	}
+
+	return b
+}
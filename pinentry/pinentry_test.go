@@ -0,0 +1,94 @@
+package pinentry_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/pinentry"
+)
+
+// fakePinentry writes a pinentry stand-in answering CONFIRM and GETPIN with
+// the given response, and logging the received commands to the returned path.
+func fakePinentry(t *testing.T, response string) (program string, log string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	log = filepath.Join(dir, "log")
+
+	script := `#!/bin/sh
+echo "OK Pleased to meet you"
+while read -r cmd rest; do
+	echo "$cmd $rest" >> '` + log + `'
+	case "$cmd" in
+	CONFIRM|GETPIN) echo "# prompting"; printf '%s\n' "` + response + `" ;;
+	BYE) echo "OK closing connection"; exit 0 ;;
+	*) echo "OK" ;;
+	esac
+done
+`
+
+	program = filepath.Join(dir, "pinentry")
+	if err := os.WriteFile(program, []byte(script), 0o700); err != nil { //nolint:gosec
+		t.Fatalf("write fake pinentry: %v", err)
+	}
+
+	return program, log
+}
+
+func TestConfirm(t *testing.T) {
+	program, log := fakePinentry(t, "OK")
+
+	if err := pinentry.Confirm(t.Context(), program, pinentry.Dialog{Title: "vlt", Desc: "100%\nsure?"}); err != nil {
+		t.Errorf("confirmed: unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "SETTITLE vlt\nSETDESC 100%25%0Asure?\nCONFIRM \nBYE \n"; string(got) != want {
+		t.Errorf("commands: got %q, want %q", got, want)
+	}
+
+	program, _ = fakePinentry(t, "ERR 83886179 Operation cancelled <Pinentry>")
+
+	if err := pinentry.Confirm(t.Context(), program, pinentry.Dialog{}); !errors.Is(err, pinentry.ErrCanceled) {
+		t.Errorf("denied: want %v, got %v", pinentry.ErrCanceled, err)
+	}
+
+	if err := pinentry.Confirm(t.Context(), filepath.Join(t.TempDir(), "missing"), pinentry.Dialog{}); err == nil || errors.Is(err, pinentry.ErrCanceled) {
+		t.Errorf("missing program: want an error, got %v", err)
+	}
+}
+
+func TestGetPIN(t *testing.T) {
+	program, log := fakePinentry(t, "D s3cr%25t%0A\nOK")
+
+	pin, err := pinentry.GetPIN(t.Context(), program, pinentry.Dialog{Prompt: "Password:", Error: "try again"})
+	if err != nil {
+		t.Fatalf("get pin: %v", err)
+	}
+
+	if got, want := string(pin), "s3cr%t\n"; got != want {
+		t.Errorf("pin: got %q, want %q", got, want)
+	}
+
+	got, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(got), "SETPROMPT Password:\nSETERROR try again\nGETPIN") {
+		t.Errorf("commands: got %q", got)
+	}
+
+	program, _ = fakePinentry(t, "ERR 83886179 Operation cancelled <Pinentry>")
+
+	if _, err := pinentry.GetPIN(t.Context(), program, pinentry.Dialog{}); !errors.Is(err, pinentry.ErrCanceled) {
+		t.Errorf("canceled: want %v, got %v", pinentry.ErrCanceled, err)
+	}
+}
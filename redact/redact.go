@@ -0,0 +1,152 @@
+// Package redact keeps a process-wide set of sensitive values, such as
+// secret values, keys and nonces, and replaces them in text meant for logs.
+//
+// Values are registered as they are read or decrypted, see [Add], and are
+// redacted along with their common encodings, i.e., hex and base64.
+//
+// No copy of a registered value is kept. Only its digest is, keyed by a random
+// key of the process, and text is redacted by comparing the digests of its
+// substrings of the registered lengths. Digests are kept until [Reset] wipes them.
+package redact
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"slices"
+	"sync"
+
+	"github.com/ladzaretti/vlt-cli/securebytes"
+)
+
+// Placeholder replaces redacted values.
+const Placeholder = "[REDACTED]"
+
+// MinLen is the minimum length of a registered value.
+// Shorter values are ignored, as redacting them would mangle unrelated text.
+const MinLen = 4
+
+type digest = [sha256.Size]byte
+
+var (
+	mu sync.RWMutex
+
+	// key is the HMAC key of the digests, generated on first use.
+	key []byte
+
+	// digests holds the digests of the registered values and their encodings, by length.
+	digests = make(map[int]map[digest]struct{})
+
+	// lengths holds the lengths of the registered values, longest first.
+	lengths []int
+)
+
+// Add registers values to be redacted, along with their hex and base64 encodings.
+// Only digests of the values are kept, and the values may be wiped by the caller once added.
+func Add(vs ...[]byte) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if key == nil {
+		key = make([]byte, sha256.Size)
+		_, _ = rand.Read(key) // never returns an error.
+	}
+
+	mac := hmac.New(sha256.New, key)
+
+	for _, v := range vs {
+		if len(bytes.TrimSpace(v)) < MinLen {
+			continue
+		}
+
+		add(mac, v)
+		add(mac, bytes.TrimSpace(v)) // e.g., values read with a trailing newline.
+		addEncoded(mac, hex.EncodedLen(len(v)), func(dst []byte) { hex.Encode(dst, v) })
+		addEncoded(mac, base64.StdEncoding.EncodedLen(len(v)), func(dst []byte) { base64.StdEncoding.Encode(dst, v) })
+		addEncoded(mac, base64.RawURLEncoding.EncodedLen(len(v)), func(dst []byte) { base64.RawURLEncoding.Encode(dst, v) })
+	}
+
+	// longer values are replaced first, as they may contain shorter ones.
+	slices.SortFunc(lengths, func(a, b int) int { return cmp.Compare(b, a) })
+}
+
+// addEncoded adds the n bytes written by encode, wiped once added.
+func addEncoded(mac hash.Hash, n int, encode func(dst []byte)) {
+	buf := make([]byte, n)
+	defer securebytes.Wipe(buf)
+
+	encode(buf)
+	add(mac, buf)
+}
+
+func add(mac hash.Hash, v []byte) {
+	set, ok := digests[len(v)]
+	if !ok {
+		set = make(map[digest]struct{})
+		digests[len(v)] = set
+		lengths = append(lengths, len(v))
+	}
+
+	set[sum(mac, v)] = struct{}{}
+}
+
+func sum(mac hash.Hash, v []byte) (d digest) {
+	mac.Reset()
+	mac.Write(v)
+	mac.Sum(d[:0])
+
+	return d
+}
+
+// String returns s with all registered values replaced by [Placeholder].
+func String(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if len(lengths) == 0 {
+		return s
+	}
+
+	var (
+		mac      = hmac.New(sha256.New, key)
+		b        = []byte(s)
+		redacted = false
+	)
+
+	for _, n := range lengths {
+		set := digests[n]
+
+		for i := 0; i+n <= len(b); i++ {
+			if _, ok := set[sum(mac, b[i:i+n])]; !ok {
+				continue
+			}
+
+			b = slices.Concat(b[:i], []byte(Placeholder), b[i+n:])
+			i += len(Placeholder) - 1
+			redacted = true
+		}
+	}
+
+	if !redacted {
+		return s
+	}
+
+	return string(b)
+}
+
+// Reset wipes and unregisters all values.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	securebytes.Wipe(key)
+	key = nil
+
+	clear(digests)
+	lengths = nil
+}
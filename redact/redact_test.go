@@ -0,0 +1,61 @@
+package redact_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/redact"
+)
+
+func TestString(t *testing.T) {
+	t.Cleanup(redact.Reset)
+
+	secret := []byte("hunter2-correct-horse")
+	redact.Add(secret, []byte("abc"), []byte("hunter2"))
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "raw", in: "value: hunter2-correct-horse.", want: "value: [REDACTED]."},
+		{name: "hex", in: "key=" + hex.EncodeToString(secret), want: "key=[REDACTED]"},
+		{name: "base64", in: base64.StdEncoding.EncodeToString(secret), want: "[REDACTED]"},
+		{name: "base64 url", in: base64.RawURLEncoding.EncodeToString(secret), want: "[REDACTED]"},
+		{name: "contained value", in: "hunter2 and hunter2-correct-horse", want: "[REDACTED] and [REDACTED]"},
+		{name: "short values are ignored", in: "abc", want: "abc"},
+		{name: "unrelated", in: "nothing to see", want: "nothing to see"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redact.String(tt.in); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAdd_WipedByCaller(t *testing.T) {
+	t.Cleanup(redact.Reset)
+
+	secret := []byte("s3cr3t-value")
+	redact.Add(secret)
+
+	copy(secret, strings.Repeat("x", len(secret))) // wiped by the caller.
+
+	if got := redact.String("s3cr3t-value"); got != redact.Placeholder {
+		t.Errorf("want %q, got %q", redact.Placeholder, got)
+	}
+}
+
+func TestReset(t *testing.T) {
+	redact.Add([]byte("s3cr3t-value"))
+	redact.Reset()
+
+	if got := redact.String("s3cr3t-value"); got != "s3cr3t-value" {
+		t.Errorf("want the value unredacted after reset, got %q", got)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !unix
+
+package sandbox
+
+// DisableCoreDumps is a no-op on platforms other than unix.
+func DisableCoreDumps() error { return nil }
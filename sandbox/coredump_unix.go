@@ -0,0 +1,24 @@
+//go:build unix
+
+package sandbox
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// DisableCoreDumps sets the core file size limit of the current process to zero,
+// so a crash never writes decrypted key material to disk. On Linux, the process
+// is also marked non-dumpable, which keeps other processes of the same user from
+// attaching to it or reading its memory through /proc.
+//
+// The limits are inherited by child processes, and cannot be raised again
+// by an unprivileged process.
+func DisableCoreDumps() error {
+	if err := unix.Setrlimit(unix.RLIMIT_CORE, &unix.Rlimit{Cur: 0, Max: 0}); err != nil {
+		return fmt.Errorf("sandbox: disable core dumps: %w", err)
+	}
+
+	return setNonDumpable()
+}
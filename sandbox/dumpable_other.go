@@ -0,0 +1,7 @@
+//go:build unix && !linux
+
+package sandbox
+
+// setNonDumpable is a no-op on unix platforms other than Linux,
+// the core file size limit is the only restriction.
+func setNonDumpable() error { return nil }
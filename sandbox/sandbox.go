@@ -0,0 +1,18 @@
+// Package sandbox hardens the vlt and vltd processes once they are started.
+//
+// On Linux, [Apply] sets the no_new_privs bit and installs a seccomp-bpf
+// filter denying system calls a password manager never needs, e.g., ptrace
+// or loading kernel modules. The restrictions are inherited by child
+// processes, e.g., hooks and clipboard tools.
+//
+// On other platforms, [Apply] is a no-op.
+//
+// [DisableCoreDumps] keeps decrypted key material out of core files,
+// and on Linux, out of reach of other processes of the same user.
+package sandbox
+
+import "errors"
+
+// ErrUnsupported is returned by [Apply] for platforms without
+// a seccomp filter, the no_new_privs bit is still set.
+var ErrUnsupported = errors.New("sandbox: seccomp filter not supported on this platform")
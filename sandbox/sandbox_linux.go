@@ -0,0 +1,152 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// deniedSyscalls are the system calls the filter fails with EPERM:
+// debugging and memory access of other processes, kernel and mount
+// namespace modification, and kernel tracing facilities.
+var deniedSyscalls = []uint32{
+	unix.SYS_PTRACE,
+	unix.SYS_PROCESS_VM_READV,
+	unix.SYS_PROCESS_VM_WRITEV,
+	unix.SYS_KEXEC_LOAD,
+	unix.SYS_INIT_MODULE,
+	unix.SYS_FINIT_MODULE,
+	unix.SYS_DELETE_MODULE,
+	unix.SYS_MOUNT,
+	unix.SYS_UMOUNT2,
+	unix.SYS_PIVOT_ROOT,
+	unix.SYS_SWAPON,
+	unix.SYS_SWAPOFF,
+	unix.SYS_REBOOT,
+	unix.SYS_ACCT,
+	unix.SYS_BPF,
+	unix.SYS_PERF_EVENT_OPEN,
+	unix.SYS_USERFAULTFD,
+	unix.SYS_OPEN_BY_HANDLE_AT,
+}
+
+// x32SyscallBit marks system calls of the x32 ABI on amd64.
+const x32SyscallBit = 0x40000000
+
+// offsets of the fields of struct seccomp_data.
+const (
+	seccompDataNR   = 0
+	seccompDataArch = 4
+)
+
+var (
+	applyOnce sync.Once
+	errApply  error
+)
+
+// Apply restricts the current process, see the package documentation.
+// Only the first call applies the restrictions, later calls return its result.
+//
+// The no_new_privs bit is set first, and is kept if the seccomp filter
+// cannot be installed, e.g., on kernels without seccomp or on unsupported
+// architectures.
+func Apply() error {
+	applyOnce.Do(func() { errApply = apply() })
+	return errApply
+}
+
+func apply() error {
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("sandbox: set no_new_privs: %w", err)
+	}
+
+	arch, ok := auditArch()
+	if !ok {
+		return ErrUnsupported
+	}
+
+	filter := buildFilter(arch, runtime.GOARCH == "amd64")
+
+	prog := unix.SockFprog{
+		Len:    uint16(len(filter)), //nolint:gosec // the filter is short
+		Filter: &filter[0],
+	}
+
+	// TSYNC applies the filter to all threads of the process,
+	// the Go runtime has started several by now.
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP,
+		unix.SECCOMP_SET_MODE_FILTER,
+		unix.SECCOMP_FILTER_FLAG_TSYNC,
+		uintptr(unsafe.Pointer(&prog)))
+	if errno != 0 {
+		return fmt.Errorf("sandbox: install seccomp filter: %w", errno)
+	}
+
+	return nil
+}
+
+// auditArch returns the seccomp architecture of the running binary.
+func auditArch() (uint32, bool) {
+	switch runtime.GOARCH {
+	case "amd64":
+		return unix.AUDIT_ARCH_X86_64, true
+	case "arm64":
+		return unix.AUDIT_ARCH_AARCH64, true
+	case "386":
+		return unix.AUDIT_ARCH_I386, true
+	default:
+		return 0, false
+	}
+}
+
+// buildFilter returns the seccomp-bpf program denying [deniedSyscalls].
+//
+// System calls of another architecture, or of the x32 ABI if denyX32 is set,
+// are denied as well, as they would bypass the system call number checks.
+func buildFilter(arch uint32, denyX32 bool) []unix.SockFilter {
+	deny := unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM)
+
+	filter := []unix.SockFilter{
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArch),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, arch, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, deny),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataNR),
+	}
+
+	if denyX32 {
+		filter = append(filter,
+			bpfJump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, x32SyscallBit, 0, 1),
+			bpfStmt(unix.BPF_RET|unix.BPF_K, deny),
+		)
+	}
+
+	for _, nr := range deniedSyscalls {
+		filter = append(filter,
+			bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, nr, 0, 1),
+			bpfStmt(unix.BPF_RET|unix.BPF_K, deny),
+		)
+	}
+
+	return append(filter, bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ALLOW))
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
+
+func setNonDumpable() error {
+	if err := unix.Prctl(unix.PR_SET_DUMPABLE, 0, 0, 0, 0); err != nil {
+		return fmt.Errorf("sandbox: set non-dumpable: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+//go:build linux
+
+package sandbox_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/sandbox"
+
+	"golang.org/x/sys/unix"
+)
+
+// sandboxHelperEnv is set to the name of the restriction to check
+// when [TestHelper] runs as a helper process.
+const sandboxHelperEnv = "VLT_TEST_SANDBOX_HELPER"
+
+// TestHelper is not a real test, it is executed as a subprocess by [TestApply]
+// and [TestDisableCoreDumps], as the restrictions cannot be lifted once applied.
+// It applies the restriction and reports the first one found missing.
+func TestHelper(t *testing.T) {
+	checks := map[string]func() error{
+		"apply":    checkApply,
+		"coredump": checkDisableCoreDumps,
+	}
+
+	check, ok := checks[os.Getenv(sandboxHelperEnv)]
+	if !ok {
+		t.Skip("helper process for TestApply and TestDisableCoreDumps")
+	}
+
+	if err := check(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func checkApply() error {
+	if err := sandbox.Apply(); err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
+
+	if v, err := unix.PrctlRetInt(unix.PR_GET_NO_NEW_PRIVS, 0, 0, 0, 0); err != nil || v != 1 {
+		return fmt.Errorf("want no_new_privs set, got %d: %v", v, err)
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_PTRACE, unix.PTRACE_TRACEME, 0, 0); !errors.Is(errno, unix.EPERM) {
+		return fmt.Errorf("want ptrace denied with EPERM, got %v", errno)
+	}
+
+	// allowed system calls keep working, including in child processes.
+	if out, err := exec.Command("/bin/sh", "-c", "echo ok").Output(); err != nil || string(out) != "ok\n" {
+		return fmt.Errorf("want child process to run, got %q: %v", out, err)
+	}
+
+	return nil
+}
+
+func checkDisableCoreDumps() error {
+	if err := sandbox.DisableCoreDumps(); err != nil {
+		return fmt.Errorf("disable core dumps: %w", err)
+	}
+
+	var rlim unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_CORE, &rlim); err != nil || rlim.Cur != 0 || rlim.Max != 0 {
+		return fmt.Errorf("want core file size limit 0, got %d/%d: %v", rlim.Cur, rlim.Max, err)
+	}
+
+	if v, err := unix.PrctlRetInt(unix.PR_GET_DUMPABLE, 0, 0, 0, 0); err != nil || v != 0 {
+		return fmt.Errorf("want non-dumpable, got %d: %v", v, err)
+	}
+
+	// the limit is inherited by child processes.
+	if out, err := exec.Command("/bin/sh", "-c", "ulimit -c").Output(); err != nil || string(out) != "0\n" {
+		return fmt.Errorf("want child core file size limit 0, got %q: %v", out, err)
+	}
+
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	runHelper(t, "apply")
+}
+
+func TestDisableCoreDumps(t *testing.T) {
+	runHelper(t, "coredump")
+}
+
+// runHelper runs [TestHelper] in a subprocess checking the given restriction.
+func runHelper(t *testing.T, check string) {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelper$") //nolint:gosec // the test binary itself
+	cmd.Env = append(os.Environ(), sandboxHelperEnv+"="+check)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%s helper process failed: %v\n%s", check, err, out)
+	}
+}
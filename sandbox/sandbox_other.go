@@ -0,0 +1,6 @@
+//go:build !linux
+
+package sandbox
+
+// Apply is a no-op on platforms other than Linux.
+func Apply() error { return nil }
@@ -0,0 +1,185 @@
+// Package securebytes provides byte buffers for holding sensitive data,
+// such as decrypted vault databases, passwords and secret values.
+//
+// Where supported, buffers are allocated outside the Go heap using anonymous
+// memory mappings, locked into RAM to prevent swapping, and excluded from core dumps.
+// Since the memory is not managed by the gc, it is never moved or copied behind our back,
+// and it is explicitly zeroed when the buffer is destroyed.
+package securebytes
+
+import (
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+)
+
+var ErrDestroyed = errors.New("securebytes: buffer already destroyed")
+
+// Buffer is a fixed-size byte buffer for sensitive data.
+//
+// A Buffer must be released using [Buffer.Destroy] once it is no longer needed.
+// The zero value is an empty, unusable buffer.
+type Buffer struct {
+	data      []byte
+	mapped    bool // mapped reports whether data is backed by an anonymous memory mapping.
+	locked    bool // locked reports whether data is locked into RAM.
+	destroyed bool
+	mu        sync.Mutex
+}
+
+// New allocates a new zeroed [Buffer] of the given size.
+//
+// Locking the memory is best effort; failing to lock
+// (e.g., due to RLIMIT_MEMLOCK) is not considered an error.
+func New(size int) (*Buffer, error) {
+	if size < 0 {
+		return nil, errors.New("securebytes: negative size")
+	}
+
+	if size == 0 {
+		return &Buffer{data: []byte{}}, nil
+	}
+
+	data, mapped, err := alloc(size)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Buffer{
+		data:   data,
+		mapped: mapped,
+		locked: lock(data) == nil,
+	}
+
+	return b, nil
+}
+
+// From copies src into a newly allocated [Buffer] and wipes src.
+func From(src []byte) (*Buffer, error) {
+	defer Wipe(src)
+
+	b, err := New(len(src))
+	if err != nil {
+		return nil, err
+	}
+
+	copy(b.data, src)
+
+	return b, nil
+}
+
+// Bytes returns the underlying byte slice.
+//
+// The returned slice must not be retained after [Buffer.Destroy] is called.
+// It returns nil for a nil or destroyed buffer.
+func (b *Buffer) Bytes() []byte {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.destroyed {
+		return nil
+	}
+
+	return b.data
+}
+
+// Len returns the length of the buffer.
+func (b *Buffer) Len() int {
+	return len(b.Bytes())
+}
+
+// Locked reports whether the buffer memory is locked into RAM.
+func (b *Buffer) Locked() bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.locked
+}
+
+// Destroy zeroes the buffer and releases its memory.
+//
+// It is safe to call Destroy multiple times and on a nil buffer.
+func (b *Buffer) Destroy() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.destroyed {
+		return nil
+	}
+
+	b.destroyed = true
+
+	Wipe(b.data)
+
+	var errs []error
+
+	if b.locked {
+		errs = append(errs, unlock(b.data))
+	}
+
+	if b.mapped {
+		errs = append(errs, free(b.data))
+	}
+
+	b.data = nil
+
+	return errors.Join(errs...)
+}
+
+// Wipe overwrites b with zeros.
+//
+// Unlike a bare [clear], the write is guaranteed
+// not to be optimized away.
+func Wipe(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
+
+// WipeFile overwrites the content of f with zeros and syncs it to disk,
+// leaving the file offset at the start.
+//
+// Copy-on-write and journaling file systems, or SSD wear leveling,
+// may still retain copies of the previous content.
+func WipeFile(f *os.File) error {
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	zeros := make([]byte, 32*1024)
+
+	for remaining := fi.Size(); remaining > 0; {
+		n := min(remaining, int64(len(zeros)))
+		if _, err := f.Write(zeros[:n]); err != nil {
+			return err
+		}
+
+		remaining -= n
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+
+	return err
+}
@@ -0,0 +1,11 @@
+//go:build linux
+
+package securebytes
+
+import "golang.org/x/sys/unix"
+
+// excludeFromDump marks the memory region as excluded from core dumps.
+// It is best effort, errors are ignored.
+func excludeFromDump(data []byte) {
+	_ = unix.Madvise(data, unix.MADV_DONTDUMP)
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package securebytes
+
+// alloc falls back to a regular heap allocation on platforms
+// without anonymous memory mappings.
+func alloc(size int) (data []byte, mapped bool, _ error) {
+	return make([]byte, size), false, nil
+}
+
+func free([]byte) error { return nil }
+
+func lock([]byte) error { return nil }
+
+func unlock([]byte) error { return nil }
@@ -0,0 +1,90 @@
+package securebytes_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/securebytes"
+)
+
+func TestBuffer(t *testing.T) {
+	src := []byte("sensitive")
+	want := bytes.Clone(src)
+
+	b, err := securebytes.From(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(src, make([]byte, len(src))) {
+		t.Errorf("source was not wiped: %q", src)
+	}
+
+	if got := b.Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if err := b.Destroy(); err != nil {
+		t.Fatalf("destroy: %v", err)
+	}
+
+	if err := b.Destroy(); err != nil {
+		t.Errorf("second destroy: %v", err)
+	}
+
+	if got := b.Bytes(); got != nil {
+		t.Errorf("got %q after destroy, want nil", got)
+	}
+}
+
+func TestNew_Empty(t *testing.T) {
+	b, err := securebytes.New(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := b.Len(); got != 0 {
+		t.Errorf("got len %d, want 0", got)
+	}
+
+	if err := b.Destroy(); err != nil {
+		t.Errorf("destroy: %v", err)
+	}
+}
+
+func TestWipe(t *testing.T) {
+	b := []byte("secret")
+	securebytes.Wipe(b)
+
+	if !bytes.Equal(b, make([]byte, len(b))) {
+		t.Errorf("got %q, want all zeros", b)
+	}
+}
+
+func TestWipeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, bytes.Repeat([]byte("secret"), 10000), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := securebytes.WipeFile(f); err != nil {
+		t.Fatalf("wipe file: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, make([]byte, 60000)) {
+		t.Errorf("want 60000 zeros, got %d bytes of other content", len(got))
+	}
+}
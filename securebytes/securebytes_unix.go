@@ -0,0 +1,41 @@
+//go:build unix
+
+package securebytes
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// alloc allocates size bytes of anonymous, private memory outside the Go heap.
+func alloc(size int) (data []byte, mapped bool, _ error) {
+	data, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANON)
+	if err != nil {
+		return nil, false, fmt.Errorf("securebytes: mmap: %w", err)
+	}
+
+	excludeFromDump(data)
+
+	return data, true, nil
+}
+
+func free(data []byte) error {
+	if err := unix.Munmap(data); err != nil {
+		return fmt.Errorf("securebytes: munmap: %w", err)
+	}
+
+	return nil
+}
+
+func lock(data []byte) error {
+	return unix.Mlock(data)
+}
+
+func unlock(data []byte) error {
+	if err := unix.Munlock(data); err != nil {
+		return fmt.Errorf("securebytes: munlock: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package selfupdate
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// minisign signature algorithms.
+	algLegacy    = "Ed" // signs the data itself.
+	algPrehashed = "ED" // signs the BLAKE2b-512 hash of the data.
+
+	keyIDSize = 8
+
+	untrustedCommentPrefix = "untrusted comment: "
+	trustedCommentPrefix   = "trusted comment: "
+)
+
+var (
+	// ErrInvalidSignature indicates that a signature does not verify
+	// against the embedded public key.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrMalformedKey indicates an unparsable minisign public key.
+	ErrMalformedKey = errors.New("malformed minisign public key")
+
+	// ErrMalformedSignature indicates an unparsable minisign signature.
+	ErrMalformedSignature = errors.New("malformed minisign signature")
+)
+
+// PublicKey is a minisign Ed25519 public key.
+type PublicKey struct {
+	keyID [keyIDSize]byte
+	key   ed25519.PublicKey
+}
+
+// ParsePublicKey parses a minisign public key, either the base64 encoded
+// key line alone or the content of a minisign public key file.
+func ParsePublicKey(s string) (*PublicKey, error) {
+	line := strings.TrimSpace(s)
+	if lines := nonEmptyLines(s); len(lines) == 2 && strings.HasPrefix(lines[0], untrustedCommentPrefix) {
+		line = lines[1]
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedKey, err)
+	}
+
+	if len(raw) != 2+keyIDSize+ed25519.PublicKeySize || string(raw[:2]) != algLegacy {
+		return nil, ErrMalformedKey
+	}
+
+	pk := &PublicKey{key: ed25519.PublicKey(raw[2+keyIDSize:])}
+	copy(pk.keyID[:], raw[2:2+keyIDSize])
+
+	return pk, nil
+}
+
+// signature is a parsed minisign signature file.
+type signature struct {
+	algorithm      string
+	keyID          [keyIDSize]byte
+	sig            []byte
+	trustedComment string
+	globalSig      []byte
+}
+
+func parseSignature(bs []byte) (*signature, error) {
+	lines := nonEmptyLines(string(bs))
+	if len(lines) != 4 ||
+		!strings.HasPrefix(lines[0], untrustedCommentPrefix) ||
+		!strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return nil, ErrMalformedSignature
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedSignature, err)
+	}
+
+	if len(raw) != 2+keyIDSize+ed25519.SignatureSize {
+		return nil, ErrMalformedSignature
+	}
+
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedSignature, err)
+	}
+
+	if len(globalSig) != ed25519.SignatureSize {
+		return nil, ErrMalformedSignature
+	}
+
+	s := &signature{
+		algorithm:      string(raw[:2]),
+		sig:            raw[2+keyIDSize:],
+		trustedComment: strings.TrimPrefix(lines[2], trustedCommentPrefix),
+		globalSig:      globalSig,
+	}
+	copy(s.keyID[:], raw[2:2+keyIDSize])
+
+	return s, nil
+}
+
+// Verify verifies the minisign signature sigFile of data.
+//
+// Both the legacy and the prehashed signature algorithms are supported.
+// On success, the signed trusted comment is returned.
+func (pk *PublicKey) Verify(data, sigFile []byte) (trustedComment string, err error) {
+	s, err := parseSignature(sigFile)
+	if err != nil {
+		return "", err
+	}
+
+	if s.keyID != pk.keyID {
+		return "", fmt.Errorf("%w: signed with key %X, expected key %X", ErrInvalidSignature, s.keyID, pk.keyID)
+	}
+
+	msg := data
+
+	switch s.algorithm {
+	case algLegacy:
+	case algPrehashed:
+		h := blake2b.Sum512(data)
+		msg = h[:]
+	default:
+		return "", fmt.Errorf("%w: unsupported algorithm %q", ErrMalformedSignature, s.algorithm)
+	}
+
+	if !ed25519.Verify(pk.key, msg, s.sig) {
+		return "", ErrInvalidSignature
+	}
+
+	global := append(bytes.Clone(s.sig), s.trustedComment...)
+	if !ed25519.Verify(pk.key, global, s.globalSig) {
+		return "", fmt.Errorf("%w: trusted comment", ErrInvalidSignature)
+	}
+
+	return s.trustedComment, nil
+}
+
+func nonEmptyLines(s string) []string {
+	var lines []string
+
+	for l := range strings.Lines(s) {
+		if l = strings.TrimRight(l, "\r\n"); l != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	return lines
+}
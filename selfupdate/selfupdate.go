@@ -0,0 +1,417 @@
+// Package selfupdate updates the vlt binaries from signed GitHub releases.
+//
+// Release archives are verified against a minisign public key embedded
+// in the running binary before any executable is replaced.
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRepository is the GitHub repository releases are fetched from.
+	DefaultRepository = "ladzaretti/vlt-cli"
+
+	defaultAPIURL = "https://api.github.com"
+
+	// maxDownloadSize bounds the size of downloaded release assets.
+	maxDownloadSize = 64 << 20
+
+	defaultTimeout = 2 * time.Minute
+)
+
+var (
+	// ErrNoPublicKey is returned when updating without a release signing key.
+	ErrNoPublicKey = errors.New("no release signing key is embedded in this build")
+
+	// ErrAssetNotFound is returned when a release has no archive for the running platform.
+	ErrAssetNotFound = errors.New("release asset not found")
+
+	// ErrInvalidVersion is returned for versions that are not in the vMAJOR.MINOR.PATCH form.
+	ErrInvalidVersion = errors.New("invalid version")
+)
+
+// Updater checks for and applies releases.
+type Updater struct {
+	publicKey string
+	repo      string
+	apiURL    string
+	client    *http.Client
+}
+
+// Option configures an [Updater].
+type Option func(*Updater)
+
+// WithPublicKey sets the minisign public key release archives must be signed with.
+func WithPublicKey(key string) Option {
+	return func(u *Updater) {
+		u.publicKey = key
+	}
+}
+
+// WithRepository sets the owner/name GitHub repository of the releases.
+func WithRepository(repo string) Option {
+	return func(u *Updater) {
+		u.repo = repo
+	}
+}
+
+// WithAPIURL overrides the GitHub API base URL.
+func WithAPIURL(url string) Option {
+	return func(u *Updater) {
+		u.apiURL = strings.TrimRight(url, "/")
+	}
+}
+
+// WithHTTPClient sets the client used for all requests.
+func WithHTTPClient(c *http.Client) Option {
+	return func(u *Updater) {
+		u.client = c
+	}
+}
+
+// New returns an [Updater] for the default repository.
+func New(opts ...Option) *Updater {
+	u := &Updater{
+		repo:   DefaultRepository,
+		apiURL: defaultAPIURL,
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
+// Release is a published release and its assets for the running platform.
+type Release struct {
+	// Version is the release tag, e.g., v1.2.3.
+	Version string
+
+	archive   asset
+	signature asset
+}
+
+type asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"` //nolint:tagliatelle
+}
+
+// Latest returns the latest published release.
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	var resp struct {
+		TagName string  `json:"tag_name"` //nolint:tagliatelle
+		Assets  []asset `json:"assets"`
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", u.apiURL, u.repo)
+
+	body, err := u.get(ctx, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("fetch latest release: %w", err)
+	}
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode latest release: %w", err)
+	}
+
+	if _, err := parseVersion(resp.TagName); err != nil {
+		return nil, fmt.Errorf("latest release: %w", err)
+	}
+
+	rel := &Release{Version: resp.TagName}
+	name := archiveName(resp.TagName)
+
+	for _, a := range resp.Assets {
+		switch a.Name {
+		case name:
+			rel.archive = a
+		case name + ".minisig":
+			rel.signature = a
+		}
+	}
+
+	if rel.archive.URL == "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrAssetNotFound, resp.TagName, name)
+	}
+
+	if rel.signature.URL == "" {
+		return nil, fmt.Errorf("%w: %s %s.minisig", ErrAssetNotFound, resp.TagName, name)
+	}
+
+	return rel, nil
+}
+
+// Apply downloads and verifies the release archive, then atomically
+// replaces each given executable with its counterpart from the archive,
+// matched by base name.
+func (u *Updater) Apply(ctx context.Context, rel *Release, executables ...string) error {
+	if u.publicKey == "" {
+		return ErrNoPublicKey
+	}
+
+	pk, err := ParsePublicKey(u.publicKey)
+	if err != nil {
+		return err
+	}
+
+	archive, err := u.get(ctx, rel.archive.URL, "application/octet-stream")
+	if err != nil {
+		return fmt.Errorf("download %s: %w", rel.archive.Name, err)
+	}
+
+	sig, err := u.get(ctx, rel.signature.URL, "application/octet-stream")
+	if err != nil {
+		return fmt.Errorf("download %s: %w", rel.signature.Name, err)
+	}
+
+	comment, err := pk.Verify(archive, sig)
+	if err != nil {
+		return fmt.Errorf("verify %s: %w", rel.archive.Name, err)
+	}
+
+	// minisign records the signed file name in the trusted comment,
+	// reject a valid signature of a different or unnamed archive.
+	f, ok := trustedFile(comment)
+	if !ok {
+		return fmt.Errorf("verify %s: %w: no file name in the trusted comment", rel.archive.Name, ErrInvalidSignature)
+	}
+
+	if f != rel.archive.Name {
+		return fmt.Errorf("verify %s: %w: signature is for %q", rel.archive.Name, ErrInvalidSignature, f)
+	}
+
+	names := make([]string, 0, len(executables))
+	for _, exe := range executables {
+		names = append(names, filepath.Base(exe))
+	}
+
+	files, err := extract(archive, names)
+	if err != nil {
+		return fmt.Errorf("extract %s: %w", rel.archive.Name, err)
+	}
+
+	for _, exe := range executables {
+		if err := replaceFile(exe, files[filepath.Base(exe)]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *Updater) get(ctx context.Context, url string, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", accept)
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > maxDownloadSize {
+		return nil, fmt.Errorf("response exceeds %d bytes", maxDownloadSize)
+	}
+
+	return body, nil
+}
+
+// archiveName returns the release archive name for the running platform,
+// as produced by the goreleaser configuration.
+func archiveName(tag string) string {
+	return fmt.Sprintf("vlt_%s_%s_%s.tar.gz", strings.TrimPrefix(tag, "v"), runtime.GOOS, runtime.GOARCH)
+}
+
+// trustedFile returns the file name recorded in a minisign trusted comment.
+func trustedFile(comment string) (string, bool) {
+	for f := range strings.FieldsSeq(comment) {
+		if name, ok := strings.CutPrefix(f, "file:"); ok {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// extract returns the content of the named regular files from a gzipped tar archive,
+// regardless of their directory within it.
+func extract(archive []byte, names []string) (map[string][]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(names))
+	tr := tar.NewReader(zr)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		name := filepath.Base(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || !slices.Contains(names, name) {
+			continue
+		}
+
+		bs, err := io.ReadAll(io.LimitReader(tr, maxDownloadSize))
+		if err != nil {
+			return nil, err
+		}
+
+		files[name] = bs
+	}
+
+	for _, name := range names {
+		if _, ok := files[name]; !ok {
+			return nil, fmt.Errorf("%w: %s not in archive", ErrAssetNotFound, name)
+		}
+	}
+
+	return files, nil
+}
+
+// replaceFile atomically replaces path with content, keeping its permissions.
+//
+// The new file is written next to path and renamed over it,
+// so a failed update leaves the original in place.
+func replaceFile(path string, content []byte) (retErr error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("replace %s: %w", path, err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".update.*")
+	if err != nil {
+		return fmt.Errorf("replace %s: %w", path, err)
+	}
+
+	defer func() {
+		if retErr != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if _, err := f.Write(content); err != nil {
+		return fmt.Errorf("replace %s: %w", path, err)
+	}
+
+	if err := f.Chmod(fi.Mode().Perm()); err != nil {
+		return fmt.Errorf("replace %s: %w", path, err)
+	}
+
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("replace %s: %w", path, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("replace %s: %w", path, err)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("replace %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Compare compares two versions in the vMAJOR.MINOR.PATCH[-PRERELEASE][+BUILD] form,
+// the leading v is optional.
+//
+// It returns -1, 0 or +1 if a is older than, equal to or newer than b.
+// A prerelease is older than its release, build metadata is ignored.
+func Compare(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va.core {
+		if c := cmp.Compare(va.core[i], vb.core[i]); c != 0 {
+			return c, nil
+		}
+	}
+
+	switch {
+	case va.pre == vb.pre:
+		return 0, nil
+	case va.pre == "":
+		return 1, nil
+	case vb.pre == "":
+		return -1, nil
+	default:
+		return strings.Compare(va.pre, vb.pre), nil
+	}
+}
+
+type version struct {
+	core [3]int
+	pre  string
+}
+
+func parseVersion(s string) (version, error) {
+	var v version
+
+	rest := strings.TrimPrefix(s, "v")
+	rest, _, _ = strings.Cut(rest, "+")
+	rest, v.pre, _ = strings.Cut(rest, "-")
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != len(v.core) {
+		return v, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
+
+		v.core[i] = n
+	}
+
+	return v, nil
+}
@@ -0,0 +1,230 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+type testKey struct {
+	id   [keyIDSize]byte
+	priv ed25519.PrivateKey
+	pub  string
+}
+
+func newTestKey(t *testing.T) *testKey {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k := &testKey{priv: priv}
+	_, _ = rand.Read(k.id[:])
+
+	raw := append([]byte(algLegacy), k.id[:]...)
+	k.pub = "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(append(raw, pub...)) + "\n"
+
+	return k
+}
+
+// sign returns a prehashed minisign signature file of data.
+func (k *testKey) sign(data []byte, trustedComment string) []byte {
+	h := blake2b.Sum512(data)
+	sig := ed25519.Sign(k.priv, h[:])
+	global := ed25519.Sign(k.priv, append(bytes.Clone(sig), trustedComment...))
+
+	raw := append(append([]byte(algPrehashed), k.id[:]...), sig...)
+
+	return fmt.Appendf(nil, "untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(raw), trustedComment, base64.StdEncoding.EncodeToString(global))
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// newReleaseServer serves a latest release with the given archive and signature.
+func newReleaseServer(t *testing.T, tag string, archive, sig []byte) *httptest.Server {
+	t.Helper()
+
+	name := archiveName(tag)
+	mux := http.NewServeMux()
+
+	var srv *httptest.Server
+
+	mux.HandleFunc("/repos/"+DefaultRepository+"/releases/latest", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"tag_name": tag,
+			"assets": []map[string]string{
+				{"name": name, "browser_download_url": srv.URL + "/download/" + name},
+				{"name": name + ".minisig", "browser_download_url": srv.URL + "/download/" + name + ".minisig"},
+			},
+		})
+	})
+	mux.HandleFunc("/download/"+name, func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(archive) })
+	mux.HandleFunc("/download/"+name+".minisig", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(sig) })
+
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestApply(t *testing.T) {
+	const tag = "v1.2.3"
+
+	key := newTestKey(t)
+	name := archiveName(tag)
+	dir := strings.TrimSuffix(name, ".tar.gz")
+	archive := tarGz(t, map[string]string{
+		dir + "/vlt":       "new vlt",
+		dir + "/vltd":      "new vltd",
+		dir + "/UNLICENSE": "license",
+	})
+
+	tests := []struct {
+		name    string
+		sig     []byte
+		wantErr error
+	}{
+		{name: "Valid", sig: key.sign(archive, "timestamp:1 file:"+name+" hashed")},
+		{name: "TamperedArchive", sig: key.sign(append(bytes.Clone(archive), 0), "file:"+name), wantErr: ErrInvalidSignature},
+		{name: "OtherFile", sig: key.sign(archive, "file:vlt_1.0.0_linux_amd64.tar.gz"), wantErr: ErrInvalidSignature},
+		{name: "NoFile", sig: key.sign(archive, "timestamp:1"), wantErr: ErrInvalidSignature},
+		{name: "OtherKey", sig: newTestKey(t).sign(archive, "file:"+name), wantErr: ErrInvalidSignature},
+		{name: "Malformed", sig: []byte("not a signature"), wantErr: ErrMalformedSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newReleaseServer(t, tag, archive, tt.sig)
+			u := New(WithPublicKey(key.pub), WithAPIURL(srv.URL))
+
+			bin := t.TempDir()
+			vlt, vltd := filepath.Join(bin, "vlt"), filepath.Join(bin, "vltd")
+
+			for _, p := range []string{vlt, vltd} {
+				if err := os.WriteFile(p, []byte("old"), 0o750); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			rel, err := u.Latest(context.Background())
+			if err != nil {
+				t.Fatalf("latest: %v", err)
+			}
+
+			if rel.Version != tag {
+				t.Errorf("want version %s, got %s", tag, rel.Version)
+			}
+
+			err = u.Apply(context.Background(), rel, vlt, vltd)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+
+			want := map[string]string{vlt: "new vlt", vltd: "new vltd"}
+			if tt.wantErr != nil {
+				want = map[string]string{vlt: "old", vltd: "old"}
+			}
+
+			for p, content := range want {
+				bs, err := os.ReadFile(p) //nolint:gosec
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if string(bs) != content {
+					t.Errorf("%s: want %q, got %q", filepath.Base(p), content, bs)
+				}
+
+				if fi, _ := os.Stat(p); fi.Mode().Perm() != 0o750 {
+					t.Errorf("%s: want mode 0750, got %v", filepath.Base(p), fi.Mode().Perm())
+				}
+			}
+
+			entries, _ := os.ReadDir(bin)
+			if len(entries) != 2 {
+				t.Errorf("want no leftover files, got %d entries", len(entries))
+			}
+		})
+	}
+}
+
+func TestApplyWithoutPublicKey(t *testing.T) {
+	if err := New().Apply(context.Background(), &Release{}); !errors.Is(err, ErrNoPublicKey) {
+		t.Errorf("want ErrNoPublicKey, got %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{a: "v1.2.3", b: "1.2.3", want: 0},
+		{a: "v1.2.4", b: "v1.2.3", want: 1},
+		{a: "v1.10.0", b: "v1.9.9", want: 1},
+		{a: "v0.9.0", b: "v1.0.0", want: -1},
+		{a: "v1.0.0-rc.1", b: "v1.0.0", want: -1},
+		{a: "v1.0.0+abc", b: "v1.0.0", want: 0},
+		{a: "0.0.0", b: "v0.1.0", want: -1},
+		{a: "v1.2", b: "v1.2.3", wantErr: true},
+		{a: "dev", b: "v1.2.3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Compare(tt.a, tt.b)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Compare(%q, %q): want error %v, got %v", tt.a, tt.b, tt.wantErr, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("Compare(%q, %q): want %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
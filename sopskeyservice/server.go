@@ -0,0 +1,158 @@
+// Package sopskeyservice implements the sops key service, serving the
+// encryption and decryption of sops data keys over gRPC using age identities
+// held by vlt, so sops files can be decrypted without an age identity file.
+//
+// sops connects to the service given by --keyservice, e.g.,
+// sops --keyservice unix:///run/user/1000/vlt-sops.sock decrypt secrets.yaml.
+package sopskeyservice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+
+	pb "github.com/ladzaretti/vlt-cli/sopskeyservice/proto/keyservicepb"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// socketPath is the default path of the unix domain socket of the key service.
+var socketPath = fmt.Sprintf("/run/user/%d/vlt-sops.sock", os.Getuid())
+
+// SocketPath returns the default path of the unix domain socket of the key service.
+func SocketPath() string { return socketPath }
+
+// ErrNoIdentity is returned by an [IdentityFunc] without an identity for a recipient.
+var ErrNoIdentity = errors.New("no identity for recipient")
+
+// IdentityFunc returns the age identity of recipient, or [ErrNoIdentity].
+type IdentityFunc func(ctx context.Context, recipient string) (age.Identity, error)
+
+// Server implements the sops KeyService for age master keys,
+// data keys are encrypted to the recipient of the key, and decrypted
+// using the identity of the recipient.
+//
+// Other master keys, e.g., AWS KMS or PGP, are left to sops.
+type Server struct {
+	pb.UnimplementedKeyServiceServer
+
+	identity IdentityFunc
+	logger   *slog.Logger
+}
+
+var _ pb.KeyServiceServer = &Server{}
+
+// NewServer returns a key service decrypting with the identities returned by identity.
+func NewServer(identity IdentityFunc, logger *slog.Logger) *Server {
+	return &Server{identity: identity, logger: logger}
+}
+
+// Encrypt encrypts the data key to the age recipient of the request key,
+// armored, as sops stores age encrypted data keys.
+func (s *Server) Encrypt(_ context.Context, req *pb.EncryptRequest) (*pb.EncryptResponse, error) {
+	recipient, err := ageRecipient(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := age.ParseX25519Recipient(recipient)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "parse recipient: %v", err)
+	}
+
+	var buf bytes.Buffer
+
+	aw := armor.NewWriter(&buf)
+
+	w, err := age.Encrypt(aw, r)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encrypt: %v", err)
+	}
+
+	if _, err := w.Write(req.GetPlaintext()); err != nil {
+		return nil, status.Errorf(codes.Internal, "encrypt: %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "encrypt: %v", err)
+	}
+
+	if err := aw.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "encrypt: %v", err)
+	}
+
+	s.logger.Info("data key encrypted", "recipient", recipient)
+
+	return &pb.EncryptResponse{Ciphertext: buf.Bytes()}, nil
+}
+
+// Decrypt decrypts the data key using the identity of the age recipient of the request key.
+func (s *Server) Decrypt(ctx context.Context, req *pb.DecryptRequest) (*pb.DecryptResponse, error) {
+	recipient, err := ageRecipient(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.identity(ctx, recipient)
+	if errors.Is(err, ErrNoIdentity) {
+		return nil, status.Errorf(codes.NotFound, "no identity for recipient %s", recipient)
+	}
+
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "identity: %v", err)
+	}
+
+	r, err := age.Decrypt(armor.NewReader(bytes.NewReader(req.GetCiphertext())), identity)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decrypt: %v", err)
+	}
+
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decrypt: %v", err)
+	}
+
+	s.logger.Info("data key decrypted", "recipient", recipient)
+
+	return &pb.DecryptResponse{Plaintext: plaintext}, nil
+}
+
+// ageRecipient returns the recipient of an age master key.
+func ageRecipient(key *pb.Key) (string, error) {
+	ageKey := key.GetAgeKey()
+	if ageKey == nil {
+		return "", status.Error(codes.Unimplemented, "only age master keys are supported")
+	}
+
+	return ageKey.GetRecipient(), nil
+}
+
+// Serve serves the key service on lis until ctx is done.
+func Serve(ctx context.Context, lis net.Listener, s *Server) error {
+	srv := grpc.NewServer()
+	pb.RegisterKeyServiceServer(srv, s)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		srv.GracefulStop()
+		<-errc
+
+		return nil
+	case err := <-errc:
+		return err
+	}
+}
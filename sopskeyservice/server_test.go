@@ -0,0 +1,133 @@
+package sopskeyservice_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/sopskeyservice"
+	pb "github.com/ladzaretti/vlt-cli/sopskeyservice/proto/keyservicepb"
+
+	"filippo.io/age"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("generate identity: %v", err)
+	}
+
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("generate identity: %v", err)
+	}
+
+	identityFunc := func(_ context.Context, recipient string) (age.Identity, error) {
+		if recipient != identity.Recipient().String() {
+			return nil, sopskeyservice.ErrNoIdentity
+		}
+
+		return identity, nil
+	}
+
+	socket := filepath.Join(t.TempDir(), "sops.sock")
+
+	var lc net.ListenConfig
+
+	lis, err := lc.Listen(t.Context(), "unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	done := make(chan error)
+	go func() {
+		done <- sopskeyservice.Serve(ctx, lis, sopskeyservice.NewServer(identityFunc, slog.New(slog.NewTextHandler(io.Discard, nil))))
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := <-done; err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	})
+
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	client := pb.NewKeyServiceClient(conn)
+
+	ageKey := func(recipient string) *pb.Key {
+		return &pb.Key{KeyType: &pb.Key_AgeKey{AgeKey: &pb.AgeKey{Recipient: recipient}}}
+	}
+
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+
+	enc, err := client.Encrypt(t.Context(), &pb.EncryptRequest{Key: ageKey(identity.Recipient().String()), Plaintext: dataKey})
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	dec, err := client.Decrypt(t.Context(), &pb.DecryptRequest{Key: ageKey(identity.Recipient().String()), Ciphertext: enc.GetCiphertext()})
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+
+	if string(dec.GetPlaintext()) != string(dataKey) {
+		t.Errorf("want data key %q, got %q", dataKey, dec.GetPlaintext())
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode codes.Code
+	}{
+		{
+			name: "unknown recipient",
+			call: func() error {
+				_, err := client.Decrypt(t.Context(), &pb.DecryptRequest{Key: ageKey(other.Recipient().String()), Ciphertext: enc.GetCiphertext()})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "pgp key",
+			call: func() error {
+				key := &pb.Key{KeyType: &pb.Key_PgpKey{PgpKey: &pb.PgpKey{Fingerprint: "ABCD"}}}
+				_, err := client.Encrypt(t.Context(), &pb.EncryptRequest{Key: key, Plaintext: dataKey})
+
+				return err
+			},
+			wantCode: codes.Unimplemented,
+		},
+		{
+			name: "invalid recipient",
+			call: func() error {
+				_, err := client.Encrypt(t.Context(), &pb.EncryptRequest{Key: ageKey("age1invalid"), Plaintext: dataKey})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.wantCode {
+				t.Errorf("want code %v, got %v", tt.wantCode, got)
+			}
+		})
+	}
+}
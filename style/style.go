@@ -0,0 +1,199 @@
+// Package style renders ANSI styled terminal output.
+//
+// Output is only styled when written to a terminal and the NO_COLOR
+// environment variable is not set, see [Enabled].
+package style
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// DefaultTheme is the name of the theme used when none is configured.
+const DefaultTheme = "default"
+
+var (
+	// ErrUnknownColor is returned for color names not in [Colors].
+	ErrUnknownColor = errors.New("unknown color")
+
+	// ErrUnknownTheme is returned for theme names not in [Themes].
+	ErrUnknownTheme = errors.New("unknown theme")
+)
+
+// Colors maps the supported color names to their ANSI SGR foreground codes.
+var Colors = map[string]int{
+	"black":          30,
+	"red":            31,
+	"green":          32,
+	"yellow":         33,
+	"blue":           34,
+	"magenta":        35,
+	"cyan":           36,
+	"white":          37,
+	"bright-black":   90,
+	"bright-red":     91,
+	"bright-green":   92,
+	"bright-yellow":  93,
+	"bright-blue":    94,
+	"bright-magenta": 95,
+	"bright-cyan":    96,
+	"bright-white":   97,
+}
+
+// ColorNames returns the supported color names, sorted.
+func ColorNames() []string {
+	return slices.Sorted(maps.Keys(Colors))
+}
+
+// ValidateColor verifies that name is one of the supported [Colors].
+func ValidateColor(name string) error {
+	if _, ok := Colors[name]; ok {
+		return nil
+	}
+
+	return fmt.Errorf("%w %q (expected one of: %s)", ErrUnknownColor, name, strings.Join(ColorNames(), ", "))
+}
+
+// Style is a text style, a foreground color and optional attributes.
+type Style struct {
+	Color string // Color is one of [Colors], or empty for the terminal default.
+	Bold  bool
+}
+
+// sgr returns the SGR parameters of s, or an empty string if s is plain.
+func (s Style) sgr() string {
+	var params []string
+
+	if s.Bold {
+		params = append(params, "1")
+	}
+
+	if code, ok := Colors[s.Color]; ok {
+		params = append(params, strconv.Itoa(code))
+	}
+
+	return strings.Join(params, ";")
+}
+
+// Theme assigns a [Style] to each role of styled output.
+type Theme struct {
+	Header  Style // Header is used for table headers.
+	Success Style // Success is used for completed operations and passed checks.
+	Warning Style // Warning is used for recoverable problems, e.g., repairable issues.
+	Error   Style // Error is used for error messages and failed checks.
+}
+
+// Themes holds the built-in themes, keyed by name.
+var Themes = map[string]Theme{
+	DefaultTheme: {
+		Header:  Style{Bold: true},
+		Success: Style{Color: "green"},
+		Warning: Style{Color: "yellow"},
+		Error:   Style{Color: "red", Bold: true},
+	},
+	"high-contrast": {
+		Header:  Style{Color: "bright-white", Bold: true},
+		Success: Style{Color: "bright-green", Bold: true},
+		Warning: Style{Color: "bright-yellow", Bold: true},
+		Error:   Style{Color: "bright-red", Bold: true},
+	},
+	"monochrome": {
+		Header: Style{Bold: true},
+		Error:  Style{Bold: true},
+	},
+}
+
+// ThemeNames returns the names of the built-in themes, sorted.
+func ThemeNames() []string {
+	return slices.Sorted(maps.Keys(Themes))
+}
+
+// LookupTheme returns the built-in theme with the given name.
+// An empty name selects the [DefaultTheme].
+func LookupTheme(name string) (Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+
+	t, ok := Themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("%w %q (expected one of: %s)", ErrUnknownTheme, name, strings.Join(ThemeNames(), ", "))
+	}
+
+	return t, nil
+}
+
+// Enabled reports whether styled output should be written to w,
+// that is, w is a terminal and NO_COLOR is not set.
+func Enabled(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+
+	f, ok := w.(*os.File)
+
+	return ok && term.IsTerminal(int(f.Fd())) //nolint:gosec // fd fits in int.
+}
+
+// Styler applies a [Theme] to text.
+//
+// A disabled or nil Styler returns text unchanged.
+type Styler struct {
+	theme   Theme
+	enabled bool
+}
+
+// New returns a [Styler] for theme, styling text only if enabled is set.
+func New(theme Theme, enabled bool) *Styler {
+	return &Styler{theme: theme, enabled: enabled}
+}
+
+// Enabled reports whether s styles text.
+func (s *Styler) Enabled() bool { return s != nil && s.enabled }
+
+// Header styles text as a table header.
+func (s *Styler) Header(text string) string {
+	return s.apply(func(t Theme) Style { return t.Header }, text)
+}
+
+// Success styles text as a success message.
+func (s *Styler) Success(text string) string {
+	return s.apply(func(t Theme) Style { return t.Success }, text)
+}
+
+// Warning styles text as a warning.
+func (s *Styler) Warning(text string) string {
+	return s.apply(func(t Theme) Style { return t.Warning }, text)
+}
+
+// Error styles text as an error message.
+func (s *Styler) Error(text string) string {
+	return s.apply(func(t Theme) Style { return t.Error }, text)
+}
+
+// Color styles text with the named color, unknown names leave text unchanged.
+func (s *Styler) Color(name string, text string) string {
+	return s.apply(func(Theme) Style { return Style{Color: name} }, text)
+}
+
+// apply wraps text in the SGR sequence of the style selected from the theme.
+func (s *Styler) apply(style func(Theme) Style, text string) string {
+	if !s.Enabled() || text == "" {
+		return text
+	}
+
+	sgr := style(s.theme).sgr()
+	if sgr == "" {
+		return text
+	}
+
+	return "\x1b[" + sgr + "m" + text + "\x1b[0m"
+}
@@ -0,0 +1,80 @@
+package style_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/style"
+)
+
+func TestStyler(t *testing.T) {
+	theme := style.Theme{
+		Header:  style.Style{Bold: true},
+		Success: style.Style{Color: "green"},
+		Warning: style.Style{Color: "yellow"},
+		Error:   style.Style{Color: "red", Bold: true},
+	}
+
+	tests := []struct {
+		name   string
+		styler *style.Styler
+		format func(*style.Styler) string
+		want   string
+	}{
+		{name: "Header", styler: style.New(theme, true), format: func(s *style.Styler) string { return s.Header("ID") }, want: "\x1b[1mID\x1b[0m"},
+		{name: "Success", styler: style.New(theme, true), format: func(s *style.Styler) string { return s.Success("ok") }, want: "\x1b[32mok\x1b[0m"},
+		{name: "Warning", styler: style.New(theme, true), format: func(s *style.Styler) string { return s.Warning("warn") }, want: "\x1b[33mwarn\x1b[0m"},
+		{name: "Error", styler: style.New(theme, true), format: func(s *style.Styler) string { return s.Error("err") }, want: "\x1b[1;31merr\x1b[0m"},
+		{name: "Color", styler: style.New(theme, true), format: func(s *style.Styler) string { return s.Color("bright-blue", "x") }, want: "\x1b[94mx\x1b[0m"},
+		{name: "UnknownColor", styler: style.New(theme, true), format: func(s *style.Styler) string { return s.Color("pink", "x") }, want: "x"},
+		{name: "PlainRole", styler: style.New(style.Theme{}, true), format: func(s *style.Styler) string { return s.Error("err") }, want: "err"},
+		{name: "Empty", styler: style.New(theme, true), format: func(s *style.Styler) string { return s.Error("") }, want: ""},
+		{name: "Disabled", styler: style.New(theme, false), format: func(s *style.Styler) string { return s.Error("err") }, want: "err"},
+		{name: "Nil", styler: nil, format: func(s *style.Styler) string { return s.Header("ID") }, want: "ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format(tt.styler); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLookupTheme(t *testing.T) {
+	def, err := style.LookupTheme("")
+	if err != nil {
+		t.Fatalf("default theme: %v", err)
+	}
+
+	if def != style.Themes[style.DefaultTheme] {
+		t.Errorf("empty name: want the default theme, got %+v", def)
+	}
+
+	for _, name := range style.ThemeNames() {
+		th, err := style.LookupTheme(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+
+		for _, st := range []style.Style{th.Header, th.Success, th.Warning, th.Error} {
+			if st.Color != "" {
+				if err := style.ValidateColor(st.Color); err != nil {
+					t.Errorf("%s: %v", name, err)
+				}
+			}
+		}
+	}
+
+	if _, err := style.LookupTheme("solarized"); !errors.Is(err, style.ErrUnknownTheme) {
+		t.Errorf("want ErrUnknownTheme, got %v", err)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	if style.Enabled(&bytes.Buffer{}) {
+		t.Error("want styling disabled for a non-terminal writer")
+	}
+}
@@ -0,0 +1,156 @@
+// Package templatehelper queries secrets using the vlt template helper,
+// for dotfile managers and other programs rendering secrets into files,
+// e.g., the secret template functions of chezmoi.
+//
+// The template helper, 'vlt --template-helper', never prompts for the
+// password, it is served by an existing session or fails with [ErrLocked]:
+//
+//	c := templatehelper.New()
+//
+//	token, err := c.Get(ctx, "github/token")
+//	if errors.Is(err, templatehelper.ErrLocked) {
+//		return errors.New("run 'vlt login' first")
+//	}
+//
+// The output of the helper is a stable contract:
+//
+//	vlt --template-helper get <name>    the value of the secret, as is
+//	vlt --template-helper json <name>   a [Secret] as a JSON object
+//
+// Failures are reported by the exit code, see [ErrNotFound], [ErrAmbiguous],
+// [ErrLocked], [ErrAuthFailed] and [ErrVaultNotFound], along with a message on stderr.
+package templatehelper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Exit codes of the template helper, the same as of other vlt commands.
+const (
+	exitNotFound      = 3
+	exitAmbiguous     = 4
+	exitAuthFailed    = 5
+	exitLocked        = 6
+	exitVaultNotFound = 8
+)
+
+var (
+	// ErrNotFound is returned when no secret has the given name.
+	ErrNotFound = errors.New("secret not found")
+
+	// ErrAmbiguous is returned when more than one secret has the given name.
+	ErrAmbiguous = errors.New("secret name is ambiguous")
+
+	// ErrAuthFailed is returned when the use of the session is denied.
+	ErrAuthFailed = errors.New("authentication failed")
+
+	// ErrLocked is returned when the vault has no session, run 'vlt login'.
+	ErrLocked = errors.New("vault is locked")
+
+	// ErrVaultNotFound is returned when the vault file does not exist.
+	ErrVaultNotFound = errors.New("vault not found")
+)
+
+var exitErrors = map[int]error{
+	exitNotFound:      ErrNotFound,
+	exitAmbiguous:     ErrAmbiguous,
+	exitAuthFailed:    ErrAuthFailed,
+	exitLocked:        ErrLocked,
+	exitVaultNotFound: ErrVaultNotFound,
+}
+
+// Secret is a secret as written by the json request of the template helper.
+type Secret struct {
+	Name       string            `json:"name"`
+	Labels     []string          `json:"labels"`
+	Attributes map[string]string `json:"attributes"`
+	Value      string            `json:"value"`
+}
+
+// HelperError reports a failure of the template helper.
+type HelperError struct {
+	ExitCode int
+	Stderr   string
+	Err      error
+}
+
+func (e *HelperError) Error() string {
+	if len(e.Stderr) == 0 {
+		return "vlt template helper: " + e.Err.Error()
+	}
+
+	return "vlt template helper: " + e.Err.Error() + ": " + e.Stderr
+}
+
+func (e *HelperError) Unwrap() error { return e.Err }
+
+// Client runs the template helper of the vlt binary.
+type Client struct {
+	// Path is the path of the vlt binary, looked up on PATH if empty.
+	Path string
+
+	// Args are global flags of vlt, e.g., --file or --config.
+	Args []string
+}
+
+// New returns a client of the vlt binary found on PATH.
+func New(args ...string) *Client {
+	return &Client{Args: args}
+}
+
+// Get returns the value of the secret with the given name.
+func (c *Client) Get(ctx context.Context, name string) ([]byte, error) {
+	return c.run(ctx, "get", name)
+}
+
+// Secret returns the secret with the given name, along with its metadata.
+func (c *Client) Secret(ctx context.Context, name string) (*Secret, error) {
+	out, err := c.run(ctx, "json", name)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Secret
+	if err := json.Unmarshal(out, &s); err != nil {
+		return nil, fmt.Errorf("vlt template helper: parse output: %w", err)
+	}
+
+	return &s, nil
+}
+
+func (c *Client) run(ctx context.Context, request string, name string) ([]byte, error) {
+	path := c.Path
+	if len(path) == 0 {
+		path = "vlt"
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, path, slices.Concat(c.Args, []string{"--template-helper", request, "--", name})...) //nolint:gosec // the arguments are not shell interpreted
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		helperErr := &HelperError{ExitCode: -1, Stderr: strings.TrimSpace(stderr.String()), Err: err}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			helperErr.ExitCode = exitErr.ExitCode()
+
+			if sentinel, ok := exitErrors[helperErr.ExitCode]; ok {
+				helperErr.Err = sentinel
+			}
+		}
+
+		return nil, helperErr
+	}
+
+	return stdout.Bytes(), nil
+}
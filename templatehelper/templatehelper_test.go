@@ -0,0 +1,95 @@
+package templatehelper_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/templatehelper"
+
+	gocmp "github.com/google/go-cmp/cmp"
+)
+
+// fakeVlt is a vlt template helper serving a single secret named foo,
+// the name is the fourth argument, i.e., --template-helper <request> -- <name>.
+const fakeVlt = `#!/bin/sh
+case "$4" in
+foo)
+	if [ "$2" = json ]; then
+		printf '{"name":"foo","labels":["dev"],"attributes":{"user":"alice"},"value":"bar"}\n'
+	else
+		printf 'bar'
+	fi
+	;;
+locked) echo "vault is locked" >&2; exit 6 ;;
+dup) exit 4 ;;
+*) echo "no match" >&2; exit 3 ;;
+esac
+`
+
+func newFakeClient(t *testing.T) *templatehelper.Client {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "vlt")
+	if err := os.WriteFile(path, []byte(fakeVlt), 0o700); err != nil { //nolint:gosec
+		t.Fatalf("write fake vlt: %v", err)
+	}
+
+	return &templatehelper.Client{Path: path}
+}
+
+func TestClient(t *testing.T) {
+	c := newFakeClient(t)
+
+	value, err := c.Get(t.Context(), "foo")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if string(value) != "bar" {
+		t.Errorf("get: want %q, got %q", "bar", value)
+	}
+
+	got, err := c.Secret(t.Context(), "foo")
+	if err != nil {
+		t.Fatalf("secret: %v", err)
+	}
+
+	want := &templatehelper.Secret{Name: "foo", Labels: []string{"dev"}, Attributes: map[string]string{"user": "alice"}, Value: "bar"}
+	if diff := gocmp.Diff(want, got); diff != "" {
+		t.Errorf("secret mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	c := newFakeClient(t)
+
+	tests := []struct {
+		name string
+		want error
+		code int
+	}{
+		{name: "missing", want: templatehelper.ErrNotFound, code: 3},
+		{name: "dup", want: templatehelper.ErrAmbiguous, code: 4},
+		{name: "locked", want: templatehelper.ErrLocked, code: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.Get(t.Context(), tt.name)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, err)
+			}
+
+			var helperErr *templatehelper.HelperError
+			if !errors.As(err, &helperErr) || helperErr.ExitCode != tt.code {
+				t.Errorf("want exit code %d, got %v", tt.code, err)
+			}
+		})
+	}
+
+	if _, err := (&templatehelper.Client{Path: filepath.Join(t.TempDir(), "vlt")}).Get(t.Context(), "foo"); err == nil {
+		t.Error("missing binary: want an error, got nil")
+	}
+}
@@ -0,0 +1,154 @@
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/ladzaretti/migrate/types"
+)
+
+// SQLiteDialect provides the needed queries for managing schema versioning
+// for an SQLite database.
+type SQLiteDialect struct{}
+
+var (
+	_ types.Dialect = SQLiteDialect{}
+	_ types.Locker  = SQLiteDialect{}
+)
+
+func (SQLiteDialect) CreateVersionTableQuery() string {
+	return `
+		CREATE TABLE
+			IF NOT EXISTS schema_version (
+				id INTEGER PRIMARY KEY CHECK (id = 0),
+				version INTEGER,
+				checksum TEXT NOT NULL
+			);
+		`
+}
+
+func (SQLiteDialect) CurrentVersionQuery() string {
+	return `SELECT id, version, checksum FROM schema_version;`
+}
+
+func (SQLiteDialect) SaveVersionQuery() string {
+	return `
+        	INSERT INTO schema_version (id, version, checksum)
+        	VALUES (0, $1, $2)
+        	ON CONFLICT(id) 
+        	DO UPDATE SET version = EXCLUDED.version, checksum = EXCLUDED.checksum;
+	`
+}
+
+// LockQuery starts the write transaction of the migration, equivalent to BEGIN IMMEDIATE:
+// a write as the first statement acquires the database write lock upfront, rather
+// than upgrading a read lock later, which fails without waiting if contended.
+//
+// Other writers wait for the lock up to the busy timeout of the connection, e.g.,
+// set using "PRAGMA busy_timeout = 5000;", and fail immediately if none is set.
+func (SQLiteDialect) LockQuery() string {
+	return `UPDATE schema_version SET version = version WHERE 0;`
+}
+
+// PostgreSQLDialect provides the needed queries for managing schema versioning
+// for an PostgreSQL database.
+type PostgreSQLDialect struct{}
+
+var (
+	_ types.Dialect = PostgreSQLDialect{}
+	_ types.Locker  = PostgreSQLDialect{}
+)
+
+func (PostgreSQLDialect) CreateVersionTableQuery() string {
+	return `
+		CREATE TABLE
+			IF NOT EXISTS schema_version (
+				id INTEGER PRIMARY KEY CHECK (id = 0),
+				version INTEGER,
+				checksum TEXT NOT NULL
+			);
+	`
+}
+
+func (PostgreSQLDialect) CurrentVersionQuery() string {
+	return `SELECT id, version, checksum FROM schema_version;`
+}
+
+func (PostgreSQLDialect) SaveVersionQuery() string {
+	return `
+		INSERT INTO schema_version (id, version, checksum)
+		VALUES (0, $1, $2)
+		ON CONFLICT (id) 
+		DO UPDATE SET version = EXCLUDED.version, checksum = EXCLUDED.checksum;
+	`
+}
+
+// postgresLockKey is the pg_advisory_xact_lock key of the migration lock.
+const postgresLockKey int64 = 0x6d6967726174 // "migrat"
+
+// LockQuery acquires a transaction level advisory lock,
+// released once the migration transaction ends.
+func (PostgreSQLDialect) LockQuery() string {
+	return fmt.Sprintf(`SELECT pg_advisory_xact_lock(%d);`, postgresLockKey)
+}
+
+// MySQLDialect provides the needed queries for managing schema versioning
+// for a MySQL or MariaDB database.
+//
+// MySQL implicitly commits the transaction before and after most DDL statements,
+// e.g., CREATE TABLE, so migrations are not rolled back as a whole on error: the
+// schema version is saved after each migration, and a failed migration is applied
+// again by the next [Migrator.Apply]. Prefer a single DDL statement per migration.
+//
+// Migrations of multiple statements require the multiStatements=true DSN parameter
+// of the github.com/go-sql-driver/mysql driver.
+type MySQLDialect struct{}
+
+var (
+	_ types.Dialect  = MySQLDialect{}
+	_ types.Locker   = MySQLDialect{}
+	_ types.Unlocker = MySQLDialect{}
+)
+
+func (MySQLDialect) CreateVersionTableQuery() string {
+	return `
+		CREATE TABLE
+			IF NOT EXISTS schema_version (
+				id INTEGER PRIMARY KEY CHECK (id = 0),
+				version INTEGER,
+				checksum TEXT NOT NULL
+			);
+	`
+}
+
+func (MySQLDialect) CurrentVersionQuery() string {
+	return `SELECT id, version, checksum FROM schema_version;`
+}
+
+// SaveVersionQuery uses VALUES() rather than a row alias,
+// as MariaDB does not support the latter.
+func (MySQLDialect) SaveVersionQuery() string {
+	return `
+		INSERT INTO schema_version (id, version, checksum)
+		VALUES (0, ?, ?)
+		ON DUPLICATE KEY UPDATE version = VALUES(version), checksum = VALUES(checksum);
+	`
+}
+
+// mysqlLockName is the GET_LOCK name of the migration lock, per database,
+// as named locks are server wide. Names are limited to 64 characters.
+const mysqlLockName = `CONCAT('migrate:', SHA1(DATABASE()))`
+
+// mysqlLockTimeout is the GET_LOCK timeout in seconds. It is finite rather
+// than negative, i.e., infinite, for portability across MySQL and MariaDB.
+const mysqlLockTimeout = 365 * 24 * 60 * 60
+
+// LockQuery acquires a named lock, waiting for it until the context is canceled.
+// Unlike row locks, it is not released by the implicit commits of DDL statements,
+// but by [MySQLDialect.UnlockQuery] or once the session ends.
+func (MySQLDialect) LockQuery() string {
+	return fmt.Sprintf(`DO GET_LOCK(%s, %d);`, mysqlLockName, mysqlLockTimeout)
+}
+
+func (MySQLDialect) UnlockQuery() string {
+	return fmt.Sprintf(`DO RELEASE_LOCK(%s);`, mysqlLockName)
+}
@@ -0,0 +1,14 @@
+// Package migrate provides a generic and database-agnostic schema migration tool.
+//
+// It works with any SQL (or SQL-like) database that has a [database/sql] driver.
+// See https://go.dev/wiki/SQLDrivers for a list of supported drivers.
+//
+// Migrations are versioned, transactional (when supported), and verified using checksums
+// to detect changes in already applied scripts. Migrations paired with down migrations
+// can be reverted, see [Migrator.Rollback], and pending migrations can be listed
+// without applying them, see [Migrator.Plan]. Data backfills that cannot be expressed
+// in SQL can be implemented in Go, interleaved with script migrations, see [Interleaved].
+//
+// PostgreSQL, SQLite and MySQL (or MariaDB) are supported out of the box, with the
+// ability to extend support for additional dialects.
+package migrate
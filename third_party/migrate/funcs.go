@@ -0,0 +1,116 @@
+package migrate
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ladzaretti/migrate/types"
+)
+
+// Func is a migration implemented in Go, e.g., to backfill data that cannot be
+// computed in SQL. It runs on the migration transaction if transactions are
+// enabled, see [WithTransaction].
+type Func func(ctx context.Context, db types.CoreDB) error
+
+// GoMigration is a migration implemented in Go.
+type GoMigration struct {
+	// ID identifies the migration in the checksum chain, as the script does for
+	// script migrations. Changing it fails the validation of databases the migration
+	// was applied to, so it should be changed along with the behavior of Up.
+	ID string
+
+	Up Func
+}
+
+// FuncLister is a [Lister] whose migrations may be implemented in Go.
+type FuncLister interface {
+	Lister
+
+	// ListFuncs returns the Go migrations aligned by index with the migrations
+	// returned by List, nil for script migrations. For Go migrations, List returns
+	// the text the checksums are computed from.
+	ListFuncs() ([]Func, error)
+}
+
+// Interleaved is a [FuncLister] interleaving Go migrations
+// with the script migrations of Scripts.
+//
+// Go migrations cannot be reverted, see [Migrator.Rollback].
+type Interleaved struct {
+	Scripts Lister
+
+	// Funcs maps positions in the execution order, starting at 1, to Go migrations.
+	// Script migrations fill the remaining positions, in order.
+	Funcs map[int]GoMigration
+}
+
+var (
+	_ FuncLister = Interleaved{}
+	_ DownLister = Interleaved{}
+)
+
+func (l Interleaved) List() ([]string, error) {
+	return interleave(l, l.Scripts.List, func(g GoMigration) string { return "-- go: " + g.ID })
+}
+
+func (l Interleaved) ListFuncs() ([]Func, error) {
+	list := func() ([]Func, error) {
+		scripts, err := l.Scripts.List()
+		return make([]Func, len(scripts)), err
+	}
+
+	return interleave(l, list, func(g GoMigration) Func { return g.Up })
+}
+
+// ListDown returns the down migrations of Scripts if it is a [DownLister],
+// Go migrations have none.
+func (l Interleaved) ListDown() ([]string, error) {
+	list := func() ([]string, error) {
+		if d, ok := l.Scripts.(DownLister); ok {
+			return d.ListDown()
+		}
+
+		scripts, err := l.Scripts.List()
+
+		return make([]string, len(scripts)), err
+	}
+
+	return interleave(l, list, func(GoMigration) string { return "" })
+}
+
+// interleave places the Go migrations of l, converted by fromGo, at their positions
+// among the script migrations returned by list.
+func interleave[T any](l Interleaved, list func() ([]T, error), fromGo func(GoMigration) T) ([]T, error) {
+	scripts, err := list()
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(scripts) + len(l.Funcs)
+	merged := make([]T, 0, total)
+
+	for i := 1; i <= total; i++ {
+		g, ok := l.Funcs[i]
+		if !ok {
+			if len(scripts) == 0 {
+				return nil, errf("go migration positions exceed %d migrations", total)
+			}
+
+			merged, scripts = append(merged, scripts[0]), scripts[1:]
+
+			continue
+		}
+
+		if len(g.ID) == 0 || g.Up == nil {
+			return nil, errf("go migration %d: missing ID or Up function", i)
+		}
+
+		merged = append(merged, fromGo(g))
+	}
+
+	if len(scripts) > 0 {
+		return nil, errf("go migration positions must be within 1..%d", total)
+	}
+
+	return slices.Clip(merged), nil
+}
@@ -0,0 +1,557 @@
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <https://unlicense.org/>
+
+package migrate
+
+import (
+	"context"
+	//nolint:gosec // in this context, SHA-1 is for change detection, not security.
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ladzaretti/migrate/internal/schemaops"
+	"github.com/ladzaretti/migrate/types"
+)
+
+// Checksum computes a hash value for the given string.
+// It is used to validate and compare migration scripts.
+type Checksum func(s string) string
+
+// Filter is used to filter migrations by their index
+// in the execution order. Return true to apply the migration.
+type Filter func(migrationIndex int) bool
+
+type Migrator struct {
+	db                     types.DBTX
+	dialect                types.Dialect
+	migrationFilter        Filter
+	checksum               Checksum
+	withChecksumValidation bool
+	withTx                 bool
+	reapplyAll             bool
+}
+
+type Opt func(*Migrator)
+
+// New creates a new Migrator with the provided database, dialect, and options.
+//
+// By default, both transactions and checksum validation are enabled. The checksum
+// validation uses a SHA-1 function that ignores formatting (e.g., whitespaces).
+// These defaults can be customized using the [Opt] functions.
+func New(db types.DBTX, dialect types.Dialect, opts ...Opt) *Migrator {
+	m := &Migrator{
+		db:                     db,
+		dialect:                dialect,
+		migrationFilter:        func(_ int) bool { return true },
+		checksum:               normalizedSha1,
+		withChecksumValidation: true,
+		withTx:                 true,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// WithChecksum sets a custom [Checksum] function or uses the default if nil.
+func WithChecksum(fn Checksum) Opt {
+	return func(m *Migrator) {
+		if fn != nil {
+			m.checksum = fn
+		}
+	}
+}
+
+func WithTransaction(enabled bool) Opt {
+	return func(m *Migrator) {
+		m.withTx = enabled
+	}
+}
+
+func WithChecksumValidation(enabled bool) Opt {
+	return func(m *Migrator) {
+		m.withChecksumValidation = enabled
+	}
+}
+
+// WithFilter is used to set a filtering function
+// to exclude certain scripts from being applied.
+//
+// Example:
+//
+//	// Skip the 4th migration
+//	skipForth := func(n int) bool { return n != 4 }
+//
+//	m := migrate.New(db, s.dialect, migrate.WithFilter(skipForth))
+//	n, err := m.Apply(migrations)
+func WithFilter(fn Filter) Opt {
+	return func(m *Migrator) {
+		m.migrationFilter = fn
+	}
+}
+
+// WithReapplyAll controls whether to reapply existing migrations.
+func WithReapplyAll(enabled bool) Opt {
+	return func(m *Migrator) {
+		m.reapplyAll = enabled
+	}
+}
+
+func errf(format string, a ...any) error {
+	return fmt.Errorf(format, a...)
+}
+
+// Apply applies the given migrations in the order they are provided.
+// Only unapplied migrations are applied.
+// That is, if the current schema version is n and n + k scripts are provided,
+// only the additional k will be applied.
+//
+// To re-apply all migrations, use the [WithReapplyAll] [Opt] function.
+//
+// The initial schema state is considered version 0.
+//
+// For each schema version, a cumulative checksum is calculated,
+// considering all previously applied migrations.
+// If an already applied migration has changed,
+// validation will fail, and no further migrations will be applied.
+//
+// It returns the number of migrations applied and any error encountered.
+//
+// Migrations listed by a [FuncLister] may be implemented in Go,
+// see [Interleaved]. These are applied like script migrations.
+//
+// With transactions enabled (default), any error triggers a rollback;
+// otherwise, migrations are applied sequentially until an error occurs or all are applied.
+//
+// Concurrent migrations of the same database, e.g., by different processes, are
+// serialized if the dialect implements [types.Locker], as the provided dialects do:
+// the migration lock is acquired within the transaction, and the schema version is
+// read again once it is held. Transactions are required for locking.
+//
+// To reset the schema and force re-application of migrations,
+// along with re-generating checksum values, use the following:
+//
+//	opts := []migrate.Opts{
+//		migrate.WithChecksumValidation(false),
+//		migrate.WithReapplyAll(true),
+//	}
+//	m := migrate.New(db, s.dialect, opts...)
+//	m.Apply(migrations)
+func (m *Migrator) Apply(from Lister) (int, error) {
+	return m.ApplyContext(context.Background(), from)
+}
+
+func (m *Migrator) ApplyContext(ctx context.Context, from Lister) (int, error) {
+	migrations, err := from.List()
+	if err != nil {
+		return 0, errf("list migrations source: %v", err)
+	}
+
+	if err := schemaops.CreateTable(ctx, m.db, m.dialect); err != nil {
+		return 0, errf("create schema version table: %v", err)
+	}
+
+	schema, err := m.CurrentSchemaVersion(ctx)
+	if err != nil {
+		return 0, errf("current schema version: %v", err)
+	}
+
+	if schema.Version > len(migrations) {
+		return 0, errf("database version (%d) exceeds available migrations (%d)", schema.Version, len(migrations))
+	}
+
+	runtimeChecksum := m.checksumHistory(migrations)
+	if err := m.validateChecksum(schema, runtimeChecksum); err != nil {
+		return 0, errf("schema integrity check failed: %v", err)
+	}
+
+	if !m.reapplyAll && schema.Version >= len(migrations) {
+		return 0, nil // already up to date
+	}
+
+	funcs, err := listFuncs(from, len(migrations))
+	if err != nil {
+		return 0, err
+	}
+
+	if !m.withTx {
+		n, err := m.applyMigrations(ctx, m.db, schema.Version, migrations, funcs, runtimeChecksum)
+		if err != nil {
+			return n, errf("non-transactional migration: %w", err)
+		}
+
+		return n, err
+	}
+
+	return m.inTx(ctx, func(tx types.CoreDB) (int, error) {
+		// another process may have migrated the database
+		// while the migration lock was being acquired.
+		schema, err := m.lock(ctx, tx)
+		if err != nil {
+			return 0, err
+		}
+
+		if schema.Version > len(migrations) {
+			return 0, errf("database version (%d) exceeds available migrations (%d)", schema.Version, len(migrations))
+		}
+
+		if err := m.validateChecksum(schema, runtimeChecksum); err != nil {
+			return 0, errf("schema integrity check failed: %v", err)
+		}
+
+		if !m.reapplyAll && schema.Version >= len(migrations) {
+			return 0, nil // migrated meanwhile
+		}
+
+		return m.applyMigrations(ctx, tx, schema.Version, migrations, funcs, runtimeChecksum)
+	})
+}
+
+// Rollback reverts the last n applied migrations using their down migrations,
+// in reverse order. It returns the number of migrations reverted.
+//
+// The listed migrations must match the applied ones, as verified by their checksums,
+// see [Migrator.Apply]. Every migration to revert must have a down migration,
+// otherwise nothing is reverted. Down migrations of migrations excluded by the
+// [Filter] are skipped, as these were never applied.
+//
+// The schema version and checksum are set to those of the remaining migrations,
+// so a following [Migrator.Apply] applies the reverted migrations again.
+//
+// With transactions enabled (default), any error triggers a rollback;
+// otherwise, migrations are reverted sequentially until an error occurs or all are reverted.
+func (m *Migrator) Rollback(from DownLister, n int) (int, error) {
+	return m.RollbackContext(context.Background(), from, n)
+}
+
+func (m *Migrator) RollbackContext(ctx context.Context, from DownLister, n int) (int, error) {
+	if n < 0 {
+		return 0, errf("invalid number of migrations to roll back: %d", n)
+	}
+
+	migrations, err := from.List()
+	if err != nil {
+		return 0, errf("list migrations source: %v", err)
+	}
+
+	downs, err := from.ListDown()
+	if err != nil {
+		return 0, errf("list down migrations source: %v", err)
+	}
+
+	if len(downs) != len(migrations) {
+		return 0, errf("mismatched migrations and down migrations: %d != %d", len(migrations), len(downs))
+	}
+
+	if err := schemaops.CreateTable(ctx, m.db, m.dialect); err != nil {
+		return 0, errf("create schema version table: %v", err)
+	}
+
+	schema, err := m.CurrentSchemaVersion(ctx)
+	if err != nil {
+		return 0, errf("current schema version: %v", err)
+	}
+
+	if schema.Version > len(migrations) {
+		return 0, errf("database version (%d) exceeds available migrations (%d)", schema.Version, len(migrations))
+	}
+
+	if n > schema.Version {
+		return 0, errf("cannot roll back %d migrations of database version %d", n, schema.Version)
+	}
+
+	runtimeChecksum := m.checksumHistory(migrations)
+	if err := m.validateChecksum(schema, runtimeChecksum); err != nil {
+		return 0, errf("schema integrity check failed: %v", err)
+	}
+
+	for i := schema.Version; i > schema.Version-n; i-- {
+		if m.migrationFilter(i) && len(strings.TrimSpace(downs[i-1])) == 0 {
+			return 0, errf("migration script %d has no down migration", i)
+		}
+	}
+
+	if n == 0 {
+		return 0, nil
+	}
+
+	if !m.withTx {
+		n, err := m.revertMigrations(ctx, m.db, schema.Version, n, downs, runtimeChecksum)
+		if err != nil {
+			return n, errf("non-transactional rollback: %w", err)
+		}
+
+		return n, err
+	}
+
+	return m.inTx(ctx, func(tx types.CoreDB) (int, error) {
+		locked, err := m.lock(ctx, tx)
+		if err != nil {
+			return 0, err
+		}
+
+		if locked.Version != schema.Version || locked.Checksum != schema.Checksum {
+			return 0, errf("schema version changed while acquiring the migration lock: %d != %d", locked.Version, schema.Version)
+		}
+
+		return m.revertMigrations(ctx, tx, schema.Version, n, downs, runtimeChecksum)
+	})
+}
+
+// lock acquires the migration lock within tx if the dialect implements [types.Locker],
+// and returns the schema version read once the lock is held.
+func (m *Migrator) lock(ctx context.Context, tx types.CoreDB) (types.SchemaVersion, error) {
+	if l, ok := m.dialect.(types.Locker); ok {
+		if err := execContext(ctx, tx, l.LockQuery()); err != nil {
+			return types.SchemaVersion{}, errf("acquire migration lock: %v", err)
+		}
+	}
+
+	schema, err := currentSchemaVersion(ctx, tx, m.dialect)
+	if err != nil {
+		return types.SchemaVersion{}, errf("current schema version: %v", err)
+	}
+
+	return schema, nil
+}
+
+// inTx runs fn within a transaction, committed only if fn succeeds.
+//
+// The migration lock is released before the transaction ends
+// if the dialect implements [types.Unlocker].
+func (m *Migrator) inTx(ctx context.Context, fn func(tx types.CoreDB) (int, error)) (int, error) {
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, errf("start transaction: %v", err)
+	}
+
+	n, err := fn(tx)
+
+	if u, ok := m.dialect.(types.Unlocker); ok {
+		if err2 := execContext(ctx, tx, u.UnlockQuery()); err2 != nil {
+			err = errors.Join(err, errf("release migration lock: %v", err2))
+		}
+	}
+
+	if err != nil {
+		if err2 := tx.Rollback(); err2 != nil {
+			return 0, errf("rollback: %v", errors.Join(err2, err))
+		}
+
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errf("transaction commit: %v", err)
+	}
+
+	return n, err
+}
+
+func (m *Migrator) CurrentSchemaVersion(ctx context.Context) (types.SchemaVersion, error) {
+	return currentSchemaVersion(ctx, m.db, m.dialect)
+}
+
+func currentSchemaVersion(ctx context.Context, db types.CoreDB, dialect types.Dialect) (types.SchemaVersion, error) {
+	schema, err := schemaops.CurrentVersion(ctx, db, dialect)
+	if err != nil && !errors.Is(err, schemaops.ErrNoSchemaVersion) {
+		//nolint:wrapcheck // error is returned from an internal package
+		return types.SchemaVersion{}, err
+	}
+
+	if schema != nil {
+		return *schema, nil
+	}
+
+	return types.SchemaVersion{}, nil
+}
+
+// listFuncs returns the Go migrations of from if it is a [FuncLister],
+// nil for script-only sources.
+func listFuncs(from Lister, n int) ([]Func, error) {
+	l, ok := from.(FuncLister)
+	if !ok {
+		return nil, nil
+	}
+
+	funcs, err := l.ListFuncs()
+	if err != nil {
+		return nil, errf("list go migrations source: %v", err)
+	}
+
+	if len(funcs) != n {
+		return nil, errf("mismatched migrations and go migrations: %d != %d", n, len(funcs))
+	}
+
+	return funcs, nil
+}
+
+func (m *Migrator) applyMigrations(ctx context.Context, db types.CoreDB, current int, migrations []string, funcs []Func, checksums []string) (n int, retErr error) {
+	if len(migrations)+1 != len(checksums) {
+		retErr = errf("mismatched migrations and checksums: expected %d checksums (+1 for initial state), but found %d", len(migrations), len(checksums))
+		return
+	}
+
+	from := current
+	if m.reapplyAll {
+		from = 0
+	}
+
+	for i := from; i < len(migrations); i++ {
+		if !m.migrationFilter(i + 1) {
+			continue
+		}
+
+		sch := types.SchemaVersion{Version: i + 1, Checksum: checksums[i+1]}
+
+		if funcs != nil && funcs[i] != nil {
+			if err := applyFunc(ctx, db, m.dialect, sch, funcs[i]); err != nil {
+				retErr = errf("apply go migration %d: %v", i+1, err)
+				return
+			}
+
+			n++
+
+			continue
+		}
+
+		if err := applyMigration(ctx, db, m.dialect, sch, migrations[i]); err != nil {
+			retErr = errf("apply migration script %d: %v", i+1, err)
+			return
+		}
+
+		n++
+	}
+
+	return
+}
+
+func (m *Migrator) revertMigrations(ctx context.Context, db types.CoreDB, current int, n int, downs []string, checksums []string) (reverted int, retErr error) {
+	for i := current; i > current-n; i-- {
+		if m.migrationFilter(i) {
+			if err := execContext(ctx, db, downs[i-1]); err != nil {
+				retErr = errf("revert migration script %d: %v", i, err)
+				return
+			}
+		}
+
+		sch := types.SchemaVersion{Version: i - 1, Checksum: checksums[i-1]}
+		if err := schemaops.SaveVersion(ctx, db, m.dialect, sch); err != nil {
+			retErr = errf("revert migration script %d: %v", i, err)
+			return
+		}
+
+		reverted++
+	}
+
+	return
+}
+
+func (m *Migrator) checksumHistory(migrations []string) []string {
+	history := make([]string, len(migrations)+1)
+	history[0] = "" // version 0 has no migrations applied
+
+	for i, mig := range migrations {
+		history[i+1] = m.checksum(history[i] + m.checksum(mig))
+	}
+
+	return history
+}
+
+func (m *Migrator) validateChecksum(schema types.SchemaVersion, runtimeChecksum []string) error {
+	if !m.withChecksumValidation {
+		return nil
+	}
+
+	if schema.Version == 0 {
+		return nil
+	}
+
+	if schema.Checksum != runtimeChecksum[schema.Version] {
+		return errf("runtime checksum %q != database checksum %q", runtimeChecksum[schema.Version], schema.Checksum)
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db types.CoreDB, dialect types.Dialect, schema types.SchemaVersion, migration string) error {
+	if err := execContext(ctx, db, migration); err != nil {
+		return err
+	}
+
+	if err := schemaops.SaveVersion(ctx, db, dialect, schema); err != nil {
+		//nolint:wrapcheck // error is returned from an internal package
+		return err
+	}
+
+	return nil
+}
+
+func applyFunc(ctx context.Context, db types.CoreDB, dialect types.Dialect, schema types.SchemaVersion, fn Func) error {
+	if err := fn(ctx, db); err != nil {
+		return err
+	}
+
+	if err := schemaops.SaveVersion(ctx, db, dialect, schema); err != nil {
+		//nolint:wrapcheck // error is returned from an internal package
+		return err
+	}
+
+	return nil
+}
+
+func execContext(ctx context.Context, db types.CoreDB, query string, args ...any) error {
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec context: %v", err)
+	}
+
+	return nil
+}
+
+func normalizedSha1(query string) string {
+	normalized := normalize(query)
+	//nolint:gosec // in this context, SHA-1 is for change detection, not security.
+	hash := sha1.Sum([]byte(normalized))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1 // Remove whitespace
+		}
+
+		return r
+	}, s)
+}
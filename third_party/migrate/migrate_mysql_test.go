@@ -0,0 +1,229 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mysql"
+
+	"github.com/ladzaretti/migrate"
+	"github.com/ladzaretti/migrate/migratetest"
+)
+
+var (
+	//go:embed testdata/mysql/migrations
+	embedMySQLFS            embed.FS
+	embeddedMySQLMigrations = migrate.EmbeddedMigrations{
+		FS:   embedMySQLFS,
+		Path: "testdata/mysql/migrations",
+	}
+)
+
+func mysqlTestContainer(ctx context.Context) (*mysql.MySQLContainer, error) {
+	ctr, err := mysql.Run(ctx,
+		"mysql:8.4",
+		mysql.WithDatabase("database"),
+		mysql.WithUsername("root"),
+		mysql.WithPassword("mysql"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create test container: %v", err)
+	}
+
+	return ctr, nil
+}
+
+// setupMySQLTestSuite creates a database per test, as MySQL containers
+// do not support snapshots.
+func setupMySQLTestSuite(ctx context.Context, t *testing.T, rawMigrations []string, embeddedMigrations migrate.EmbeddedMigrations) (*testSuite, func()) {
+	t.Helper()
+
+	ctr, err := mysqlTestContainer(ctx)
+	if err != nil {
+		t.Fatalf("create test container: %v", err)
+	}
+
+	cleanup := func() {
+		_ = testcontainers.TerminateContainer(ctr)
+	}
+
+	connString, err := ctr.ConnectionString(ctx, "multiStatements=true")
+	if err != nil {
+		t.Fatalf("connection string: %v", err)
+	}
+
+	cfg, err := mysqldriver.ParseDSN(connString)
+	if err != nil {
+		t.Fatalf("parse connection string: %v", err)
+	}
+
+	root, err := sql.Open("mysql", connString)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	var seq atomic.Int64
+
+	helper := func(ctx context.Context, t *testing.T) *sql.DB {
+		t.Helper()
+
+		cfg := cfg.Clone()
+		cfg.DBName = fmt.Sprintf("test_%d", seq.Add(1))
+
+		if _, err := root.ExecContext(ctx, "CREATE DATABASE "+cfg.DBName); err != nil {
+			t.Fatalf("create database: %v", err)
+		}
+
+		db, err := sql.Open("mysql", cfg.FormatDSN())
+		if err != nil {
+			t.Fatalf("open database: %v", err)
+		}
+
+		t.Cleanup(func() {
+			_ = db.Close()
+		})
+
+		return db
+	}
+
+	suite, err := newTestSuite(testSuiteConfig{
+		dbHelper:           helper,
+		dialect:            migrate.MySQLDialect{},
+		embeddedMigrations: embeddedMigrations,
+		rawMigrations:      rawMigrations,
+	})
+	if err != nil {
+		t.Fatalf("create test suite: %v", err)
+	}
+
+	return suite, func() {
+		_ = root.Close()
+
+		cleanup()
+	}
+}
+
+// TestMigrateWithMySQL omits the tests relying on transactional DDL,
+// see [migrate.MySQLDialect].
+func TestMigrateWithMySQL(t *testing.T) {
+	rawMigrations := []string{
+		`CREATE TABLE
+			IF NOT EXISTS testing_migration_1 (
+				id INTEGER PRIMARY KEY,
+				another_id INTEGER,
+				something_else TEXT
+			);
+		`,
+		`CREATE TABLE
+			IF NOT EXISTS testing_migration_2 (
+				id INTEGER PRIMARY KEY,
+				another_id INTEGER,
+				something_else TEXT
+			);
+		`,
+	}
+
+	suite, cleanup := setupMySQLTestSuite(t.Context(), t, rawMigrations, embeddedMySQLMigrations)
+	defer cleanup()
+
+	t.Run("TestDialect", func(t *testing.T) {
+		if err := migratetest.TestDialect(t.Context(), suite.dbHelper(t.Context(), t), migrate.MySQLDialect{}); err != nil {
+			t.Fatalf("TestDialect: %v", err)
+		}
+	})
+
+	t.Run("ApplyStringMigrations", suite.applyStringMigrations)
+	t.Run("ApplyEmbeddedMigrations", suite.applyEmbeddedMigrations)
+	t.Run("ApplyWithTxDisabled", suite.applyWithTxDisabled)
+	t.Run("ApplyWithNoChecksumValidation", suite.applyWithNoChecksumValidation)
+	t.Run("ApplyWithFilter", suite.applyWithFilter)
+	t.Run("ReapplyAll", suite.reapplyAll)
+	t.Run("RollsBackOnValidationError", suite.rollsBackOnValidationError)
+	t.Run("RollbackEmbeddedMigrations", suite.rollbackEmbeddedMigrations)
+	t.Run("RollbackWithoutDownMigration", suite.rollbackWithoutDownMigration)
+	t.Run("ApplyGoMigrations", suite.applyGoMigrations)
+	t.Run("ConcurrentApply", func(t *testing.T) {
+		mysqlConcurrentApply(t, suite.dbHelper(t.Context(), t))
+	})
+}
+
+// mysqlConcurrentApply verifies that the migration lock
+// is held across the implicit commits of DDL statements.
+func mysqlConcurrentApply(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	dialect := migrate.MySQLDialect{}
+	m := migrate.New(db, dialect)
+
+	planned, err := m.Plan(embeddedMySQLMigrations)
+	if err != nil {
+		t.Fatalf("m.Plan() returned an error: %v", err)
+	}
+
+	migrations, _ := embeddedMySQLMigrations.List()
+
+	// another process holds the migration lock while migrating the database.
+	tx, err := db.BeginTx(t.Context(), &sql.TxOptions{})
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }() //nolint:wsl // rolled back on failure only
+
+	if _, err := tx.ExecContext(t.Context(), dialect.LockQuery()); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	type result struct {
+		n   int
+		err error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		n, err := m.Apply(embeddedMySQLMigrations)
+		done <- result{n, err}
+	}()
+
+	for _, mig := range migrations {
+		if _, err := tx.ExecContext(t.Context(), mig); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	last := planned[len(planned)-1]
+	if _, err := tx.ExecContext(t.Context(), dialect.SaveVersionQuery(), last.Index, last.Checksum); err != nil {
+		t.Fatalf("save version: %v", err)
+	}
+
+	if _, err := tx.ExecContext(t.Context(), dialect.UnlockQuery()); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	r := <-done
+	if r.err != nil {
+		t.Fatalf("m.Apply() returned an error: %v", r.err)
+	}
+
+	if got, want := r.n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(migrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/ladzaretti/migrate"
+	"github.com/ladzaretti/migrate/migratetest"
+)
+
+var (
+	//go:embed testdata/sqlite/migrations
+	embedSQLiteFS embed.FS
+
+	embeddedSQLiteMigrations = migrate.EmbeddedMigrations{
+		FS:   embedSQLiteFS,
+		Path: "testdata/sqlite/migrations",
+	}
+)
+
+// createSQLiteDB is a testing helper that creates an in-memory sqlite
+// database connection.
+func createSQLiteDB(_ context.Context, t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func TestMigrateWithSQLite(t *testing.T) {
+	rawMigrations := []string{
+		`CREATE TABLE
+			IF NOT EXISTS testing_migration_1 (
+				id INTEGER PRIMARY KEY,
+				another_id INTEGER,
+				something_else TEXT
+			);
+		`,
+		`CREATE TABLE
+			IF NOT EXISTS testing_migration_2 (
+				id INTEGER PRIMARY KEY,
+				another_id INTEGER,
+				something_else TEXT
+			);
+		`,
+	}
+
+	suite, err := newTestSuite(testSuiteConfig{
+		dbHelper:           createSQLiteDB,
+		dialect:            migrate.SQLiteDialect{},
+		embeddedMigrations: embeddedSQLiteMigrations,
+		rawMigrations:      rawMigrations,
+	})
+	if err != nil {
+		t.Fatalf("create test suite: %v", err)
+	}
+
+	t.Run("TestDialect", func(t *testing.T) {
+		if err := migratetest.TestDialect(t.Context(), suite.dbHelper(t.Context(), t), migrate.SQLiteDialect{}); err != nil {
+			t.Fatalf("TestDialect: %v", err)
+		}
+	})
+
+	t.Run("ApplyStringMigrations", suite.applyStringMigrations)
+	t.Run("ApplyEmbeddedMigrations", suite.applyEmbeddedMigrations)
+	t.Run("ApplyWithTxDisabled", suite.applyWithTxDisabled)
+	t.Run("ApplyWithNoChecksumValidation", suite.applyWithNoChecksumValidation)
+	t.Run("ApplyWithFilter", suite.applyWithFilter)
+	t.Run("ReapplyAll", suite.reapplyAll)
+	t.Run("RollsBackOnSQLError", suite.rollsBackOnSQLError)
+	t.Run("RollsBackOnValidationError", suite.rollsBackOnValidationError)
+	t.Run("RollbackEmbeddedMigrations", suite.rollbackEmbeddedMigrations)
+	t.Run("RollbackWithoutDownMigration", suite.rollbackWithoutDownMigration)
+	t.Run("RollbackRollsBackOnSQLError", suite.rollbackRollsBackOnSQLError)
+	t.Run("Plan", suite.plan)
+	t.Run("ApplyGoMigrations", suite.applyGoMigrations)
+	t.Run("GoMigrationRollsBackOnError", suite.goMigrationRollsBackOnError)
+}
+
+func TestSQLiteConcurrentApply(t *testing.T) {
+	dsn := "file:" + filepath.Join(t.TempDir(), "db.sqlite") + "?_pragma=busy_timeout(5000)"
+
+	open := func() *sql.DB {
+		db, err := sql.Open("sqlite", dsn)
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+
+		t.Cleanup(func() { _ = db.Close() })
+
+		return db
+	}
+
+	db1, db2 := open(), open()
+	m := migrate.New(db1, migrate.SQLiteDialect{})
+
+	planned, err := m.Plan(embeddedSQLiteMigrations)
+	if err != nil {
+		t.Fatalf("m.Plan() returned an error: %v", err)
+	}
+
+	migrations, _ := embeddedSQLiteMigrations.List()
+
+	if _, err := m.Apply(stringMigrationsFrom()); err != nil {
+		t.Fatalf("m.Apply() returned an error: %v", err)
+	}
+
+	// another process holds the migration lock while migrating the database.
+	tx, err := db2.BeginTx(t.Context(), &sql.TxOptions{})
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }() //nolint:wsl // rolled back on failure only
+
+	if _, err := tx.ExecContext(t.Context(), migrate.SQLiteDialect{}.LockQuery()); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	type result struct {
+		n   int
+		err error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		n, err := m.Apply(embeddedSQLiteMigrations)
+		done <- result{n, err}
+	}()
+
+	for _, mig := range migrations {
+		if _, err := tx.ExecContext(t.Context(), mig); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+
+	last := planned[len(planned)-1]
+	if _, err := tx.ExecContext(t.Context(), migrate.SQLiteDialect{}.SaveVersionQuery(), last.Index, last.Checksum); err != nil {
+		t.Fatalf("save version: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	r := <-done
+	if r.err != nil {
+		t.Fatalf("m.Apply() returned an error: %v", r.err)
+	}
+
+	if got, want := r.n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(migrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+}
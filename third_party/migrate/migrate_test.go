@@ -0,0 +1,738 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ladzaretti/migrate"
+	"github.com/ladzaretti/migrate/types"
+)
+
+type testSuiteConfig struct {
+	dbHelper           func(context.Context, *testing.T) *sql.DB
+	dialect            types.Dialect
+	embeddedMigrations migrate.EmbeddedMigrations
+	rawMigrations      []string
+}
+
+type testSuite struct {
+	testSuiteConfig
+}
+
+func newTestSuite(conf testSuiteConfig) (*testSuite, error) {
+	if len(conf.rawMigrations) < 2 {
+		return nil, errors.New("stringMigrations must have at least 2 elements")
+	}
+
+	embeddedMigrations, err := conf.embeddedMigrations.List()
+	if err != nil {
+		return nil, fmt.Errorf("list embedded migrations: %w", err)
+	}
+
+	if len(embeddedMigrations) < 2 {
+		return nil, errors.New("embeddedMigrations must have at least 2 elements")
+	}
+
+	return &testSuite{testSuiteConfig: conf}, nil
+}
+
+func (s *testSuite) applyStringMigrations(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	if got, want := currentSchemaVersion(m), -1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	n, err := m.Apply(stringMigrationsFrom(s.rawMigrations[0]))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, 1; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), 1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	n, err = m.Apply(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(s.rawMigrations)-1; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("expected schema version = %v, want %v", got, want)
+	}
+}
+
+func (s *testSuite) applyEmbeddedMigrations(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	migrations, _ := s.embeddedMigrations.List()
+
+	if got, want := currentSchemaVersion(m), -1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	n, err := m.Apply(s.embeddedMigrations)
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(migrations); got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(migrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	n, err = m.Apply(s.embeddedMigrations)
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(migrations); got != want {
+		t.Errorf("expected schema version = %v, want %v", got, want)
+	}
+}
+
+func (s *testSuite) applyWithTxDisabled(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+
+	opts := []migrate.Opt{
+		migrate.WithTransaction(false),
+	}
+	m := migrate.New(db, s.dialect, opts...)
+
+	migrations, _ := s.embeddedMigrations.List()
+
+	if got, want := currentSchemaVersion(m), -1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	n, err := m.Apply(s.embeddedMigrations)
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(migrations); got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(migrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+}
+
+func (s *testSuite) applyWithNoChecksumValidation(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	opts := []migrate.Opt{
+		migrate.WithChecksumValidation(false),
+	}
+	m := migrate.New(db, s.dialect, opts...)
+
+	if got, want := currentSchemaVersion(m), -1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	n, err := m.Apply(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(s.rawMigrations); got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	// run the same migration again
+	//
+
+	n, err = m.Apply(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	// run corrupted migration
+	//
+
+	corrupted := copyAppend(s.rawMigrations)
+	corrupted[len(corrupted)-1] += "this string wasn't here before"
+
+	n, err = m.Apply(stringMigrationsFrom(corrupted...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+}
+
+func (s *testSuite) applyWithFilter(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	opts := []migrate.Opt{
+		migrate.WithFilter(func(migrationNumber int) bool {
+			return migrationNumber != 1
+		}),
+	}
+	m := migrate.New(db, s.dialect, opts...)
+
+	if got, want := currentSchemaVersion(m), -1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	n, err := m.Apply(stringMigrationsFrom(s.rawMigrations[0]))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), 0; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	opts = []migrate.Opt{
+		migrate.WithFilter(func(migrationNumber int) bool {
+			return migrationNumber != 2
+		}),
+	}
+	m = migrate.New(db, s.dialect, opts...)
+
+	n, err = m.Apply(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, 1; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), 1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	m = migrate.New(db, s.dialect)
+
+	n, err = m.Apply(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(s.rawMigrations)-1; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+}
+
+func (s *testSuite) reapplyAll(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	n, err := m.Apply(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(s.rawMigrations); got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	opts := []migrate.Opt{
+		migrate.WithReapplyAll(true),
+	}
+	m = migrate.New(db, s.dialect, opts...)
+
+	n, err = m.Apply(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(s.rawMigrations); got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+}
+
+func (s *testSuite) rollsBackOnSQLError(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	if got, want := currentSchemaVersion(m), -1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	n, err := m.Apply(stringMigrationsFrom(s.rawMigrations[0]))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, 1; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), 1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	// run corrupted migration
+	//
+
+	corrupted := copyAppend(s.rawMigrations, "invalid migration script")
+
+	n, err = m.Apply(stringMigrationsFrom(corrupted...))
+	if err == nil {
+		t.Error("expected an error but got none")
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	gotErr, wantPrefix := err.Error(), `apply migration script 3: exec context:`
+	if !strings.HasPrefix(gotErr, wantPrefix) {
+		t.Errorf("unexpected error: got %q, want prefix %q", gotErr, wantPrefix)
+	}
+
+	if got, want := currentSchemaVersion(m), 1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+}
+
+func (s *testSuite) rollsBackOnValidationError(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	if got, want := currentSchemaVersion(m), -1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	n, err := m.Apply(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(s.rawMigrations); got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	// run the same migration again
+	//
+
+	n, err = m.Apply(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	corrupted := copyAppend(s.rawMigrations)
+	corrupted[len(corrupted)-1] += "this string wasn't here before"
+
+	// run corrupted migration
+	//
+
+	n, err = m.Apply(stringMigrationsFrom(corrupted...))
+	if err == nil {
+		t.Error("expected an error but got none")
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	gotErr, wantPrefix := err.Error(), `schema integrity check failed:`
+	if !strings.HasPrefix(gotErr, wantPrefix) {
+		t.Errorf("unexpected error: got %q, want prefix %q", gotErr, wantPrefix)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations); got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+}
+
+func stringMigrationsFrom(s ...string) migrate.StringMigrations {
+	return migrate.StringMigrations(s)
+}
+
+func currentSchemaVersion(m *migrate.Migrator) int {
+	v, err := m.CurrentSchemaVersion(context.Background())
+	if err != nil {
+		return -1
+	}
+
+	return v.Version
+}
+
+func copyAppend[T any](s []T, el ...T) []T {
+	cs := make([]T, len(s), len(s)+len(el))
+	copy(cs, s)
+
+	return append(cs, el...)
+}
+
+func (s *testSuite) rollbackEmbeddedMigrations(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	migrations, _ := s.embeddedMigrations.List()
+
+	downs, err := s.embeddedMigrations.ListDown()
+	if err != nil {
+		t.Fatalf("ListDown() returned an error: %v", err)
+	}
+
+	if got, want := len(downs), len(migrations); got != want {
+		t.Fatalf("down migrations: got %d, want %d", got, want)
+	}
+
+	if _, err := m.Apply(s.embeddedMigrations); err != nil {
+		t.Fatalf("m.Apply() returned an error: %v", err)
+	}
+
+	n, err := m.Rollback(s.embeddedMigrations, 1)
+	if err != nil {
+		t.Errorf("m.Rollback() returned an error: %v", err)
+	}
+
+	if got, want := n, 1; got != want {
+		t.Errorf("reverted migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(migrations)-1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	// reverted migrations are applied again
+	//
+
+	n, err = m.Apply(s.embeddedMigrations)
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, 1; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	n, err = m.Rollback(s.embeddedMigrations, len(migrations))
+	if err != nil {
+		t.Errorf("m.Rollback() returned an error: %v", err)
+	}
+
+	if got, want := n, len(migrations); got != want {
+		t.Errorf("reverted migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), 0; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	if _, err := m.Rollback(s.embeddedMigrations, 1); err == nil {
+		t.Error("expected an error but got none")
+	}
+
+	n, err = m.Apply(s.embeddedMigrations)
+	if err != nil {
+		t.Errorf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(migrations); got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+}
+
+func (s *testSuite) rollbackWithoutDownMigration(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	migrations := migrate.PairedMigrations{
+		{Up: s.rawMigrations[0], Down: "DROP TABLE testing_migration_1;"},
+		{Up: s.rawMigrations[1]},
+	}
+
+	if _, err := m.Apply(migrations); err != nil {
+		t.Fatalf("m.Apply() returned an error: %v", err)
+	}
+
+	n, err := m.Rollback(migrations, 2)
+	if err == nil {
+		t.Error("expected an error but got none")
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("reverted migrations: got %d, want %d", got, want)
+	}
+
+	gotErr, wantSuffix := err.Error(), "migration script 2 has no down migration"
+	if !strings.HasSuffix(gotErr, wantSuffix) {
+		t.Errorf("unexpected error: got %q, want suffix %q", gotErr, wantSuffix)
+	}
+
+	if got, want := currentSchemaVersion(m), 2; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+}
+
+func (s *testSuite) rollbackRollsBackOnSQLError(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	migrations := migrate.PairedMigrations{
+		{Up: s.rawMigrations[0], Down: "DROP TABLE testing_migration_1;"},
+		{Up: s.rawMigrations[1], Down: "DROP TABLE testing_migration_2;"},
+	}
+
+	if _, err := m.Apply(migrations); err != nil {
+		t.Fatalf("m.Apply() returned an error: %v", err)
+	}
+
+	broken := slices.Clone(migrations)
+	broken[0].Down = "DROP TABLE does_not_exist;"
+
+	n, err := m.Rollback(broken, 2)
+	if err == nil {
+		t.Error("expected an error but got none")
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("reverted migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), 2; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	// the second migration was reverted within the rolled back transaction
+	//
+
+	if _, err := m.Rollback(migrations, 2); err != nil {
+		t.Errorf("m.Rollback() returned an error: %v", err)
+	}
+}
+
+func (s *testSuite) plan(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	migrations, _ := s.embeddedMigrations.List()
+
+	planned, err := m.Plan(s.embeddedMigrations)
+	if err != nil {
+		t.Fatalf("m.Plan() returned an error: %v", err)
+	}
+
+	if got, want := len(planned), len(migrations); got != want {
+		t.Errorf("planned migrations: got %d, want %d", got, want)
+	}
+
+	// planning leaves the database as is
+	//
+
+	if got, want := currentSchemaVersion(m), -1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	if _, err := m.Apply(stringMigrationsFrom(s.rawMigrations[0])); err != nil {
+		t.Fatalf("m.Apply() returned an error: %v", err)
+	}
+
+	planned, err = m.Plan(stringMigrationsFrom(s.rawMigrations...))
+	if err != nil {
+		t.Fatalf("m.Plan() returned an error: %v", err)
+	}
+
+	if got, want := len(planned), len(s.rawMigrations)-1; got != want {
+		t.Fatalf("planned migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := planned[0].Index, 2; got != want {
+		t.Errorf("planned migration index: got %d, want %d", got, want)
+	}
+
+	if got, want := planned[0].FirstLine, "CREATE TABLE"; got != want {
+		t.Errorf("planned migration first line: got %q, want %q", got, want)
+	}
+
+	if _, err := m.Apply(stringMigrationsFrom(s.rawMigrations[:2]...)); err != nil {
+		t.Fatalf("m.Apply() returned an error: %v", err)
+	}
+
+	schema, err := m.CurrentSchemaVersion(t.Context())
+	if err != nil {
+		t.Fatalf("m.CurrentSchemaVersion() returned an error: %v", err)
+	}
+
+	if got, want := planned[0].Checksum, schema.Checksum; got != want {
+		t.Errorf("planned migration checksum: got %q, want %q", got, want)
+	}
+
+	// filtered migrations are not planned
+	//
+
+	m = migrate.New(db, s.dialect, migrate.WithFilter(func(int) bool { return false }))
+
+	planned, err = m.Plan(stringMigrationsFrom(append(slices.Clone(s.rawMigrations[:2]), "SELECT 1;")...))
+	if err != nil {
+		t.Fatalf("m.Plan() returned an error: %v", err)
+	}
+
+	if got, want := len(planned), 0; got != want {
+		t.Errorf("planned migrations: got %d, want %d", got, want)
+	}
+}
+
+func (s *testSuite) applyGoMigrations(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	backfill := func(ctx context.Context, db types.CoreDB) error {
+		_, err := db.ExecContext(ctx, `INSERT INTO testing_migration_1 (id, another_id) VALUES (1, 2);`)
+		return err
+	}
+
+	migrations := migrate.Interleaved{
+		Scripts: stringMigrationsFrom(s.rawMigrations...),
+		Funcs:   map[int]migrate.GoMigration{2: {ID: "backfill", Up: backfill}},
+	}
+
+	planned, err := m.Plan(migrations)
+	if err != nil {
+		t.Fatalf("m.Plan() returned an error: %v", err)
+	}
+
+	if got, want := planned[1].FirstLine, "-- go: backfill"; got != want {
+		t.Errorf("planned migration first line: got %q, want %q", got, want)
+	}
+
+	n, err := m.Apply(migrations)
+	if err != nil {
+		t.Fatalf("m.Apply() returned an error: %v", err)
+	}
+
+	if got, want := n, len(s.rawMigrations)+1; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if got, want := currentSchemaVersion(m), len(s.rawMigrations)+1; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	var count int
+	if err := db.QueryRowContext(t.Context(), `SELECT COUNT(*) FROM testing_migration_1;`).Scan(&count); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+
+	if got, want := count, 1; got != want {
+		t.Errorf("backfilled rows: got %d, want %d", got, want)
+	}
+
+	// go migrations are part of the checksum chain
+	//
+
+	migrations.Funcs = map[int]migrate.GoMigration{2: {ID: "backfill_v2", Up: backfill}}
+
+	_, err = m.Apply(migrations)
+	if err == nil {
+		t.Fatal("expected an error but got none")
+	}
+
+	gotErr, wantPrefix := err.Error(), `schema integrity check failed:`
+	if !strings.HasPrefix(gotErr, wantPrefix) {
+		t.Errorf("unexpected error: got %q, want prefix %q", gotErr, wantPrefix)
+	}
+
+	migrations.Funcs = map[int]migrate.GoMigration{len(s.rawMigrations) + 2: {ID: "out_of_range", Up: backfill}}
+
+	if _, err := m.Apply(migrations); err == nil {
+		t.Error("expected an error but got none")
+	}
+}
+
+func (s *testSuite) goMigrationRollsBackOnError(t *testing.T) {
+	db := s.dbHelper(t.Context(), t)
+	m := migrate.New(db, s.dialect)
+
+	migrations := migrate.Interleaved{
+		Scripts: stringMigrationsFrom(s.rawMigrations...),
+		Funcs: map[int]migrate.GoMigration{2: {ID: "failing", Up: func(context.Context, types.CoreDB) error {
+			return errors.New("backfill failed")
+		}}},
+	}
+
+	n, err := m.Apply(migrations)
+	if err == nil {
+		t.Fatal("expected an error but got none")
+	}
+
+	if got, want := n, 0; got != want {
+		t.Errorf("applied migrations: got %d, want %d", got, want)
+	}
+
+	if !strings.Contains(err.Error(), "backfill failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if got, want := currentSchemaVersion(m), 0; got != want {
+		t.Errorf("schema version mismatch: got %v, want %v", got, want)
+	}
+
+	if _, err := db.ExecContext(t.Context(), `SELECT * FROM testing_migration_1;`); err == nil {
+		t.Error("expected the first migration to be rolled back")
+	}
+}
@@ -0,0 +1,163 @@
+package migrate
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// downInfix marks the down migration of a migration file, e.g.,
+// "001_init.down.sql" reverts "001_init.sql".
+const downInfix = ".down"
+
+// Lister is an interface that defines a method for listing
+// the contents of the underlying data source.
+type Lister interface {
+	List() ([]string, error)
+}
+
+// DownLister is a [Lister] that also lists down migrations,
+// reverting the listed migrations, see [Migrator.Rollback].
+type DownLister interface {
+	Lister
+
+	// ListDown returns the down migrations aligned by index with the
+	// migrations returned by List. An empty string marks a migration
+	// that cannot be reverted.
+	ListDown() ([]string, error)
+}
+
+// StringMigrations is a slice of plain string migration script queries to be applied.
+type StringMigrations []string
+
+func (s StringMigrations) List() ([]string, error) {
+	return s, nil
+}
+
+// Migration is a migration script query paired with the query reverting it.
+type Migration struct {
+	Up   string
+	Down string // Down is empty if the migration cannot be reverted.
+}
+
+// PairedMigrations is a slice of migrations paired with their down migrations.
+type PairedMigrations []Migration
+
+var _ DownLister = PairedMigrations{}
+
+func (p PairedMigrations) List() ([]string, error) {
+	ss := make([]string, len(p))
+	for i, m := range p {
+		ss[i] = m.Up
+	}
+
+	return ss, nil
+}
+
+func (p PairedMigrations) ListDown() ([]string, error) {
+	ss := make([]string, len(p))
+	for i, m := range p {
+		ss[i] = m.Down
+	}
+
+	return ss, nil
+}
+
+// EmbeddedMigrations wraps the [embed.FS] and the path to the migration scripts directory.
+type EmbeddedMigrations struct {
+	FS   embed.FS
+	Path string
+}
+
+var _ DownLister = EmbeddedMigrations{}
+
+// List returns a list of migration script queries from the embedded file system.
+//
+// It reads migration scripts from the directory specified
+// in the [EmbeddedMigrations.Path] field within the embedded file system [EmbeddedMigrations.FS]
+// and returns them as a slice of strings.
+//
+// This function does not recursively read subdirectories.
+// Down migrations, i.e., files named with a ".down" infix, e.g., "001.down.sql",
+// are not listed, see [EmbeddedMigrations.ListDown].
+//
+// Queries are ordered lexicographically rather than naturally.
+// For example, the files "1.sql", "2.sql", and "03.sql"
+// will be read in the order: "03.sql", "1.sql", "2.sql".
+//
+// To ensure correct ordering, use zero-padding for numbers, e.g.,
+// "001.sql", "002.sql", "003.sql".
+func (e EmbeddedMigrations) List() ([]string, error) {
+	names, err := e.upNames()
+	if err != nil {
+		return nil, err
+	}
+
+	ss := make([]string, 0, len(names))
+
+	for _, name := range names {
+		s, err := e.FS.ReadFile(path.Join(e.Path, name))
+		if err != nil {
+			return nil, errf("reading embedded migration file: %v", err)
+		}
+
+		ss = append(ss, string(s))
+	}
+
+	return ss, nil
+}
+
+// ListDown returns the down migrations of the migrations returned by
+// [EmbeddedMigrations.List], aligned by index.
+//
+// The down migration of a migration file is the file of the same name with a
+// ".down" infix before its extension, e.g., "001_init.down.sql" reverts "001_init.sql".
+// Migrations without a down migration file are listed as empty strings.
+func (e EmbeddedMigrations) ListDown() ([]string, error) {
+	names, err := e.upNames()
+	if err != nil {
+		return nil, err
+	}
+
+	ss := make([]string, 0, len(names))
+
+	for _, name := range names {
+		ext := path.Ext(name)
+		p := path.Join(e.Path, strings.TrimSuffix(name, ext)+downInfix+ext)
+
+		s, err := e.FS.ReadFile(p)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, errf("reading embedded down migration file: %v", err)
+		}
+
+		ss = append(ss, string(s))
+	}
+
+	return ss, nil
+}
+
+// upNames returns the names of the migration files, excluding down migrations.
+func (e EmbeddedMigrations) upNames() ([]string, error) {
+	files, err := e.FS.ReadDir(e.Path)
+	if err != nil {
+		return nil, errf("reading embedded migration directory: %v", err)
+	}
+
+	names := make([]string, 0, len(files))
+
+	for _, f := range files {
+		if f.IsDir() || isDown(f.Name()) {
+			continue
+		}
+
+		names = append(names, f.Name())
+	}
+
+	return names, nil
+}
+
+func isDown(name string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(name, path.Ext(name)), downInfix)
+}
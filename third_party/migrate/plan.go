@@ -0,0 +1,96 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/ladzaretti/migrate/internal/schemaops"
+)
+
+// PlannedMigration describes a migration that [Migrator.Apply] would apply.
+type PlannedMigration struct {
+	// Index is the position of the migration in the execution order,
+	// i.e., the schema version once applied.
+	Index int
+
+	// Checksum is the cumulative checksum recorded once the migration is applied.
+	Checksum string
+
+	// FirstLine is the first non-blank line of the migration script, e.g., a comment
+	// describing it.
+	FirstLine string
+}
+
+// Plan returns the migrations that [Migrator.Apply] would apply, in order,
+// without applying them. The options of the [Migrator] are honored,
+// e.g., filtered migrations are not planned.
+//
+// Like [Migrator.Apply], it fails if the applied migrations do not match
+// the given ones. The database is left as is: the schema version table
+// is created within a transaction that is rolled back, if missing.
+func (m *Migrator) Plan(from Lister) ([]PlannedMigration, error) {
+	return m.PlanContext(context.Background(), from)
+}
+
+func (m *Migrator) PlanContext(ctx context.Context, from Lister) ([]PlannedMigration, error) {
+	migrations, err := from.List()
+	if err != nil {
+		return nil, errf("list migrations source: %v", err)
+	}
+
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, errf("start transaction: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }() //nolint:wsl // nothing is written
+
+	if err := schemaops.CreateTable(ctx, tx, m.dialect); err != nil {
+		return nil, errf("create schema version table: %v", err)
+	}
+
+	schema, err := currentSchemaVersion(ctx, tx, m.dialect)
+	if err != nil {
+		return nil, errf("current schema version: %v", err)
+	}
+
+	if schema.Version > len(migrations) {
+		return nil, errf("database version (%d) exceeds available migrations (%d)", schema.Version, len(migrations))
+	}
+
+	runtimeChecksum := m.checksumHistory(migrations)
+	if err := m.validateChecksum(schema, runtimeChecksum); err != nil {
+		return nil, errf("schema integrity check failed: %v", err)
+	}
+
+	start := schema.Version
+	if m.reapplyAll {
+		start = 0
+	}
+
+	var planned []PlannedMigration
+
+	for i := start; i < len(migrations); i++ {
+		if !m.migrationFilter(i + 1) {
+			continue
+		}
+
+		planned = append(planned, PlannedMigration{
+			Index:     i + 1,
+			Checksum:  runtimeChecksum[i+1],
+			FirstLine: firstLine(migrations[i]),
+		})
+	}
+
+	return planned, nil
+}
+
+func firstLine(s string) string {
+	for line := range strings.Lines(s) {
+		if l := strings.TrimSpace(line); len(l) > 0 {
+			return l
+		}
+	}
+
+	return ""
+}
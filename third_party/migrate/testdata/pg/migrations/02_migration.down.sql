@@ -0,0 +1 @@
+DROP TABLE IF EXISTS testing_migration_2;
@@ -0,0 +1,6 @@
+CREATE TABLE
+    IF NOT EXISTS testing_migration_2 (
+        id INTEGER PRIMARY KEY,
+        another_id INTEGER,
+        something_else TEXT
+    );
\ No newline at end of file
@@ -0,0 +1,139 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ladzaretti/vlt-cli/vaultcrypto"
+)
+
+var ErrBackupVerification = errors.New("backup verification failed")
+
+// Backup writes a consistent copy of the encrypted vault container
+// at path to a new file at dest. dest must not exist.
+//
+// The vault is copied as stored, it is neither decrypted nor re-encrypted.
+func Backup(ctx context.Context, path string, dest string) error {
+	vaultContainerHandle, err := newVaultContainerHandle(ctx, path, nil, 0)
+	if err != nil {
+		return errf("vault.backup: failed to initialize vault container handle: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		_ = vaultContainerHandle.cleanup()
+	}()
+
+	if err := vaultContainerHandle.db.VacuumInto(ctx, dest); err != nil {
+		return errf("vault.backup: failed to copy vault container: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyBackup test-opens the vault container at path without unlocking it.
+// It verifies the database integrity, that the key derivation headers decode,
+// and that the encrypted vault matches its checksum.
+//
+// Verification failures wrap [ErrBackupVerification].
+func VerifyBackup(ctx context.Context, path string) error {
+	vaultContainerHandle, err := newVaultContainerHandle(ctx, path, nil, 0)
+	if err != nil {
+		return fmt.Errorf("vault.verify backup: %w: %w", ErrBackupVerification, err)
+	}
+	defer func() { //nolint:wsl_v5
+		_ = vaultContainerHandle.cleanup()
+	}()
+
+	db := vaultContainerHandle.db
+
+	problems, err := db.IntegrityCheck(ctx)
+	if err != nil {
+		return errf("vault.verify backup: integrity check: %w", err)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("vault.verify backup: %w: integrity check: %s", ErrBackupVerification, problems[0])
+	}
+
+	cipherdata, err := db.SelectVault(ctx)
+	if err != nil {
+		return fmt.Errorf("vault.verify backup: %w: select vault: %w", ErrBackupVerification, err)
+	}
+
+	for _, phc := range []string{cipherdata.AuthPHC, cipherdata.KDFPHC} {
+		if _, err := vaultcrypto.DecodeAragon2idPHC(phc); err != nil {
+			return fmt.Errorf("vault.verify backup: %w: decode PHC: %w", ErrBackupVerification, err)
+		}
+	}
+
+	ok, err := db.VerifyVaultChecksum(ctx)
+	if err != nil {
+		return errf("vault.verify backup: verify checksum: %w", err)
+	}
+
+	if !ok {
+		return fmt.Errorf("vault.verify backup: %w: vault checksum mismatch", ErrBackupVerification)
+	}
+
+	return nil
+}
+
+// OpenBackup verifies the vault container of the backup at path, see [VerifyBackup],
+// and opens the backed up vault using password, e.g., to verify it restores.
+//
+// The backup itself is never modified, neither by migrations nor by recorded unlock
+// attempts: a temporary copy is opened instead, it is removed once the vault is closed.
+func OpenBackup(ctx context.Context, path string, password []byte) (_ *Vault, retErr error) {
+	copied, err := copyToTemp(path)
+	if err != nil {
+		return nil, errf("vault.open backup: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		if retErr != nil {
+			_ = os.Remove(copied)
+		}
+	}()
+
+	if err := VerifyBackup(ctx, copied); err != nil {
+		return nil, err
+	}
+
+	vlt, err := Open(ctx, copied, WithPassword(password))
+	if err != nil {
+		return nil, err
+	}
+
+	vlt.RegisterCleanup(func() error {
+		return errors.Join(vlt.containerHandle.cleanup(), os.Remove(copied))
+	})
+
+	return vlt, nil
+}
+
+// copyToTemp copies the file at path to a new temporary file, and returns its path.
+func copyToTemp(path string) (_ string, retErr error) {
+	src, err := os.Open(path) //nolint:gosec // path is a user provided vault file
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = src.Close() }() //nolint:wsl_v5
+
+	dst, err := os.CreateTemp("", "vlt-copy-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() { //nolint:wsl_v5
+		retErr = errors.Join(retErr, dst.Close())
+		if retErr != nil {
+			_ = os.Remove(dst.Name())
+		}
+	}()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return "", err
+	}
+
+	return dst.Name(), nil
+}
@@ -0,0 +1,254 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ladzaretti/vlt-cli/securebytes"
+
+	"github.com/ladzaretti/migrate"
+)
+
+// IssueKind classifies an inconsistency found by [Vault.Check].
+type IssueKind string
+
+const (
+	IssueSchemaVersion    IssueKind = "schema-version"
+	IssueIntegrity        IssueKind = "integrity"
+	IssueSecretAuth       IssueKind = "secret-auth"
+	IssueOrphanLabel      IssueKind = "orphan-label"
+	IssueVaultChecksum    IssueKind = "vault-checksum"
+	IssueSnapshotChecksum IssueKind = "snapshot-checksum"
+)
+
+// CheckIssue describes a single inconsistency found by [Vault.Check].
+type CheckIssue struct {
+	Kind   IssueKind
+	Detail string
+
+	// repair fixes the issue, nil if the issue cannot be repaired automatically.
+	repair func(context.Context) error
+
+	// modifiesVault reports whether repair modifies the in-memory vault,
+	// requiring it to be sealed afterwards.
+	modifiesVault bool
+}
+
+// Repairable reports whether the issue can be fixed by [Vault.Repair].
+func (i CheckIssue) Repairable() bool { return i.repair != nil }
+
+// SchemaStatus holds the applied and the latest known schema versions of a database.
+type SchemaStatus struct {
+	Version int
+	Latest  int
+}
+
+// CheckReport holds the results of [Vault.Check].
+type CheckReport struct {
+	ContainerSchema SchemaStatus
+	VaultSchema     SchemaStatus
+	Secrets         int // Secrets is the number of secrets verified.
+	Snapshots       int // Snapshots is the number of history snapshots verified.
+	Issues          []CheckIssue
+}
+
+// Check verifies the consistency of the vault and its container:
+//   - schema versions of both databases are not older than this build.
+//   - both databases pass the SQLite integrity check.
+//   - every secret decrypts successfully, i.e., its AEAD tag is valid.
+//   - every label references an existing secret.
+//   - the checksums of the encrypted vault and its history snapshots match.
+func (vlt *Vault) Check(ctx context.Context) (*CheckReport, error) {
+	r := &CheckReport{}
+
+	if err := vlt.checkSchemas(ctx, r); err != nil {
+		return nil, errf("check: %w", err)
+	}
+
+	if err := vlt.checkIntegrity(ctx, r); err != nil {
+		return nil, errf("check: %w", err)
+	}
+
+	if err := vlt.checkSecrets(ctx, r); err != nil {
+		return nil, errf("check: %w", err)
+	}
+
+	if err := vlt.checkLabels(ctx, r); err != nil {
+		return nil, errf("check: %w", err)
+	}
+
+	if err := vlt.checkChecksums(ctx, r); err != nil {
+		return nil, errf("check: %w", err)
+	}
+
+	return r, nil
+}
+
+// Repair fixes the repairable issues of the given report.
+//
+// It returns the number of repaired issues, and whether the in-memory vault
+// was modified and must be persisted using [Vault.Seal].
+func (vlt *Vault) Repair(ctx context.Context, r *CheckReport) (repaired int, sealRequired bool, _ error) {
+	for _, issue := range r.Issues {
+		if !issue.Repairable() {
+			continue
+		}
+
+		if err := issue.repair(ctx); err != nil {
+			return repaired, sealRequired, errf("repair: %s: %w", issue.Kind, err)
+		}
+
+		repaired++
+
+		sealRequired = sealRequired || issue.modifiesVault
+	}
+
+	return repaired, sealRequired, nil
+}
+
+func (vlt *Vault) checkSchemas(ctx context.Context, r *CheckReport) error {
+	containerSchema, err := schemaStatus(ctx, migrate.New(vlt.containerHandle.conn, migrate.SQLiteDialect{}), vaultContainerMigrations)
+	if err != nil {
+		return fmt.Errorf("container schema: %w", err)
+	}
+
+	vaultSchema, err := schemaStatus(ctx, migrate.New(vlt.conn, migrate.SQLiteDialect{}), vaultMigrations)
+	if err != nil {
+		return fmt.Errorf("vault schema: %w", err)
+	}
+
+	r.ContainerSchema, r.VaultSchema = containerSchema, vaultSchema
+
+	// newer schemas were written by newer builds, in a format this build reads.
+	if s := containerSchema; s.Version < s.Latest {
+		r.add(IssueSchemaVersion, fmt.Sprintf("vault container schema version %d, expected %d", s.Version, s.Latest))
+	}
+
+	if s := vaultSchema; s.Version < s.Latest {
+		r.add(IssueSchemaVersion, fmt.Sprintf("vault schema version %d, expected %d", s.Version, s.Latest))
+	}
+
+	return nil
+}
+
+func schemaStatus(ctx context.Context, m *migrate.Migrator, migrations migrate.Lister) (SchemaStatus, error) {
+	current, err := m.CurrentSchemaVersion(ctx)
+	if err != nil {
+		return SchemaStatus{}, err
+	}
+
+	all, err := migrations.List()
+	if err != nil {
+		return SchemaStatus{}, err
+	}
+
+	return SchemaStatus{Version: current.Version, Latest: len(all)}, nil
+}
+
+func (vlt *Vault) checkIntegrity(ctx context.Context, r *CheckReport) error {
+	containerProblems, err := vlt.containerHandle.db.IntegrityCheck(ctx)
+	if err != nil {
+		return fmt.Errorf("container integrity: %w", err)
+	}
+
+	for _, p := range containerProblems {
+		r.add(IssueIntegrity, "vault container: "+p)
+	}
+
+	vaultProblems, err := vlt.db.IntegrityCheck(ctx)
+	if err != nil {
+		return fmt.Errorf("vault integrity: %w", err)
+	}
+
+	for _, p := range vaultProblems {
+		r.add(IssueIntegrity, "vault: "+p)
+	}
+
+	return nil
+}
+
+func (vlt *Vault) checkSecrets(ctx context.Context, r *CheckReport) error {
+	secrets, err := vlt.db.ExportSecrets(ctx)
+	if err != nil {
+		return fmt.Errorf("secrets: %w", err)
+	}
+
+	for id, s := range secrets {
+		r.Secrets++
+
+		decrypted, err := vlt.aesgcm.Open(s.Nonce, s.Ciphertext)
+		if err != nil {
+			r.add(IssueSecretAuth, fmt.Sprintf("secret %d (%q) failed authentication: %v", id, s.Name, err))
+			continue
+		}
+
+		securebytes.Wipe(decrypted)
+	}
+
+	return nil
+}
+
+func (vlt *Vault) checkLabels(ctx context.Context, r *CheckReport) error {
+	orphans, err := vlt.db.OrphanLabels(ctx)
+	if err != nil {
+		return fmt.Errorf("labels: %w", err)
+	}
+
+	for _, l := range orphans {
+		r.Issues = append(r.Issues, CheckIssue{
+			Kind:   IssueOrphanLabel,
+			Detail: fmt.Sprintf("label %q references missing secret %s", l.Name, l.SecretID),
+			repair: func(ctx context.Context) error {
+				_, err := vlt.db.DeleteLabelByID(ctx, l.ID)
+				return err
+			},
+			modifiesVault: true,
+		})
+	}
+
+	return nil
+}
+
+func (vlt *Vault) checkChecksums(ctx context.Context, r *CheckReport) error {
+	container := vlt.containerHandle.db
+
+	ok, err := container.VerifyVaultChecksum(ctx)
+	if err != nil {
+		return fmt.Errorf("vault checksum: %w", err)
+	}
+
+	if !ok {
+		r.Issues = append(r.Issues, CheckIssue{
+			Kind:   IssueVaultChecksum,
+			Detail: "encrypted vault checksum mismatch",
+			repair: container.RepairVaultChecksum,
+		})
+	}
+
+	snapshots, err := container.VerifyHistory(ctx)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+
+	for _, s := range snapshots {
+		r.Snapshots++
+
+		if s.Valid {
+			continue
+		}
+
+		r.Issues = append(r.Issues, CheckIssue{
+			Kind:   IssueSnapshotChecksum,
+			Detail: fmt.Sprintf("history snapshot %d (%s) checksum mismatch", s.ID, s.CreatedAt),
+			repair: func(ctx context.Context) error {
+				return container.DeleteHistorySnapshot(ctx, s.ID)
+			},
+		})
+	}
+
+	return nil
+}
+
+func (r *CheckReport) add(kind IssueKind, detail string) {
+	r.Issues = append(r.Issues, CheckIssue{Kind: kind, Detail: detail})
+}
@@ -0,0 +1,190 @@
+package vault_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultcontainer"
+)
+
+func TestVault_CheckRepair(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+	password := []byte("password")
+
+	v, err := vault.New(t.Context(), vaultPath, password, vault.WithMaxHistorySnapshots(5))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	if _, err := v.InsertNewSecret(t.Context(), "name", []byte("secret"), []string{"label"}); err != nil {
+		t.Fatalf("failed to insert new secret: %v", err)
+	}
+
+	if _, err := v.Seal(t.Context()); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	corruptContainer(t, vaultPath)
+
+	v, err = vault.Open(t.Context(), vaultPath, vault.WithPassword(password))
+	if err != nil {
+		t.Fatalf("failed to open vault: %v", err)
+	}
+	defer func() { _ = v.Close() }() //nolint:wsl_v5
+
+	report, err := v.Check(t.Context())
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+
+	want := map[vault.IssueKind]bool{
+		vault.IssueVaultChecksum:    true,
+		vault.IssueSnapshotChecksum: true,
+	}
+
+	if got := len(report.Issues); got != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", got, len(want), report.Issues)
+	}
+
+	for _, issue := range report.Issues {
+		if !want[issue.Kind] {
+			t.Errorf("unexpected issue: %s: %s", issue.Kind, issue.Detail)
+		}
+
+		if !issue.Repairable() {
+			t.Errorf("issue %s: want repairable", issue.Kind)
+		}
+	}
+
+	if got, want := report.Secrets, 1; got != want {
+		t.Errorf("got %d secrets checked, want %d", got, want)
+	}
+
+	repaired, sealRequired, err := v.Repair(t.Context(), report)
+	if err != nil {
+		t.Fatalf("repair: %v", err)
+	}
+
+	if repaired != len(want) || sealRequired {
+		t.Errorf("got repaired=%d sealRequired=%v, want repaired=%d sealRequired=false", repaired, sealRequired, len(want))
+	}
+
+	report, err = v.Check(t.Context())
+	if err != nil {
+		t.Fatalf("check after repair: %v", err)
+	}
+
+	if len(report.Issues) != 0 {
+		t.Errorf("got issues after repair: %+v", report.Issues)
+	}
+}
+
+// corruptContainer overwrites the stored checksums of the
+// encrypted vault and of its history snapshots.
+func corruptContainer(t *testing.T, path string) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open container: %v", err)
+	}
+	defer func() { _ = db.Close() }() //nolint:wsl_v5
+
+	for _, q := range []string{
+		"UPDATE vault_container SET checksum = x'00' WHERE id = 0;",
+		"UPDATE vault_history SET checksum = x'00';",
+	} {
+		if _, err := db.ExecContext(t.Context(), q); err != nil {
+			t.Fatalf("corrupt container: %v", err)
+		}
+	}
+}
+
+func TestVaultContainer_HistorySnapshots(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"), vault.WithMaxHistorySnapshots(5))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	for i := range 3 {
+		if _, err := v.InsertNewSecret(t.Context(), fmt.Sprintf("name%d", i), []byte("secret"), nil); err != nil {
+			t.Fatalf("failed to insert new secret: %v", err)
+		}
+
+		if _, err := v.Seal(t.Context()); err != nil {
+			t.Fatalf("failed to seal vault: %v", err)
+		}
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", vaultPath)
+	if err != nil {
+		t.Fatalf("open container: %v", err)
+	}
+	defer func() { _ = db.Close() }() //nolint:wsl_v5
+
+	container := vaultcontainer.New(db, 5)
+
+	snapshots, err := container.ListHistorySnapshots(t.Context())
+	if err != nil {
+		t.Fatalf("list history snapshots: %v", err)
+	}
+
+	if got, want := len(snapshots), 3; got != want {
+		t.Fatalf("got %d snapshots, want %d", got, want)
+	}
+
+	latest := snapshots[len(snapshots)-1]
+
+	s, err := container.SelectHistorySnapshot(t.Context(), latest.ID)
+	if err != nil {
+		t.Fatalf("select history snapshot: %v", err)
+	}
+
+	if got := int64(len(s.Snapshot)); got != latest.Size || got == 0 {
+		t.Errorf("got snapshot of %d bytes, want %d", got, latest.Size)
+	}
+
+	if len(s.Nonce) == 0 {
+		t.Errorf("want the snapshot nonce")
+	}
+
+	if _, err := container.SelectHistorySnapshot(t.Context(), -1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("want %v, got %v", sql.ErrNoRows, err)
+	}
+
+	deleted, err := container.PruneHistory(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("prune history: %v", err)
+	}
+
+	if deleted != 2 {
+		t.Errorf("got %d deleted snapshots, want 2", deleted)
+	}
+
+	n, err := container.CountHistorySnapshots(t.Context())
+	if err != nil {
+		t.Fatalf("count history snapshots: %v", err)
+	}
+
+	if n != 1 {
+		t.Errorf("got %d snapshots, want 1", n)
+	}
+
+	if _, err := container.SelectHistorySnapshot(t.Context(), latest.ID); err != nil {
+		t.Errorf("want the latest snapshot kept, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package vault_test
+
+import (
+	"bufio"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/vault"
+)
+
+// crashHelperEnv holds the vault path for [TestSealCrashHelper],
+// when set the test runs as a helper process.
+const crashHelperEnv = "VLT_TEST_CRASH_HELPER_VAULT"
+
+var crashTestPassword = []byte("password")
+
+// TestSealCrashHelper is not a real test, it is executed as a subprocess by
+// [TestSeal_CrashSafety]. It keeps modifying and sealing the vault until killed,
+// reporting the number of secrets after every successful seal.
+func TestSealCrashHelper(t *testing.T) {
+	path := os.Getenv(crashHelperEnv)
+	if path == "" {
+		t.Skip("helper process for TestSeal_CrashSafety")
+	}
+
+	v, err := vault.Open(t.Context(), path, vault.WithPassword(crashTestPassword))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open: %v\n", err)
+		os.Exit(1)
+	}
+
+	secrets, err := v.ExportSecrets(t.Context())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export: %v\n", err)
+		os.Exit(1)
+	}
+
+	// large values make each seal take long enough to be interrupted mid-write.
+	value := make([]byte, 64<<10)
+
+	for i := len(secrets); ; i++ {
+		_, _ = rand.Read(value)
+
+		if _, err := v.InsertNewSecret(t.Context(), fmt.Sprintf("secret-%d", i), value, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "insert: %v\n", err)
+			os.Exit(1)
+		}
+
+		if _, err := v.Seal(t.Context()); err != nil {
+			fmt.Fprintf(os.Stderr, "seal: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(i + 1)
+	}
+}
+
+func TestSeal_CrashSafety(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping crash injection test in short mode")
+	}
+
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, crashTestPassword)
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	const rounds = 5
+
+	for round := range rounds {
+		sealed := runUntilKilled(t, vaultPath)
+
+		v, err := vault.Open(t.Context(), vaultPath, vault.WithPassword(crashTestPassword))
+		if err != nil {
+			t.Fatalf("round %d: failed to open vault after crash: %v", round, err)
+		}
+
+		secrets, err := v.ExportSecrets(t.Context())
+		if err != nil {
+			t.Fatalf("round %d: failed to export secrets after crash: %v", round, err)
+		}
+
+		if err := v.Close(); err != nil {
+			t.Fatalf("round %d: failed to close vault: %v", round, err)
+		}
+
+		if got := len(secrets); got < sealed {
+			t.Errorf("round %d: got %d secrets after crash, want at least %d sealed", round, got, sealed)
+		}
+	}
+}
+
+// runUntilKilled starts [TestSealCrashHelper] against the vault at path,
+// kills it at a random point after its first seal, and returns
+// the number of secrets it reported as sealed.
+func runUntilKilled(t *testing.T, path string) (sealed int) {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSealCrashHelper$") //nolint:gosec // re-executing the test binary.
+	cmd.Env = append(os.Environ(), crashHelperEnv+"="+path)
+	cmd.Stderr = os.Stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("stdout pipe: %v", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start helper process: %v", err)
+	}
+
+	reports := make(chan int)
+
+	go func() {
+		defer close(reports)
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if n, err := strconv.Atoi(strings.TrimSpace(scanner.Text())); err == nil {
+				reports <- n
+			}
+		}
+	}()
+
+	n, ok := <-reports
+	if !ok {
+		_ = cmd.Wait()
+		t.Fatalf("helper process exited before sealing")
+	}
+
+	sealed = n
+
+	delay, err := rand.Int(rand.Reader, big.NewInt(int64(50*time.Millisecond)))
+	if err != nil {
+		t.Fatalf("random delay: %v", err)
+	}
+
+	deadline := time.After(time.Duration(delay.Int64()))
+
+	for {
+		select {
+		case n, ok := <-reports:
+			if ok {
+				sealed = n
+				continue
+			}
+		case <-deadline:
+			_ = cmd.Process.Kill()
+			deadline = nil
+
+			continue
+		}
+
+		break
+	}
+
+	_ = cmd.Wait()
+
+	return sealed
+}
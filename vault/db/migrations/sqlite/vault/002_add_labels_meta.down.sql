@@ -0,0 +1,2 @@
+-- Reverts 002_add_labels_meta.sql, label display metadata is lost.
+DROP TABLE IF EXISTS labels_meta;
@@ -0,0 +1,13 @@
+-- Optional display metadata of labels, keyed by label name.
+-- Rows are independent of the labels table, so metadata
+-- is kept while no secret carries the label.
+CREATE TABLE
+    IF NOT EXISTS labels_meta (
+        name TEXT PRIMARY KEY,
+
+        -- ANSI color name, e.g., red or bright-blue.
+        color TEXT NOT NULL DEFAULT '',
+
+        -- short symbol shown before the label, e.g., an emoji.
+        icon TEXT NOT NULL DEFAULT ''
+    );
@@ -0,0 +1,2 @@
+-- Reverts 003_add_secret_usage.sql, usage statistics are lost.
+DROP TABLE IF EXISTS secret_usage;
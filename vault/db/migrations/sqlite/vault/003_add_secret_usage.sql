@@ -0,0 +1,13 @@
+-- Optional per-secret usage statistics, recorded when usage tracking is enabled.
+-- Kept apart from the secrets table, so recording an access
+-- does not change the secret updated_at timestamp.
+CREATE TABLE
+    IF NOT EXISTS secret_usage (
+        secret_id INTEGER PRIMARY KEY REFERENCES secrets (id) ON DELETE CASCADE,
+
+        -- number of times the secret value was retrieved.
+        access_count INTEGER NOT NULL DEFAULT 0,
+
+        -- unix time of the last retrieval, in seconds.
+        last_accessed_at INTEGER NOT NULL
+    );
@@ -0,0 +1,2 @@
+-- Reverts 004_add_secret_attributes.sql, secret attributes are lost.
+DROP TABLE IF EXISTS secret_attributes;
@@ -0,0 +1,15 @@
+-- Optional per-secret key-value attributes, e.g., env=prod.
+-- Keys are stored in plain text within the vault to allow lookups,
+-- values are encrypted like secret values.
+CREATE TABLE
+    IF NOT EXISTS secret_attributes (
+        secret_id INTEGER NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+        key TEXT NOT NULL,
+
+        ciphertext BLOB NOT NULL,
+
+        -- 96-bit (12-byte) nonce used for AES-GCM encryption.
+        nonce BLOB NOT NULL,
+
+        PRIMARY KEY (secret_id, key)
+    );
@@ -0,0 +1,2 @@
+-- Reverts 005_add_secret_url.sql, secret URLs are lost.
+ALTER TABLE secrets DROP COLUMN url;
@@ -0,0 +1,3 @@
+-- Optional canonical URL of the site a secret belongs to, e.g., a login page.
+-- Stored in plain text within the vault to allow lookups by domain.
+ALTER TABLE secrets ADD COLUMN url TEXT NOT NULL DEFAULT '';
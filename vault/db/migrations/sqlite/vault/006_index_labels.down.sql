@@ -0,0 +1,23 @@
+-- Reverts 006_index_labels.sql, the labels table is rebuilt with a TEXT secret_id.
+DROP INDEX IF EXISTS secrets_name;
+
+ALTER TABLE labels RENAME TO labels_new;
+
+CREATE TABLE
+    labels (
+        id INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        secret_id TEXT NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+        UNIQUE (name, secret_id)
+    );
+
+INSERT INTO
+    labels (id, name, secret_id)
+SELECT
+    id,
+    name,
+    secret_id
+FROM
+    labels_new;
+
+DROP TABLE labels_new;
@@ -0,0 +1,38 @@
+-- Indexes the lookups done when filtering secrets.
+-- The labels table is rebuilt with an INTEGER secret_id: joining the former
+-- TEXT column on secrets (id) applied numeric affinity to every label row,
+-- so no index could be used and each join scanned all labels.
+-- Labels of missing secrets are dropped, no secret references them.
+ALTER TABLE labels RENAME TO labels_old;
+
+CREATE TABLE
+    labels (
+        id INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        secret_id INTEGER NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+        UNIQUE (name, secret_id)
+    );
+
+INSERT INTO
+    labels (id, name, secret_id)
+SELECT
+    id,
+    name,
+    secret_id
+FROM
+    labels_old
+WHERE
+    secret_id IN (
+        SELECT
+            id
+        FROM
+            secrets
+    );
+
+DROP TABLE labels_old;
+
+-- labels of a secret, the UNIQUE (name, secret_id) index serves lookups by name.
+CREATE INDEX IF NOT EXISTS labels_secret_id ON labels (secret_id);
+
+-- secret names, used by GLOB patterns with a literal prefix, e.g., 'github*'.
+CREATE INDEX IF NOT EXISTS secrets_name ON secrets (name);
@@ -0,0 +1,11 @@
+-- Encrypted metadata index (secret names and labels), used to serve
+-- metadata-only queries without decrypting and deserializing the vault.
+ALTER TABLE vault_container
+ADD COLUMN index_nonce BLOB;
+
+ALTER TABLE vault_container
+ADD COLUMN index_encrypted BLOB;
+
+-- Checksum of the encrypted vault the index was built from.
+ALTER TABLE vault_container
+ADD COLUMN index_checksum BLOB;
@@ -0,0 +1,9 @@
+-- Vault members, each holding a copy of the vault key wrapped
+-- (age encrypted) to the member's X25519 public key.
+CREATE TABLE
+    IF NOT EXISTS vault_members (
+        name TEXT NOT NULL UNIQUE,
+        recipient TEXT PRIMARY KEY,
+        wrapped_key BLOB NOT NULL,
+        created_at TEXT NOT NULL DEFAULT (datetime ('now'))
+    );
@@ -0,0 +1,14 @@
+-- Failed unlock attempts since the last successful unlock,
+-- used to throttle password guessing through the cli.
+CREATE TABLE
+    IF NOT EXISTS unlock_attempts (
+        id INTEGER PRIMARY KEY CHECK (id = 0),
+        failed INTEGER NOT NULL DEFAULT 0,
+        -- Time of the last failed attempt, in unix milliseconds.
+        last_failed_at INTEGER NOT NULL DEFAULT 0
+    );
+
+INSERT
+OR IGNORE INTO unlock_attempts (id)
+VALUES
+    (0);
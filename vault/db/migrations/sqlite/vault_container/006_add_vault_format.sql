@@ -0,0 +1,12 @@
+-- Format version of the vault, checked before migrations are applied,
+-- so builds too old to read the vault fail with a precise error.
+CREATE TABLE
+    IF NOT EXISTS vault_format (
+        id INTEGER PRIMARY KEY CHECK (id = 0),
+        -- Format version of the newest build that opened the vault.
+        version INTEGER NOT NULL,
+        -- Oldest format version of builds able to read the vault.
+        min_reader_version INTEGER NOT NULL,
+        -- First vlt release reading min_reader_version, empty if unknown.
+        min_reader_release TEXT NOT NULL DEFAULT ''
+    );
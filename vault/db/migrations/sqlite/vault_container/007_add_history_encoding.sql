@@ -0,0 +1,8 @@
+-- Encoding of vault history snapshots. Snapshots are recorded in full by the
+-- after_vault_update trigger, and re-encoded by vlt once the vault is sealed:
+--   full: the encrypted vault as stored before the update.
+--   checkpoint: the compressed vault, encrypted.
+--   delta: the compressed changes reverting the next newer snapshot,
+--          or the current vault, to the snapshot, encrypted.
+ALTER TABLE vault_history
+ADD COLUMN encoding TEXT NOT NULL DEFAULT 'full';
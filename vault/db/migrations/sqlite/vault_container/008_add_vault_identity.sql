@@ -0,0 +1,19 @@
+-- Identity of the vault, independent of the path of its file, e.g., to name
+-- the vault in password prompts, and to find its session after it was moved.
+-- Both are stored unencrypted, as these are read before the vault is unlocked.
+CREATE TABLE
+    IF NOT EXISTS vault_identity (
+        id INTEGER PRIMARY KEY CHECK (id = 0),
+        -- Random (version 4) UUID, kept when the vault is moved or rotated.
+        uuid TEXT NOT NULL,
+        -- Human readable title of the vault, empty if not set.
+        title TEXT NOT NULL DEFAULT ''
+    );
+
+INSERT
+OR IGNORE INTO vault_identity (id, uuid)
+VALUES
+    (
+        0,
+        lower(hex (randomblob (4))) || '-' || lower(hex (randomblob (2))) || '-4' || substr(lower(hex (randomblob (2))), 2) || '-' || substr('89ab', 1 + (abs(random()) % 4), 1) || substr(lower(hex (randomblob (2))), 2) || '-' || lower(hex (randomblob (6)))
+    );
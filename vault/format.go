@@ -0,0 +1,192 @@
+package vault
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultcontainer"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/ladzaretti/migrate"
+	migratetypes "github.com/ladzaretti/migrate/types"
+)
+
+// Format versions of the vault, recorded in the vault container.
+//
+// FormatVersion is incremented with each change to the container or vault schema.
+// MinReaderVersion is raised to FormatVersion only if builds reading an older format
+// cannot read the new one, e.g., a new encryption scheme, as opposed to a new table
+// older builds do not use.
+const (
+	FormatVersion    = 5
+	MinReaderVersion = 1
+)
+
+// formatReleases maps format versions to the first vlt release reading them,
+// named in the [FormatError] of older builds. An entry is added when
+// [MinReaderVersion] is raised, e.g., 2: "1.4".
+var formatReleases = map[int]string{}
+
+// FormatError is returned when opening a vault written in a format
+// this build cannot read.
+type FormatError struct {
+	Version          int    // Version is the format version of the vault.
+	MinReaderVersion int    // MinReaderVersion is the oldest format version able to read the vault.
+	MinReaderRelease string // MinReaderRelease is the first vlt release reading the vault, empty if unknown.
+}
+
+func (e *FormatError) Error() string {
+	if len(e.MinReaderRelease) > 0 {
+		return fmt.Sprintf("this vault requires vlt >= %s (vault format version %d, this vlt reads up to version %d)",
+			e.MinReaderRelease, e.Version, FormatVersion)
+	}
+
+	return fmt.Sprintf("this vault requires a newer vlt (vault format version %d, this vlt reads up to version %d)",
+		e.Version, FormatVersion)
+}
+
+func (*FormatError) Unwrap() error { return vaulterrors.ErrUnsupportedVaultFormat }
+
+// checkFormat verifies that this build can read the vault, before any migration
+// is applied to it. Vaults predating format versions are readable by all builds.
+func checkFormat(ctx context.Context, container *vaultcontainer.VaultContainer) error {
+	f, err := container.SelectVaultFormat(ctx)
+	if err != nil {
+		return fmt.Errorf("select vault format: %w", err)
+	}
+
+	if f != nil && f.MinReaderVersion > FormatVersion {
+		return &FormatError{
+			Version:          f.Version,
+			MinReaderVersion: f.MinReaderVersion,
+			MinReaderRelease: f.MinReaderRelease,
+		}
+	}
+
+	return nil
+}
+
+// upgradeFormat records the format of this build in the vault,
+// unless the vault was opened by a newer build already.
+func upgradeFormat(ctx context.Context, container *vaultcontainer.VaultContainer) error {
+	return container.UpgradeVaultFormat(ctx, vaultcontainer.VaultFormat{
+		Version:          FormatVersion,
+		MinReaderVersion: MinReaderVersion,
+		MinReaderRelease: formatReleases[MinReaderVersion],
+	})
+}
+
+// applyMigrations applies the migrations to db. A schema newer than the migrations
+// of this build is left as is: it was written by a newer build, in a format this
+// build was verified to read, see [checkFormat].
+func applyMigrations(ctx context.Context, db migratetypes.DBTX, migrations migrate.Lister) error {
+	m := migrate.New(db, migrate.SQLiteDialect{})
+
+	_, err := m.ApplyContext(ctx, migrations)
+	if err == nil {
+		return nil
+	}
+
+	if s, statusErr := schemaStatus(ctx, m, migrations); statusErr == nil && s.Version > s.Latest {
+		return nil
+	}
+
+	return err
+}
+
+// planMigrations returns the migrations pending for db. None are pending if its
+// schema is newer than the migrations of this build, see [applyMigrations].
+func planMigrations(ctx context.Context, db migratetypes.DBTX, migrations migrate.Lister) ([]migrate.PlannedMigration, error) {
+	m := migrate.New(db, migrate.SQLiteDialect{})
+
+	planned, err := m.PlanContext(ctx, migrations)
+	if err == nil {
+		return planned, nil
+	}
+
+	if s, statusErr := schemaStatus(ctx, m, migrations); statusErr == nil && s.Version > s.Latest {
+		return nil, nil
+	}
+
+	return nil, err
+}
+
+// MigrationPlan holds the migrations pending for a vault,
+// applied the next time it is opened, see [PlanMigrations].
+type MigrationPlan struct {
+	Container []migrate.PlannedMigration
+	Vault     []migrate.PlannedMigration
+}
+
+// PlanMigrations unlocks the vault at path using opts, e.g., [WithPassword],
+// and returns the migrations pending for its container and vault databases,
+// without applying them.
+//
+// The vault is never modified, a temporary copy is opened instead, see [OpenBackup].
+func PlanMigrations(ctx context.Context, path string, opts ...Option) (_ *MigrationPlan, retErr error) {
+	copied, err := copyToTemp(path)
+	if err != nil {
+		return nil, errf("vault.plan migrations: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		retErr = errors.Join(retErr, os.Remove(copied))
+	}()
+
+	plan := &MigrationPlan{}
+
+	if plan.Container, err = planContainerMigrations(ctx, copied); err != nil {
+		return nil, errf("vault.plan migrations: %w", err)
+	}
+
+	vlt, err := Open(ctx, copied, append(opts, func(c *config) { c.migrationPlan = plan })...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := errors.Join(vlt.Close(), vlt.containerHandle.cleanup()); err != nil {
+		return nil, errf("vault.plan migrations: %w", err)
+	}
+
+	return plan, nil
+}
+
+// planContainerMigrations returns the migrations pending for the vault container at path.
+func planContainerMigrations(ctx context.Context, path string) (_ []migrate.PlannedMigration, retErr error) {
+	db, err := sql.Open("sqlite", containerDSN(path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { //nolint:wsl_v5
+		retErr = errors.Join(retErr, db.Close())
+	}()
+
+	if err := checkFormat(ctx, vaultcontainer.New(db, 0)); err != nil {
+		return nil, err
+	}
+
+	return planMigrations(ctx, db, vaultContainerMigrations)
+}
+
+// RollbackSchema reverts the last n migrations of the vault schema using their
+// down migrations, e.g., before downgrading to an older vlt release. Data stored
+// by the reverted migrations, e.g., in tables they added, is lost.
+//
+// It returns the resulting schema version. The in-memory vault is modified,
+// it must be persisted using [Vault.Seal].
+func (vlt *Vault) RollbackSchema(ctx context.Context, n int) (int, error) {
+	m := migrate.New(vlt.conn, migrate.SQLiteDialect{})
+
+	if _, err := m.RollbackContext(ctx, vaultMigrations, n); err != nil {
+		return 0, errf("rollback schema: %w", err)
+	}
+
+	current, err := m.CurrentSchemaVersion(ctx)
+	if err != nil {
+		return 0, errf("rollback schema: %w", err)
+	}
+
+	return current.Version, nil
+}
@@ -0,0 +1,315 @@
+package vault_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"github.com/ladzaretti/migrate"
+)
+
+func TestVault_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		queries []string
+		wantErr string
+	}{
+		{
+			name: "newer readable schema",
+			queries: []string{
+				fmt.Sprintf("UPDATE vault_format SET version = %d;", vault.FormatVersion+1),
+				"UPDATE schema_version SET version = version + 1;",
+			},
+		},
+		{
+			name: "unreadable with release",
+			queries: []string{
+				fmt.Sprintf("UPDATE vault_format SET version = %[1]d, min_reader_version = %[1]d, min_reader_release = '9.9';", vault.FormatVersion+1),
+				"UPDATE schema_version SET version = version + 1;",
+			},
+			wantErr: "this vault requires vlt >= 9.9",
+		},
+		{
+			name: "unreadable",
+			queries: []string{
+				fmt.Sprintf("UPDATE vault_format SET version = %[1]d, min_reader_version = %[1]d;", vault.FormatVersion+1),
+			},
+			wantErr: "this vault requires a newer vlt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+
+			v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+			if err != nil {
+				t.Fatalf("failed to create vault: %v", err)
+			}
+
+			if err := v.Close(); err != nil {
+				t.Fatalf("failed to close vault: %v", err)
+			}
+
+			execContainer(t, vaultPath, tt.queries...)
+
+			v, err = vault.Open(t.Context(), vaultPath, vault.WithPassword([]byte("password")))
+			if err == nil {
+				_ = v.Close()
+			}
+
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			var formatErr *vault.FormatError
+			if !errors.As(err, &formatErr) || !errors.Is(err, vaulterrors.ErrUnsupportedVaultFormat) {
+				t.Fatalf("want a format error, got %v", err)
+			}
+
+			if !strings.Contains(formatErr.Error(), tt.wantErr) {
+				t.Errorf("want error containing %q, got %q", tt.wantErr, formatErr.Error())
+			}
+		})
+	}
+}
+
+// execContainer executes the given queries on the vault container at path.
+func execContainer(t *testing.T, path string, queries ...string) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open container: %v", err)
+	}
+	defer func() { _ = db.Close() }() //nolint:wsl_v5
+
+	for _, q := range queries {
+		if _, err := db.ExecContext(t.Context(), q); err != nil {
+			t.Fatalf("exec %q: %v", q, err)
+		}
+	}
+}
+
+func TestVault_RollbackSchema(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	id, err := v.InsertNewSecret(t.Context(), "foo", []byte("bar"), []string{"l1", "l2"})
+	if err != nil {
+		t.Fatalf("insert secret: %v", err)
+	}
+
+	if err := v.SetSecretURL(t.Context(), id, "https://example.com"); err != nil {
+		t.Fatalf("set url: %v", err)
+	}
+
+	report, err := v.Check(t.Context())
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+
+	latest := report.VaultSchema.Latest
+
+	if _, err := v.RollbackSchema(t.Context(), latest); err == nil {
+		t.Errorf("want an error reverting the initial migration")
+	}
+
+	// reverts the labels rebuild and the urls.
+	version, err := v.RollbackSchema(t.Context(), 2)
+	if err != nil {
+		t.Fatalf("rollback schema: %v", err)
+	}
+
+	if want := latest - 2; version != want {
+		t.Errorf("want schema version %d, got %d", want, version)
+	}
+
+	if _, err := v.Seal(t.Context()); err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	// the reverted migrations are applied again on open.
+	v, err = vault.Open(t.Context(), vaultPath, vault.WithPassword([]byte("password")))
+	if err != nil {
+		t.Fatalf("failed to open vault: %v", err)
+	}
+	defer func() { _ = v.Close() }() //nolint:wsl_v5
+
+	urls, err := v.SecretURLs(t.Context())
+	if err != nil {
+		t.Fatalf("secret urls: %v", err)
+	}
+
+	if len(urls) != 0 {
+		t.Errorf("want the urls of the reverted migration removed, got %v", urls)
+	}
+
+	secret, err := v.ShowSecret(t.Context(), id)
+	if err != nil || string(secret) != "bar" {
+		t.Errorf("want secret %q, got %q (%v)", "bar", secret, err)
+	}
+
+	secrets, err := v.FilterSecrets(t.Context(), "", "", []string{"l*"})
+	if err != nil {
+		t.Fatalf("filter secrets: %v", err)
+	}
+
+	if got, want := secrets[id].Labels, []string{"l1", "l2"}; len(secrets) != 1 || !slices.Equal(got, want) {
+		t.Errorf("want labels %q kept, got %v", want, secrets)
+	}
+}
+
+func TestPlanMigrations(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+	password := vault.WithPassword([]byte("password"))
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	plan, err := vault.PlanMigrations(t.Context(), vaultPath, password)
+	if err != nil {
+		t.Fatalf("plan migrations: %v", err)
+	}
+
+	if len(plan.Container) != 0 || len(plan.Vault) != 0 {
+		t.Errorf("want no pending migrations, got %+v", plan)
+	}
+
+	version, err := v.RollbackSchema(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("rollback schema: %v", err)
+	}
+
+	if _, err := v.Seal(t.Context()); err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	// planning does not apply the migrations.
+	for range 2 {
+		plan, err = vault.PlanMigrations(t.Context(), vaultPath, password)
+		if err != nil {
+			t.Fatalf("plan migrations: %v", err)
+		}
+
+		if len(plan.Container) != 0 {
+			t.Errorf("want no pending container migrations, got %+v", plan.Container)
+		}
+
+		if len(plan.Vault) != 1 || plan.Vault[0].Index != version+1 {
+			t.Fatalf("want vault migration %d pending, got %+v", version+1, plan.Vault)
+		}
+
+		if want := "-- Indexes the lookups done when filtering secrets."; plan.Vault[0].FirstLine != want {
+			t.Errorf("want first line %q, got %q", want, plan.Vault[0].FirstLine)
+		}
+	}
+
+	if _, err := vault.PlanMigrations(t.Context(), vaultPath, vault.WithPassword([]byte("wrong"))); !errors.Is(err, vault.ErrAuthenticationFailed) {
+		t.Errorf("want %v, got %v", vault.ErrAuthenticationFailed, err)
+	}
+}
+
+func TestVault_ConcurrentMigrations(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	revertLatestContainerMigration(t, vaultPath)
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, 4)
+	)
+
+	for i := range errs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			v, err := vault.Open(t.Context(), vaultPath, vault.WithPassword([]byte("password")))
+			if err == nil {
+				err = v.Close()
+			}
+
+			errs[i] = err
+		}()
+	}
+
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		t.Errorf("concurrent open: %v", err)
+	}
+}
+
+// revertLatestContainerMigration reverts the latest migration
+// of the vault container at path, which adds the vault identity table.
+func revertLatestContainerMigration(t *testing.T, path string) {
+	t.Helper()
+
+	dir := filepath.Join("db", "migrations", "sqlite", "vault_container")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrations := make(migrate.PairedMigrations, 0, len(files))
+
+	for _, f := range files {
+		up, err := os.ReadFile(f) //nolint:gosec // test data
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		migrations = append(migrations, migrate.Migration{Up: string(up)})
+	}
+
+	migrations[len(migrations)-1].Down = "DROP TABLE vault_identity;"
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open container: %v", err)
+	}
+	defer func() { _ = db.Close() }() //nolint:wsl_v5
+
+	if _, err := migrate.New(db, migrate.SQLiteDialect{}).Rollback(migrations, 1); err != nil {
+		t.Fatalf("revert container migration: %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+package vault
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultcontainer"
+	"github.com/ladzaretti/vlt-cli/vaultcrypto"
+)
+
+var (
+	ErrSnapshotNotFound = errors.New("history snapshot not found")
+	ErrBrokenHistory    = errors.New("history snapshot chain is broken")
+)
+
+const (
+	// checkpointInterval is the maximum number of consecutive delta snapshots.
+	// Every checkpointInterval-th snapshot is stored in full, bounding the deltas
+	// applied to restore a snapshot.
+	checkpointInterval = 8
+
+	// deltaChunkSize is the granularity of snapshot deltas,
+	// matching the default SQLite page size.
+	deltaChunkSize = 4096
+
+	deltaVersion    = 1
+	deltaHeaderSize = 1 + 2*sha256.Size + 8
+)
+
+// encodeLatestSnapshot re-encodes the history snapshot recorded in full by the
+// vault container when prev, the previously stored vault, was replaced by the
+// vault serialized as current.
+//
+// The snapshot is stored as a compressed delta reverting current to it, or as
+// a compressed checkpoint every [checkpointInterval] snapshots. Snapshots not
+// recorded from prev, e.g., if history is disabled, are left as is.
+func (vlt *Vault) encodeLatestSnapshot(ctx context.Context, container *vaultcontainer.VaultContainer, prev *vaultcontainer.CipherData, current []byte) error {
+	latest, err := container.SelectLatestHistorySnapshot(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return errf("encode snapshot: %w", err)
+	}
+
+	if latest.Encoding != vaultcontainer.EncodingFull || !bytes.Equal(latest.Snapshot, prev.Vault) {
+		return nil
+	}
+
+	snapshot, err := vlt.aesgcm.Open(prev.Nonce, prev.Vault)
+	if err != nil {
+		return errf("encode snapshot: decrypt previous vault: %w", err)
+	}
+	defer securebytes.Wipe(snapshot)
+
+	checkpoint, err := checkpointDue(ctx, container, latest.ID)
+	if err != nil {
+		return errf("encode snapshot: %w", err)
+	}
+
+	encoding, payload := vaultcontainer.EncodingDelta, encodeDelta(current, snapshot)
+	if checkpoint {
+		encoding, payload = vaultcontainer.EncodingCheckpoint, slices.Clone(snapshot)
+	}
+	defer securebytes.Wipe(payload)
+
+	compressed, err := deflate(payload)
+	if err != nil {
+		return errf("encode snapshot: %w", err)
+	}
+	defer securebytes.Wipe(compressed)
+
+	nonce, err := vaultcrypto.RandBytes(vaultcrypto.NonceSizeGCM)
+	if err != nil {
+		return errf("encode snapshot: failed to generate random nonce: %w", err)
+	}
+
+	sealed, err := vlt.aesgcm.Seal(nonce, compressed)
+	if err != nil {
+		return errf("encode snapshot: failed to seal data with AES-GCM: %w", err)
+	}
+
+	if err := container.UpdateHistorySnapshot(ctx, latest.ID, encoding, nonce, sealed); err != nil {
+		return errf("encode snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// checkpointDue reports whether the snapshot id is to be stored in full,
+// as the snapshots preceding it are the maximum number of consecutive deltas.
+func checkpointDue(ctx context.Context, container *vaultcontainer.VaultContainer, id int) (bool, error) {
+	snapshots, err := container.ListHistorySnapshots(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	deltas := 0
+
+	for _, s := range slices.Backward(snapshots) {
+		if s.ID >= id {
+			continue
+		}
+
+		if s.Encoding != vaultcontainer.EncodingDelta {
+			break
+		}
+
+		deltas++
+	}
+
+	return deltas >= checkpointInterval-1, nil
+}
+
+// HistorySnapshots returns the history snapshots of the vault container,
+// oldest first.
+func (vlt *Vault) HistorySnapshots(ctx context.Context) ([]vaultcontainer.SnapshotInfo, error) {
+	return vlt.containerHandle.db.ListHistorySnapshots(ctx)
+}
+
+// PruneHistory deletes all but the keep most recent history snapshots, and
+// returns the number of deleted snapshots. Delta snapshots depend on newer
+// snapshots only, so the kept snapshots remain restorable.
+func (vlt *Vault) PruneHistory(ctx context.Context, keep int) (int64, error) {
+	n, err := vlt.containerHandle.db.PruneHistory(ctx, keep)
+	if err != nil {
+		return 0, errf("prune history: %w", err)
+	}
+
+	return n, nil
+}
+
+// RestoreSnapshot replaces the in-memory vault with the history snapshot id,
+// e.g., as listed by [vaultcontainer.VaultContainer.ListHistorySnapshots].
+//
+// Delta snapshots are reconstructed from the newer snapshots they depend on,
+// up to the nearest one stored in full, or the current vault. The in-memory
+// vault is modified, it must be persisted using [Vault.Seal], which records
+// the replaced vault as a new history snapshot.
+func (vlt *Vault) RestoreSnapshot(ctx context.Context, id int) error {
+	snapshot, err := vlt.reconstructSnapshot(ctx, id)
+	if err != nil {
+		return errf("restore snapshot: %w", err)
+	}
+
+	// sqlite copies the snapshot, see [Vault.open].
+	defer securebytes.Wipe(snapshot)
+
+	if err := Deserialize(vlt.conn, snapshot); err != nil {
+		return errf("restore snapshot: %w", err)
+	}
+
+	// snapshots taken by older builds may predate the current vault schema.
+	if err := applyMigrations(ctx, vlt.conn, vaultMigrations); err != nil {
+		return errf("restore snapshot: failed to apply migrations: %w", err)
+	}
+
+	return nil
+}
+
+// reconstructSnapshot returns the serialized vault of the history snapshot id.
+func (vlt *Vault) reconstructSnapshot(ctx context.Context, id int) ([]byte, error) {
+	container := vlt.containerHandle.db
+
+	snapshots, err := container.ListHistorySnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(snapshots, func(s vaultcontainer.SnapshotInfo) bool { return s.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrSnapshotNotFound, id)
+	}
+
+	// deltas are applied starting from the nearest snapshot at or after i
+	// stored in full, or from the current vault if all are deltas.
+	base := slices.IndexFunc(snapshots[i:], func(s vaultcontainer.SnapshotInfo) bool {
+		return s.Encoding != vaultcontainer.EncodingDelta
+	})
+
+	var state []byte
+
+	if base < 0 {
+		base = len(snapshots)
+		state, err = vlt.openCurrentVault(ctx)
+	} else {
+		base += i
+		state, err = vlt.openSnapshot(ctx, snapshots[base].ID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for j := base - 1; j >= i; j-- {
+		delta, err := vlt.openSnapshot(ctx, snapshots[j].ID)
+		if err != nil {
+			securebytes.Wipe(state)
+			return nil, err
+		}
+
+		next, err := applyDelta(state, delta)
+		securebytes.Wipe(state)
+		securebytes.Wipe(delta)
+
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %d: %w", snapshots[j].ID, err)
+		}
+
+		state = next
+	}
+
+	return state, nil
+}
+
+// openCurrentVault returns the serialized vault as currently stored.
+func (vlt *Vault) openCurrentVault(ctx context.Context) ([]byte, error) {
+	cipherdata, err := vlt.containerHandle.db.SelectVault(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return vlt.aesgcm.Open(cipherdata.Nonce, cipherdata.Vault)
+}
+
+// openSnapshot decrypts the history snapshot id. It returns the serialized
+// vault of full and checkpoint snapshots, and the delta of delta snapshots.
+func (vlt *Vault) openSnapshot(ctx context.Context, id int) ([]byte, error) {
+	s, err := vlt.containerHandle.db.SelectHistorySnapshot(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %d: %w", id, err)
+	}
+
+	if len(s.Nonce) == 0 {
+		return nil, fmt.Errorf("snapshot %d: no nonce recorded", id)
+	}
+
+	plaintext, err := vlt.aesgcm.Open(s.Nonce, s.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %d: %w", id, err)
+	}
+
+	switch s.Encoding {
+	case vaultcontainer.EncodingFull:
+		return plaintext, nil
+	case vaultcontainer.EncodingCheckpoint, vaultcontainer.EncodingDelta:
+		defer securebytes.Wipe(plaintext)
+
+		inflated, err := inflate(plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %d: %w", id, err)
+		}
+
+		return inflated, nil
+	default:
+		securebytes.Wipe(plaintext)
+		return nil, fmt.Errorf("snapshot %d: unknown encoding %q", id, s.Encoding)
+	}
+}
+
+// encodeDelta returns the delta reverting base to target: the chunks of target
+// that differ from base, along with the checksums of both.
+func encodeDelta(base, target []byte) []byte {
+	baseSum, targetSum := sha256.Sum256(base), sha256.Sum256(target)
+
+	delta := make([]byte, 0, deltaHeaderSize)
+	delta = append(delta, deltaVersion)
+	delta = append(delta, baseSum[:]...)
+	delta = append(delta, targetSum[:]...)
+	delta = binary.BigEndian.AppendUint64(delta, uint64(len(target)))
+
+	for off := 0; off < len(target); off += deltaChunkSize {
+		chunk := target[off:min(off+deltaChunkSize, len(target))]
+
+		if end := off + len(chunk); end <= len(base) && bytes.Equal(chunk, base[off:end]) {
+			continue
+		}
+
+		delta = binary.BigEndian.AppendUint32(delta, uint32(off/deltaChunkSize)) //nolint:gosec // vaults are far smaller than 16 TiB
+		delta = append(delta, chunk...)
+	}
+
+	return delta
+}
+
+// applyDelta returns the target encoded by [encodeDelta] for base.
+func applyDelta(base, delta []byte) ([]byte, error) {
+	if len(delta) < deltaHeaderSize || delta[0] != deltaVersion {
+		return nil, errors.New("invalid delta header")
+	}
+
+	baseSum := sha256.Sum256(base)
+	if !bytes.Equal(baseSum[:], delta[1:1+sha256.Size]) {
+		return nil, ErrBrokenHistory
+	}
+
+	targetSum := delta[1+sha256.Size : 1+2*sha256.Size]
+
+	n := binary.BigEndian.Uint64(delta[1+2*sha256.Size : deltaHeaderSize])
+	if n > uint64(len(base))+uint64(len(delta)) {
+		return nil, errors.New("invalid delta length")
+	}
+
+	target := make([]byte, n)
+	copy(target, base)
+
+	for rest := delta[deltaHeaderSize:]; len(rest) > 0; {
+		if len(rest) < 4 {
+			return nil, errors.New("truncated delta")
+		}
+
+		off := int(binary.BigEndian.Uint32(rest)) * deltaChunkSize
+		if off >= len(target) {
+			return nil, errors.New("invalid delta chunk")
+		}
+
+		size := min(deltaChunkSize, len(target)-off)
+		if len(rest) < 4+size {
+			return nil, errors.New("truncated delta")
+		}
+
+		copy(target[off:], rest[4:4+size])
+		rest = rest[4+size:]
+	}
+
+	if sum := sha256.Sum256(target); !bytes.Equal(sum[:], targetSum) {
+		securebytes.Wipe(target)
+		return nil, ErrBrokenHistory
+	}
+
+	return target, nil
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func inflate(data []byte) ([]byte, error) {
+	return io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+}
@@ -0,0 +1,238 @@
+package vault_test
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultcontainer"
+)
+
+func TestVault_RestoreSnapshot(t *testing.T) {
+	const seals = 12
+
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+	password := []byte("password")
+
+	v, err := vault.New(t.Context(), vaultPath, password, vault.WithMaxHistorySnapshots(seals))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	for i := range seals {
+		if _, err := v.InsertNewSecret(t.Context(), fmt.Sprintf("name%d", i), []byte("secret"), nil); err != nil {
+			t.Fatalf("failed to insert new secret: %v", err)
+		}
+
+		if _, err := v.Seal(t.Context()); err != nil {
+			t.Fatalf("failed to seal vault: %v", err)
+		}
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	snapshots := historySnapshots(t, vaultPath)
+	if got := len(snapshots); got != seals {
+		t.Fatalf("got %d snapshots, want %d", got, seals)
+	}
+
+	encodings := map[string]int{}
+	for _, s := range snapshots {
+		encodings[s.Encoding]++
+	}
+
+	if encodings[vaultcontainer.EncodingDelta] == 0 || encodings[vaultcontainer.EncodingCheckpoint] == 0 || encodings[vaultcontainer.EncodingFull] != 0 {
+		t.Errorf("want delta and checkpoint snapshots only, got %v", encodings)
+	}
+
+	if delta, checkpoint := snapshotSize(snapshots, vaultcontainer.EncodingDelta), snapshotSize(snapshots, vaultcontainer.EncodingCheckpoint); delta >= checkpoint {
+		t.Errorf("want deltas smaller than checkpoints, got %d >= %d bytes", delta, checkpoint)
+	}
+
+	// the i-th snapshot holds the vault before the i-th seal, i.e., i secrets.
+	for i, s := range snapshots {
+		v, err := vault.Open(t.Context(), vaultPath, vault.WithPassword(password))
+		if err != nil {
+			t.Fatalf("failed to open vault: %v", err)
+		}
+
+		if err := v.RestoreSnapshot(t.Context(), s.ID); err != nil {
+			t.Fatalf("restore snapshot %d (%s): %v", s.ID, s.Encoding, err)
+		}
+
+		secrets, err := v.ExportSecrets(t.Context())
+		if err != nil {
+			t.Fatalf("export secrets: %v", err)
+		}
+
+		if got := len(secrets); got != i {
+			t.Errorf("snapshot %d (%s): got %d secrets, want %d", s.ID, s.Encoding, got, i)
+		}
+
+		_ = v.Close()
+	}
+
+	v, err = vault.Open(t.Context(), vaultPath, vault.WithPassword(password))
+	if err != nil {
+		t.Fatalf("failed to open vault: %v", err)
+	}
+	defer func() { _ = v.Close() }() //nolint:wsl_v5
+
+	if err := v.RestoreSnapshot(t.Context(), -1); !errors.Is(err, vault.ErrSnapshotNotFound) {
+		t.Errorf("want %v, got %v", vault.ErrSnapshotNotFound, err)
+	}
+}
+
+func TestVault_RestoreSnapshotBrokenHistory(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+	password := []byte("password")
+
+	v, err := vault.New(t.Context(), vaultPath, password, vault.WithMaxHistorySnapshots(5))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	for i := range 3 {
+		if _, err := v.InsertNewSecret(t.Context(), fmt.Sprintf("name%d", i), []byte("secret"), nil); err != nil {
+			t.Fatalf("failed to insert new secret: %v", err)
+		}
+
+		if _, err := v.Seal(t.Context()); err != nil {
+			t.Fatalf("failed to seal vault: %v", err)
+		}
+	}
+
+	snapshots := historySnapshots(t, vaultPath)
+
+	// deleting a snapshot breaks the deltas of older snapshots.
+	execContainer(t, vaultPath, fmt.Sprintf("DELETE FROM vault_history WHERE id = %d;", snapshots[1].ID))
+
+	if err := v.RestoreSnapshot(t.Context(), snapshots[0].ID); !errors.Is(err, vault.ErrBrokenHistory) {
+		t.Errorf("want %v, got %v", vault.ErrBrokenHistory, err)
+	}
+
+	if err := v.RestoreSnapshot(t.Context(), snapshots[2].ID); err != nil {
+		t.Errorf("restore snapshot: %v", err)
+	}
+
+	_ = v.Close()
+}
+
+func TestVault_AutoVacuum(t *testing.T) {
+	const threshold = 16 << 10
+
+	tests := []struct {
+		name      string
+		threshold int64
+		vacuumed  bool
+	}{
+		{name: "disabled", threshold: 0, vacuumed: false},
+		{name: "enabled", threshold: threshold, vacuumed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+			password := []byte("password")
+
+			v, err := vault.New(t.Context(), vaultPath, password, vault.WithMaxHistorySnapshots(1))
+			if err != nil {
+				t.Fatalf("failed to create vault: %v", err)
+			}
+
+			_ = v.Close()
+
+			v, err = vault.Open(t.Context(), vaultPath, vault.WithPassword(password),
+				vault.WithMaxHistorySnapshots(1), vault.WithAutoVacuumThreshold(tt.threshold))
+			if err != nil {
+				t.Fatalf("failed to open vault: %v", err)
+			}
+			defer func() { _ = v.Close() }() //nolint:wsl_v5
+
+			// incompressible secrets grow the vault, and the snapshots pruned once these are removed.
+			ids := make([]int, 4)
+
+			for i := range ids {
+				secret := make([]byte, 32<<10)
+				_, _ = rand.Read(secret)
+
+				ids[i], err = v.InsertNewSecret(t.Context(), fmt.Sprintf("name%d", i), secret, nil)
+				if err != nil {
+					t.Fatalf("failed to insert new secret: %v", err)
+				}
+
+				if _, err := v.Seal(t.Context()); err != nil {
+					t.Fatalf("failed to seal vault: %v", err)
+				}
+			}
+
+			for _, id := range ids {
+				if _, err := v.DeleteSecretsByIDs(t.Context(), id); err != nil {
+					t.Fatalf("failed to delete secret: %v", err)
+				}
+
+				if _, err := v.Seal(t.Context()); err != nil {
+					t.Fatalf("failed to seal vault: %v", err)
+				}
+			}
+
+			stats := containerPageStats(t, vaultPath)
+
+			if got := stats.Reclaimable() <= threshold; got != tt.vacuumed {
+				t.Errorf("reclaimable %d bytes: vacuumed %v, want %v", stats.Reclaimable(), got, tt.vacuumed)
+			}
+		})
+	}
+}
+
+// historySnapshots lists the history snapshots of the vault container at path.
+func historySnapshots(t *testing.T, path string) []vaultcontainer.SnapshotInfo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open container: %v", err)
+	}
+	defer func() { _ = db.Close() }() //nolint:wsl_v5
+
+	snapshots, err := vaultcontainer.New(db, 0).ListHistorySnapshots(t.Context())
+	if err != nil {
+		t.Fatalf("list history snapshots: %v", err)
+	}
+
+	return snapshots
+}
+
+func snapshotSize(snapshots []vaultcontainer.SnapshotInfo, encoding string) (size int64) {
+	for _, s := range snapshots {
+		if s.Encoding == encoding && s.Size > size {
+			size = s.Size
+		}
+	}
+
+	return size
+}
+
+// containerPageStats returns the page usage of the vault container at path.
+func containerPageStats(t *testing.T, path string) vaultcontainer.PageStats {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open container: %v", err)
+	}
+	defer func() { _ = db.Close() }() //nolint:wsl_v5
+
+	stats, err := vaultcontainer.New(db, 0).PageStats(t.Context())
+	if err != nil {
+		t.Fatalf("page stats: %v", err)
+	}
+
+	return stats
+}
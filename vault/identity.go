@@ -0,0 +1,133 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultcontainer"
+
+	"github.com/google/uuid"
+)
+
+// MaxTitleLen is the maximum length of a vault title, in characters.
+const MaxTitleLen = 64
+
+// Identity identifies a vault independent of the path of its file,
+// e.g., to name it in password prompts, or to find its session once it was moved.
+//
+// The identity is stored unencrypted in the vault container,
+// it is read before the vault is unlocked, see [ReadIdentity].
+type Identity struct {
+	ID    string // ID is a random UUID, kept when the vault file is moved, copied or rotated.
+	Title string // Title is the human readable title of the vault, empty if not set.
+}
+
+// WithIdentity sets the identity of a vault created by [New],
+// e.g., to keep the identity of a rotated vault. A random ID is generated if empty.
+func WithIdentity(i Identity) Option {
+	return func(c *config) {
+		c.identity = i
+	}
+}
+
+// ValidateTitle verifies that title is a single line of printable
+// characters, at most [MaxTitleLen] long. An empty title is valid.
+func ValidateTitle(title string) error {
+	if !utf8.ValidString(title) {
+		return fmt.Errorf("title %q is not valid UTF-8", title)
+	}
+
+	if n := utf8.RuneCountInString(title); n > MaxTitleLen {
+		return fmt.Errorf("title is %d characters long, at most %d are allowed", n, MaxTitleLen)
+	}
+
+	if strings.ContainsFunc(title, func(r rune) bool { return !unicode.IsPrint(r) && r != ' ' }) {
+		return fmt.Errorf("title %q holds non-printable characters", title)
+	}
+
+	if len(title) > 0 && len(strings.TrimSpace(title)) != len(title) {
+		return fmt.Errorf("title %q starts or ends with spaces", title)
+	}
+
+	return nil
+}
+
+// ReadIdentity returns the identity of the vault at path, without unlocking it.
+func ReadIdentity(ctx context.Context, path string, opts ...Option) (*Identity, error) {
+	config := &config{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	vaultContainerHandle, err := newVaultContainerHandle(ctx, path, config.containerSnapshot, config.maxHistorySnapshots)
+	if err != nil {
+		return nil, errf("read identity: failed to initialize vault container handle: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		_ = vaultContainerHandle.cleanup()
+	}()
+
+	return vaultContainerHandle.identity(ctx)
+}
+
+// SetTitle sets the title of the vault at path, without unlocking it,
+// an empty title clears it. See [ValidateTitle].
+func SetTitle(ctx context.Context, path string, title string, opts ...Option) error {
+	if err := ValidateTitle(title); err != nil {
+		return errf("set title: %w", err)
+	}
+
+	config := &config{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	vaultContainerHandle, err := newVaultContainerHandle(ctx, path, config.containerSnapshot, config.maxHistorySnapshots)
+	if err != nil {
+		return errf("set title: failed to initialize vault container handle: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		_ = vaultContainerHandle.cleanup()
+	}()
+
+	if err := vaultContainerHandle.db.UpdateTitle(ctx, title); err != nil {
+		return errf("set title: %w", err)
+	}
+
+	return nil
+}
+
+// Identity returns the identity of the vault.
+func (vlt *Vault) Identity(ctx context.Context) (*Identity, error) {
+	return vlt.containerHandle.identity(ctx)
+}
+
+func (h *vaultContainerHandle) identity(ctx context.Context) (*Identity, error) {
+	i, err := h.db.SelectIdentity(ctx)
+	if err != nil {
+		return nil, errf("select identity: %w", err)
+	}
+
+	return &Identity{ID: i.UUID, Title: i.Title}, nil
+}
+
+// storeIdentity stores the identity of a new vault, with a random ID if not set.
+func (h *vaultContainerHandle) storeIdentity(ctx context.Context, i Identity) error {
+	if len(i.ID) == 0 {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return errf("generate vault id: %w", err)
+		}
+
+		i.ID = id.String()
+	}
+
+	if err := ValidateTitle(i.Title); err != nil {
+		return err
+	}
+
+	return h.db.UpsertIdentity(ctx, vaultcontainer.Identity{UUID: i.ID, Title: i.Title})
+}
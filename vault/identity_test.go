@@ -0,0 +1,120 @@
+package vault_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/vault"
+
+	"github.com/google/uuid"
+)
+
+func TestVault_Identity(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"), vault.WithIdentity(vault.Identity{Title: "work"}))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	i, err := v.Identity(t.Context())
+	if err != nil {
+		t.Fatalf("identity: %v", err)
+	}
+
+	if _, err := uuid.Parse(i.ID); err != nil {
+		t.Errorf("want a uuid, got %q: %v", i.ID, err)
+	}
+
+	if i.Title != "work" {
+		t.Errorf("want title %q, got %q", "work", i.Title)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	if err := vault.SetTitle(t.Context(), vaultPath, "personal"); err != nil {
+		t.Fatalf("set title: %v", err)
+	}
+
+	got, err := vault.ReadIdentity(t.Context(), vaultPath)
+	if err != nil {
+		t.Fatalf("read identity: %v", err)
+	}
+
+	if want := (vault.Identity{ID: i.ID, Title: "personal"}); *got != want {
+		t.Errorf("want identity %+v, got %+v", want, *got)
+	}
+
+	for _, title := range []string{" work", "work\n", strings.Repeat("x", vault.MaxTitleLen+1), "\xff"} {
+		if err := vault.SetTitle(t.Context(), vaultPath, title); err == nil {
+			t.Errorf("want an error setting title %q", title)
+		}
+	}
+
+	// a vault created with the identity of another, e.g., when rotated, keeps it.
+	rotatedPath := filepath.Join(t.TempDir(), ".vlt.temp")
+
+	v, err = vault.New(t.Context(), rotatedPath, []byte("password"), vault.WithIdentity(*got))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	rotated, err := vault.ReadIdentity(t.Context(), rotatedPath)
+	if err != nil {
+		t.Fatalf("read identity: %v", err)
+	}
+
+	if *rotated != *got {
+		t.Errorf("want identity %+v, got %+v", *got, *rotated)
+	}
+}
+
+func TestVault_IdentityMigration(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	revertLatestContainerMigration(t, vaultPath)
+
+	// vaults predating identities are given a random id once migrated.
+	i, err := vault.ReadIdentity(t.Context(), vaultPath)
+	if err != nil {
+		t.Fatalf("read identity: %v", err)
+	}
+
+	id, err := uuid.Parse(i.ID)
+	if err != nil {
+		t.Fatalf("want a uuid, got %q: %v", i.ID, err)
+	}
+
+	if id.Version() != 4 || id.Variant() != uuid.RFC4122 {
+		t.Errorf("want a version 4 uuid, got %q", i.ID)
+	}
+
+	if len(i.Title) != 0 {
+		t.Errorf("want no title, got %q", i.Title)
+	}
+
+	again, err := vault.ReadIdentity(t.Context(), vaultPath)
+	if err != nil {
+		t.Fatalf("read identity: %v", err)
+	}
+
+	if *again != *i {
+		t.Errorf("want identity %+v kept, got %+v", *i, *again)
+	}
+}
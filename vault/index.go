@@ -0,0 +1,295 @@
+package vault
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+	"github.com/ladzaretti/vlt-cli/vaultcrypto"
+
+	"github.com/ladzaretti/migrate"
+)
+
+// ErrIndexUnavailable indicates that the vault container holds no usable metadata index,
+// either because none was stored yet, or because it is out of date with the vault.
+var ErrIndexUnavailable = errors.New("metadata index unavailable")
+
+// indexData is the serialized form of the metadata index.
+//
+// Indexes written before label metadata was introduced
+// are a bare array of secret entries, see [decodeIndex].
+type indexData struct {
+	Secrets []indexEntry     `json:"secrets"`
+	Labels  []indexLabelMeta `json:"labels,omitempty"`
+}
+
+// indexLabelMeta is the serialized form of the display metadata of a label.
+type indexLabelMeta struct {
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+	Icon  string `json:"icon,omitempty"`
+}
+
+// indexEntry is the serialized form of a single secret in the metadata index.
+type indexEntry struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Labels []string `json:"labels"`
+}
+
+// Index is a read-only view of the secret metadata, names, labels and label display metadata.
+//
+// It is loaded from the metadata index stored next to the encrypted vault,
+// which is encrypted separately, so it can be queried without decrypting
+// and deserializing the whole vault.
+//
+// The index holds no secret values.
+type Index struct {
+	db           *vaultdb.VaultDB
+	cleanupFuncs []cleanupFunc
+	closeOnce    sync.Once
+}
+
+// OpenIndex loads the metadata index of the vault container at the given path.
+//
+// The index can only be opened using a session key, see [WithSessionKey].
+// If the container holds no index, or the index is out of date with the stored vault,
+// [ErrIndexUnavailable] is returned and the caller should fall back to [Open].
+func OpenIndex(ctx context.Context, path string, opts ...Option) (_ *Index, retErr error) {
+	config := &config{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.key == nil {
+		return nil, errf("vault.open index: %w: no session key provided", ErrIndexUnavailable)
+	}
+
+	vaultContainerHandle, err := newVaultContainerHandle(ctx, path, config.containerSnapshot, config.maxHistorySnapshots)
+	if err != nil {
+		return nil, errf("vault.open index: failed to initialize vault container handle: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		retErr = errors.Join(retErr, vaultContainerHandle.cleanup())
+	}()
+
+	cipherindex, err := vaultContainerHandle.db.SelectIndex(ctx)
+	if err != nil {
+		return nil, errf("vault.open index: failed to select index from container database: %w", err)
+	}
+
+	if cipherindex.Index == nil || !cipherindex.Current {
+		return nil, errf("vault.open index: %w", ErrIndexUnavailable)
+	}
+
+	aes, err := vaultcrypto.NewAESGCM(config.key)
+	if err != nil {
+		return nil, errf("vault.open index: failed to initialize AES-GCM cipher: %w", err)
+	}
+
+	decrypted, err := aes.Open(cipherindex.Nonce, cipherindex.Index)
+	if err != nil {
+		return nil, errf("vault.open index: failed to decrypt index: %w", err)
+	}
+	defer securebytes.Wipe(decrypted)
+
+	data, err := decodeIndex(decrypted)
+	if err != nil {
+		return nil, errf("vault.open index: failed to decode index: %w", err)
+	}
+
+	idx := &Index{}
+	defer func() {
+		if retErr != nil {
+			retErr = errors.Join(retErr, idx.Close())
+			return
+		}
+	}()
+
+	if err := idx.load(ctx, data); err != nil {
+		return nil, errf("vault.open index: %w", err)
+	}
+
+	return idx, nil
+}
+
+// decodeIndex decodes a serialized metadata index,
+// either an [indexData] object or a legacy bare array of entries.
+func decodeIndex(bs []byte) (indexData, error) {
+	var data indexData
+
+	if trimmed := bytes.TrimSpace(bs); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &data.Secrets)
+		return data, err
+	}
+
+	err := json.Unmarshal(bs, &data)
+
+	return data, err
+}
+
+// load populates an in-memory database with the index data,
+// so that queries share the semantics of the vault database.
+func (idx *Index) load(ctx context.Context, data indexData) error {
+	var (
+		db   *sql.DB
+		conn *sql.Conn
+	)
+
+	idx.cleanupFuncs = append(idx.cleanupFuncs, func() error {
+		// prefer conn.Close if available to avoid double-closing
+		// the shared driver connection.
+		if conn != nil {
+			return conn.Close()
+		}
+
+		if db != nil {
+			return db.Close()
+		}
+
+		return nil
+	})
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return err
+	}
+
+	conn, err = db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, pragma); err != nil {
+		return err
+	}
+
+	if _, err := migrate.New(conn, migrate.SQLiteDialect{}).ApplyContext(ctx, vaultMigrations); err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	storeTx := vaultdb.New(conn).WithTx(tx)
+
+	for _, e := range data.Secrets {
+		if _, err := storeTx.InsertNewSecretWithID(ctx, e.ID, e.Name, []byte{}, []byte{}); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+
+		for _, l := range e.Labels {
+			if _, err := storeTx.InsertLabel(ctx, l, e.ID); err != nil {
+				return errors.Join(err, tx.Rollback())
+			}
+		}
+	}
+
+	for _, m := range data.Labels {
+		if err := storeTx.UpsertLabelMeta(ctx, vaultdb.LabelMeta{Name: m.Name, Color: m.Color, Icon: m.Icon}); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	idx.db = vaultdb.New(conn)
+
+	return nil
+}
+
+// FilterSecrets returns secrets that match the given filters.
+func (idx *Index) FilterSecrets(ctx context.Context, wildcard string, name string, labels []string) (map[int]vaultdb.SecretWithLabels, error) {
+	filters := vaultdb.Filters{
+		Wildcard: wildcard,
+		Name:     name,
+		Labels:   labels,
+	}
+
+	return idx.db.FilterSecrets(ctx, filters)
+}
+
+// SecretsByIDs returns a map of secrets that match any of the provided IDs,
+// along with all labels associated with each.
+//
+// If the IDs slice is empty, the function returns [vaultdb.ErrNoIDsProvided].
+func (idx *Index) SecretsByIDs(ctx context.Context, ids ...int) (map[int]vaultdb.SecretWithLabels, error) {
+	return idx.db.SecretsByIDs(ctx, ids)
+}
+
+// LabelsMeta returns the display metadata of all labels, keyed by label name.
+func (idx *Index) LabelsMeta(ctx context.Context) (map[string]vaultdb.LabelMeta, error) {
+	return idx.db.LabelsMeta(ctx)
+}
+
+// Close releases the resources associated with the index.
+//
+// It is safe to call Close multiple times; only the first call has an effect.
+func (idx *Index) Close() (retErr error) {
+	if idx == nil {
+		return nil
+	}
+
+	idx.closeOnce.Do(func() {
+		retErr = executeCleanup(idx.cleanupFuncs)
+	})
+
+	return retErr
+}
+
+// sealIndex builds the metadata index of the in-memory vault
+// and encrypts it using a new random nonce.
+func (vlt *Vault) sealIndex(ctx context.Context) (nonce []byte, cipherindex []byte, _ error) {
+	secrets, err := vlt.db.FilterSecrets(ctx, vaultdb.Filters{})
+	if err != nil {
+		return nil, nil, errf("seal index: %w", err)
+	}
+
+	entries := make([]indexEntry, 0, len(secrets))
+	for id, s := range secrets {
+		entries = append(entries, indexEntry{ID: id, Name: s.Name, Labels: s.Labels})
+	}
+
+	slices.SortFunc(entries, func(a, b indexEntry) int { return cmp.Compare(a.ID, b.ID) })
+
+	meta, err := vlt.db.LabelsMeta(ctx)
+	if err != nil {
+		return nil, nil, errf("seal index: %w", err)
+	}
+
+	labels := make([]indexLabelMeta, 0, len(meta))
+	for _, m := range meta {
+		labels = append(labels, indexLabelMeta{Name: m.Name, Color: m.Color, Icon: m.Icon})
+	}
+
+	slices.SortFunc(labels, func(a, b indexLabelMeta) int { return cmp.Compare(a.Name, b.Name) })
+
+	serialized, err := json.Marshal(indexData{Secrets: entries, Labels: labels})
+	if err != nil {
+		return nil, nil, errf("seal index: %w", err)
+	}
+	defer securebytes.Wipe(serialized)
+
+	nonce, err = vaultcrypto.RandBytes(vaultcrypto.NonceSizeGCM)
+	if err != nil {
+		return nil, nil, errf("seal index: failed to generate random nonce: %w", err)
+	}
+
+	cipherindex, err = vlt.aesgcm.Seal(nonce, serialized)
+	if err != nil {
+		return nil, nil, errf("seal index: failed to seal data with AES-GCM: %w", err)
+	}
+
+	return nonce, cipherindex, nil
+}
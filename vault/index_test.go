@@ -0,0 +1,113 @@
+package vault_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+)
+
+func TestOpenIndex(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), ".vlt.temp")
+	password := []byte("password")
+
+	v, err := vault.New(t.Context(), vaultPath, password)
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	for _, s := range []struct {
+		name   string
+		labels []string
+	}{
+		{"github", []string{"dev", "work"}},
+		{"gitlab", []string{"dev"}},
+		{"bank", nil},
+	} {
+		if _, err := v.InsertNewSecret(t.Context(), s.name, []byte("secret"), s.labels); err != nil {
+			t.Fatalf("failed to insert new secret: %v", err)
+		}
+	}
+
+	if err := v.SetLabelMeta(t.Context(), vaultdb.LabelMeta{Name: "work", Color: "red", Icon: "W"}); err != nil {
+		t.Fatalf("failed to set label meta: %v", err)
+	}
+
+	if _, err := v.Seal(t.Context()); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	key, nonce, err := vault.Login(t.Context(), vaultPath, password)
+	if err != nil {
+		t.Fatalf("failed to login: %v", err)
+	}
+
+	idx, err := vault.OpenIndex(t.Context(), vaultPath, vault.WithSessionKey(key, bytes.Clone(nonce)))
+	if err != nil {
+		t.Fatalf("failed to open index: %v", err)
+	}
+
+	got, err := idx.FilterSecrets(t.Context(), "git*", "", nil)
+	if err != nil {
+		t.Fatalf("filter secrets: %v", err)
+	}
+
+	names := make([]string, 0, len(got))
+	for _, s := range got {
+		names = append(names, s.Name)
+
+		if len(s.Value) > 0 || len(s.Ciphertext) > 0 {
+			t.Errorf("index exposes secret data for %q", s.Name)
+		}
+	}
+
+	slices.Sort(names)
+
+	if want := []string{"github", "gitlab"}; !slices.Equal(names, want) {
+		t.Errorf("got names %v, want %v", names, want)
+	}
+
+	meta, err := idx.LabelsMeta(t.Context())
+	if err != nil {
+		t.Fatalf("labels meta: %v", err)
+	}
+
+	if want := (vaultdb.LabelMeta{Name: "work", Color: "red", Icon: "W"}); len(meta) != 1 || meta["work"] != want {
+		t.Errorf("got labels meta %v, want %v", meta, want)
+	}
+
+	if err := idx.Close(); err != nil {
+		t.Errorf("failed to close index: %v", err)
+	}
+
+	// an index that does not match the stored vault must not be used.
+	markIndexStale(t, vaultPath)
+
+	_, err = vault.OpenIndex(t.Context(), vaultPath, vault.WithSessionKey(key, bytes.Clone(nonce)))
+	if !errors.Is(err, vault.ErrIndexUnavailable) {
+		t.Errorf("got error %v, want %v", err, vault.ErrIndexUnavailable)
+	}
+}
+
+func markIndexStale(t *testing.T, path string) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open container: %v", err)
+	}
+	defer func() { _ = db.Close() }() //nolint:wsl_v5
+
+	if _, err := db.ExecContext(t.Context(), "UPDATE vault_container SET index_checksum = x'00' WHERE id = 0;"); err != nil {
+		t.Fatalf("update index checksum: %v", err)
+	}
+}
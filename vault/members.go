@@ -0,0 +1,177 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ladzaretti/vlt-cli/securebytes"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultcontainer"
+
+	"filippo.io/age"
+)
+
+var (
+	ErrMemberExists   = errors.New("member already exists")
+	ErrMemberNotFound = errors.New("member not found")
+)
+
+// Member describes a vault member, a user that unlocks
+// the vault with their own age identity instead of the password.
+//
+// Member names and recipients are stored unencrypted in the vault container.
+type Member struct {
+	Name      string
+	Recipient string // Recipient is the member's age X25519 public key, e.g., age1...
+	CreatedAt string
+}
+
+// AddMember wraps the vault key to the given age X25519 recipient
+// and stores it as a key slot of a new member with the given name.
+//
+// The member can then unlock the vault with the matching identity,
+// see [LoginWithIdentity].
+func (vlt *Vault) AddMember(ctx context.Context, name string, recipient string) error {
+	if len(name) == 0 {
+		return errf("add member: empty member name")
+	}
+
+	r, err := age.ParseX25519Recipient(recipient)
+	if err != nil {
+		return errf("add member: %w", err)
+	}
+
+	members, err := vlt.containerHandle.db.SelectMembers(ctx)
+	if err != nil {
+		return errf("add member: %w", err)
+	}
+
+	for _, m := range members {
+		if m.Name == name || m.Recipient == r.String() {
+			return fmt.Errorf("%w: %s (%s)", ErrMemberExists, m.Name, m.Recipient)
+		}
+	}
+
+	var wrapped bytes.Buffer
+
+	w, err := age.Encrypt(&wrapped, r)
+	if err != nil {
+		return errf("add member: wrap key: %w", err)
+	}
+
+	if _, err := w.Write(vlt.key.Bytes()); err != nil {
+		return errf("add member: wrap key: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return errf("add member: wrap key: %w", err)
+	}
+
+	m := vaultcontainer.Member{
+		Name:       name,
+		Recipient:  r.String(),
+		WrappedKey: wrapped.Bytes(),
+	}
+
+	if err := vlt.containerHandle.db.InsertMember(ctx, m); err != nil {
+		return errf("add member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember deletes the key slot of the member with the given name.
+//
+// The removed member may still hold a copy of the vault key;
+// revoking access requires changing the key, e.g., by rotating the password.
+func (vlt *Vault) RemoveMember(ctx context.Context, name string) error {
+	n, err := vlt.containerHandle.db.DeleteMember(ctx, name)
+	if err != nil {
+		return errf("remove member: %w", err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%w: %s", ErrMemberNotFound, name)
+	}
+
+	return nil
+}
+
+// Members returns the vault members ordered by name.
+func (vlt *Vault) Members(ctx context.Context) ([]Member, error) {
+	members, err := vlt.containerHandle.db.SelectMembers(ctx)
+	if err != nil {
+		return nil, errf("members: %w", err)
+	}
+
+	result := make([]Member, 0, len(members))
+	for _, m := range members {
+		result = append(result, Member{
+			Name:      m.Name,
+			Recipient: m.Recipient,
+			CreatedAt: m.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// LoginWithIdentity unwraps the vault key from the key slot of the member
+// matching one of the given age identities, for the vault at the given path.
+//
+// It returns [ErrAuthenticationFailed] if none of the identities belongs to a member.
+func LoginWithIdentity(ctx context.Context, path string, identities []age.Identity, opts ...Option) (key []byte, nonce []byte, _ error) {
+	config := &config{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	vaultContainerHandle, err := newVaultContainerHandle(ctx, path, config.containerSnapshot, config.maxHistorySnapshots)
+	if err != nil {
+		return nil, nil, errf("vault.login: failed to initialize vault container handle: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		_ = vaultContainerHandle.cleanup()
+	}()
+
+	cipherdata, err := vaultContainerHandle.db.SelectVault(ctx)
+	if err != nil {
+		return nil, nil, errf("vault.login: failed to select vault from container database: %w", err)
+	}
+
+	members, err := vaultContainerHandle.db.SelectMembers(ctx)
+	if err != nil {
+		return nil, nil, errf("vault.login: failed to select vault members: %w", err)
+	}
+
+	for _, m := range members {
+		key, err := unwrapKey(m.WrappedKey, identities)
+		if err == nil {
+			return key, cipherdata.Nonce, nil
+		}
+
+		var noMatch *age.NoIdentityMatchError
+		if !errors.As(err, &noMatch) {
+			return nil, nil, errf("vault.login: unwrap key of member %q: %w", m.Name, err)
+		}
+	}
+
+	return nil, nil, errf("vault.login: no member matches the given identity: %w", ErrAuthenticationFailed)
+}
+
+func unwrapKey(wrapped []byte, identities []age.Identity) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(wrapped), identities...)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := io.ReadAll(r)
+	if err != nil {
+		securebytes.Wipe(key)
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -2,9 +2,17 @@ package vault_test
 
 import (
 	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ladzaretti/vlt-cli/vault"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestSerialization(t *testing.T) {
@@ -76,3 +84,103 @@ func TestSerialization(t *testing.T) {
 		t.Fatalf("unexpected msg: %q", msg)
 	}
 }
+
+// randomSecrets returns n secrets with random names, values and labels, covering
+// encoding edge cases, e.g., unicode and control characters, NUL bytes and empty values.
+func randomSecrets(r *rand.Rand, n int) []vault.NewSecret {
+	alphabet := []rune("abcXYZ019 _-/.,;:'\"\\\t\n\r\x00#=%*?[]{}äöü€日本語🔑\u200b\ufeff")
+
+	randString := func(minLen, maxLen int) string {
+		s := make([]rune, minLen+r.IntN(maxLen-minLen+1))
+		for i := range s {
+			s[i] = alphabet[r.IntN(len(alphabet))]
+		}
+
+		return string(s)
+	}
+
+	secrets := make([]vault.NewSecret, n)
+
+	for i := range secrets {
+		value := make([]byte, r.IntN(64))
+		for j := range value {
+			value[j] = byte(r.IntN(4) * r.IntN(256) / 3) // biased to NUL bytes.
+		}
+
+		labels := make([]string, r.IntN(16))
+		for j := range labels {
+			labels[j] = fmt.Sprintf("%d-%s", j, randString(0, 12)) // unique labels.
+		}
+
+		secrets[i] = vault.NewSecret{
+			Name:   fmt.Sprintf("%d-%s", i, randString(0, 24)), // unique names.
+			Value:  value,
+			Labels: labels,
+		}
+	}
+
+	return secrets
+}
+
+func TestVault_SerializeRoundTrip(t *testing.T) {
+	seed := time.Now().UnixNano()
+	t.Logf("seed: %d", seed)
+
+	r := rand.New(rand.NewPCG(uint64(seed), 0)) //nolint:gosec
+
+	for i := range 3 {
+		t.Run(fmt.Sprintf("round %d", i), func(t *testing.T) {
+			dir := t.TempDir()
+
+			v, err := vault.New(t.Context(), filepath.Join(dir, "vault.db"), []byte("password"))
+			if err != nil {
+				t.Fatalf("failed to create vault: %v", err)
+			}
+			defer func() { _ = v.Close() }()
+
+			secrets := randomSecrets(r, 1+r.IntN(64))
+			if _, err := v.InsertNewSecrets(t.Context(), secrets); err != nil {
+				t.Fatalf("failed to insert secrets: %v", err)
+			}
+
+			data, err := v.Serialize(t.Context())
+			if err != nil {
+				t.Fatalf("failed to serialize vault: %v", err)
+			}
+
+			path := filepath.Join(dir, "deserialized.db")
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatalf("failed to write serialized vault: %v", err)
+			}
+
+			v2, err := vault.Open(t.Context(), path, vault.WithPassword([]byte("password")))
+			if err != nil {
+				t.Fatalf("failed to open deserialized vault: %v", err)
+			}
+			defer func() { _ = v2.Close() }()
+
+			got, err := v2.ExportSecrets(t.Context())
+			if err != nil {
+				t.Fatalf("failed to export deserialized secrets: %v", err)
+			}
+
+			want := make(map[string]vault.NewSecret, len(secrets))
+			for _, s := range secrets {
+				want[s.Name] = s
+			}
+
+			gotByName := make(map[string]vault.NewSecret, len(got))
+			for _, s := range got {
+				gotByName[s.Name] = vault.NewSecret{Name: s.Name, Value: s.Value, Labels: s.Labels}
+			}
+
+			opts := []cmp.Option{
+				cmpopts.SortSlices(func(a, b string) bool { return a < b }),
+				cmpopts.EquateEmpty(),
+			}
+			if diff := cmp.Diff(want, gotByName, opts...); diff != "" {
+				t.Errorf("secrets mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
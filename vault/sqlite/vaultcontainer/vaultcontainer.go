@@ -1,9 +1,12 @@
 package vaultcontainer
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha1" //nolint:gosec // in this context, SHA-1 is for change detection, not security.
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/ladzaretti/vlt-cli/vault/types"
 )
@@ -25,9 +28,10 @@ func New(db types.DBTX, historySnapshotLimit int) *VaultContainer {
 }
 
 // WithTx returns a new [VaultContainer] using the given transaction.
-func (*VaultContainer) WithTx(tx *sql.Tx) *VaultContainer {
+func (vc *VaultContainer) WithTx(tx *sql.Tx) *VaultContainer {
 	return &VaultContainer{
-		db: tx,
+		db:                  tx,
+		maxHistorySnapshots: vc.maxHistorySnapshots,
 	}
 }
 
@@ -94,6 +98,60 @@ func (vc *VaultContainer) UpdateVault(ctx context.Context, nonce, ciphervault []
 	return err
 }
 
+const updateIndex = `
+	UPDATE vault_container
+	SET
+		index_nonce = ?,
+		index_encrypted = ?,
+		index_checksum = ?
+	WHERE
+		id = 0;
+`
+
+// UpdateIndex stores the encrypted metadata index built from the given encrypted vault.
+func (vc *VaultContainer) UpdateIndex(ctx context.Context, nonce, cipherindex []byte, ciphervault []byte) error {
+	//nolint:gosec // in this context, SHA-1 is for change detection, not security.
+	checksum := sha1.Sum(ciphervault)
+	_, err := vc.db.ExecContext(ctx, updateIndex, nonce, cipherindex, checksum[:])
+
+	return err
+}
+
+const selectIndex = `
+	SELECT
+		index_nonce,
+		index_encrypted,
+		coalesce(index_checksum = checksum, 0)
+	FROM
+		vault_container
+	WHERE
+		id = 0;
+`
+
+// CipherIndex holds the encrypted metadata index.
+type CipherIndex struct {
+	Nonce []byte
+	Index []byte
+
+	// Current reports whether the index was built from
+	// the currently stored encrypted vault.
+	Current bool
+}
+
+// SelectIndex returns the encrypted metadata index.
+//
+// Both Nonce and Index are nil if no index was stored.
+func (vc *VaultContainer) SelectIndex(ctx context.Context) (*CipherIndex, error) {
+	row := vc.db.QueryRowContext(ctx, selectIndex)
+
+	var data CipherIndex
+	if err := row.Scan(&data.Nonce, &data.Index, &data.Current); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
 const selectVault = `
 	SELECT
 		auth_phc, kdf_phc, nonce, vault_encrypted
@@ -121,11 +179,575 @@ func (vc *VaultContainer) SelectVault(ctx context.Context) (*CipherData, error)
 	return &data, nil
 }
 
+// IntegrityCheck runs the SQLite integrity check on the vault container database
+// and returns the problems found, if any.
+func (vc *VaultContainer) IntegrityCheck(ctx context.Context) ([]string, error) {
+	return types.IntegrityCheck(ctx, vc.db)
+}
+
+const selectVaultChecksum = `
+	SELECT
+		vault_encrypted, checksum
+	FROM
+		vault_container
+	WHERE
+		id = 0;
+`
+
+// VerifyVaultChecksum reports whether the stored checksum
+// of the encrypted vault matches its content.
+func (vc *VaultContainer) VerifyVaultChecksum(ctx context.Context) (bool, error) {
+	var ciphervault, stored []byte
+	if err := vc.db.QueryRowContext(ctx, selectVaultChecksum).Scan(&ciphervault, &stored); err != nil {
+		return false, err
+	}
+
+	//nolint:gosec // in this context, SHA-1 is for change detection, not security.
+	checksum := sha1.Sum(ciphervault)
+
+	return bytes.Equal(checksum[:], stored), nil
+}
+
+const repairVaultChecksum = `
+	UPDATE vault_container
+	SET
+		checksum = ?
+	WHERE
+		id = 0;
+`
+
+// RepairVaultChecksum recomputes the checksum of the encrypted vault.
+func (vc *VaultContainer) RepairVaultChecksum(ctx context.Context) error {
+	var ciphervault, stored []byte
+	if err := vc.db.QueryRowContext(ctx, selectVaultChecksum).Scan(&ciphervault, &stored); err != nil {
+		return err
+	}
+
+	//nolint:gosec // in this context, SHA-1 is for change detection, not security.
+	checksum := sha1.Sum(ciphervault)
+	_, err := vc.db.ExecContext(ctx, repairVaultChecksum, checksum[:])
+
+	return err
+}
+
+const selectHistory = `
+	SELECT
+		id, created_at, checksum, snapshot
+	FROM
+		vault_history
+	ORDER BY
+		id;
+`
+
+// HistorySnapshot describes a single vault history snapshot.
+type HistorySnapshot struct {
+	ID        int
+	CreatedAt string
+	Valid     bool // Valid reports whether the stored checksum matches the snapshot.
+}
+
+// VerifyHistory returns all vault history snapshots along
+// with the result of their checksum verification.
+func (vc *VaultContainer) VerifyHistory(ctx context.Context) ([]HistorySnapshot, error) {
+	rows, err := vc.db.QueryContext(ctx, selectHistory)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	var snapshots []HistorySnapshot
+	for rows.Next() {
+		var (
+			s                  HistorySnapshot
+			checksum, snapshot []byte
+		)
+
+		if err := rows.Scan(&s.ID, &s.CreatedAt, &checksum, &snapshot); err != nil {
+			return nil, err
+		}
+
+		//nolint:gosec // in this context, SHA-1 is for change detection, not security.
+		sum := sha1.Sum(snapshot)
+		s.Valid = bytes.Equal(sum[:], checksum)
+
+		snapshots = append(snapshots, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+const deleteHistorySnapshot = `
+	DELETE FROM vault_history
+	WHERE
+		id = ?;
+`
+
+// DeleteHistorySnapshot deletes the vault history snapshot identified by id.
+func (vc *VaultContainer) DeleteHistorySnapshot(ctx context.Context, id int) error {
+	_, err := vc.db.ExecContext(ctx, deleteHistorySnapshot, id)
+	return err
+}
+
+// Encodings of vault history snapshots.
+const (
+	EncodingFull       = "full"       // EncodingFull is the encrypted vault, as recorded on update.
+	EncodingCheckpoint = "checkpoint" // EncodingCheckpoint is the compressed vault, encrypted.
+	EncodingDelta      = "delta"      // EncodingDelta is the compressed delta to the next newer snapshot, encrypted.
+)
+
+const selectHistorySnapshots = `
+	SELECT
+		id, length(snapshot), created_at, encoding
+	FROM
+		vault_history
+	ORDER BY
+		id;
+`
+
+// SnapshotInfo describes a vault history snapshot without its content.
+type SnapshotInfo struct {
+	ID        int
+	Size      int64 // Size is the size of the encrypted snapshot in bytes.
+	CreatedAt string
+	Encoding  string
+}
+
+// ListHistorySnapshots returns the vault history snapshots, oldest first.
+func (vc *VaultContainer) ListHistorySnapshots(ctx context.Context) ([]SnapshotInfo, error) {
+	rows, err := vc.db.QueryContext(ctx, selectHistorySnapshots)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	var snapshots []SnapshotInfo
+	for rows.Next() {
+		var s SnapshotInfo
+		if err := rows.Scan(&s.ID, &s.Size, &s.CreatedAt, &s.Encoding); err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// CountHistorySnapshots returns the number of vault history snapshots.
+func (vc *VaultContainer) CountHistorySnapshots(ctx context.Context) (int, error) {
+	var n int
+	if err := vc.db.QueryRowContext(ctx, "SELECT count(*) FROM vault_history;").Scan(&n); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+const selectHistorySnapshot = `
+	SELECT
+		id, created_at, encoding, nonce, snapshot
+	FROM
+		vault_history
+	WHERE
+		id = ?;
+`
+
+const selectLatestHistorySnapshot = `
+	SELECT
+		id, created_at, encoding, nonce, snapshot
+	FROM
+		vault_history
+	ORDER BY
+		id DESC
+	LIMIT
+		1;
+`
+
+// CipherSnapshot holds an encrypted vault history snapshot.
+type CipherSnapshot struct {
+	ID        int
+	CreatedAt string
+	Encoding  string
+
+	// Nonce is the nonce the snapshot was encrypted with,
+	// nil for snapshots taken before nonces were recorded.
+	Nonce    []byte
+	Snapshot []byte
+}
+
+// SelectHistorySnapshot returns the vault history snapshot identified by id,
+// or [sql.ErrNoRows] if there is none.
+func (vc *VaultContainer) SelectHistorySnapshot(ctx context.Context, id int) (*CipherSnapshot, error) {
+	return scanCipherSnapshot(vc.db.QueryRowContext(ctx, selectHistorySnapshot, id))
+}
+
+// SelectLatestHistorySnapshot returns the most recent vault history snapshot,
+// or [sql.ErrNoRows] if there is none.
+func (vc *VaultContainer) SelectLatestHistorySnapshot(ctx context.Context) (*CipherSnapshot, error) {
+	return scanCipherSnapshot(vc.db.QueryRowContext(ctx, selectLatestHistorySnapshot))
+}
+
+func scanCipherSnapshot(row *sql.Row) (*CipherSnapshot, error) {
+	var s CipherSnapshot
+	if err := row.Scan(&s.ID, &s.CreatedAt, &s.Encoding, &s.Nonce, &s.Snapshot); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+const updateHistorySnapshot = `
+	UPDATE vault_history
+	SET
+		encoding = ?,
+		nonce = ?,
+		snapshot = ?,
+		checksum = ?
+	WHERE
+		id = ?;
+`
+
+// UpdateHistorySnapshot replaces the content of the vault history
+// snapshot identified by id, e.g., to re-encode it.
+func (vc *VaultContainer) UpdateHistorySnapshot(ctx context.Context, id int, encoding string, nonce, snapshot []byte) error {
+	//nolint:gosec // in this context, SHA-1 is for change detection, not security.
+	checksum := sha1.Sum(snapshot)
+	_, err := vc.db.ExecContext(ctx, updateHistorySnapshot, encoding, nonce, snapshot, checksum[:], id)
+
+	return err
+}
+
+// PruneHistory deletes all but the n most recent vault history snapshots,
+// and returns the number of deleted snapshots.
+func (vc *VaultContainer) PruneHistory(ctx context.Context, n int) (int64, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("invalid number of snapshots to keep: %d", n)
+	}
+
+	res, err := vc.db.ExecContext(ctx, pruneHistory, n)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+const insertMember = `
+	INSERT INTO
+		vault_members (name, recipient, wrapped_key)
+	VALUES
+		(?, ?, ?);
+`
+
+// Member represents a vault member, holding the vault key
+// wrapped to the member's public key.
+type Member struct {
+	Name       string
+	Recipient  string
+	WrappedKey []byte
+	CreatedAt  string
+}
+
+// InsertMember inserts a new vault member.
+func (vc *VaultContainer) InsertMember(ctx context.Context, m Member) error {
+	_, err := vc.db.ExecContext(ctx, insertMember, m.Name, m.Recipient, m.WrappedKey)
+	return err
+}
+
+const deleteMember = `
+	DELETE FROM vault_members
+	WHERE
+		name = ?;
+`
+
+// DeleteMember deletes the vault member with the given name,
+// and returns the number of deleted rows.
+func (vc *VaultContainer) DeleteMember(ctx context.Context, name string) (int64, error) {
+	res, err := vc.db.ExecContext(ctx, deleteMember, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// DeleteAllMembers deletes all vault members.
+func (vc *VaultContainer) DeleteAllMembers(ctx context.Context) error {
+	_, err := vc.db.ExecContext(ctx, "DELETE FROM vault_members;")
+	return err
+}
+
+const selectMembers = `
+	SELECT
+		name, recipient, wrapped_key, created_at
+	FROM
+		vault_members
+	ORDER BY
+		name;
+`
+
+// SelectMembers returns all vault members ordered by name.
+func (vc *VaultContainer) SelectMembers(ctx context.Context) ([]Member, error) {
+	rows, err := vc.db.QueryContext(ctx, selectMembers)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	var members []Member
+	for rows.Next() {
+		var m Member
+		if err := rows.Scan(&m.Name, &m.Recipient, &m.WrappedKey, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		members = append(members, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+const vaultFormatExists = `
+	SELECT
+		count(*)
+	FROM
+		sqlite_master
+	WHERE
+		type = 'table'
+		AND name = 'vault_format';
+`
+
+const selectVaultFormat = `
+	SELECT
+		version, min_reader_version, min_reader_release
+	FROM
+		vault_format
+	WHERE
+		id = 0;
+`
+
+// VaultFormat holds the format versions of the vault.
+type VaultFormat struct {
+	Version          int
+	MinReaderVersion int
+	MinReaderRelease string
+}
+
+// SelectVaultFormat returns the format versions of the vault,
+// nil if not recorded yet, e.g., before migrations are applied.
+func (vc *VaultContainer) SelectVaultFormat(ctx context.Context) (*VaultFormat, error) {
+	var n int
+	if err := vc.db.QueryRowContext(ctx, vaultFormatExists).Scan(&n); err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, nil //nolint:nilnil // the format is not recorded yet
+	}
+
+	var f VaultFormat
+
+	err := vc.db.QueryRowContext(ctx, selectVaultFormat).Scan(&f.Version, &f.MinReaderVersion, &f.MinReaderRelease)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // the format is not recorded yet
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+const upgradeVaultFormat = `
+	INSERT INTO
+		vault_format (id, version, min_reader_version, min_reader_release)
+	VALUES
+		(0, ?, ?, ?) ON CONFLICT (id) DO
+	UPDATE
+	SET
+		version = excluded.version,
+		min_reader_version = excluded.min_reader_version,
+		min_reader_release = excluded.min_reader_release
+	WHERE
+		vault_format.version < excluded.version;
+`
+
+// UpgradeVaultFormat records the given format of the vault,
+// unless a newer format version is recorded already.
+func (vc *VaultContainer) UpgradeVaultFormat(ctx context.Context, f VaultFormat) error {
+	_, err := vc.db.ExecContext(ctx, upgradeVaultFormat, f.Version, f.MinReaderVersion, f.MinReaderRelease)
+	return err
+}
+
+const selectUnlockAttempts = `
+	SELECT
+		failed, last_failed_at
+	FROM
+		unlock_attempts
+	WHERE
+		id = 0;
+`
+
+// UnlockAttempts holds the failed unlock attempts since the last successful unlock.
+type UnlockAttempts struct {
+	Failed       int
+	LastFailedAt int64 // LastFailedAt is the time of the last failed attempt, in unix milliseconds.
+}
+
+// SelectUnlockAttempts returns the failed unlock attempts since the last successful unlock.
+func (vc *VaultContainer) SelectUnlockAttempts(ctx context.Context) (*UnlockAttempts, error) {
+	var a UnlockAttempts
+	if err := vc.db.QueryRowContext(ctx, selectUnlockAttempts).Scan(&a.Failed, &a.LastFailedAt); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+const recordFailedUnlock = `
+	UPDATE unlock_attempts
+	SET
+		failed = failed + 1,
+		last_failed_at = ?
+	WHERE
+		id = 0;
+`
+
+// RecordFailedUnlock records a failed unlock attempt made at the given unix milliseconds.
+func (vc *VaultContainer) RecordFailedUnlock(ctx context.Context, at int64) error {
+	_, err := vc.db.ExecContext(ctx, recordFailedUnlock, at)
+	return err
+}
+
+const resetUnlockAttempts = `
+	UPDATE unlock_attempts
+	SET
+		failed = 0
+	WHERE
+		id = 0
+		AND failed > 0;
+`
+
+// ResetUnlockAttempts clears the failed unlock attempts.
+func (vc *VaultContainer) ResetUnlockAttempts(ctx context.Context) error {
+	_, err := vc.db.ExecContext(ctx, resetUnlockAttempts)
+	return err
+}
+
+const selectIdentity = `
+	SELECT
+		uuid, title
+	FROM
+		vault_identity
+	WHERE
+		id = 0;
+`
+
+// Identity holds the identity of the vault, independent of the path of its file.
+type Identity struct {
+	UUID  string
+	Title string
+}
+
+// SelectIdentity returns the identity of the vault.
+func (vc *VaultContainer) SelectIdentity(ctx context.Context) (*Identity, error) {
+	var i Identity
+	if err := vc.db.QueryRowContext(ctx, selectIdentity).Scan(&i.UUID, &i.Title); err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+const upsertIdentity = `
+	INSERT INTO
+		vault_identity (id, uuid, title)
+	VALUES
+		(0, ?, ?) ON CONFLICT (id) DO
+	UPDATE
+	SET
+		uuid = excluded.uuid,
+		title = excluded.title;
+`
+
+// UpsertIdentity replaces the identity of the vault.
+func (vc *VaultContainer) UpsertIdentity(ctx context.Context, i Identity) error {
+	_, err := vc.db.ExecContext(ctx, upsertIdentity, i.UUID, i.Title)
+	return err
+}
+
+const updateTitle = `
+	UPDATE vault_identity
+	SET
+		title = ?
+	WHERE
+		id = 0;
+`
+
+// UpdateTitle sets the title of the vault.
+func (vc *VaultContainer) UpdateTitle(ctx context.Context, title string) error {
+	_, err := vc.db.ExecContext(ctx, updateTitle, title)
+	return err
+}
+
 func (vc *VaultContainer) Vacuum(ctx context.Context) error {
 	_, err := vc.db.ExecContext(ctx, "VACUUM;")
 	return err
 }
 
+// PageStats describes the page usage of the vault container database file.
+type PageStats struct {
+	PageSize  int64 // PageSize is the size of a database page in bytes.
+	PageCount int64 // PageCount is the total number of pages in the database file.
+	FreePages int64 // FreePages is the number of unused pages, reclaimed by [VaultContainer.Vacuum].
+}
+
+// Size returns the size of the database file in bytes.
+func (s PageStats) Size() int64 { return s.PageSize * s.PageCount }
+
+// Reclaimable returns the space in bytes held by unused pages.
+func (s PageStats) Reclaimable() int64 { return s.PageSize * s.FreePages }
+
+const selectPageStats = `
+	SELECT
+		page_size,
+		page_count,
+		freelist_count
+	FROM
+		pragma_page_size,
+		pragma_page_count,
+		pragma_freelist_count;
+`
+
+// PageStats returns the page usage of the vault container database.
+func (vc *VaultContainer) PageStats(ctx context.Context) (PageStats, error) {
+	var s PageStats
+
+	err := vc.db.QueryRowContext(ctx, selectPageStats).Scan(&s.PageSize, &s.PageCount, &s.FreePages)
+
+	return s, err
+}
+
+// VacuumInto writes a consistent, vacuumed copy of the
+// vault container database to a new file at path.
+func (vc *VaultContainer) VacuumInto(ctx context.Context, path string) error {
+	_, err := vc.db.ExecContext(ctx, "VACUUM INTO ?;", path)
+	return err
+}
+
 const pruneHistory = `
 	DELETE FROM vault_history
 	WHERE
@@ -135,7 +757,8 @@ const pruneHistory = `
 			FROM
 				vault_history
 			ORDER BY
-				created_at DESC
+				created_at DESC,
+				id DESC
 			LIMIT
 				?
 		);
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/ladzaretti/vlt-cli/vault/types"
 )
@@ -127,6 +129,156 @@ func (s *VaultDB) UpdateName(ctx context.Context, id int, name string) (n int64,
 	return n, nil
 }
 
+const updateURL = `
+	UPDATE secrets
+	SET
+		url = $1
+	WHERE
+		id = $2
+`
+
+// UpdateURL sets the url of the secret, an empty url removes it.
+func (s *VaultDB) UpdateURL(ctx context.Context, id int, url string) (int64, error) {
+	res, err := s.db.ExecContext(ctx, updateURL, url, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+const selectURLs = `
+	SELECT
+		id,
+		url
+	FROM
+		secrets
+	WHERE
+		url != ''
+`
+
+// URLs returns the urls of the secrets that have one, by secret id.
+func (s *VaultDB) URLs(ctx context.Context) (map[int]string, error) {
+	rows, err := s.db.QueryContext(ctx, selectURLs)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	urls := make(map[int]string)
+
+	for rows.Next() {
+		var (
+			id  int
+			url string
+		)
+
+		if err := rows.Scan(&id, &url); err != nil {
+			return nil, err
+		}
+
+		urls[id] = url
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+// createUniqueNamesIndex enforces case-insensitive unique secret names,
+// see [VaultDB.SetUniqueNames].
+const createUniqueNamesIndex = `
+	CREATE UNIQUE INDEX IF NOT EXISTS secrets_unique_name ON secrets (name COLLATE NOCASE)
+`
+
+const dropUniqueNamesIndex = `
+	DROP INDEX IF EXISTS secrets_unique_name
+`
+
+// SetUniqueNames creates or drops the unique index on secret names,
+// compared case-insensitively.
+//
+// Creating the index fails if the stored names are not unique,
+// see [VaultDB.DuplicateNames].
+func (s *VaultDB) SetUniqueNames(ctx context.Context, enabled bool) error {
+	query := dropUniqueNamesIndex
+	if enabled {
+		query = createUniqueNamesIndex
+	}
+
+	_, err := s.db.ExecContext(ctx, query)
+
+	return err
+}
+
+const selectDuplicateNames = `
+	SELECT
+		name
+	FROM
+		secrets
+	GROUP BY
+		name COLLATE NOCASE
+	HAVING
+		COUNT(*) > 1
+	ORDER BY
+		name COLLATE NOCASE
+`
+
+// DuplicateNames returns one name of each group of secrets
+// sharing a name, compared case-insensitively.
+func (s *VaultDB) DuplicateNames(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, selectDuplicateNames)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	var names []string
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+const selectNameConflict = `
+	SELECT
+		id,
+		name
+	FROM
+		secrets
+	WHERE
+		name = $1 COLLATE NOCASE
+		AND id != $2
+	ORDER BY
+		id
+	LIMIT
+		1
+`
+
+// NameConflict returns the id and name of a secret other than excludeID
+// named name, compared case-insensitively. It returns a zero id if none is.
+func (s *VaultDB) NameConflict(ctx context.Context, name string, excludeID int) (id int, existing string, err error) {
+	err = s.db.QueryRowContext(ctx, selectNameConflict, name, excludeID).Scan(&id, &existing)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", nil
+	}
+
+	return id, existing, err
+}
+
 //nolint:gosec
 const selectSecret = `
 	SELECT
@@ -168,6 +320,45 @@ func (s *VaultDB) InsertLabel(ctx context.Context, name string, secretID int) (i
 	return id, nil
 }
 
+// LabelInserter inserts labels using a single prepared statement,
+// see [VaultDB.PrepareInsertLabel].
+type LabelInserter struct {
+	stmt *sql.Stmt
+}
+
+// PrepareInsertLabel prepares the statement of [VaultDB.InsertLabel],
+// to insert the labels of many secrets.
+//
+// The caller must close the returned inserter.
+func (s *VaultDB) PrepareInsertLabel(ctx context.Context) (*LabelInserter, error) {
+	stmt, err := s.db.PrepareContext(ctx, insertLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelInserter{stmt: stmt}, nil
+}
+
+// InsertLabel inserts the label of the secret, see [VaultDB.InsertLabel].
+func (li *LabelInserter) InsertLabel(ctx context.Context, name string, secretID int) (int64, error) {
+	res, err := li.stmt.ExecContext(ctx, name, secretID)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Close closes the prepared statement.
+func (li *LabelInserter) Close() error {
+	return li.stmt.Close()
+}
+
 const deleteLabel = `
 	DELETE FROM labels
 	WHERE
@@ -251,47 +442,90 @@ type Filters struct {
 }
 
 // FilterSecrets returns secrets that match the given filters.
+//
+// If labels are filtered, only the matching labels of each secret are returned.
 func (s *VaultDB) FilterSecrets(ctx context.Context, m Filters) (map[int]SecretWithLabels, error) {
-	query := `
-		SELECT
-			s.id,
-			s.name,
-			l.name AS label
-		FROM
-			secrets s
-			LEFT JOIN labels l ON s.id = l.secret_id
-	`
+	newQuery := func() *filterQuery {
+		q := &filterQuery{}
 
-	var (
-		args         []any
-		whereClauses []string
-	)
+		if len(m.Name) > 0 {
+			q.name(m.Name)
+		}
+
+		if len(m.Labels) > 0 {
+			q.labels(m.Labels...)
+		}
 
-	if len(m.Wildcard) > 0 {
-		whereClauses = append(whereClauses, "(s.name GLOB ? OR l.name GLOB ?)")
-		args = append(args, m.Wildcard, m.Wildcard)
+		return q
 	}
 
-	if len(m.Name) > 0 {
-		whereClauses = append(whereClauses, "s.name GLOB ?")
-		args = append(args, m.Name)
+	if len(m.Wildcard) == 0 {
+		q := newQuery()
+		return s.secretsJoinLabels(ctx, q.String(), q.args...)
 	}
 
-	if len(m.Labels) > 0 {
-		clauses := make([]string, len(m.Labels))
-		for i := range clauses {
-			clauses[i] = "l.name GLOB ?"
-			args = append(args, m.Labels[i])
-		}
+	// matching the wildcard against names OR labels in a single query
+	// cannot use an index, so each is matched by a query of its own.
+	byName, byLabel := newQuery(), newQuery()
+	byName.name(m.Wildcard)
+	byLabel.labels(m.Wildcard)
 
-		whereClauses = append(whereClauses, "("+strings.Join(clauses, " OR ")+")")
+	query := byName.String() + " UNION " + byLabel.String()
+
+	return s.secretsJoinLabels(ctx, query, slices.Concat(byName.args, byLabel.args)...)
+}
+
+// filterQuery builds a query selecting secrets joined with their labels,
+// where all added conditions hold.
+type filterQuery struct {
+	whereClauses []string
+	args         []any
+
+	// labelsFiltered reports whether a label condition was added,
+	// in which case secrets without labels cannot match.
+	labelsFiltered bool
+}
+
+// name adds a condition on the secret name matching the pattern.
+func (q *filterQuery) name(pattern string) {
+	q.whereClauses = append(q.whereClauses, "s.name GLOB ?")
+	q.args = append(q.args, pattern)
+}
+
+// labels adds a condition on the label matching any of the patterns.
+func (q *filterQuery) labels(patterns ...string) {
+	clauses := make([]string, len(patterns))
+	for i := range clauses {
+		clauses[i] = "l.name GLOB ?"
+		q.args = append(q.args, patterns[i])
 	}
 
-	if len(whereClauses) > 0 {
-		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	q.whereClauses = append(q.whereClauses, "("+strings.Join(clauses, " OR ")+")")
+	q.labelsFiltered = true
+}
+
+func (q *filterQuery) String() string {
+	// an inner join lets the label conditions use the labels name index.
+	join := "LEFT JOIN"
+	if q.labelsFiltered {
+		join = "JOIN"
 	}
 
-	return s.secretsJoinLabels(ctx, query, args...)
+	query := `
+		SELECT
+			s.id,
+			s.name,
+			l.name AS label
+		FROM
+			secrets s
+			` + join + ` labels l ON s.id = l.secret_id
+	`
+
+	if len(q.whereClauses) > 0 {
+		query += " WHERE " + strings.Join(q.whereClauses, " AND ")
+	}
+
+	return query
 }
 
 // secretsJoinLabels executes a query to join secrets with their labels.
@@ -320,6 +554,7 @@ func (s *VaultDB) secretsJoinLabels(ctx context.Context, query string, args ...a
 }
 
 // ExportSecrets exports all secret-related data stored in the database.
+// The labels of a secret are in the order they were added.
 func (s *VaultDB) ExportSecrets(ctx context.Context) (map[int]SecretWithLabels, error) {
 	query := `	
 	SELECT
@@ -330,7 +565,10 @@ func (s *VaultDB) ExportSecrets(ctx context.Context) (map[int]SecretWithLabels,
 		l.name AS label
 	FROM
 		secrets s
-		LEFT JOIN labels l ON s.id = l.secret_id;
+		LEFT JOIN labels l ON s.id = l.secret_id
+	ORDER BY
+		s.id,
+		l.id;
 	`
 
 	rows, err := s.db.QueryContext(ctx, query)
@@ -387,6 +625,311 @@ func (s *VaultDB) DeleteSecretsByIDs(ctx context.Context, ids []int) (int64, err
 	return n, nil
 }
 
+// IntegrityCheck runs the SQLite integrity check on the vault database
+// and returns the problems found, if any.
+func (s *VaultDB) IntegrityCheck(ctx context.Context) ([]string, error) {
+	return types.IntegrityCheck(ctx, s.db)
+}
+
+// Label represents a single label row.
+type Label struct {
+	ID       int
+	Name     string
+	SecretID string
+}
+
+const selectOrphanLabels = `
+	SELECT
+		id, name, secret_id
+	FROM
+		labels
+	WHERE
+		rowid IN (
+			SELECT
+				rowid
+			FROM
+				pragma_foreign_key_check ('labels')
+		)
+`
+
+// OrphanLabels returns labels that reference a non-existing secret.
+func (s *VaultDB) OrphanLabels(ctx context.Context) ([]Label, error) {
+	rows, err := s.db.QueryContext(ctx, selectOrphanLabels)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	var labels []Label
+	for rows.Next() {
+		var l Label
+		if err := rows.Scan(&l.ID, &l.Name, &l.SecretID); err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, l)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+const deleteLabelByID = `
+	DELETE FROM labels
+	WHERE
+		id = ?
+`
+
+// DeleteLabelByID deletes the label identified by id.
+func (s *VaultDB) DeleteLabelByID(ctx context.Context, id int) (int64, error) {
+	res, err := s.db.ExecContext(ctx, deleteLabelByID, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// LabelMeta holds the optional display metadata of a label.
+type LabelMeta struct {
+	Name  string
+	Color string
+	Icon  string
+}
+
+const upsertLabelMeta = `
+	INSERT INTO
+		labels_meta (name, color, icon)
+	VALUES
+		($1, $2, $3) ON CONFLICT (name) DO UPDATE
+	SET
+		color = excluded.color,
+		icon = excluded.icon
+`
+
+// UpsertLabelMeta inserts or replaces the display metadata of a label.
+func (s *VaultDB) UpsertLabelMeta(ctx context.Context, m LabelMeta) error {
+	_, err := s.db.ExecContext(ctx, upsertLabelMeta, m.Name, m.Color, m.Icon)
+	return err
+}
+
+const deleteLabelMeta = `
+	DELETE FROM labels_meta
+	WHERE
+		name = $1
+`
+
+// DeleteLabelMeta deletes the display metadata of a label.
+func (s *VaultDB) DeleteLabelMeta(ctx context.Context, name string) (int64, error) {
+	res, err := s.db.ExecContext(ctx, deleteLabelMeta, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+const selectLabelsMeta = `
+	SELECT
+		name, color, icon
+	FROM
+		labels_meta
+`
+
+// LabelsMeta returns the display metadata of all labels, keyed by label name.
+func (s *VaultDB) LabelsMeta(ctx context.Context) (map[string]LabelMeta, error) {
+	rows, err := s.db.QueryContext(ctx, selectLabelsMeta)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	meta := make(map[string]LabelMeta)
+	for rows.Next() {
+		var m LabelMeta
+		if err := rows.Scan(&m.Name, &m.Color, &m.Icon); err != nil {
+			return nil, err
+		}
+
+		meta[m.Name] = m
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+const upsertSecretAccess = `
+	INSERT INTO
+		secret_usage (secret_id, access_count, last_accessed_at)
+	VALUES
+		($1, 1, $2) ON CONFLICT (secret_id) DO UPDATE
+	SET
+		access_count = access_count + 1,
+		last_accessed_at = excluded.last_accessed_at
+`
+
+// RecordAccess increments the access count of the secret
+// and sets its last access time to at.
+func (s *VaultDB) RecordAccess(ctx context.Context, id int, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, upsertSecretAccess, id, at.Unix())
+	return err
+}
+
+// SecretUsage holds the recorded usage of a secret and its last modification time.
+type SecretUsage struct {
+	ID           int
+	Name         string
+	AccessCount  int
+	LastAccessed time.Time // LastAccessed is zero if no access was recorded.
+	Modified     time.Time // Modified is the time the secret was last updated, or created if never updated.
+}
+
+const selectSecretsUsage = `
+	SELECT
+		s.id,
+		s.name,
+		COALESCE(u.access_count, 0),
+		u.last_accessed_at,
+		CAST(strftime('%s', COALESCE(s.updated_at, s.created_at)) AS INTEGER)
+	FROM
+		secrets s
+		LEFT JOIN secret_usage u ON s.id = u.secret_id
+	ORDER BY
+		s.id
+`
+
+// SecretsUsage returns the recorded usage of all secrets, ordered by id,
+// including secrets with no recorded access.
+func (s *VaultDB) SecretsUsage(ctx context.Context) ([]SecretUsage, error) {
+	rows, err := s.db.QueryContext(ctx, selectSecretsUsage)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	var usage []SecretUsage
+	for rows.Next() {
+		var (
+			u                      SecretUsage
+			lastAccessed, modified sql.NullInt64
+		)
+
+		if err := rows.Scan(&u.ID, &u.Name, &u.AccessCount, &lastAccessed, &modified); err != nil {
+			return nil, err
+		}
+
+		if lastAccessed.Valid {
+			u.LastAccessed = time.Unix(lastAccessed.Int64, 0)
+		}
+
+		if modified.Valid {
+			u.Modified = time.Unix(modified.Int64, 0)
+		}
+
+		usage = append(usage, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// Attribute represents an encrypted key-value attribute of a secret.
+type Attribute struct {
+	SecretID   int
+	Key        string
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+const upsertAttribute = `
+	INSERT INTO
+		secret_attributes (secret_id, key, nonce, ciphertext)
+	VALUES
+		($1, $2, $3, $4) ON CONFLICT (secret_id, key) DO UPDATE
+	SET
+		nonce = excluded.nonce,
+		ciphertext = excluded.ciphertext
+`
+
+// UpsertAttribute inserts or replaces the attribute a.Key of the secret a.SecretID.
+func (s *VaultDB) UpsertAttribute(ctx context.Context, a Attribute) error {
+	_, err := s.db.ExecContext(ctx, upsertAttribute, a.SecretID, a.Key, a.Nonce, a.Ciphertext)
+	return err
+}
+
+const deleteAttribute = `
+	DELETE FROM secret_attributes
+	WHERE
+		secret_id = $1
+		AND key = $2
+`
+
+// DeleteAttribute deletes the attribute key of the secret identified by secretID.
+func (s *VaultDB) DeleteAttribute(ctx context.Context, secretID int, key string) (int64, error) {
+	res, err := s.db.ExecContext(ctx, deleteAttribute, secretID, key)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// AttributesByIDs returns the attributes of the secrets with the given IDs,
+// ordered by secret id and key.
+//
+// If the IDs slice is empty, the function returns [ErrNoIDsProvided].
+func (s *VaultDB) AttributesByIDs(ctx context.Context, ids []int) ([]Attribute, error) {
+	if len(ids) == 0 {
+		return nil, ErrNoIDsProvided
+	}
+
+	placeholders := make([]string, len(ids))
+	for i := range ids {
+		placeholders[i] = "?"
+	}
+
+	query := `
+	SELECT
+		secret_id, key, nonce, ciphertext
+	FROM
+		secret_attributes
+	WHERE
+		secret_id IN (` + strings.Join(placeholders, ",") + `)
+	ORDER BY
+		secret_id, key`
+
+	rows, err := s.db.QueryContext(ctx, query, toAnySlice(ids)...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	var attrs []Attribute
+	for rows.Next() {
+		var a Attribute
+		if err := rows.Scan(&a.SecretID, &a.Key, &a.Nonce, &a.Ciphertext); err != nil {
+			return nil, err
+		}
+
+		attrs = append(attrs, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attrs, nil
+}
+
 func (s *VaultDB) Vacuum(ctx context.Context) error {
 	_, err := s.db.ExecContext(ctx, "VACUUM;")
 	return err
@@ -12,3 +12,31 @@ type DBTX interface {
 	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
+
+// IntegrityCheck runs the SQLite integrity check on db
+// and returns the problems found, if any.
+func IntegrityCheck(ctx context.Context, db DBTX) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check;")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }() //nolint:wsl_v5
+
+	var problems []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return nil, err
+		}
+
+		if msg != "ok" {
+			problems = append(problems, msg)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
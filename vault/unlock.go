@@ -0,0 +1,119 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+)
+
+const (
+	// freeUnlockAttempts is the number of failed unlock attempts
+	// allowed before unlocking is throttled.
+	freeUnlockAttempts = 3
+
+	// maxUnlockDelay caps the delay between throttled unlock attempts.
+	maxUnlockDelay = 5 * time.Minute
+)
+
+// UnlockAttempts describes the failed password unlock attempts
+// since the last successful unlock of a vault.
+type UnlockAttempts struct {
+	Failed       int
+	LastFailedAt time.Time // LastFailedAt is the time of the last failed attempt, zero if none.
+}
+
+// ThrottledError is returned when unlocking a vault with a password is
+// attempted too soon after repeated failures, the delay doubles with each
+// failed attempt beyond the first few.
+type ThrottledError struct {
+	Failed     int
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%s (%d), retry in %s",
+		vaulterrors.ErrUnlockThrottled, e.Failed, e.RetryAfter.Round(time.Second))
+}
+
+func (*ThrottledError) Unwrap() error { return vaulterrors.ErrUnlockThrottled }
+
+// FailedUnlocks returns the failed password unlock attempts
+// since the last successful unlock of the vault at the given path.
+func FailedUnlocks(ctx context.Context, path string, opts ...Option) (*UnlockAttempts, error) {
+	config := &config{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	vaultContainerHandle, err := newVaultContainerHandle(ctx, path, config.containerSnapshot, config.maxHistorySnapshots)
+	if err != nil {
+		return nil, errf("failed unlocks: failed to initialize vault container handle: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		_ = vaultContainerHandle.cleanup()
+	}()
+
+	a, err := vaultContainerHandle.db.SelectUnlockAttempts(ctx)
+	if err != nil {
+		return nil, errf("failed unlocks: %w", err)
+	}
+
+	attempts := &UnlockAttempts{Failed: a.Failed}
+	if a.LastFailedAt > 0 {
+		attempts.LastFailedAt = time.UnixMilli(a.LastFailedAt)
+	}
+
+	return attempts, nil
+}
+
+// unlockDelay returns the minimum delay between the last failed
+// unlock attempt and the next one, after the given number of failures.
+func unlockDelay(failed int) time.Duration {
+	if failed < freeUnlockAttempts {
+		return 0
+	}
+
+	shift := failed - freeUnlockAttempts
+	if shift >= 16 {
+		return maxUnlockDelay
+	}
+
+	return min(time.Second<<shift, maxUnlockDelay)
+}
+
+// verifyPassword verifies the password against the stored auth PHC,
+// unless throttled by previous failed attempts, and records the outcome.
+func (h *vaultContainerHandle) verifyPassword(ctx context.Context, password []byte, authPHC string) error {
+	a, err := h.db.SelectUnlockAttempts(ctx)
+	if err != nil {
+		return errf("select unlock attempts: %w", err)
+	}
+
+	now := time.Now()
+
+	if delay := unlockDelay(a.Failed); delay > 0 {
+		// a clock set back never delays the next attempt beyond the full delay.
+		wait := min(time.UnixMilli(a.LastFailedAt).Add(delay).Sub(now), delay)
+		if wait > 0 {
+			return &ThrottledError{Failed: a.Failed, RetryAfter: wait}
+		}
+	}
+
+	err = verifyPassword(password, authPHC)
+	if errors.Is(err, ErrAuthenticationFailed) {
+		return errors.Join(err, h.db.RecordFailedUnlock(ctx, now.UnixMilli()))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := h.db.ResetUnlockAttempts(ctx); err != nil {
+		return errf("reset unlock attempts: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+var ErrInvalidURL = errors.New("invalid url")
+
+// NormalizeURL returns rawURL in the form stored as the url of a secret,
+// with a lowercase scheme and host. URLs without a scheme default to https,
+// e.g., "github.com/login" is stored as "https://github.com/login".
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := parseURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// RegistrableDomain returns the domain of rawURL that can be registered,
+// i.e., one label below its public suffix, e.g., "github.com" for
+// "https://app.github.com/login", or "example.co.uk" for "www.example.co.uk".
+//
+// Hosts without a registrable domain, e.g., IP addresses or "localhost",
+// are returned as is.
+func RegistrableDomain(rawURL string) (string, error) {
+	u, err := parseURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	return registrableDomain(u.Hostname()), nil
+}
+
+func parseURL(rawURL string) (*url.URL, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+
+	if len(u.Hostname()) == 0 {
+		return nil, fmt.Errorf("%w: %q has no host", ErrInvalidURL, rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+
+	return u, nil
+}
+
+func registrableDomain(host string) string {
+	host = strings.TrimSuffix(host, ".")
+
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+
+	return domain
+}
+
+// SetSecretURL sets the url of the secret with the given id, normalized
+// using [NormalizeURL]. An empty url removes it.
+func (vlt *Vault) SetSecretURL(ctx context.Context, id int, rawURL string) error {
+	normalized := ""
+
+	if len(rawURL) > 0 {
+		u, err := NormalizeURL(rawURL)
+		if err != nil {
+			return errf("set secret url: %w", err)
+		}
+
+		normalized = u
+	}
+
+	if _, err := vlt.db.UpdateURL(ctx, id, normalized); err != nil {
+		return errf("set secret url: %w", err)
+	}
+
+	return nil
+}
+
+// SecretURLs returns the urls of the secrets that have one, by secret id.
+func (vlt *Vault) SecretURLs(ctx context.Context) (map[int]string, error) {
+	return vlt.db.URLs(ctx)
+}
+
+// SecretsByURL returns the secrets with a url of the same registrable domain
+// as rawURL, see [RegistrableDomain]. For example, "https://app.github.com/login"
+// matches a secret stored with the url "https://github.com".
+func (vlt *Vault) SecretsByURL(ctx context.Context, rawURL string) (map[int]vaultdb.SecretWithLabels, error) {
+	domain, err := RegistrableDomain(rawURL)
+	if err != nil {
+		return nil, errf("secrets by url: %w", err)
+	}
+
+	urls, err := vlt.db.URLs(ctx)
+	if err != nil {
+		return nil, errf("secrets by url: %w", err)
+	}
+
+	var ids []int
+
+	for id, u := range urls {
+		if d, err := RegistrableDomain(u); err == nil && d == domain {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return map[int]vaultdb.SecretWithLabels{}, nil
+	}
+
+	return vlt.SecretsByIDs(ctx, ids...)
+}
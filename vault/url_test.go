@@ -0,0 +1,105 @@
+package vault_test
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/vault"
+)
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "https://app.github.com/login", want: "github.com"},
+		{url: "github.com", want: "github.com"},
+		{url: "HTTPS://GitHub.com:443/", want: "github.com"},
+		{url: "https://www.example.co.uk", want: "example.co.uk"},
+		{url: "http://192.168.1.1:8080/admin", want: "192.168.1.1"},
+		{url: "http://localhost:3000", want: "localhost"},
+		{url: "https://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := vault.RegistrableDomain(tt.url)
+		if tt.wantErr {
+			if !errors.Is(err, vault.ErrInvalidURL) {
+				t.Errorf("%q: want %v, got %v", tt.url, vault.ErrInvalidURL, err)
+			}
+
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("%q: want %q, got %q (%v)", tt.url, tt.want, got, err)
+		}
+	}
+}
+
+func TestVault_SecretsByURL(t *testing.T) {
+	v, err := vault.New(t.Context(), filepath.Join(t.TempDir(), ".vlt.temp"), []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	urls := map[string]string{
+		"github":  "github.com",
+		"gist":    "https://gist.github.com",
+		"gitlab":  "https://gitlab.com/users/sign_in",
+		"no-url":  "",
+		"example": "https://example.co.uk",
+	}
+
+	ids := make(map[string]int, len(urls))
+
+	for name, u := range urls {
+		id, err := v.InsertNewSecret(t.Context(), name, []byte("secret"), nil)
+		if err != nil {
+			t.Fatalf("failed to insert new secret: %v", err)
+		}
+
+		if err := v.SetSecretURL(t.Context(), id, u); err != nil {
+			t.Fatalf("failed to set url: %v", err)
+		}
+
+		ids[name] = id
+	}
+
+	stored, err := v.SecretURLs(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get urls: %v", err)
+	}
+
+	if got, want := stored[ids["github"]], "https://github.com"; got != want {
+		t.Errorf("want normalized url %q, got %q", want, got)
+	}
+
+	if _, ok := stored[ids["no-url"]]; ok {
+		t.Errorf("want no url stored for an empty url")
+	}
+
+	matches, err := v.SecretsByURL(t.Context(), "https://app.github.com/login")
+	if err != nil {
+		t.Fatalf("secrets by url: %v", err)
+	}
+
+	var names []string
+	for _, s := range matches {
+		names = append(names, s.Name)
+	}
+
+	slices.Sort(names)
+
+	if want := []string{"gist", "github"}; !slices.Equal(names, want) {
+		t.Errorf("want matches %q, got %q", want, names)
+	}
+
+	if err := v.SetSecretURL(t.Context(), ids["gitlab"], "https://"); !errors.Is(err, vault.ErrInvalidURL) {
+		t.Errorf("want %v, got %v", vault.ErrInvalidURL, err)
+	}
+}
@@ -7,11 +7,18 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/ladzaretti/vlt-cli/securebytes"
 	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultcontainer"
 	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
 	"github.com/ladzaretti/vlt-cli/vaultcrypto"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
 
 	"github.com/ladzaretti/migrate"
 
@@ -25,6 +32,24 @@ PRAGMA synchronous = EXTRA;
 PRAGMA foreign_keys = ON;
 `
 
+// containerPragmas are applied to every connection opened to the on-disk
+// vault container database.
+//
+// A rollback journal combined with synchronous=EXTRA makes each write
+// transaction atomic and durable: the original pages are journaled and
+// fsynced before the database file is modified, and the journal removal
+// is fsynced as well. An interrupted write is rolled back on the next open.
+//
+// Writers wait for the lock held by another vlt process, e.g., one migrating
+// the same container, up to the busy timeout instead of failing immediately.
+var containerPragmas = []string{
+	"busy_timeout(5000)",
+	"journal_mode(DELETE)",
+	"synchronous(EXTRA)",
+	"foreign_keys(ON)",
+	"temp_store(MEMORY)",
+}
+
 var ErrAuthenticationFailed = errors.New("authentication failed")
 
 var (
@@ -58,13 +83,17 @@ type cleanupFunc func() error
 type Vault struct {
 	Path            string                // Path to the underlying SQLite file.
 	aesgcm          *vaultcrypto.AESGCM   // aesgcm is used for cryptographic ops on the vault data.
+	key             *securebytes.Buffer   // key holds the vault key in locked memory, used to wrap it for vault members, destroyed in [Vault.Close].
 	decryptionNonce []byte                // decryptionNonce is the cryptographic nonce used to decrypt the serialized vault database.
 	conn            *sql.Conn             // conn is the connection to the vault database, it is used for serializing and deserializing.
 	db              *vaultdb.VaultDB      // db provides an interface to the in-memory database holding the actual user data.
-	buf             []byte                // buf holds the backing in-memory SQLite database. retained to prevent GC while the DB is active, released in [Vault.Close].
 	containerHandle *vaultContainerHandle // vaultContainerHandle connects to the vault container database.
 	cleanupFuncs    []cleanupFunc         // cleanupFuncs contains deferred cleanup functions.
 	closeOnce       sync.Once             // closeOnce protects [Vault.Close].
+	migrationPlan   *MigrationPlan        // migrationPlan records the pending vault migrations on open, see [PlanMigrations].
+	autoVacuum      int64                 // autoVacuum is the reclaimable container space in bytes that triggers a vacuum on seal, see [WithAutoVacuumThreshold].
+	uniqueNames     bool                  // uniqueNames rejects secret names already in use, see [WithUniqueNames].
+	secretObserver  func([]byte)          // secretObserver is called with the secret values passing through the vault, see [WithSecretObserver].
 }
 
 type session struct {
@@ -82,8 +111,24 @@ type config struct {
 	// A snapshot is taken each time the vault is modified.
 	maxHistorySnapshots int
 
+	// autoVacuumThreshold is the reclaimable space in bytes of the vault container
+	// database above which it is vacuumed once sealed. Zero disables auto-vacuum.
+	autoVacuumThreshold int64
+
+	// uniqueNames enforces unique secret names, compared case-insensitively.
+	uniqueNames bool
+
+	// secretObserver is called with each secret value encrypted or decrypted, if set.
+	secretObserver func([]byte)
+
 	// containerSnapshot is the serialized vault container database to restore from, if set.
 	containerSnapshot []byte
+
+	// migrationPlan records the pending vault migrations before these are applied, if set.
+	migrationPlan *MigrationPlan
+
+	// identity is the identity of a vault created by [New], see [WithIdentity].
+	identity Identity
 }
 
 type Option func(*config)
@@ -123,11 +168,44 @@ func WithMaxHistorySnapshots(n int) Option {
 	}
 }
 
-func newVault(path string, nonce []byte, aesgcm *vaultcrypto.AESGCM, vch *vaultContainerHandle) *Vault {
+// WithAutoVacuumThreshold sets the reclaimable space in bytes, e.g., left by
+// pruned history snapshots, above which the vault container database is
+// vacuumed after [Vault.Seal]. Zero, the default, disables auto-vacuum.
+func WithAutoVacuumThreshold(n int64) Option {
+	return func(c *config) {
+		c.autoVacuumThreshold = n
+	}
+}
+
+// WithUniqueNames enforces unique secret names, compared case-insensitively,
+// using a unique index of the vault database, see [Vault.DuplicateNames].
+//
+// Inserting or renaming a secret to a name in use fails with
+// [vaulterrors.ErrNameConflict]. Disabling it drops the index.
+func WithUniqueNames(enabled bool) Option {
+	return func(c *config) {
+		c.uniqueNames = enabled
+	}
+}
+
+// WithSecretObserver sets a function called with each secret value the vault
+// encrypts or decrypts, e.g., to redact the values from logs.
+//
+// The value must not be retained, copied or modified by f, e.g., f may keep a
+// keyed digest of it. Values passed by [Vault.WithSecret] are in locked memory,
+// and are wiped once f and the callback return.
+func WithSecretObserver(f func(value []byte)) Option {
+	return func(c *config) {
+		c.secretObserver = f
+	}
+}
+
+func newVault(path string, nonce []byte, aesgcm *vaultcrypto.AESGCM, key *securebytes.Buffer, vch *vaultContainerHandle) *Vault {
 	return &Vault{
 		Path:            path,
 		decryptionNonce: nonce,
 		aesgcm:          aesgcm,
+		key:             key,
 		containerHandle: vch,
 	}
 }
@@ -170,12 +248,15 @@ func New(ctx context.Context, path string, password []byte, opts ...Option) (vlt
 		return nil, fmt.Errorf("vault.new: failed to decode KDF PHC: %w", err)
 	}
 
-	aes, err := deriveAESGCM(phc, password)
+	aes, key, err := deriveAESGCM(phc, password)
 	if err != nil {
 		return nil, fmt.Errorf("vault.new: failed to derive AES-GCM key: %w", err)
 	}
 
-	vlt = newVault(path, cipherdata.Nonce, aes, vaultContainerHandle)
+	vlt = newVault(path, cipherdata.Nonce, aes, key, vaultContainerHandle)
+	vlt.autoVacuum = config.autoVacuumThreshold
+	vlt.uniqueNames = config.uniqueNames
+	vlt.secretObserver = config.secretObserver
 
 	if err := vlt.open(ctx, nil); err != nil {
 		return vlt, fmt.Errorf("vault.new: failed to open vault: %w", err)
@@ -185,6 +266,7 @@ func New(ctx context.Context, path string, password []byte, opts ...Option) (vlt
 	if err != nil {
 		return vlt, fmt.Errorf("vault.new: failed to serialize vault connection: %w", err)
 	}
+	defer securebytes.Wipe(serialized)
 
 	ciphervault, err := aes.Seal(cipherdata.Nonce, serialized)
 	if err != nil {
@@ -195,6 +277,29 @@ func New(ctx context.Context, path string, password []byte, opts ...Option) (vlt
 		return vlt, fmt.Errorf("vault.new: failed to insert new vault into vault container database: %w", err)
 	}
 
+	// failed attempts to unlock an overwritten vault do not apply to the new password.
+	if err := vaultContainerHandle.db.ResetUnlockAttempts(ctx); err != nil {
+		return vlt, fmt.Errorf("vault.new: failed to reset unlock attempts: %w", err)
+	}
+
+	// members of an overwritten vault hold a key that no longer applies.
+	if err := vaultContainerHandle.db.DeleteAllMembers(ctx); err != nil {
+		return vlt, fmt.Errorf("vault.new: failed to delete vault members: %w", err)
+	}
+
+	if err := vaultContainerHandle.storeIdentity(ctx, config.identity); err != nil {
+		return vlt, fmt.Errorf("vault.new: failed to store vault identity: %w", err)
+	}
+
+	indexNonce, cipherindex, err := vlt.sealIndex(ctx)
+	if err != nil {
+		return vlt, fmt.Errorf("vault.new: %w", err)
+	}
+
+	if err := vaultContainerHandle.db.UpdateIndex(ctx, indexNonce, cipherindex, ciphervault); err != nil {
+		return vlt, fmt.Errorf("vault.new: failed to store metadata index: %w", err)
+	}
+
 	return vlt, nil
 }
 
@@ -219,7 +324,7 @@ func Login(ctx context.Context, path string, password []byte, opts ...Option) (k
 		return nil, nil, fmt.Errorf("vault.login: failed to select vault from container database: %w", err)
 	}
 
-	if err := verifyPassword(password, cipherdata.AuthPHC); err != nil {
+	if err := vaultContainerHandle.verifyPassword(ctx, password, cipherdata.AuthPHC); err != nil {
 		return nil, nil, errf("vault.login: password verification failed: %w", err)
 	}
 
@@ -263,30 +368,40 @@ func Open(ctx context.Context, path string, opts ...Option) (vlt *Vault, retErr
 
 	var (
 		aes   *vaultcrypto.AESGCM
+		key   *securebytes.Buffer
 		nonce []byte
 	)
 
 	// choose key derivation method: password-based or session-based
 	switch {
 	case len(config.password) > 0:
-		a, err := deriveAESFromPassword(cipherdata, config.password)
+		if err := vaultContainerHandle.verifyPassword(ctx, config.password, cipherdata.AuthPHC); err != nil {
+			return nil, errf("vault.open: password verification failed: %w", err)
+		}
+
+		a, k, err := deriveAESFromPassword(cipherdata, config.password)
 		if err != nil {
 			return nil, errf("vault.open: failed to derive AES key from password: %w", err)
 		}
 
-		aes, nonce = a, cipherdata.Nonce
+		aes, key, nonce = a, k, cipherdata.Nonce
 	case config.key != nil && config.nonce != nil:
-		a, err := vaultcrypto.NewAESGCM(config.key)
+		a, k, err := newAESGCM(slices.Clone(config.key))
 		if err != nil {
 			return nil, errf("vault.open: failed to initialize AES-GCM cipher: %w", err)
 		}
 
-		aes, nonce = a, config.nonce
+		aes, key, nonce = a, k, config.nonce
 	default:
 		return nil, errf("vault.open: no password or session key provided")
 	}
 
-	vlt = newVault(path, nonce, aes, vaultContainerHandle)
+	vlt = newVault(path, nonce, aes, key, vaultContainerHandle)
+	vlt.migrationPlan = config.migrationPlan
+	vlt.autoVacuum = config.autoVacuumThreshold
+	vlt.uniqueNames = config.uniqueNames
+	vlt.secretObserver = config.secretObserver
+
 	defer func() {
 		if retErr != nil {
 			_ = vlt.cleanup()
@@ -301,30 +416,27 @@ func Open(ctx context.Context, path string, opts ...Option) (vlt *Vault, retErr
 	return vlt, nil
 }
 
-func deriveAESFromPassword(cipherdata *vaultcontainer.CipherData, password []byte) (*vaultcrypto.AESGCM, error) {
-	if err := verifyPassword(password, cipherdata.AuthPHC); err != nil {
-		return nil, errf("derive AES from password: password verification failed: %w", err)
-	}
-
+// deriveAESFromPassword derives the vault AES-GCM key from the verified password.
+func deriveAESFromPassword(cipherdata *vaultcontainer.CipherData, password []byte) (*vaultcrypto.AESGCM, *securebytes.Buffer, error) {
 	phc, err := vaultcrypto.DecodeAragon2idPHC(cipherdata.KDFPHC)
 	if err != nil {
-		return nil, errf("derive AES from password: failed to decode KDF PHC: %w", err)
+		return nil, nil, errf("derive AES from password: failed to decode KDF PHC: %w", err)
 	}
 
-	aes, err := deriveAESGCM(phc, password)
+	aes, key, err := deriveAESGCM(phc, password)
 	if err != nil {
-		return nil, errf("derive AES from password: failed to derive AES-GCM key: %w", err)
+		return nil, nil, errf("derive AES from password: failed to derive AES-GCM key: %w", err)
 	}
 
-	return aes, nil
+	return aes, key, nil
 }
 
 // Close releases resources associated with the in-memory SQLite database.
 //
 // It is safe to call Close multiple times; only the first call has an effect.
 //
-// After calling Close, the in-memory database buffer [Vault.buf] is eligible for gc
-// and should not be used again unless reinitialized.
+// After calling Close, the in-memory database is freed by sqlite,
+// its memory is not wiped, and the vault must not be used again.
 func (vlt *Vault) Close() (retErr error) {
 	if vlt == nil {
 		return nil
@@ -346,6 +458,7 @@ func (vlt *Vault) Seal(ctx context.Context) (nonce []byte, _ error) {
 	if err != nil {
 		return nil, errf("seal: failed to serialize vault connection: %w", err)
 	}
+	defer securebytes.Wipe(serialized)
 
 	nonce, err = vaultcrypto.RandBytes(vaultcrypto.NonceSizeGCM)
 	if err != nil {
@@ -357,13 +470,31 @@ func (vlt *Vault) Seal(ctx context.Context) (nonce []byte, _ error) {
 		return nil, errf("seal: failed to seal data with AES-GCM: %w", err)
 	}
 
-	if err := vlt.containerHandle.db.UpdateVault(ctx, nonce, ciphervault); err != nil {
+	indexNonce, cipherindex, err := vlt.sealIndex(ctx)
+	if err != nil {
+		return nil, errf("seal: %w", err)
+	}
+
+	encodeSnapshot := func(container *vaultcontainer.VaultContainer, prev *vaultcontainer.CipherData) error {
+		return vlt.encodeLatestSnapshot(ctx, container, prev, serialized)
+	}
+
+	if err := vlt.containerHandle.updateVault(ctx, nonce, ciphervault, indexNonce, cipherindex, encodeSnapshot); err != nil {
 		return nil, errf("seal: failed to update vault in the vault container database: %w", err)
 	}
 
+	// best effort, the vault is sealed regardless.
+	_ = vlt.containerHandle.autoVacuum(ctx, vlt.autoVacuum)
+
 	return nonce, nil
 }
 
+// Key returns a copy of the vault key, to be wiped by the caller, e.g., to start
+// a session of a vault created by [New] without deriving the key again.
+func (vlt *Vault) Key() []byte {
+	return slices.Clone(vlt.key.Bytes())
+}
+
 // Serialize returns the serialized form of the vault container, including the encrypted vault.
 //
 // It first seals the in-memory Vault to ensure the latest state is captured,
@@ -384,25 +515,21 @@ func (vlt *Vault) cleanup() error {
 		return nil
 	}
 
-	zeroBytes(vlt.buf)
-	zeroBytes(vlt.decryptionNonce)
-
-	vlt.buf = nil // release backing buffer to allow garbage collection.
+	securebytes.Wipe(vlt.decryptionNonce)
 	vlt.decryptionNonce = nil
 
-	if err := executeCleanup(vlt.cleanupFuncs); err != nil {
+	err := executeCleanup(vlt.cleanupFuncs)
+
+	err = errors.Join(err, vlt.key.Destroy())
+	vlt.key = nil
+
+	if err != nil {
 		return errf("cleanup: cleanup failed: %w", err)
 	}
 
 	return nil
 }
 
-func zeroBytes(b []byte) {
-	for i := range b {
-		b[i] = 0
-	}
-}
-
 // verifyPassword checks whether the given password matches the Argon2id PHC hash.
 func verifyPassword(password []byte, phc string) error {
 	authPHC, err := vaultcrypto.DecodeAragon2idPHC(phc)
@@ -412,6 +539,7 @@ func verifyPassword(password []byte, phc string) error {
 
 	kdf := vaultcrypto.NewArgon2idKDF(vaultcrypto.WithPHC(authPHC))
 	derived := kdf.Derive(password)
+	defer securebytes.Wipe(derived)
 
 	if subtle.ConstantTimeCompare(authPHC.Hash, derived) != 1 {
 		return ErrAuthenticationFailed
@@ -451,6 +579,7 @@ func executeCleanup(fs []cleanupFunc) error {
 // vaultContainerHandle manages the database connection and access
 // to the vault container database schema used for storing the encrypted vault.
 type vaultContainerHandle struct {
+	sqlDB        *sql.DB
 	conn         *sql.Conn
 	db           *vaultcontainer.VaultContainer
 	cleanupFuncs []cleanupFunc
@@ -464,6 +593,87 @@ func (h *vaultContainerHandle) cleanup() error {
 	return executeCleanup(h.cleanupFuncs)
 }
 
+// updateVault persists the encrypted vault and its metadata index, and prunes
+// the vault history within a single transaction, so an interrupted seal leaves
+// the previously sealed vault intact.
+//
+// encodeSnapshot is called within the transaction once the vault is updated,
+// with the previously stored vault, recorded as the latest history snapshot.
+func (h *vaultContainerHandle) updateVault(ctx context.Context, nonce, ciphervault, indexNonce, cipherindex []byte,
+	encodeSnapshot func(*vaultcontainer.VaultContainer, *vaultcontainer.CipherData) error,
+) (retErr error) {
+	tx, err := h.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return errf("update vault: begin transaction: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		if retErr != nil {
+			retErr = errors.Join(retErr, tx.Rollback())
+		}
+	}()
+
+	containerTx := h.db.WithTx(tx)
+
+	prev, err := containerTx.SelectVault(ctx)
+	if err != nil {
+		return errf("update vault: select previous vault: %w", err)
+	}
+
+	if err := containerTx.UpdateVault(ctx, nonce, ciphervault); err != nil {
+		return errf("update vault: %w", err)
+	}
+
+	if err := encodeSnapshot(containerTx, prev); err != nil {
+		return errf("update vault: %w", err)
+	}
+
+	if err := containerTx.UpdateIndex(ctx, indexNonce, cipherindex, ciphervault); err != nil {
+		return errf("update vault: index: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errf("update vault: commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// autoVacuum vacuums the vault container database if the space held by its
+// unused pages exceeds threshold bytes. A non-positive threshold disables it.
+func (h *vaultContainerHandle) autoVacuum(ctx context.Context, threshold int64) error {
+	if threshold <= 0 {
+		return nil
+	}
+
+	stats, err := h.db.PageStats(ctx)
+	if err != nil {
+		return errf("auto vacuum: %w", err)
+	}
+
+	if stats.Reclaimable() <= threshold {
+		return nil
+	}
+
+	if err := h.db.Vacuum(ctx); err != nil {
+		return errf("auto vacuum: %w", err)
+	}
+
+	return nil
+}
+
+// containerDSN returns the data source name of the vault container
+// database at path, with [containerPragmas] applied to each connection.
+func containerDSN(path string) string {
+	u := url.URL{
+		Scheme:   "file",
+		Path:     filepath.ToSlash(path),
+		OmitHost: true,
+		RawQuery: url.Values{"_pragma": containerPragmas}.Encode(),
+	}
+
+	return u.String()
+}
+
 func newVaultContainerHandle(ctx context.Context, path string, containerSnapshot []byte, maxHistorySnapshots int) (_ *vaultContainerHandle, retErr error) {
 	handle := &vaultContainerHandle{}
 	defer func() {
@@ -492,7 +702,7 @@ func newVaultContainerHandle(ctx context.Context, path string, containerSnapshot
 		return nil
 	})
 
-	db, err := sql.Open("sqlite", path)
+	db, err := sql.Open("sqlite", containerDSN(path))
 	if err != nil {
 		return nil, errf("new vault container handle: failed to open database: %w", err)
 	}
@@ -502,25 +712,29 @@ func newVaultContainerHandle(ctx context.Context, path string, containerSnapshot
 		return nil, errf("new vault container handle: failed to get database connection: %w", err)
 	}
 
-	if _, err := conn.ExecContext(ctx, pragma); err != nil {
-		return nil, err
-	}
-
 	if containerSnapshot != nil {
 		if err := Deserialize(conn, containerSnapshot); err != nil {
 			return nil, errf("new vault container handle: failed to deserialize snapshot: %w", err)
 		}
 	}
 
-	m := migrate.New(db, migrate.SQLiteDialect{})
+	container := vaultcontainer.New(db, maxHistorySnapshots)
 
-	_, err = m.Apply(vaultContainerMigrations)
-	if err != nil {
+	if err := checkFormat(ctx, container); err != nil {
+		return nil, errf("new vault container handle: %w", err)
+	}
+
+	if err := applyMigrations(ctx, db, vaultContainerMigrations); err != nil {
 		return nil, errf("new vault container handle: failed to apply migrations: %w", err)
 	}
 
+	if err := upgradeFormat(ctx, container); err != nil {
+		return nil, errf("new vault container handle: failed to record vault format: %w", err)
+	}
+
+	handle.sqlDB = db
 	handle.conn = conn
-	handle.db = vaultcontainer.New(db, maxHistorySnapshots)
+	handle.db = container
 
 	return handle, nil
 }
@@ -556,11 +770,11 @@ func vaultCipherData(password []byte) (*vaultcontainer.CipherData, error) {
 	}, nil
 }
 
-// open decrypts and loads the encrypted vault into memory by deserializing
-// the SQLite database into a preallocated buffer.
+// open decrypts and loads the encrypted vault into an in-memory SQLite database.
 //
-// The buffer is retained in vlt.buf for the lifetime of the in-memory database
-// and must remain valid until Seal() is called, which releases it.
+// The vault is decrypted into locked memory, destroyed once deserialized.
+// sqlite copies the database into memory of its own, which is neither
+// locked nor wiped, and is freed when the connection is closed.
 //
 // This method should only be called once during initialization and must not
 // be called concurrently.
@@ -606,45 +820,122 @@ func (vlt *Vault) open(ctx context.Context, ciphervault []byte) (retErr error) {
 	}
 
 	if ciphervault != nil {
-		decrypted, err := vlt.aesgcm.Open(vlt.decryptionNonce, ciphervault)
+		buf, err := securebytes.New(max(vlt.aesgcm.PlaintextLen(ciphervault), 0))
 		if err != nil {
 			return err
 		}
+		defer func() { retErr = errors.Join(retErr, buf.Destroy()) }() //nolint:wsl_v5
 
-		vlt.buf = decrypted
+		if err := vlt.aesgcm.OpenInto(buf.Bytes(), vlt.decryptionNonce, ciphervault); err != nil {
+			return err
+		}
 
-		if err := Deserialize(conn, vlt.buf); err != nil {
+		if err := Deserialize(conn, buf.Bytes()); err != nil {
 			return err
 		}
 	}
 
-	m := migrate.New(conn, migrate.SQLiteDialect{})
+	if vlt.migrationPlan != nil {
+		planned, err := planMigrations(ctx, conn, vaultMigrations)
+		if err != nil {
+			return err
+		}
 
-	_, err = m.Apply(vaultMigrations)
-	if err != nil {
+		vlt.migrationPlan.Vault = planned
+	}
+
+	if err := applyMigrations(ctx, conn, vaultMigrations); err != nil {
 		return err
 	}
 
 	vlt.conn = conn
 	vlt.db = vaultdb.New(conn)
 
+	return vlt.enforceUniqueNames(ctx)
+}
+
+// enforceUniqueNames creates or drops the unique index on secret names,
+// see [WithUniqueNames].
+//
+// The index is not created while names are not unique, so that the vault
+// can still be opened to rename the duplicates, new conflicts are rejected
+// regardless, see [Vault.DuplicateNames].
+func (vlt *Vault) enforceUniqueNames(ctx context.Context) error {
+	if vlt.uniqueNames {
+		duplicates, err := vlt.db.DuplicateNames(ctx)
+		if err != nil {
+			return fmt.Errorf("unique names: %w", err)
+		}
+
+		if len(duplicates) > 0 {
+			return nil
+		}
+	}
+
+	if err := vlt.db.SetUniqueNames(ctx, vlt.uniqueNames); err != nil {
+		return fmt.Errorf("unique names: %w", err)
+	}
+
 	return nil
 }
 
+// DuplicateNames returns one name of each group of secrets sharing a name,
+// compared case-insensitively, which prevent [WithUniqueNames] from being
+// enforced by the vault database.
+func (vlt *Vault) DuplicateNames(ctx context.Context) ([]string, error) {
+	return vlt.db.DuplicateNames(ctx)
+}
+
+// checkNameConflict returns an error wrapping [vaulterrors.ErrNameConflict]
+// if unique names are enforced and a secret other than id is named name.
+func (vlt *Vault) checkNameConflict(ctx context.Context, storeTx *vaultdb.VaultDB, name string, id int) error {
+	if !vlt.uniqueNames {
+		return nil
+	}
+
+	conflictID, existing, err := storeTx.NameConflict(ctx, name, id)
+	if err != nil {
+		return err
+	}
+
+	if conflictID == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q is taken by secret %d (%q), names are compared case-insensitively",
+		vaulterrors.ErrNameConflict, name, conflictID, existing)
+}
+
 // deriveAESGCM derives an AES-GCM cipher using the given PHC and password.
 // The [vaultcrypto.Argon2idPHC] provides the key derivation parameters,
 // and the password is used to derive the encryption key.
-func deriveAESGCM(phc vaultcrypto.Argon2idPHC, password []byte) (*vaultcrypto.AESGCM, error) {
+func deriveAESGCM(phc vaultcrypto.Argon2idPHC, password []byte) (*vaultcrypto.AESGCM, *securebytes.Buffer, error) {
 	kdf := vaultcrypto.NewArgon2idKDF(vaultcrypto.WithPHC(phc))
 
-	key := kdf.Derive(password)
+	aes, key, err := newAESGCM(kdf.Derive(password))
+	if err != nil {
+		return nil, nil, errf("derive AES-GCM: %w", err)
+	}
+
+	return aes, key, nil
+}
+
+// newAESGCM initializes the AES-GCM cipher for the given key,
+// and moves the key into locked memory, wiping the given slice.
+func newAESGCM(key []byte) (*vaultcrypto.AESGCM, *securebytes.Buffer, error) {
+	defer securebytes.Wipe(key)
 
 	aes, err := vaultcrypto.NewAESGCM(key)
 	if err != nil {
-		return nil, errf("derive AES-GCM: %w", err)
+		return nil, nil, err
 	}
 
-	return aes, nil
+	buf, err := securebytes.From(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return aes, buf, nil
 }
 
 func errf(format string, a ...any) error {
@@ -686,7 +977,7 @@ func (vlt *Vault) InsertNewSecret(ctx context.Context, name string, secret []byt
 
 	storeTx := vlt.db.WithTx(tx)
 
-	nonce, err := vaultcrypto.RandBytes(vaultcrypto.NonceSizeGCM)
+	secretID, err := vlt.insertSecret(ctx, storeTx, storeTx, name, secret, labels, insertConfig.id)
 	if err != nil {
 		if err2 := tx.Rollback(); err2 != nil {
 			return 0, errf("insert new secret: rollback: %w", errors.Join(err2, err))
@@ -695,45 +986,124 @@ func (vlt *Vault) InsertNewSecret(ctx context.Context, name string, secret []byt
 		return 0, errf("insert new secret: %w", err)
 	}
 
-	ciphertext, err := vlt.aesgcm.Seal(nonce, secret)
+	if err := tx.Commit(); err != nil {
+		return 0, errf("insert new secret: tx commit: %w", err)
+	}
+
+	return secretID, nil
+}
+
+// NewSecret is a secret to insert into the vault, see [Vault.InsertNewSecrets].
+type NewSecret struct {
+	// ID is the id of the inserted secret, e.g., to keep the ids of rotated secrets.
+	// If zero, a new id is assigned.
+	ID int
+
+	Name   string
+	Value  []byte
+	Labels []string
+}
+
+// InsertNewSecrets inserts the given secrets with their labels
+// into the vault using a single transaction, either all secrets
+// are inserted or none is.
+//
+// The labels of all secrets are inserted by a single prepared statement,
+// making it considerably faster than [Vault.InsertNewSecret] for many secrets.
+//
+// Returns the IDs of the inserted secrets, in the order given.
+func (vlt *Vault) InsertNewSecrets(ctx context.Context, secrets []NewSecret) (_ []int, retErr error) {
+	tx, err := vlt.conn.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
+		return nil, err
+	}
+
+	storeTx := vlt.db.WithTx(tx)
+
+	rollback := func(err error) error {
 		if err2 := tx.Rollback(); err2 != nil {
-			return 0, errf("insert new secret: rollback: %w", errors.Join(err2, err))
+			return errf("insert new secrets: rollback: %w", errors.Join(err2, err))
 		}
 
-		return 0, errf("insert new secret: %w", err)
+		return errf("insert new secrets: %w", err)
+	}
+
+	labelsStmt, err := storeTx.PrepareInsertLabel(ctx)
+	if err != nil {
+		return nil, rollback(err)
+	}
+	defer func() { //nolint:wsl_v5
+		if err := labelsStmt.Close(); err != nil {
+			retErr = errors.Join(retErr, errf("insert new secrets: close statement: %w", err))
+		}
+	}()
+
+	ids := make([]int, 0, len(secrets))
+
+	for i, s := range secrets {
+		var id *int
+		if s.ID != 0 {
+			id = &s.ID
+		}
+
+		secretID, err := vlt.insertSecret(ctx, storeTx, labelsStmt, s.Name, s.Value, s.Labels, id)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("secret %d (%q): %w", i, s.Name, err))
+		}
+
+		ids = append(ids, secretID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errf("insert new secrets: tx commit: %w", err)
+	}
+
+	return ids, nil
+}
+
+// labelInserter inserts the labels of a secret,
+// either a [vaultdb.VaultDB] or a [vaultdb.LabelInserter].
+type labelInserter interface {
+	InsertLabel(ctx context.Context, name string, secretID int) (int64, error)
+}
+
+// insertSecret encrypts and inserts a secret using storeTx, and its labels using
+// labelsTx, with the given id if set. The caller owns the transaction.
+func (vlt *Vault) insertSecret(ctx context.Context, storeTx *vaultdb.VaultDB, labelsTx labelInserter, name string, secret []byte, labels []string, id *int) (int, error) {
+	if err := vlt.checkNameConflict(ctx, storeTx, name, 0); err != nil {
+		return 0, err
+	}
+
+	nonce, err := vaultcrypto.RandBytes(vaultcrypto.NonceSizeGCM)
+	if err != nil {
+		return 0, err
+	}
+
+	vlt.observeSecret(secret)
+
+	ciphertext, err := vlt.aesgcm.Seal(nonce, secret)
+	if err != nil {
+		return 0, err
 	}
 
 	var secretID int
 
-	if insertConfig.id != nil {
-		secretID, err = storeTx.InsertNewSecretWithID(ctx, *insertConfig.id, name, nonce, ciphertext)
+	if id != nil {
+		secretID, err = storeTx.InsertNewSecretWithID(ctx, *id, name, nonce, ciphertext)
 	} else {
 		secretID, err = storeTx.InsertNewSecret(ctx, name, nonce, ciphertext)
 	}
 
 	if err != nil {
-		if err2 := tx.Rollback(); err2 != nil {
-			return 0, errf("insert new secret: rollback: %w", errors.Join(err2, err))
-		}
-
-		return 0, errf("insert new secret: %w", err)
+		return 0, err
 	}
 
 	for _, l := range labels {
-		if _, err := storeTx.InsertLabel(ctx, l, secretID); err != nil {
-			if err2 := tx.Rollback(); err2 != nil {
-				return 0, errf("insert new secret: insert label: rollback: %w", errors.Join(err2, err))
-			}
-
-			return 0, errf("insert new secret: insert label: %w", err)
+		if _, err := labelsTx.InsertLabel(ctx, l, secretID); err != nil {
+			return 0, fmt.Errorf("insert label: %w", err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return 0, errf("insert new secret: tx commit: %w", err)
-	}
-
 	return secretID, nil
 }
 
@@ -747,7 +1117,11 @@ func (vlt *Vault) UpdateSecretMetadata(ctx context.Context, id int, newName stri
 	updateTx := vlt.db.WithTx(tx)
 
 	if len(newName) > 0 {
-		_, err = vlt.db.UpdateName(ctx, id, newName)
+		err = vlt.checkNameConflict(ctx, updateTx, newName, id)
+		if err == nil {
+			_, err = updateTx.UpdateName(ctx, id, newName)
+		}
+
 		if err != nil {
 			if err2 := tx.Rollback(); err2 != nil {
 				return errf("update secret: name: rollback: %w", errors.Join(err2, err))
@@ -784,6 +1158,93 @@ func (vlt *Vault) UpdateSecretMetadata(ctx context.Context, id int, newName stri
 	return nil
 }
 
+// UpdateSecretAttributes sets and removes key-value attributes
+// of the secret identified by id using a transaction.
+//
+// Attribute values are encrypted, and existing attributes are replaced.
+// Keys in unset that are not set on the secret are ignored.
+func (vlt *Vault) UpdateSecretAttributes(ctx context.Context, id int, set map[string]string, unset []string) error {
+	tx, err := vlt.conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	updateTx := vlt.db.WithTx(tx)
+
+	for _, key := range slices.Sorted(maps.Keys(set)) {
+		nonce, err := vaultcrypto.RandBytes(vaultcrypto.NonceSizeGCM)
+		if err != nil {
+			if err2 := tx.Rollback(); err2 != nil {
+				return errf("update attributes: rollback: %w", errors.Join(err2, err))
+			}
+
+			return errf("update attributes: %w", err)
+		}
+
+		ciphertext, err := vlt.aesgcm.Seal(nonce, []byte(set[key]))
+		if err != nil {
+			if err2 := tx.Rollback(); err2 != nil {
+				return errf("update attributes: rollback: %w", errors.Join(err2, err))
+			}
+
+			return errf("update attributes: %w", err)
+		}
+
+		a := vaultdb.Attribute{SecretID: id, Key: key, Nonce: nonce, Ciphertext: ciphertext}
+		if err := updateTx.UpsertAttribute(ctx, a); err != nil {
+			if err2 := tx.Rollback(); err2 != nil {
+				return errf("update attributes: set %q: rollback: %w", key, errors.Join(err2, err))
+			}
+
+			return errf("update attributes: set %q: %w", key, err)
+		}
+	}
+
+	for _, key := range unset {
+		if _, err := updateTx.DeleteAttribute(ctx, id, key); err != nil {
+			if err2 := tx.Rollback(); err2 != nil {
+				return errf("update attributes: unset %q: rollback: %w", key, errors.Join(err2, err))
+			}
+
+			return errf("update attributes: unset %q: %w", key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errf("update attributes: tx commit: %w", err)
+	}
+
+	return nil
+}
+
+// SecretsAttributes returns the decrypted attributes of the secrets with the given IDs,
+// keyed by secret id. Secrets without attributes are omitted.
+//
+// If the IDs slice is empty, the function returns [vaultdb.ErrNoIDsProvided].
+func (vlt *Vault) SecretsAttributes(ctx context.Context, ids ...int) (map[int]map[string]string, error) {
+	attrs, err := vlt.db.AttributesByIDs(ctx, ids)
+	if err != nil {
+		return nil, errf("secrets attributes: %w", err)
+	}
+
+	m := make(map[int]map[string]string)
+
+	for _, a := range attrs {
+		value, err := vlt.aesgcm.Open(a.Nonce, a.Ciphertext)
+		if err != nil {
+			return nil, errf("secrets attributes: %q: %w", a.Key, err)
+		}
+
+		if m[a.SecretID] == nil {
+			m[a.SecretID] = make(map[string]string)
+		}
+
+		m[a.SecretID][a.Key] = string(value)
+	}
+
+	return m, nil
+}
+
 // UpdateSecret updates the secret value of the secret identified by id.
 func (vlt *Vault) UpdateSecret(ctx context.Context, id int, secret []byte) (int64, error) {
 	nonce, err := vaultcrypto.RandBytes(vaultcrypto.NonceSizeGCM)
@@ -791,6 +1252,8 @@ func (vlt *Vault) UpdateSecret(ctx context.Context, id int, secret []byte) (int6
 		return 0, errf("update secret: %w", err)
 	}
 
+	vlt.observeSecret(secret)
+
 	ciphertext, err := vlt.aesgcm.Seal(nonce, secret)
 	if err != nil {
 		return 0, errf("update secret: %w", err)
@@ -812,6 +1275,8 @@ func (vlt *Vault) ExportSecrets(ctx context.Context) (map[int]vaultdb.SecretWith
 			return nil, err
 		}
 
+		vlt.observeSecret(decrypted)
+
 		s.Value = decrypted
 
 		encryptedSecrets[id] = s
@@ -840,6 +1305,9 @@ func (vlt *Vault) SecretsByIDs(ctx context.Context, ids ...int) (map[int]vaultdb
 }
 
 // ShowSecret returns the decrypted ciphertext associated with the given secret ID.
+//
+// The caller owns the returned value and should wipe it once done,
+// prefer [Vault.WithSecret] where the value is only needed for a short time.
 func (vlt *Vault) ShowSecret(ctx context.Context, id int) ([]byte, error) {
 	nonce, ciphertext, err := vlt.db.ShowSecret(ctx, id)
 	if err != nil {
@@ -851,14 +1319,87 @@ func (vlt *Vault) ShowSecret(ctx context.Context, id int) ([]byte, error) {
 		return nil, errf("show secret: %w", err)
 	}
 
+	vlt.observeSecret(secret)
+
 	return secret, nil
 }
 
+// WithSecret decrypts the value of the secret identified by id into locked memory,
+// and calls f with it. The value is wiped once f returns, and must not be retained by f,
+// nor by the secret observer, see [WithSecretObserver].
+func (vlt *Vault) WithSecret(ctx context.Context, id int, f func(value []byte) error) error {
+	nonce, ciphertext, err := vlt.db.ShowSecret(ctx, id)
+	if err != nil {
+		return errf("with secret: %w", err)
+	}
+
+	buf, err := securebytes.New(max(vlt.aesgcm.PlaintextLen(ciphertext), 0))
+	if err != nil {
+		return errf("with secret: %w", err)
+	}
+	defer func() { _ = buf.Destroy() }()
+
+	value := buf.Bytes()
+
+	if err := vlt.aesgcm.OpenInto(value, nonce, ciphertext); err != nil {
+		return errf("with secret: %w", err)
+	}
+
+	vlt.observeSecret(value)
+
+	return f(value)
+}
+
+// observeSecret passes a secret value to the secret observer, if set.
+func (vlt *Vault) observeSecret(value []byte) {
+	if vlt.secretObserver != nil {
+		vlt.secretObserver(value)
+	}
+}
+
 // DeleteSecretsByIDs deletes secrets by their IDs, along with their labels.
 func (vlt *Vault) DeleteSecretsByIDs(ctx context.Context, ids ...int) (int64, error) {
 	return vlt.db.DeleteSecretsByIDs(ctx, ids)
 }
 
+// SetLabelMeta sets the display metadata of the label m.Name.
+// Metadata with neither a color nor an icon is removed.
+func (vlt *Vault) SetLabelMeta(ctx context.Context, m vaultdb.LabelMeta) error {
+	if m.Color == "" && m.Icon == "" {
+		if _, err := vlt.db.DeleteLabelMeta(ctx, m.Name); err != nil {
+			return errf("set label meta: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := vlt.db.UpsertLabelMeta(ctx, m); err != nil {
+		return errf("set label meta: %w", err)
+	}
+
+	return nil
+}
+
+// LabelsMeta returns the display metadata of all labels, keyed by label name.
+func (vlt *Vault) LabelsMeta(ctx context.Context) (map[string]vaultdb.LabelMeta, error) {
+	return vlt.db.LabelsMeta(ctx)
+}
+
+// RecordAccess records a retrieval of the secret value at the given time,
+// see [Vault.SecretsUsage].
+func (vlt *Vault) RecordAccess(ctx context.Context, id int, at time.Time) error {
+	if err := vlt.db.RecordAccess(ctx, id, at); err != nil {
+		return errf("record access: %w", err)
+	}
+
+	return nil
+}
+
+// SecretsUsage returns the recorded usage and the modification time of all secrets.
+func (vlt *Vault) SecretsUsage(ctx context.Context) ([]vaultdb.SecretUsage, error) {
+	return vlt.db.SecretsUsage(ctx)
+}
+
 // Vacuum performs a VACUUM operation on the vault database.
 func (vlt *Vault) Vacuum(ctx context.Context) error {
 	return vlt.db.Vacuum(ctx)
@@ -868,3 +1409,9 @@ func (vlt *Vault) Vacuum(ctx context.Context) error {
 func (vlt *Vault) VacuumContainer(ctx context.Context) error {
 	return vlt.containerHandle.db.Vacuum(ctx)
 }
+
+// ContainerPageStats returns the page usage of the vault container database,
+// e.g., the space reclaimed by [Vault.VacuumContainer].
+func (vlt *Vault) ContainerPageStats(ctx context.Context) (vaultcontainer.PageStats, error) {
+	return vlt.containerHandle.db.PageStats(ctx)
+}
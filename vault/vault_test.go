@@ -1,10 +1,22 @@
 package vault_test
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"path"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vault/sqlite/vaultdb"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	"filippo.io/age"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 // https://github.com/spf13/cobra/issues/1419
@@ -52,3 +64,664 @@ func TestVault_New(t *testing.T) {
 		t.Errorf("got %d secrets after reopen, want %d", got, want)
 	}
 }
+
+func TestVault_SecretAttributes(t *testing.T) {
+	vaultPath := path.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	id, err := v.InsertNewSecret(t.Context(), "secret", []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("failed to insert new secret: %v", err)
+	}
+
+	other, err := v.InsertNewSecret(t.Context(), "other", []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("failed to insert new secret: %v", err)
+	}
+
+	if err := v.UpdateSecretAttributes(t.Context(), id, map[string]string{"env": "dev", "owner": "alice"}, nil); err != nil {
+		t.Fatalf("failed to set attributes: %v", err)
+	}
+
+	if err := v.UpdateSecretAttributes(t.Context(), id, map[string]string{"env": "prod"}, []string{"owner", "missing"}); err != nil {
+		t.Fatalf("failed to update attributes: %v", err)
+	}
+
+	attrs, err := v.SecretsAttributes(t.Context(), id, other)
+	if err != nil {
+		t.Fatalf("failed to get attributes: %v", err)
+	}
+
+	want := map[int]map[string]string{id: {"env": "prod"}}
+
+	if diff := cmp.Diff(want, attrs); diff != "" {
+		t.Errorf("attributes mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := v.DeleteSecretsByIDs(t.Context(), id); err != nil {
+		t.Fatalf("failed to delete secret: %v", err)
+	}
+
+	attrs, err = v.SecretsAttributes(t.Context(), id)
+	if err != nil {
+		t.Fatalf("failed to get attributes: %v", err)
+	}
+
+	if len(attrs) > 0 {
+		t.Errorf("want no attributes after delete, got %v", attrs)
+	}
+}
+
+func TestVault_Members(t *testing.T) {
+	vaultPath := path.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	if _, err := v.InsertNewSecret(t.Context(), "secret", []byte("secret"), nil); err != nil {
+		t.Fatalf("failed to insert new secret: %v", err)
+	}
+
+	if _, err := v.Seal(t.Context()); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+
+	alice, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("generate identity: %v", err)
+	}
+
+	bob, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("generate identity: %v", err)
+	}
+
+	if err := v.AddMember(t.Context(), "alice", alice.Recipient().String()); err != nil {
+		t.Fatalf("failed to add member: %v", err)
+	}
+
+	if err := v.AddMember(t.Context(), "alice", bob.Recipient().String()); !errors.Is(err, vault.ErrMemberExists) {
+		t.Errorf("want %v for a duplicate name, got %v", vault.ErrMemberExists, err)
+	}
+
+	if err := v.AddMember(t.Context(), "bob", "age1invalid"); err == nil {
+		t.Error("want error for an invalid recipient")
+	}
+
+	members, err := v.Members(t.Context())
+	if err != nil {
+		t.Fatalf("failed to list members: %v", err)
+	}
+
+	if len(members) != 1 || members[0].Name != "alice" || members[0].Recipient != alice.Recipient().String() {
+		t.Errorf("want member alice, got %+v", members)
+	}
+
+	key, nonce, err := vault.LoginWithIdentity(t.Context(), vaultPath, []age.Identity{bob, alice})
+	if err != nil {
+		t.Fatalf("failed to login with identity: %v", err)
+	}
+
+	want, _, err := vault.Login(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to login: %v", err)
+	}
+
+	if diff := cmp.Diff(want, key); diff != "" {
+		t.Errorf("unwrapped key mismatch (-want +got):\n%s", diff)
+	}
+
+	opened, err := vault.Open(t.Context(), vaultPath, vault.WithSessionKey(key, nonce))
+	if err != nil {
+		t.Fatalf("failed to open vault with the unwrapped key: %v", err)
+	}
+	t.Cleanup(func() { _ = opened.Close() }) //nolint:wsl_v5
+
+	if _, _, err := vault.LoginWithIdentity(t.Context(), vaultPath, []age.Identity{bob}); !errors.Is(err, vault.ErrAuthenticationFailed) {
+		t.Errorf("want %v for a non-member identity, got %v", vault.ErrAuthenticationFailed, err)
+	}
+
+	if err := v.RemoveMember(t.Context(), "alice"); err != nil {
+		t.Fatalf("failed to remove member: %v", err)
+	}
+
+	if err := v.RemoveMember(t.Context(), "alice"); !errors.Is(err, vault.ErrMemberNotFound) {
+		t.Errorf("want %v, got %v", vault.ErrMemberNotFound, err)
+	}
+
+	if _, _, err := vault.LoginWithIdentity(t.Context(), vaultPath, []age.Identity{alice}); !errors.Is(err, vault.ErrAuthenticationFailed) {
+		t.Errorf("want %v for a removed member, got %v", vault.ErrAuthenticationFailed, err)
+	}
+}
+
+func TestVault_Backup(t *testing.T) {
+	dir := t.TempDir()
+	vaultPath := path.Join(dir, ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	backupPath := path.Join(dir, "backup")
+
+	if err := vault.Backup(t.Context(), vaultPath, backupPath); err != nil {
+		t.Fatalf("failed to back up vault: %v", err)
+	}
+
+	if err := vault.VerifyBackup(t.Context(), backupPath); err != nil {
+		t.Errorf("failed to verify backup: %v", err)
+	}
+
+	if err := vault.Backup(t.Context(), vaultPath, backupPath); err == nil {
+		t.Error("want error for an existing backup file")
+	}
+
+	if _, _, err := vault.Login(t.Context(), backupPath, []byte("password")); err != nil {
+		t.Errorf("failed to login to backup: %v", err)
+	}
+
+	garbage := path.Join(dir, "garbage")
+	if err := os.WriteFile(garbage, []byte("not a vault"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vault.VerifyBackup(t.Context(), garbage); !errors.Is(err, vault.ErrBackupVerification) {
+		t.Errorf("want %v, got %v", vault.ErrBackupVerification, err)
+	}
+}
+
+func TestVault_OpenBackup(t *testing.T) {
+	dir := t.TempDir()
+	vaultPath := path.Join(dir, ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	if _, err := v.InsertNewSecret(t.Context(), "name", []byte("secret"), []string{"label"}); err != nil {
+		t.Fatalf("failed to insert new secret: %v", err)
+	}
+
+	if _, err := v.Seal(t.Context()); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+
+	backupPath := path.Join(dir, "backup")
+
+	if err := vault.Backup(t.Context(), vaultPath, backupPath); err != nil {
+		t.Fatalf("failed to back up vault: %v", err)
+	}
+
+	before, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := vault.OpenBackup(t.Context(), backupPath, []byte("wrong")); !errors.Is(err, vault.ErrAuthenticationFailed) {
+		t.Errorf("want %v, got %v", vault.ErrAuthenticationFailed, err)
+	}
+
+	restored, err := vault.OpenBackup(t.Context(), backupPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to open backup: %v", err)
+	}
+
+	report, err := restored.Check(t.Context())
+	if err != nil {
+		t.Fatalf("failed to check backup: %v", err)
+	}
+
+	if err := restored.Close(); err != nil {
+		t.Errorf("failed to close backup: %v", err)
+	}
+
+	if report.Secrets != 1 || len(report.Issues) > 0 {
+		t.Errorf("want 1 secret and no issues, got %d secrets and issues %v", report.Secrets, report.Issues)
+	}
+
+	after, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(before, after) {
+		t.Error("want the backup file unmodified")
+	}
+}
+
+func TestVault_UnlockThrottling(t *testing.T) {
+	vaultPath := path.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("failed to close vault: %v", err)
+	}
+
+	for range 3 {
+		if _, _, err := vault.Login(t.Context(), vaultPath, []byte("wrong")); !errors.Is(err, vault.ErrAuthenticationFailed) {
+			t.Fatalf("want %v, got %v", vault.ErrAuthenticationFailed, err)
+		}
+	}
+
+	attempts, err := vault.FailedUnlocks(t.Context(), vaultPath)
+	if err != nil {
+		t.Fatalf("failed to read unlock attempts: %v", err)
+	}
+
+	if attempts.Failed != 3 || attempts.LastFailedAt.IsZero() {
+		t.Errorf("want 3 failed attempts with a timestamp, got %+v", attempts)
+	}
+
+	// the correct password is throttled as well, it is not verified.
+	_, _, err = vault.Login(t.Context(), vaultPath, []byte("password"))
+
+	var throttled *vault.ThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("want %T, got %v", throttled, err)
+	}
+
+	if throttled.Failed != 3 || throttled.RetryAfter <= 0 || throttled.RetryAfter > time.Second {
+		t.Errorf("want a retry within a second after 3 failures, got %+v", throttled)
+	}
+
+	time.Sleep(throttled.RetryAfter)
+
+	if _, _, err := vault.Login(t.Context(), vaultPath, []byte("password")); err != nil {
+		t.Fatalf("failed to login after the delay: %v", err)
+	}
+
+	attempts, err = vault.FailedUnlocks(t.Context(), vaultPath)
+	if err != nil {
+		t.Fatalf("failed to read unlock attempts: %v", err)
+	}
+
+	if attempts.Failed != 0 {
+		t.Errorf("want failed attempts reset by a successful login, got %d", attempts.Failed)
+	}
+}
+
+func TestVault_RecordAccess(t *testing.T) {
+	vaultPath := path.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	used, err := v.InsertNewSecret(t.Context(), "used", []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("failed to insert new secret: %v", err)
+	}
+
+	unused, err := v.InsertNewSecret(t.Context(), "unused", []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("failed to insert new secret: %v", err)
+	}
+
+	first, last := time.Unix(1_700_000_000, 0), time.Unix(1_700_000_100, 0)
+
+	for _, at := range []time.Time{first, last} {
+		if err := v.RecordAccess(t.Context(), used, at); err != nil {
+			t.Fatalf("failed to record access: %v", err)
+		}
+	}
+
+	usage, err := v.SecretsUsage(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+
+	want := []vaultdb.SecretUsage{
+		{ID: used, Name: "used", AccessCount: 2, LastAccessed: last},
+		{ID: unused, Name: "unused"},
+	}
+
+	if diff := cmp.Diff(want, usage, cmpopts.IgnoreFields(vaultdb.SecretUsage{}, "Modified")); diff != "" {
+		t.Errorf("usage mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, u := range usage {
+		if time.Since(u.Modified).Abs() > time.Minute {
+			t.Errorf("%s: want a recent modification time, got %v", u.Name, u.Modified)
+		}
+	}
+
+	if _, err := v.DeleteSecretsByIDs(t.Context(), used); err != nil {
+		t.Fatalf("failed to delete secret: %v", err)
+	}
+
+	usage, err = v.SecretsUsage(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+
+	if len(usage) != 1 || usage[0].ID != unused {
+		t.Errorf("want only the unused secret after delete, got %+v", usage)
+	}
+}
+
+func TestVault_UniqueNames(t *testing.T) {
+	vaultPath := path.Join(t.TempDir(), ".vlt.temp")
+	password := []byte("password")
+
+	v, err := vault.New(t.Context(), vaultPath, password)
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	// duplicates inserted while unique names are not enforced.
+	for _, name := range []string{"github", "GitHub", "gitlab"} {
+		if _, err := v.InsertNewSecret(t.Context(), name, []byte("secret"), nil); err != nil {
+			t.Fatalf("failed to insert new secret: %v", err)
+		}
+	}
+
+	if _, err := v.Seal(t.Context()); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+
+	_ = v.Close()
+
+	v, err = vault.Open(t.Context(), vaultPath, vault.WithPassword(password), vault.WithUniqueNames(true))
+	if err != nil {
+		t.Fatalf("failed to open vault with duplicate names: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	duplicates, err := v.DuplicateNames(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get duplicate names: %v", err)
+	}
+
+	if len(duplicates) != 1 || !strings.EqualFold(duplicates[0], "github") {
+		t.Errorf("want the github duplicates, got %q", duplicates)
+	}
+
+	if _, err := v.InsertNewSecret(t.Context(), "GITLAB", []byte("secret"), nil); !errors.Is(err, vaulterrors.ErrNameConflict) {
+		t.Errorf("insert: want %v, got %v", vaulterrors.ErrNameConflict, err)
+	}
+
+	if err := v.UpdateSecretMetadata(t.Context(), 3, "Gitlab", nil, nil); err != nil {
+		t.Errorf("renaming a secret to its own name in another case: unexpected error: %v", err)
+	}
+
+	if err := v.UpdateSecretMetadata(t.Context(), 2, "gitlab", nil, nil); !errors.Is(err, vaulterrors.ErrNameConflict) {
+		t.Errorf("rename: want %v, got %v", vaulterrors.ErrNameConflict, err)
+	}
+
+	if err := v.UpdateSecretMetadata(t.Context(), 2, "github-work", nil, nil); err != nil {
+		t.Fatalf("failed to rename duplicate: %v", err)
+	}
+
+	if _, err := v.Seal(t.Context()); err != nil {
+		t.Fatalf("failed to seal vault: %v", err)
+	}
+
+	_ = v.Close()
+
+	v, err = vault.Open(t.Context(), vaultPath, vault.WithPassword(password), vault.WithUniqueNames(true))
+	if err != nil {
+		t.Fatalf("failed to reopen vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	// the batch is rolled back as a whole.
+	_, err = v.InsertNewSecrets(t.Context(), []vault.NewSecret{
+		{Name: "aws", Value: []byte("secret")},
+		{Name: "AWS", Value: []byte("secret")},
+	})
+	if !errors.Is(err, vaulterrors.ErrNameConflict) {
+		t.Errorf("batch insert: want %v, got %v", vaulterrors.ErrNameConflict, err)
+	}
+
+	secrets, err := v.FilterSecrets(t.Context(), "", "aws", nil)
+	if err != nil {
+		t.Fatalf("failed to filter secrets: %v", err)
+	}
+
+	if len(secrets) != 0 {
+		t.Errorf("want no secret of the rolled back batch, got %v", secrets)
+	}
+}
+
+func TestVault_InsertNewSecrets(t *testing.T) {
+	v, err := vault.New(t.Context(), path.Join(t.TempDir(), ".vlt.temp"), []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	ids, err := v.InsertNewSecrets(t.Context(), []vault.NewSecret{
+		{Name: "github", Value: []byte("secret1"), Labels: []string{"dev", "work"}},
+		{ID: 42, Name: "gitlab", Value: []byte("secret2"), Labels: []string{"dev"}},
+		{Name: "bank", Value: []byte("secret3")},
+	})
+	if err != nil {
+		t.Fatalf("failed to insert secrets: %v", err)
+	}
+
+	if len(ids) != 3 || ids[1] != 42 {
+		t.Fatalf("want 3 ids with the given id 42 second, got %v", ids)
+	}
+
+	got, err := v.SecretsByIDs(t.Context(), ids...)
+	if err != nil {
+		t.Fatalf("secrets by ids: %v", err)
+	}
+
+	want := map[int]vaultdb.SecretWithLabels{
+		ids[0]: {Name: "github", Labels: []string{"dev", "work"}},
+		ids[1]: {Name: "gitlab", Labels: []string{"dev"}},
+		ids[2]: {Name: "bank", Labels: []string{}},
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("secrets mismatch (-want +got):\n%s", diff)
+	}
+
+	for i, value := range []string{"secret1", "secret2", "secret3"} {
+		secret, err := v.ShowSecret(t.Context(), ids[i])
+		if err != nil || string(secret) != value {
+			t.Errorf("secret %d: want %q, got %q (%v)", ids[i], value, secret, err)
+		}
+	}
+}
+
+func TestVault_SecretObserver(t *testing.T) {
+	vaultPath := path.Join(t.TempDir(), ".vlt.temp")
+
+	var observed []string
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"),
+		vault.WithSecretObserver(func(value []byte) { observed = append(observed, string(value)) }))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	id, err := v.InsertNewSecret(t.Context(), "name", []byte("inserted"), nil)
+	if err != nil {
+		t.Fatalf("failed to insert new secret: %v", err)
+	}
+
+	if _, err := v.UpdateSecret(t.Context(), id, []byte("updated")); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+
+	if _, err := v.ShowSecret(t.Context(), id); err != nil {
+		t.Fatalf("failed to show secret: %v", err)
+	}
+
+	if _, err := v.ExportSecrets(t.Context()); err != nil {
+		t.Fatalf("failed to export secrets: %v", err)
+	}
+
+	want := []string{"inserted", "updated", "updated", "updated"}
+
+	if diff := cmp.Diff(want, observed); diff != "" {
+		t.Errorf("observed values mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVault_WithSecret(t *testing.T) {
+	vaultPath := path.Join(t.TempDir(), ".vlt.temp")
+
+	v, err := vault.New(t.Context(), vaultPath, []byte("password"))
+	if err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	t.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	id, err := v.InsertNewSecret(t.Context(), "name", []byte("secret"), nil)
+	if err != nil {
+		t.Fatalf("failed to insert new secret: %v", err)
+	}
+
+	var got string
+
+	if err := v.WithSecret(t.Context(), id, func(value []byte) error {
+		got = string(value)
+		return nil
+	}); err != nil {
+		t.Fatalf("with secret: %v", err)
+	}
+
+	if got != "secret" {
+		t.Errorf("want %q, got %q", "secret", got)
+	}
+
+	errCallback := errors.New("callback error")
+
+	if err := v.WithSecret(t.Context(), id, func([]byte) error { return errCallback }); !errors.Is(err, errCallback) {
+		t.Errorf("want the callback error, got %v", err)
+	}
+
+	called := false
+
+	if err := v.WithSecret(t.Context(), id+1, func([]byte) error { called = true; return nil }); err == nil || called {
+		t.Errorf("missing secret: want an error without calling f, got %v (called: %t)", err, called)
+	}
+}
+
+func BenchmarkVault_FilterSecrets(b *testing.B) {
+	const n = 50_000
+
+	v, err := vault.New(b.Context(), path.Join(b.TempDir(), ".vlt.temp"), []byte("password"))
+	if err != nil {
+		b.Fatalf("failed to create vault: %v", err)
+	}
+	b.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+	secrets := make([]vault.NewSecret, n)
+	for i := range secrets {
+		secrets[i] = vault.NewSecret{
+			Name:   fmt.Sprintf("secret-%05d", i),
+			Value:  []byte("secret"),
+			Labels: []string{fmt.Sprintf("team-%02d", i%50), fmt.Sprintf("env-%d", i%5)},
+		}
+	}
+
+	if _, err := v.InsertNewSecrets(b.Context(), secrets); err != nil {
+		b.Fatalf("failed to insert secrets: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		wildcard string
+		secret   string
+		labels   []string
+		want     int
+	}{
+		{name: "all", want: n},
+		{name: "name", secret: "secret-01234", want: 1},
+		{name: "name prefix", secret: "secret-012*", want: 100},
+		{name: "label", labels: []string{"team-07"}, want: n / 50},
+		{name: "labels", labels: []string{"team-07", "team-08"}, want: n / 25},
+		{name: "name and label", secret: "secret-0*", labels: []string{"team-07"}, want: 200},
+		{name: "wildcard name", wildcard: "secret-0123*", want: 10},
+		{name: "wildcard label", wildcard: "team-07", want: n / 50},
+	}
+
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			for b.Loop() {
+				got, err := v.FilterSecrets(b.Context(), tt.wildcard, tt.secret, tt.labels)
+				if err != nil {
+					b.Fatalf("filter secrets: %v", err)
+				}
+
+				if len(got) != tt.want {
+					b.Fatalf("got %d secrets, want %d", len(got), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVault_InsertNewSecrets(b *testing.B) {
+	const n = 1_000
+
+	secrets := make([]vault.NewSecret, n)
+	for i := range secrets {
+		secrets[i] = vault.NewSecret{
+			Name:   fmt.Sprintf("secret-%05d", i),
+			Value:  []byte("secret"),
+			Labels: []string{fmt.Sprintf("team-%02d", i%50), fmt.Sprintf("env-%d", i%5)},
+		}
+	}
+
+	newVault := func(b *testing.B) *vault.Vault {
+		b.Helper()
+
+		v, err := vault.New(b.Context(), path.Join(b.TempDir(), ".vlt.temp"), []byte("password"))
+		if err != nil {
+			b.Fatalf("failed to create vault: %v", err)
+		}
+		b.Cleanup(func() { _ = v.Close() }) //nolint:wsl_v5
+
+		return v
+	}
+
+	b.Run("batch", func(b *testing.B) {
+		for b.Loop() {
+			b.StopTimer()
+			v := newVault(b)
+			b.StartTimer()
+
+			if _, err := v.InsertNewSecrets(b.Context(), secrets); err != nil {
+				b.Fatalf("failed to insert secrets: %v", err)
+			}
+		}
+	})
+
+	b.Run("single", func(b *testing.B) {
+		for b.Loop() {
+			b.StopTimer()
+			v := newVault(b)
+			b.StartTimer()
+
+			for _, s := range secrets {
+				if _, err := v.InsertNewSecret(b.Context(), s.Name, s.Value, s.Labels); err != nil {
+					b.Fatalf("failed to insert secret: %v", err)
+				}
+			}
+		}
+	})
+}
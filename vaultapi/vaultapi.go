@@ -0,0 +1,311 @@
+// Package vaultapi provides access to vlt vaults for Go programs embedding
+// them, independent of the vlt cli, its configuration and terminal IO.
+//
+// A vault is unlocked by [Open] or [Create] into memory. Changes made by
+// [Vault.Put] and [Vault.Delete] are held in memory until persisted using
+// [Vault.Seal], and discarded by [Vault.Close] otherwise:
+//
+//	v, err := vaultapi.Open(ctx, path, password)
+//	if err != nil {
+//		return err
+//	}
+//	defer v.Close()
+//
+//	if err := v.Put(ctx, "github/token", token, "work"); err != nil {
+//		return err
+//	}
+//
+//	return v.Seal(ctx)
+//
+// Errors wrap the sentinel errors of [vaulterrors] and [vault],
+// e.g., [vaulterrors.ErrSearchNoMatch] or [vault.ErrAuthenticationFailed].
+package vaultapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+)
+
+const (
+	// DefaultMaxHistorySnapshots is the default number of
+	// history snapshots kept by the vault, as used by the vlt cli.
+	DefaultMaxHistorySnapshots = 3
+
+	// DefaultAutoVacuumThreshold is the default reclaimable space in bytes
+	// above which the vault file is vacuumed, as used by the vlt cli.
+	DefaultAutoVacuumThreshold = 1 << 20
+
+	// vaultPerm is the permission of vault files created by [Create].
+	vaultPerm = 0o600
+)
+
+var ErrEmptyName = errors.New("secret name cannot be empty")
+
+// Secret describes a secret stored in the vault, without its value.
+type Secret struct {
+	ID     int
+	Name   string
+	Labels []string
+}
+
+type options struct {
+	maxHistorySnapshots int
+	autoVacuumThreshold int64
+}
+
+type Option func(*options)
+
+// WithMaxHistorySnapshots sets the number of history snapshots to keep,
+// zero disables history. Defaults to [DefaultMaxHistorySnapshots].
+func WithMaxHistorySnapshots(n int) Option {
+	return func(o *options) {
+		o.maxHistorySnapshots = n
+	}
+}
+
+// WithAutoVacuumThreshold sets the reclaimable space in bytes above which the
+// vault file is vacuumed when sealed, zero disables it.
+// Defaults to [DefaultAutoVacuumThreshold].
+func WithAutoVacuumThreshold(n int64) Option {
+	return func(o *options) {
+		o.autoVacuumThreshold = n
+	}
+}
+
+func (o *options) vaultOptions() []vault.Option {
+	return []vault.Option{
+		vault.WithMaxHistorySnapshots(o.maxHistorySnapshots),
+		vault.WithAutoVacuumThreshold(o.autoVacuumThreshold),
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		maxHistorySnapshots: DefaultMaxHistorySnapshots,
+		autoVacuumThreshold: DefaultAutoVacuumThreshold,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// Vault is an unlocked vlt vault. It is not safe for concurrent use.
+type Vault struct {
+	vlt *vault.Vault
+}
+
+// Create creates a new empty vault at path, encrypted using password.
+//
+// It returns [vaulterrors.ErrVaultFileExists] if path exists.
+func Create(ctx context.Context, path string, password []byte, opts ...Option) (_ *Vault, retErr error) {
+	if len(password) == 0 {
+		return nil, fmt.Errorf("create: %w", vaulterrors.ErrEmptyPassword)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		if err == nil {
+			err = vaulterrors.ErrVaultFileExists
+		}
+
+		return nil, fmt.Errorf("create: %w", err)
+	}
+
+	vlt, err := vault.New(ctx, path, password, newOptions(opts).vaultOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("create: %w", err)
+	}
+	defer func() { //nolint:wsl_v5
+		if retErr != nil {
+			_ = vlt.Close()
+		}
+	}()
+
+	if _, err := vlt.Seal(ctx); err != nil {
+		return nil, fmt.Errorf("create: %w", err)
+	}
+
+	if err := os.Chmod(path, vaultPerm); err != nil {
+		return nil, fmt.Errorf("create: %w", err)
+	}
+
+	return &Vault{vlt: vlt}, nil
+}
+
+// Open unlocks the existing vault at path using password.
+//
+// It returns [vaulterrors.ErrVaultFileNotFound] if path does not exist,
+// and [vault.ErrAuthenticationFailed] if the password is incorrect.
+func Open(ctx context.Context, path string, password []byte, opts ...Option) (*Vault, error) {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			err = vaulterrors.ErrVaultFileNotFound
+		}
+
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	vlt, err := vault.Open(ctx, path, append(newOptions(opts).vaultOptions(), vault.WithPassword(password))...)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	return &Vault{vlt: vlt}, nil
+}
+
+// Find returns the secrets with a name matching the glob pattern,
+// and any of the label glob patterns, ordered by id, with sorted labels.
+// An empty pattern and no labels match all secrets.
+func (v *Vault) Find(ctx context.Context, pattern string, labels ...string) ([]Secret, error) {
+	matches, err := v.vlt.FilterSecrets(ctx, "", pattern, labels)
+	if err != nil {
+		return nil, fmt.Errorf("find: %w", err)
+	}
+
+	secrets := make([]Secret, 0, len(matches))
+	for id, s := range matches {
+		slices.Sort(s.Labels)
+		secrets = append(secrets, Secret{ID: id, Name: s.Name, Labels: s.Labels})
+	}
+
+	slices.SortFunc(secrets, func(a, b Secret) int { return a.ID - b.ID })
+
+	return secrets, nil
+}
+
+// Get returns the value of the secret named name.
+// The caller should wipe the returned value once done with it.
+//
+// It returns [vaulterrors.ErrSearchNoMatch] if no secret is named name,
+// and [vaulterrors.ErrAmbiguousSecretMatch] if several are.
+func (v *Vault) Get(ctx context.Context, name string) ([]byte, error) {
+	id, err := v.lookup(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get: %w", err)
+	}
+
+	value, err := v.vlt.ShowSecret(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get: %w", err)
+	}
+
+	return value, nil
+}
+
+// Put stores value as the secret named name, adding labels to it.
+// The value of an existing secret named name is replaced.
+//
+// It returns [vaulterrors.ErrAmbiguousSecretMatch] if several secrets are named name.
+func (v *Vault) Put(ctx context.Context, name string, value []byte, labels ...string) error {
+	if len(value) == 0 {
+		return fmt.Errorf("put: %w", vaulterrors.ErrEmptySecret)
+	}
+
+	id, err := v.lookup(ctx, name)
+	if errors.Is(err, vaulterrors.ErrSearchNoMatch) {
+		if _, err := v.vlt.InsertNewSecret(ctx, name, value, labels); err != nil {
+			return fmt.Errorf("put: %w", err)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("put: %w", err)
+	}
+
+	if _, err := v.vlt.UpdateSecret(ctx, id, value); err != nil {
+		return fmt.Errorf("put: %w", err)
+	}
+
+	if len(labels) > 0 {
+		if err := v.vlt.UpdateSecretMetadata(ctx, id, "", nil, labels); err != nil {
+			return fmt.Errorf("put: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the secret named name.
+//
+// It returns [vaulterrors.ErrSearchNoMatch] if no secret is named name,
+// and [vaulterrors.ErrAmbiguousSecretMatch] if several are.
+func (v *Vault) Delete(ctx context.Context, name string) error {
+	id, err := v.lookup(ctx, name)
+	if err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	if _, err := v.vlt.DeleteSecretsByIDs(ctx, id); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Seal persists the changes made to the vault since it was unlocked or last sealed.
+func (v *Vault) Seal(ctx context.Context) error {
+	if _, err := v.vlt.Seal(ctx); err != nil {
+		return fmt.Errorf("seal: %w", err)
+	}
+
+	return nil
+}
+
+// Close locks the vault, wiping its decrypted content from memory.
+// Changes not persisted using [Vault.Seal] are discarded.
+func (v *Vault) Close() error {
+	return v.vlt.Close()
+}
+
+// lookup returns the id of the only secret named name.
+func (v *Vault) lookup(ctx context.Context, name string) (int, error) {
+	if len(name) == 0 {
+		return 0, ErrEmptyName
+	}
+
+	matches, err := v.vlt.FilterSecrets(ctx, "", escapeGlob(name), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("%w: %q", vaulterrors.ErrSearchNoMatch, name)
+	case 1:
+		for id := range matches {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", vaulterrors.ErrAmbiguousSecretMatch, name)
+}
+
+// escapeGlob returns a glob pattern matching s literally.
+func escapeGlob(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[':
+			b.WriteByte('[')
+			b.WriteRune(r)
+			b.WriteByte(']')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package vaultapi_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/vault"
+	"github.com/ladzaretti/vlt-cli/vaultapi"
+	"github.com/ladzaretti/vlt-cli/vaulterrors"
+
+	gocmp "github.com/google/go-cmp/cmp"
+)
+
+func TestVault(t *testing.T) { //nolint:revive // function-length
+	vaultPath := filepath.Join(t.TempDir(), "vault.db")
+	password := []byte("password")
+
+	if _, err := vaultapi.Open(t.Context(), vaultPath, password); !errors.Is(err, vaulterrors.ErrVaultFileNotFound) {
+		t.Errorf("want %v, got %v", vaulterrors.ErrVaultFileNotFound, err)
+	}
+
+	v, err := vaultapi.Create(t.Context(), vaultPath, password)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if fi, err := os.Stat(vaultPath); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("want a 0600 vault file, got %v, %v", fi, err)
+	}
+
+	if _, err := vaultapi.Create(t.Context(), vaultPath, password); !errors.Is(err, vaulterrors.ErrVaultFileExists) {
+		t.Errorf("want %v, got %v", vaulterrors.ErrVaultFileExists, err)
+	}
+
+	for _, s := range [][2]string{{"aws/*", "glob"}, {"aws/key", "akia"}, {"github/token", "ghp"}} {
+		if err := v.Put(t.Context(), s[0], []byte(s[1]), "work"); err != nil {
+			t.Fatalf("put %q: %v", s[0], err)
+		}
+	}
+
+	if err := v.Put(t.Context(), "github/token", []byte("ghp2"), "dev"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if err := v.Put(t.Context(), "empty", nil); !errors.Is(err, vaulterrors.ErrEmptySecret) {
+		t.Errorf("want %v, got %v", vaulterrors.ErrEmptySecret, err)
+	}
+
+	if err := v.Seal(t.Context()); err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	if err := v.Put(t.Context(), "unsealed", []byte("lost")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if _, err := vaultapi.Open(t.Context(), vaultPath, []byte("wrong")); !errors.Is(err, vault.ErrAuthenticationFailed) {
+		t.Errorf("want %v, got %v", vault.ErrAuthenticationFailed, err)
+	}
+
+	v, err = vaultapi.Open(t.Context(), vaultPath, password)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = v.Close() }() //nolint:wsl_v5
+
+	got, err := v.Find(t.Context(), "")
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+
+	want := []vaultapi.Secret{
+		{ID: 1, Name: "aws/*", Labels: []string{"work"}},
+		{ID: 2, Name: "aws/key", Labels: []string{"work"}},
+		{ID: 3, Name: "github/token", Labels: []string{"dev", "work"}},
+	}
+
+	if diff := gocmp.Diff(want, got); diff != "" {
+		t.Errorf("find mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = v.Find(t.Context(), "aws/*", "wo*")
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Errorf("want 2 aws secrets, got %v", got)
+	}
+
+	for name, want := range map[string]string{"github/token": "ghp2", "aws/*": "glob", "aws/key": "akia"} {
+		value, err := v.Get(t.Context(), name)
+		if err != nil {
+			t.Fatalf("get %q: %v", name, err)
+		}
+
+		if string(value) != want {
+			t.Errorf("get %q: got %q, want %q", name, value, want)
+		}
+	}
+
+	if _, err := v.Get(t.Context(), "unsealed"); !errors.Is(err, vaulterrors.ErrSearchNoMatch) {
+		t.Errorf("want %v, got %v", vaulterrors.ErrSearchNoMatch, err)
+	}
+
+	if err := v.Delete(t.Context(), "aws/*"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if _, err := v.Get(t.Context(), "aws/key"); err != nil {
+		t.Errorf("want aws/key kept, got %v", err)
+	}
+
+	if err := v.Delete(t.Context(), "aws/*"); !errors.Is(err, vaulterrors.ErrSearchNoMatch) {
+		t.Errorf("want %v, got %v", vaulterrors.ErrSearchNoMatch, err)
+	}
+
+	if _, err := v.Get(t.Context(), ""); !errors.Is(err, vaultapi.ErrEmptyName) {
+		t.Errorf("want %v, got %v", vaultapi.ErrEmptyName, err)
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"errors"
 )
 
-var ErrNilAESGCM = errors.New("AESGCM is nil")
+var (
+	ErrNilAESGCM    = errors.New("AESGCM is nil")
+	ErrPlaintextLen = errors.New("AESGCM: destination length does not match the plaintext length")
+)
 
 // AESGCM wraps an [cipher.AEAD] using AES in GCM mode.
 type AESGCM struct {
@@ -46,6 +49,39 @@ func (g *AESGCM) Open(nonce, ciphertext []byte) ([]byte, error) {
 	return g.aead.Open(nil, nonce, ciphertext, nil)
 }
 
+// OpenInto decrypts the ciphertext using the given nonce into dst,
+// without intermediate copies of the plaintext, e.g., into locked memory.
+//
+// dst must be the length of the plaintext, see [AESGCM.PlaintextLen].
+func (g *AESGCM) OpenInto(dst, nonce, ciphertext []byte) error {
+	if g == nil {
+		return ErrNilAESGCM
+	}
+
+	if len(dst) != g.PlaintextLen(ciphertext) {
+		return ErrPlaintextLen
+	}
+
+	_, err := g.aead.Open(dst[:0], nonce, ciphertext, nil)
+
+	return err
+}
+
+// PlaintextLen returns the length of the plaintext of the ciphertext,
+// or -1 if the ciphertext is too short to be valid.
+func (g *AESGCM) PlaintextLen(ciphertext []byte) int {
+	if g == nil {
+		return -1
+	}
+
+	n := len(ciphertext) - g.aead.Overhead()
+	if n < 0 {
+		return -1
+	}
+
+	return n
+}
+
 // AEAD returns the underlying cipher.AEAD instance.
 func (g *AESGCM) AEAD() cipher.AEAD {
 	return g.aead
@@ -0,0 +1,49 @@
+package vaultcrypto_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/vaultcrypto"
+)
+
+func TestAESGCM_OpenInto(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	nonce := bytes.Repeat([]byte{2}, vaultcrypto.NonceSizeGCM)
+	plaintext := []byte("secret value")
+
+	g, err := vaultcrypto.NewAESGCM(key)
+	if err != nil {
+		t.Fatalf("new aesgcm: %v", err)
+	}
+
+	ciphertext, err := g.Seal(nonce, plaintext)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	dst := make([]byte, g.PlaintextLen(ciphertext))
+
+	if err := g.OpenInto(dst, nonce, ciphertext); err != nil {
+		t.Fatalf("open into: %v", err)
+	}
+
+	if !bytes.Equal(dst, plaintext) {
+		t.Errorf("want %q, got %q", plaintext, dst)
+	}
+
+	if err := g.OpenInto(make([]byte, len(plaintext)-1), nonce, ciphertext); !errors.Is(err, vaultcrypto.ErrPlaintextLen) {
+		t.Errorf("short destination: want %v, got %v", vaultcrypto.ErrPlaintextLen, err)
+	}
+
+	ciphertext[0] ^= 1
+
+	if err := g.OpenInto(dst, nonce, ciphertext); err == nil {
+		t.Error("tampered ciphertext: want an error, got nil")
+	}
+
+	if got := g.PlaintextLen(nil); got != -1 {
+		t.Errorf("plaintext length of a short ciphertext: want -1, got %d", got)
+	}
+}
@@ -149,3 +149,29 @@ func TestDecodeAragon2idPHC(t *testing.T) {
 		})
 	}
 }
+
+// FuzzDecodeAragon2idPHC verifies decoding arbitrary strings returns an error rather
+// than panic, and that decoded strings survive a round trip through [Argon2idPHC.String].
+func FuzzDecodeAragon2idPHC(f *testing.F) {
+	f.Add("$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA")
+	f.Add("$argon2id$v=16$m=32768,t=2,p=2$c2FsdA")
+	f.Add("$argon2id$v=19$m=65536,t=3,p=256$c2FsdA")
+	f.Add("$argon2i$v=19$m=65536,t=3,p=4$c2FsdA")
+	f.Add("$argon2id$v=19$$$")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		phc, err := vaultcrypto.DecodeAragon2idPHC(s)
+		if err != nil {
+			return
+		}
+
+		got, err := vaultcrypto.DecodeAragon2idPHC(phc.String())
+		if err != nil {
+			t.Fatalf("decode %q: %v", phc.String(), err)
+		}
+
+		if got.String() != phc.String() {
+			t.Errorf("round trip: want %q, got %q", phc.String(), got.String())
+		}
+	})
+}
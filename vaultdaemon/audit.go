@@ -0,0 +1,171 @@
+package vaultdaemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	auditLogPerm    = 0o600
+	auditLogDirPerm = 0o700
+
+	// auditLogName is the file name of the default audit log, see [DefaultAuditLogPath].
+	auditLogName = "vltd-audit.log"
+)
+
+// auditedMethods are the rpc methods recorded in the audit log, i.e., these handling
+// session keys. Rate limited requests of any method are recorded as well.
+var auditedMethods = []string{"Login", "GetSessionKey", "UpdateSession", "MoveSession", "Logout", "LogoutAll"}
+
+// errAuditLogDisabled is returned by GetAuditLog if the daemon runs without an audit log.
+var errAuditLogDisabled = errors.New("audit log disabled")
+
+// DefaultAuditLogPath returns the default path of the daemon audit log,
+// in $XDG_STATE_HOME/vlt, or in ~/.local/state/vlt if unset.
+func DefaultAuditLogPath() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "vlt", auditLogName)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".local", "state", "vlt", auditLogName)
+}
+
+// auditEntry is a line of the audit log, in the JSON Lines format.
+type auditEntry struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	UID    int       `json:"uid"`
+	PID    int       `json:"pid"`
+	Vault  string    `json:"vault,omitempty"`
+	Result string    `json:"result"`
+}
+
+// auditLog appends a record of each session request to a file, which is only
+// ever appended to. Records hold the client uid and pid, and the requested vault.
+type auditLog struct {
+	path   string
+	now    func() time.Time
+	logger *slog.Logger
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+// openAuditLog opens the audit log at path for appending, creating it if needed.
+func openAuditLog(p string, logger *slog.Logger) (*auditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(p), auditLogDirPerm); err != nil {
+		return nil, fmt.Errorf("audit log: %w", err)
+	}
+
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, auditLogPerm)
+	if err != nil {
+		return nil, fmt.Errorf("audit log: %w", err)
+	}
+
+	return &auditLog{path: p, now: time.Now, logger: logger, f: f}, nil
+}
+
+func (a *auditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.f.Close()
+}
+
+// record appends e to the audit log. Failures are logged, not returned,
+// as they must not fail the request.
+func (a *auditLog) record(e auditEntry) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		a.logger.Error("audit log", "err", err)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := a.f.Write(append(line, '\n')); err != nil {
+		a.logger.Error("audit log", "err", err)
+	}
+}
+
+// unaryInterceptor records the audited requests, and the result of handling them.
+func (a *auditLog) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+
+	method := path.Base(info.FullMethod)
+	result := status.Code(err)
+
+	if !slices.Contains(auditedMethods, method) && result != status.Code(errRateLimited) {
+		return resp, err
+	}
+
+	e := auditEntry{Time: a.now(), Method: method, Result: result.String()}
+
+	if cred, ok := peerFromContext(ctx); ok {
+		e.UID, e.PID = cred.uid, cred.pid
+	}
+
+	if r, ok := req.(interface{ GetVaultPath() string }); ok {
+		e.Vault = r.GetVaultPath()
+	}
+
+	a.record(e)
+
+	return resp, err
+}
+
+// readAuditLog returns the last limit entries of the audit log at path,
+// oldest first, or all entries if limit is zero.
+func readAuditLog(p string, limit int) ([]*pb.AuditEntry, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []*pb.AuditEntry
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		var e auditEntry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			continue // skip lines torn by a crash.
+		}
+
+		entries = append(entries, &pb.AuditEntry{
+			TimeUnix:  e.Time.Unix(),
+			Method:    e.Method,
+			Uid:       int64(e.UID),
+			Pid:       int64(e.PID),
+			VaultPath: e.Vault,
+			Result:    e.Result,
+		})
+
+		if limit > 0 && len(entries) > limit {
+			entries = entries[1:]
+		}
+	}
+
+	return entries, s.Err()
+}
@@ -0,0 +1,125 @@
+package vaultdaemon
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
+
+	gocmp "github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestAuditLog(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	p := filepath.Join(t.TempDir(), "state", "vltd-audit.log")
+
+	a, err := openAuditLog(p, logger)
+	if err != nil {
+		t.Fatalf("open audit log: %v", err)
+	}
+	defer func() { _ = a.Close() }()
+
+	a.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: peerAddr{Addr: &net.UnixAddr{Name: "@", Net: "unix"}, cred: peerCredentials{uid: 1000, pid: 42}},
+	})
+
+	requests := []struct {
+		method string
+		req    any
+		err    error
+	}{
+		{method: "Login", req: &pb.LoginRequest{VaultPath: "/a"}},
+		{method: "GetSessionKey", req: &pb.SessionRequest{VaultPath: "/a"}},
+		{method: "Ping", req: &emptypb.Empty{}}, // not audited.
+		{method: "GetSessionKey", req: &pb.SessionRequest{VaultPath: "/b"}, err: errNoSession},
+		{method: "Health", req: &emptypb.Empty{}, err: errRateLimited},
+	}
+
+	for _, r := range requests {
+		info := &grpc.UnaryServerInfo{FullMethod: "/sessionpb.Session/" + r.method}
+		handler := func(context.Context, any) (any, error) { return nil, r.err }
+
+		if _, err := a.unaryInterceptor(ctx, r.req, info, handler); status.Code(err) != status.Code(r.err) {
+			t.Fatalf("%s: want %v, got %v", r.method, r.err, err)
+		}
+	}
+
+	want := []plainEntry{
+		{TimeUnix: 1700000000, Method: "Login", Uid: 1000, Pid: 42, VaultPath: "/a", Result: "OK"},
+		{TimeUnix: 1700000000, Method: "GetSessionKey", Uid: 1000, Pid: 42, VaultPath: "/a", Result: "OK"},
+		{TimeUnix: 1700000000, Method: "GetSessionKey", Uid: 1000, Pid: 42, VaultPath: "/b", Result: "NotFound"},
+		{TimeUnix: 1700000000, Method: "Health", Uid: 1000, Pid: 42, Result: "ResourceExhausted"},
+	}
+
+	s := newSessionServer(logger, newMetrics())
+	s.audit = a
+
+	resp, err := s.GetAuditLog(ctx, &pb.AuditLogRequest{})
+	if err != nil {
+		t.Fatalf("get audit log: %v", err)
+	}
+
+	if diff := gocmp.Diff(want, plainEntries(resp.GetEntries())); diff != "" {
+		t.Errorf("entries mismatch (-want +got):\n%s", diff)
+	}
+
+	if resp.GetPath() != p {
+		t.Errorf("want path %q, got %q", p, resp.GetPath())
+	}
+
+	resp, err = s.GetAuditLog(ctx, &pb.AuditLogRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("get audit log: %v", err)
+	}
+
+	if diff := gocmp.Diff(want[2:], plainEntries(resp.GetEntries())); diff != "" {
+		t.Errorf("limited entries mismatch (-want +got):\n%s", diff)
+	}
+
+	fi, err := os.Stat(p)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+
+	if perm := fi.Mode().Perm(); perm != auditLogPerm {
+		t.Errorf("want mode %o, got %o", auditLogPerm, perm)
+	}
+}
+
+func TestGetAuditLog_Disabled(t *testing.T) {
+	s := newSessionServer(slog.New(slog.NewTextHandler(io.Discard, nil)), newMetrics())
+
+	if _, err := s.GetAuditLog(context.Background(), &pb.AuditLogRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("want FailedPrecondition, got %v", err)
+	}
+}
+
+// plainEntry is a comparable copy of [pb.AuditEntry].
+type plainEntry struct {
+	TimeUnix          int64
+	Method            string
+	Uid, Pid          int64
+	VaultPath, Result string
+}
+
+func plainEntries(entries []*pb.AuditEntry) []plainEntry {
+	plain := make([]plainEntry, 0, len(entries))
+	for _, e := range entries {
+		plain = append(plain, plainEntry{e.GetTimeUnix(), e.GetMethod(), e.GetUid(), e.GetPid(), e.GetVaultPath(), e.GetResult()})
+	}
+
+	return plain
+}
@@ -1,11 +1,14 @@
 package vaultdaemon
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"net"
 	"os"
-	"syscall"
+	"path/filepath"
 	"time"
 
 	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
@@ -14,11 +17,23 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// handshakeTimeout bounds the version handshake performed on connect.
+const handshakeTimeout = 2 * time.Second
+
 var (
 	ErrEmptyVaultPath    = errors.New("vault path must not be empty")
 	ErrSocketUnavailable = errors.New("vault daemon socket unavailable")
+
+	// ErrSessionDenied indicates that the user declined the release of a session key
+	// of a vault requiring confirmation.
+	ErrSessionDenied = errors.New("session use denied")
+
+	// ErrIncompatibleDaemon indicates that the running daemon speaks a different
+	// session protocol version, typically because it was not restarted after an upgrade.
+	ErrIncompatibleDaemon = errors.New("incompatible vault daemon")
 )
 
 // SessionClient wraps the gRPC SessionHandlerClient and provides
@@ -31,15 +46,25 @@ type SessionClient struct {
 // NewSessionClient connects to the local vault daemon over a UNIX socket
 // and returns a SessionClient.
 //
-// It returns [ErrSocketUnavailable] if the daemon socket is missing or inaccessible.
+// It returns [ErrSocketUnavailable] if the daemon socket is missing or inaccessible,
+// and [ErrIncompatibleDaemon] if the daemon speaks a different protocol version.
 func NewSessionClient() (*SessionClient, error) {
 	if err := verifySocketSecure(socketPath, os.Getuid()); err != nil {
 		return nil, err
 	}
 
-	conn, err := grpc.NewClient("unix://"+socketPath,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	target, opts := "unix://"+socketPath, []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+
+	if isAbstract(socketPath) {
+		uid := os.Getuid()
+
+		target = "passthrough:///" + socketPath
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialAbstractSocket(ctx, addr, uid)
+		}))
+	}
+
+	conn, err := grpc.NewClient(target, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to connect: %v", ErrSocketUnavailable, err)
 	}
@@ -49,11 +74,75 @@ func NewSessionClient() (*SessionClient, error) {
 		pb:   pb.NewSessionClient(conn),
 	}
 
+	if err := c.handshake(); err != nil {
+		return nil, errors.Join(err, c.Close())
+	}
+
 	return c, nil
 }
 
+// handshake verifies that the daemon speaks the client protocol version.
+func (c *SessionClient) handshake() error {
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	info, err := c.pb.GetInfo(ctx, &emptypb.Empty{})
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("%w: daemon predates the version handshake", ErrIncompatibleDaemon)
+		}
+
+		return fmt.Errorf("%w: handshake: %v", ErrSocketUnavailable, err)
+	}
+
+	return checkCompatible(info)
+}
+
+func checkCompatible(info *pb.InfoResponse) error {
+	if info.GetProtocolVersion() != ProtocolVersion {
+		return fmt.Errorf("%w: daemon %s speaks protocol version %d, expected %d",
+			ErrIncompatibleDaemon, cmp.Or(info.GetVersion(), "(unknown version)"), info.GetProtocolVersion(), ProtocolVersion)
+	}
+
+	return nil
+}
+
+// RequestOption configures a session request.
+type RequestOption func(*requestConfig)
+
+type requestConfig struct {
+	confirmEachUse bool
+	vaultID        string
+}
+
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	c := &requestConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithConfirmEachUse requires the daemon to obtain a user confirmation
+// before each release of the session key. It applies to [SessionClient.Login] only.
+func WithConfirmEachUse(confirm bool) RequestOption {
+	return func(c *requestConfig) {
+		c.confirmEachUse = confirm
+	}
+}
+
+// WithVaultID identifies the session by the id of the vault instead of its path,
+// so that the session is kept once the vault file is moved. Daemons predating
+// vault ids ignore it, and identify the session by the path.
+func WithVaultID(id string) RequestOption {
+	return func(c *requestConfig) {
+		c.vaultID = id
+	}
+}
+
 // Login starts a new session by storing cipher data for the given vault path.
-func (c *SessionClient) Login(ctx context.Context, vaultPath string, key []byte, nonce []byte, duration time.Duration) error {
+func (c *SessionClient) Login(ctx context.Context, vaultPath string, key []byte, nonce []byte, duration time.Duration, opts ...RequestOption) error {
 	if c == nil {
 		return nil
 	}
@@ -69,15 +158,20 @@ func (c *SessionClient) Login(ctx context.Context, vaultPath string, key []byte,
 			Key:   key,
 			Nonce: nonce,
 		},
+		Stamp: vaultStamp(vaultPath),
 	}
 
+	config := newRequestConfig(opts)
+	in.ConfirmEachUse = config.confirmEachUse
+	in.VaultId = config.vaultID
+
 	_, err := c.pb.Login(ctx, in)
 
 	return err
 }
 
 // Logout requests the daemon to clear the session for the given vault path.
-func (c *SessionClient) Logout(ctx context.Context, vaultPath string) error {
+func (c *SessionClient) Logout(ctx context.Context, vaultPath string, opts ...RequestOption) error {
 	if c == nil {
 		return nil
 	}
@@ -88,6 +182,7 @@ func (c *SessionClient) Logout(ctx context.Context, vaultPath string) error {
 
 	in := &pb.SessionRequest{
 		VaultPath: vaultPath,
+		VaultId:   newRequestConfig(opts).vaultID,
 	}
 
 	_, err := c.pb.Logout(ctx, in)
@@ -95,7 +190,26 @@ func (c *SessionClient) Logout(ctx context.Context, vaultPath string) error {
 	return err
 }
 
-func (c *SessionClient) UpdateSession(ctx context.Context, vaultPath string, nonce []byte) error {
+// LogoutAll requests the daemon to clear all vault sessions,
+// returning the number of sessions cleared.
+func (c *SessionClient) LogoutAll(ctx context.Context) (int, error) {
+	if c == nil {
+		return 0, nil
+	}
+
+	resp, err := c.pb.LogoutAll(ctx, &emptypb.Empty{})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(resp.GetSessions()), nil
+}
+
+// UpdateSession updates the nonce of the session of the vault at vaultPath,
+// and the stamp of the vault file, after vlt wrote it. A nil nonce keeps the
+// nonce of the session, e.g., after a vacuum rewrote the file without resealing
+// the vault, which would otherwise end the session as if the file was replaced.
+func (c *SessionClient) UpdateSession(ctx context.Context, vaultPath string, nonce []byte, opts ...RequestOption) error {
 	if c == nil {
 		return nil
 	}
@@ -107,6 +221,8 @@ func (c *SessionClient) UpdateSession(ctx context.Context, vaultPath string, non
 	in := &pb.UpdateRequest{
 		VaultPath: vaultPath,
 		Nonce:     nonce,
+		Stamp:     vaultStamp(vaultPath),
+		VaultId:   newRequestConfig(opts).vaultID,
 	}
 
 	_, err := c.pb.UpdateSession(ctx, in)
@@ -122,7 +238,7 @@ func (c *SessionClient) UpdateSession(ctx context.Context, vaultPath string, non
 }
 
 // GetSessionKey retrieves the session key and nonce for the given vault path.
-func (c *SessionClient) GetSessionKey(ctx context.Context, vaultPath string) (key []byte, nonce []byte, _ error) {
+func (c *SessionClient) GetSessionKey(ctx context.Context, vaultPath string, opts ...RequestOption) (key []byte, nonce []byte, _ error) {
 	if c == nil {
 		return nil, nil, nil
 	}
@@ -133,52 +249,157 @@ func (c *SessionClient) GetSessionKey(ctx context.Context, vaultPath string) (ke
 
 	in := &pb.SessionRequest{
 		VaultPath: vaultPath,
+		Stamp:     vaultStamp(vaultPath),
+		VaultId:   newRequestConfig(opts).vaultID,
 	}
 
 	vaultKey, err := c.pb.GetSessionKey(ctx, in)
 	if err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			return nil, nil, fmt.Errorf("%w: %s", ErrSessionDenied, vaultPath)
+		}
+
 		return nil, nil, err
 	}
 
 	return vaultKey.GetKey(), vaultKey.GetNonce(), nil
 }
 
-// Close safely shuts down the gRPC connection.
-// No-op if the client or connection is nil.
-func (c *SessionClient) Close() error {
-	if c == nil || c.conn == nil {
-		return nil
+// Health retrieves the daemon status and request counters.
+func (c *SessionClient) Health(ctx context.Context) (*pb.HealthResponse, error) {
+	if c == nil {
+		return nil, ErrSocketUnavailable
 	}
 
-	return c.conn.Close()
+	return c.pb.Health(ctx, &emptypb.Empty{})
 }
 
-func verifySocketSecure(path string, uid int) (retErr error) {
-	fi, err := os.Stat(path)
+// Info retrieves the daemon version and protocol version.
+func (c *SessionClient) Info(ctx context.Context) (*pb.InfoResponse, error) {
+	if c == nil {
+		return nil, ErrSocketUnavailable
+	}
+
+	return c.pb.GetInfo(ctx, &emptypb.Empty{})
+}
+
+// Schedule retrieves the scheduled jobs of the daemon and their last results.
+func (c *SessionClient) Schedule(ctx context.Context) ([]*pb.JobStatus, error) {
+	if c == nil {
+		return nil, ErrSocketUnavailable
+	}
+
+	resp, err := c.pb.GetSchedule(ctx, &emptypb.Empty{})
 	if err != nil {
-		return fmt.Errorf("%w: could not stat socket: %w", ErrSocketUnavailable, err)
+		if status.Code(err) == codes.Unimplemented {
+			return nil, fmt.Errorf("%w: daemon predates scheduled jobs", ErrIncompatibleDaemon)
+		}
+
+		return nil, err
 	}
 
-	stat, ok := fi.Sys().(*syscall.Stat_t)
-	if !ok {
-		return errors.New("socket verify: unexpected file stat type")
+	return resp.GetJobs(), nil
+}
+
+// AuditLog retrieves the last limit entries of the daemon audit log, oldest first,
+// or all entries if limit is zero, and the path of the audit log file.
+func (c *SessionClient) AuditLog(ctx context.Context, limit int) ([]*pb.AuditEntry, string, error) {
+	if c == nil {
+		return nil, "", ErrSocketUnavailable
 	}
 
-	if int(stat.Uid) != uid {
-		return fmt.Errorf("socket verify: unexpected socket owner uid: got %d, want %d", stat.Uid, uid)
+	resp, err := c.pb.GetAuditLog(ctx, &pb.AuditLogRequest{Limit: int64(limit)})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unimplemented:
+			return nil, "", fmt.Errorf("%w: daemon predates the audit log", ErrIncompatibleDaemon)
+		case codes.FailedPrecondition:
+			return nil, "", fmt.Errorf("%s, see vltd --audit-log", status.Convert(err).Message())
+		default:
+			return nil, "", err
+		}
 	}
 
-	if (fi.Mode() & os.ModeSymlink) != 0 {
-		return fmt.Errorf("socket verify: refusing to follow symlink: %s", path)
+	return resp.GetEntries(), resp.GetPath(), nil
+}
+
+// MoveSession moves the session of the vault at vaultPath to newVaultPath,
+// the path the vault file was moved to, so that the session is kept.
+func (c *SessionClient) MoveSession(ctx context.Context, vaultPath string, newVaultPath string, opts ...RequestOption) error {
+	if c == nil {
+		return ErrSocketUnavailable
 	}
 
-	if (fi.Mode() & os.ModeSocket) == 0 {
-		return fmt.Errorf("socket verify: file is not a socket: %s", path)
+	in := &pb.MoveRequest{
+		VaultPath:    vaultPath,
+		VaultId:      newRequestConfig(opts).vaultID,
+		NewVaultPath: newVaultPath,
+		Stamp:        vaultStamp(newVaultPath),
 	}
 
-	if fi.Mode().Perm() != socketPerm {
-		return fmt.Errorf("socket verify: socket file has insecure permissions: %v", fi.Mode().Perm())
+	if _, err := c.pb.MoveSession(ctx, in); err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("%w: daemon predates moving sessions", ErrIncompatibleDaemon)
+		}
+
+		return err
 	}
 
 	return nil
 }
+
+// ShredFile requests the daemon to overwrite the regular file at path with zeros
+// and remove it once ttl passes, rounded up to whole seconds. The schedule is lost
+// if the daemon is stopped before, the daemon shreds the file on shutdown instead.
+func (c *SessionClient) ShredFile(ctx context.Context, path string, ttl time.Duration) error {
+	if c == nil {
+		return ErrSocketUnavailable
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.pb.ShredFile(ctx, &pb.ShredRequest{Path: abs, DelaySeconds: delaySeconds(ttl)})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unimplemented:
+			return fmt.Errorf("%w: daemon predates file shredding", ErrIncompatibleDaemon)
+		case codes.FailedPrecondition, codes.InvalidArgument:
+			return errors.New(status.Convert(err).Message())
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// delaySeconds returns d in whole seconds, rounded up so that
+// a file is never shredded before its ttl passes.
+func delaySeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// Close safely shuts down the gRPC connection.
+// No-op if the client or connection is nil.
+func (c *SessionClient) Close() error {
+	if c == nil || c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+// vaultStamp returns the stamp of the vault file at path, sent along session requests
+// so that the daemon drops sessions of vault files changed outside vlt.
+// It is nil if the file cannot be read, the session is then kept.
+func vaultStamp(path string) *pb.VaultStamp {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	return &pb.VaultStamp{ModTimeUnixNano: fi.ModTime().UnixNano(), Size: fi.Size()}
+}
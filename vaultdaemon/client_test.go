@@ -0,0 +1,228 @@
+package vaultdaemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// startDaemon runs the daemon with the given options on a temporary socket until the test ends.
+func startDaemon(t *testing.T, opts ...Option) {
+	t.Helper()
+	startDaemonAt(t, filepath.Join(t.TempDir(), "vlt.sock"), opts...)
+}
+
+// startDaemonAt runs the daemon with the given options on the socket at path until the test ends.
+func startDaemonAt(t *testing.T, path string, opts ...Option) {
+	t.Helper()
+
+	orig := socketPath
+	SetSocketPath(path)
+	t.Cleanup(func() { SetSocketPath(orig) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	opts = append([]Option{WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))), WithAuditLog("")}, opts...)
+
+	go func() {
+		done <- Run(ctx, opts...)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if verifySocketSecure(socketPath, os.Getuid()) == nil {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("daemon socket not created")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	ctx := context.Background()
+
+	startDaemon(t, WithVersion("v1.2.3"))
+
+	c, err := NewSessionClient()
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	info, err := c.pb.GetInfo(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("get info: %v", err)
+	}
+
+	if info.GetVersion() != "v1.2.3" || info.GetProtocolVersion() != ProtocolVersion {
+		t.Errorf("unexpected daemon info: %v", info)
+	}
+
+	err = checkCompatible(&pb.InfoResponse{Version: "v0.0.1", ProtocolVersion: ProtocolVersion + 1})
+	if !errors.Is(err, ErrIncompatibleDaemon) {
+		t.Errorf("want ErrIncompatibleDaemon, got %v", err)
+	}
+}
+
+func TestAbstractSocket(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("abstract sockets are only supported on linux")
+	}
+
+	ctx := context.Background()
+
+	startDaemonAt(t, fmt.Sprintf("@vlt-test-%d", time.Now().UnixNano()), WithVersion("v1.2.3"))
+
+	c, err := NewSessionClient()
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if err := c.Login(ctx, "/vault", []byte("key"), []byte("nonce"), time.Minute); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	if key, _, err := c.GetSessionKey(ctx, "/vault"); err != nil || string(key) != "key" {
+		t.Fatalf("want the session key, got %q: %v", key, err)
+	}
+
+	// the name of an abstract socket is available to any process, the daemon uid is verified.
+	conn, err := dialAbstractSocket(ctx, socketPath, os.Getuid())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	_ = conn.Close() //nolint:wsl_v5
+
+	if _, err := dialAbstractSocket(ctx, socketPath, os.Getuid()+1); err == nil || !strings.Contains(err.Error(), "unexpected daemon uid") {
+		t.Errorf("want an unexpected daemon uid error, got %v", err)
+	}
+}
+
+func TestListenSocketDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket directories have no unix permissions on windows")
+	}
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	listen := func(dir string) error {
+		lis, err := Listen(ctx, filepath.Join(dir, "vlt.sock"), logger)
+		if err != nil {
+			return err
+		}
+
+		return lis.Close()
+	}
+
+	t.Run("missing directory is created", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "vlt-1000")
+
+		if err := listen(dir); err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+
+		fi, err := os.Stat(dir)
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+
+		if perm := fi.Mode().Perm(); perm != socketDirPerm {
+			t.Errorf("want mode %v, got %v", os.FileMode(socketDirPerm), perm)
+		}
+	})
+
+	t.Run("world-writable directory", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.Chmod(dir, 0o777|os.ModeSticky); err != nil {
+			t.Fatalf("chmod: %v", err)
+		}
+
+		if err := listen(dir); err == nil || !strings.Contains(err.Error(), "world-writable") {
+			t.Errorf("want a world-writable error, got %v", err)
+		}
+	})
+
+	t.Run("group-accessible fallback directory", func(t *testing.T) {
+		tmp := t.TempDir()
+		t.Setenv("TMPDIR", tmp)
+
+		dir := filepath.Join(tmp, fmt.Sprintf("vlt-%d", os.Getuid()))
+		if err := os.Mkdir(dir, 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+
+		if err := os.Chmod(dir, 0o770); err != nil {
+			t.Fatalf("chmod: %v", err)
+		}
+
+		if err := listen(dir); err == nil || !strings.Contains(err.Error(), "want -rwx------") {
+			t.Errorf("want a directory mode error, got %v", err)
+		}
+	})
+
+	t.Run("world-writable parent directory", func(t *testing.T) {
+		parent := t.TempDir()
+		if err := os.Chmod(parent, 0o777); err != nil {
+			t.Fatalf("chmod: %v", err)
+		}
+
+		if err := listen(filepath.Join(parent, "vlt-1000")); err == nil || !strings.Contains(err.Error(), "world-writable") {
+			t.Errorf("want a world-writable error, got %v", err)
+		}
+	})
+
+	t.Run("sticky world-writable parent directory", func(t *testing.T) {
+		parent := t.TempDir()
+		if err := os.Chmod(parent, 0o777|os.ModeSticky); err != nil {
+			t.Fatalf("chmod: %v", err)
+		}
+
+		if err := listen(filepath.Join(parent, "vlt-1000")); err != nil {
+			t.Errorf("listen: %v", err)
+		}
+	})
+}
+
+func TestRuntimeDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1234")
+
+	if got := RuntimeDir(); got != "/run/user/1234" {
+		t.Errorf("want $XDG_RUNTIME_DIR, got %q", got)
+	}
+
+	if runtime.GOOS == "windows" || isDir(fmt.Sprintf("/run/user/%d", os.Getuid())) {
+		return
+	}
+
+	tmp := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("TMPDIR", tmp)
+
+	if want, got := filepath.Join(tmp, fmt.Sprintf("vlt-%d", os.Getuid())), RuntimeDir(); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,57 @@
+package vaultdaemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/pinentry"
+)
+
+const (
+	// defaultConfirmProgram is the default program used to confirm session key releases.
+	defaultConfirmProgram = pinentry.DefaultProgram
+
+	// confirmTimeout bounds the time the user has to answer a confirmation prompt.
+	confirmTimeout = time.Minute
+)
+
+var (
+	// errConfirmDenied indicates that the user declined the confirmation prompt.
+	errConfirmDenied = errors.New("confirmation denied")
+
+	// errConfirmUnavailable indicates that no confirmation prompt could be shown.
+	errConfirmUnavailable = errors.New("confirmation unavailable")
+)
+
+// confirmFunc asks the user to confirm the release of the session key of the given vault.
+//
+// It returns nil if confirmed, an error wrapping [errConfirmDenied] if declined,
+// or an error wrapping [errConfirmUnavailable] if the prompt could not be shown.
+type confirmFunc func(ctx context.Context, vaultPath string) error
+
+// pinentryConfirm returns a [confirmFunc] that prompts using the given pinentry program,
+// see [pinentry.Confirm].
+func pinentryConfirm(program string) confirmFunc {
+	return func(ctx context.Context, vaultPath string) error {
+		ctx, cancel := context.WithTimeout(ctx, confirmTimeout)
+		defer cancel()
+
+		err := pinentry.Confirm(ctx, program, pinentry.Dialog{
+			Title:  "vlt",
+			Desc:   fmt.Sprintf("Allow access to the vault session of %q?", vaultPath),
+			OK:     "Allow",
+			Cancel: "Deny",
+		})
+
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, pinentry.ErrCanceled):
+			return fmt.Errorf("%w: %v", errConfirmDenied, err)
+		default:
+			return fmt.Errorf("%w: pinentry: %v", errConfirmUnavailable, err)
+		}
+	}
+}
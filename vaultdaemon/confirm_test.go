@@ -0,0 +1,93 @@
+package vaultdaemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakePinentry writes a pinentry stand-in answering CONFIRM with the given response.
+func fakePinentry(t *testing.T, confirmResponse string) string {
+	t.Helper()
+
+	script := `#!/bin/sh
+echo "OK Pleased to meet you"
+while read -r cmd rest; do
+	case "$cmd" in
+	CONFIRM) echo "# prompting"; echo "` + confirmResponse + `" ;;
+	BYE) echo "OK closing connection"; exit 0 ;;
+	*) echo "OK" ;;
+	esac
+done
+`
+
+	path := filepath.Join(t.TempDir(), "pinentry")
+	if err := os.WriteFile(path, []byte(script), 0o700); err != nil { //nolint:gosec
+		t.Fatalf("write fake pinentry: %v", err)
+	}
+
+	return path
+}
+
+func TestPinentryConfirm(t *testing.T) {
+	ctx := context.Background()
+
+	if err := pinentryConfirm(fakePinentry(t, "OK"))(ctx, "/vault"); err != nil {
+		t.Errorf("confirmed: unexpected error: %v", err)
+	}
+
+	err := pinentryConfirm(fakePinentry(t, "ERR 83886179 Operation cancelled <Pinentry>"))(ctx, "/vault")
+	if !errors.Is(err, errConfirmDenied) {
+		t.Errorf("denied: want errConfirmDenied, got %v", err)
+	}
+
+	err = pinentryConfirm(filepath.Join(t.TempDir(), "missing"))(ctx, "/vault")
+	if !errors.Is(err, errConfirmUnavailable) {
+		t.Errorf("missing program: want errConfirmUnavailable, got %v", err)
+	}
+}
+
+func TestGetSessionKey_ConfirmEachUse(t *testing.T) {
+	ctx := context.Background()
+	s := newSessionServer(slog.New(slog.NewTextHandler(io.Discard, nil)), newMetrics())
+	defer s.stopAll()
+
+	var confirmErr error
+
+	prompts := 0
+	s.confirm = func(context.Context, string) error {
+		prompts++
+		return confirmErr
+	}
+
+	for path, confirm := range map[string]bool{"/plain": false, "/sensitive": true} {
+		req := &pb.LoginRequest{VaultPath: path, DurationSeconds: 60, VaultKey: &pb.VaultKey{Key: []byte("k")}, ConfirmEachUse: confirm}
+		if _, err := s.Login(ctx, req); err != nil {
+			t.Fatalf("login %s: %v", path, err)
+		}
+	}
+
+	if _, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/plain"}); err != nil || prompts != 0 {
+		t.Errorf("plain session: err %v, prompts %d", err, prompts)
+	}
+
+	if _, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/sensitive"}); err != nil || prompts != 1 {
+		t.Errorf("confirmed session: err %v, prompts %d", err, prompts)
+	}
+
+	confirmErr = errConfirmDenied
+
+	_, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/sensitive"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("denied session: want PermissionDenied, got %v", err)
+	}
+}
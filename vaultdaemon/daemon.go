@@ -2,94 +2,338 @@ package vaultdaemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"syscall"
 
 	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
 
-	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
 )
 
 // socketPerm is the file permission mode for the unix domain socket.
 const socketPerm = 0o600
 
+// ProtocolVersion is the version of the session protocol spoken between vlt and vltd.
+//
+// It must be incremented on any incompatible change to the session service,
+// so that clients can detect a daemon left running across an upgrade.
+//
+// Version 2 added session confirmation; older daemons would silently ignore it.
+const ProtocolVersion = 2
+
+// socketDirPerm is the file permission mode for the socket directory, if created by the daemon.
+const socketDirPerm = 0o700
+
 // socketPath is the path of the unix domain socket
 // used by the daemon.
-var socketPath = fmt.Sprintf("/run/user/%d/vlt.sock", os.Getuid())
+var socketPath = filepath.Join(RuntimeDir(), "vlt.sock")
+
+// errAbstractUnsupported is returned for abstract socket paths outside of Linux.
+var errAbstractUnsupported = fmt.Errorf("abstract unix sockets are only supported on linux, not on %s", runtime.GOOS)
+
+// SocketPath returns the path of the unix domain socket of the daemon.
+func SocketPath() string { return socketPath }
+
+// RuntimeDir returns the per-user directory of the daemon socket, i.e.,
+// $XDG_RUNTIME_DIR, or /run/user/<uid> if it exists.
+//
+// Without either, e.g., in ssh sessions on systems without systemd, it is
+// <tmp>/vlt-<uid>, created with mode 0700 by the daemon on listen.
+func RuntimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); filepath.IsAbs(dir) {
+		return dir
+	}
+
+	if dir := fmt.Sprintf("/run/user/%d", os.Getuid()); isDir(dir) {
+		return dir
+	}
+
+	return fallbackRuntimeDir(os.Getuid())
+}
+
+// fallbackRuntimeDir returns the private runtime directory of uid under the
+// temporary directory, see [RuntimeDir].
+func fallbackRuntimeDir(uid int) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("vlt-%d", uid))
+}
+
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+// SetSocketPath sets the path of the unix domain socket the daemon listens on,
+// and its clients connect to, by default <runtime dir>/vlt.sock, see [RuntimeDir].
+//
+// Paths starting with '@' name sockets in the Linux abstract namespace, which
+// have no file, e.g., for containers sharing the network namespace of the host
+// but not its /run directory. See [verifySocketSecure] for how they are secured.
+func SetSocketPath(path string) { socketPath = path }
+
+// isAbstract reports whether path names a socket in the Linux abstract namespace.
+func isAbstract(path string) bool { return strings.HasPrefix(path, "@") }
+
+type config struct {
+	logger         *slog.Logger
+	metricsAddr    string
+	version        string
+	confirmProgram string
+	jobs           []Job
+	auditLogPath   string
+	rateLimit      float64
+	rateBurst      int
+}
+
+// Option configures the daemon.
+type Option func(*config)
+
+// WithLogger sets the logger used for daemon events.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// WithVersion sets the daemon version reported to clients.
+func WithVersion(version string) Option {
+	return func(c *config) {
+		c.version = version
+	}
+}
+
+// WithConfirmProgram sets the pinentry program used to confirm
+// the release of session keys of vaults that require confirmation.
+func WithConfirmProgram(program string) Option {
+	return func(c *config) {
+		c.confirmProgram = program
+	}
+}
+
+// WithAuditLog appends a record of each session request to the file at path,
+// i.e., which client uid and pid requested which vault's key and when.
+func WithAuditLog(path string) Option {
+	return func(c *config) {
+		c.auditLogPath = path
+	}
+}
+
+// WithRateLimit limits the requests of each client uid to rate per second,
+// allowing bursts of up to burst requests. A zero rate disables the limit.
+//
+// The default is [DefaultRateLimit] requests per second, bursting to [DefaultRateBurst].
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *config) {
+		c.rateLimit = rate
+		c.rateBurst = burst
+	}
+}
+
+// WithMetricsAddr enables serving metrics in the Prometheus text format
+// at /metrics on the given loopback address, in host:port form.
+func WithMetricsAddr(addr string) Option {
+	return func(c *config) {
+		c.metricsAddr = addr
+	}
+}
 
 // Run starts the vltd daemon and serves grpc over a unix domain socket
 // that only allows connections from the same user that runs the daemon.
-func Run(ctx context.Context) error {
-	log.SetPrefix("[vltd] ")
+func Run(ctx context.Context, opts ...Option) error {
+	c := &config{
+		logger:         slog.Default(),
+		confirmProgram: defaultConfirmProgram,
+		rateLimit:      DefaultRateLimit,
+		rateBurst:      DefaultRateBurst,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
 
-	log.Print("daemon started")
+	logger := c.logger
 
-	if socketInUse(ctx, socketPath) {
-		return fmt.Errorf("socket already in use: %v", socketPath)
+	if len(c.metricsAddr) > 0 {
+		if err := verifyLoopbackAddr(c.metricsAddr); err != nil {
+			return err
+		}
 	}
 
-	_ = os.Remove(socketPath) // remove stale socket
+	if err := validateJobs(c.jobs); err != nil {
+		return err
+	}
 
-	var lc net.ListenConfig
+	if c.rateLimit < 0 {
+		return fmt.Errorf("invalid rate limit: %v", c.rateLimit)
+	}
+
+	if !filepath.IsAbs(socketPath) && !isAbstract(socketPath) {
+		return fmt.Errorf("invalid socket path %q: must be absolute, or start with '@' for an abstract socket", socketPath)
+	}
 
-	socket, err := lc.Listen(ctx, "unix", socketPath)
+	logger.Info("daemon started")
+
+	lis, err := Listen(ctx, socketPath, logger)
 	if err != nil {
-		panic(fmt.Errorf("unix socket listen: %w", err))
+		return err
 	}
 	defer func() { //nolint:wsl_v5
-		_ = socket.Close()
-		_ = os.Remove(socketPath)
+		_ = lis.Close()
 	}()
 
-	if err := os.Chmod(socketPath, socketPerm); err != nil {
-		panic(fmt.Errorf("unix socket chmod: %w", err))
-	}
-
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	srv := grpc.NewServer()
-	handler := newSessionServer()
+	m := newMetrics()
+	interceptors := []grpc.UnaryServerInterceptor{m.unaryInterceptor}
 
-	pb.RegisterSessionServer(srv, handler)
+	var audit *auditLog
+
+	if len(c.auditLogPath) > 0 {
+		audit, err = openAuditLog(c.auditLogPath, logger)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = audit.Close() }()
+
+		interceptors = append(interceptors, audit.unaryInterceptor)
+	}
 
-	lis := &secureUnixListener{
-		Listener:   socket,
-		allowedUID: os.Getuid(),
+	if c.rateLimit > 0 {
+		interceptors = append(interceptors, newRateLimiter(logger, c.rateLimit, c.rateBurst).unaryInterceptor)
 	}
 
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	handler := newSessionServer(logger, m)
+	handler.audit = audit
+	handler.version = c.version
+	handler.confirm = pinentryConfirm(c.confirmProgram)
+	handler.scheduler = newScheduler(logger, c.jobs)
+
+	pb.RegisterSessionServer(srv, handler)
+
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
 
-		log.Printf("server listening at: %v", socket.Addr())
+		logger.Info("server listening", "addr", lis.Addr().String())
 
 		if err := srv.Serve(lis); err != nil {
-			log.Printf("grpc server stopped with error: %v", err)
+			logger.Error("grpc server stopped", "err", err)
 			return
 		}
 
-		log.Print("grpc server stopped")
+		logger.Info("grpc server stopped")
+	}()
+
+	metricsDone := make(chan struct{})
+	go func() {
+		defer close(metricsDone)
+
+		if len(c.metricsAddr) == 0 {
+			return
+		}
+
+		if err := serveMetrics(ctx, c.metricsAddr, handler, logger); err != nil {
+			logger.Error("metrics server stopped", "err", err)
+		}
+	}()
+
+	scheduleDone := make(chan struct{})
+	go func() {
+		defer close(scheduleDone)
+		handler.scheduler.run(ctx)
 	}()
 
 	<-ctx.Done()
 
-	log.Print("received shutdown signal: shutting down...")
+	logger.Info("received shutdown signal: shutting down...")
 
 	srv.Stop()
 	handler.stopAll()
 
 	<-done
-	log.Println("shutdown complete")
+	<-metricsDone
+	<-scheduleDone
+	logger.Info("shutdown complete")
 
 	return ctx.Err()
 }
 
+// Listen listens on the unix domain socket at path, with mode 0600,
+// only accepting connections from processes of the current user.
+// A stale socket left at path is replaced, one in use is an error.
+//
+// The socket file is removed once the listener is closed.
+func Listen(ctx context.Context, path string, logger *slog.Logger) (net.Listener, error) {
+	return ListenUIDs(ctx, path, logger, os.Getuid())
+}
+
+// ListenUIDs is like [Listen], but accepts connections from processes
+// of any of the given uids, e.g., of root, running a CSI driver.
+//
+// A missing socket directory is created with mode 0700. The socket directory,
+// and the directories above it, must be owned by the current user or root, and
+// must not be writable by others, see [verifySocketDir].
+//
+// Abstract sockets, see [SetSocketPath], have neither a file to replace nor a mode,
+// only the uid check of connecting clients applies.
+func ListenUIDs(ctx context.Context, path string, logger *slog.Logger, uids ...int) (net.Listener, error) {
+	abstract := isAbstract(path)
+	if abstract && runtime.GOOS != "linux" {
+		return nil, errAbstractUnsupported
+	}
+
+	if socketInUse(ctx, path) {
+		return nil, fmt.Errorf("socket already in use: %v", path)
+	}
+
+	if !abstract {
+		if err := prepareSocketDir(filepath.Dir(path)); err != nil {
+			return nil, err
+		}
+
+		_ = os.Remove(path) // remove stale socket
+	}
+
+	var lc net.ListenConfig
+
+	socket, err := lc.Listen(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("unix socket listen: %w", err)
+	}
+
+	if !abstract {
+		if err := os.Chmod(path, socketPerm); err != nil {
+			_ = socket.Close()
+			return nil, fmt.Errorf("unix socket chmod: %w", err)
+		}
+	}
+
+	return &secureUnixListener{
+		Listener:    socket,
+		allowedUIDs: uids,
+		logger:      logger,
+	}, nil
+}
+
+// prepareSocketDir creates the socket directory dir if missing, and verifies it is secure.
+func prepareSocketDir(dir string) error {
+	if err := os.Mkdir(dir, socketDirPerm); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("socket directory: %w", err)
+	}
+
+	return verifySocketDir(dir, os.Getuid())
+}
+
 func socketInUse(ctx context.Context, path string) bool {
 	var d net.Dialer
 
@@ -103,14 +347,17 @@ func socketInUse(ctx context.Context, path string) bool {
 }
 
 // secureUnixListener wraps a unix [net.Listener] and only accepts connections
-// from clients matching the allowed uid.
+// from clients matching one of the allowed uids.
 type secureUnixListener struct {
 	net.Listener
-	allowedUID int
+	allowedUIDs []int
+	logger      *slog.Logger
 }
 
-// Accept only returns the next connection if the client's uid matches [secureUnixListener.allowedUID].
+// Accept only returns the next connection if the client's uid is one of [secureUnixListener.allowedUIDs].
 // Other connections are closed and skipped.
+//
+// The remote address of returned connections carries the client credentials, see [peerFromContext].
 func (l *secureUnixListener) Accept() (net.Conn, error) {
 	for {
 		conn, err := l.Listener.Accept()
@@ -118,58 +365,22 @@ func (l *secureUnixListener) Accept() (net.Conn, error) {
 			return nil, err
 		}
 
-		ucred, err := getCred(conn)
+		cred, err := peerCred(conn)
 		if err != nil {
-			log.Printf("uid check failed: %v", err)
+			l.logger.Warn("uid check failed", "err", err)
 			_ = conn.Close() //nolint:wsl_v5
 
 			continue
 		}
 
-		if int(ucred.Uid) != l.allowedUID {
-			log.Printf("connection from disallowed uid: %d", ucred.Uid)
+		if !slices.Contains(l.allowedUIDs, cred.uid) {
+			l.logger.Warn("connection from disallowed uid", "uid", cred.uid, "pid", cred.pid)
 			_ = conn.Close() //nolint:wsl_v5
 
 			continue
 		}
 
 		// connection allowed
-		return conn, nil
-	}
-}
-
-// getCred returns the credentials from the remote end of a unix socket.
-func getCred(conn net.Conn) (*unix.Ucred, error) {
-	unixConn, ok := conn.(*net.UnixConn)
-	if !ok {
-		return nil, fmt.Errorf("connection is not a *net.UnixConn: got %T", conn)
-	}
-
-	rawConn, err := unixConn.SyscallConn()
-	if err != nil {
-		return nil, err
-	}
-
-	var (
-		ucred    *unix.Ucred
-		ucredErr error
-	)
-
-	err = rawConn.Control(func(fd uintptr) {
-		// Getsockopt syscall to retrieve peer credentials (uid, gid, pid)
-		// from the remote end of the connected unix socket
-		//
-		// see SO_PEERCRED:
-		// https://man7.org/linux/man-pages/man7/unix.7.html
-		ucred, ucredErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
-	})
-	if err != nil {
-		return nil, err
+		return &peerConn{Conn: conn, addr: peerAddr{Addr: conn.RemoteAddr(), cred: cred}}, nil
 	}
-
-	if ucredErr != nil {
-		return nil, ucredErr
-	}
-
-	return ucred, nil
 }
@@ -0,0 +1,180 @@
+package vaultdaemon
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
+
+	"google.golang.org/grpc"
+)
+
+// ErrNonLoopbackAddr is returned when the metrics listener address
+// is not a loopback address.
+var ErrNonLoopbackAddr = errors.New("metrics address must be a loopback address")
+
+// metrics collects the daemon request counters.
+type metrics struct {
+	start time.Time
+
+	mu       sync.Mutex
+	requests map[string]*pb.RequestCounter
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		start:    time.Now(),
+		requests: make(map[string]*pb.RequestCounter),
+	}
+}
+
+// unaryInterceptor counts the handled requests and errors per rpc method.
+func (m *metrics) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+
+	method := path.Base(info.FullMethod)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.requests[method]
+	if !ok {
+		c = &pb.RequestCounter{Method: method}
+		m.requests[method] = c
+	}
+
+	c.Total++
+
+	if err != nil {
+		c.Errors++
+	}
+
+	return resp, err
+}
+
+func (m *metrics) uptime() time.Duration {
+	return time.Since(m.start)
+}
+
+// counters returns a copy of the request counters sorted by method.
+func (m *metrics) counters() []*pb.RequestCounter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters := make([]*pb.RequestCounter, 0, len(m.requests))
+	for _, c := range m.requests {
+		counters = append(counters, &pb.RequestCounter{Method: c.Method, Total: c.Total, Errors: c.Errors})
+	}
+
+	slices.SortFunc(counters, func(a, b *pb.RequestCounter) int { return cmp.Compare(a.Method, b.Method) })
+
+	return counters
+}
+
+// writePrometheus writes the daemon health in the Prometheus text exposition format.
+func writePrometheus(w io.Writer, health *pb.HealthResponse) error {
+	var err error
+
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("# HELP vltd_uptime_seconds Time since the daemon started.\n")
+	printf("# TYPE vltd_uptime_seconds gauge\n")
+	printf("vltd_uptime_seconds %d\n", health.GetUptimeSeconds())
+
+	printf("# HELP vltd_active_sessions Number of active vault sessions.\n")
+	printf("# TYPE vltd_active_sessions gauge\n")
+	printf("vltd_active_sessions %d\n", health.GetActiveSessions())
+
+	printf("# HELP vltd_requests_total Number of handled requests by method.\n")
+	printf("# TYPE vltd_requests_total counter\n")
+
+	for _, c := range health.GetRequests() {
+		printf("vltd_requests_total{method=%q} %d\n", c.GetMethod(), c.GetTotal())
+	}
+
+	printf("# HELP vltd_request_errors_total Number of requests that returned an error by method.\n")
+	printf("# TYPE vltd_request_errors_total counter\n")
+
+	for _, c := range health.GetRequests() {
+		printf("vltd_request_errors_total{method=%q} %d\n", c.GetMethod(), c.GetErrors())
+	}
+
+	return err
+}
+
+// verifyLoopbackAddr verifies that addr, in host:port form,
+// refers to a loopback address, so metrics are not exposed on the network.
+func verifyLoopbackAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid metrics address %q: %w", addr, err)
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrNonLoopbackAddr, addr)
+}
+
+// serveMetrics serves the daemon health at /metrics on the given loopback address
+// until the context is canceled.
+func serveMetrics(ctx context.Context, addr string, s *sessionServer, logger *slog.Logger) error {
+	if err := verifyLoopbackAddr(addr); err != nil {
+		return err
+	}
+
+	var lc net.ListenConfig
+
+	lis, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("metrics listen: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		health, _ := s.Health(r.Context(), nil)
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		if err := writePrometheus(w, health); err != nil {
+			logger.Warn("write metrics", "err", err)
+		}
+	})
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	logger.Info("metrics listening", "addr", lis.Addr().String())
+
+	if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics serve: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package vaultdaemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
+
+	"google.golang.org/grpc"
+)
+
+func TestHealthMetrics(t *testing.T) {
+	m := newMetrics()
+	s := newSessionServer(slog.New(slog.NewTextHandler(io.Discard, nil)), m)
+
+	call := func(method string, err error) {
+		info := &grpc.UnaryServerInfo{FullMethod: "/sessionpb.Session/" + method}
+		_, _ = m.unaryInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+			return nil, err
+		})
+	}
+
+	call("Login", nil)
+	call("GetSessionKey", nil)
+	call("GetSessionKey", errors.New("not found"))
+
+	if _, err := s.Login(context.Background(), &pb.LoginRequest{VaultPath: "/vault", DurationSeconds: 60}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	defer s.stopAll()
+
+	health, err := s.Health(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("health: %v", err)
+	}
+
+	if got := health.GetActiveSessions(); got != 1 {
+		t.Errorf("want 1 active session, got %d", got)
+	}
+
+	var sb strings.Builder
+	if err := writePrometheus(&sb, health); err != nil {
+		t.Fatalf("write metrics: %v", err)
+	}
+
+	for _, want := range []string{
+		"vltd_active_sessions 1\n",
+		`vltd_requests_total{method="GetSessionKey"} 2` + "\n",
+		`vltd_requests_total{method="Login"} 1` + "\n",
+		`vltd_request_errors_total{method="GetSessionKey"} 1` + "\n",
+		`vltd_request_errors_total{method="Login"} 0` + "\n",
+	} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("missing %q in metrics:\n%s", want, sb.String())
+		}
+	}
+}
+
+func TestVerifyLoopbackAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{addr: "127.0.0.1:9464"},
+		{addr: "[::1]:9464"},
+		{addr: "localhost:9464"},
+		{addr: "0.0.0.0:9464", wantErr: true},
+		{addr: ":9464", wantErr: true},
+		{addr: "192.168.1.10:9464", wantErr: true},
+		{addr: "127.0.0.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := verifyLoopbackAddr(tt.addr); (err != nil) != tt.wantErr {
+			t.Errorf("verifyLoopbackAddr(%q): want error %v, got %v", tt.addr, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package vaultdaemon
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/peer"
+)
+
+// peerCredentials identifies the process at the remote end of a unix socket.
+type peerCredentials struct {
+	uid, pid int
+}
+
+// peerAddr is the remote address of connections accepted by [secureUnixListener],
+// carrying the credentials of the client process to the rpc handlers, see [peerFromContext].
+type peerAddr struct {
+	net.Addr
+	cred peerCredentials
+}
+
+// peerConn is a connection whose remote address is a [peerAddr].
+type peerConn struct {
+	net.Conn
+	addr peerAddr
+}
+
+func (c *peerConn) RemoteAddr() net.Addr { return c.addr }
+
+// peerFromContext returns the credentials of the client of an rpc,
+// if it connected through a [secureUnixListener].
+func peerFromContext(ctx context.Context) (peerCredentials, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return peerCredentials{}, false
+	}
+
+	addr, ok := p.Addr.(peerAddr)
+	if !ok {
+		return peerCredentials{}, false
+	}
+
+	return addr.cred, true
+}
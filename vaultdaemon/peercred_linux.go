@@ -0,0 +1,54 @@
+package vaultdaemon
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerCred returns the credentials of the process at the remote end of a unix socket.
+func peerCred(conn net.Conn) (peerCredentials, error) {
+	ucred, err := getCred(conn)
+	if err != nil {
+		return peerCredentials{}, err
+	}
+
+	return peerCredentials{uid: int(ucred.Uid), pid: int(ucred.Pid)}, nil
+}
+
+// getCred returns the credentials from the remote end of a unix socket.
+func getCred(conn net.Conn) (*unix.Ucred, error) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return nil, fmt.Errorf("connection is not a *net.UnixConn: got %T", conn)
+	}
+
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ucred    *unix.Ucred
+		ucredErr error
+	)
+
+	err = rawConn.Control(func(fd uintptr) {
+		// Getsockopt syscall to retrieve peer credentials (uid, gid, pid)
+		// from the remote end of the connected unix socket
+		//
+		// see SO_PEERCRED:
+		// https://man7.org/linux/man-pages/man7/unix.7.html
+		ucred, ucredErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if ucredErr != nil {
+		return nil, ucredErr
+	}
+
+	return ucred, nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package vaultdaemon
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// peerCred is not supported outside of Linux, the daemon rejects all connections.
+func peerCred(net.Conn) (peerCredentials, error) {
+	return peerCredentials{}, fmt.Errorf("peer credentials: %w on %s", errors.ErrUnsupported, runtime.GOOS)
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.6
+// 	protoc-gen-go v1.36.11
 // 	protoc        v3.19.6
 // source: sessionpb/session.proto
 
@@ -81,6 +81,9 @@ type LoginRequest struct {
 	VaultPath       string                 `protobuf:"bytes,1,opt,name=vault_path,json=vaultPath,proto3" json:"vault_path,omitempty"`
 	DurationSeconds int64                  `protobuf:"varint,2,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
 	VaultKey        *VaultKey              `protobuf:"bytes,3,opt,name=vault_key,json=vaultKey,proto3" json:"vault_key,omitempty"`
+	ConfirmEachUse  bool                   `protobuf:"varint,4,opt,name=confirm_each_use,json=confirmEachUse,proto3" json:"confirm_each_use,omitempty"` // require user confirmation before each key release
+	Stamp           *VaultStamp            `protobuf:"bytes,5,opt,name=stamp,proto3" json:"stamp,omitempty"`                                            // the vault file on login, unset to not detect changes
+	VaultId         string                 `protobuf:"bytes,6,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"`                         // the vault id, identifying the session instead of the path if set
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -136,10 +139,33 @@ func (x *LoginRequest) GetVaultKey() *VaultKey {
 	return nil
 }
 
-// SessionRequest identifies a vault session by path.
+func (x *LoginRequest) GetConfirmEachUse() bool {
+	if x != nil {
+		return x.ConfirmEachUse
+	}
+	return false
+}
+
+func (x *LoginRequest) GetStamp() *VaultStamp {
+	if x != nil {
+		return x.Stamp
+	}
+	return nil
+}
+
+func (x *LoginRequest) GetVaultId() string {
+	if x != nil {
+		return x.VaultId
+	}
+	return ""
+}
+
+// SessionRequest identifies a vault session by id, or by path.
 type SessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	VaultPath     string                 `protobuf:"bytes,1,opt,name=vault_path,json=vaultPath,proto3" json:"vault_path,omitempty"`
+	Stamp         *VaultStamp            `protobuf:"bytes,2,opt,name=stamp,proto3" json:"stamp,omitempty"`                    // the current vault file, the session is dropped if it changed since
+	VaultId       string                 `protobuf:"bytes,3,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"` // the vault id, identifying the session instead of the path if set
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -181,11 +207,27 @@ func (x *SessionRequest) GetVaultPath() string {
 	return ""
 }
 
+func (x *SessionRequest) GetStamp() *VaultStamp {
+	if x != nil {
+		return x.Stamp
+	}
+	return nil
+}
+
+func (x *SessionRequest) GetVaultId() string {
+	if x != nil {
+		return x.VaultId
+	}
+	return ""
+}
+
 // UpdateRequest updates the nonce for an existing vault session.
 type UpdateRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	VaultPath     string                 `protobuf:"bytes,1,opt,name=vault_path,json=vaultPath,proto3" json:"vault_path,omitempty"`
-	Nonce         []byte                 `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"` // AES-GCM nonce
+	Nonce         []byte                 `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`                    // AES-GCM nonce
+	Stamp         *VaultStamp            `protobuf:"bytes,3,opt,name=stamp,proto3" json:"stamp,omitempty"`                    // the vault file written by the client
+	VaultId       string                 `protobuf:"bytes,4,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"` // the vault id, identifying the session instead of the path if set
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -234,31 +276,836 @@ func (x *UpdateRequest) GetNonce() []byte {
 	return nil
 }
 
-var File_sessionpb_session_proto protoreflect.FileDescriptor
+func (x *UpdateRequest) GetStamp() *VaultStamp {
+	if x != nil {
+		return x.Stamp
+	}
+	return nil
+}
 
-const file_sessionpb_session_proto_rawDesc = "" +
-	"\n" +
-	"\x17sessionpb/session.proto\x12\tsessionpb\x1a\x1bgoogle/protobuf/empty.proto\"2\n" +
-	"\bVaultKey\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\fR\x03key\x12\x14\n" +
-	"\x05nonce\x18\x02 \x01(\fR\x05nonce\"\x8a\x01\n" +
-	"\fLoginRequest\x12\x1d\n" +
-	"\n" +
-	"vault_path\x18\x01 \x01(\tR\tvaultPath\x12)\n" +
-	"\x10duration_seconds\x18\x02 \x01(\x03R\x0fdurationSeconds\x120\n" +
-	"\tvault_key\x18\x03 \x01(\v2\x13.sessionpb.VaultKeyR\bvaultKey\"/\n" +
-	"\x0eSessionRequest\x12\x1d\n" +
-	"\n" +
-	"vault_path\x18\x01 \x01(\tR\tvaultPath\"D\n" +
-	"\rUpdateRequest\x12\x1d\n" +
-	"\n" +
-	"vault_path\x18\x01 \x01(\tR\tvaultPath\x12\x14\n" +
-	"\x05nonce\x18\x02 \x01(\fR\x05nonce2\x84\x02\n" +
+func (x *UpdateRequest) GetVaultId() string {
+	if x != nil {
+		return x.VaultId
+	}
+	return ""
+}
+
+// MoveRequest moves an existing vault session to the new path of the vault file.
+type MoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VaultPath     string                 `protobuf:"bytes,1,opt,name=vault_path,json=vaultPath,proto3" json:"vault_path,omitempty"` // the path the session was started for
+	VaultId       string                 `protobuf:"bytes,2,opt,name=vault_id,json=vaultId,proto3" json:"vault_id,omitempty"`       // the vault id, identifying the session instead of the path if set
+	NewVaultPath  string                 `protobuf:"bytes,3,opt,name=new_vault_path,json=newVaultPath,proto3" json:"new_vault_path,omitempty"`
+	Stamp         *VaultStamp            `protobuf:"bytes,4,opt,name=stamp,proto3" json:"stamp,omitempty"` // the moved vault file
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveRequest) Reset() {
+	*x = MoveRequest{}
+	mi := &file_sessionpb_session_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveRequest) ProtoMessage() {}
+
+func (x *MoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveRequest.ProtoReflect.Descriptor instead.
+func (*MoveRequest) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MoveRequest) GetVaultPath() string {
+	if x != nil {
+		return x.VaultPath
+	}
+	return ""
+}
+
+func (x *MoveRequest) GetVaultId() string {
+	if x != nil {
+		return x.VaultId
+	}
+	return ""
+}
+
+func (x *MoveRequest) GetNewVaultPath() string {
+	if x != nil {
+		return x.NewVaultPath
+	}
+	return ""
+}
+
+func (x *MoveRequest) GetStamp() *VaultStamp {
+	if x != nil {
+		return x.Stamp
+	}
+	return nil
+}
+
+// VaultStamp identifies a version of the vault file by its modification time and size.
+type VaultStamp struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ModTimeUnixNano int64                  `protobuf:"varint,1,opt,name=mod_time_unix_nano,json=modTimeUnixNano,proto3" json:"mod_time_unix_nano,omitempty"`
+	Size            int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *VaultStamp) Reset() {
+	*x = VaultStamp{}
+	mi := &file_sessionpb_session_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VaultStamp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VaultStamp) ProtoMessage() {}
+
+func (x *VaultStamp) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VaultStamp.ProtoReflect.Descriptor instead.
+func (*VaultStamp) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VaultStamp) GetModTimeUnixNano() int64 {
+	if x != nil {
+		return x.ModTimeUnixNano
+	}
+	return 0
+}
+
+func (x *VaultStamp) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// HealthResponse describes the daemon status.
+type HealthResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UptimeSeconds  int64                  `protobuf:"varint,1,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	ActiveSessions int64                  `protobuf:"varint,2,opt,name=active_sessions,json=activeSessions,proto3" json:"active_sessions,omitempty"`
+	Requests       []*RequestCounter      `protobuf:"bytes,3,rep,name=requests,proto3" json:"requests,omitempty"` // per method counters, sorted by method
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_sessionpb_session_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HealthResponse) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *HealthResponse) GetActiveSessions() int64 {
+	if x != nil {
+		return x.ActiveSessions
+	}
+	return 0
+}
+
+func (x *HealthResponse) GetRequests() []*RequestCounter {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// RequestCounter counts the handled requests of a single rpc method.
+type RequestCounter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Total         uint64                 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Errors        uint64                 `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"` // requests that returned an error
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestCounter) Reset() {
+	*x = RequestCounter{}
+	mi := &file_sessionpb_session_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestCounter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestCounter) ProtoMessage() {}
+
+func (x *RequestCounter) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestCounter.ProtoReflect.Descriptor instead.
+func (*RequestCounter) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RequestCounter) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *RequestCounter) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *RequestCounter) GetErrors() uint64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+// InfoResponse identifies the daemon build and protocol.
+type InfoResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Version         string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	ProtocolVersion uint32                 `protobuf:"varint,2,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InfoResponse) Reset() {
+	*x = InfoResponse{}
+	mi := &file_sessionpb_session_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InfoResponse) ProtoMessage() {}
+
+func (x *InfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InfoResponse.ProtoReflect.Descriptor instead.
+func (*InfoResponse) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *InfoResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *InfoResponse) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+// LogoutAllResponse reports the number of sessions cleared by LogoutAll.
+type LogoutAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      int64                  `protobuf:"varint,1,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutAllResponse) Reset() {
+	*x = LogoutAllResponse{}
+	mi := &file_sessionpb_session_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutAllResponse) ProtoMessage() {}
+
+func (x *LogoutAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutAllResponse.ProtoReflect.Descriptor instead.
+func (*LogoutAllResponse) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LogoutAllResponse) GetSessions() int64 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+// ScheduleResponse lists the scheduled jobs, sorted by name.
+type ScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*JobStatus           `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleResponse) Reset() {
+	*x = ScheduleResponse{}
+	mi := &file_sessionpb_session_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleResponse) ProtoMessage() {}
+
+func (x *ScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ScheduleResponse) GetJobs() []*JobStatus {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// JobStatus describes a scheduled job and its last run.
+type JobStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	IntervalSeconds int64                  `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	NextRunUnix     int64                  `protobuf:"varint,3,opt,name=next_run_unix,json=nextRunUnix,proto3" json:"next_run_unix,omitempty"`
+	LastRunUnix     int64                  `protobuf:"varint,4,opt,name=last_run_unix,json=lastRunUnix,proto3" json:"last_run_unix,omitempty"` // zero if the job has not run yet
+	LastResult      string                 `protobuf:"bytes,5,opt,name=last_result,json=lastResult,proto3" json:"last_result,omitempty"`       // summary of the last successful run
+	LastError       string                 `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`          // error of the last run, empty if it succeeded
+	Runs            uint64                 `protobuf:"varint,7,opt,name=runs,proto3" json:"runs,omitempty"`
+	Failures        uint64                 `protobuf:"varint,8,opt,name=failures,proto3" json:"failures,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *JobStatus) Reset() {
+	*x = JobStatus{}
+	mi := &file_sessionpb_session_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatus) ProtoMessage() {}
+
+func (x *JobStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
+func (*JobStatus) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *JobStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobStatus) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *JobStatus) GetNextRunUnix() int64 {
+	if x != nil {
+		return x.NextRunUnix
+	}
+	return 0
+}
+
+func (x *JobStatus) GetLastRunUnix() int64 {
+	if x != nil {
+		return x.LastRunUnix
+	}
+	return 0
+}
+
+func (x *JobStatus) GetLastResult() string {
+	if x != nil {
+		return x.LastResult
+	}
+	return ""
+}
+
+func (x *JobStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *JobStatus) GetRuns() uint64 {
+	if x != nil {
+		return x.Runs
+	}
+	return 0
+}
+
+func (x *JobStatus) GetFailures() uint64 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+// AuditLogRequest selects the most recent audit log entries.
+type AuditLogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int64                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // maximum number of entries, all entries if zero
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditLogRequest) Reset() {
+	*x = AuditLogRequest{}
+	mi := &file_sessionpb_session_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLogRequest) ProtoMessage() {}
+
+func (x *AuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLogRequest.ProtoReflect.Descriptor instead.
+func (*AuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AuditLogRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// AuditLogResponse lists audit log entries, oldest first.
+type AuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*AuditEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"` // path of the audit log file
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditLogResponse) Reset() {
+	*x = AuditLogResponse{}
+	mi := &file_sessionpb_session_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLogResponse) ProtoMessage() {}
+
+func (x *AuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLogResponse.ProtoReflect.Descriptor instead.
+func (*AuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AuditLogResponse) GetEntries() []*AuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *AuditLogResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+// AuditEntry records a session request and the client that made it.
+type AuditEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimeUnix      int64                  `protobuf:"varint,1,opt,name=time_unix,json=timeUnix,proto3" json:"time_unix,omitempty"`
+	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Uid           int64                  `protobuf:"varint,3,opt,name=uid,proto3" json:"uid,omitempty"`
+	Pid           int64                  `protobuf:"varint,4,opt,name=pid,proto3" json:"pid,omitempty"`
+	VaultPath     string                 `protobuf:"bytes,5,opt,name=vault_path,json=vaultPath,proto3" json:"vault_path,omitempty"` // empty for requests not naming a vault, e.g., LogoutAll
+	Result        string                 `protobuf:"bytes,6,opt,name=result,proto3" json:"result,omitempty"`                        // status code of the response, e.g., OK or ResourceExhausted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_sessionpb_session_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AuditEntry) GetTimeUnix() int64 {
+	if x != nil {
+		return x.TimeUnix
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetUid() int64 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetVaultPath() string {
+	if x != nil {
+		return x.VaultPath
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+// ShredRequest schedules the shredding of a file.
+type ShredRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`                                      // absolute path of a regular file
+	DelaySeconds  int64                  `protobuf:"varint,2,opt,name=delay_seconds,json=delaySeconds,proto3" json:"delay_seconds,omitempty"` // time until the file is shredded
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShredRequest) Reset() {
+	*x = ShredRequest{}
+	mi := &file_sessionpb_session_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShredRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShredRequest) ProtoMessage() {}
+
+func (x *ShredRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sessionpb_session_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShredRequest.ProtoReflect.Descriptor instead.
+func (*ShredRequest) Descriptor() ([]byte, []int) {
+	return file_sessionpb_session_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ShredRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ShredRequest) GetDelaySeconds() int64 {
+	if x != nil {
+		return x.DelaySeconds
+	}
+	return 0
+}
+
+var File_sessionpb_session_proto protoreflect.FileDescriptor
+
+const file_sessionpb_session_proto_rawDesc = "" +
+	"\n" +
+	"\x17sessionpb/session.proto\x12\tsessionpb\x1a\x1bgoogle/protobuf/empty.proto\"2\n" +
+	"\bVaultKey\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\fR\x05nonce\"\xfc\x01\n" +
+	"\fLoginRequest\x12\x1d\n" +
+	"\n" +
+	"vault_path\x18\x01 \x01(\tR\tvaultPath\x12)\n" +
+	"\x10duration_seconds\x18\x02 \x01(\x03R\x0fdurationSeconds\x120\n" +
+	"\tvault_key\x18\x03 \x01(\v2\x13.sessionpb.VaultKeyR\bvaultKey\x12(\n" +
+	"\x10confirm_each_use\x18\x04 \x01(\bR\x0econfirmEachUse\x12+\n" +
+	"\x05stamp\x18\x05 \x01(\v2\x15.sessionpb.VaultStampR\x05stamp\x12\x19\n" +
+	"\bvault_id\x18\x06 \x01(\tR\avaultId\"w\n" +
+	"\x0eSessionRequest\x12\x1d\n" +
+	"\n" +
+	"vault_path\x18\x01 \x01(\tR\tvaultPath\x12+\n" +
+	"\x05stamp\x18\x02 \x01(\v2\x15.sessionpb.VaultStampR\x05stamp\x12\x19\n" +
+	"\bvault_id\x18\x03 \x01(\tR\avaultId\"\x8c\x01\n" +
+	"\rUpdateRequest\x12\x1d\n" +
+	"\n" +
+	"vault_path\x18\x01 \x01(\tR\tvaultPath\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\fR\x05nonce\x12+\n" +
+	"\x05stamp\x18\x03 \x01(\v2\x15.sessionpb.VaultStampR\x05stamp\x12\x19\n" +
+	"\bvault_id\x18\x04 \x01(\tR\avaultId\"\x9a\x01\n" +
+	"\vMoveRequest\x12\x1d\n" +
+	"\n" +
+	"vault_path\x18\x01 \x01(\tR\tvaultPath\x12\x19\n" +
+	"\bvault_id\x18\x02 \x01(\tR\avaultId\x12$\n" +
+	"\x0enew_vault_path\x18\x03 \x01(\tR\fnewVaultPath\x12+\n" +
+	"\x05stamp\x18\x04 \x01(\v2\x15.sessionpb.VaultStampR\x05stamp\"M\n" +
+	"\n" +
+	"VaultStamp\x12+\n" +
+	"\x12mod_time_unix_nano\x18\x01 \x01(\x03R\x0fmodTimeUnixNano\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\"\x97\x01\n" +
+	"\x0eHealthResponse\x12%\n" +
+	"\x0euptime_seconds\x18\x01 \x01(\x03R\ruptimeSeconds\x12'\n" +
+	"\x0factive_sessions\x18\x02 \x01(\x03R\x0eactiveSessions\x125\n" +
+	"\brequests\x18\x03 \x03(\v2\x19.sessionpb.RequestCounterR\brequests\"V\n" +
+	"\x0eRequestCounter\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x04R\x05total\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x04R\x06errors\"S\n" +
+	"\fInfoResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12)\n" +
+	"\x10protocol_version\x18\x02 \x01(\rR\x0fprotocolVersion\"/\n" +
+	"\x11LogoutAllResponse\x12\x1a\n" +
+	"\bsessions\x18\x01 \x01(\x03R\bsessions\"<\n" +
+	"\x10ScheduleResponse\x12(\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x14.sessionpb.JobStatusR\x04jobs\"\x82\x02\n" +
+	"\tJobStatus\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x03R\x0fintervalSeconds\x12\"\n" +
+	"\rnext_run_unix\x18\x03 \x01(\x03R\vnextRunUnix\x12\"\n" +
+	"\rlast_run_unix\x18\x04 \x01(\x03R\vlastRunUnix\x12\x1f\n" +
+	"\vlast_result\x18\x05 \x01(\tR\n" +
+	"lastResult\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x12\x12\n" +
+	"\x04runs\x18\a \x01(\x04R\x04runs\x12\x1a\n" +
+	"\bfailures\x18\b \x01(\x04R\bfailures\"'\n" +
+	"\x0fAuditLogRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x03R\x05limit\"W\n" +
+	"\x10AuditLogResponse\x12/\n" +
+	"\aentries\x18\x01 \x03(\v2\x15.sessionpb.AuditEntryR\aentries\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"\x9c\x01\n" +
+	"\n" +
+	"AuditEntry\x12\x1b\n" +
+	"\ttime_unix\x18\x01 \x01(\x03R\btimeUnix\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x10\n" +
+	"\x03uid\x18\x03 \x01(\x03R\x03uid\x12\x10\n" +
+	"\x03pid\x18\x04 \x01(\x03R\x03pid\x12\x1d\n" +
+	"\n" +
+	"vault_path\x18\x05 \x01(\tR\tvaultPath\x12\x16\n" +
+	"\x06result\x18\x06 \x01(\tR\x06result\"G\n" +
+	"\fShredRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12#\n" +
+	"\rdelay_seconds\x18\x02 \x01(\x03R\fdelaySeconds2\xc9\x05\n" +
 	"\aSession\x128\n" +
 	"\x05Login\x12\x17.sessionpb.LoginRequest\x1a\x16.google.protobuf.Empty\x12?\n" +
 	"\rGetSessionKey\x12\x19.sessionpb.SessionRequest\x1a\x13.sessionpb.VaultKey\x12A\n" +
 	"\rUpdateSession\x12\x18.sessionpb.UpdateRequest\x1a\x16.google.protobuf.Empty\x12;\n" +
-	"\x06Logout\x12\x19.sessionpb.SessionRequest\x1a\x16.google.protobuf.EmptyB;Z9github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpbb\x06proto3"
+	"\x06Logout\x12\x19.sessionpb.SessionRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
+	"\tLogoutAll\x12\x16.google.protobuf.Empty\x1a\x1c.sessionpb.LogoutAllResponse\x12;\n" +
+	"\x06Health\x12\x16.google.protobuf.Empty\x1a\x19.sessionpb.HealthResponse\x12:\n" +
+	"\aGetInfo\x12\x16.google.protobuf.Empty\x1a\x17.sessionpb.InfoResponse\x12B\n" +
+	"\vGetSchedule\x12\x16.google.protobuf.Empty\x1a\x1b.sessionpb.ScheduleResponse\x12F\n" +
+	"\vGetAuditLog\x12\x1a.sessionpb.AuditLogRequest\x1a\x1b.sessionpb.AuditLogResponse\x12<\n" +
+	"\tShredFile\x12\x17.sessionpb.ShredRequest\x1a\x16.google.protobuf.Empty\x12=\n" +
+	"\vMoveSession\x12\x16.sessionpb.MoveRequest\x1a\x16.google.protobuf.EmptyB;Z9github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpbb\x06proto3"
 
 var (
 	file_sessionpb_session_proto_rawDescOnce sync.Once
@@ -272,29 +1119,62 @@ func file_sessionpb_session_proto_rawDescGZIP() []byte {
 	return file_sessionpb_session_proto_rawDescData
 }
 
-var file_sessionpb_session_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_sessionpb_session_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_sessionpb_session_proto_goTypes = []any{
-	(*VaultKey)(nil),       // 0: sessionpb.VaultKey
-	(*LoginRequest)(nil),   // 1: sessionpb.LoginRequest
-	(*SessionRequest)(nil), // 2: sessionpb.SessionRequest
-	(*UpdateRequest)(nil),  // 3: sessionpb.UpdateRequest
-	(*emptypb.Empty)(nil),  // 4: google.protobuf.Empty
+	(*VaultKey)(nil),          // 0: sessionpb.VaultKey
+	(*LoginRequest)(nil),      // 1: sessionpb.LoginRequest
+	(*SessionRequest)(nil),    // 2: sessionpb.SessionRequest
+	(*UpdateRequest)(nil),     // 3: sessionpb.UpdateRequest
+	(*MoveRequest)(nil),       // 4: sessionpb.MoveRequest
+	(*VaultStamp)(nil),        // 5: sessionpb.VaultStamp
+	(*HealthResponse)(nil),    // 6: sessionpb.HealthResponse
+	(*RequestCounter)(nil),    // 7: sessionpb.RequestCounter
+	(*InfoResponse)(nil),      // 8: sessionpb.InfoResponse
+	(*LogoutAllResponse)(nil), // 9: sessionpb.LogoutAllResponse
+	(*ScheduleResponse)(nil),  // 10: sessionpb.ScheduleResponse
+	(*JobStatus)(nil),         // 11: sessionpb.JobStatus
+	(*AuditLogRequest)(nil),   // 12: sessionpb.AuditLogRequest
+	(*AuditLogResponse)(nil),  // 13: sessionpb.AuditLogResponse
+	(*AuditEntry)(nil),        // 14: sessionpb.AuditEntry
+	(*ShredRequest)(nil),      // 15: sessionpb.ShredRequest
+	(*emptypb.Empty)(nil),     // 16: google.protobuf.Empty
 }
 var file_sessionpb_session_proto_depIdxs = []int32{
-	0, // 0: sessionpb.LoginRequest.vault_key:type_name -> sessionpb.VaultKey
-	1, // 1: sessionpb.Session.Login:input_type -> sessionpb.LoginRequest
-	2, // 2: sessionpb.Session.GetSessionKey:input_type -> sessionpb.SessionRequest
-	3, // 3: sessionpb.Session.UpdateSession:input_type -> sessionpb.UpdateRequest
-	2, // 4: sessionpb.Session.Logout:input_type -> sessionpb.SessionRequest
-	4, // 5: sessionpb.Session.Login:output_type -> google.protobuf.Empty
-	0, // 6: sessionpb.Session.GetSessionKey:output_type -> sessionpb.VaultKey
-	4, // 7: sessionpb.Session.UpdateSession:output_type -> google.protobuf.Empty
-	4, // 8: sessionpb.Session.Logout:output_type -> google.protobuf.Empty
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: sessionpb.LoginRequest.vault_key:type_name -> sessionpb.VaultKey
+	5,  // 1: sessionpb.LoginRequest.stamp:type_name -> sessionpb.VaultStamp
+	5,  // 2: sessionpb.SessionRequest.stamp:type_name -> sessionpb.VaultStamp
+	5,  // 3: sessionpb.UpdateRequest.stamp:type_name -> sessionpb.VaultStamp
+	5,  // 4: sessionpb.MoveRequest.stamp:type_name -> sessionpb.VaultStamp
+	7,  // 5: sessionpb.HealthResponse.requests:type_name -> sessionpb.RequestCounter
+	11, // 6: sessionpb.ScheduleResponse.jobs:type_name -> sessionpb.JobStatus
+	14, // 7: sessionpb.AuditLogResponse.entries:type_name -> sessionpb.AuditEntry
+	1,  // 8: sessionpb.Session.Login:input_type -> sessionpb.LoginRequest
+	2,  // 9: sessionpb.Session.GetSessionKey:input_type -> sessionpb.SessionRequest
+	3,  // 10: sessionpb.Session.UpdateSession:input_type -> sessionpb.UpdateRequest
+	2,  // 11: sessionpb.Session.Logout:input_type -> sessionpb.SessionRequest
+	16, // 12: sessionpb.Session.LogoutAll:input_type -> google.protobuf.Empty
+	16, // 13: sessionpb.Session.Health:input_type -> google.protobuf.Empty
+	16, // 14: sessionpb.Session.GetInfo:input_type -> google.protobuf.Empty
+	16, // 15: sessionpb.Session.GetSchedule:input_type -> google.protobuf.Empty
+	12, // 16: sessionpb.Session.GetAuditLog:input_type -> sessionpb.AuditLogRequest
+	15, // 17: sessionpb.Session.ShredFile:input_type -> sessionpb.ShredRequest
+	4,  // 18: sessionpb.Session.MoveSession:input_type -> sessionpb.MoveRequest
+	16, // 19: sessionpb.Session.Login:output_type -> google.protobuf.Empty
+	0,  // 20: sessionpb.Session.GetSessionKey:output_type -> sessionpb.VaultKey
+	16, // 21: sessionpb.Session.UpdateSession:output_type -> google.protobuf.Empty
+	16, // 22: sessionpb.Session.Logout:output_type -> google.protobuf.Empty
+	9,  // 23: sessionpb.Session.LogoutAll:output_type -> sessionpb.LogoutAllResponse
+	6,  // 24: sessionpb.Session.Health:output_type -> sessionpb.HealthResponse
+	8,  // 25: sessionpb.Session.GetInfo:output_type -> sessionpb.InfoResponse
+	10, // 26: sessionpb.Session.GetSchedule:output_type -> sessionpb.ScheduleResponse
+	13, // 27: sessionpb.Session.GetAuditLog:output_type -> sessionpb.AuditLogResponse
+	16, // 28: sessionpb.Session.ShredFile:output_type -> google.protobuf.Empty
+	16, // 29: sessionpb.Session.MoveSession:output_type -> google.protobuf.Empty
+	19, // [19:30] is the sub-list for method output_type
+	8,  // [8:19] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_sessionpb_session_proto_init() }
@@ -308,7 +1188,7 @@ func file_sessionpb_session_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sessionpb_session_proto_rawDesc), len(file_sessionpb_session_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -24,6 +24,13 @@ const (
 	Session_GetSessionKey_FullMethodName = "/sessionpb.Session/GetSessionKey"
 	Session_UpdateSession_FullMethodName = "/sessionpb.Session/UpdateSession"
 	Session_Logout_FullMethodName        = "/sessionpb.Session/Logout"
+	Session_LogoutAll_FullMethodName     = "/sessionpb.Session/LogoutAll"
+	Session_Health_FullMethodName        = "/sessionpb.Session/Health"
+	Session_GetInfo_FullMethodName       = "/sessionpb.Session/GetInfo"
+	Session_GetSchedule_FullMethodName   = "/sessionpb.Session/GetSchedule"
+	Session_GetAuditLog_FullMethodName   = "/sessionpb.Session/GetAuditLog"
+	Session_ShredFile_FullMethodName     = "/sessionpb.Session/ShredFile"
+	Session_MoveSession_FullMethodName   = "/sessionpb.Session/MoveSession"
 )
 
 // SessionClient is the client API for Session service.
@@ -41,6 +48,20 @@ type SessionClient interface {
 	UpdateSession(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Logout clears stored cipher data for a vault path.
 	Logout(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// LogoutAll clears the stored cipher data of all vault sessions.
+	LogoutAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LogoutAllResponse, error)
+	// Health reports the daemon status and request counters.
+	Health(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*HealthResponse, error)
+	// GetInfo reports the daemon version and the session protocol version it speaks.
+	GetInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*InfoResponse, error)
+	// GetSchedule reports the scheduled jobs of the daemon and their last results.
+	GetSchedule(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ScheduleResponse, error)
+	// GetAuditLog returns the most recent entries of the daemon audit log.
+	GetAuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (*AuditLogResponse, error)
+	// ShredFile overwrites a file with zeros and removes it once a delay passes.
+	ShredFile(ctx context.Context, in *ShredRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// MoveSession moves the session of a vault to the new path of its file.
+	MoveSession(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type sessionClient struct {
@@ -91,6 +112,76 @@ func (c *sessionClient) Logout(ctx context.Context, in *SessionRequest, opts ...
 	return out, nil
 }
 
+func (c *sessionClient) LogoutAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LogoutAllResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogoutAllResponse)
+	err := c.cc.Invoke(ctx, Session_LogoutAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) Health(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*HealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, Session_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) GetInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*InfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InfoResponse)
+	err := c.cc.Invoke(ctx, Session_GetInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) GetSchedule(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduleResponse)
+	err := c.cc.Invoke(ctx, Session_GetSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) GetAuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (*AuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditLogResponse)
+	err := c.cc.Invoke(ctx, Session_GetAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) ShredFile(ctx context.Context, in *ShredRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Session_ShredFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionClient) MoveSession(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Session_MoveSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SessionServer is the server API for Session service.
 // All implementations must embed UnimplementedSessionServer
 // for forward compatibility.
@@ -106,6 +197,20 @@ type SessionServer interface {
 	UpdateSession(context.Context, *UpdateRequest) (*emptypb.Empty, error)
 	// Logout clears stored cipher data for a vault path.
 	Logout(context.Context, *SessionRequest) (*emptypb.Empty, error)
+	// LogoutAll clears the stored cipher data of all vault sessions.
+	LogoutAll(context.Context, *emptypb.Empty) (*LogoutAllResponse, error)
+	// Health reports the daemon status and request counters.
+	Health(context.Context, *emptypb.Empty) (*HealthResponse, error)
+	// GetInfo reports the daemon version and the session protocol version it speaks.
+	GetInfo(context.Context, *emptypb.Empty) (*InfoResponse, error)
+	// GetSchedule reports the scheduled jobs of the daemon and their last results.
+	GetSchedule(context.Context, *emptypb.Empty) (*ScheduleResponse, error)
+	// GetAuditLog returns the most recent entries of the daemon audit log.
+	GetAuditLog(context.Context, *AuditLogRequest) (*AuditLogResponse, error)
+	// ShredFile overwrites a file with zeros and removes it once a delay passes.
+	ShredFile(context.Context, *ShredRequest) (*emptypb.Empty, error)
+	// MoveSession moves the session of a vault to the new path of its file.
+	MoveSession(context.Context, *MoveRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedSessionServer()
 }
 
@@ -128,6 +233,27 @@ func (UnimplementedSessionServer) UpdateSession(context.Context, *UpdateRequest)
 func (UnimplementedSessionServer) Logout(context.Context, *SessionRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
 }
+func (UnimplementedSessionServer) LogoutAll(context.Context, *emptypb.Empty) (*LogoutAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LogoutAll not implemented")
+}
+func (UnimplementedSessionServer) Health(context.Context, *emptypb.Empty) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedSessionServer) GetInfo(context.Context, *emptypb.Empty) (*InfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
+func (UnimplementedSessionServer) GetSchedule(context.Context, *emptypb.Empty) (*ScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchedule not implemented")
+}
+func (UnimplementedSessionServer) GetAuditLog(context.Context, *AuditLogRequest) (*AuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuditLog not implemented")
+}
+func (UnimplementedSessionServer) ShredFile(context.Context, *ShredRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShredFile not implemented")
+}
+func (UnimplementedSessionServer) MoveSession(context.Context, *MoveRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveSession not implemented")
+}
 func (UnimplementedSessionServer) mustEmbedUnimplementedSessionServer() {}
 func (UnimplementedSessionServer) testEmbeddedByValue()                 {}
 
@@ -221,6 +347,132 @@ func _Session_Logout_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Session_LogoutAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).LogoutAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_LogoutAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).LogoutAll(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).Health(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_GetInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).GetInfo(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_GetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).GetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_GetSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).GetSchedule(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_GetAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).GetAuditLog(ctx, req.(*AuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_ShredFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).ShredFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_ShredFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).ShredFile(ctx, req.(*ShredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Session_MoveSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServer).MoveSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Session_MoveSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServer).MoveSession(ctx, req.(*MoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Session_ServiceDesc is the grpc.ServiceDesc for Session service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -244,6 +496,34 @@ var Session_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Logout",
 			Handler:    _Session_Logout_Handler,
 		},
+		{
+			MethodName: "LogoutAll",
+			Handler:    _Session_LogoutAll_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _Session_Health_Handler,
+		},
+		{
+			MethodName: "GetInfo",
+			Handler:    _Session_GetInfo_Handler,
+		},
+		{
+			MethodName: "GetSchedule",
+			Handler:    _Session_GetSchedule_Handler,
+		},
+		{
+			MethodName: "GetAuditLog",
+			Handler:    _Session_GetAuditLog_Handler,
+		},
+		{
+			MethodName: "ShredFile",
+			Handler:    _Session_ShredFile_Handler,
+		},
+		{
+			MethodName: "MoveSession",
+			Handler:    _Session_MoveSession_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "sessionpb/session.proto",
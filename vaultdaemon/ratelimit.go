@@ -0,0 +1,86 @@
+package vaultdaemon
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults of the per-uid request rate limit, see [WithRateLimit].
+const (
+	DefaultRateLimit = 50
+	DefaultRateBurst = 100
+)
+
+// errRateLimited is returned for requests exceeding the rate limit of their uid.
+var errRateLimited = status.Error(codes.ResourceExhausted, "rate limit exceeded")
+
+// rateLimiter limits the request rate of each uid using a token bucket,
+// refilled at rate tokens per second, holding up to burst tokens.
+type rateLimiter struct {
+	rate   float64
+	burst  float64
+	now    func() time.Time
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	buckets map[int]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(logger *slog.Logger, rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(max(burst, 1)),
+		now:     time.Now,
+		logger:  logger,
+		buckets: make(map[int]*tokenBucket),
+	}
+}
+
+// allow reports whether a request of uid is allowed, taking a token from its bucket.
+func (l *rateLimiter) allow(uid int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	b, ok := l.buckets[uid]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[uid] = b
+	}
+
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+// unaryInterceptor rejects requests exceeding the rate limit of the client uid.
+// Requests of clients without known credentials are not limited.
+func (l *rateLimiter) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	cred, ok := peerFromContext(ctx)
+	if !ok || l.allow(cred.uid) {
+		return handler(ctx, req)
+	}
+
+	l.logger.Warn("request rate limited", "method", info.FullMethod, "uid", cred.uid, "pid", cred.pid)
+
+	return nil, errRateLimited
+}
@@ -0,0 +1,83 @@
+package vaultdaemon
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	l := newRateLimiter(slog.New(slog.NewTextHandler(io.Discard, nil)), 2, 3)
+	l.now = func() time.Time { return now }
+
+	for i := range 3 {
+		if !l.allow(1000) {
+			t.Fatalf("request %d: want allowed within the burst", i+1)
+		}
+	}
+
+	if l.allow(1000) {
+		t.Fatal("want rate limited after the burst")
+	}
+
+	if !l.allow(1001) {
+		t.Error("want the requests of another uid allowed")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+
+	if !l.allow(1000) {
+		t.Error("want allowed after a refill of one token")
+	}
+
+	if l.allow(1000) {
+		t.Error("want rate limited after using the refilled token")
+	}
+
+	now = now.Add(time.Hour)
+
+	for i := range 3 {
+		if !l.allow(1000) {
+			t.Fatalf("request %d: want allowed within the refilled burst", i+1)
+		}
+	}
+
+	if l.allow(1000) {
+		t.Error("want the refill capped by the burst")
+	}
+}
+
+func TestRateLimiter_UnaryInterceptor(t *testing.T) {
+	l := newRateLimiter(slog.New(slog.NewTextHandler(io.Discard, nil)), 1, 1)
+	l.now = func() time.Time { return time.Unix(0, 0) }
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/sessionpb.Session/GetSessionKey"}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: peerAddr{Addr: &net.UnixAddr{Name: "@", Net: "unix"}, cred: peerCredentials{uid: 1000, pid: 42}},
+	})
+
+	if _, err := l.unaryInterceptor(ctx, nil, info, handler); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	if _, err := l.unaryInterceptor(ctx, nil, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("want ResourceExhausted, got %v", err)
+	}
+
+	// clients without known credentials are not limited.
+	if _, err := l.unaryInterceptor(context.Background(), nil, info, handler); err != nil {
+		t.Errorf("want requests without credentials allowed, got %v", err)
+	}
+}
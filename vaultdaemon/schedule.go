@@ -0,0 +1,174 @@
+package vaultdaemon
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
+)
+
+// Job is a task the daemon runs periodically while it is up,
+// e.g., an automatic vault backup.
+type Job struct {
+	Name     string
+	Interval time.Duration
+
+	// Run performs the job and returns a short summary of its result.
+	Run func(ctx context.Context) (string, error)
+}
+
+// WithJobs sets the jobs the daemon runs periodically.
+func WithJobs(jobs ...Job) Option {
+	return func(c *config) {
+		c.jobs = append(c.jobs, jobs...)
+	}
+}
+
+// validateJobs verifies that every job has a unique name,
+// a positive interval and a run function.
+func validateJobs(jobs []Job) error {
+	for i, j := range jobs {
+		switch {
+		case len(j.Name) == 0:
+			return errors.New("scheduled job: empty name")
+		case j.Interval <= 0:
+			return fmt.Errorf("scheduled job %q: interval must be positive", j.Name)
+		case j.Run == nil:
+			return fmt.Errorf("scheduled job %q: no run function", j.Name)
+		case slices.ContainsFunc(jobs[:i], func(o Job) bool { return o.Name == j.Name }):
+			return fmt.Errorf("scheduled job %q: defined more than once", j.Name)
+		}
+	}
+
+	return nil
+}
+
+// scheduler runs the scheduled jobs and keeps the status of their last run.
+type scheduler struct {
+	jobs   []Job
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	status map[string]*pb.JobStatus
+}
+
+func newScheduler(logger *slog.Logger, jobs []Job) *scheduler {
+	s := &scheduler{
+		jobs:   jobs,
+		logger: logger,
+		status: make(map[string]*pb.JobStatus, len(jobs)),
+	}
+
+	for _, j := range jobs {
+		s.status[j.Name] = &pb.JobStatus{
+			Name:            j.Name,
+			IntervalSeconds: int64(j.Interval.Seconds()),
+		}
+	}
+
+	return s
+}
+
+// run runs each job once per interval, the first run is one interval
+// after the start, until ctx is done.
+func (s *scheduler) run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, j := range s.jobs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (s *scheduler) loop(ctx context.Context, j Job) {
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	s.logger.Info("job scheduled", "job", j.Name, "interval", j.Interval)
+	s.setNextRun(j.Name, time.Now().Add(j.Interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runJob(ctx, j)
+			s.setNextRun(j.Name, time.Now().Add(j.Interval))
+		}
+	}
+}
+
+// runJob runs j once, logs its result and records it in the job status.
+func (s *scheduler) runJob(ctx context.Context, j Job) {
+	start := time.Now()
+
+	result, err := j.Run(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.status[j.Name]
+	st.LastRunUnix = start.Unix()
+	st.Runs++
+
+	if err != nil {
+		st.Failures++
+		st.LastError = err.Error()
+
+		s.logger.Error("job failed", "job", j.Name, "err", err, "elapsed", time.Since(start))
+
+		return
+	}
+
+	st.LastError = ""
+	st.LastResult = result
+
+	s.logger.Info("job finished", "job", j.Name, "result", result, "elapsed", time.Since(start))
+}
+
+func (s *scheduler) setNextRun(name string, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status[name].NextRunUnix = t.Unix()
+}
+
+// snapshot returns a copy of the job statuses, sorted by name.
+func (s *scheduler) snapshot() []*pb.JobStatus {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]*pb.JobStatus, 0, len(s.status))
+	for _, st := range s.status {
+		jobs = append(jobs, &pb.JobStatus{
+			Name:            st.GetName(),
+			IntervalSeconds: st.GetIntervalSeconds(),
+			NextRunUnix:     st.GetNextRunUnix(),
+			LastRunUnix:     st.GetLastRunUnix(),
+			LastResult:      st.GetLastResult(),
+			LastError:       st.GetLastError(),
+			Runs:            st.GetRuns(),
+			Failures:        st.GetFailures(),
+		})
+	}
+
+	slices.SortFunc(jobs, func(a, b *pb.JobStatus) int { return cmp.Compare(a.GetName(), b.GetName()) })
+
+	return jobs
+}
@@ -0,0 +1,88 @@
+package vaultdaemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	fail := true
+	jobs := []Job{
+		{Name: "backup", Interval: time.Hour, Run: func(context.Context) (string, error) { return "ok", nil }},
+		{Name: "audit", Interval: time.Minute, Run: func(context.Context) (string, error) {
+			if fail {
+				return "", errors.New("boom")
+			}
+
+			return "clean", nil
+		}},
+	}
+
+	s := newSessionServer(logger, newMetrics())
+	s.scheduler = newScheduler(logger, jobs)
+
+	s.scheduler.runJob(ctx, jobs[0])
+	s.scheduler.runJob(ctx, jobs[1])
+
+	resp, err := s.GetSchedule(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("get schedule: %v", err)
+	}
+
+	got := resp.GetJobs()
+	if len(got) != 2 || got[0].GetName() != "audit" || got[1].GetName() != "backup" {
+		t.Fatalf("want jobs sorted by name, got %v", got)
+	}
+
+	audit, backup := got[0], got[1]
+
+	if audit.GetLastError() != "boom" || audit.GetFailures() != 1 || audit.GetRuns() != 1 {
+		t.Errorf("audit: want a recorded failure, got %v", audit)
+	}
+
+	if backup.GetLastResult() != "ok" || backup.GetLastError() != "" || backup.GetIntervalSeconds() != 3600 || backup.GetLastRunUnix() == 0 {
+		t.Errorf("backup: want a recorded success, got %v", backup)
+	}
+
+	fail = false
+
+	s.scheduler.runJob(ctx, jobs[1])
+
+	audit = s.scheduler.snapshot()[0]
+	if audit.GetLastError() != "" || audit.GetLastResult() != "clean" || audit.GetFailures() != 1 || audit.GetRuns() != 2 {
+		t.Errorf("audit: want the error cleared by a successful run, got %v", audit)
+	}
+}
+
+func TestValidateJobs(t *testing.T) {
+	run := func(context.Context) (string, error) { return "", nil }
+
+	tests := []struct {
+		name    string
+		jobs    []Job
+		wantErr bool
+	}{
+		{name: "valid", jobs: []Job{{Name: "a", Interval: time.Hour, Run: run}, {Name: "b", Interval: time.Minute, Run: run}}},
+		{name: "empty name", jobs: []Job{{Interval: time.Hour, Run: run}}, wantErr: true},
+		{name: "zero interval", jobs: []Job{{Name: "a", Run: run}}, wantErr: true},
+		{name: "no run", jobs: []Job{{Name: "a", Interval: time.Hour}}, wantErr: true},
+		{name: "duplicate", jobs: []Job{{Name: "a", Interval: time.Hour, Run: run}, {Name: "a", Interval: time.Minute, Run: run}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateJobs(tt.jobs); (err != nil) != tt.wantErr {
+				t.Errorf("want error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -2,10 +2,17 @@ package vaultdaemon
 
 import (
 	"context"
-	"log"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"maps"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ladzaretti/vlt-cli/securebytes"
 	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
 
 	"google.golang.org/grpc/codes"
@@ -54,6 +61,13 @@ func (m *safeMap[K, V]) Range(f func(K, V) bool) {
 	}
 }
 
+func (m *safeMap[K, V]) len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.data)
+}
+
 func (m *safeMap[K, V]) delete(key K) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -61,17 +75,65 @@ func (m *safeMap[K, V]) delete(key K) {
 	delete(m.data, key)
 }
 
+// deleteFunc deletes the key-value pairs for which f returns true.
+// The map is write locked for the duration of the iteration.
+func (m *safeMap[K, V]) deleteFunc(f func(K, V) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	maps.DeleteFunc(m.data, f)
+}
+
+// rename moves the value stored by from to the key to, replacing the value
+// stored by to, if any. f is called with the moved value while the map is locked.
+func (m *safeMap[K, V]) rename(from, to K, f func(V)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, ok := m.data[from]
+	if !ok {
+		return
+	}
+
+	f(v)
+
+	delete(m.data, from)
+	m.data[to] = v
+}
+
+// Errors returned for session requests. These hold no details, e.g., the vault
+// path, so that clients cannot tell why a session key was not released.
+var (
+	// errNoSession is returned whether no session exists for the path,
+	// or its key release could not be confirmed, see [sessionServer.lookup].
+	errNoSession = status.Error(codes.NotFound, "no session")
+
+	// errSessionDenied is returned if the user declined the key release.
+	errSessionDenied = status.Error(codes.PermissionDenied, "session use denied")
+)
+
 type session struct {
 	key      *pb.VaultKey
 	duration time.Duration
 	done     chan struct{}
+
+	// digest is the digest of the session name, see [sessionServer.lookup].
+	digest [sha256.Size]byte
+
+	// confirm requires a user confirmation before each key release.
+	confirm bool
+
+	// stamp identifies the vault file the key belongs to, see [stale].
+	// It is updated by concurrent rpcs, e.g., UpdateSession and MoveSession.
+	stamp atomic.Pointer[pb.VaultStamp]
 }
 
-func newSession(duration time.Duration, key *pb.VaultKey) *session {
+func newSession(duration time.Duration, key *pb.VaultKey, confirm bool) *session {
 	return &session{
 		key:      key,
 		duration: duration,
 		done:     make(chan struct{}),
+		confirm:  confirm,
 	}
 }
 
@@ -101,15 +163,36 @@ type sessionServer struct {
 	pb.UnimplementedSessionServer
 
 	sessions *safeMap[string, *session]
+	metrics  *metrics
+	logger   *slog.Logger
+
+	// version is the daemon version reported by GetInfo.
+	version string
+
+	// confirm prompts the user before releasing the key of sessions requiring confirmation.
+	confirm confirmFunc
+
+	// scheduler runs the scheduled jobs reported by GetSchedule.
+	scheduler *scheduler
+
+	// audit records session requests, reported by GetAuditLog, nil if disabled.
+	audit *auditLog
+
+	// shredder shreds the files scheduled by ShredFile.
+	shredder *shredder
 }
 
-func newSessionServer() *sessionServer {
+func newSessionServer(logger *slog.Logger, m *metrics) *sessionServer {
 	return &sessionServer{
 		sessions: newSafeMap[string, *session](),
+		metrics:  m,
+		logger:   logger,
+		shredder: newShredder(logger),
 	}
 }
 
-// stopAll stops all active sessions safely via safeMap.
+// stopAll stops all active sessions safely via safeMap,
+// and shreds the files pending shredding.
 func (s *sessionServer) stopAll() {
 	s.sessions.Range(func(_ string, s *session) bool {
 		zeroVaultKey(s.key)
@@ -117,10 +200,23 @@ func (s *sessionServer) stopAll() {
 
 		return true
 	})
+
+	s.shredder.flush()
+}
+
+// sessionName returns the name a vault session is stored by, the vault id if set,
+// so that the session is found once the vault file is moved, or its path otherwise.
+func sessionName(vaultID, vaultPath string) string {
+	if len(vaultID) > 0 {
+		return "id:" + vaultID
+	}
+
+	return vaultPath
 }
 
 func (s *sessionServer) Login(_ context.Context, req *pb.LoginRequest) (*emptypb.Empty, error) {
 	vaultPath := req.GetVaultPath()
+	name := sessionName(req.GetVaultId(), vaultPath)
 	sessionSeconds := req.GetDurationSeconds()
 
 	if sessionSeconds < 0 {
@@ -129,24 +225,20 @@ func (s *sessionServer) Login(_ context.Context, req *pb.LoginRequest) (*emptypb
 
 	duration := time.Duration(sessionSeconds) * time.Second
 
-	if existing, ok := s.sessions.load(vaultPath); ok {
+	if existing, ok := s.sessions.load(name); ok {
 		zeroVaultKey(existing.key)
 	}
 
-	session := newSession(duration, req.GetVaultKey())
-	s.sessions.store(req.GetVaultPath(), session)
+	session := newSession(duration, req.GetVaultKey(), req.GetConfirmEachUse())
+	session.digest = sha256.Sum256([]byte(name))
+	session.stamp.Store(req.GetStamp())
+	s.sessions.store(name, session)
 
-	log.Printf("session started for vault: %q: duration: %d[sec]", vaultPath, sessionSeconds)
+	s.logger.Info("session started", "vault", vaultPath, "duration", duration, "confirm", session.confirm)
 
 	go session.start(func() {
-		cur, ok := s.sessions.load(vaultPath)
-		if ok {
-			zeroVaultKey(cur.key)
-			cur.key = nil
-		}
-
-		s.sessions.delete(vaultPath)
-		log.Printf("session ended for vault: %s", vaultPath)
+		s.end(session)
+		s.logger.Info("session ended", "vault", vaultPath)
 	})
 
 	return &emptypb.Empty{}, nil
@@ -154,49 +246,230 @@ func (s *sessionServer) Login(_ context.Context, req *pb.LoginRequest) (*emptypb
 
 func (s *sessionServer) Logout(_ context.Context, req *pb.SessionRequest) (*emptypb.Empty, error) {
 	path := req.GetVaultPath()
+	name := sessionName(req.GetVaultId(), path)
 
-	session, ok := s.sessions.load(path)
+	session, ok := s.lookup(name)
 	if !ok {
-		return nil, status.Errorf(codes.NotFound, "no session found for the given path: %q", path)
+		return nil, errNoSession
 	}
 
+	s.drop(name, session)
+
+	s.logger.Debug("session logged out", "vault", path)
+
+	return &emptypb.Empty{}, nil
+}
+
+// end deletes the expired session, wiping its key. It is deleted by value,
+// the session may have been moved to another name since, see [sessionServer.MoveSession].
+func (s *sessionServer) end(expired *session) {
+	s.sessions.deleteFunc(func(_ string, cur *session) bool {
+		if cur != expired {
+			return false
+		}
+
+		zeroVaultKey(cur.key)
+		cur.key = nil
+
+		return true
+	})
+}
+
+// drop ends the named session, wiping its key.
+func (s *sessionServer) drop(name string, session *session) {
 	zeroVaultKey(session.key)
 	session.stop()
 
-	s.sessions.delete(path)
+	s.sessions.delete(name)
+}
+
+func (s *sessionServer) LogoutAll(context.Context, *emptypb.Empty) (*pb.LogoutAllResponse, error) {
+	var names []string
 
-	return &emptypb.Empty{}, nil
+	s.sessions.Range(func(name string, session *session) bool {
+		zeroVaultKey(session.key)
+		session.stop()
+
+		names = append(names, name)
+
+		return true
+	})
+
+	for _, n := range names {
+		s.sessions.delete(n)
+	}
+
+	s.logger.Info("all sessions logged out", "sessions", len(names))
+
+	return &pb.LogoutAllResponse{Sessions: int64(len(names))}, nil
 }
 
 func (s *sessionServer) UpdateSession(_ context.Context, req *pb.UpdateRequest) (*emptypb.Empty, error) {
-	path := req.GetVaultPath()
 	nonce := req.GetNonce()
 
-	session, ok := s.sessions.load(path)
+	session, ok := s.lookup(sessionName(req.GetVaultId(), req.GetVaultPath()))
 	if !ok {
-		return nil, status.Errorf(codes.NotFound, "no session found for the given path: %q", path)
+		return nil, errNoSession
 	}
 
-	session.key.Nonce = nonce
+	if len(nonce) > 0 {
+		session.key.Nonce = nonce
+	}
+
+	if stamp := req.GetStamp(); stamp != nil {
+		session.stamp.Store(stamp)
+	}
 
 	return &emptypb.Empty{}, nil
 }
 
-func (s *sessionServer) GetSessionKey(_ context.Context, req *pb.SessionRequest) (*pb.VaultKey, error) {
+// MoveSession moves the session of a vault to the new path of its file,
+// e.g., moved by 'vlt mv'. Sessions identified by the vault id keep their name.
+func (s *sessionServer) MoveSession(_ context.Context, req *pb.MoveRequest) (*emptypb.Empty, error) {
+	path, newPath := req.GetVaultPath(), req.GetNewVaultPath()
+	name, newName := sessionName(req.GetVaultId(), path), sessionName(req.GetVaultId(), newPath)
+
+	found, ok := s.lookup(name)
+	if !ok {
+		return nil, errNoSession
+	}
+
+	if name != newName {
+		// a session of another vault formerly at the new path is of no use anymore.
+		if existing, ok := s.sessions.load(newName); ok {
+			s.drop(newName, existing)
+		}
+
+		s.sessions.rename(name, newName, func(moved *session) {
+			moved.digest = sha256.Sum256([]byte(newName))
+		})
+	}
+
+	if stamp := req.GetStamp(); stamp != nil {
+		found.stamp.Store(stamp)
+	}
+
+	s.logger.Info("session moved", "vault", path, "to", newPath)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *sessionServer) GetSessionKey(ctx context.Context, req *pb.SessionRequest) (*pb.VaultKey, error) {
 	path := req.GetVaultPath()
 
-	session, ok := s.sessions.load(path)
+	name := sessionName(req.GetVaultId(), path)
+
+	session, ok := s.lookup(name)
 	if !ok {
-		return nil, status.Errorf(codes.NotFound, "no session found for the given path: %q", path)
+		return nil, errNoSession
+	}
+
+	// the vault file was replaced or modified outside vlt, e.g., by a file sync,
+	// the key would fail to decrypt it, or decrypt an outdated copy.
+	if stale(session.stamp.Load(), req.GetStamp()) {
+		s.drop(name, session)
+		s.logger.Info("session dropped: vault file changed", "vault", path)
+
+		return nil, errNoSession
+	}
+
+	if session.confirm {
+		if err := s.confirm(ctx, path); err != nil {
+			s.logger.Warn("session key release not confirmed", "vault", path, "err", err)
+
+			if errors.Is(err, errConfirmDenied) {
+				return nil, errSessionDenied
+			}
+
+			return nil, errNoSession
+		}
 	}
 
 	return session.key, nil
 }
 
-func zeroBytes(b []byte) {
-	for i := range b {
-		b[i] = 0
+// lookup returns the named session, see [sessionName].
+//
+// The name digest is compared to these of all sessions in constant time, without
+// returning early, so that the time taken does not reveal whether a session exists.
+func (s *sessionServer) lookup(name string) (*session, bool) {
+	digest := sha256.Sum256([]byte(name))
+
+	var found *session
+
+	s.sessions.Range(func(_ string, session *session) bool {
+		if subtle.ConstantTimeCompare(digest[:], session.digest[:]) == 1 {
+			found = session
+		}
+
+		return true
+	})
+
+	return found, found != nil
+}
+
+func (s *sessionServer) Health(context.Context, *emptypb.Empty) (*pb.HealthResponse, error) {
+	return &pb.HealthResponse{
+		UptimeSeconds:  int64(s.metrics.uptime().Seconds()),
+		ActiveSessions: int64(s.sessions.len()),
+		Requests:       s.metrics.counters(),
+	}, nil
+}
+
+func (s *sessionServer) GetInfo(context.Context, *emptypb.Empty) (*pb.InfoResponse, error) {
+	return &pb.InfoResponse{
+		Version:         s.version,
+		ProtocolVersion: ProtocolVersion,
+	}, nil
+}
+
+func (s *sessionServer) GetSchedule(context.Context, *emptypb.Empty) (*pb.ScheduleResponse, error) {
+	return &pb.ScheduleResponse{Jobs: s.scheduler.snapshot()}, nil
+}
+
+func (s *sessionServer) GetAuditLog(_ context.Context, req *pb.AuditLogRequest) (*pb.AuditLogResponse, error) {
+	if s.audit == nil {
+		return nil, status.Error(codes.FailedPrecondition, errAuditLogDisabled.Error())
+	}
+
+	if req.GetLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid limit: %d", req.GetLimit())
+	}
+
+	entries, err := readAuditLog(s.audit.path, int(req.GetLimit()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "read audit log: %v", err)
 	}
+
+	return &pb.AuditLogResponse{Entries: entries, Path: s.audit.path}, nil
+}
+
+func (s *sessionServer) ShredFile(_ context.Context, req *pb.ShredRequest) (*emptypb.Empty, error) {
+	path := req.GetPath()
+
+	if !filepath.IsAbs(path) {
+		return nil, status.Errorf(codes.InvalidArgument, "path must be absolute: %q", path)
+	}
+
+	if req.GetDelaySeconds() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid delay: %d", req.GetDelaySeconds())
+	}
+
+	if err := s.shredder.schedule(path, time.Duration(req.GetDelaySeconds())*time.Second); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// stale reports whether the vault file changed since the session stamp was taken.
+// Sessions and requests without stamps are never stale, e.g., of older clients.
+func stale(session, current *pb.VaultStamp) bool {
+	if session == nil || current == nil {
+		return false
+	}
+
+	return session.GetModTimeUnixNano() != current.GetModTimeUnixNano() || session.GetSize() != current.GetSize()
 }
 
 func zeroVaultKey(vk *pb.VaultKey) {
@@ -204,6 +477,6 @@ func zeroVaultKey(vk *pb.VaultKey) {
 		return
 	}
 
-	zeroBytes(vk.GetKey())
-	zeroBytes(vk.GetNonce())
+	securebytes.Wipe(vk.GetKey())
+	securebytes.Wipe(vk.GetNonce())
 }
@@ -0,0 +1,291 @@
+package vaultdaemon
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/ladzaretti/vlt-cli/vaultdaemon/proto/sessionpb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestLogoutAll(t *testing.T) {
+	ctx := context.Background()
+	s := newSessionServer(slog.New(slog.NewTextHandler(io.Discard, nil)), newMetrics())
+
+	keys := map[string]*pb.VaultKey{}
+
+	for _, path := range []string{"/a", "/b"} {
+		keys[path] = &pb.VaultKey{Key: []byte("key"), Nonce: []byte("nonce")}
+
+		if _, err := s.Login(ctx, &pb.LoginRequest{VaultPath: path, DurationSeconds: 60, VaultKey: keys[path]}); err != nil {
+			t.Fatalf("login %s: %v", path, err)
+		}
+	}
+
+	resp, err := s.LogoutAll(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("logout all: %v", err)
+	}
+
+	if got := resp.GetSessions(); got != 2 {
+		t.Errorf("want 2 sessions logged out, got %d", got)
+	}
+
+	for path, key := range keys {
+		if _, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: path}); status.Code(err) != codes.NotFound {
+			t.Errorf("%s: want NotFound after logout, got %v", path, err)
+		}
+
+		if string(key.GetKey()) != "\x00\x00\x00" {
+			t.Errorf("%s: session key not wiped: %q", path, key.GetKey())
+		}
+	}
+}
+
+func TestGetSessionKey_UniformErrors(t *testing.T) {
+	ctx := context.Background()
+	s := newSessionServer(slog.New(slog.NewTextHandler(io.Discard, nil)), newMetrics())
+	defer s.stopAll()
+
+	s.confirm = func(context.Context, string) error { return errConfirmUnavailable }
+
+	req := &pb.LoginRequest{VaultPath: "/confirmed", DurationSeconds: 60, VaultKey: &pb.VaultKey{Key: []byte("k")}, ConfirmEachUse: true}
+	if _, err := s.Login(ctx, req); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	_, errMissing := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/missing"})
+	_, errUnconfirmed := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/confirmed"})
+	_, errLogout := s.Logout(ctx, &pb.SessionRequest{VaultPath: "/missing"})
+	_, errUpdate := s.UpdateSession(ctx, &pb.UpdateRequest{VaultPath: "/missing"})
+
+	want := status.Convert(errMissing)
+
+	if want.Code() != codes.NotFound || strings.Contains(want.Message(), "/missing") {
+		t.Errorf("want a NotFound error without the vault path, got %v", errMissing)
+	}
+
+	for name, err := range map[string]error{"unconfirmed": errUnconfirmed, "logout": errLogout, "update": errUpdate} {
+		if got := status.Convert(err); got.Code() != want.Code() || got.Message() != want.Message() {
+			t.Errorf("%s: want %v, got %v", name, errMissing, err)
+		}
+	}
+}
+
+func TestSession_VaultID(t *testing.T) {
+	ctx := context.Background()
+	s := newSessionServer(slog.New(slog.NewTextHandler(io.Discard, nil)), newMetrics())
+	defer s.stopAll()
+
+	req := &pb.LoginRequest{VaultPath: "/old", VaultId: "id", DurationSeconds: 60, VaultKey: &pb.VaultKey{Key: []byte("k")}}
+	if _, err := s.Login(ctx, req); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	// the vault file was moved, its session is found by the vault id.
+	if vk, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/new", VaultId: "id"}); err != nil || string(vk.GetKey()) != "k" {
+		t.Fatalf("want the session of the moved vault, got %v", err)
+	}
+
+	if _, err := s.UpdateSession(ctx, &pb.UpdateRequest{VaultPath: "/new", VaultId: "id", Nonce: []byte("n")}); err != nil {
+		t.Fatalf("update session: %v", err)
+	}
+
+	// sessions of vaults with an id are not found by the path.
+	if _, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/old"}); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound by path, got %v", err)
+	}
+
+	if _, err := s.Logout(ctx, &pb.SessionRequest{VaultPath: "/new", VaultId: "id"}); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+
+	if _, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/new", VaultId: "id"}); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound after logout, got %v", err)
+	}
+}
+
+func TestMoveSession(t *testing.T) {
+	ctx := context.Background()
+	s := newSessionServer(slog.New(slog.NewTextHandler(io.Discard, nil)), newMetrics())
+	defer s.stopAll()
+
+	key := &pb.VaultKey{Key: []byte("k")}
+
+	if _, err := s.Login(ctx, &pb.LoginRequest{VaultPath: "/old", DurationSeconds: 1, VaultKey: key}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	if _, err := s.MoveSession(ctx, &pb.MoveRequest{VaultPath: "/missing", NewVaultPath: "/new"}); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound moving a missing session, got %v", err)
+	}
+
+	if _, err := s.MoveSession(ctx, &pb.MoveRequest{VaultPath: "/old", NewVaultPath: "/new"}); err != nil {
+		t.Fatalf("move session: %v", err)
+	}
+
+	if _, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/old"}); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound by the old path, got %v", err)
+	}
+
+	if vk, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/new"}); err != nil || string(vk.GetKey()) != "k" {
+		t.Fatalf("want the moved session, got %v", err)
+	}
+
+	// the moved session still ends once its duration passes.
+	deadline := time.Now().Add(5 * time.Second)
+	for s.sessions.len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if n := s.sessions.len(); n != 0 {
+		t.Errorf("want the moved session ended, got %d sessions", n)
+	}
+
+	if string(key.GetKey()) != "\x00" {
+		t.Errorf("session key not wiped: %q", key.GetKey())
+	}
+}
+
+func TestSession_ConcurrentStamp(t *testing.T) {
+	ctx := context.Background()
+	s := newSessionServer(slog.New(slog.NewTextHandler(io.Discard, nil)), newMetrics())
+	defer s.stopAll()
+
+	stamp := &pb.VaultStamp{ModTimeUnixNano: 1, Size: 2}
+
+	if _, err := s.Login(ctx, &pb.LoginRequest{VaultPath: "/vault", DurationSeconds: 60, VaultKey: &pb.VaultKey{Key: []byte("k")}, Stamp: stamp}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	// stamps are updated while keys are requested, run with -race.
+	var wg sync.WaitGroup
+
+	for range 4 {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = s.UpdateSession(ctx, &pb.UpdateRequest{VaultPath: "/vault", Stamp: stamp})
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = s.MoveSession(ctx, &pb.MoveRequest{VaultPath: "/vault", NewVaultPath: "/vault", Stamp: stamp})
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := s.GetSessionKey(ctx, &pb.SessionRequest{VaultPath: "/vault", Stamp: stamp}); err != nil {
+				t.Errorf("get session key: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestGetSessionKey_VaultChanged(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("peer credentials are only supported on linux")
+	}
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "vault.db")
+
+	if err := os.WriteFile(path, []byte("vault"), 0o600); err != nil {
+		t.Fatalf("write vault: %v", err)
+	}
+
+	startDaemon(t)
+
+	c, err := NewSessionClient()
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if err := c.Login(ctx, path, []byte("key"), []byte("nonce"), time.Minute); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	// a write of vlt itself updates the session.
+	if err := os.WriteFile(path, []byte("vault v2"), 0o600); err != nil {
+		t.Fatalf("write vault: %v", err)
+	}
+
+	if err := c.UpdateSession(ctx, path, []byte("nonce2")); err != nil {
+		t.Fatalf("update session: %v", err)
+	}
+
+	if _, nonce, err := c.GetSessionKey(ctx, path); err != nil || string(nonce) != "nonce2" {
+		t.Fatalf("want the updated session, got %q: %v", nonce, err)
+	}
+
+	// a write without resealing, e.g., a vacuum, only updates the stamp.
+	if err := os.WriteFile(path, []byte("vacuumed"), 0o600); err != nil {
+		t.Fatalf("write vault: %v", err)
+	}
+
+	if err := c.UpdateSession(ctx, path, nil); err != nil {
+		t.Fatalf("update session: %v", err)
+	}
+
+	if _, nonce, err := c.GetSessionKey(ctx, path); err != nil || string(nonce) != "nonce2" {
+		t.Fatalf("want the session nonce kept, got %q: %v", nonce, err)
+	}
+
+	// the vault file is replaced outside vlt, e.g., by a file sync.
+	if err := os.WriteFile(path, []byte("synced vault"), 0o600); err != nil {
+		t.Fatalf("write vault: %v", err)
+	}
+
+	if _, _, err := c.GetSessionKey(ctx, path); status.Code(err) != codes.NotFound {
+		t.Fatalf("want NotFound for a changed vault, got %v", err)
+	}
+
+	// the session was dropped, not only denied.
+	if err := os.WriteFile(path, []byte("vault v2"), 0o600); err != nil {
+		t.Fatalf("write vault: %v", err)
+	}
+
+	if _, _, err := c.GetSessionKey(ctx, path); status.Code(err) != codes.NotFound {
+		t.Errorf("want the session dropped, got %v", err)
+	}
+}
+
+func TestStale(t *testing.T) {
+	stamp := &pb.VaultStamp{ModTimeUnixNano: 1, Size: 2}
+
+	tests := []struct {
+		name             string
+		session, current *pb.VaultStamp
+		want             bool
+	}{
+		{name: "unchanged", session: stamp, current: &pb.VaultStamp{ModTimeUnixNano: 1, Size: 2}},
+		{name: "modified", session: stamp, current: &pb.VaultStamp{ModTimeUnixNano: 3, Size: 2}, want: true},
+		{name: "resized", session: stamp, current: &pb.VaultStamp{ModTimeUnixNano: 1, Size: 4}, want: true},
+		{name: "session without stamp", current: stamp},
+		{name: "request without stamp", session: stamp},
+	}
+
+	for _, tt := range tests {
+		if got := stale(tt.session, tt.current); got != tt.want {
+			t.Errorf("%s: want %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package vaultdaemon
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/securebytes"
+)
+
+// shredder shreds files once their delay passes, e.g., secrets written
+// to files by 'vlt show --output --ttl', for tools reading them from a path.
+type shredder struct {
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	pending map[string]*pendingShred
+}
+
+// pendingShred is a file scheduled to be shredded.
+type pendingShred struct {
+	timer *time.Timer
+	fi    os.FileInfo // fi identifies the file, see [shredFile].
+}
+
+func newShredder(logger *slog.Logger) *shredder {
+	return &shredder{
+		logger:  logger,
+		pending: make(map[string]*pendingShred),
+	}
+}
+
+// schedule shreds the regular file at the absolute path once delay passes,
+// replacing an earlier schedule of the same path.
+func (s *shredder) schedule(path string, delay time.Duration) error {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, ok := s.pending[path]; ok {
+		p.timer.Stop()
+	}
+
+	p := &pendingShred{fi: fi}
+	p.timer = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		if s.pending[path] == p {
+			delete(s.pending, path)
+		}
+		s.mu.Unlock()
+
+		s.shred(path, fi)
+	})
+
+	s.pending[path] = p
+
+	s.logger.Info("file shred scheduled", "path", path, "delay", delay)
+
+	return nil
+}
+
+// flush shreds all pending files immediately, e.g., on shutdown,
+// as their schedules do not survive the daemon.
+func (s *shredder) flush() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]*pendingShred)
+	s.mu.Unlock()
+
+	for path, p := range pending {
+		if p.timer.Stop() {
+			s.shred(path, p.fi)
+		}
+	}
+}
+
+func (s *shredder) shred(path string, fi os.FileInfo) {
+	if err := shredFile(path, fi); err != nil {
+		s.logger.Error("file shred failed", "path", path, "err", err)
+		return
+	}
+
+	s.logger.Info("file shredded", "path", path)
+}
+
+// shredFile overwrites the file at path with zeros and removes it, unless it was
+// replaced since fi was taken, e.g., by another file of the same name.
+// A file removed in the meantime is not an error.
+func shredFile(path string, fi os.FileInfo) error {
+	cur, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !os.SameFile(fi, cur) {
+		return fmt.Errorf("%s was replaced since scheduled, not shredded", path)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0) //nolint:gosec // the path is given by a client of the same user
+	if err != nil {
+		return err
+	}
+
+	// the path may be replaced in between, the opened file is verified too.
+	opened, err := f.Stat()
+	if err == nil && !os.SameFile(fi, opened) {
+		err = fmt.Errorf("%s was replaced since scheduled, not shredded", path)
+	}
+
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := securebytes.WipeFile(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
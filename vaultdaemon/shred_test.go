@@ -0,0 +1,123 @@
+package vaultdaemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestShredFile(t *testing.T) {
+	startDaemon(t)
+
+	c, err := NewSessionClient()
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ShredFile(context.Background(), path, time.Second); err != nil {
+		t.Fatalf("shred file: %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("want the file kept until the ttl passes, got %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("file not shredded")
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if err := c.ShredFile(context.Background(), filepath.Dir(path), time.Second); err == nil {
+		t.Error("want an error shredding a directory")
+	}
+}
+
+func TestDelaySeconds(t *testing.T) {
+	tests := []struct {
+		ttl  time.Duration
+		want int64
+	}{
+		{ttl: 500 * time.Millisecond, want: 1},
+		{ttl: time.Second, want: 1},
+		{ttl: 1500 * time.Millisecond, want: 2},
+		{ttl: time.Minute, want: 60},
+	}
+
+	for _, tt := range tests {
+		if got := delaySeconds(tt.ttl); got != tt.want {
+			t.Errorf("delaySeconds(%s) = %d, want %d", tt.ttl, got, tt.want)
+		}
+	}
+}
+
+func TestShredder(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("replaced file is kept", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token")
+		if err := os.WriteFile(path, []byte("secret"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		fi, err := os.Lstat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the original is kept aside, so that its inode is not reused.
+		if err := os.Rename(path, path+".old"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte("other"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := shredFile(path, fi); err == nil {
+			t.Error("want an error shredding a replaced file")
+		}
+
+		if got, err := os.ReadFile(path); err != nil || string(got) != "other" {
+			t.Errorf("want the replaced file kept, got %q: %v", got, err)
+		}
+	})
+
+	t.Run("flush shreds pending files", func(t *testing.T) {
+		s := newShredder(logger)
+
+		path := filepath.Join(t.TempDir(), "token")
+		if err := os.WriteFile(path, []byte("secret"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.schedule(path, time.Hour); err != nil {
+			t.Fatalf("schedule: %v", err)
+		}
+
+		s.flush()
+
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("want the file removed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+//go:build unix
+
+package vaultdaemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+)
+
+// verifySocketSecure verifies the daemon socket at path is owned by uid, and only
+// accessible to it.
+//
+// Abstract sockets have no owner or permissions, and their name is available to any
+// process first binding it, the daemon listening on them must run as uid instead.
+func verifySocketSecure(path string, uid int) (retErr error) {
+	if isAbstract(path) {
+		return verifyAbstractSocket(path, uid)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("%w: could not stat socket: %w", ErrSocketUnavailable, err)
+	}
+
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return errors.New("socket verify: unexpected file stat type")
+	}
+
+	if int(stat.Uid) != uid {
+		return fmt.Errorf("socket verify: unexpected socket owner uid: got %d, want %d", stat.Uid, uid)
+	}
+
+	if (fi.Mode() & os.ModeSymlink) != 0 {
+		return fmt.Errorf("socket verify: refusing to follow symlink: %s", path)
+	}
+
+	if (fi.Mode() & os.ModeSocket) == 0 {
+		return fmt.Errorf("socket verify: file is not a socket: %s", path)
+	}
+
+	if fi.Mode().Perm() != socketPerm {
+		return fmt.Errorf("socket verify: socket file has insecure permissions: %v", fi.Mode().Perm())
+	}
+
+	return nil
+}
+
+// verifyAbstractSocket verifies the daemon listening on the abstract socket at path runs as uid.
+func verifyAbstractSocket(path string, uid int) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("%w: %w", ErrSocketUnavailable, errAbstractUnsupported)
+	}
+
+	var d net.Dialer
+
+	conn, err := d.DialContext(context.Background(), "unix", path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSocketUnavailable, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	return verifyDaemonUID(conn, uid)
+}
+
+// dialAbstractSocket connects to the daemon listening on the abstract socket at path,
+// verifying it runs as uid. The check of [verifyAbstractSocket] is repeated for each
+// connection, as another process may have bound the name in between.
+func dialAbstractSocket(ctx context.Context, path string, uid int) (net.Conn, error) {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyDaemonUID(conn, uid); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// verifyDaemonUID verifies the process at the remote end of conn runs as uid.
+func verifyDaemonUID(conn net.Conn, uid int) error {
+	cred, err := peerCred(conn)
+	if err != nil {
+		return fmt.Errorf("socket verify: %w", err)
+	}
+
+	if cred.uid != uid {
+		return fmt.Errorf("socket verify: unexpected daemon uid: got %d, want %d", cred.uid, uid)
+	}
+
+	return nil
+}
+
+// verifySocketDir verifies the socket directory dir, and the directories above it,
+// are owned by uid or root, and are not writable by others, who could replace the
+// socket with their own. The <tmp>/vlt-<uid> fallback directory must have mode 0700,
+// as it is meant to be private, and may have been created by another program.
+//
+// World-writable directories above dir are allowed if sticky, e.g., /tmp,
+// as their entries can only be removed or renamed by their owners.
+func verifySocketDir(dir string, uid int) error {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return fmt.Errorf("socket directory: %w", err)
+	}
+
+	resolved, err = filepath.Abs(resolved)
+	if err != nil {
+		return fmt.Errorf("socket directory: %w", err)
+	}
+
+	for d := resolved; ; d = filepath.Dir(d) {
+		fi, err := os.Lstat(d)
+		if err != nil {
+			return fmt.Errorf("socket directory: %w", err)
+		}
+
+		stat, ok := fi.Sys().(*syscall.Stat_t)
+		if !ok {
+			return errors.New("socket directory: unexpected file stat type")
+		}
+
+		if int(stat.Uid) != uid && stat.Uid != 0 {
+			return fmt.Errorf("socket directory: refusing %s owned by uid %d, want %d or root", d, stat.Uid, uid)
+		}
+
+		worldWritable := fi.Mode().Perm()&0o002 != 0
+		if worldWritable && (d == resolved || fi.Mode()&os.ModeSticky == 0) {
+			return fmt.Errorf("socket directory: refusing world-writable %s", d)
+		}
+
+		if d == resolved && dir == fallbackRuntimeDir(uid) && fi.Mode().Perm()&0o077 != 0 {
+			return fmt.Errorf("socket directory: refusing %s with mode %v, want %v", d, fi.Mode().Perm(), os.FileMode(socketDirPerm))
+		}
+
+		if filepath.Dir(d) == d {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package vaultdaemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// verifySocketSecure always fails on Windows, where vltd and sessions are not supported;
+// vlt falls back to prompting for the password on each command.
+func verifySocketSecure(string, int) error {
+	return fmt.Errorf("%w: sessions are not supported on windows", ErrSocketUnavailable)
+}
+
+// dialAbstractSocket always fails on Windows, see [verifySocketSecure].
+func dialAbstractSocket(context.Context, string, int) (net.Conn, error) {
+	return nil, errAbstractUnsupported
+}
+
+// verifySocketDir is a no-op on Windows, where unix socket directories have no unix permissions.
+func verifySocketDir(string, int) error { return nil }
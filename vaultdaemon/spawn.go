@@ -0,0 +1,89 @@
+package vaultdaemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// daemonExecutable is the name of the daemon executable.
+	daemonExecutable = "vltd"
+
+	// spawnTimeout bounds the wait for the socket of a spawned daemon.
+	spawnTimeout = 3 * time.Second
+
+	// spawnPollInterval is the interval between socket checks while waiting for a spawned daemon.
+	spawnPollInterval = 25 * time.Millisecond
+)
+
+// lookupDaemon returns the path of the daemon executable.
+//
+// The executable next to the running one is preferred,
+// so that matching vlt and vltd versions are used together.
+var lookupDaemon = func() (string, error) {
+	if exe, err := os.Executable(); err == nil {
+		p := filepath.Join(filepath.Dir(exe), daemonExecutable)
+		if fi, err := os.Stat(p); err == nil && fi.Mode().IsRegular() {
+			return p, nil
+		}
+	}
+
+	return exec.LookPath(daemonExecutable)
+}
+
+// Spawn starts the daemon in the background and waits until its socket is available.
+//
+// The daemon runs in a new session, detached from the controlling terminal,
+// so it outlives the calling process and is not affected by its signals.
+func Spawn(ctx context.Context) error {
+	path, err := lookupDaemon()
+	if err != nil {
+		return fmt.Errorf("spawn daemon: %w", err)
+	}
+
+	// the socket is passed on, it may be set by the config of the caller, see [SetSocketPath].
+	cmd := exec.Command(path, "--socket", socketPath) //nolint:gosec,noctx // the daemon must outlive ctx.
+	cmd.SysProcAttr = detachedProcAttr()
+
+	// stdio defaults to the null device, the daemon has no terminal.
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("spawn daemon: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	ctx, cancel := context.WithTimeout(ctx, spawnTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(spawnPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if verifySocketSecure(socketPath, os.Getuid()) == nil {
+			return nil
+		}
+
+		select {
+		case err := <-exited:
+			return fmt.Errorf("spawn daemon: %s exited before its socket became available: %w", path, exitError(err))
+		case <-ctx.Done():
+			return fmt.Errorf("spawn daemon: waiting for socket %s: %w", socketPath, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// exitError returns the error of a daemon that exited prematurely.
+func exitError(err error) error {
+	if err == nil {
+		return errors.New("exit status 0")
+	}
+
+	return err
+}
@@ -0,0 +1,21 @@
+package vaultdaemon
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSpawn_DaemonExitsEarly(t *testing.T) {
+	origSocket, origLookup := socketPath, lookupDaemon
+	t.Cleanup(func() { socketPath, lookupDaemon = origSocket, origLookup })
+
+	socketPath = filepath.Join(t.TempDir(), "vlt.sock")
+	lookupDaemon = func() (string, error) { return "/bin/false", nil }
+
+	err := Spawn(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "exited before its socket became available") {
+		t.Errorf("want early exit error, got: %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build unix
+
+package vaultdaemon
+
+import "syscall"
+
+// detachedProcAttr starts the daemon in a new session.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package vaultdaemon
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// detachedProcAttr starts the daemon without a console, in a new process group.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP}
+}
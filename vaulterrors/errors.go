@@ -12,4 +12,9 @@ var (
 	ErrEmptySecret               = errors.New("secret cannot be empty")
 	ErrSearchNoMatch             = errors.New("no match found")
 	ErrAmbiguousSecretMatch      = errors.New("ambiguous secret match: multiple secrets match the search criteria")
+	ErrInsecureVaultPath         = errors.New("insecure vault path")
+	ErrVaultInconsistent         = errors.New("vault integrity check failed")
+	ErrUnlockThrottled           = errors.New("too many failed unlock attempts")
+	ErrUnsupportedVaultFormat    = errors.New("unsupported vault format")
+	ErrNameConflict              = errors.New("secret name already in use")
 )
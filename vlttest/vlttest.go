@@ -0,0 +1,251 @@
+// Package vlttest provides helpers for testing integrations and plugins
+// against the vlt CLI, running its commands on a temporary vault.
+//
+// Streams created by [NewIOStreams] hold no global state, passwords are read
+// from their scripted prompter, and are safe to use by parallel tests.
+// vlt commands configure process-wide state, e.g., the error handler and
+// the clipboard commands, so commands run by [Env.Run] are run one at a time,
+// and parallel tests may share the package.
+package vlttest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ladzaretti/vlt-cli/cli"
+	"github.com/ladzaretti/vlt-cli/clierror"
+	"github.com/ladzaretti/vlt-cli/genericclioptions"
+	"github.com/ladzaretti/vlt-cli/input"
+)
+
+// DefaultPassword is the master password of vaults created by [Env.Init].
+const DefaultPassword = "vlttest-master-password" //nolint:gosec // test password
+
+// Streams are the IOStreams of a command run in tests, with their buffers.
+type Streams struct {
+	*genericclioptions.IOStreams
+
+	Out      *bytes.Buffer                   // Out is the standard output of the command.
+	ErrOut   *bytes.Buffer                   // ErrOut is the error output of the command, including its errors.
+	Prompter *input.TestPrompter             // Prompter answers the prompts of the command, e.g., for passwords.
+	In       *genericclioptions.TestFdReader // In is the standard input of the command.
+}
+
+// NewIOStreams returns streams reading stdin, as a terminal if tty is set,
+// or as piped input otherwise. Prompts are answered by the returned
+// [Streams.Prompter], the scripted secrets and lines of which are set by the caller.
+func NewIOStreams(stdin string, tty bool) *Streams {
+	in := NewFdReader([]byte(stdin), tty)
+
+	ioStreams, _, out, errOut := genericclioptions.NewTestIOStreams(in)
+
+	prompter := &input.TestPrompter{}
+	ioStreams.SetPrompter(prompter)
+
+	return &Streams{
+		IOStreams: ioStreams,
+		Out:       out,
+		ErrOut:    errOut,
+		Prompter:  prompter,
+		In:        in,
+	}
+}
+
+// NewFdReader returns a mocked stdin reading data, reported as a terminal
+// if tty is set, or as piped input otherwise.
+func NewFdReader(data []byte, tty bool) *genericclioptions.TestFdReader {
+	var mode os.FileMode
+	if tty {
+		mode = os.ModeCharDevice
+	}
+
+	fi := genericclioptions.NewMockFileInfo("stdin", int64(len(data)), mode, false, time.Now())
+
+	return genericclioptions.NewTestFdReader(bytes.NewBuffer(data), 0, fi)
+}
+
+// Env is a temporary vlt environment: a config file, the path of its vault,
+// and a clipboard backed by files, removed once the test ends.
+type Env struct {
+	Dir        string // Dir is the temporary directory holding the environment files.
+	ConfigPath string // ConfigPath is the path of the config file, passed to the commands run by [Env.Run].
+	VaultPath  string // VaultPath is the path of the vault, created by [Env.Init].
+	Password   string // Password is the master password of the vault, [DefaultPassword] unless set by [WithPassword].
+
+	copyPath        string
+	pastePath       string
+	sessionDuration time.Duration
+	vaultConfig     string
+	config          string
+}
+
+// EnvOption configures an [Env].
+type EnvOption func(*Env)
+
+// WithPassword sets the master password of the vault created by [Env.Init].
+func WithPassword(password string) EnvOption {
+	return func(e *Env) {
+		e.Password = password
+	}
+}
+
+// WithSessionDuration enables sessions lasting d, e.g., for tests running a vltd of their own.
+func WithSessionDuration(d time.Duration) EnvOption {
+	return func(e *Env) {
+		e.sessionDuration = d
+	}
+}
+
+// WithVaultConfig appends the given TOML to the [vault] section of the config file,
+// e.g., 'unique_names = true'. The 'path', 'session_duration' and 'allow_core_dumps'
+// settings are set by the environment, and must not be set again.
+func WithVaultConfig(toml string) EnvOption {
+	return func(e *Env) {
+		e.vaultConfig += "\n" + toml
+	}
+}
+
+// WithConfig appends the given TOML to the config file, e.g., a '[defaults.show]' table.
+// The [vault] section, see [WithVaultConfig], and the [clipboard] section are set
+// by the environment, and must not be set again.
+func WithConfig(toml string) EnvOption {
+	return func(e *Env) {
+		e.config += "\n" + toml
+	}
+}
+
+// NewEnv creates an environment in a temporary directory of t.
+//
+// Sessions are disabled unless set by [WithSessionDuration], so that commands
+// do not connect to a running vltd. Copying writes the file read by [Env.Clipboard],
+// and pasting reads the file written by [Env.SetClipboard], using tee and cat, expected on PATH.
+//
+// Core dumps are left enabled, as disabling them would apply to the test process.
+func NewEnv(t testing.TB, opts ...EnvOption) *Env {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	e := &Env{
+		Dir:        dir,
+		ConfigPath: filepath.Join(dir, "vlt.toml"),
+		VaultPath:  filepath.Join(dir, "vault.vlt"),
+		Password:   DefaultPassword,
+		copyPath:   filepath.Join(dir, "clipboard"),
+		pastePath:  filepath.Join(dir, "clipboard.paste"),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	config := fmt.Sprintf(`[vault]
+path = %q
+session_duration = %q
+allow_core_dumps = true
+%s
+
+[clipboard]
+copy_cmd = ['tee', %q]
+paste_cmd = ['cat', %q]
+`, e.VaultPath, e.sessionDuration, e.vaultConfig, e.copyPath, e.pastePath) + e.config
+
+	for _, path := range []string{e.copyPath, e.pastePath} {
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatalf("vlttest: create clipboard file: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(e.ConfigPath, []byte(config), 0o600); err != nil {
+		t.Fatalf("vlttest: write config file: %v", err)
+	}
+
+	return e
+}
+
+// Init creates the vault of the environment, protected by [Env.Password].
+func (e *Env) Init(t testing.TB) {
+	t.Helper()
+
+	s := NewIOStreams("", true)
+	s.Prompter.Secrets = [][]byte{[]byte(e.Password), []byte(e.Password)}
+
+	if err := e.Run(s, "create"); err != nil {
+		t.Fatalf("vlttest: create vault: %v\nstderr: %s", err, s.ErrOut)
+	}
+}
+
+// Streams returns streams reading stdin, see [NewIOStreams],
+// with the vault password scripted for the first password prompt.
+func (e *Env) Streams(stdin string, tty bool) *Streams {
+	s := NewIOStreams(stdin, tty)
+	s.Prompter.Secrets = [][]byte{[]byte(e.Password)}
+
+	return s
+}
+
+// runMu serializes the commands run by [Env.Run].
+var runMu sync.Mutex
+
+// Run runs the vlt command given by args using the config file of the environment
+// and the streams s, and returns its error, also written to s.ErrOut.
+//
+// The process sandbox is disabled, as it would otherwise restrict the test process.
+// While the command runs, $XDG_RUNTIME_DIR is set to [Env.Dir], so that the
+// clipboard ownership marker is kept out of the runtime directory of the user.
+func (e *Env) Run(s *Streams, args ...string) error {
+	runMu.Lock()
+	defer runMu.Unlock()
+
+	runtimeDir, ok := os.LookupEnv("XDG_RUNTIME_DIR")
+	_ = os.Setenv("XDG_RUNTIME_DIR", e.Dir)
+
+	defer func() {
+		if ok {
+			_ = os.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+			return
+		}
+
+		_ = os.Unsetenv("XDG_RUNTIME_DIR")
+	}()
+
+	clierror.SetErrorHandler(clierror.PrintErrHandler)
+	clierror.SetErrWriter(s.ErrOut)
+
+	defer func() {
+		clierror.ResetErrorHandler()
+		clierror.ResetErrWriter()
+	}()
+
+	args = slices.Concat([]string{"--config", e.ConfigPath, "--no-sandbox"}, args)
+
+	return cli.NewDefaultVltCommand(s.IOStreams, args).Execute()
+}
+
+// Clipboard returns the content copied to the clipboard of the environment.
+func (e *Env) Clipboard(t testing.TB) string {
+	t.Helper()
+
+	b, err := os.ReadFile(e.copyPath)
+	if err != nil {
+		t.Fatalf("vlttest: read clipboard: %v", err)
+	}
+
+	return string(b)
+}
+
+// SetClipboard sets the content pasted from the clipboard of the environment.
+// Copied content is not pasted, so that it is read by [Env.Clipboard] as copied.
+func (e *Env) SetClipboard(t testing.TB, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(e.pastePath, []byte(content), 0o600); err != nil {
+		t.Fatalf("vlttest: write clipboard: %v", err)
+	}
+}
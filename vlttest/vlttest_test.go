@@ -0,0 +1,64 @@
+package vlttest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/vlttest"
+)
+
+func TestEnv(t *testing.T) {
+	for _, name := range []string{"a", "b", "c"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			env := vlttest.NewEnv(t, vlttest.WithPassword("password-"+name))
+			env.Init(t)
+
+			s := env.Streams("secret-"+name, false)
+			if err := env.Run(s, "save", "--name", "foo", "--label", name); err != nil {
+				t.Fatalf("save: %v\nstderr: %s", err, s.ErrOut)
+			}
+
+			s = env.Streams("", false)
+			if err := env.Run(s, "show", "--name", "foo", "--copy-clipboard"); err != nil {
+				t.Fatalf("show: %v\nstderr: %s", err, s.ErrOut)
+			}
+
+			if got, want := env.Clipboard(t), "secret-"+name; got != want {
+				t.Errorf("want clipboard %q, got %q", want, got)
+			}
+
+			s = env.Streams("", false)
+			if err := env.Run(s, "show", "--name", "foo", "--stdout"); err != nil {
+				t.Fatalf("show: %v\nstderr: %s", err, s.ErrOut)
+			}
+
+			if got, want := s.Out.String(), "secret-"+name; !strings.HasSuffix(got, want) {
+				t.Errorf("want stdout ending with %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestEnv_Run(t *testing.T) {
+	t.Parallel()
+
+	env := vlttest.NewEnv(t)
+	env.Init(t)
+
+	s := vlttest.NewIOStreams("", false)
+	s.Prompter.Secrets = [][]byte{[]byte("wrong password")}
+
+	if err := env.Run(s, "find"); err == nil {
+		t.Fatal("want error, got nil")
+	}
+
+	if len(s.ErrOut.String()) == 0 {
+		t.Error("want the error written to stderr")
+	}
+
+	if len(s.Prompter.Prompts) != 1 {
+		t.Errorf("want a single password prompt, got %q", s.Prompter.Prompts)
+	}
+}
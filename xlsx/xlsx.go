@@ -0,0 +1,383 @@
+// Package xlsx reads the rows of the first sheet of Office Open XML
+// spreadsheets, i.e., .xlsx files, as records of strings.
+//
+// Cells are read as stored: shared and inline strings, the cached values of
+// formulas, booleans as TRUE or FALSE, and numbers as written, e.g., dates
+// as serial numbers. Formatting is ignored.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	workbookPath      = "xl/workbook.xml"
+	workbookRelsPath  = "xl/_rels/workbook.xml.rels"
+	sharedStringsPath = "xl/sharedStrings.xml"
+)
+
+// MaxPartSize is the maximal uncompressed size of a part of the spreadsheet,
+// e.g., of a sheet, guarding against zip bombs.
+const MaxPartSize = 64 << 20
+
+// ErrTooLarge is returned for parts larger than [MaxPartSize].
+var ErrTooLarge = errors.New("xlsx: part exceeds the maximal size")
+
+// zipMagic prefixes zip archives, xlsx files included.
+var zipMagic = []byte("PK\x03\x04")
+
+// Detect reports whether data starts as a zip archive, e.g., an xlsx file.
+func Detect(data []byte) bool {
+	return bytes.HasPrefix(data, zipMagic)
+}
+
+// Reader reads the rows of the first sheet, see [NewReader].
+type Reader struct {
+	d             *xml.Decoder
+	sharedStrings []string
+	width         int // width is the number of cells of the first row, shorter rows are padded to it.
+}
+
+// NewReader returns a reader of the first sheet of the xlsx file r of the given size.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+
+	sheetPath, err := firstSheetPath(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedStrings, err := readSharedStrings(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, err := readPart(zr, sheetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		d:             xml.NewDecoder(bytes.NewReader(sheet)),
+		sharedStrings: sharedStrings,
+	}, nil
+}
+
+// Read returns the cells of the next row, or [io.EOF] after the last one.
+//
+// Rows without values are skipped. Rows are padded with empty cells
+// to the width of the first row, as trailing empty cells are not stored.
+func (r *Reader) Read() ([]string, error) {
+	for {
+		row, err := r.nextRow()
+		if err != nil {
+			return nil, err
+		}
+
+		if !hasValue(row) {
+			continue
+		}
+
+		if r.width == 0 {
+			r.width = len(row)
+		}
+
+		for len(row) < r.width {
+			row = append(row, "")
+		}
+
+		return row, nil
+	}
+}
+
+type cell struct {
+	Ref    string    `xml:"r,attr"`
+	Type   string    `xml:"t,attr"`
+	Value  string    `xml:"v"`
+	Inline *richText `xml:"is"`
+}
+
+// richText is a string of text runs, e.g., of shared and inline strings.
+type richText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t *richText) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(t.Text)
+
+	for _, r := range t.Runs {
+		sb.WriteString(r.Text)
+	}
+
+	return sb.String()
+}
+
+// nextRow decodes the cells of the next row element.
+func (r *Reader) nextRow() ([]string, error) {
+	var row []string
+
+	inRow := false
+
+	for {
+		tok, err := r.d.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: sheet: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				inRow, row = true, []string{}
+
+			case "c":
+				if !inRow {
+					continue
+				}
+
+				var c cell
+				if err := r.d.DecodeElement(&c, &t); err != nil {
+					return nil, fmt.Errorf("xlsx: sheet: %w", err)
+				}
+
+				i := len(row)
+
+				if len(c.Ref) > 0 {
+					i, err = columnIndex(c.Ref)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				if i < len(row) {
+					return nil, fmt.Errorf("xlsx: sheet: cell %s out of order", c.Ref)
+				}
+
+				for len(row) < i {
+					row = append(row, "")
+				}
+
+				v, err := r.cellValue(c)
+				if err != nil {
+					return nil, err
+				}
+
+				row = append(row, v)
+			}
+
+		case xml.EndElement:
+			if t.Name.Local == "row" && inRow {
+				return row, nil
+			}
+		}
+	}
+}
+
+func (r *Reader) cellValue(c cell) (string, error) {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(c.Value))
+		if err != nil || i < 0 || i >= len(r.sharedStrings) {
+			return "", fmt.Errorf("xlsx: cell %s: invalid shared string %q", c.Ref, c.Value)
+		}
+
+		return r.sharedStrings[i], nil
+
+	case "inlineStr":
+		if c.Inline == nil {
+			return "", nil
+		}
+
+		return c.Inline.String(), nil
+
+	case "b":
+		if strings.TrimSpace(c.Value) == "1" {
+			return "TRUE", nil
+		}
+
+		return "FALSE", nil
+
+	default:
+		// numbers, the cached string values of formulas, errors and iso dates.
+		return c.Value, nil
+	}
+}
+
+// columnIndex returns the zero-based column index of a cell reference, e.g., 1 for B3.
+func columnIndex(ref string) (int, error) {
+	i, n := 0, 0
+
+	for ; n < len(ref) && ref[n] >= 'A' && ref[n] <= 'Z'; n++ {
+		i = i*26 + int(ref[n]-'A'+1)
+
+		// the last column of the format is XFD.
+		if i > 16384 {
+			break
+		}
+	}
+
+	if n == 0 || i > 16384 {
+		return 0, fmt.Errorf("xlsx: invalid cell reference %q", ref)
+	}
+
+	return i - 1, nil
+}
+
+func hasValue(row []string) bool {
+	for _, c := range row {
+		if len(c) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// firstSheetPath returns the path of the first sheet of the workbook,
+// resolved using the workbook relationships.
+func firstSheetPath(zr *zip.Reader) (string, error) {
+	data, err := readPart(zr, workbookPath)
+	if err != nil {
+		return "", err
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Attrs []xml.Attr `xml:",any,attr"`
+		} `xml:"sheets>sheet"`
+	}
+
+	if err := xml.Unmarshal(data, &workbook); err != nil {
+		return "", fmt.Errorf("xlsx: workbook: %w", err)
+	}
+
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("xlsx: workbook has no sheets")
+	}
+
+	// r:id, in the relationships namespace of either transitional or strict files.
+	var id string
+
+	for _, a := range workbook.Sheets[0].Attrs {
+		if a.Name.Local == "id" && len(a.Name.Space) > 0 {
+			id = a.Value
+		}
+	}
+
+	data, err = readPart(zr, workbookRelsPath)
+	if err != nil {
+		return "", err
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return "", fmt.Errorf("xlsx: workbook relationships: %w", err)
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != id {
+			continue
+		}
+
+		if target, ok := strings.CutPrefix(rel.Target, "/"); ok {
+			return target, nil
+		}
+
+		return path.Join(path.Dir(workbookPath), rel.Target), nil
+	}
+
+	return "", fmt.Errorf("xlsx: sheet relationship %q not found", id)
+}
+
+// readSharedStrings reads the shared strings table, if any.
+func readSharedStrings(zr *zip.Reader) ([]string, error) {
+	data, err := readPart(zr, sharedStringsPath)
+	if errors.Is(err, errPartNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+
+	var sharedStrings []string
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return sharedStrings, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: shared strings: %w", err)
+		}
+
+		if t, ok := tok.(xml.StartElement); ok && t.Name.Local == "si" {
+			// phonetic runs, rPh, are skipped as they are not decoded.
+			var s richText
+			if err := d.DecodeElement(&s, &t); err != nil {
+				return nil, fmt.Errorf("xlsx: shared strings: %w", err)
+			}
+
+			sharedStrings = append(sharedStrings, s.String())
+		}
+	}
+}
+
+var errPartNotFound = errors.New("xlsx: part not found")
+
+// readPart reads the uncompressed part at name, at most [MaxPartSize] bytes.
+func readPart(zr *zip.Reader, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: %s: %w", name, err)
+		}
+		defer func() { _ = rc.Close() }() //nolint:wsl_v5
+
+		data, err := io.ReadAll(io.LimitReader(rc, MaxPartSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: %s: %w", name, err)
+		}
+
+		if len(data) > MaxPartSize {
+			return nil, fmt.Errorf("%w: %s", ErrTooLarge, name)
+		}
+
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errPartNotFound, name)
+}
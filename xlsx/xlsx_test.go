@@ -0,0 +1,195 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ladzaretti/vlt-cli/xlsx"
+
+	gocmp "github.com/google/go-cmp/cmp"
+)
+
+const (
+	workbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Passwords" sheetId="2" r:id="rId3"/><sheet name="Other" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	workbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`
+
+	sharedStrings = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="4" uniqueCount="4">
+<si><t>name</t></si>
+<si><t>secret</t></si>
+<si><r><t>ali</t></r><r><rPr><b/></rPr><t>ce</t></r><rPh><t>x</t></rPh></si>
+<si><t xml:space="preserve"> s3cr&amp;t </t></si>
+</sst>`
+
+	sheet = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>label</t></is></c><c r="D1" t="inlineStr"><is><t>note</t></is></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2" t="s"><v>3</v></c><c r="D2"><v>42</v></c></row>
+<row r="3"><c r="A3" s="1"/></row>
+<row r="5"><c r="A5" t="str"><f>UPPER("bob")</f><v>BOB</v></c><c r="B5" t="b"><v>1</v></c></row>
+</sheetData>
+</worksheet>`
+)
+
+func newXLSX(t *testing.T, parts map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := io.WriteString(w, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func readAll(r *xlsx.Reader) ([][]string, error) {
+	var rows [][]string
+
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+
+		if err != nil {
+			return rows, err
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+func TestReader(t *testing.T) {
+	f := newXLSX(t, map[string]string{
+		"xl/workbook.xml":            workbook,
+		"xl/_rels/workbook.xml.rels": workbookRels,
+		"xl/sharedStrings.xml":       sharedStrings,
+		"xl/worksheets/sheet1.xml":   `<worksheet><sheetData><row><c t="inlineStr"><is><t>other</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml":   sheet,
+	})
+
+	r, err := xlsx.NewReader(f, f.Size())
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+
+	got, err := readAll(r)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	want := [][]string{
+		{"name", "secret", "label", "note"},
+		{"alice", " s3cr&t ", "", "42"},
+		{"BOB", "TRUE", "", ""},
+	}
+
+	if diff := gocmp.Diff(want, got); diff != "" {
+		t.Errorf("rows mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReader_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts map[string]string
+	}{
+		{
+			name:  "no workbook",
+			parts: map[string]string{"xl/worksheets/sheet1.xml": sheet},
+		},
+		{
+			name: "missing sheet",
+			parts: map[string]string{
+				"xl/workbook.xml":            workbook,
+				"xl/_rels/workbook.xml.rels": workbookRels,
+			},
+		},
+		{
+			name: "shared string out of range",
+			parts: map[string]string{
+				"xl/workbook.xml":            workbook,
+				"xl/_rels/workbook.xml.rels": workbookRels,
+				"xl/worksheets/sheet2.xml":   sheet,
+			},
+		},
+		{
+			name: "invalid cell reference",
+			parts: map[string]string{
+				"xl/workbook.xml":            workbook,
+				"xl/_rels/workbook.xml.rels": workbookRels,
+				"xl/worksheets/sheet2.xml":   `<worksheet><sheetData><row><c r="1A"><v>1</v></c></row></sheetData></worksheet>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newXLSX(t, tt.parts)
+
+			r, err := xlsx.NewReader(f, f.Size())
+			if err != nil {
+				return
+			}
+
+			if _, err := readAll(r); err == nil {
+				t.Error("want error, got nil")
+			}
+		})
+	}
+}
+
+func TestReader_TooLarge(t *testing.T) {
+	f := newXLSX(t, map[string]string{
+		"xl/workbook.xml":            workbook,
+		"xl/_rels/workbook.xml.rels": workbookRels,
+		"xl/worksheets/sheet2.xml":   string(bytes.Repeat([]byte(" "), xlsx.MaxPartSize+1)),
+	})
+
+	if _, err := xlsx.NewReader(f, f.Size()); !errors.Is(err, xlsx.ErrTooLarge) {
+		t.Errorf("want %v, got %v", xlsx.ErrTooLarge, err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	f := newXLSX(t, map[string]string{"xl/workbook.xml": workbook})
+
+	head := make([]byte, 4)
+	if _, err := f.Read(head); err != nil {
+		t.Fatal(err)
+	}
+
+	if !xlsx.Detect(head) {
+		t.Error("xlsx file not detected")
+	}
+
+	if xlsx.Detect([]byte("name,secret\n")) {
+		t.Error("csv file detected as xlsx")
+	}
+}